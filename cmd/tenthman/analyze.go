@@ -1,18 +1,164 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
 
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/adr"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/redact"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
 	"github.com/spf13/cobra"
 )
 
 func newAnalyzeCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "analyze",
 		Short: "Feed a document/decision for Tenth Man counter-analysis",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Analyze mode coming soon.")
-			return nil
+		RunE:  runAnalyze,
+	}
+	cmd.Flags().String("adr", "", "Architecture Decision Record (markdown) to stress-test")
+	cmd.Flags().String("name", "", "Override output folder name (default: auto-slug from ADR title)")
+	cmd.Flags().Bool("redact", false, "Mask emails, API keys, tokens and internal hostnames in the ADR before any model sees it and in every saved artifact")
+	cmd.Flags().StringArray("redact-pattern", nil, "Extra regular expression to mask, optionally named as name=regex; implies --redact (repeatable)")
+	cmd.Flags().Bool("gate", false, "Exit non-zero when the Tenth Man raises risks at or above --severity-threshold (for CI)")
+	cmd.Flags().String("severity-threshold", "high", "Lowest risk severity that fails --gate: low, medium, high or critical")
+	cmd.MarkFlagFilename("adr", "md", "markdown")
+	cmd.RegisterFlagCompletionFunc("severity-threshold", completeValues("low", "medium", "high", "critical"))
+	return cmd
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	adrPath, _ := cmd.Flags().GetString("adr")
+	name, _ := cmd.Flags().GetString("name")
+	gate, _ := cmd.Flags().GetBool("gate")
+	thresholdFlag, _ := cmd.Flags().GetString("severity-threshold")
+	outputDir, _ := cmd.Root().PersistentFlags().GetString("output-dir")
+
+	if adrPath == "" {
+		return fmt.Errorf("analyze: --adr is required")
+	}
+	threshold, err := adr.ParseSeverity(thresholdFlag)
+	if err != nil {
+		return err
+	}
+	record, err := adr.Load(adrPath)
+	if err != nil {
+		return err
+	}
+	redactSecrets, _ := cmd.Flags().GetBool("redact")
+	redactPatterns, _ := cmd.Flags().GetStringArray("redact-pattern")
+	if redactSecrets || len(redactPatterns) > 0 {
+		// The ADR is masked as a whole, so the revised draft written from it
+		// is clean as well.
+		redactor, err := redact.New(redactPatterns)
+		if err != nil {
+			return err
+		}
+		if record, err = adr.Parse(redactor.Redact(record.Raw)); err != nil {
+			return err
+		}
+	}
+
+	title := record.Title
+	if title == "" {
+		title = filepath.Base(adrPath)
+	}
+	if name == "" {
+		name = output.GenerateSlug(title)
+	}
+	job := jobFromFlags(cmd)
+	job.Topic = record.Topic()
+	job.Name = name
+	job.Redact = redactSecrets
+	job.RedactPatterns = redactPatterns
+	if err := job.Validate(); err != nil {
+		return err
+	}
+
+	apiKey, err := resolveAPIKey(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := newClient(cmd, apiKey)
+	if err != nil {
+		return err
+	}
+	client.SetMaxTokens(500)
+	registry := loadRegistry(ctx, client)
+
+	outcome, err := runner.Run(ctx, client, registry, outputDir, job, runner.Hooks{
+		OnStart: func(dir string) {
+			fmt.Printf("%s %s\n", output.Bold("ADR:"), output.Colorize(output.AnsiMagenta, title))
+			fmt.Printf("Agents: %d | Rounds: %d-%d | Output: %s\n\n", job.Agents, job.MinRounds, job.MaxRounds, dir)
 		},
+		OnTurn:  output.PrintTurn,
+		OnPhase: output.PrintPhase,
+	})
+	if err != nil {
+		return fmt.Errorf("analyze: %w", err)
+	}
+
+	// Risks are scored by the model that argued the Tenth Man's case. A
+	// scoring failure only matters when the result gates the build.
+	risks, err := adr.NewRiskScorer(client, tenthManModel(outcome.Result)).Score(ctx, record, outcome.Result)
+	if err != nil {
+		if gate {
+			return fmt.Errorf("analyze: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if err := adr.WriteRisks(outcome.Dir, risks); err != nil {
+		return err
+	}
+	if err := adr.WriteRevision(outcome.Dir, record, outcome.Result, risks); err != nil {
+		return err
+	}
+
+	output.PrintConsensus(outcome.Consensus)
+	output.PrintOutcome(outcome.Result.Verdict())
+	output.PrintPositionChange(outcome.Result.Transcript.PositionChange)
+	output.PrintMinorityReports(outcome.Result.MinorityReports)
+	printRisks(risks)
+	fmt.Printf("\nAnalysis complete. Revised ADR draft saved to: %s\n", filepath.Join(outcome.Dir, "adr-revised.md"))
+
+	if gate {
+		if blocking := adr.RisksAtOrAbove(risks, threshold); len(blocking) > 0 {
+			return fmt.Errorf("analyze: gate failed: %d risk(s) at or above %s severity", len(blocking), threshold)
+		}
+		fmt.Printf("Gate passed: no risks at or above %s severity.\n", threshold)
+	}
+	return nil
+}
+
+// tenthManModel returns the model that played the Tenth Man, or "" if the
+// Tenth Man was not activated.
+func tenthManModel(result *debate.Result) string {
+	for _, turn := range result.Transcript.Turns {
+		if turn.Agent.Role == "tenth-man" {
+			return turn.Agent.Model
+		}
+	}
+	return ""
+}
+
+func printRisks(risks []adr.Risk) {
+	if len(risks) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", output.Bold("Risks:"))
+	for _, r := range risks {
+		label := fmt.Sprintf("[%s/%s]", r.Severity, r.Likelihood)
+		if r.Severity >= adr.SeverityHigh {
+			label = output.Bold(label)
+		}
+		fmt.Printf("  %s %s (#%d)\n", output.Colorize(output.AnsiMagenta, label), r.Title, r.Objection)
 	}
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/qa"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+func newAskCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ask <run-dir> <question>",
+		Short: "Ask a question about a completed debate without re-running it",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runAsk,
+	}
+	cmd.Flags().String("model", "", "Model to answer with (default: the first debater's model)")
+	cmd.Flags().Int("context-tokens", qa.DefaultContextTokens, "Transcript tokens sent with the question; longer transcripts are cut to the most relevant turns")
+	cmd.RegisterFlagCompletionFunc("model", completeModels)
+	return cmd
+}
+
+func runAsk(cmd *cobra.Command, args []string) error {
+	model, _ := cmd.Flags().GetString("model")
+	contextTokens, _ := cmd.Flags().GetInt("context-tokens")
+
+	transcript, err := runs.LoadTranscript(args[0])
+	if err != nil {
+		return err
+	}
+	if len(transcript.Turns) == 0 {
+		return fmt.Errorf("ask: %s has no turns", args[0])
+	}
+	if model == "" {
+		model = transcript.Turns[0].Agent.Model
+	}
+
+	apiKey, err := resolveAPIKey(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := newClient(cmd, apiKey)
+	if err != nil {
+		return err
+	}
+	answerer := qa.NewAnswerer(client, model)
+	answerer.SetContextTokens(contextTokens)
+	answer, err := answerer.Answer(ctx, transcript, args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Println(answer)
+	return nil
+}
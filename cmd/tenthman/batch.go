@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/batch"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+func newBatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Run many independent debates concurrently from a jobs file",
+		RunE:  runBatch,
+	}
+	cmd.Flags().String("file", "", "YAML jobs file (required)")
+	cmd.Flags().Int("concurrency", 3, "Maximum debates running at once")
+	cmd.Flags().Int("rpm", 20, "Requests per minute shared across all debates (0 disables limiting)")
+	cmd.MarkFlagFilename("file", "yaml", "yml")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	rpm, _ := cmd.Flags().GetInt("rpm")
+	outputDir, _ := cmd.Root().PersistentFlags().GetString("output-dir")
+
+	jobs, err := batch.LoadJobs(file)
+	if err != nil {
+		return err
+	}
+	defaults := jobFromFlags(cmd)
+	for i := range jobs {
+		jobs[i] = jobs[i].WithDefaults(defaults)
+		if err := jobs[i].Validate(); err != nil {
+			return fmt.Errorf("job %d: %w", i+1, err)
+		}
+	}
+
+	apiKey, err := resolveAPIKey(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// One client for every debate so the rate limit is shared.
+	client, err := newClient(cmd, apiKey)
+	if err != nil {
+		return err
+	}
+	client.SetMaxTokens(500)
+	client.SetAdaptivePacing(maxPace)
+	client.SetRateLimit(rpm)
+	registry := loadRegistry(ctx, client)
+
+	fmt.Printf("%s %d debates, concurrency %d\n\n", output.Bold("Batch:"), len(jobs), concurrency)
+
+	progress := &batchProgress{total: len(jobs)}
+	results := batch.Run(ctx, jobs, concurrency, func(ctx context.Context, i int, job runner.Job) (*runner.Outcome, error) {
+		progress.printf(i, "started: %s", job.Topic)
+		outcome, err := runner.Run(ctx, client, registry, outputDir, job, runner.Hooks{
+			OnTurn: func(turn debate.Turn) {
+				progress.round(i, turn)
+			},
+			OnPhase: func(phase debate.Phase) {
+				if phase == debate.TenthManPhase {
+					progress.printf(i, "%s", output.Colorize(output.AnsiMagenta, "Tenth Man activated"))
+				}
+			},
+		})
+		progress.finish(i, err)
+		return outcome, err
+	})
+
+	summaryDir, err := output.CreateOutputDir(outputDir, "batch", defaults.Layout, defaults.Project)
+	if err != nil {
+		return fmt.Errorf("batch: %w", err)
+	}
+	if err := batch.WriteSummary(summaryDir, results); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	fmt.Printf("\nBatch complete: %d succeeded, %d failed. Summary saved to: %s\n", len(results)-failed, failed, summaryDir)
+	if failed > 0 {
+		return fmt.Errorf("batch: %d of %d debates failed", failed, len(results))
+	}
+	return nil
+}
+
+// batchProgress prints a combined progress feed for concurrently running debates.
+type batchProgress struct {
+	mu        sync.Mutex
+	total     int
+	done      int
+	lastRound map[int]int
+}
+
+func (p *batchProgress) printf(i int, format string, a ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.printLocked(i, format, a...)
+}
+
+func (p *batchProgress) printLocked(i int, format string, a ...any) {
+	fmt.Printf("%s %s\n", output.Bold(fmt.Sprintf("[job %d/%d]", i+1, p.total)), fmt.Sprintf(format, a...))
+}
+
+// round prints one line the first time a debate reaches a new round.
+func (p *batchProgress) round(i int, turn debate.Turn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.lastRound == nil {
+		p.lastRound = make(map[int]int)
+	}
+	if p.lastRound[i] == turn.Round {
+		return
+	}
+	p.lastRound[i] = turn.Round
+	p.printLocked(i, "round %d", turn.Round)
+}
+
+func (p *batchProgress) finish(i int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if err != nil {
+		p.printLocked(i, "failed: %v (%d/%d finished)", err, p.done, p.total)
+		return
+	}
+	p.printLocked(i, "complete (%d/%d finished)", p.done, p.total)
+}
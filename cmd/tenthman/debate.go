@@ -1,141 +1,550 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
-	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/consensus"
-	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/tenthman"
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/templates"
 	"github.com/spf13/cobra"
 )
 
+// maxPace caps the delay adaptive pacing puts between requests for a model
+// that keeps hitting rate limits.
+const maxPace = 30 * time.Second
+
 func newDebateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "debate",
 		Short: "Run a multi-agent debate on a topic",
 		RunE:  runDebate,
 	}
-	cmd.Flags().String("topic", "", "Debate topic (required)")
+	cmd.Flags().String("topic", "", "Debate topic (required unless --continue)")
 	cmd.Flags().String("name", "", "Override output folder name (default: auto-slug from topic)")
-	cmd.MarkFlagRequired("topic")
+	cmd.Flags().String("template", "", "Scenario template name or .yaml path (see `tenthman templates`)")
+	cmd.Flags().StringSlice("template-dir", nil, "Extra directories to search for templates (default: user config dir)")
+	cmd.Flags().String("preset", "", "Debate size: "+strings.Join(templates.Presets, ", ")+"; sets agents, rounds and turn length, which explicit flags and --template still override")
+	cmd.Flags().String("compress", "", "Compress transcript.json and debate.log when the run finishes (gzip or zstd)")
+	cmd.Flags().String("report-template", "", "Go template file to render report.md with instead of the built-in layout")
+	cmd.Flags().String("log-format", "", "debate.log format: text, or json for one JSON event per line (default text)")
+	cmd.Flags().StringSlice("sink", []string{"terminal"}, "Where engine events go besides debate.log: terminal, stdout, file:<path>, webhook:<url> or store:<dir> (repeatable)")
+	cmd.Flags().StringArray("plugin", nil, "Command to run with every completed turn and the finished debate as JSON on stdin; its output goes to debate.log (repeatable)")
+	cmd.Flags().Int("stagnation-rounds", 0, "End the free debate early after this many consecutive rounds with little new content (0 disables)")
+	cmd.Flags().Bool("adaptive-rounds", false, "Size the free debate by how fast the agreement score moves: keep going while it rises, stop when it falls, and stop at --target-rounds when it holds steady")
+	cmd.Flags().Int("target-rounds", 0, "Rounds the free debate lasts with --adaptive-rounds while the agreement score holds steady (0 is halfway between --min-rounds and --max-rounds)")
+	cmd.Flags().Int("velocity-window", 0, "Consensus evaluations the agreement score's velocity is measured over with --adaptive-rounds (0 is 2)")
+	cmd.Flags().Float64("min-velocity", 0, "Agreement points per round that count as rising or falling with --adaptive-rounds (0 is 0.5)")
+	cmd.Flags().Int("token-budget", 0, "Stop the debate with an error once it has used this many LLM tokens (0 is unlimited)")
+	cmd.Flags().Int("retry-budget", 0, "End the debate early with partial results after this many retried LLM calls in total (0 is unlimited)")
+	cmd.Flags().Int("max-tokens", 0, "Cap each turn's completion at this many tokens (default: the client's cap of 500)")
+	cmd.Flags().Int("max-words", 0, "Ask agents whose turn runs over this many words to restate it concisely, truncating if they still overrun (0 is unlimited)")
+	cmd.Flags().Int("samples", 0, "Draw this many candidate replies per turn and keep the strongest, at the cost of more calls (0 or 1 draws one)")
+	cmd.Flags().String("sample-pick", "", "How the strongest sample is chosen: llm, a ranking call to the agent's model, or heuristic (default llm)")
+	cmd.Flags().Int("cross-examination", 0, "Run this round of the free debate as a cross-examination, each debater questioning the next (0 disables it)")
+	cmd.Flags().Bool("redact", false, "Mask emails, API keys, tokens and internal hostnames in the topic and every saved artifact")
+	cmd.Flags().StringArray("redact-pattern", nil, "Extra regular expression to mask, optionally named as name=regex; implies --redact (repeatable)")
+	cmd.Flags().Bool("disagreement", false, "Score how strongly each pair of agents disagrees in every round (one judge call per round) and draw heatmaps in disagreement.html")
+	cmd.Flags().Bool("fact-check", false, "Verify the factual claims of the final consensus position, flagging unverifiable ones in the report")
+	cmd.Flags().String("fact-check-model", "", "Model for --fact-check (default: the judge's model)")
+	cmd.Flags().Bool("refine", false, "Have a critic review every turn's draft and the agent revise it once before publishing (up to two more calls per turn)")
+	cmd.Flags().Bool("allow-pass", false, "Let debaters answer PASS in the free debate when they have nothing new to add; the judge reads a pass as standing by their previous turn")
+	cmd.Flags().Bool("opening-statements", false, "Open with a round of opening statements, each debater stating an initial position without seeing the others'")
+	cmd.Flags().Bool("closing-statements", false, "End the Tenth Man phase with a round of closing statements, each debater stating a final position")
+	cmd.Flags().Bool("classify-topic", false, "Classify the topic as a question of fact or of value first, and adapt the debaters' prompts and the judging to it")
+	cmd.Flags().String("topic-kind", "", "Treat the topic as a question of fact or value without classifying it: fact or value")
+	cmd.Flags().Int("max-agent-failures", 0, "Remove a debater after this many failed turns in a row instead of failing the run (0 fails on the first)")
+	cmd.Flags().StringArray("image", nil, "Image file or URL to attach to the topic, e.g. a chart or screenshot; only vision-capable models are used (repeatable)")
+	cmd.Flags().String("reasoning-effort", "", "Reasoning effort for reasoning models: low, medium or high (default: the model's own); traces are kept out of the debate")
+	cmd.Flags().String("judge", "", "Consensus judge strategy: "+strings.Join(runner.NewStrategies().Judges(), ", ")+" (default "+runner.DefaultJudge+")")
+	cmd.Flags().String("tenth-man", "", "Tenth Man strategy: "+strings.Join(runner.NewStrategies().TenthMen(), ", ")+" (default "+runner.DefaultTenthMan+")")
+	cmd.Flags().Int("judge-window", 0, "Judge consensus on only the last N rounds, so early disagreement does not mask later convergence (0 judges every round)")
+	cmd.Flags().String("stop-when", "", `Condition ending the free debate without the Tenth Man, e.g. "round >= 6 && consensus.score < 4"`)
+	cmd.Flags().String("tenth-man-when", "", `Condition activating the Tenth Man instead of a consensus scoring 7 or more, e.g. "consensus.score >= 8 && dissenters == 0"`)
+	cmd.Flags().StringSlice("experts", nil, "Built-in expert archetypes to seat, e.g. security,legal,economics")
+	cmd.Flags().String("roster", "", "YAML file defining each agent's name, model, role, expertise and temperature")
+	cmd.Flags().String("continue", "", "Extend a finished run directory with more rounds instead of starting a new debate")
+	cmd.Flags().Int("rounds", 3, "Rounds to add with --continue")
+	cmd.Flags().Bool("interactive", false, "Read new information from stdin while the debate runs; each line is shown to all agents from the next round")
+	cmd.Flags().StringArray("inject", nil, "New information shown to every agent before the continued rounds (repeatable, with --continue)")
+
+	cmd.RegisterFlagCompletionFunc("template", completeTemplates)
+	cmd.MarkFlagDirname("template-dir")
+	cmd.RegisterFlagCompletionFunc("preset", completeValues(templates.Presets...))
+	cmd.RegisterFlagCompletionFunc("compress", completeValues(output.CompressGzip, output.CompressZstd))
+	cmd.MarkFlagFilename("report-template")
+	cmd.RegisterFlagCompletionFunc("log-format", completeValues(output.LogText, output.LogJSON))
+	cmd.RegisterFlagCompletionFunc("sink", completePrefixes("terminal", "stdout", "file:", "webhook:", "store:"))
+	cmd.RegisterFlagCompletionFunc("sample-pick", completeValues(debate.PickLLM, debate.PickHeuristic))
+	cmd.RegisterFlagCompletionFunc("fact-check-model", completeModels)
+	cmd.MarkFlagFilename("image", "png", "jpg", "jpeg", "gif", "webp")
+	cmd.RegisterFlagCompletionFunc("reasoning-effort", completeValues(openrouter.EffortLow, openrouter.EffortMedium, openrouter.EffortHigh))
+	cmd.RegisterFlagCompletionFunc("judge", completeValues(runner.NewStrategies().Judges()...))
+	cmd.RegisterFlagCompletionFunc("tenth-man", completeValues(runner.NewStrategies().TenthMen()...))
+	cmd.RegisterFlagCompletionFunc("experts", completeValues(runner.ExpertKeys()...))
+	cmd.MarkFlagFilename("roster", "yaml", "yml")
+	cmd.MarkFlagDirname("continue")
 	return cmd
 }
 
 func runDebate(cmd *cobra.Command, args []string) error {
+	if dir, _ := cmd.Flags().GetString("continue"); dir != "" {
+		return continueDebate(cmd, dir)
+	}
 	topic, _ := cmd.Flags().GetString("topic")
+	if topic == "" {
+		return fmt.Errorf(`required flag(s) "topic" not set`)
+	}
 	name, _ := cmd.Flags().GetString("name")
-	apiKey, _ := cmd.Root().PersistentFlags().GetString("api-key")
 	outputDir, _ := cmd.Root().PersistentFlags().GetString("output-dir")
-	agentCount, _ := cmd.Root().PersistentFlags().GetInt("agents")
-	minRounds, _ := cmd.Root().PersistentFlags().GetInt("min-rounds")
-	maxRounds, _ := cmd.Root().PersistentFlags().GetInt("max-rounds")
 
-	if apiKey == "" {
-		apiKey = os.Getenv("OPENROUTER_API_KEY")
+	job, err := jobWithTemplate(cmd)
+	if err != nil {
+		return err
 	}
-	if apiKey == "" {
-		return fmt.Errorf("API key required: set --api-key flag or OPENROUTER_API_KEY env var")
+	job.Topic = topic
+	job.Name = name
+	job.Sinks, _ = cmd.Flags().GetStringSlice("sink")
+	if err := job.Validate(); err != nil {
+		return err
 	}
-	if agentCount < 3 {
-		return fmt.Errorf("agent count must be >= 3, got %d", agentCount)
+
+	apiKey, err := resolveAPIKey(cmd)
+	if err != nil {
+		return err
 	}
 
 	// Setup context with Ctrl+C cancellation
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
-	// Create OpenRouter client
-	client := openrouter.NewClient(apiKey)
+	client, err := newClient(cmd, apiKey)
+	if err != nil {
+		return err
+	}
 	client.SetMaxTokens(500)
+	client.SetAdaptivePacing(maxPace)
+	registry := loadRegistry(ctx, client)
 
-	// Fetch live models, fallback to defaults
-	allModels, err := client.ListModels(ctx)
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	if interactive {
+		stdinEvents(ctx, &job)
+	}
+
+	outcome, err := runner.Run(ctx, client, registry, outputDir, job, runner.Hooks{
+		OnStart: func(dir string) {
+			fmt.Printf("%s %s\n", output.Bold("Debate:"), output.Colorize(output.AnsiMagenta, topic))
+			fmt.Printf("Agents: %d | Rounds: %d-%d | Output: %s\n\n", job.Agents, job.MinRounds, job.MaxRounds, dir)
+			if interactive {
+				fmt.Printf("Type new information and press Enter to share it with the agents from the next round.\n")
+				fmt.Printf("Type /swap <agent> <model> to move an agent to another model, /remove <agent> [reason] to take it out,\n")
+				fmt.Printf("or /join <name|expert> [model] to add a debater, from the next round.\n\n")
+			}
+		},
+	})
 	if err != nil {
-		fmt.Printf("Warning: could not fetch models: %v. Using defaults.\n", err)
-		allModels = models.DefaultFreeModels()
+		return fmt.Errorf("debate: %w", err)
 	}
-	registry := models.NewRegistry(allModels)
-	if len(registry.FreeModels()) == 0 {
-		registry = models.NewRegistry(models.DefaultFreeModels())
+
+	if outcome.Result.Stagnated {
+		fmt.Printf("\nFree debate ended early after round %d: rounds stopped adding new information.\n", outcome.Result.Transcript.Rounds)
+	}
+	if outcome.Result.Settled != "" {
+		fmt.Printf("\nFree debate ended after round %d: %s.\n", outcome.Result.Transcript.Rounds, outcome.Result.Settled)
+	}
+	if outcome.Result.Partial {
+		fmt.Printf("\nDebate ended early after round %d: the retry budget was spent. Results are partial.\n", outcome.Result.Transcript.Rounds)
 	}
-	selected := registry.SelectModels(agentCount + 1)
+	output.PrintConsensus(outcome.Consensus)
+	output.PrintOutcome(outcome.Result.Verdict())
+	output.PrintPositionChange(outcome.Result.Transcript.PositionChange)
+	output.PrintMinorityReports(outcome.Result.MinorityReports)
+	fmt.Printf("\nDebate complete. Output saved to: %s\n", outcome.Dir)
+	if outcome.Uploaded != "" {
+		fmt.Printf("Uploaded to: %s\n", outcome.Uploaded)
+	}
+	return nil
+}
 
-	// Build agents
-	agents := make([]debate.Agent, agentCount)
-	names := []string{"Alice", "Bob", "Carol", "Dave", "Eve", "Frank", "Grace", "Heidi", "Ivan"}
-	for i := range agentCount {
-		agentName := fmt.Sprintf("Agent-%d", i+1)
-		if i < len(names) {
-			agentName = names[i]
-		}
-		agents[i] = debate.Agent{
-			ID:    i + 1,
-			Name:  agentName,
-			Model: selected[i].ID,
-			Role:  "debater",
+// stdinEvents reads stdin until ctx is done, feeding job's live channels.
+// Lines of the form "/swap <agent> <model>" are sent as model swaps,
+// "/remove <agent> [reason]" as agent removals and "/join <name|expert>
+// [model]" as new debaters; every other non-empty line is sent as new
+// information.
+func stdinEvents(ctx context.Context, job *runner.Job) {
+	events := make(chan string)
+	swaps := make(chan debate.ModelSwap)
+	removals := make(chan debate.AgentRemoval)
+	joins := make(chan debate.Agent)
+	job.Events, job.Swaps, job.Removals, job.Joins = events, swaps, removals, joins
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			if cmd, ok := strings.CutPrefix(line, "/swap"); ok {
+				fields := strings.Fields(cmd)
+				if len(fields) != 2 {
+					fmt.Fprintln(os.Stderr, "Usage: /swap <agent> <model>")
+					continue
+				}
+				select {
+				case swaps <- debate.ModelSwap{Agent: fields[0], To: fields[1]}:
+					fmt.Printf("%s\n", output.Colorize(output.AnsiMagenta, fmt.Sprintf("Model swap for %s queued for the next round.", fields[0])))
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			if cmd, ok := strings.CutPrefix(line, "/remove"); ok {
+				agent, reason, _ := strings.Cut(strings.TrimSpace(cmd), " ")
+				if agent == "" {
+					fmt.Fprintln(os.Stderr, "Usage: /remove <agent> [reason]")
+					continue
+				}
+				if reason = strings.TrimSpace(reason); reason == "" {
+					reason = "removed by the operator"
+				}
+				select {
+				case removals <- debate.AgentRemoval{Agent: agent, Reason: reason}:
+					fmt.Printf("%s\n", output.Colorize(output.AnsiMagenta, fmt.Sprintf("Removal of %s queued for the next round.", agent)))
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			if cmd, ok := strings.CutPrefix(line, "/join"); ok {
+				fields := strings.Fields(cmd)
+				if len(fields) == 0 || len(fields) > 2 {
+					fmt.Fprintln(os.Stderr, "Usage: /join <name|expert> [model]")
+					continue
+				}
+				agent := debate.Agent{Name: fields[0], Role: "debater"}
+				if personas, err := runner.ExpertPersonas(fields[:1]); err == nil {
+					agent.Name, agent.Persona, agent.Frame = personas[0].Name, personas[0].Description, personas[0].Frame
+				}
+				if len(fields) == 2 {
+					agent.Model = fields[1]
+				}
+				select {
+				case joins <- agent:
+					fmt.Printf("%s\n", output.Colorize(output.AnsiMagenta, fmt.Sprintf("%s will join from the next round.", agent.Name)))
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case events <- line:
+				fmt.Printf("%s\n", output.Colorize(output.AnsiMagenta, "Moderator note queued for the next round."))
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
+}
+
+// continueDebate runs more rounds on the finished run in dir.
+func continueDebate(cmd *cobra.Command, dir string) error {
+	rounds, _ := cmd.Flags().GetInt("rounds")
+	notes, _ := cmd.Flags().GetStringArray("inject")
+	upload, _ := cmd.Root().PersistentFlags().GetString("upload")
+	encryptTo, _ := cmd.Root().PersistentFlags().GetStringArray("encrypt-to")
+	reportTemplate, _ := cmd.Flags().GetString("report-template")
+	sinks, _ := cmd.Flags().GetStringSlice("sink")
+	maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+	maxWords, _ := cmd.Flags().GetInt("max-words")
+	redactSecrets, _ := cmd.Flags().GetBool("redact")
+	redactPatterns, _ := cmd.Flags().GetStringArray("redact-pattern")
+
+	apiKey, err := resolveAPIKey(cmd)
+	if err != nil {
+		return err
 	}
 
-	// Create judge and tenth man activator
-	judgeModel := selected[0].ID
-	judge := consensus.NewJudge(client, judgeModel)
-	tm := tenthman.NewActivator()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	// Setup output directory
-	slug := name
-	if slug == "" {
-		slug = output.GenerateSlug(topic)
+	client, err := newClient(cmd, apiKey)
+	if err != nil {
+		return err
 	}
-	outDir, err := output.CreateOutputDir(outputDir, slug)
+	client.SetMaxTokens(500)
+	client.SetAdaptivePacing(maxPace)
+
+	outcome, err := runner.Continue(ctx, client, runner.Extension{Dir: dir, Rounds: rounds, Notes: notes, Upload: upload, ReportTemplate: reportTemplate, Sinks: sinks, MaxTokens: maxTokens, MaxWords: maxWords, Redact: redactSecrets, RedactPatterns: redactPatterns, EncryptTo: encryptTo}, runner.Hooks{
+		OnStart: func(dir string) {
+			fmt.Printf("%s %s (+%d rounds)\n\n", output.Bold("Continuing:"), output.Colorize(output.AnsiMagenta, dir), rounds)
+		},
+	})
 	if err != nil {
-		return fmt.Errorf("creating output directory: %w", err)
+		return fmt.Errorf("debate: %w", err)
 	}
 
-	// Run debate
-	fmt.Printf("%s %s\n", output.Bold("Debate:"), output.Colorize(output.AnsiMagenta, topic))
-	fmt.Printf("Agents: %d | Rounds: %d-%d | Output: %s\n\n", agentCount, minRounds, maxRounds, outDir)
+	output.PrintConsensus(outcome.Consensus)
+	output.PrintOutcome(outcome.Result.Verdict())
+	output.PrintPositionChange(outcome.Result.Transcript.PositionChange)
+	output.PrintMinorityReports(outcome.Result.MinorityReports)
+	fmt.Printf("\nDebate extended to %d rounds. Output updated in: %s\n", outcome.Result.Transcript.Rounds, outcome.Dir)
+	if outcome.Uploaded != "" {
+		fmt.Printf("Uploaded to: %s\n", outcome.Uploaded)
+	}
+	return nil
+}
+
+// jobWithTemplate builds a job for cmd: explicitly set flags win over the
+// --template settings, which win over the --preset, which wins over flag
+// defaults.
+func jobWithTemplate(cmd *cobra.Command) (runner.Job, error) {
+	var job runner.Job
+	if cmd.Flags().Changed("agents") {
+		job.Agents, _ = cmd.Flags().GetInt("agents")
+	}
+	if cmd.Flags().Changed("min-rounds") {
+		job.MinRounds, _ = cmd.Flags().GetInt("min-rounds")
+	}
+	if cmd.Flags().Changed("max-rounds") {
+		job.MaxRounds, _ = cmd.Flags().GetInt("max-rounds")
+	}
+	if cmd.Flags().Changed("compress") {
+		job.Compress, _ = cmd.Flags().GetString("compress")
+	}
+	if cmd.Flags().Changed("report-template") {
+		job.ReportTemplate, _ = cmd.Flags().GetString("report-template")
+	}
+	if cmd.Flags().Changed("log-format") {
+		job.LogFormat, _ = cmd.Flags().GetString("log-format")
+	}
+	if cmd.Flags().Changed("stagnation-rounds") {
+		job.StagnationRounds, _ = cmd.Flags().GetInt("stagnation-rounds")
+	}
+	if cmd.Flags().Changed("adaptive-rounds") {
+		job.AdaptiveRounds, _ = cmd.Flags().GetBool("adaptive-rounds")
+	}
+	if cmd.Flags().Changed("target-rounds") {
+		job.TargetRounds, _ = cmd.Flags().GetInt("target-rounds")
+	}
+	if cmd.Flags().Changed("velocity-window") {
+		job.VelocityWindow, _ = cmd.Flags().GetInt("velocity-window")
+	}
+	if cmd.Flags().Changed("min-velocity") {
+		job.MinVelocity, _ = cmd.Flags().GetFloat64("min-velocity")
+	}
+	if cmd.Flags().Changed("token-budget") {
+		job.TokenBudget, _ = cmd.Flags().GetInt("token-budget")
+	}
+	if cmd.Flags().Changed("retry-budget") {
+		job.RetryBudget, _ = cmd.Flags().GetInt("retry-budget")
+	}
+	if cmd.Flags().Changed("max-tokens") {
+		job.MaxTokens, _ = cmd.Flags().GetInt("max-tokens")
+	}
+	if cmd.Flags().Changed("max-words") {
+		job.MaxWords, _ = cmd.Flags().GetInt("max-words")
+	}
+	if cmd.Flags().Changed("image") {
+		job.Images, _ = cmd.Flags().GetStringArray("image")
+	}
+	if cmd.Flags().Changed("samples") {
+		job.Samples, _ = cmd.Flags().GetInt("samples")
+	}
+	if cmd.Flags().Changed("sample-pick") {
+		job.SamplePick, _ = cmd.Flags().GetString("sample-pick")
+	}
+	if cmd.Flags().Changed("cross-examination") {
+		job.CrossExamination, _ = cmd.Flags().GetInt("cross-examination")
+	}
+	if cmd.Flags().Changed("redact") {
+		job.Redact, _ = cmd.Flags().GetBool("redact")
+	}
+	if cmd.Flags().Changed("redact-pattern") {
+		job.RedactPatterns, _ = cmd.Flags().GetStringArray("redact-pattern")
+	}
+	if cmd.Flags().Changed("plugin") {
+		job.Plugins, _ = cmd.Flags().GetStringArray("plugin")
+	}
+	if cmd.Flags().Changed("disagreement") {
+		job.Disagreement, _ = cmd.Flags().GetBool("disagreement")
+	}
+	if cmd.Flags().Changed("fact-check") {
+		job.FactCheck, _ = cmd.Flags().GetBool("fact-check")
+	}
+	if cmd.Flags().Changed("fact-check-model") {
+		job.FactCheckModel, _ = cmd.Flags().GetString("fact-check-model")
+	}
+	if cmd.Flags().Changed("refine") {
+		job.Refine, _ = cmd.Flags().GetBool("refine")
+	}
+	if cmd.Flags().Changed("allow-pass") {
+		job.AllowPass, _ = cmd.Flags().GetBool("allow-pass")
+	}
+	if cmd.Flags().Changed("opening-statements") {
+		job.Opening, _ = cmd.Flags().GetBool("opening-statements")
+	}
+	if cmd.Flags().Changed("closing-statements") {
+		job.Closing, _ = cmd.Flags().GetBool("closing-statements")
+	}
+	if cmd.Flags().Changed("classify-topic") {
+		job.ClassifyTopic, _ = cmd.Flags().GetBool("classify-topic")
+	}
+	if cmd.Flags().Changed("topic-kind") {
+		job.TopicKind, _ = cmd.Flags().GetString("topic-kind")
+	}
+	if cmd.Flags().Changed("max-agent-failures") {
+		job.MaxAgentFailures, _ = cmd.Flags().GetInt("max-agent-failures")
+	}
+	if cmd.Flags().Changed("reasoning-effort") {
+		job.ReasoningEffort, _ = cmd.Flags().GetString("reasoning-effort")
+	}
+	if cmd.Flags().Changed("judge") {
+		job.Judge, _ = cmd.Flags().GetString("judge")
+	}
+	if cmd.Flags().Changed("tenth-man") {
+		job.TenthMan, _ = cmd.Flags().GetString("tenth-man")
+	}
+	if cmd.Flags().Changed("judge-window") {
+		job.JudgeWindow, _ = cmd.Flags().GetInt("judge-window")
+	}
+	if cmd.Flags().Changed("stop-when") {
+		job.StopWhen, _ = cmd.Flags().GetString("stop-when")
+	}
+	if cmd.Flags().Changed("tenth-man-when") {
+		job.TenthManWhen, _ = cmd.Flags().GetString("tenth-man-when")
+	}
 
-	writer := output.NewWriter(outDir)
+	name, _ := cmd.Flags().GetString("template")
+	if name != "" {
+		dirs, _ := cmd.Flags().GetStringSlice("template-dir")
+		tmpl, err := templates.Load(name, append(dirs, templates.UserDirs()...))
+		if err != nil {
+			return runner.Job{}, err
+		}
+		job = tmpl.Apply(job)
+	}
+	if name, _ := cmd.Flags().GetString("preset"); name != "" {
+		preset, err := templates.LoadPreset(name)
+		if err != nil {
+			return runner.Job{}, err
+		}
+		job = preset.Apply(job)
+	}
 
-	engine := debate.NewEngine(topic, agents, client, judge, tm, minRounds, maxRounds)
-	engine.SetTenthManModel(selected[agentCount].ID)
-	engine.OnTurn = func(turn debate.Turn) {
-		output.PrintTurn(turn)
-		writer.Log(fmt.Sprintf("[Round %d] %s (%s): %s", turn.Round, turn.Agent.Name, turn.Agent.Model, turn.Content))
+	if experts, _ := cmd.Flags().GetStringSlice("experts"); len(experts) > 0 {
+		job.Experts = experts
 	}
-	engine.OnPhase = func(phase debate.Phase) {
-		output.PrintPhase(phase)
-		writer.Log(fmt.Sprintf("Phase transition: %d", phase))
+
+	if path, _ := cmd.Flags().GetString("roster"); path != "" {
+		roster, err := runner.LoadRoster(path)
+		if err != nil {
+			return runner.Job{}, err
+		}
+		job.Roster = roster
 	}
+	return job.WithDefaults(jobFromFlags(cmd)), nil
+}
 
-	result, err := engine.Run(ctx)
-	if err != nil {
-		return fmt.Errorf("debate: %w", err)
+// jobFromFlags builds a job from the root persistent flags and the
+// conditions, judge window and adaptive rounds settings in the user config
+// file.
+func jobFromFlags(cmd *cobra.Command) runner.Job {
+	agentCount, _ := cmd.Root().PersistentFlags().GetInt("agents")
+	minRounds, _ := cmd.Root().PersistentFlags().GetInt("min-rounds")
+	maxRounds, _ := cmd.Root().PersistentFlags().GetInt("max-rounds")
+	upload, _ := cmd.Root().PersistentFlags().GetString("upload")
+	encryptTo, _ := cmd.Root().PersistentFlags().GetStringArray("encrypt-to")
+	layout, _ := cmd.Root().PersistentFlags().GetString("layout")
+	project, _ := cmd.Root().PersistentFlags().GetString("project")
+	job := runner.Job{Agents: agentCount, MinRounds: minRounds, MaxRounds: maxRounds, Upload: upload, EncryptTo: encryptTo, Layout: layout, Project: project}
+	if file, _ := loadUserConfig(); file != nil {
+		job.StopWhen, job.TenthManWhen = file.StopWhen, file.TenthManWhen
+		job.JudgeWindow = file.JudgeWindow
+		job.AdaptiveRounds, job.TargetRounds = file.AdaptiveRounds, file.TargetRounds
+		job.VelocityWindow, job.MinVelocity = file.VelocityWindow, file.MinVelocity
 	}
+	return job
+}
 
-	// Write outputs
-	if err := writer.WriteJSON(result.Transcript); err != nil {
-		return fmt.Errorf("writing JSON: %w", err)
+func resolveAPIKey(cmd *cobra.Command) (string, error) {
+	apiKey, _ := cmd.Root().PersistentFlags().GetString("api-key")
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENROUTER_API_KEY")
 	}
-	consensus := result.Consensus
-	if consensus == nil {
-		consensus = &debate.ConsensusResult{}
+	if apiKey == "" {
+		if file, _ := loadUserConfig(); file != nil {
+			apiKey = file.APIKey
+		}
 	}
-	if err := writer.WriteMarkdown(result.Transcript, consensus); err != nil {
-		return fmt.Errorf("writing markdown: %w", err)
+	if apiKey == "" {
+		return "", fmt.Errorf("API key required: set --api-key flag or OPENROUTER_API_KEY env var, or run tenthman init")
 	}
+	return apiKey, nil
+}
 
-	if err := writer.WriteLog(); err != nil {
-		return fmt.Errorf("writing log: %w", err)
+// newClient returns an OpenRouter client for apiKey, talking to the base URL
+// set with --base-url or OPENROUTER_BASE_URL, if any, and recording to or
+// replaying from the cassette set with --record or --replay.
+func newClient(cmd *cobra.Command, apiKey string) (*openrouter.Client, error) {
+	baseURL, _ := cmd.Root().PersistentFlags().GetString("base-url")
+	if baseURL == "" {
+		baseURL = os.Getenv("OPENROUTER_BASE_URL")
+	}
+	client := openrouter.NewClient(apiKey)
+	if baseURL != "" {
+		client = openrouter.NewClientWithBaseURL(apiKey, strings.TrimSuffix(baseURL, "/"))
 	}
 
-	output.PrintConsensus(consensus)
-	fmt.Printf("\nDebate complete. Output saved to: %s\n", outDir)
-	return nil
+	record, _ := cmd.Root().PersistentFlags().GetString("record")
+	replay, _ := cmd.Root().PersistentFlags().GetString("replay")
+	switch {
+	case record != "" && replay != "":
+		return nil, fmt.Errorf("--record and --replay cannot be used together")
+	case record != "":
+		client.SetTransport(openrouter.NewRecorder(record, nil))
+	case replay != "":
+		replayer, err := openrouter.LoadCassette(replay)
+		if err != nil {
+			return nil, err
+		}
+		client.SetTransport(replayer)
+	}
+	return client, nil
+}
+
+// loadRegistry fetches live models, falling back to the built-in free list.
+func loadRegistry(ctx context.Context, client *openrouter.Client) *models.Registry {
+	allModels, err := client.ListModels(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not fetch models: %v. Using defaults.\n", err)
+		allModels = models.DefaultFreeModels()
+	}
+	registry := models.NewRegistry(allModels)
+	if len(registry.FreeModels()) == 0 {
+		registry = models.NewRegistry(models.DefaultFreeModels())
+	}
+	if file, _ := loadUserConfig(); file != nil && len(file.Models) > 0 {
+		// Draw debaters from the models chosen with tenthman init, as long
+		// as any of them is still listed.
+		var chosen []openrouter.Model
+		for _, m := range registry.FreeModels() {
+			if slices.Contains(file.Models, m.ID) {
+				chosen = append(chosen, m)
+			}
+		}
+		if len(chosen) > 0 {
+			return models.NewRegistry(chosen)
+		}
+		fmt.Fprintln(os.Stderr, "Warning: none of the configured models is listed as free. Using every free model.")
+	}
+	return registry
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/templates"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+func newDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate reference documentation for tenthman",
+	}
+	man := &cobra.Command{
+		Use:   "man",
+		Short: "Write a man page for every command",
+		Long: `Man writes tenthman.1 and one page per subcommand, such as
+tenthman-debate.1, into --dir. Install them with, for example:
+
+  tenthman docs man --dir /usr/local/share/man/man1`,
+		Args: cobra.NoArgs,
+		RunE: runDocsMan,
+	}
+	man.Flags().String("dir", "man", "Directory to write the man pages into")
+	man.MarkFlagDirname("dir")
+	cmd.AddCommand(man)
+	return cmd
+}
+
+func runDocsMan(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("docs: %w", err)
+	}
+	root := cmd.Root()
+	// Without the generation date the pages only change with the commands.
+	root.DisableAutoGenTag = true
+	header := &doc.GenManHeader{Title: "TENTHMAN", Section: "1", Source: "tenthman", Manual: "Tenth Man Rule Manual"}
+	if err := doc.GenManTree(root, header, dir); err != nil {
+		return fmt.Errorf("docs: %w", err)
+	}
+	fmt.Printf("Man pages written to %s\n", dir)
+	return nil
+}
+
+// completeValues completes a flag with a fixed set of values.
+func completeValues(values ...string) cobra.CompletionFunc {
+	return cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp)
+}
+
+// completePrefixes completes a flag with the schemes of its values, such as
+// "s3://", leaving the cursor after them.
+func completePrefixes(prefixes ...string) cobra.CompletionFunc {
+	return cobra.FixedCompletions(prefixes, cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace)
+}
+
+// completeModels completes a flag with the IDs of the built-in free models.
+func completeModels(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	var ids []cobra.Completion
+	for _, m := range models.DefaultFreeModels() {
+		ids = append(ids, cobra.CompletionWithDesc(m.ID, m.Name))
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplates completes --template with the templates found in
+// --template-dir and the user config dir. A path to a .yaml file also works.
+func completeTemplates(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	dirs, _ := cmd.Flags().GetStringSlice("template-dir")
+	list, err := templates.List(append(dirs, templates.UserDirs()...))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names := make([]cobra.Completion, len(list))
+	for i, t := range list {
+		names[i] = cobra.CompletionWithDesc(t.Name, t.Description)
+	}
+	return names, cobra.ShellCompDirectiveDefault
+}
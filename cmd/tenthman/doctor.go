@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/health"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/server"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// doctorTimeout bounds every check, so an unreachable service fails instead
+// of hanging the command.
+const doctorTimeout = 15 * time.Second
+
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the API key, OpenRouter, output directory and serve config",
+		Long: `Runs the same checks as the server's readiness probe plus local setup checks and
+prints a checklist. Include its output in support requests. Exits with status 1 if any
+check fails.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runDoctor,
+	}
+	cmd.Flags().String("config", "", "Serve config file to validate, including its store")
+	cmd.MarkFlagFilename("config", "yaml", "yml")
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	outputDir, _ := cmd.Root().PersistentFlags().GetString("output-dir")
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+
+	apiKey, keyErr := resolveAPIKey(cmd)
+	client, clientErr := newClient(cmd, apiKey)
+	if clientErr != nil {
+		return clientErr
+	}
+	checks := []health.Check{
+		{Name: "api key", Run: func(context.Context) error { return keyErr }},
+		{Name: "openrouter", Run: client.Ping},
+		{Name: "free models", Run: func(ctx context.Context) error {
+			all, err := client.ListModels(ctx)
+			if err != nil {
+				return err
+			}
+			if len(models.NewRegistry(all).FreeModels()) == 0 {
+				return fmt.Errorf("no free models listed; debates fall back to the built-in list")
+			}
+			return nil
+		}},
+		{Name: "output dir " + outputDir, Run: func(context.Context) error { return writable(outputDir) }},
+	}
+	if configPath != "" {
+		cfg, cfgErr := server.LoadConfig(configPath)
+		checks = append(checks, health.Check{Name: "serve config " + configPath, Run: func(context.Context) error { return cfgErr }})
+		if cfgErr == nil && cfg.Store.Driver != "" {
+			checks = append(checks, health.Check{Name: "store " + cfg.Store.Driver, Run: func(ctx context.Context) error {
+				return pingStore(ctx, cfg.Store)
+			}})
+		}
+	}
+
+	fmt.Printf("%s (%s, %s/%s)\n", output.Bold("tenthman doctor"), runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	results := health.Run(ctx, checks)
+	for _, r := range results {
+		output.PrintCheck(r)
+	}
+	if !health.Healthy(results) {
+		return exitError{1}
+	}
+	return nil
+}
+
+// writable checks that files can be created in dir, creating it if needed.
+func writable(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// pingStore opens the store c describes and pings it if it can be pinged.
+func pingStore(ctx context.Context, c store.Config) error {
+	st, err := c.Open(ctx)
+	if err != nil {
+		return err
+	}
+	if closer, ok := st.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if pinger, ok := st.(store.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// errorKinds maps the error kinds users can act on to a stable name for JSON
+// output and a suggestion for the terminal.
+var errorKinds = []struct {
+	err  error
+	kind string
+	hint string
+}{
+	{openrouter.ErrRateLimited, "rate_limited", "OpenRouter kept rate limiting requests; wait and retry, or lower --rpm in batch and serve mode."},
+	{openrouter.ErrInvalidModel, "invalid_model", "OpenRouter does not know a model ID; check the IDs passed to --roster."},
+	{openrouter.ErrModerated, "moderated", "A provider's moderation flagged the prompt; rephrase the question or pick models without moderation."},
+	{openrouter.ErrCircuitOpen, "circuit_open", "Every model tried kept failing and is paused for a minute; wait, or pick other models with --roster."},
+	{openrouter.ErrModelUnavailable, "model_unavailable", "A model could not be reached; run `tenthman doctor`, or pick other models with --roster."},
+	{debate.ErrConsensusParse, "consensus_parse", "The consensus judge kept returning output that is not valid JSON; retry, or use a roster whose first model follows instructions better."},
+	{debate.ErrBudgetExceeded, "budget_exceeded", "The debate used its token budget; raise --token-budget or lower --max-rounds."},
+}
+
+// errorKind returns the name of err's kind, or "" for other errors.
+func errorKind(err error) string {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			return k.kind
+		}
+	}
+	return ""
+}
+
+// errorHint suggests what to do about err, or returns "".
+func errorHint(err error) string {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			return k.hint
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/eval"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/mockserver"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+func newEvalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Run the benchmark debates and check their results for regressions",
+		Long: `Eval runs a fixed suite of benchmark topics and checks structural invariants of
+each result: the debate completes, the transcript and report are saved with every
+standard section, consensus is detected where it is expected and the Tenth Man
+takes part. Use it to validate prompt and template changes before a release.
+
+Run it against the built-in mock server with --mock, against a cassette with
+--replay (record one with --record), or against the free models. Runs are written
+to a temporary directory that is removed when every case passes. Exits with
+status 1 if any check fails.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runEval,
+	}
+	cmd.Flags().String("suite", "", "YAML suite file to run instead of the built-in benchmark suite")
+	cmd.Flags().StringSlice("case", nil, "Run only these cases of the suite")
+	cmd.Flags().Bool("mock", false, "Answer every request from an in-process mock OpenRouter server instead of the network")
+	cmd.Flags().Bool("keep", false, "Keep the run directories even when every case passes")
+	cmd.MarkFlagFilename("suite", "yaml", "yml")
+	cmd.RegisterFlagCompletionFunc("case", func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		suite, err := loadSuite(cmd)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var names []cobra.Completion
+		for _, c := range suite.Cases {
+			names = append(names, cobra.CompletionWithDesc(c.Job.Name, c.Job.Topic))
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func runEval(cmd *cobra.Command, args []string) error {
+	suite, err := loadSuite(cmd)
+	if err != nil {
+		return err
+	}
+	cases, _ := cmd.Flags().GetStringSlice("case")
+	if suite, err = suite.Filter(cases); err != nil {
+		return err
+	}
+	mock, _ := cmd.Flags().GetBool("mock")
+	keep, _ := cmd.Flags().GetBool("keep")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var client *openrouter.Client
+	var registry *models.Registry
+	if mock {
+		srv := httptest.NewServer(mockserver.New())
+		defer srv.Close()
+		client = openrouter.NewClientWithBaseURL("mock", srv.URL)
+		registry = models.NewRegistry(models.DefaultFreeModels())
+	} else {
+		apiKey, err := resolveAPIKey(cmd)
+		if err != nil {
+			return err
+		}
+		if client, err = newClient(cmd, apiKey); err != nil {
+			return err
+		}
+		client.SetAdaptivePacing(maxPace)
+		registry = loadRegistry(ctx, client)
+	}
+	client.SetMaxTokens(500)
+
+	dir, err := os.MkdirTemp("", "tenthman-eval-")
+	if err != nil {
+		return fmt.Errorf("eval: %w", err)
+	}
+	fmt.Printf("%s %d case(s)\n", output.Bold("tenthman eval:"), len(suite.Cases))
+	results := eval.Run(ctx, suite, jobFromFlags(cmd), func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		fmt.Printf("\n%s %s\n", output.Bold(job.Name+":"), job.Topic)
+		return runner.Run(ctx, client, registry, dir, job, runner.Hooks{})
+	})
+	passed := 0
+	fmt.Println()
+	for _, r := range results {
+		if r.Passed() {
+			passed++
+		}
+		fmt.Printf("%s\n", output.Bold(r.Case))
+		for _, c := range r.Checks {
+			output.PrintCheck(c)
+		}
+	}
+	fmt.Printf("\n%d of %d case(s) passed.\n", passed, len(results))
+
+	if eval.Passed(results) && !keep {
+		os.RemoveAll(dir)
+		return nil
+	}
+	fmt.Printf("Runs kept in %s\n", dir)
+	if !eval.Passed(results) {
+		return exitError{1}
+	}
+	return nil
+}
+
+// loadSuite returns the suite named by --suite, or the built-in one.
+func loadSuite(cmd *cobra.Command) (*eval.Suite, error) {
+	if path, _ := cmd.Flags().GetString("suite"); path != "" {
+		return eval.Load(path)
+	}
+	return eval.Builtin()
+}
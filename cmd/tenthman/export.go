@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <run-dir>...",
+		Short: "Flatten the turns of saved runs into a table for pandas or DuckDB",
+		Long: `Export writes one row per turn of the given runs: run, turn, round, phase,
+agent, model, role, in_reply_to, confidence, tokens, latency_ms and
+content_length. Tokens and latency are empty for runs saved before they were
+recorded.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runExport,
+	}
+	cmd.Flags().String("format", runs.FormatCSV, "Output format: csv or parquet")
+	cmd.Flags().String("out", "", "File to write (default: stdout)")
+	cmd.Flags().Bool("anonymize", false, "Replace agent names and model IDs with stable pseudonyms such as \"Agent A\" and \"Model 1\"")
+	cmd.RegisterFlagCompletionFunc("format", completeValues(runs.FormatCSV, runs.FormatParquet))
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	dest, _ := cmd.Flags().GetString("out")
+	var names *runs.Pseudonyms
+	if anonymize, _ := cmd.Flags().GetBool("anonymize"); anonymize {
+		names = runs.NewPseudonyms()
+	}
+	if err := runs.ValidateExportFormat(format); err != nil {
+		return err
+	}
+
+	if dest == "" {
+		return runs.ExportTurns(os.Stdout, format, names, args...)
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	if err := runs.ExportTurns(f, format, names, args...); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d run(s) to %s\n", len(args), dest)
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/config"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// initTimeout bounds the live model listing of tenthman init.
+const initTimeout = 30 * time.Second
+
+func newInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Set up the API key, default models and output directory interactively",
+		Long: `Init walks through the API key, the free models debaters are drawn from,
+the number of agents and the output directory, and writes them to the user
+config file ($TENTHMAN_CONFIG, or tenthman/config.yaml in the user config
+directory). Every command reads it; flags and OPENROUTER_API_KEY override it.
+Press Enter to keep the value shown in brackets.`,
+		Args: cobra.NoArgs,
+		RunE: runInit,
+	}
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return err
+	}
+	// A broken file is replaced rather than stopping the wizard.
+	existing, _ := config.LoadFile(path)
+	if existing == nil {
+		existing = &config.File{}
+	}
+	in := bufio.NewReader(cmd.InOrStdin())
+	out := cmd.OutOrStdout()
+
+	fmt.Fprintf(out, "%s writing %s\n\n", output.Bold("tenthman init:"), path)
+	if existing.APIKey != "" || existing.OutputDir != "" || len(existing.Models) > 0 {
+		if !confirm(in, out, "A config file already exists. Replace it?") {
+			return nil
+		}
+	}
+
+	// API key.
+	apiKey := existing.APIKey
+	if env := os.Getenv("OPENROUTER_API_KEY"); apiKey == "" && env != "" {
+		apiKey = env
+	}
+	fmt.Fprintln(out, "Get a free OpenRouter API key at https://openrouter.ai/keys. It is saved in the config file, readable by you only.")
+	apiKey = ask(in, out, "API key", maskKey(apiKey), apiKey)
+	if apiKey == "" {
+		return fmt.Errorf("init: an API key is required")
+	}
+
+	// Models, from the live free list when OpenRouter answers.
+	client, err := newClient(cmd, apiKey)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), initTimeout)
+	defer cancel()
+	fmt.Fprintln(out, "\nFetching the free models...")
+	free := models.DefaultFreeModels()
+	if all, err := client.ListModels(ctx); err != nil {
+		fmt.Fprintf(out, "Could not list models (%v); showing the built-in list.\n", err)
+	} else if listed := models.NewRegistry(all).FreeModels(); len(listed) > 0 {
+		free = listed
+	}
+	selected, err := chooseModels(in, out, free, existing.Models)
+	if err != nil {
+		return err
+	}
+
+	// Agents and output directory.
+	agents := existing.Agents
+	if agents == 0 {
+		agents, _ = cmd.Root().PersistentFlags().GetInt("agents")
+	}
+	for {
+		answer := ask(in, out, "\nAgents per debate (at least 3)", strconv.Itoa(agents), strconv.Itoa(agents))
+		if n, err := strconv.Atoi(answer); err == nil && n >= 3 {
+			agents = n
+			break
+		}
+		fmt.Fprintln(out, "Enter a whole number of at least 3.")
+	}
+	outputDir := existing.OutputDir
+	if outputDir == "" {
+		outputDir, _ = cmd.Root().PersistentFlags().GetString("output-dir")
+	}
+	outputDir = ask(in, out, "Output directory", outputDir, outputDir)
+
+	file := &config.File{
+		APIKey:    apiKey,
+		OutputDir: outputDir,
+		Agents:    agents,
+		MinRounds: existing.MinRounds,
+		MaxRounds: existing.MaxRounds,
+		Models:    selected,
+
+		StopWhen:       existing.StopWhen,
+		TenthManWhen:   existing.TenthManWhen,
+		JudgeWindow:    existing.JudgeWindow,
+		AdaptiveRounds: existing.AdaptiveRounds,
+		TargetRounds:   existing.TargetRounds,
+		VelocityWindow: existing.VelocityWindow,
+		MinVelocity:    existing.MinVelocity,
+	}
+	if err := file.Save(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nSaved %s. Try:\n  tenthman debate --topic \"Should we adopt a four-day work week?\"\n", path)
+	return nil
+}
+
+// chooseModels lists free and asks which of them debaters are drawn from,
+// by number. An empty answer keeps current, or every model when current is
+// empty, which is saved as no restriction.
+func chooseModels(in *bufio.Reader, out io.Writer, free []openrouter.Model, current []string) ([]string, error) {
+	fmt.Fprintln(out, "\nDebaters are drawn from these free models:")
+	for i, m := range free {
+		mark := " "
+		if slices.Contains(current, m.ID) {
+			mark = "*"
+		}
+		fmt.Fprintf(out, " %s %2d. %s (%s)\n", mark, i+1, m.ID, m.Name)
+	}
+	def := "all"
+	if len(current) > 0 {
+		def = "the ones marked *"
+	}
+	for {
+		answer := ask(in, out, "Models to use, as numbers separated by commas, or all", def, "")
+		switch answer {
+		case "":
+			return current, nil
+		case "all":
+			return nil, nil
+		}
+		var ids []string
+		valid := true
+		for _, field := range strings.Split(answer, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || n < 1 || n > len(free) {
+				valid = false
+				break
+			}
+			if id := free[n-1].ID; !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		if valid {
+			return ids, nil
+		}
+		fmt.Fprintf(out, "Enter numbers between 1 and %d, e.g. 1,3,4.\n", len(free))
+	}
+}
+
+// ask prints prompt with shown as the value kept on Enter, and returns the
+// trimmed answer, or def if it is empty or input has ended.
+func ask(in *bufio.Reader, out io.Writer, prompt, shown, def string) string {
+	if shown != "" {
+		fmt.Fprintf(out, "%s [%s]: ", prompt, shown)
+	} else {
+		fmt.Fprintf(out, "%s: ", prompt)
+	}
+	line, _ := in.ReadString('\n')
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// confirm asks a yes/no question, defaulting to no.
+func confirm(in *bufio.Reader, out io.Writer, question string) bool {
+	answer := strings.ToLower(ask(in, out, question+" (y/N)", "", ""))
+	return answer == "y" || answer == "yes"
+}
+
+// maskKey shows only the end of an API key.
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return strings.Repeat("*", len(key))
+	}
+	return "..." + key[len(key)-4:]
+}
+
+// applyUserConfig makes the user config file's settings the defaults of the
+// root flags the command line left unset.
+func applyUserConfig(cmd *cobra.Command) error {
+	file, err := loadUserConfig()
+	if err != nil || file == nil {
+		return err
+	}
+	flags := cmd.Root().PersistentFlags()
+	for name, value := range map[string]string{
+		"output-dir": file.OutputDir,
+		"agents":     strconv.Itoa(file.Agents),
+		"min-rounds": strconv.Itoa(file.MinRounds),
+		"max-rounds": strconv.Itoa(file.MaxRounds),
+	} {
+		if value == "" || value == "0" || flags.Changed(name) {
+			continue
+		}
+		// Set the value without marking the flag changed, so templates and
+		// batch jobs still override it as they do the built-in defaults.
+		if err := flags.Lookup(name).Value.Set(value); err != nil {
+			return fmt.Errorf("config: %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// loadUserConfig reads the user config file, nil if there is none.
+func loadUserConfig() (*config.File, error) {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return nil, nil
+	}
+	return config.LoadFile(path)
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -12,20 +14,59 @@ func main() {
 		Use:   "tenthman",
 		Short: "Multi-agent debate orchestrator using the Tenth Man Rule",
 		Long:  "Orchestrates multi-agent debates, research, and analysis using free LLM models via OpenRouter. If 9 people agree, the 10th is obligated to argue the contrary position.",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Name() == "init" {
+				return nil // init replaces the config file, even a broken one
+			}
+			return applyUserConfig(cmd)
+		},
 	}
 
 	root.PersistentFlags().String("api-key", "", "OpenRouter API key (overrides OPENROUTER_API_KEY env var)")
+	root.PersistentFlags().String("base-url", "", "OpenRouter API base URL, such as a `tenthman mockserver` (overrides OPENROUTER_BASE_URL env var)")
+	root.PersistentFlags().String("record", "", "Record every OpenRouter request and response to this cassette file")
+	root.PersistentFlags().String("replay", "", "Answer OpenRouter requests from this cassette file instead of the network")
 	root.PersistentFlags().String("output-dir", "output", "Output directory for results")
+	root.PersistentFlags().String("layout", "flat", "Output directory layout: flat, nested (by month) or a template such as {date}/{slug} or {project}/{slug}-{seq}")
+	root.PersistentFlags().String("project", "", "Project tag recorded in each run's transcript and used by {project} in --layout")
 	root.PersistentFlags().Int("agents", 9, "Number of debate agents (minimum 3)")
 	root.PersistentFlags().Int("min-rounds", 5, "Minimum debate rounds before consensus check")
 	root.PersistentFlags().Int("max-rounds", 15, "Maximum debate rounds")
+	root.PersistentFlags().String("upload", "", "Upload each finished run directory to s3://bucket/prefix or gs://bucket/prefix")
+	root.PersistentFlags().StringArray("encrypt-to", nil, "Encrypt every finished artifact to this age (age1..., ssh-...) or GPG recipient (repeatable)")
+	root.MarkPersistentFlagDirname("output-dir")
+	root.RegisterFlagCompletionFunc("layout", completeValues(output.LayoutFlat, output.LayoutNested, "{date}/{slug}", "{project}/{slug}-{seq}"))
+	root.RegisterFlagCompletionFunc("upload", completePrefixes("s3://", "gs://"))
 
 	root.AddCommand(newDebateCmd())
+	root.AddCommand(newBatchCmd())
+	root.AddCommand(newServeCmd())
+	root.AddCommand(newTemplatesCmd())
 	root.AddCommand(newResearchCmd())
 	root.AddCommand(newAnalyzeCmd())
+	root.AddCommand(newSearchCmd())
+	root.AddCommand(newStatsCmd())
+	root.AddCommand(newExportCmd())
+	root.AddCommand(newNarrateCmd())
+	root.AddCommand(newModelsCmd())
+	root.AddCommand(newOutputCmd())
+	root.AddCommand(newRunCmd())
+	root.AddCommand(newAskCmd())
+	root.AddCommand(newDoctorCmd())
+	root.AddCommand(newMockServerCmd())
+	root.AddCommand(newEvalCmd())
+	root.AddCommand(newDocsCmd())
+	root.AddCommand(newInitCmd())
 
 	if err := root.Execute(); err != nil {
+		var exit exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		fmt.Fprintln(os.Stderr, err)
+		if hint := errorHint(err); hint != "" {
+			fmt.Fprintln(os.Stderr, hint)
+		}
 		os.Exit(1)
 	}
 }
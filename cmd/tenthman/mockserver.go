@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/mockserver"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+func newMockServerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mockserver",
+		Short: "Serve a fake OpenRouter API for running debates offline",
+		Long:  "Serves the OpenRouter endpoints tenthman uses with synthesized replies, or replays the turns of a saved run. Point other commands at it with --base-url and any --api-key.",
+		RunE:  runMockServer,
+	}
+	cmd.Flags().String("addr", "127.0.0.1:8089", "HTTP listen address")
+	cmd.Flags().String("replay-run", "", "Run directory whose transcript the agents replay")
+	cmd.Flags().Int("consensus-after", 15, "Turns after which the judge detects a consensus")
+	cmd.MarkFlagDirname("replay-run")
+	return cmd
+}
+
+func runMockServer(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	replay, _ := cmd.Flags().GetString("replay-run")
+	consensusAfter, _ := cmd.Flags().GetInt("consensus-after")
+
+	mock := mockserver.New()
+	mock.SetConsensusAfter(consensusAfter)
+	if replay != "" {
+		transcript, err := runs.LoadTranscript(replay)
+		if err != nil {
+			return err
+		}
+		mock.Replay(transcript)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	srv := &http.Server{Addr: addr, Handler: mock}
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	fmt.Printf("Mock OpenRouter API on http://%s/api/v1\n", addr)
+	fmt.Printf("Try: tenthman debate --base-url http://%s/api/v1 --api-key mock --topic \"...\"\n", addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("mockserver: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+func newModelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "Compare models across saved debate runs",
+	}
+	cmd.PersistentFlags().String("dir", "", "Directory of saved runs (default: --output-dir)")
+	cmd.MarkPersistentFlagDirname("dir")
+	cmd.AddCommand(newModelsLeaderboardCmd())
+	return cmd
+}
+
+func newModelsLeaderboardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "leaderboard",
+		Short: "Rank models by argument quality, reliability and judging",
+		Long: `Ranks every model in the saved runs by:
+
+  quality      share of its turns other agents replied to
+  reliability  share of its turns that were substantive answers
+  judge        share of its consensus verdicts that were valid
+  tenth-man    share of the consensuses it challenged that were revised or overturned`,
+		Args: cobra.NoArgs,
+		RunE: runModelsLeaderboard,
+	}
+	cmd.Flags().String("sort", "quality", "Sort order: quality, reliability, judge or tenth-man")
+	cmd.Flags().Int("min-turns", 1, "Leave out models with fewer debate turns (0 keeps judge-only models)")
+	cmd.RegisterFlagCompletionFunc("sort", completeValues("quality", "reliability", "judge", "tenth-man"))
+	return cmd
+}
+
+func runModelsLeaderboard(cmd *cobra.Command, args []string) error {
+	order, _ := cmd.Flags().GetString("sort")
+	minTurns, _ := cmd.Flags().GetInt("min-turns")
+
+	board, err := runs.Leaderboard(runsDir(cmd))
+	if err != nil {
+		return err
+	}
+	switch order {
+	case "quality":
+	case "reliability":
+		sort.SliceStable(board, func(i, j int) bool { return board[i].Reliability() > board[j].Reliability() })
+	case "judge":
+		sort.SliceStable(board, func(i, j int) bool {
+			if (board[i].Judged > 0) != (board[j].Judged > 0) {
+				return board[i].Judged > 0
+			}
+			return board[i].JudgeAccuracy() > board[j].JudgeAccuracy()
+		})
+	case "tenth-man":
+		sort.SliceStable(board, func(i, j int) bool {
+			if (board[i].Challenges > 0) != (board[j].Challenges > 0) {
+				return board[i].Challenges > 0
+			}
+			return movedShare(board[i]) > movedShare(board[j])
+		})
+	default:
+		return fmt.Errorf("models: unknown sort order %q (want quality, reliability, judge or tenth-man)", order)
+	}
+
+	fmt.Println(output.Bold(fmt.Sprintf("%-4s %-45s %7s %5s %7s %11s %9s %9s", "RANK", "MODEL", "DEBATES", "TURNS", "QUALITY", "RELIABILITY", "JUDGE", "TENTH MAN")))
+	rank := 0
+	for _, m := range board {
+		if m.Turns < minTurns {
+			continue
+		}
+		rank++
+		quality, reliability := "-", "-"
+		if m.Turns > 0 {
+			quality = percent(m.Quality())
+			reliability = percent(m.Reliability())
+		}
+		judge, tenthMan := "-", "-"
+		if m.Verdicts+m.Rejected > 0 {
+			judge = percent(m.JudgeAccuracy())
+		}
+		if m.Challenges > 0 {
+			tenthMan = fmt.Sprintf("%d/%d", m.Moved, m.Challenges)
+		}
+		fmt.Printf("%-4d %-45s %7d %5d %7s %11s %9s %9s\n", rank, m.Model, m.Debates, m.Turns, quality, reliability, judge, tenthMan)
+	}
+	if rank == 0 {
+		fmt.Printf("No models with at least %d turn(s) in %s\n", minTurns, runsDir(cmd))
+	}
+	return nil
+}
+
+func movedShare(m runs.ModelScore) float64 {
+	if m.Challenges == 0 {
+		return 0
+	}
+	return float64(m.Moved) / float64(m.Challenges)
+}
+
+func percent(f float64) string { return fmt.Sprintf("%.0f%%", 100*f) }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/narration"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+func newNarrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "narrate <run-dir>",
+		Short: "Render a saved debate as a multi-voice audio file",
+		Long: `Narrate reads a saved debate aloud with a text-to-speech backend: a
+narrator introduces the topic, rounds and the Tenth Man's challenge, and each
+agent speaks its turns in its own voice. The result is a single WAV file.
+
+--tts picks the backend (default: $TTS_BACKEND):
+  https://api.openai.com/v1         an OpenAI-compatible speech API; the model
+                                    comes from $TTS_MODEL (default tts-1) and
+                                    the key from $TTS_API_KEY
+  exec:espeak-ng -v {voice} --stdout  a local program reading text on stdin
+                                    and writing WAV to stdout`,
+		Args: cobra.ExactArgs(1),
+		RunE: runNarrate,
+	}
+	cmd.Flags().String("tts", "", "Text-to-speech backend: an OpenAI-compatible base URL or exec:<command> (default: $TTS_BACKEND)")
+	cmd.Flags().StringSlice("voices", []string{"onyx", "alloy", "echo", "fable", "nova", "shimmer"}, "Voices to use; the first narrates and agents take the rest in turn")
+	cmd.Flags().String("out", "", "Audio file to write (default: <run-dir>/debate.wav)")
+	cmd.RegisterFlagCompletionFunc("tts", completePrefixes("https://", "http://", "exec:"))
+	cmd.RegisterFlagCompletionFunc("voices", completeValues("onyx", "alloy", "echo", "fable", "nova", "shimmer"))
+	cmd.MarkFlagFilename("out", "wav")
+	return cmd
+}
+
+func runNarrate(cmd *cobra.Command, args []string) error {
+	backend, _ := cmd.Flags().GetString("tts")
+	voices, _ := cmd.Flags().GetStringSlice("voices")
+	dest, _ := cmd.Flags().GetString("out")
+	if backend == "" {
+		backend = os.Getenv("TTS_BACKEND")
+	}
+	if backend == "" {
+		return fmt.Errorf("narrate: no TTS backend; set --tts or TTS_BACKEND")
+	}
+	if len(voices) == 0 {
+		return fmt.Errorf("narrate: --voices is empty")
+	}
+	if dest == "" {
+		dest = filepath.Join(args[0], "debate.wav")
+	}
+
+	transcript, err := runs.LoadTranscript(args[0])
+	if err != nil {
+		return err
+	}
+	if len(transcript.Turns) == 0 {
+		return fmt.Errorf("narrate: %s has no turns", args[0])
+	}
+	synth, err := narration.NewSynthesizer(backend)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("narrate: %w", err)
+	}
+	if err := narration.NewNarrator(synth, voices).Narrate(ctx, f, transcript); err != nil {
+		f.Close()
+		os.Remove(dest)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("narrate: %w", err)
+	}
+	fmt.Printf("Narrated %d turns to %s\n", len(transcript.Turns), dest)
+	return nil
+}
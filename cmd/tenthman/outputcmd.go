@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+func newOutputCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "output",
+		Short: "Inspect and clean up saved debate runs",
+	}
+	cmd.PersistentFlags().String("dir", "", "Directory of saved runs (default: --output-dir)")
+	cmd.MarkPersistentFlagDirname("dir")
+	cmd.AddCommand(newOutputListCmd(), newOutputPruneCmd(), newOutputArchiveCmd())
+	return cmd
+}
+
+func newOutputListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved runs by date, topic and size",
+		RunE:  runOutputList,
+	}
+	cmd.Flags().String("sort", "date", "Sort order: date, size or topic")
+	cmd.RegisterFlagCompletionFunc("sort", completeValues("date", "size", "topic"))
+	return cmd
+}
+
+func newOutputPruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete runs older than a number of days or beyond a size quota",
+		RunE:  runOutputPrune,
+	}
+	cmd.Flags().Int("older-than", 0, "Delete runs older than this many days")
+	cmd.Flags().String("max-size", "", "Keep only the newest runs fitting in this total size, e.g. 500MB")
+	cmd.Flags().Bool("dry-run", false, "Show what would be deleted without deleting")
+	return cmd
+}
+
+func newOutputArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive <run-dir>",
+		Short: "Pack a run directory into a single .tar.gz for sharing",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := filepath.Clean(args[0])
+			dest, _ := cmd.Flags().GetString("out")
+			if dest == "" {
+				dest = dir + ".tar.gz"
+			}
+			var names *runs.Pseudonyms
+			if anonymize, _ := cmd.Flags().GetBool("anonymize"); anonymize {
+				names = runs.NewPseudonyms()
+			}
+			if err := runs.Archive(dir, dest, names); err != nil {
+				return err
+			}
+			fmt.Printf("Archived %s to %s\n", dir, dest)
+			return nil
+		},
+	}
+	cmd.Flags().String("out", "", "Archive path (default: <run-dir>.tar.gz)")
+	cmd.Flags().Bool("anonymize", false, "Replace agent names and model IDs with stable pseudonyms in every text artifact, leaving out files that cannot be rewritten such as audio")
+	return cmd
+}
+
+// runsDir returns the --dir flag, falling back to the root --output-dir.
+func runsDir(cmd *cobra.Command) string {
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		dir, _ = cmd.Root().PersistentFlags().GetString("output-dir")
+	}
+	return dir
+}
+
+func runOutputList(cmd *cobra.Command, args []string) error {
+	order, _ := cmd.Flags().GetString("sort")
+	list, warnings, err := runs.Scan(runsDir(cmd))
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %v (skipped)\n", w)
+	}
+	switch order {
+	case "date":
+	case "size":
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Size > list[j].Size })
+	case "topic":
+		sort.SliceStable(list, func(i, j int) bool { return strings.ToLower(list[i].Topic) < strings.ToLower(list[j].Topic) })
+	default:
+		return fmt.Errorf("output: unknown sort order %q (want date, size or topic)", order)
+	}
+
+	var total int64
+	for _, r := range list {
+		total += r.Size
+		topic := r.Topic
+		if r.Encrypted {
+			topic = "(encrypted)"
+		}
+		if r.Project != "" {
+			topic = "[" + r.Project + "] " + topic
+		}
+		fmt.Printf("%s  %9s  %3d rounds  %s\n    %s\n", r.Created.Format("2006-01-02 15:04"), runs.FormatSize(r.Size), r.Rounds, topic, r.Dir)
+	}
+	fmt.Printf("%d run(s), %s total\n", len(list), runs.FormatSize(total))
+	return nil
+}
+
+func runOutputPrune(cmd *cobra.Command, args []string) error {
+	days, _ := cmd.Flags().GetInt("older-than")
+	maxSize, _ := cmd.Flags().GetString("max-size")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	var policy runs.PrunePolicy
+	if days > 0 {
+		policy.OlderThan = time.Duration(days) * 24 * time.Hour
+	}
+	if maxSize != "" {
+		n, err := runs.ParseSize(maxSize)
+		if err != nil {
+			return err
+		}
+		policy.MaxSize = n
+	}
+	if policy.OlderThan == 0 && policy.MaxSize == 0 {
+		return fmt.Errorf("output: set --older-than and/or --max-size")
+	}
+
+	list, warnings, err := runs.Scan(runsDir(cmd))
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %v (skipped)\n", w)
+	}
+	prune := runs.SelectPrunable(list, policy, time.Now())
+	var freed int64
+	for _, r := range prune {
+		if dryRun {
+			fmt.Printf("would delete %s (%s)\n", r.Dir, runs.FormatSize(r.Size))
+		} else {
+			if err := runs.Remove(r); err != nil {
+				return err
+			}
+			fmt.Printf("deleted %s (%s)\n", r.Dir, runs.FormatSize(r.Size))
+		}
+		freed += r.Size
+	}
+	verb := "Deleted"
+	if dryRun {
+		verb = "Would delete"
+	}
+	fmt.Printf("%s %d of %d run(s), freeing %s\n", verb, len(prune), len(list), runs.FormatSize(freed))
+	return nil
+}
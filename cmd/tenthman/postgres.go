@@ -0,0 +1,7 @@
+//go:build postgres
+
+package main
+
+// Registers the "pgx" database/sql driver used by the postgres transcript
+// store in serve mode.
+import _ "github.com/jackc/pgx/v5/stdlib"
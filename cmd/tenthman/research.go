@@ -1,18 +1,102 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/research"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
 	"github.com/spf13/cobra"
 )
 
 func newResearchCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "research",
 		Short: "Deep investigation with contrarian stress-testing",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Research mode coming soon.")
-			return nil
+		Long:  "Runs a debate in which agents may request evidence with \"EVIDENCE NEEDED: <query>\" lines. Requests are answered from local source documents and shared with every agent in the next round.",
+		RunE:  runResearch,
+	}
+	cmd.Flags().String("topic", "", "Research question (required)")
+	cmd.Flags().String("name", "", "Override output folder name (default: auto-slug from topic)")
+	cmd.Flags().StringSlice("sources", nil, "Files or directories of .md/.txt documents to search for evidence (required)")
+	cmd.Flags().Int("evidence-budget", 10, "Maximum number of evidence queries per run")
+	cmd.Flags().Bool("redact", false, "Mask emails, API keys, tokens and internal hostnames in retrieved evidence and every saved artifact")
+	cmd.Flags().StringArray("redact-pattern", nil, "Extra regular expression to mask, optionally named as name=regex; implies --redact (repeatable)")
+	cmd.Flags().Bool("fact-check", false, "Verify the factual claims of the final consensus position against the sources, flagging unverifiable ones in the report")
+	cmd.Flags().String("fact-check-model", "", "Model for --fact-check (default: the judge's model)")
+	cmd.RegisterFlagCompletionFunc("fact-check-model", completeModels)
+	cmd.MarkFlagRequired("topic")
+	cmd.MarkFlagRequired("sources")
+	return cmd
+}
+
+func runResearch(cmd *cobra.Command, args []string) error {
+	topic, _ := cmd.Flags().GetString("topic")
+	name, _ := cmd.Flags().GetString("name")
+	sources, _ := cmd.Flags().GetStringSlice("sources")
+	budget, _ := cmd.Flags().GetInt("evidence-budget")
+	outputDir, _ := cmd.Root().PersistentFlags().GetString("output-dir")
+
+	retriever, err := research.NewLocalRetriever(sources...)
+	if err != nil {
+		return err
+	}
+
+	job := jobFromFlags(cmd)
+	job.Topic = topic
+	job.Name = name
+	job.Retriever = retriever
+	job.EvidenceBudget = budget
+	job.FactCheck, _ = cmd.Flags().GetBool("fact-check")
+	job.Redact, _ = cmd.Flags().GetBool("redact")
+	job.RedactPatterns, _ = cmd.Flags().GetStringArray("redact-pattern")
+	job.FactCheckModel, _ = cmd.Flags().GetString("fact-check-model")
+	if err := job.Validate(); err != nil {
+		return err
+	}
+
+	apiKey, err := resolveAPIKey(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := newClient(cmd, apiKey)
+	if err != nil {
+		return err
+	}
+	client.SetMaxTokens(500)
+	client.SetAdaptivePacing(maxPace)
+	registry := loadRegistry(ctx, client)
+
+	outcome, err := runner.Run(ctx, client, registry, outputDir, job, runner.Hooks{
+		OnStart: func(dir string) {
+			fmt.Printf("%s %s\n", output.Bold("Research:"), output.Colorize(output.AnsiMagenta, topic))
+			fmt.Printf("Agents: %d | Rounds: %d-%d | Evidence budget: %d | Output: %s\n\n", job.Agents, job.MinRounds, job.MaxRounds, budget, dir)
+		},
+		OnTurn:  output.PrintTurn,
+		OnPhase: output.PrintPhase,
+		OnEvidence: func(ev debate.Evidence) {
+			fmt.Printf("%s %s (requested by %s)\n", output.Bold("Evidence:"), ev.Query, ev.RequestedBy)
 		},
+	})
+	if err != nil {
+		return fmt.Errorf("research: %w", err)
+	}
+
+	output.PrintConsensus(outcome.Consensus)
+	output.PrintOutcome(outcome.Result.Verdict())
+	output.PrintPositionChange(outcome.Result.Transcript.PositionChange)
+	output.PrintMinorityReports(outcome.Result.MinorityReports)
+	fmt.Printf("\nResearch complete (%d evidence queries). Output saved to: %s\n", len(outcome.Result.Transcript.Evidence), outcome.Dir)
+	if outcome.Uploaded != "" {
+		fmt.Printf("Uploaded to: %s\n", outcome.Uploaded)
 	}
+	return nil
 }
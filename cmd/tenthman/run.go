@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes for `tenthman run`. Any other failure exits 1.
+const (
+	exitUpheld      = 0
+	exitOverturned  = 2
+	exitNoConsensus = 3
+	exitRevised     = 4
+)
+
+// runResult is the JSON document `tenthman run` writes to stdout.
+type runResult struct {
+	Verdict         debate.Verdict          `json:"verdict,omitempty"`
+	Topic           string                  `json:"topic,omitempty"`
+	Dir             string                  `json:"dir,omitempty"`
+	Uploaded        string                  `json:"uploaded,omitempty"`
+	Rounds          int                     `json:"rounds,omitempty"`
+	Stagnated       bool                    `json:"stagnated,omitempty"`
+	Settled         string                  `json:"settled,omitempty"`
+	Partial         bool                    `json:"partial,omitempty"`
+	Consensus       *debate.ConsensusResult `json:"consensus,omitempty"`
+	PositionChange  *positionChange         `json:"position_change,omitempty"`
+	MinorityReports []minorityReport        `json:"minority_reports,omitempty"`
+	Claims          []debate.Claim          `json:"claims,omitempty"`
+	Actions         *debate.ActionItems     `json:"actions,omitempty"`
+	Error           string                  `json:"error,omitempty"`
+	ErrorKind       string                  `json:"error_kind,omitempty"` // rate_limited, model_unavailable, consensus_parse or budget_exceeded
+}
+
+type positionChange struct {
+	Changed bool   `json:"changed"`
+	Summary string `json:"summary,omitempty"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
+}
+
+type minorityReport struct {
+	Agent      string `json:"agent"`
+	Objections string `json:"objections"`
+}
+
+// exitError makes main exit with code without printing anything further.
+type exitError struct{ code int }
+
+func (e exitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+
+func newRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run",
+		Short: "Run a job read as JSON from stdin and print the result as JSON",
+		Long: `Reads one job (same fields as a batch job, e.g. {"topic": "...", "agents": 5}) from stdin,
+runs it and writes a single JSON result to stdout. Nothing else is written to stdout.
+
+Exit codes: 0 consensus upheld, 2 consensus overturned by the Tenth Man,
+3 no consensus reached, 4 consensus upheld with a position the Tenth Man
+revised, 1 any error (the result JSON then carries "error").`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runJSON,
+	}
+}
+
+func runJSON(cmd *cobra.Command, args []string) error {
+	res, err := runJob(cmd)
+	if err != nil {
+		res.Error = err.Error()
+		res.ErrorKind = errorKind(err)
+	}
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	if encErr := enc.Encode(res); encErr != nil {
+		return encErr
+	}
+	if err != nil {
+		return exitError{1}
+	}
+	switch res.Verdict {
+	case debate.VerdictOverturned:
+		return exitError{exitOverturned}
+	case debate.VerdictNoConsensus:
+		return exitError{exitNoConsensus}
+	case debate.VerdictRevised:
+		return exitError{exitRevised}
+	}
+	return nil
+}
+
+func runJob(cmd *cobra.Command) (runResult, error) {
+	var job runner.Job
+	dec := json.NewDecoder(cmd.InOrStdin())
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&job); err != nil {
+		return runResult{}, fmt.Errorf("run: reading job from stdin: %w", err)
+	}
+	job = job.WithDefaults(jobFromFlags(cmd))
+	if err := job.Validate(); err != nil {
+		return runResult{Topic: job.Topic}, err
+	}
+
+	apiKey, err := resolveAPIKey(cmd)
+	if err != nil {
+		return runResult{Topic: job.Topic}, err
+	}
+	outputDir, _ := cmd.Root().PersistentFlags().GetString("output-dir")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := newClient(cmd, apiKey)
+	if err != nil {
+		return runResult{Topic: job.Topic}, err
+	}
+	client.SetMaxTokens(500)
+	client.SetAdaptivePacing(maxPace)
+	registry := loadRegistry(ctx, client)
+
+	outcome, err := runner.Run(ctx, client, registry, outputDir, job, runner.Hooks{})
+	res := runResult{Topic: job.Topic}
+	if outcome != nil {
+		res.Dir = outcome.Dir
+		res.Uploaded = outcome.Uploaded
+		res.Claims = outcome.Claims
+		res.Actions = outcome.Actions
+		if outcome.Result != nil {
+			res.Verdict = outcome.Result.Verdict()
+			res.Rounds = outcome.Result.Transcript.Rounds
+			res.Stagnated = outcome.Result.Stagnated
+			res.Settled = outcome.Result.Settled
+			res.Partial = outcome.Result.Partial
+			res.Consensus = outcome.Consensus
+			if c := outcome.Result.Transcript.PositionChange; c != nil {
+				res.PositionChange = &positionChange{Changed: c.Changed, Summary: c.Summary, Before: c.Before, After: c.After}
+			}
+			for _, r := range outcome.Result.MinorityReports {
+				res.MinorityReports = append(res.MinorityReports, minorityReport{Agent: r.Agent.Name, Objections: r.Objections})
+			}
+		}
+	}
+	return res, err
+}
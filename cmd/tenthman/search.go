@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+func newSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search saved debate transcripts",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSearch,
+	}
+	cmd.Flags().String("dir", "", "Directory of saved runs (default: --output-dir)")
+	cmd.Flags().Int("limit", 50, "Maximum matches to show (0 for all)")
+	cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		dir, _ = cmd.Root().PersistentFlags().GetString("output-dir")
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	matches, err := runs.Search(dir, args[0], limit)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		fmt.Printf("No matches for %q in %s\n", args[0], dir)
+		return nil
+	}
+	for _, m := range matches {
+		fmt.Printf("%s %s\n", output.Bold(m.Run.Topic), output.Colorize(output.AnsiMagenta, m.Run.Dir))
+		fmt.Printf("  [Round %d] %s (%s), %s\n", m.Turn.Round, m.Turn.Agent.Name, m.Turn.Agent.Model, m.Run.Created.Format("2006-01-02 15:04"))
+		fmt.Printf("  %s\n\n", m.Snippet)
+	}
+	fmt.Printf("%d match(es)\n", len(matches))
+	return nil
+}
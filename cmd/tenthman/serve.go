@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/health"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// healthProbeTTL is how long the readiness probe reuses an OpenRouter check.
+const healthProbeTTL = 30 * time.Second
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the HTTP API and scheduled debates",
+		RunE:  runServe,
+	}
+	cmd.Flags().String("addr", ":8080", "HTTP listen address")
+	cmd.Flags().String("grpc-addr", "", "gRPC listen address, e.g. :9090 (empty serves HTTP only)")
+	cmd.Flags().String("config", "", "Serve config file with schedules and notifications (YAML)")
+	cmd.Flags().Int("rpm", 20, "Requests per minute shared across all debates (0 disables limiting)")
+	cmd.Flags().Int("workers", 2, "Debates run at the same time; others wait in the queue (0 runs all at once)")
+	cmd.Flags().Int("queue-size", 50, "Debates that may wait for a worker before new ones are rejected")
+	cmd.Flags().Bool("resume", false, "Resume debates interrupted by the last shutdown on startup (needs a store)")
+	cmd.Flags().Bool("pprof", false, "Serve Go runtime profiles under /debug/pprof/")
+	cmd.MarkFlagFilename("config", "yaml", "yml")
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	grpcAddr, _ := cmd.Flags().GetString("grpc-addr")
+	configPath, _ := cmd.Flags().GetString("config")
+	rpm, _ := cmd.Flags().GetInt("rpm")
+	workers, _ := cmd.Flags().GetInt("workers")
+	queueSize, _ := cmd.Flags().GetInt("queue-size")
+	resume, _ := cmd.Flags().GetBool("resume")
+	profiling, _ := cmd.Flags().GetBool("pprof")
+	outputDir, _ := cmd.Root().PersistentFlags().GetString("output-dir")
+
+	cfg := &server.Config{}
+	if configPath != "" {
+		var err error
+		if cfg, err = server.LoadConfig(configPath); err != nil {
+			return err
+		}
+	}
+	defaults := jobFromFlags(cmd)
+	for i := range cfg.Schedules {
+		cfg.Schedules[i].Job = cfg.Schedules[i].Job.WithDefaults(defaults)
+		if err := cfg.Schedules[i].Job.Validate(); err != nil {
+			return fmt.Errorf("schedule %q: %w", cfg.Schedules[i].Job.Name, err)
+		}
+	}
+
+	notifiers, err := cfg.Notify.Notifiers()
+	if err != nil {
+		return err
+	}
+
+	apiKey, err := resolveAPIKey(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	transcripts, err := cfg.Store.Open(ctx)
+	if err != nil {
+		return err
+	}
+	if closer, ok := transcripts.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	client, err := newClient(cmd, apiKey)
+	if err != nil {
+		return err
+	}
+	client.SetMaxTokens(500)
+	client.SetAdaptivePacing(maxPace)
+	client.SetRateLimit(rpm)
+	registry := loadRegistry(ctx, client)
+
+	srv := server.New(func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		return runner.Run(ctx, client, registry, outputDir, job, runner.Hooks{})
+	}, notifiers...)
+	srv.SetDefaults(defaults)
+	srv.SetWorkers(workers, queueSize)
+	srv.SetTenants(cfg.Tenants)
+	srv.SetProfiling(profiling)
+	srv.SetGRPCAddr(grpcAddr)
+	srv.SetHealthChecks(health.Check{Name: "openrouter", Run: health.Cached(healthProbeTTL, client.Ping)})
+	if transcripts != nil {
+		srv.SetStore(transcripts)
+		srv.SetRetention(cfg.Store.Retention)
+		srv.SetAutoResume(resume)
+	}
+
+	fmt.Printf("Serving on %s (%d schedules)\n", addr, len(cfg.Schedules))
+	if grpcAddr != "" {
+		fmt.Printf("Serving gRPC on %s\n", grpcAddr)
+	}
+	return srv.Serve(ctx, addr, cfg.Schedules)
+}
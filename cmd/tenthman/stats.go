@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize saved debates: consensus rate, Tenth Man outcomes, models and tokens",
+		Args:  cobra.NoArgs,
+		RunE:  runStats,
+	}
+	cmd.Flags().String("dir", "", "Directory of saved runs (default: --output-dir)")
+	cmd.Flags().Int("top", 5, "How many of the most-used models to show (0 for all)")
+	cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	dir := runsDir(cmd)
+	top, _ := cmd.Flags().GetInt("top")
+
+	stats, err := runs.Summarize(dir)
+	if err != nil {
+		return err
+	}
+	if stats.Debates == 0 {
+		fmt.Printf("No saved runs in %s\n", dir)
+		return nil
+	}
+
+	fmt.Printf("%s %d\n", output.Bold("Debates:"), stats.Debates)
+	fmt.Printf("%s %d (%.0f%%)", output.Bold("Consensus reached:"), stats.Consensus, 100*stats.ConsensusRate())
+	if stats.Consensus > 0 {
+		fmt.Printf(", after %.1f rounds on average", stats.RoundsToConsensus)
+	}
+	fmt.Println()
+
+	upheld, revised, overturned := stats.Outcomes[debate.VerdictUpheld], stats.Outcomes[debate.VerdictRevised], stats.Outcomes[debate.VerdictOverturned]
+	if challenged := upheld + revised + overturned; challenged > 0 {
+		share := func(n int) float64 { return 100 * float64(n) / float64(challenged) }
+		fmt.Printf("%s of %d challenged consensus(es): %d upheld (%.0f%%), %d revised (%.0f%%), %d overturned (%.0f%%)\n",
+			output.Bold("Tenth Man:"), challenged, upheld, share(upheld), revised, share(revised), overturned, share(overturned))
+	}
+
+	models := stats.Models
+	if top > 0 && len(models) > top {
+		models = models[:top]
+	}
+	if len(models) > 0 {
+		fmt.Println(output.Bold("Most-used models:"))
+		for _, m := range models {
+			fmt.Printf("  %-50s %4d turns in %d debate(s)\n", m.Model, m.Turns, m.Debates)
+		}
+	}
+
+	if stats.Metered > 0 {
+		fmt.Printf("%s %d across %d run(s), %d per run on average\n", output.Bold("Tokens:"), stats.Tokens, stats.Metered, stats.Tokens/stats.Metered)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+func newTemplatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "List available debate scenario templates",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dirs, _ := cmd.Flags().GetStringSlice("template-dir")
+			list, err := templates.List(append(dirs, templates.UserDirs()...))
+			if err != nil {
+				return err
+			}
+			for _, t := range list {
+				fmt.Printf("%s  %s\n", output.Bold(t.Name), t.Description)
+				fmt.Printf("    agents: %d | rounds: %d-%d | source: %s\n", t.Job.Agents, t.Job.MinRounds, t.Job.MaxRounds, t.Source)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSlice("template-dir", nil, "Extra directories to search for templates (default: user config dir)")
+	cmd.MarkFlagDirname("template-dir")
+	return cmd
+}
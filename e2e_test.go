@@ -83,7 +83,7 @@ func TestE2EFullDebateWithMockServer(t *testing.T) {
 	// Setup output
 	outDir := t.TempDir()
 	slug := output.GenerateSlug("Space exploration investment")
-	dir, err := output.CreateOutputDir(outDir, slug)
+	dir, err := output.CreateOutputDir(outDir, slug, output.LayoutFlat, "")
 	if err != nil {
 		t.Fatalf("CreateOutputDir: %v", err)
 	}
@@ -115,7 +115,7 @@ func TestE2EFullDebateWithMockServer(t *testing.T) {
 	if consensusResult == nil {
 		consensusResult = &debate.ConsensusResult{}
 	}
-	if err := writer.WriteMarkdown(result.Transcript, consensusResult); err != nil {
+	if err := writer.WriteMarkdown(result.Transcript, consensusResult, result.MinorityReports); err != nil {
 		t.Fatalf("WriteMarkdown: %v", err)
 	}
 	if err := writer.WriteLog(); err != nil {
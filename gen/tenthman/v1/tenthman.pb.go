@@ -0,0 +1,1507 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: tenthman/v1/tenthman.proto
+
+package tenthmanv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED Status = 0
+	Status_STATUS_RUNNING     Status = 1
+	Status_STATUS_COMPLETED   Status = 2
+	Status_STATUS_FAILED      Status = 3
+	Status_STATUS_QUEUED      Status = 4
+	Status_STATUS_INTERRUPTED Status = 5
+	Status_STATUS_CANCELLING  Status = 6
+	Status_STATUS_CANCELLED   Status = 7
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_RUNNING",
+		2: "STATUS_COMPLETED",
+		3: "STATUS_FAILED",
+		4: "STATUS_QUEUED",
+		5: "STATUS_INTERRUPTED",
+		6: "STATUS_CANCELLING",
+		7: "STATUS_CANCELLED",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_RUNNING":     1,
+		"STATUS_COMPLETED":   2,
+		"STATUS_FAILED":      3,
+		"STATUS_QUEUED":      4,
+		"STATUS_INTERRUPTED": 5,
+		"STATUS_CANCELLING":  6,
+		"STATUS_CANCELLED":   7,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_tenthman_v1_tenthman_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_tenthman_v1_tenthman_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{0}
+}
+
+type Phase int32
+
+const (
+	Phase_PHASE_UNSPECIFIED Phase = 0
+	Phase_PHASE_FREE_DEBATE Phase = 1
+	Phase_PHASE_TENTH_MAN   Phase = 2
+)
+
+// Enum value maps for Phase.
+var (
+	Phase_name = map[int32]string{
+		0: "PHASE_UNSPECIFIED",
+		1: "PHASE_FREE_DEBATE",
+		2: "PHASE_TENTH_MAN",
+	}
+	Phase_value = map[string]int32{
+		"PHASE_UNSPECIFIED": 0,
+		"PHASE_FREE_DEBATE": 1,
+		"PHASE_TENTH_MAN":   2,
+	}
+)
+
+func (x Phase) Enum() *Phase {
+	p := new(Phase)
+	*p = x
+	return p
+}
+
+func (x Phase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Phase) Descriptor() protoreflect.EnumDescriptor {
+	return file_tenthman_v1_tenthman_proto_enumTypes[1].Descriptor()
+}
+
+func (Phase) Type() protoreflect.EnumType {
+	return &file_tenthman_v1_tenthman_proto_enumTypes[1]
+}
+
+func (x Phase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Phase.Descriptor instead.
+func (Phase) EnumDescriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{1}
+}
+
+// Job mirrors runner.Job.
+type Job struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Topic            string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Agents           int32                  `protobuf:"varint,3,opt,name=agents,proto3" json:"agents,omitempty"`
+	MinRounds        int32                  `protobuf:"varint,4,opt,name=min_rounds,json=minRounds,proto3" json:"min_rounds,omitempty"`
+	MaxRounds        int32                  `protobuf:"varint,5,opt,name=max_rounds,json=maxRounds,proto3" json:"max_rounds,omitempty"`
+	TenthManRounds   int32                  `protobuf:"varint,6,opt,name=tenth_man_rounds,json=tenthManRounds,proto3" json:"tenth_man_rounds,omitempty"`
+	StagnationRounds int32                  `protobuf:"varint,7,opt,name=stagnation_rounds,json=stagnationRounds,proto3" json:"stagnation_rounds,omitempty"`
+	Instructions     string                 `protobuf:"bytes,8,opt,name=instructions,proto3" json:"instructions,omitempty"`
+	Experts          []string               `protobuf:"bytes,9,rep,name=experts,proto3" json:"experts,omitempty"`
+	Compress         string                 `protobuf:"bytes,10,opt,name=compress,proto3" json:"compress,omitempty"`
+	Upload           string                 `protobuf:"bytes,11,opt,name=upload,proto3" json:"upload,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Job) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *Job) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Job) GetAgents() int32 {
+	if x != nil {
+		return x.Agents
+	}
+	return 0
+}
+
+func (x *Job) GetMinRounds() int32 {
+	if x != nil {
+		return x.MinRounds
+	}
+	return 0
+}
+
+func (x *Job) GetMaxRounds() int32 {
+	if x != nil {
+		return x.MaxRounds
+	}
+	return 0
+}
+
+func (x *Job) GetTenthManRounds() int32 {
+	if x != nil {
+		return x.TenthManRounds
+	}
+	return 0
+}
+
+func (x *Job) GetStagnationRounds() int32 {
+	if x != nil {
+		return x.StagnationRounds
+	}
+	return 0
+}
+
+func (x *Job) GetInstructions() string {
+	if x != nil {
+		return x.Instructions
+	}
+	return ""
+}
+
+func (x *Job) GetExperts() []string {
+	if x != nil {
+		return x.Experts
+	}
+	return nil
+}
+
+func (x *Job) GetCompress() string {
+	if x != nil {
+		return x.Compress
+	}
+	return ""
+}
+
+func (x *Job) GetUpload() string {
+	if x != nil {
+		return x.Upload
+	}
+	return ""
+}
+
+type StartRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRunRequest) Reset() {
+	*x = StartRunRequest{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRunRequest) ProtoMessage() {}
+
+func (x *StartRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRunRequest.ProtoReflect.Descriptor instead.
+func (*StartRunRequest) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StartRunRequest) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type GetRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunRequest) Reset() {
+	*x = GetRunRequest{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunRequest) ProtoMessage() {}
+
+func (x *GetRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunRequest.ProtoReflect.Descriptor instead.
+func (*GetRunRequest) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRunRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListRunsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsRequest) Reset() {
+	*x = ListRunsRequest{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsRequest) ProtoMessage() {}
+
+func (x *ListRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListRunsRequest) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{3}
+}
+
+type ListRunsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Runs          []*Run                 `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsResponse) Reset() {
+	*x = ListRunsResponse{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsResponse) ProtoMessage() {}
+
+func (x *ListRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListRunsResponse) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListRunsResponse) GetRuns() []*Run {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+type InjectEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InjectEventRequest) Reset() {
+	*x = InjectEventRequest{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InjectEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InjectEventRequest) ProtoMessage() {}
+
+func (x *InjectEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InjectEventRequest.ProtoReflect.Descriptor instead.
+func (*InjectEventRequest) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *InjectEventRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *InjectEventRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type InjectEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InjectEventResponse) Reset() {
+	*x = InjectEventResponse{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InjectEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InjectEventResponse) ProtoMessage() {}
+
+func (x *InjectEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InjectEventResponse.ProtoReflect.Descriptor instead.
+func (*InjectEventResponse) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{6}
+}
+
+type StreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Consensus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Detected       bool                   `protobuf:"varint,1,opt,name=detected,proto3" json:"detected,omitempty"`
+	Position       string                 `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	AgreementScore int32                  `protobuf:"varint,3,opt,name=agreement_score,json=agreementScore,proto3" json:"agreement_score,omitempty"`
+	Dissenters     []string               `protobuf:"bytes,4,rep,name=dissenters,proto3" json:"dissenters,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Consensus) Reset() {
+	*x = Consensus{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Consensus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Consensus) ProtoMessage() {}
+
+func (x *Consensus) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Consensus.ProtoReflect.Descriptor instead.
+func (*Consensus) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Consensus) GetDetected() bool {
+	if x != nil {
+		return x.Detected
+	}
+	return false
+}
+
+func (x *Consensus) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *Consensus) GetAgreementScore() int32 {
+	if x != nil {
+		return x.AgreementScore
+	}
+	return 0
+}
+
+func (x *Consensus) GetDissenters() []string {
+	if x != nil {
+		return x.Dissenters
+	}
+	return nil
+}
+
+// Run mirrors server.Run.
+type Run struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Tenant        string                 `protobuf:"bytes,3,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Job           *Job                   `protobuf:"bytes,4,opt,name=job,proto3" json:"job,omitempty"`
+	Status        Status                 `protobuf:"varint,5,opt,name=status,proto3,enum=tenthman.v1.Status" json:"status,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Dir           string                 `protobuf:"bytes,8,opt,name=dir,proto3" json:"dir,omitempty"`
+	Rounds        int32                  `protobuf:"varint,9,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	Phase         Phase                  `protobuf:"varint,10,opt,name=phase,proto3,enum=tenthman.v1.Phase" json:"phase,omitempty"`
+	Consensus     *Consensus             `protobuf:"bytes,11,opt,name=consensus,proto3" json:"consensus,omitempty"`
+	Error         string                 `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
+	Tokens        int64                  `protobuf:"varint,13,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	Models        map[string]int32       `protobuf:"bytes,14,rep,name=models,proto3" json:"models,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Run) Reset() {
+	*x = Run{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Run) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Run) ProtoMessage() {}
+
+func (x *Run) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Run.ProtoReflect.Descriptor instead.
+func (*Run) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Run) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Run) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Run) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *Run) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *Run) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *Run) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Run) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *Run) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+func (x *Run) GetRounds() int32 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+func (x *Run) GetPhase() Phase {
+	if x != nil {
+		return x.Phase
+	}
+	return Phase_PHASE_UNSPECIFIED
+}
+
+func (x *Run) GetConsensus() *Consensus {
+	if x != nil {
+		return x.Consensus
+	}
+	return nil
+}
+
+func (x *Run) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Run) GetTokens() int64 {
+	if x != nil {
+		return x.Tokens
+	}
+	return 0
+}
+
+func (x *Run) GetModels() map[string]int32 {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+type Agent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Agent) Reset() {
+	*x = Agent{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Agent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Agent) ProtoMessage() {}
+
+func (x *Agent) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Agent.ProtoReflect.Descriptor instead.
+func (*Agent) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Agent) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Agent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Agent) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Agent) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type Turn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Round         int32                  `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Agent         *Agent                 `protobuf:"bytes,3,opt,name=agent,proto3" json:"agent,omitempty"`
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	InReplyTo     int32                  `protobuf:"varint,5,opt,name=in_reply_to,json=inReplyTo,proto3" json:"in_reply_to,omitempty"`
+	Confidence    *int32                 `protobuf:"varint,6,opt,name=confidence,proto3,oneof" json:"confidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Turn) Reset() {
+	*x = Turn{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Turn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Turn) ProtoMessage() {}
+
+func (x *Turn) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Turn.ProtoReflect.Descriptor instead.
+func (*Turn) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Turn) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Turn) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *Turn) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+func (x *Turn) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Turn) GetInReplyTo() int32 {
+	if x != nil {
+		return x.InReplyTo
+	}
+	return 0
+}
+
+func (x *Turn) GetConfidence() int32 {
+	if x != nil && x.Confidence != nil {
+		return *x.Confidence
+	}
+	return 0
+}
+
+// Event mirrors the debate engine's typed events.
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*Event_TurnCompleted
+	//	*Event_PhaseChanged
+	//	*Event_RoundStarted
+	//	*Event_RoundEnded
+	//	*Event_ConsensusEvaluated
+	//	*Event_TenthManActivated
+	//	*Event_AgentError
+	//	*Event_Done
+	Event         isEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Event) GetEvent() isEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Event) GetTurnCompleted() *Turn {
+	if x != nil {
+		if x, ok := x.Event.(*Event_TurnCompleted); ok {
+			return x.TurnCompleted
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetPhaseChanged() Phase {
+	if x != nil {
+		if x, ok := x.Event.(*Event_PhaseChanged); ok {
+			return x.PhaseChanged
+		}
+	}
+	return Phase_PHASE_UNSPECIFIED
+}
+
+func (x *Event) GetRoundStarted() *RoundSummary {
+	if x != nil {
+		if x, ok := x.Event.(*Event_RoundStarted); ok {
+			return x.RoundStarted
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetRoundEnded() *RoundSummary {
+	if x != nil {
+		if x, ok := x.Event.(*Event_RoundEnded); ok {
+			return x.RoundEnded
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetConsensusEvaluated() *Consensus {
+	if x != nil {
+		if x, ok := x.Event.(*Event_ConsensusEvaluated); ok {
+			return x.ConsensusEvaluated
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetTenthManActivated() *TenthManActivated {
+	if x != nil {
+		if x, ok := x.Event.(*Event_TenthManActivated); ok {
+			return x.TenthManActivated
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetAgentError() *AgentError {
+	if x != nil {
+		if x, ok := x.Event.(*Event_AgentError); ok {
+			return x.AgentError
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetDone() *Run {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Done); ok {
+			return x.Done
+		}
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_TurnCompleted struct {
+	TurnCompleted *Turn `protobuf:"bytes,1,opt,name=turn_completed,json=turnCompleted,proto3,oneof"`
+}
+
+type Event_PhaseChanged struct {
+	PhaseChanged Phase `protobuf:"varint,2,opt,name=phase_changed,json=phaseChanged,proto3,enum=tenthman.v1.Phase,oneof"`
+}
+
+type Event_RoundStarted struct {
+	RoundStarted *RoundSummary `protobuf:"bytes,3,opt,name=round_started,json=roundStarted,proto3,oneof"`
+}
+
+type Event_RoundEnded struct {
+	RoundEnded *RoundSummary `protobuf:"bytes,4,opt,name=round_ended,json=roundEnded,proto3,oneof"`
+}
+
+type Event_ConsensusEvaluated struct {
+	ConsensusEvaluated *Consensus `protobuf:"bytes,5,opt,name=consensus_evaluated,json=consensusEvaluated,proto3,oneof"`
+}
+
+type Event_TenthManActivated struct {
+	TenthManActivated *TenthManActivated `protobuf:"bytes,6,opt,name=tenth_man_activated,json=tenthManActivated,proto3,oneof"`
+}
+
+type Event_AgentError struct {
+	AgentError *AgentError `protobuf:"bytes,7,opt,name=agent_error,json=agentError,proto3,oneof"`
+}
+
+type Event_Done struct {
+	Done *Run `protobuf:"bytes,8,opt,name=done,proto3,oneof"`
+}
+
+func (*Event_TurnCompleted) isEvent_Event() {}
+
+func (*Event_PhaseChanged) isEvent_Event() {}
+
+func (*Event_RoundStarted) isEvent_Event() {}
+
+func (*Event_RoundEnded) isEvent_Event() {}
+
+func (*Event_ConsensusEvaluated) isEvent_Event() {}
+
+func (*Event_TenthManActivated) isEvent_Event() {}
+
+func (*Event_AgentError) isEvent_Event() {}
+
+func (*Event_Done) isEvent_Event() {}
+
+type RoundSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Round         int32                  `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Phase         Phase                  `protobuf:"varint,2,opt,name=phase,proto3,enum=tenthman.v1.Phase" json:"phase,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoundSummary) Reset() {
+	*x = RoundSummary{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoundSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundSummary) ProtoMessage() {}
+
+func (x *RoundSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundSummary.ProtoReflect.Descriptor instead.
+func (*RoundSummary) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RoundSummary) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *RoundSummary) GetPhase() Phase {
+	if x != nil {
+		return x.Phase
+	}
+	return Phase_PHASE_UNSPECIFIED
+}
+
+type TenthManActivated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	Position      string                 `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TenthManActivated) Reset() {
+	*x = TenthManActivated{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TenthManActivated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenthManActivated) ProtoMessage() {}
+
+func (x *TenthManActivated) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenthManActivated.ProtoReflect.Descriptor instead.
+func (*TenthManActivated) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TenthManActivated) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+func (x *TenthManActivated) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+type AgentError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	WillRetry     bool                   `protobuf:"varint,3,opt,name=will_retry,json=willRetry,proto3" json:"will_retry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentError) Reset() {
+	*x = AgentError{}
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentError) ProtoMessage() {}
+
+func (x *AgentError) ProtoReflect() protoreflect.Message {
+	mi := &file_tenthman_v1_tenthman_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentError.ProtoReflect.Descriptor instead.
+func (*AgentError) Descriptor() ([]byte, []int) {
+	return file_tenthman_v1_tenthman_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AgentError) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+func (x *AgentError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AgentError) GetWillRetry() bool {
+	if x != nil {
+		return x.WillRetry
+	}
+	return false
+}
+
+var File_tenthman_v1_tenthman_proto protoreflect.FileDescriptor
+
+const file_tenthman_v1_tenthman_proto_rawDesc = "" +
+	"\n" +
+	"\x1atenthman/v1/tenthman.proto\x12\vtenthman.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xce\x02\n" +
+	"\x03Job\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06agents\x18\x03 \x01(\x05R\x06agents\x12\x1d\n" +
+	"\n" +
+	"min_rounds\x18\x04 \x01(\x05R\tminRounds\x12\x1d\n" +
+	"\n" +
+	"max_rounds\x18\x05 \x01(\x05R\tmaxRounds\x12(\n" +
+	"\x10tenth_man_rounds\x18\x06 \x01(\x05R\x0etenthManRounds\x12+\n" +
+	"\x11stagnation_rounds\x18\a \x01(\x05R\x10stagnationRounds\x12\"\n" +
+	"\finstructions\x18\b \x01(\tR\finstructions\x12\x18\n" +
+	"\aexperts\x18\t \x03(\tR\aexperts\x12\x1a\n" +
+	"\bcompress\x18\n" +
+	" \x01(\tR\bcompress\x12\x16\n" +
+	"\x06upload\x18\v \x01(\tR\x06upload\"5\n" +
+	"\x0fStartRunRequest\x12\"\n" +
+	"\x03job\x18\x01 \x01(\v2\x10.tenthman.v1.JobR\x03job\"\x1f\n" +
+	"\rGetRunRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x11\n" +
+	"\x0fListRunsRequest\"8\n" +
+	"\x10ListRunsResponse\x12$\n" +
+	"\x04runs\x18\x01 \x03(\v2\x10.tenthman.v1.RunR\x04runs\">\n" +
+	"\x12InjectEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"\x15\n" +
+	"\x13InjectEventResponse\"\x1f\n" +
+	"\rStreamRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8c\x01\n" +
+	"\tConsensus\x12\x1a\n" +
+	"\bdetected\x18\x01 \x01(\bR\bdetected\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\tR\bposition\x12'\n" +
+	"\x0fagreement_score\x18\x03 \x01(\x05R\x0eagreementScore\x12\x1e\n" +
+	"\n" +
+	"dissenters\x18\x04 \x03(\tR\n" +
+	"dissenters\"\xb7\x04\n" +
+	"\x03Run\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x16\n" +
+	"\x06tenant\x18\x03 \x01(\tR\x06tenant\x12\"\n" +
+	"\x03job\x18\x04 \x01(\v2\x10.tenthman.v1.JobR\x03job\x12+\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x13.tenthman.v1.StatusR\x06status\x129\n" +
+	"\n" +
+	"started_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12\x10\n" +
+	"\x03dir\x18\b \x01(\tR\x03dir\x12\x16\n" +
+	"\x06rounds\x18\t \x01(\x05R\x06rounds\x12(\n" +
+	"\x05phase\x18\n" +
+	" \x01(\x0e2\x12.tenthman.v1.PhaseR\x05phase\x124\n" +
+	"\tconsensus\x18\v \x01(\v2\x16.tenthman.v1.ConsensusR\tconsensus\x12\x14\n" +
+	"\x05error\x18\f \x01(\tR\x05error\x12\x16\n" +
+	"\x06tokens\x18\r \x01(\x03R\x06tokens\x124\n" +
+	"\x06models\x18\x0e \x03(\v2\x1c.tenthman.v1.Run.ModelsEntryR\x06models\x1a9\n" +
+	"\vModelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"U\n" +
+	"\x05Agent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\"\xc4\x01\n" +
+	"\x04Turn\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05round\x18\x02 \x01(\x05R\x05round\x12(\n" +
+	"\x05agent\x18\x03 \x01(\v2\x12.tenthman.v1.AgentR\x05agent\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x1e\n" +
+	"\vin_reply_to\x18\x05 \x01(\x05R\tinReplyTo\x12#\n" +
+	"\n" +
+	"confidence\x18\x06 \x01(\x05H\x00R\n" +
+	"confidence\x88\x01\x01B\r\n" +
+	"\v_confidence\"\x88\x04\n" +
+	"\x05Event\x12:\n" +
+	"\x0eturn_completed\x18\x01 \x01(\v2\x11.tenthman.v1.TurnH\x00R\rturnCompleted\x129\n" +
+	"\rphase_changed\x18\x02 \x01(\x0e2\x12.tenthman.v1.PhaseH\x00R\fphaseChanged\x12@\n" +
+	"\rround_started\x18\x03 \x01(\v2\x19.tenthman.v1.RoundSummaryH\x00R\froundStarted\x12<\n" +
+	"\vround_ended\x18\x04 \x01(\v2\x19.tenthman.v1.RoundSummaryH\x00R\n" +
+	"roundEnded\x12I\n" +
+	"\x13consensus_evaluated\x18\x05 \x01(\v2\x16.tenthman.v1.ConsensusH\x00R\x12consensusEvaluated\x12P\n" +
+	"\x13tenth_man_activated\x18\x06 \x01(\v2\x1e.tenthman.v1.TenthManActivatedH\x00R\x11tenthManActivated\x12:\n" +
+	"\vagent_error\x18\a \x01(\v2\x17.tenthman.v1.AgentErrorH\x00R\n" +
+	"agentError\x12&\n" +
+	"\x04done\x18\b \x01(\v2\x10.tenthman.v1.RunH\x00R\x04doneB\a\n" +
+	"\x05event\"N\n" +
+	"\fRoundSummary\x12\x14\n" +
+	"\x05round\x18\x01 \x01(\x05R\x05round\x12(\n" +
+	"\x05phase\x18\x02 \x01(\x0e2\x12.tenthman.v1.PhaseR\x05phase\"Y\n" +
+	"\x11TenthManActivated\x12(\n" +
+	"\x05agent\x18\x01 \x01(\v2\x12.tenthman.v1.AgentR\x05agent\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\tR\bposition\"k\n" +
+	"\n" +
+	"AgentError\x12(\n" +
+	"\x05agent\x18\x01 \x01(\v2\x12.tenthman.v1.AgentR\x05agent\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"will_retry\x18\x03 \x01(\bR\twillRetry*\xb5\x01\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSTATUS_RUNNING\x10\x01\x12\x14\n" +
+	"\x10STATUS_COMPLETED\x10\x02\x12\x11\n" +
+	"\rSTATUS_FAILED\x10\x03\x12\x11\n" +
+	"\rSTATUS_QUEUED\x10\x04\x12\x16\n" +
+	"\x12STATUS_INTERRUPTED\x10\x05\x12\x15\n" +
+	"\x11STATUS_CANCELLING\x10\x06\x12\x14\n" +
+	"\x10STATUS_CANCELLED\x10\a*J\n" +
+	"\x05Phase\x12\x15\n" +
+	"\x11PHASE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11PHASE_FREE_DEBATE\x10\x01\x12\x13\n" +
+	"\x0fPHASE_TENTH_MAN\x10\x022\xa0\x03\n" +
+	"\rDebateService\x12:\n" +
+	"\bStartRun\x12\x1c.tenthman.v1.StartRunRequest\x1a\x10.tenthman.v1.Run\x126\n" +
+	"\x06GetRun\x12\x1a.tenthman.v1.GetRunRequest\x1a\x10.tenthman.v1.Run\x12G\n" +
+	"\bListRuns\x12\x1c.tenthman.v1.ListRunsRequest\x1a\x1d.tenthman.v1.ListRunsResponse\x12P\n" +
+	"\vInjectEvent\x12\x1f.tenthman.v1.InjectEventRequest\x1a .tenthman.v1.InjectEventResponse\x12>\n" +
+	"\vStreamTurns\x12\x1a.tenthman.v1.StreamRequest\x1a\x11.tenthman.v1.Turn0\x01\x12@\n" +
+	"\fStreamEvents\x12\x1a.tenthman.v1.StreamRequest\x1a\x12.tenthman.v1.Event0\x01BGZEgithub.com/lorenzotomasdiez/tenth-man-rule/gen/tenthman/v1;tenthmanv1b\x06proto3"
+
+var (
+	file_tenthman_v1_tenthman_proto_rawDescOnce sync.Once
+	file_tenthman_v1_tenthman_proto_rawDescData []byte
+)
+
+func file_tenthman_v1_tenthman_proto_rawDescGZIP() []byte {
+	file_tenthman_v1_tenthman_proto_rawDescOnce.Do(func() {
+		file_tenthman_v1_tenthman_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tenthman_v1_tenthman_proto_rawDesc), len(file_tenthman_v1_tenthman_proto_rawDesc)))
+	})
+	return file_tenthman_v1_tenthman_proto_rawDescData
+}
+
+var file_tenthman_v1_tenthman_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_tenthman_v1_tenthman_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_tenthman_v1_tenthman_proto_goTypes = []any{
+	(Status)(0),                   // 0: tenthman.v1.Status
+	(Phase)(0),                    // 1: tenthman.v1.Phase
+	(*Job)(nil),                   // 2: tenthman.v1.Job
+	(*StartRunRequest)(nil),       // 3: tenthman.v1.StartRunRequest
+	(*GetRunRequest)(nil),         // 4: tenthman.v1.GetRunRequest
+	(*ListRunsRequest)(nil),       // 5: tenthman.v1.ListRunsRequest
+	(*ListRunsResponse)(nil),      // 6: tenthman.v1.ListRunsResponse
+	(*InjectEventRequest)(nil),    // 7: tenthman.v1.InjectEventRequest
+	(*InjectEventResponse)(nil),   // 8: tenthman.v1.InjectEventResponse
+	(*StreamRequest)(nil),         // 9: tenthman.v1.StreamRequest
+	(*Consensus)(nil),             // 10: tenthman.v1.Consensus
+	(*Run)(nil),                   // 11: tenthman.v1.Run
+	(*Agent)(nil),                 // 12: tenthman.v1.Agent
+	(*Turn)(nil),                  // 13: tenthman.v1.Turn
+	(*Event)(nil),                 // 14: tenthman.v1.Event
+	(*RoundSummary)(nil),          // 15: tenthman.v1.RoundSummary
+	(*TenthManActivated)(nil),     // 16: tenthman.v1.TenthManActivated
+	(*AgentError)(nil),            // 17: tenthman.v1.AgentError
+	nil,                           // 18: tenthman.v1.Run.ModelsEntry
+	(*timestamppb.Timestamp)(nil), // 19: google.protobuf.Timestamp
+}
+var file_tenthman_v1_tenthman_proto_depIdxs = []int32{
+	2,  // 0: tenthman.v1.StartRunRequest.job:type_name -> tenthman.v1.Job
+	11, // 1: tenthman.v1.ListRunsResponse.runs:type_name -> tenthman.v1.Run
+	2,  // 2: tenthman.v1.Run.job:type_name -> tenthman.v1.Job
+	0,  // 3: tenthman.v1.Run.status:type_name -> tenthman.v1.Status
+	19, // 4: tenthman.v1.Run.started_at:type_name -> google.protobuf.Timestamp
+	19, // 5: tenthman.v1.Run.finished_at:type_name -> google.protobuf.Timestamp
+	1,  // 6: tenthman.v1.Run.phase:type_name -> tenthman.v1.Phase
+	10, // 7: tenthman.v1.Run.consensus:type_name -> tenthman.v1.Consensus
+	18, // 8: tenthman.v1.Run.models:type_name -> tenthman.v1.Run.ModelsEntry
+	12, // 9: tenthman.v1.Turn.agent:type_name -> tenthman.v1.Agent
+	13, // 10: tenthman.v1.Event.turn_completed:type_name -> tenthman.v1.Turn
+	1,  // 11: tenthman.v1.Event.phase_changed:type_name -> tenthman.v1.Phase
+	15, // 12: tenthman.v1.Event.round_started:type_name -> tenthman.v1.RoundSummary
+	15, // 13: tenthman.v1.Event.round_ended:type_name -> tenthman.v1.RoundSummary
+	10, // 14: tenthman.v1.Event.consensus_evaluated:type_name -> tenthman.v1.Consensus
+	16, // 15: tenthman.v1.Event.tenth_man_activated:type_name -> tenthman.v1.TenthManActivated
+	17, // 16: tenthman.v1.Event.agent_error:type_name -> tenthman.v1.AgentError
+	11, // 17: tenthman.v1.Event.done:type_name -> tenthman.v1.Run
+	1,  // 18: tenthman.v1.RoundSummary.phase:type_name -> tenthman.v1.Phase
+	12, // 19: tenthman.v1.TenthManActivated.agent:type_name -> tenthman.v1.Agent
+	12, // 20: tenthman.v1.AgentError.agent:type_name -> tenthman.v1.Agent
+	3,  // 21: tenthman.v1.DebateService.StartRun:input_type -> tenthman.v1.StartRunRequest
+	4,  // 22: tenthman.v1.DebateService.GetRun:input_type -> tenthman.v1.GetRunRequest
+	5,  // 23: tenthman.v1.DebateService.ListRuns:input_type -> tenthman.v1.ListRunsRequest
+	7,  // 24: tenthman.v1.DebateService.InjectEvent:input_type -> tenthman.v1.InjectEventRequest
+	9,  // 25: tenthman.v1.DebateService.StreamTurns:input_type -> tenthman.v1.StreamRequest
+	9,  // 26: tenthman.v1.DebateService.StreamEvents:input_type -> tenthman.v1.StreamRequest
+	11, // 27: tenthman.v1.DebateService.StartRun:output_type -> tenthman.v1.Run
+	11, // 28: tenthman.v1.DebateService.GetRun:output_type -> tenthman.v1.Run
+	6,  // 29: tenthman.v1.DebateService.ListRuns:output_type -> tenthman.v1.ListRunsResponse
+	8,  // 30: tenthman.v1.DebateService.InjectEvent:output_type -> tenthman.v1.InjectEventResponse
+	13, // 31: tenthman.v1.DebateService.StreamTurns:output_type -> tenthman.v1.Turn
+	14, // 32: tenthman.v1.DebateService.StreamEvents:output_type -> tenthman.v1.Event
+	27, // [27:33] is the sub-list for method output_type
+	21, // [21:27] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
+}
+
+func init() { file_tenthman_v1_tenthman_proto_init() }
+func file_tenthman_v1_tenthman_proto_init() {
+	if File_tenthman_v1_tenthman_proto != nil {
+		return
+	}
+	file_tenthman_v1_tenthman_proto_msgTypes[11].OneofWrappers = []any{}
+	file_tenthman_v1_tenthman_proto_msgTypes[12].OneofWrappers = []any{
+		(*Event_TurnCompleted)(nil),
+		(*Event_PhaseChanged)(nil),
+		(*Event_RoundStarted)(nil),
+		(*Event_RoundEnded)(nil),
+		(*Event_ConsensusEvaluated)(nil),
+		(*Event_TenthManActivated)(nil),
+		(*Event_AgentError)(nil),
+		(*Event_Done)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tenthman_v1_tenthman_proto_rawDesc), len(file_tenthman_v1_tenthman_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tenthman_v1_tenthman_proto_goTypes,
+		DependencyIndexes: file_tenthman_v1_tenthman_proto_depIdxs,
+		EnumInfos:         file_tenthman_v1_tenthman_proto_enumTypes,
+		MessageInfos:      file_tenthman_v1_tenthman_proto_msgTypes,
+	}.Build()
+	File_tenthman_v1_tenthman_proto = out.File
+	file_tenthman_v1_tenthman_proto_goTypes = nil
+	file_tenthman_v1_tenthman_proto_depIdxs = nil
+}
@@ -0,0 +1,336 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: tenthman/v1/tenthman.proto
+
+package tenthmanv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DebateService_StartRun_FullMethodName     = "/tenthman.v1.DebateService/StartRun"
+	DebateService_GetRun_FullMethodName       = "/tenthman.v1.DebateService/GetRun"
+	DebateService_ListRuns_FullMethodName     = "/tenthman.v1.DebateService/ListRuns"
+	DebateService_InjectEvent_FullMethodName  = "/tenthman.v1.DebateService/InjectEvent"
+	DebateService_StreamTurns_FullMethodName  = "/tenthman.v1.DebateService/StreamTurns"
+	DebateService_StreamEvents_FullMethodName = "/tenthman.v1.DebateService/StreamEvents"
+)
+
+// DebateServiceClient is the client API for DebateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DebateService mirrors the serve-mode REST API for gRPC clients.
+type DebateServiceClient interface {
+	// StartRun queues a debate and returns its run record immediately.
+	StartRun(ctx context.Context, in *StartRunRequest, opts ...grpc.CallOption) (*Run, error)
+	// GetRun returns a single run.
+	GetRun(ctx context.Context, in *GetRunRequest, opts ...grpc.CallOption) (*Run, error)
+	// ListRuns returns every run visible to the caller, oldest first.
+	ListRuns(ctx context.Context, in *ListRunsRequest, opts ...grpc.CallOption) (*ListRunsResponse, error)
+	// InjectEvent shares new information with a running debate from its next round.
+	InjectEvent(ctx context.Context, in *InjectEventRequest, opts ...grpc.CallOption) (*InjectEventResponse, error)
+	// StreamTurns sends every turn of a run, live until it finishes.
+	StreamTurns(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Turn], error)
+	// StreamEvents sends the engine events of a run that Event covers, live
+	// until it finishes, then the final run record as done.
+	StreamEvents(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type debateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDebateServiceClient(cc grpc.ClientConnInterface) DebateServiceClient {
+	return &debateServiceClient{cc}
+}
+
+func (c *debateServiceClient) StartRun(ctx context.Context, in *StartRunRequest, opts ...grpc.CallOption) (*Run, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Run)
+	err := c.cc.Invoke(ctx, DebateService_StartRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debateServiceClient) GetRun(ctx context.Context, in *GetRunRequest, opts ...grpc.CallOption) (*Run, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Run)
+	err := c.cc.Invoke(ctx, DebateService_GetRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debateServiceClient) ListRuns(ctx context.Context, in *ListRunsRequest, opts ...grpc.CallOption) (*ListRunsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRunsResponse)
+	err := c.cc.Invoke(ctx, DebateService_ListRuns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debateServiceClient) InjectEvent(ctx context.Context, in *InjectEventRequest, opts ...grpc.CallOption) (*InjectEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InjectEventResponse)
+	err := c.cc.Invoke(ctx, DebateService_InjectEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debateServiceClient) StreamTurns(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Turn], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DebateService_ServiceDesc.Streams[0], DebateService_StreamTurns_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, Turn]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DebateService_StreamTurnsClient = grpc.ServerStreamingClient[Turn]
+
+func (c *debateServiceClient) StreamEvents(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DebateService_ServiceDesc.Streams[1], DebateService_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DebateService_StreamEventsClient = grpc.ServerStreamingClient[Event]
+
+// DebateServiceServer is the server API for DebateService service.
+// All implementations must embed UnimplementedDebateServiceServer
+// for forward compatibility.
+//
+// DebateService mirrors the serve-mode REST API for gRPC clients.
+type DebateServiceServer interface {
+	// StartRun queues a debate and returns its run record immediately.
+	StartRun(context.Context, *StartRunRequest) (*Run, error)
+	// GetRun returns a single run.
+	GetRun(context.Context, *GetRunRequest) (*Run, error)
+	// ListRuns returns every run visible to the caller, oldest first.
+	ListRuns(context.Context, *ListRunsRequest) (*ListRunsResponse, error)
+	// InjectEvent shares new information with a running debate from its next round.
+	InjectEvent(context.Context, *InjectEventRequest) (*InjectEventResponse, error)
+	// StreamTurns sends every turn of a run, live until it finishes.
+	StreamTurns(*StreamRequest, grpc.ServerStreamingServer[Turn]) error
+	// StreamEvents sends the engine events of a run that Event covers, live
+	// until it finishes, then the final run record as done.
+	StreamEvents(*StreamRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedDebateServiceServer()
+}
+
+// UnimplementedDebateServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDebateServiceServer struct{}
+
+func (UnimplementedDebateServiceServer) StartRun(context.Context, *StartRunRequest) (*Run, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRun not implemented")
+}
+func (UnimplementedDebateServiceServer) GetRun(context.Context, *GetRunRequest) (*Run, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRun not implemented")
+}
+func (UnimplementedDebateServiceServer) ListRuns(context.Context, *ListRunsRequest) (*ListRunsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRuns not implemented")
+}
+func (UnimplementedDebateServiceServer) InjectEvent(context.Context, *InjectEventRequest) (*InjectEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InjectEvent not implemented")
+}
+func (UnimplementedDebateServiceServer) StreamTurns(*StreamRequest, grpc.ServerStreamingServer[Turn]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTurns not implemented")
+}
+func (UnimplementedDebateServiceServer) StreamEvents(*StreamRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedDebateServiceServer) mustEmbedUnimplementedDebateServiceServer() {}
+func (UnimplementedDebateServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeDebateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DebateServiceServer will
+// result in compilation errors.
+type UnsafeDebateServiceServer interface {
+	mustEmbedUnimplementedDebateServiceServer()
+}
+
+func RegisterDebateServiceServer(s grpc.ServiceRegistrar, srv DebateServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDebateServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DebateService_ServiceDesc, srv)
+}
+
+func _DebateService_StartRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebateServiceServer).StartRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DebateService_StartRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebateServiceServer).StartRun(ctx, req.(*StartRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DebateService_GetRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebateServiceServer).GetRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DebateService_GetRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebateServiceServer).GetRun(ctx, req.(*GetRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DebateService_ListRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRunsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebateServiceServer).ListRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DebateService_ListRuns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebateServiceServer).ListRuns(ctx, req.(*ListRunsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DebateService_InjectEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebateServiceServer).InjectEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DebateService_InjectEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebateServiceServer).InjectEvent(ctx, req.(*InjectEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DebateService_StreamTurns_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DebateServiceServer).StreamTurns(m, &grpc.GenericServerStream[StreamRequest, Turn]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DebateService_StreamTurnsServer = grpc.ServerStreamingServer[Turn]
+
+func _DebateService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DebateServiceServer).StreamEvents(m, &grpc.GenericServerStream[StreamRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DebateService_StreamEventsServer = grpc.ServerStreamingServer[Event]
+
+// DebateService_ServiceDesc is the grpc.ServiceDesc for DebateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DebateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tenthman.v1.DebateService",
+	HandlerType: (*DebateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartRun",
+			Handler:    _DebateService_StartRun_Handler,
+		},
+		{
+			MethodName: "GetRun",
+			Handler:    _DebateService_GetRun_Handler,
+		},
+		{
+			MethodName: "ListRuns",
+			Handler:    _DebateService_ListRuns_Handler,
+		},
+		{
+			MethodName: "InjectEvent",
+			Handler:    _DebateService_InjectEvent_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTurns",
+			Handler:       _DebateService_StreamTurns_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _DebateService_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tenthman/v1/tenthman.proto",
+}
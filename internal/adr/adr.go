@@ -0,0 +1,195 @@
+package adr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+const revisedFile = "adr-revised.md"
+
+// ADR is an Architecture Decision Record split into its standard sections.
+type ADR struct {
+	Title        string
+	Status       string
+	Context      string
+	Decision     string
+	Consequences string
+	Raw          string
+}
+
+// Parse extracts the title and the Status, Context, Decision and Consequences
+// sections from an ADR in markdown. Section headings may be at any level and
+// are matched case-insensitively.
+func Parse(markdown string) (*ADR, error) {
+	a := &ADR{Raw: markdown}
+	var current *string
+	var sb strings.Builder
+	flush := func() {
+		if current != nil {
+			*current = strings.TrimSpace(sb.String())
+		}
+		sb.Reset()
+	}
+
+	for line := range strings.SplitSeq(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			if a.Title == "" && strings.HasPrefix(trimmed, "# ") {
+				flush()
+				a.Title = heading
+				current = nil
+				continue
+			}
+			if field := a.section(heading); field != nil {
+				flush()
+				current = field
+				continue
+			}
+			if strings.HasPrefix(trimmed, "## ") {
+				flush()
+				current = nil
+				continue
+			}
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	flush()
+
+	if a.Decision == "" {
+		return nil, fmt.Errorf("adr: no Decision section found")
+	}
+	return a, nil
+}
+
+func (a *ADR) section(heading string) *string {
+	switch strings.ToLower(heading) {
+	case "status":
+		return &a.Status
+	case "context", "context and problem statement":
+		return &a.Context
+	case "decision", "decision outcome":
+		return &a.Decision
+	case "consequences":
+		return &a.Consequences
+	}
+	return nil
+}
+
+// Load reads and parses the ADR at path.
+func Load(path string) (*ADR, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("adr: %w", err)
+	}
+	return Parse(string(data))
+}
+
+// Topic builds the debate topic asking whether the decision holds.
+func (a *ADR) Topic() string {
+	var sb strings.Builder
+	title := a.Title
+	if title == "" {
+		title = "this architecture decision"
+	}
+	fmt.Fprintf(&sb, "Does the architecture decision %q hold up? Decision: %s", title, a.Decision)
+	if a.Context != "" {
+		fmt.Fprintf(&sb, " Context: %s", a.Context)
+	}
+	if a.Consequences != "" {
+		fmt.Fprintf(&sb, " Expected consequences: %s", a.Consequences)
+	}
+	return sb.String()
+}
+
+// Revise returns a revised ADR draft: the original record followed by the
+// Tenth Man's objections, the scored risks and the outcome of the review
+// debate.
+func Revise(a *ADR, result *debate.Result, risks []Risk) string {
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(a.Raw, "\n"))
+	sb.WriteString("\n\n## Tenth Man Objections\n\n")
+
+	objections := 0
+	for _, turn := range result.Transcript.Turns {
+		if turn.Agent.Role != "tenth-man" {
+			continue
+		}
+		objections++
+		fmt.Fprintf(&sb, "### Round %d\n\n%s\n\n", turn.Round, strings.TrimSpace(turn.Content))
+	}
+	if objections == 0 {
+		sb.WriteString("The Tenth Man was not activated: the reviewers did not reach a strong enough consensus to require a contrarian case.\n\n")
+	}
+
+	if len(risks) > 0 {
+		sb.WriteString("## Risk Assessment\n\n")
+		writeRiskMatrix(&sb, NewRiskMatrix(risks))
+		sb.WriteString("| Severity | Likelihood | Risk | Objection | Rationale |\n|----------|------------|------|-----------|-----------|\n")
+		sorted := slices.Clone(risks)
+		slices.SortStableFunc(sorted, func(x, y Risk) int {
+			if x.Severity != y.Severity {
+				return int(y.Severity - x.Severity)
+			}
+			return int(y.Likelihood - x.Likelihood)
+		})
+		for _, r := range sorted {
+			fmt.Fprintf(&sb, "| %s | %s | %s | #%d | %s |\n", r.Severity, r.Likelihood, tableCell(r.Title), r.Objection, tableCell(r.Rationale))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Review Outcome\n\n")
+	c := result.Consensus
+	if c == nil {
+		c = &debate.ConsensusResult{}
+	}
+	if c.Detected {
+		fmt.Fprintf(&sb, "- **Consensus:** %s\n", c.Position)
+	} else {
+		sb.WriteString("- **Consensus:** none reached\n")
+	}
+	fmt.Fprintf(&sb, "- **Agreement Score:** %d/10\n", c.Score)
+	if len(c.Dissenters) > 0 {
+		fmt.Fprintf(&sb, "- **Dissenters:** %s\n", strings.Join(c.Dissenters, ", "))
+	}
+	fmt.Fprintf(&sb, "- **Rounds:** %d\n", result.Transcript.Rounds)
+	return sb.String()
+}
+
+// WriteRevision writes the revised ADR draft into dir.
+func WriteRevision(dir string, a *ADR, result *debate.Result, risks []Risk) error {
+	if err := os.WriteFile(filepath.Join(dir, revisedFile), []byte(Revise(a, result, risks)), 0o644); err != nil {
+		return fmt.Errorf("adr: %w", err)
+	}
+	return nil
+}
+
+// writeRiskMatrix renders m as a severity-by-likelihood grid of risk counts,
+// most severe first.
+func writeRiskMatrix(sb *strings.Builder, m RiskMatrix) {
+	sb.WriteString("| Severity / Likelihood | low | medium | high |\n|---|---|---|---|\n")
+	for sev := SeverityCritical; sev >= SeverityLow; sev-- {
+		fmt.Fprintf(sb, "| **%s** |", sev)
+		for l := LikelihoodLow; l <= LikelihoodHigh; l++ {
+			if n := m[sev-1][l-1]; n > 0 {
+				fmt.Fprintf(sb, " %d |", n)
+			} else {
+				sb.WriteString(" · |")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+}
+
+// tableCell flattens s for use inside a markdown table cell.
+func tableCell(s string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(s), " "), "|", "\\|")
+}
@@ -0,0 +1,127 @@
+package adr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+const sampleADR = `# ADR 7: Use PostgreSQL as the event store
+
+## Status
+
+Accepted
+
+## Context
+
+We need durable, ordered storage for domain events.
+
+## Decision
+
+We will store events in a single PostgreSQL table.
+
+## Consequences
+
+Operational simplicity; throughput is bounded by one primary.
+
+## Notes
+
+Discussed in the March architecture review.
+`
+
+func TestParse(t *testing.T) {
+	a, err := Parse(sampleADR)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if a.Title != "ADR 7: Use PostgreSQL as the event store" {
+		t.Errorf("Title = %q", a.Title)
+	}
+	if a.Status != "Accepted" {
+		t.Errorf("Status = %q", a.Status)
+	}
+	if a.Context != "We need durable, ordered storage for domain events." {
+		t.Errorf("Context = %q", a.Context)
+	}
+	if a.Decision != "We will store events in a single PostgreSQL table." {
+		t.Errorf("Decision = %q", a.Decision)
+	}
+	if strings.Contains(a.Consequences, "March") {
+		t.Errorf("Consequences should stop at the next section, got %q", a.Consequences)
+	}
+}
+
+func TestParseMADRHeadings(t *testing.T) {
+	a, err := Parse("# Title\n\n### Context and Problem Statement\nctx\n\n### Decision Outcome\nchosen\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if a.Context != "ctx" || a.Decision != "chosen" {
+		t.Errorf("unexpected ADR: %+v", a)
+	}
+}
+
+func TestParseRequiresDecision(t *testing.T) {
+	if _, err := Parse("# Title\n\n## Context\nonly context\n"); err == nil {
+		t.Fatal("expected error without a Decision section")
+	}
+}
+
+func TestTopicIncludesSections(t *testing.T) {
+	a, _ := Parse(sampleADR)
+	topic := a.Topic()
+	for _, want := range []string{"Use PostgreSQL as the event store", "single PostgreSQL table", "ordered storage", "bounded by one primary"} {
+		if !strings.Contains(topic, want) {
+			t.Errorf("topic missing %q", want)
+		}
+	}
+}
+
+func TestReviseAppendsObjections(t *testing.T) {
+	a, _ := Parse(sampleADR)
+	result := &debate.Result{
+		Transcript: &debate.Transcript{
+			Rounds: 7,
+			Turns: []debate.Turn{
+				{Round: 5, Agent: debate.Agent{Name: "Alice", Role: "debater"}, Content: "Postgres is fine."},
+				{Round: 6, Agent: debate.Agent{Name: "The Tenth Man", Role: "tenth-man"}, Content: "A single primary is a write bottleneck."},
+			},
+		},
+		Consensus: &debate.ConsensusResult{Detected: true, Position: "Keep Postgres but plan for partitioning", Score: 7, Dissenters: []string{"Bob"}},
+	}
+
+	revised := Revise(a, result, nil)
+	if !strings.HasPrefix(revised, "# ADR 7") {
+		t.Error("revised ADR should start with the original record")
+	}
+	for _, want := range []string{"## Tenth Man Objections", "### Round 6", "write bottleneck", "## Review Outcome", "plan for partitioning", "7/10", "Bob"} {
+		if !strings.Contains(revised, want) {
+			t.Errorf("revised ADR missing %q", want)
+		}
+	}
+	if strings.Contains(revised, "Postgres is fine.") {
+		t.Error("debater turns should not be listed as objections")
+	}
+}
+
+func TestReviseWithoutTenthMan(t *testing.T) {
+	a, _ := Parse(sampleADR)
+	revised := Revise(a, &debate.Result{Transcript: &debate.Transcript{Rounds: 5}}, nil)
+	if !strings.Contains(revised, "not activated") || !strings.Contains(revised, "none reached") {
+		t.Errorf("unexpected revision:\n%s", revised)
+	}
+}
+
+func TestWriteRevision(t *testing.T) {
+	dir := t.TempDir()
+	a, _ := Parse(sampleADR)
+	if err := WriteRevision(dir, a, &debate.Result{Transcript: &debate.Transcript{}}, nil); err != nil {
+		t.Fatalf("WriteRevision() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "adr-revised.md")); err != nil {
+		t.Errorf("adr-revised.md not written: %v", err)
+	}
+}
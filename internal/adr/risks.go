@@ -0,0 +1,253 @@
+package adr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/llmjson"
+)
+
+const (
+	risksFile       = "risks.json"
+	maxScoreRetries = 3
+)
+
+// Severity ranks how damaging a risk would be if it materialized.
+type Severity int
+
+const (
+	SeverityLow Severity = iota + 1
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityLow:      "low",
+	SeverityMedium:   "medium",
+	SeverityHigh:     "high",
+	SeverityCritical: "critical",
+}
+
+// ParseSeverity parses "low", "medium", "high" or "critical" (case-insensitive).
+func ParseSeverity(s string) (Severity, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for sev, name := range severityNames {
+		if name == s {
+			return sev, nil
+		}
+	}
+	return 0, fmt.Errorf("adr: unknown severity %q (want low, medium, high or critical)", s)
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	if _, ok := severityNames[s]; !ok {
+		return nil, fmt.Errorf("adr: invalid severity %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(text []byte) error {
+	sev, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = sev
+	return nil
+}
+
+// Likelihood ranks how probable a risk is.
+type Likelihood int
+
+const (
+	LikelihoodLow Likelihood = iota + 1
+	LikelihoodMedium
+	LikelihoodHigh
+)
+
+var likelihoodNames = map[Likelihood]string{
+	LikelihoodLow:    "low",
+	LikelihoodMedium: "medium",
+	LikelihoodHigh:   "high",
+}
+
+// ParseLikelihood parses "low", "medium" or "high" (case-insensitive).
+func ParseLikelihood(s string) (Likelihood, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for l, name := range likelihoodNames {
+		if name == s {
+			return l, nil
+		}
+	}
+	return 0, fmt.Errorf("adr: unknown likelihood %q (want low, medium or high)", s)
+}
+
+func (l Likelihood) String() string {
+	if name, ok := likelihoodNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("Likelihood(%d)", int(l))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Likelihood) MarshalText() ([]byte, error) {
+	if _, ok := likelihoodNames[l]; !ok {
+		return nil, fmt.Errorf("adr: invalid likelihood %d", int(l))
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *Likelihood) UnmarshalText(text []byte) error {
+	v, err := ParseLikelihood(string(text))
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+// Risk is one risk raised by the Tenth Man against a decision.
+type Risk struct {
+	Title      string     `json:"title"`
+	Objection  int        `json:"objection"` // ID of the Tenth Man turn that raised it
+	Severity   Severity   `json:"severity"`
+	Likelihood Likelihood `json:"likelihood"`
+	Rationale  string     `json:"rationale"`
+}
+
+// RiskScorer turns the Tenth Man's objections into severity-scored risks
+// using an LLM.
+type RiskScorer struct {
+	llm   debate.LLMClient
+	model string
+}
+
+// NewRiskScorer creates a new RiskScorer.
+func NewRiskScorer(llm debate.LLMClient, model string) *RiskScorer {
+	return &RiskScorer{llm: llm, model: model}
+}
+
+// Score classifies each of the Tenth Man's objections to the ADR by severity
+// and likelihood. It returns no risks when the Tenth Man was not activated.
+// Responses that fail validation are retried with the problems fed back to
+// the model. Unlike claims extraction, a model that never returns valid risks
+// is an error, so a CI gate cannot pass by accident.
+func (s *RiskScorer) Score(ctx context.Context, a *ADR, result *debate.Result) ([]Risk, error) {
+	var objections strings.Builder
+	objectionIDs := make(map[int]bool)
+	for _, turn := range result.Transcript.Turns {
+		if turn.Agent.Role == "tenth-man" {
+			objectionIDs[turn.ID] = true
+			fmt.Fprintf(&objections, "[#%d] %s\n\n", turn.ID, strings.TrimSpace(turn.Content))
+		}
+	}
+	if len(objectionIDs) == 0 {
+		return nil, nil
+	}
+
+	out, err := llmjson.StructuredCall(ctx, s.llm, s.model, llmjson.Prompt[risksReply]{
+		System: "You are a risk analyst reviewing objections to an architecture decision. Each objection is labelled [#N]. List each distinct risk the objections identify. " + guard.Instruction,
+		Schema: `{"risks": [{"title": "...", "objection": N, "severity": "low|medium|high|critical", "likelihood": "low|medium|high", "rationale": "..."}]}`,
+		Notes: `"objection" is the number of the objection that raises the risk.
+Severity is the impact if the risk materializes: "critical" means outage, data loss, security breach or legal exposure; "high" means significant cost or rework; "medium" means noticeable but contained; "low" means minor.
+Likelihood is how probable the risk is given the decision as written.`,
+		User:     fmt.Sprintf("Decision: %s\n\nObjections:\n%s", a.Decision, guard.Wrap("objections", strings.TrimSpace(objections.String()))),
+		Required: []string{"risks"},
+		Validate: func(out *risksReply) error { return validateRisks(out.Risks, objectionIDs) },
+		Attempts: maxScoreRetries,
+	})
+	if err != nil {
+		var invalid *llmjson.InvalidError
+		if errors.As(err, &invalid) {
+			err = invalid.Err
+		}
+		return nil, fmt.Errorf("adr: scoring risks: %w", err)
+	}
+	return out.Risks, nil
+}
+
+// risksReply is the model's answer when scoring risks.
+type risksReply struct {
+	Risks []Risk `json:"risks"`
+}
+
+// validateRisks checks that every risk is titled, fully classified and traced
+// to one of the Tenth Man's objections.
+func validateRisks(risks []Risk, objectionIDs map[int]bool) error {
+	var errs []error
+	for i, r := range risks {
+		if strings.TrimSpace(r.Title) == "" {
+			errs = append(errs, fmt.Errorf("risk %d: title is required", i+1))
+		}
+		if r.Severity == 0 {
+			errs = append(errs, fmt.Errorf("risk %d: severity is required", i+1))
+		}
+		if r.Likelihood == 0 {
+			errs = append(errs, fmt.Errorf("risk %d: likelihood is required", i+1))
+		}
+		if !objectionIDs[r.Objection] {
+			errs = append(errs, fmt.Errorf("risk %d: objection #%d does not exist", i+1, r.Objection))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RisksAtOrAbove returns the risks whose severity is at least threshold.
+func RisksAtOrAbove(risks []Risk, threshold Severity) []Risk {
+	var out []Risk
+	for _, r := range risks {
+		if r.Severity >= threshold {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// WriteRisks writes risks as JSON into dir.
+func WriteRisks(dir string, risks []Risk) error {
+	if risks == nil {
+		risks = []Risk{}
+	}
+	data, err := json.MarshalIndent(struct {
+		Risks []Risk `json:"risks"`
+	}{risks}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("adr: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, risksFile), data, 0o644); err != nil {
+		return fmt.Errorf("adr: %w", err)
+	}
+	return nil
+}
+
+// RiskMatrix counts risks by severity and likelihood, indexed
+// [severity-1][likelihood-1].
+type RiskMatrix [SeverityCritical][LikelihoodHigh]int
+
+// NewRiskMatrix aggregates risks into a matrix, ignoring unclassified ones.
+func NewRiskMatrix(risks []Risk) RiskMatrix {
+	var m RiskMatrix
+	for _, r := range risks {
+		if r.Severity >= SeverityLow && r.Severity <= SeverityCritical && r.Likelihood >= LikelihoodLow && r.Likelihood <= LikelihoodHigh {
+			m[r.Severity-1][r.Likelihood-1]++
+		}
+	}
+	return m
+}
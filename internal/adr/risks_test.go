@@ -0,0 +1,169 @@
+package adr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+type mockLLM struct {
+	responses []string
+	calls     int
+	system    string
+	prompt    string
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	m.system, m.prompt = msgs[0].Content, msgs[1].Content
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+func tenthManResult() *debate.Result {
+	return &debate.Result{Transcript: &debate.Transcript{Turns: []debate.Turn{
+		{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Role: "debater"}, Content: "Postgres is fine."},
+		{ID: 2, Round: 2, Agent: debate.Agent{Name: "The Tenth Man", Role: "tenth-man"}, Content: "A single primary is a write bottleneck."},
+	}}}
+}
+
+func TestScoreRisks(t *testing.T) {
+	a, _ := Parse(sampleADR)
+	llm := &mockLLM{responses: []string{"```json\n" + `{"risks": [{"title": "Write bottleneck", "objection": 2, "severity": "High", "likelihood": "medium", "rationale": "One primary"}]}` + "\n```"}}
+
+	risks, err := NewRiskScorer(llm, "m").Score(context.Background(), a, tenthManResult())
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+	if len(risks) != 1 || risks[0].Severity != SeverityHigh || risks[0].Likelihood != LikelihoodMedium || risks[0].Objection != 2 {
+		t.Errorf("unexpected risks %+v", risks)
+	}
+	if !strings.Contains(llm.prompt, "[#2] A single primary is a write bottleneck.") || strings.Contains(llm.prompt, "Postgres is fine") {
+		t.Errorf("prompt should contain only Tenth Man objections, got %q", llm.prompt)
+	}
+}
+
+func TestScoreRisksRejectsInvalidRisks(t *testing.T) {
+	a, _ := Parse(sampleADR)
+	llm := &mockLLM{responses: []string{
+		`{"risks": [{"title": "x", "objection": 2, "severity": "severe", "likelihood": "low"}]}`,
+		`{"risks": [{"title": "x", "objection": 2, "severity": "high"}]}`,
+		`{"risks": [{"title": "x", "objection": 9, "severity": "high", "likelihood": "low"}]}`,
+	}}
+	if _, err := NewRiskScorer(llm, "m").Score(context.Background(), a, tenthManResult()); err == nil {
+		t.Fatal("expected error when no response has valid severities")
+	}
+	if llm.calls != maxScoreRetries {
+		t.Errorf("calls = %d, want %d", llm.calls, maxScoreRetries)
+	}
+}
+
+func TestScoreRisksFeedsBackValidationErrors(t *testing.T) {
+	a, _ := Parse(sampleADR)
+	llm := &feedbackLLM{responses: []string{
+		`{"risks": [{"title": "x", "objection": 2, "severity": "high"}]}`,
+		`{"risks": [{"title": "x", "objection": 2, "severity": "high", "likelihood": "low"}]}`,
+	}}
+	risks, err := NewRiskScorer(llm, "m").Score(context.Background(), a, tenthManResult())
+	if err != nil || len(risks) != 1 {
+		t.Fatalf("Score() = %v, %v", risks, err)
+	}
+	if !strings.Contains(llm.retryPrompt, "likelihood is required") {
+		t.Errorf("retry prompt should explain the problem, got %q", llm.retryPrompt)
+	}
+}
+
+// feedbackLLM records the retry message sent after an invalid response.
+type feedbackLLM struct {
+	responses   []string
+	calls       int
+	retryPrompt string
+}
+
+func (m *feedbackLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if len(msgs) > 2 {
+		m.retryPrompt = msgs[len(msgs)-1].Content
+	}
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+func TestNewRiskMatrix(t *testing.T) {
+	m := NewRiskMatrix([]Risk{
+		{Severity: SeverityCritical, Likelihood: LikelihoodHigh},
+		{Severity: SeverityCritical, Likelihood: LikelihoodHigh},
+		{Severity: SeverityLow, Likelihood: LikelihoodMedium},
+		{Severity: SeverityHigh}, // unclassified, ignored
+	})
+	if m[SeverityCritical-1][LikelihoodHigh-1] != 2 || m[SeverityLow-1][LikelihoodMedium-1] != 1 {
+		t.Errorf("unexpected matrix %v", m)
+	}
+}
+
+func TestScoreRisksWithoutTenthMan(t *testing.T) {
+	a, _ := Parse(sampleADR)
+	llm := &mockLLM{responses: []string{"unused"}}
+	risks, err := NewRiskScorer(llm, "m").Score(context.Background(), a, &debate.Result{Transcript: &debate.Transcript{}})
+	if err != nil || risks != nil || llm.calls != 0 {
+		t.Errorf("Score() = %v, %v after %d calls; want no risks and no LLM call", risks, err, llm.calls)
+	}
+}
+
+func TestRisksAtOrAbove(t *testing.T) {
+	risks := []Risk{{Title: "a", Severity: SeverityLow}, {Title: "b", Severity: SeverityHigh}, {Title: "c", Severity: SeverityCritical}}
+	if got := RisksAtOrAbove(risks, SeverityHigh); len(got) != 2 || got[0].Title != "b" {
+		t.Errorf("RisksAtOrAbove(high) = %+v", got)
+	}
+	if _, err := ParseSeverity("extreme"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
+
+func TestReviseIncludesRiskAssessment(t *testing.T) {
+	a, _ := Parse(sampleADR)
+	risks := []Risk{
+		{Title: "Minor", Objection: 2, Severity: SeverityLow, Likelihood: LikelihoodLow},
+		{Title: "Data loss | replay", Objection: 2, Severity: SeverityCritical, Likelihood: LikelihoodMedium, Rationale: "No backups"},
+	}
+	revised := Revise(a, tenthManResult(), risks)
+	for _, want := range []string{"## Risk Assessment", "| **critical** | · | 1 | · |", "| **low** | 1 | · | · |", `| critical | medium | Data loss \| replay | #2 | No backups |`} {
+		if !strings.Contains(revised, want) {
+			t.Errorf("revised ADR missing %q:\n%s", want, revised)
+		}
+	}
+	if strings.Index(revised, "| critical | medium |") > strings.Index(revised, "| low | low |") {
+		t.Error("risks should be ordered by severity, highest first")
+	}
+}
+
+func TestWriteRisks(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteRisks(dir, []Risk{{Title: "x", Objection: 2, Severity: SeverityMedium, Likelihood: LikelihoodHigh}}); err != nil {
+		t.Fatalf("WriteRisks() error = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "risks.json"))
+	if !strings.Contains(string(data), `"likelihood": "high"`) {
+		t.Errorf("unexpected risks.json: %s", data)
+	}
+}
+
+func TestScoreRisksFencesObjections(t *testing.T) {
+	llm := &mockLLM{responses: []string{`{"risks": [{"title": "Write bottleneck", "objection": 2, "severity": "high", "likelihood": "medium"}]}`}}
+	if _, err := NewRiskScorer(llm, "m").Score(context.Background(), &ADR{Decision: "Use Postgres"}, tenthManResult()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(llm.prompt, "<untrusted source=\"objections\">\n[#2] A single primary is a write bottleneck.") || !strings.Contains(llm.system, guard.Instruction) {
+		t.Errorf("expected the turns fenced as untrusted, got system %q and prompt %q", llm.system, llm.prompt)
+	}
+}
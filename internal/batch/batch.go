@@ -0,0 +1,135 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"gopkg.in/yaml.v3"
+)
+
+const summaryFile = "summary.md"
+
+// File is the on-disk batch definition. Defaults fill in any zero fields of
+// the individual jobs.
+type File struct {
+	Defaults runner.Job   `yaml:"defaults"`
+	Jobs     []runner.Job `yaml:"jobs"`
+}
+
+// LoadJobs reads a YAML batch file and returns its jobs with defaults applied.
+func LoadJobs(path string) ([]runner.Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("batch: %w", err)
+	}
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("batch: parsing %s: %w", path, err)
+	}
+	if len(f.Jobs) == 0 {
+		return nil, fmt.Errorf("batch: %s defines no jobs", path)
+	}
+	jobs := make([]runner.Job, len(f.Jobs))
+	for i, job := range f.Jobs {
+		if strings.TrimSpace(job.Topic) == "" {
+			return nil, fmt.Errorf("batch: job %d: topic is required", i+1)
+		}
+		jobs[i] = job.WithDefaults(f.Defaults)
+	}
+	return jobs, nil
+}
+
+// Result records how a single job in the batch finished.
+type Result struct {
+	Job      runner.Job
+	Outcome  *runner.Outcome // nil if the job failed before creating a run directory
+	Err      error
+	Duration time.Duration
+}
+
+// RunFunc executes the job at index i.
+type RunFunc func(ctx context.Context, i int, job runner.Job) (*runner.Outcome, error)
+
+// Run executes jobs with at most concurrency running at once. Results are
+// returned in job order regardless of completion order. A failing job does
+// not stop the others.
+func Run(ctx context.Context, jobs []runner.Job, concurrency int, run RunFunc) []Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]Result, len(jobs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = Result{Job: job, Err: fmt.Errorf("batch: %w", ctx.Err())}
+				return
+			}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			outcome, err := run(ctx, i, job)
+			results[i] = Result{Job: job, Outcome: outcome, Err: err, Duration: time.Since(start)}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// WriteSummary writes an aggregate markdown report of results into dir.
+func WriteSummary(dir string, results []Result) error {
+	var sb strings.Builder
+	succeeded := 0
+	detected := 0
+	for _, r := range results {
+		if r.Err == nil {
+			succeeded++
+			if r.Outcome.Consensus.Detected {
+				detected++
+			}
+		}
+	}
+
+	sb.WriteString("# Batch Summary\n\n")
+	fmt.Fprintf(&sb, "- **Debates:** %d\n", len(results))
+	fmt.Fprintf(&sb, "- **Succeeded:** %d\n", succeeded)
+	fmt.Fprintf(&sb, "- **Failed:** %d\n", len(results)-succeeded)
+	fmt.Fprintf(&sb, "- **Consensus Detected:** %d\n\n", detected)
+
+	sb.WriteString("| # | Topic | Status | Consensus | Score | Rounds | Duration | Output |\n")
+	sb.WriteString("|---|-------|--------|-----------|-------|--------|----------|--------|\n")
+	for i, r := range results {
+		status, cons, score, rounds, dir := "ok", "-", "-", "-", "-"
+		if r.Outcome != nil {
+			dir = filepath.Base(r.Outcome.Dir)
+		}
+		if r.Err != nil {
+			status = "failed: " + strings.ReplaceAll(r.Err.Error(), "|", "\\|")
+		} else {
+			cons = "No"
+			if r.Outcome.Consensus.Detected {
+				cons = "Yes"
+			}
+			score = fmt.Sprintf("%d/10", r.Outcome.Consensus.Score)
+			rounds = fmt.Sprintf("%d", r.Outcome.Result.Transcript.Rounds)
+		}
+		fmt.Fprintf(&sb, "| %d | %s | %s | %s | %s | %s | %s | %s |\n",
+			i+1, strings.ReplaceAll(r.Job.Topic, "|", "\\|"), status, cons, score, rounds, r.Duration.Round(time.Second), dir)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, summaryFile), []byte(sb.String()), 0o644); err != nil {
+		return fmt.Errorf("batch: %w", err)
+	}
+	return nil
+}
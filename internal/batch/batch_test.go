@@ -0,0 +1,129 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jobs.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadJobsAppliesDefaults(t *testing.T) {
+	path := writeFile(t, `
+defaults:
+  agents: 4
+  min_rounds: 2
+  max_rounds: 6
+jobs:
+  - topic: "Should we adopt Rust?"
+  - topic: "Is remote work better?"
+    name: remote
+    agents: 5
+`)
+	jobs, err := LoadJobs(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(jobs))
+	}
+	if jobs[0].Agents != 4 || jobs[0].MinRounds != 2 || jobs[0].MaxRounds != 6 {
+		t.Errorf("defaults not applied: %+v", jobs[0])
+	}
+	if jobs[1].Agents != 5 || jobs[1].Name != "remote" {
+		t.Errorf("job override lost: %+v", jobs[1])
+	}
+}
+
+func TestLoadJobsRequiresTopic(t *testing.T) {
+	path := writeFile(t, "jobs:\n  - name: untitled\n")
+	if _, err := LoadJobs(path); err == nil {
+		t.Fatal("expected error for job without topic")
+	}
+}
+
+func TestLoadJobsEmpty(t *testing.T) {
+	path := writeFile(t, "jobs: []\n")
+	if _, err := LoadJobs(path); err == nil {
+		t.Fatal("expected error for empty jobs list")
+	}
+}
+
+func TestRunBoundsConcurrency(t *testing.T) {
+	jobs := make([]runner.Job, 6)
+	for i := range jobs {
+		jobs[i] = runner.Job{Topic: "t"}
+	}
+
+	var running, peak atomic.Int32
+	results := Run(context.Background(), jobs, 2, func(_ context.Context, i int, _ runner.Job) (*runner.Outcome, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+		if i == 3 {
+			return nil, errors.New("boom")
+		}
+		return &runner.Outcome{}, nil
+	})
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("expected at most 2 concurrent jobs, saw %d", got)
+	}
+	if len(results) != 6 {
+		t.Fatalf("expected 6 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if (r.Err != nil) != (i == 3) {
+			t.Errorf("result %d: unexpected error state %v", i, r.Err)
+		}
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	dir := t.TempDir()
+	results := []Result{
+		{
+			Job: runner.Job{Topic: "Topic A"},
+			Outcome: &runner.Outcome{
+				Dir:       "/out/topic-a-20260101-000000",
+				Result:    &debate.Result{Transcript: &debate.Transcript{Rounds: 5}},
+				Consensus: &debate.ConsensusResult{Detected: true, Score: 8},
+			},
+		},
+		{Job: runner.Job{Topic: "Topic B"}, Err: errors.New("rate limited")},
+	}
+	if err := WriteSummary(dir, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "summary.md"))
+	if err != nil {
+		t.Fatalf("reading summary.md: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{"Topic A", "Topic B", "8/10", "failed: rate limited", "**Succeeded:** 1", "topic-a-20260101-000000"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("summary.md missing %q", want)
+		}
+	}
+}
@@ -178,3 +178,52 @@ func TestLoadDotEnv_MissingFileIsNotError(t *testing.T) {
 		t.Fatalf("missing .env file should not be an error, got: %v", err)
 	}
 }
+
+func TestFile_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenthman", "config.yaml")
+	want := &File{APIKey: "sk-test", OutputDir: "runs", Agents: 5, Models: []string{"a:free", "b:free"}, StopWhen: "round >= 6", JudgeWindow: 3}
+	if err := want.Save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("config file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	got, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.APIKey != "sk-test" || got.OutputDir != "runs" || got.Agents != 5 || len(got.Models) != 2 || got.StopWhen != "round >= 6" || got.JudgeWindow != 3 {
+		t.Errorf("LoadFile() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadFile_MissingAndInvalid(t *testing.T) {
+	if f, err := LoadFile("/nonexistent/config.yaml"); f != nil || err != nil {
+		t.Errorf("missing config file should load as nil, got %+v, %v", f, err)
+	}
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"unknown.yaml": "agent: 5\n",
+		"agents.yaml":  "agents: 2\n",
+		"rounds.yaml":  "min_rounds: 6\nmax_rounds: 3\n",
+	} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0o600)
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("%s: expected error for %q", name, content)
+		}
+	}
+}
+
+func TestDefaultPath_Env(t *testing.T) {
+	t.Setenv("TENTHMAN_CONFIG", "/etc/tenthman.yaml")
+	if path, err := DefaultPath(); err != nil || path != "/etc/tenthman.yaml" {
+		t.Errorf("DefaultPath() = %q, %v", path, err)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the user config file written by `tenthman init`. Its settings
+// replace the built-in defaults; flags and OPENROUTER_API_KEY still win.
+// Zero fields leave the built-in defaults.
+type File struct {
+	APIKey    string `yaml:"api_key,omitempty"`
+	OutputDir string `yaml:"output_dir,omitempty"`
+	Agents    int    `yaml:"agents,omitempty"`
+	MinRounds int    `yaml:"min_rounds,omitempty"`
+	MaxRounds int    `yaml:"max_rounds,omitempty"`
+	// Models are the model IDs debaters are drawn from; empty draws from
+	// every free model.
+	Models []string `yaml:"models,omitempty"`
+	// StopWhen and TenthManWhen are condition expressions, such as
+	// "consensus.score >= 8 && round >= 4 && dissenters == 0", that end the
+	// free debate without the Tenth Man and replace the built-in Tenth Man
+	// activation. They are checked when a debate starts.
+	StopWhen     string `yaml:"stop_when,omitempty"`
+	TenthManWhen string `yaml:"tenth_man_when,omitempty"`
+	// JudgeWindow is how many of the latest rounds the consensus judge
+	// evaluates; 0 evaluates every round.
+	JudgeWindow int `yaml:"judge_window,omitempty"`
+	// AdaptiveRounds sizes the free debate by the velocity of the agreement
+	// score, tuned by TargetRounds, VelocityWindow and MinVelocity, with
+	// min_rounds and max_rounds as hard limits.
+	AdaptiveRounds bool    `yaml:"adaptive_rounds,omitempty"`
+	TargetRounds   int     `yaml:"target_rounds,omitempty"`
+	VelocityWindow int     `yaml:"velocity_window,omitempty"`
+	MinVelocity    float64 `yaml:"min_velocity,omitempty"`
+}
+
+// DefaultPath returns where the user config file lives: $TENTHMAN_CONFIG,
+// or tenthman/config.yaml in the user config directory.
+func DefaultPath() (string, error) {
+	if path := os.Getenv("TENTHMAN_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("config: %w", err)
+	}
+	return filepath.Join(dir, "tenthman", "config.yaml"), nil
+}
+
+// LoadFile reads the config file at path. A missing file is not an error:
+// it returns nil.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	var f File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("config: parsing %s: %w", path, err)
+	}
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Validate checks the settings that are set.
+func (f *File) Validate() error {
+	if f.Agents != 0 && f.Agents < 3 {
+		return fmt.Errorf("agents must be >= 3, got %d", f.Agents)
+	}
+	if f.MinRounds < 0 {
+		return fmt.Errorf("min_rounds must be >= 1, got %d", f.MinRounds)
+	}
+	if f.MaxRounds != 0 && f.MinRounds != 0 && f.MaxRounds < f.MinRounds {
+		return fmt.Errorf("max_rounds (%d) must be >= min_rounds (%d)", f.MaxRounds, f.MinRounds)
+	}
+	if f.JudgeWindow < 0 {
+		return fmt.Errorf("judge_window must be >= 0, got %d", f.JudgeWindow)
+	}
+	if f.TargetRounds < 0 || f.VelocityWindow < 0 || f.MinVelocity < 0 {
+		return fmt.Errorf("target_rounds, velocity_window and min_velocity must be >= 0")
+	}
+	return nil
+}
+
+// Save writes f to path, creating its directory. The file is readable by
+// its owner only, since it may hold the API key.
+func (f *File) Save(path string) error {
+	if err := f.Validate(); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	header := []byte("# Written by `tenthman init`. Flags and OPENROUTER_API_KEY override these settings.\n")
+	if err := os.WriteFile(path, append(header, data...), 0o600); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Package actions turns the final rounds of a debate into recommended
+// actions, open questions and follow-up research.
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/llmjson"
+)
+
+const maxExtractRetries = 3
+
+// DefaultRounds is how many of the last rounds are read. By then the
+// debaters have answered the Tenth Man, so their recommendations are final.
+const DefaultRounds = 3
+
+// Extractor turns a debate transcript into action items using an LLM.
+type Extractor struct {
+	llm    debate.LLMClient
+	model  string
+	rounds int
+}
+
+// NewExtractor creates a new actions Extractor.
+func NewExtractor(llm debate.LLMClient, model string) *Extractor {
+	return &Extractor{llm: llm, model: model, rounds: DefaultRounds}
+}
+
+// SetRounds sets how many of the last rounds are read.
+func (x *Extractor) SetRounds(n int) {
+	if n > 0 {
+		x.rounds = n
+	}
+}
+
+// Extract returns the action items of transcript's final rounds. If the
+// model never returns valid JSON, it returns nil rather than an error.
+func (x *Extractor) Extract(ctx context.Context, transcript *debate.Transcript) (*debate.ActionItems, error) {
+	first := transcript.Rounds - x.rounds + 1
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Debate topic: %s\n\n", transcript.Topic)
+	if first > 1 {
+		fmt.Fprintf(&sb, "Rounds %d to %d of %d:\n\n", first, transcript.Rounds, transcript.Rounds)
+	}
+	var turns strings.Builder
+	for _, turn := range transcript.Turns {
+		if turn.Round >= first {
+			fmt.Fprintf(&turns, "%s (%s): %s\n", turn.Agent.Name, turn.Agent.Role, turn.Content)
+		}
+	}
+	sb.WriteString(guard.Wrap("transcript", turns.String()))
+
+	out, err := llmjson.StructuredCall(ctx, x.llm, x.model, llmjson.Prompt[reply]{
+		System: "You are an operations analyst. Read the final rounds of a debate and list what should happen next. " + guard.Instruction,
+		Schema: `{"actions": [{"description": "...", "rationale": "...", "raised_by": ["..."]}], "open_questions": ["..."], "follow_up_research": ["..."]}`,
+		Notes: `"actions" are concrete steps the debaters recommend, "open_questions" are questions the debate left unresolved, and "follow_up_research" is evidence or analysis someone must gather before deciding.
+Use the agent names exactly as they appear. Use empty lists where the debate offers nothing.`,
+		User:     sb.String(),
+		Required: []string{"actions"},
+		Attempts: maxExtractRetries,
+	})
+	if errors.As(err, new(*llmjson.InvalidError)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("actions: %w", err)
+	}
+	return out.items(), nil
+}
+
+// reply is the model's answer.
+type reply struct {
+	Actions       []debate.Action `json:"actions"`
+	OpenQuestions []string        `json:"open_questions"`
+	Research      []string        `json:"follow_up_research"`
+}
+
+// items returns the action items of r. Actions without a description and
+// blank entries are dropped, and missing lists are returned empty.
+func (r *reply) items() *debate.ActionItems {
+	items := &debate.ActionItems{Actions: []debate.Action{}, OpenQuestions: nonBlank(r.OpenQuestions), Research: nonBlank(r.Research)}
+	for _, a := range r.Actions {
+		if a.Description = strings.TrimSpace(a.Description); a.Description != "" {
+			items.Actions = append(items.Actions, a)
+		}
+	}
+	return items
+}
+
+func nonBlank(list []string) []string {
+	out := []string{}
+	for _, s := range list {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+type mockLLM struct {
+	responses []string
+	err       error
+	calls     int
+	system    string
+	prompt    string
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.system, m.prompt = msgs[0].Content, msgs[1].Content
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+func sampleTranscript() *debate.Transcript {
+	return &debate.Transcript{
+		Topic:  "test topic",
+		Rounds: 4,
+		Turns: []debate.Turn{
+			{Round: 1, Agent: debate.Agent{Name: "Alice", Role: "debater"}, Content: "Opening remarks."},
+			{Round: 3, Agent: debate.Agent{Name: "Alice", Role: "debater"}, Content: "Caching cuts latency."},
+			{Round: 4, Agent: debate.Agent{Name: "Tenth Man", Role: "tenth-man"}, Content: "Caching adds staleness."},
+		},
+	}
+}
+
+func TestExtractActions(t *testing.T) {
+	llm := &mockLLM{responses: []string{"```json\n" + `{"actions": [{"description": "Add a cache", "rationale": "latency", "raised_by": ["Alice"]}, {"description": " "}], "open_questions": ["How stale is too stale?", ""]}` + "\n```"}}
+	x := NewExtractor(llm, "m")
+	x.SetRounds(2)
+	got, err := x.Extract(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || len(got.Actions) != 1 || got.Actions[0].Description != "Add a cache" || got.Actions[0].RaisedBy[0] != "Alice" {
+		t.Fatalf("unexpected actions %+v", got)
+	}
+	if len(got.OpenQuestions) != 1 || got.Research == nil || len(got.Research) != 0 {
+		t.Errorf("expected blank entries dropped and missing lists empty, got %+v", got)
+	}
+	if strings.Contains(llm.prompt, "Opening remarks") || !strings.Contains(llm.prompt, "Rounds 3 to 4 of 4") || !strings.Contains(llm.prompt, "Tenth Man (tenth-man): Caching adds staleness.") {
+		t.Errorf("expected only the last 2 rounds, got %q", llm.prompt)
+	}
+}
+
+func TestExtractRetriesThenGivesUp(t *testing.T) {
+	llm := &mockLLM{responses: []string{`{"open_questions": []}`}}
+	got, err := NewExtractor(llm, "m").Extract(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != nil || llm.calls != maxExtractRetries {
+		t.Errorf("expected nothing after %d calls, got %+v after %d", maxExtractRetries, got, llm.calls)
+	}
+}
+
+func TestExtractError(t *testing.T) {
+	llm := &mockLLM{err: errors.New("boom")}
+	if _, err := NewExtractor(llm, "m").Extract(context.Background(), sampleTranscript()); err == nil {
+		t.Error("expected the client error")
+	}
+}
+
+func TestExtractFencesTurns(t *testing.T) {
+	llm := &mockLLM{responses: []string{`{"actions": []}`}}
+	if _, err := NewExtractor(llm, "m").Extract(context.Background(), sampleTranscript()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(llm.prompt, "<untrusted source=\"transcript\">\nAlice (debater): Caching cuts latency.") || !strings.Contains(llm.system, guard.Instruction) {
+		t.Errorf("expected the turns fenced as untrusted, got system %q and prompt %q", llm.system, llm.prompt)
+	}
+}
@@ -0,0 +1,68 @@
+package debate
+
+import "fmt"
+
+// Defaults for AdaptiveRounds.
+const (
+	DefaultVelocityWindow = 2
+	DefaultMinVelocity    = 0.5
+)
+
+// AdaptiveRounds tunes the length of Phase 1 to the velocity of the judge's
+// agreement score, its change per round over the latest evaluations. The
+// engine's minimum and maximum rounds stay hard limits; between them, a
+// debate whose score is rising keeps going past Target, one whose score is
+// falling ends at once, and one whose score holds steady ends at Target.
+// Phase 1 always ends as soon as consensus is reached.
+type AdaptiveRounds struct {
+	Target      int     // rounds Phase 1 lasts while the score holds steady; 0 is halfway between the minimum and maximum
+	Window      int     // evaluations the velocity is measured over; 0 is DefaultVelocityWindow
+	MinVelocity float64 // agreement points per round that count as rising or falling; 0 is DefaultMinVelocity
+}
+
+// SetAdaptiveRounds lets a decide when Phase 1 ends between the minimum and
+// maximum rounds. A nil a runs Phase 1 until consensus or the maximum
+// rounds, as usual.
+func (e *Engine) SetAdaptiveRounds(a *AdaptiveRounds) {
+	e.adaptive = a
+}
+
+// Velocity returns the change per round of the agreement score over the
+// last window evaluations in scores, and false if there are not that many.
+func Velocity(scores []RoundScore, window int) (float64, bool) {
+	if window < 1 || len(scores) <= window {
+		return 0, false
+	}
+	first, last := scores[len(scores)-1-window], scores[len(scores)-1]
+	if last.Round <= first.Round {
+		return 0, false
+	}
+	return float64(last.Score-first.Score) / float64(last.Round-first.Round), true
+}
+
+// settled reports why Phase 1 should end after round, judged by scores, or
+// "" if it should go on.
+func (a AdaptiveRounds) settled(round, minRounds, maxRounds int, scores []RoundScore) string {
+	window := a.Window
+	if window == 0 {
+		window = DefaultVelocityWindow
+	}
+	minVelocity := a.MinVelocity
+	if minVelocity == 0 {
+		minVelocity = DefaultMinVelocity
+	}
+	target := a.Target
+	if target == 0 {
+		target = (minRounds + maxRounds + 1) / 2
+	}
+	v, ok := Velocity(scores, window)
+	switch {
+	case !ok || v >= minVelocity:
+		return ""
+	case v <= -minVelocity:
+		return fmt.Sprintf("the agreement score is falling (%+.1f per round)", v)
+	case round >= target:
+		return fmt.Sprintf("the agreement score has stalled (%+.1f per round) at the target of %d rounds", v, target)
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package claims
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/llmjson"
+)
+
+const maxExtractRetries = 3
+
+// Extractor turns a debate transcript into discrete claims using an LLM.
+type Extractor struct {
+	llm   debate.LLMClient
+	model string
+}
+
+// NewExtractor creates a new claims Extractor.
+func NewExtractor(llm debate.LLMClient, model string) *Extractor {
+	return &Extractor{llm: llm, model: model}
+}
+
+// Extract returns the claims made in transcript. If the model never returns
+// valid JSON, it returns no claims rather than an error.
+func (x *Extractor) Extract(ctx context.Context, transcript *debate.Transcript) ([]debate.Claim, error) {
+	var sb strings.Builder
+	for _, turn := range transcript.Turns {
+		fmt.Fprintf(&sb, "%s (%s): %s\n", turn.Agent.Name, turn.Agent.Role, turn.Content)
+	}
+
+	out, err := llmjson.StructuredCall(ctx, x.llm, x.model, llmjson.Prompt[struct {
+		Claims []debate.Claim `json:"claims"`
+	}]{
+		System:   "You are a claims analyst. Break the debate transcript into the discrete claims that were argued. " + guard.Instruction,
+		Schema:   `{"claims": [{"statement": "...", "supporting_agents": ["..."], "opposing_agents": ["..."], "tenth_man_rebuttals": ["..."]}]}`,
+		Notes:    `Use the agent names exactly as they appear. "tenth_man_rebuttals" lists the Tenth Man's counter-arguments to that claim, if any.`,
+		User:     guard.Wrap("transcript", sb.String()),
+		Required: []string{"claims"},
+		Attempts: maxExtractRetries,
+	})
+	if errors.As(err, new(*llmjson.InvalidError)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claims: %w", err)
+	}
+	return out.Claims, nil
+}
@@ -0,0 +1,84 @@
+package claims
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+type mockLLM struct {
+	responses []string
+	err       error
+	calls     int
+	system    string
+	prompt    string
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.system, m.prompt = msgs[0].Content, msgs[1].Content
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+func sampleTranscript() *debate.Transcript {
+	return &debate.Transcript{
+		Topic: "test topic",
+		Turns: []debate.Turn{
+			{Round: 1, Agent: debate.Agent{Name: "Alice", Role: "debater"}, Content: "Caching cuts latency."},
+			{Round: 2, Agent: debate.Agent{Name: "Tenth Man", Role: "tenth-man"}, Content: "Caching adds staleness."},
+		},
+	}
+}
+
+func TestExtractClaims(t *testing.T) {
+	llm := &mockLLM{responses: []string{"```json\n" + `{"claims": [{"statement": "Caching cuts latency", "supporting_agents": ["Alice"], "opposing_agents": [], "tenth_man_rebuttals": ["Caching adds staleness"]}]}` + "\n```"}}
+	got, err := NewExtractor(llm, "m").Extract(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].Statement != "Caching cuts latency" || got[0].Supporters[0] != "Alice" || got[0].Rebuttals[0] != "Caching adds staleness" {
+		t.Errorf("unexpected claims %+v", got)
+	}
+	if !strings.Contains(llm.prompt, "Tenth Man (tenth-man): Caching adds staleness.") {
+		t.Errorf("transcript should label roles, got %q", llm.prompt)
+	}
+}
+
+func TestExtractRetriesThenGivesUp(t *testing.T) {
+	llm := &mockLLM{responses: []string{"not json"}}
+	got, err := NewExtractor(llm, "m").Extract(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != nil || llm.calls != maxExtractRetries {
+		t.Errorf("expected no claims after %d attempts, got %v after %d", maxExtractRetries, got, llm.calls)
+	}
+}
+
+func TestExtractPropagatesLLMError(t *testing.T) {
+	llm := &mockLLM{err: errors.New("boom")}
+	if _, err := NewExtractor(llm, "m").Extract(context.Background(), sampleTranscript()); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestExtractFencesTurns(t *testing.T) {
+	llm := &mockLLM{responses: []string{`{"claims": []}`}}
+	if _, err := NewExtractor(llm, "m").Extract(context.Background(), sampleTranscript()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(llm.prompt, "<untrusted source=\"transcript\">\nAlice (debater): Caching cuts latency.") || !strings.Contains(llm.system, guard.Instruction) {
+		t.Errorf("expected the turns fenced as untrusted, got system %q and prompt %q", llm.system, llm.prompt)
+	}
+}
@@ -0,0 +1,95 @@
+package debate
+
+import "fmt"
+
+// Facts describe a free debate at a consensus check, for the conditions set
+// with SetStopCondition and SetTenthManCondition.
+type Facts struct {
+	Round       int  // rounds done
+	MinRounds   int  // the engine's minimum rounds
+	MaxRounds   int  // the engine's maximum rounds
+	Agents      int  // debaters still in the debate
+	Detected    bool // the judge detected a consensus
+	Score       int  // the judge's agreement score, 0-10
+	Dissenters  int  // agents the judge named as dissenting
+	StaleRounds int  // consecutive rounds that added little new content
+}
+
+// Env returns f as the variables of a condition expression: round,
+// min_rounds, max_rounds, agents, consensus.detected, consensus.score,
+// dissenters and stale_rounds.
+func (f Facts) Env() map[string]any {
+	return map[string]any{
+		"round":              f.Round,
+		"min_rounds":         f.MinRounds,
+		"max_rounds":         f.MaxRounds,
+		"agents":             f.Agents,
+		"consensus.detected": f.Detected,
+		"consensus.score":    f.Score,
+		"dissenters":         f.Dissenters,
+		"stale_rounds":       f.StaleRounds,
+	}
+}
+
+// Condition decides something about a debate from its Facts.
+type Condition func(Facts) (bool, error)
+
+// SetStopCondition ends the free debate at the first consensus check where
+// c holds, without the Tenth Man, as stagnation does. It is checked before
+// the Tenth Man's activation. A nil c disables it.
+func (e *Engine) SetStopCondition(c Condition) {
+	e.stopWhen = c
+}
+
+// SetTenthManCondition replaces the built-in Tenth Man activation, a
+// detected consensus scoring at least ConsensusThreshold, with c. The Tenth
+// Man challenges the judge's consensus position, so c is only consulted
+// once the judge has named one. A nil c restores the built-in activation.
+func (e *Engine) SetTenthManCondition(c Condition) {
+	e.tenthManWhen = c
+}
+
+// facts returns the facts of the debate so far.
+func (m *Machine) facts() Facts {
+	e := m.engine
+	f := Facts{
+		Round:       e.transcript.Rounds,
+		MinRounds:   e.minRounds,
+		MaxRounds:   e.maxRounds,
+		Agents:      len(e.agents),
+		StaleRounds: m.staleRounds,
+	}
+	if c := m.Consensus; c != nil {
+		f.Detected = c.Detected
+		f.Score = c.Score
+		f.Dissenters = len(c.Dissenters)
+	}
+	return f
+}
+
+// stop reports whether the stop condition holds.
+func (m *Machine) stop() (bool, error) {
+	if m.engine.stopWhen == nil {
+		return false, nil
+	}
+	ok, err := m.engine.stopWhen(m.facts())
+	if err != nil {
+		return false, fmt.Errorf("debate: stop condition: %w", err)
+	}
+	return ok, nil
+}
+
+// activate reports whether the Tenth Man joins now.
+func (m *Machine) activate() (bool, error) {
+	if m.engine.tenthManWhen == nil {
+		return m.reached(), nil
+	}
+	if m.Consensus == nil || m.Consensus.Position == "" {
+		return false, nil
+	}
+	ok, err := m.engine.tenthManWhen(m.facts())
+	if err != nil {
+		return false, fmt.Errorf("debate: Tenth Man condition: %w", err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,67 @@
+package debate
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const confidenceInstruction = `End your response with a final line "CONFIDENCE: N", where N from 0 to 100 is how likely you think your current position is correct. Be calibrated: 70 should mean you expect to be right about 70% of the time.`
+
+// confidenceRe matches a "CONFIDENCE: N" line, tolerating markdown emphasis
+// and a trailing percent sign.
+var confidenceRe = regexp.MustCompile(`(?im)^[ \t*_]*confidence[ \t*_]*:[ \t*_]*(\d{1,3})[ \t]*%?[ \t*_]*$`)
+
+// RoundConfidence aggregates the confidence debaters reported in one round.
+type RoundConfidence struct {
+	Round    int
+	Mean     float64 // average reported confidence, 0-100
+	Spread   float64 // standard deviation of the reported values
+	Reported int     // number of debaters that reported a value
+}
+
+// parseConfidence extracts the last "CONFIDENCE: N" line from content and
+// returns it with that line removed. It returns nil if there is no valid
+// value.
+func parseConfidence(content string) (*int, string) {
+	matches := confidenceRe.FindAllStringSubmatchIndex(content, -1)
+	if matches == nil {
+		return nil, content
+	}
+	m := matches[len(matches)-1]
+	n, err := strconv.Atoi(content[m[2]:m[3]])
+	if err != nil || n > 100 {
+		return nil, content
+	}
+	return &n, strings.TrimSpace(content[:m[0]] + content[m[1]:])
+}
+
+// roundConfidence aggregates the confidence reported by debaters in round.
+// ok is false if none reported one.
+func roundConfidence(turns []Turn, round int) (rc RoundConfidence, ok bool) {
+	var values []float64
+	for _, turn := range turns {
+		if turn.Round == round && turn.Agent.Role != "tenth-man" && turn.Confidence != nil {
+			values = append(values, float64(*turn.Confidence))
+		}
+	}
+	if len(values) == 0 {
+		return RoundConfidence{}, false
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return RoundConfidence{
+		Round:    round,
+		Mean:     mean,
+		Spread:   math.Sqrt(variance / float64(len(values))),
+		Reported: len(values),
+	}, true
+}
@@ -0,0 +1,129 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/tokens"
+)
+
+// defaultMaxTranscript is the longest transcript, in tokens, that the judge
+// reads verbatim. It leaves room in the smallest free models' context.
+const defaultMaxTranscript = 6000
+
+// summaryExcerptLength bounds each turn in a round the summarizer returned
+// nothing for.
+const summaryExcerptLength = 200
+
+// SetMaxTranscript sets the longest transcript, in tokens of the judge's
+// model, the judge reads verbatim. Longer transcripts are judged map-reduce
+// style: every round but the latest is first summarized to one line per
+// agent, and the judge reads those summaries followed by the latest round in
+// full. Summaries are cached, so each round is summarized once. 0 always
+// sends the full transcript.
+func (j *Judge) SetMaxTranscript(n int) {
+	j.maxTranscript = n
+}
+
+// transcriptText renders transcript for the judge, summarizing earlier rounds
+// when it is too long to send verbatim.
+func (j *Judge) transcriptText(ctx context.Context, transcript *debate.Transcript) (string, error) {
+	full := renderTurns(transcript.Turns)
+	if j.maxTranscript <= 0 || tokens.Count(j.model, full) <= j.maxTranscript {
+		return full, nil
+	}
+
+	var rounds [][]debate.Turn
+	for _, turn := range transcript.Turns {
+		if n := len(rounds); n == 0 || rounds[n-1][0].Round != turn.Round {
+			rounds = append(rounds, nil)
+		}
+		rounds[len(rounds)-1] = append(rounds[len(rounds)-1], turn)
+	}
+	if len(rounds) < 2 {
+		return full, nil
+	}
+
+	var sb strings.Builder
+	for _, turns := range rounds[:len(rounds)-1] {
+		summary, err := j.summarize(ctx, transcript.Topic, turns)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "Round %d (summary):\n%s\n\n", turns[0].Round, summary)
+	}
+	latest := rounds[len(rounds)-1]
+	fmt.Fprintf(&sb, "Round %d (latest, in full):\n%s", latest[0].Round, renderTurns(latest))
+	return sb.String(), nil
+}
+
+// summarize returns one line per agent summarizing the position each took in
+// a round's turns.
+func (j *Judge) summarize(ctx context.Context, topic string, turns []debate.Turn) (string, error) {
+	text := renderTurns(turns)
+	h := fnv.New64a()
+	h.Write([]byte(text))
+	key := h.Sum64()
+
+	j.mu.Lock()
+	summary, ok := j.summaries[key]
+	j.mu.Unlock()
+	if ok {
+		return summary, nil
+	}
+
+	msgs := []openrouter.Message{
+		{
+			Role: "system",
+			Content: `You are a debate summarizer. For the debate round below, write one line per agent: the agent's name exactly as given, a colon, and the position it took in at most 25 words, including whom it agreed or disagreed with.
+Write ` + noResponse + ` for agents shown without one. Return ONLY those lines.
+` + guard.Instruction,
+		},
+		{Role: "user", Content: fmt.Sprintf("Topic: %s\n\nRound %d:\n%s", topic, turns[0].Round, guard.Wrap(fmt.Sprintf("round %d", turns[0].Round), text))},
+	}
+	resp, err := j.llm.ChatCompletion(ctx, j.model, msgs)
+	if err != nil {
+		return "", fmt.Errorf("consensus: summarizing round %d: %w", turns[0].Round, err)
+	}
+	if len(resp.Choices) > 0 {
+		summary = strings.TrimSpace(resp.Choices[0].Message.Content)
+	}
+	if summary == "" {
+		var sb strings.Builder
+		for _, turn := range turns {
+			fmt.Fprintf(&sb, "%s: %s\n", turn.Agent.Name, truncate(turnContent(turn), summaryExcerptLength))
+		}
+		summary = strings.TrimSpace(sb.String())
+	}
+
+	j.mu.Lock()
+	j.summaries[key] = summary
+	j.mu.Unlock()
+	return summary, nil
+}
+
+// renderTurns writes turns as one "Name: content" line each.
+func renderTurns(turns []debate.Turn) string {
+	var sb strings.Builder
+	for _, turn := range turns {
+		fmt.Fprintf(&sb, "%s: %s\n", turn.Agent.Name, turnContent(turn))
+	}
+	return sb.String()
+}
+
+// turnContent returns turn's content, debate.PassNote if the agent passed,
+// or noResponse if it has none worth judging.
+func turnContent(turn debate.Turn) string {
+	if turn.Passed {
+		return debate.PassNote
+	}
+	if !debate.Substantive(turn.Content) {
+		return noResponse
+	}
+	return turn.Content
+}
@@ -0,0 +1,146 @@
+package consensus
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// overlapThreshold is the cosine similarity of word counts above which two
+// debaters' latest turns are considered to argue the same position.
+const overlapThreshold = 0.3
+
+// FallbackJudge implements debate.ConsensusJudge deterministically, for when
+// the LLM judge cannot produce a verdict. It combines two signals from each
+// debater's latest turn: the keyword vote of KeywordJudge, and how many
+// debaters fall into the largest group of turns linked by vocabulary overlap.
+// Overlap is the cosine similarity of word counts, so turns that make the
+// same point in different words are not grouped; no embeddings model is
+// called. The score is the mean of the agreeing share of votes and the
+// largest group's share of debaters, on a 0-10 scale. Debaters who vote
+// against or sit outside that group are dissenters.
+type FallbackJudge struct{}
+
+// NewFallbackJudge creates a FallbackJudge.
+func NewFallbackJudge() *FallbackJudge {
+	return &FallbackJudge{}
+}
+
+// Evaluate implements debate.ConsensusJudge.
+func (j *FallbackJudge) Evaluate(_ context.Context, transcript *debate.Transcript) (*debate.ConsensusResult, error) {
+	result := &debate.ConsensusResult{Dissenters: []string{}, Fallback: true}
+	turns := latestTurns(transcript)
+	if len(turns) == 0 {
+		return result, nil
+	}
+
+	group := largestOverlapGroup(turns)
+	agree, voters := 0, 0
+	for i, turn := range turns {
+		vote := keywordVote(turn.Content)
+		if vote != 0 {
+			voters++
+		}
+		if vote > 0 {
+			agree++
+		}
+		switch {
+		case vote < 0 || !group[i]:
+			result.Dissenters = append(result.Dissenters, turn.Agent.Name)
+		case vote > 0 || result.Position == "":
+			result.Position = firstSentence(turn.Content)
+		}
+	}
+
+	var voteShare float64
+	if voters > 0 {
+		voteShare = float64(agree) / float64(voters)
+	}
+	groupShare := float64(len(group)) / float64(len(turns))
+	result.Score = int(math.Round(5 * (voteShare + groupShare)))
+	result.Detected = result.Score >= debate.ConsensusThreshold
+	if !result.Detected {
+		result.Position = ""
+	}
+	return result, nil
+}
+
+// largestOverlapGroup groups turns by vocabulary overlap, linking any two
+// whose word counts have a cosine similarity of at least overlapThreshold,
+// and returns the indexes of the largest group. Ties go to the group that
+// spoke first.
+func largestOverlapGroup(turns []debate.Turn) map[int]bool {
+	counts := make([]map[string]float64, len(turns))
+	for i, turn := range turns {
+		counts[i] = wordCounts(turn.Content)
+	}
+
+	group := make([]int, len(turns)) // union-find parents
+	for i := range group {
+		group[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		for group[i] != i {
+			i = group[i]
+		}
+		return i
+	}
+	for i := range turns {
+		for k := i + 1; k < len(turns); k++ {
+			if cosine(counts[i], counts[k]) >= overlapThreshold {
+				group[root(k)] = root(i)
+			}
+		}
+	}
+
+	sizes := make(map[int]int)
+	for i := range turns {
+		sizes[root(i)]++
+	}
+	best := root(0)
+	for i := range turns {
+		if sizes[root(i)] > sizes[best] {
+			best = root(i)
+		}
+	}
+	members := make(map[int]bool)
+	for i := range turns {
+		if root(i) == best {
+			members[i] = true
+		}
+	}
+	return members
+}
+
+// wordCounts counts the words of at least four letters in content.
+func wordCounts(content string) map[string]float64 {
+	v := make(map[string]float64)
+	for _, w := range strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) >= 4 {
+			v[w]++
+		}
+	}
+	return v
+}
+
+// cosine returns the cosine similarity of two word counts.
+func cosine(a, b map[string]float64) float64 {
+	var dot, na, nb float64
+	for w, x := range a {
+		dot += x * b[w]
+		na += x * x
+	}
+	for _, y := range b {
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
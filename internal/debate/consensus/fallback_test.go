@@ -0,0 +1,44 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+func TestFallbackJudge(t *testing.T) {
+	transcript := &debate.Transcript{Turns: []debate.Turn{
+		{Agent: debate.Agent{Name: "Alice"}, Content: "Remote work raises productivity for focused engineering teams. I agree."},
+		{Agent: debate.Agent{Name: "Bob"}, Content: "I agree remote work raises productivity for engineering teams."},
+		{Agent: debate.Agent{Name: "Carol"}, Content: "Remote work raises productivity for most engineering teams, I support it."},
+		{Agent: debate.Agent{Name: "Dave"}, Content: "Office culture matters more than anything written above."},
+	}}
+	result, err := NewFallbackJudge().Evaluate(context.Background(), transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 of 3 votes agree and 3 of 4 debaters overlap: (1 + 0.75) * 5.
+	if !result.Fallback || !result.Detected || result.Score != 9 {
+		t.Errorf("expected a detected fallback consensus scoring 9, got %+v", result)
+	}
+	if len(result.Dissenters) != 1 || result.Dissenters[0] != "Dave" {
+		t.Errorf("expected Dave, outside the overlap group, to dissent, got %v", result.Dissenters)
+	}
+	if result.Position != "Remote work raises productivity for most engineering teams, I support it." {
+		t.Errorf("expected the latest agreeing turn in the overlap group, got %q", result.Position)
+	}
+
+	transcript.Turns = append(transcript.Turns,
+		debate.Turn{Agent: debate.Agent{Name: "Bob"}, Content: "I disagree now: the productivity data is flawed."},
+		debate.Turn{Agent: debate.Agent{Name: "Carol"}, Content: "Wrong framing; commuting costs dominate."},
+	)
+	result, _ = NewFallbackJudge().Evaluate(context.Background(), transcript)
+	if result.Detected || result.Position != "" {
+		t.Errorf("expected no consensus once the debaters split, got %+v", result)
+	}
+
+	if result, _ := NewFallbackJudge().Evaluate(context.Background(), &debate.Transcript{}); result.Score != 0 || result.Detected {
+		t.Errorf("expected an empty transcript to score 0, got %+v", result)
+	}
+}
@@ -2,96 +2,222 @@ package consensus
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"regexp"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
-	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/llmjson"
 )
 
 const maxJudgeRetries = 3
 
-var codeBlockRe = regexp.MustCompile("(?s)```(?:json)?\\s*\\n?(.*?)\\n?```")
+// noResponse stands in for a turn with no substantive content in the
+// transcript shown to the judge.
+const noResponse = "[no substantive response]"
+
+// maxDiagnosticResponse caps how much of a rejected judge response is kept
+// in the transcript.
+const maxDiagnosticResponse = 500
 
 // Judge evaluates debate transcripts for consensus using an LLM.
 type Judge struct {
-	llm   debate.LLMClient
-	model string
+	llm           debate.LLMClient
+	model         string
+	strict        bool
+	fallback      debate.ConsensusJudge
+	maxTranscript int
+	window        int
+
+	mu        sync.Mutex
+	summaries map[uint64]string // round summaries by hash of the round's text
 }
 
-// NewJudge creates a new consensus Judge.
+// NewJudge creates a new consensus Judge. When no attempt returns a
+// parseable verdict, it falls back to a FallbackJudge.
 func NewJudge(llm debate.LLMClient, model string) *Judge {
-	return &Judge{llm: llm, model: model}
+	return &Judge{
+		llm:           llm,
+		model:         model,
+		fallback:      NewFallbackJudge(),
+		maxTranscript: defaultMaxTranscript,
+		summaries:     make(map[uint64]string),
+	}
+}
+
+// SetWindow makes the judge evaluate only the last rounds rounds of the
+// transcript, so early exploratory disagreement does not mask later
+// convergence. 0, the default, evaluates every round.
+func (j *Judge) SetWindow(rounds int) {
+	j.window = rounds
+}
+
+// SetStrict makes Evaluate fail with debate.ErrConsensusParse when no attempt
+// returns a parseable verdict, instead of using the fallback judge.
+func (j *Judge) SetStrict(strict bool) {
+	j.strict = strict
+}
+
+// SetFallback replaces the judge used when no attempt returns a parseable
+// verdict. With nil, such a debate is judged to have no consensus.
+func (j *Judge) SetFallback(fallback debate.ConsensusJudge) {
+	j.fallback = fallback
 }
 
 // Evaluate implements debate.ConsensusJudge.
 func (j *Judge) Evaluate(ctx context.Context, transcript *debate.Transcript) (*debate.ConsensusResult, error) {
-	system := openrouter.Message{
-		Role: "system",
-		Content: `You are a consensus judge. Analyze the debate transcript and return ONLY valid JSON in this exact format:
-{"consensus_detected": bool, "consensus_position": "...", "agreement_score": 1-10, "dissenting_agents": ["..."]}
-Do NOT include any other text, explanation, or markdown formatting. Return ONLY the JSON object.`,
+	recent := recentRounds(transcript, j.window)
+	text, err := j.transcriptText(ctx, recent)
+	if err != nil {
+		return nil, err
+	}
+	text = guard.Wrap("transcript", text)
+	if recent != transcript {
+		text = fmt.Sprintf("Only the last %d of %d rounds are shown; judge where the debate stands now.\n\n%s", j.window, transcript.Rounds, text)
 	}
+	if note := removalNote(transcript.Removals); note != "" {
+		text = note + "\n\n" + text
+	}
+	if guidance := transcript.TopicClass.JudgeGuidance(); guidance != "" {
+		text = guidance + "\n\n" + text
+	}
+	roster := slices.DeleteFunc(debaterNames(recent), func(name string) bool {
+		return slices.ContainsFunc(transcript.Removals, func(r debate.AgentRemoval) bool { return r.Agent == name })
+	})
+
+	var diagnostics []debate.JudgeDiagnostic
+	result, err := llmjson.StructuredCall(ctx, j.llm, j.model, llmjson.Prompt[debate.ConsensusResult]{
+		System: "You are a consensus judge. Analyze the debate transcript. " + guard.Instruction,
+		Schema: `{"consensus_detected": bool, "consensus_position": "...", "agreement_score": 1-10, "dissenting_agents": ["..."], "agent_scores": {"<agent name>": 1-10}}`,
+		Notes: `"agent_scores" rates how far each agent agrees with the consensus position, or with the majority view if there is none: 10 is full agreement, 1 is strong dissent.
+Turns shown as ` + noResponse + ` are agents that failed to answer. Silence is not agreement: score agreement only among agents who actually argued, and lower the score when many did not.
+Turns shown as ` + debate.PassNote + ` are agents with nothing new to add: they still hold the position of their previous turn, so count it as theirs.`,
+		User:     text,
+		Required: []string{"consensus_detected", "agreement_score"},
+		Validate: func(r *debate.ConsensusResult) error { return validateConsensus(r, roster) },
+		Attempts: maxJudgeRetries,
+		OnReject: func(attempt int, raw string, err error) {
+			diagnostics = append(diagnostics, debate.JudgeDiagnostic{Attempt: attempt, Error: err.Error(), Response: truncate(raw, maxDiagnosticResponse)})
+		},
+	})
+	var invalid *llmjson.InvalidError
+	switch {
+	case err == nil:
+		result.Diagnostics = diagnostics
+		return result, nil
+	case !errors.As(err, &invalid):
+		return nil, fmt.Errorf("consensus: %w", err)
+	case j.strict:
+		return nil, fmt.Errorf("consensus: %w after %d attempts: %v", debate.ErrConsensusParse, invalid.Attempts, invalid.Err)
+	}
+	result = &debate.ConsensusResult{}
+	if j.fallback != nil {
+		if result, err = j.fallback.Evaluate(ctx, recent); err != nil {
+			return nil, fmt.Errorf("consensus: fallback: %w", err)
+		}
+	}
+	result.Diagnostics = diagnostics
+	return result, nil
+}
 
-	var sb strings.Builder
+// recentRounds returns a copy of transcript holding only the turns of its
+// last window rounds, or transcript itself if window covers every round.
+func recentRounds(transcript *debate.Transcript, window int) *debate.Transcript {
+	if window <= 0 || transcript.Rounds <= window {
+		return transcript
+	}
+	first := transcript.Rounds - window + 1
+	recent := *transcript
+	recent.Turns = nil
 	for _, turn := range transcript.Turns {
-		fmt.Fprintf(&sb, "%s: %s\n", turn.Agent.Name, turn.Content)
+		if turn.Round >= first {
+			recent.Turns = append(recent.Turns, turn)
+		}
 	}
-	user := openrouter.Message{Role: "user", Content: sb.String()}
+	return &recent
+}
 
-	for attempt := range maxJudgeRetries {
-		if err := ctx.Err(); err != nil {
-			return nil, fmt.Errorf("consensus: %w", err)
+// validateConsensus checks that result's agreement scores are in range and
+// that every dissenter and scored agent is one of roster, normalizing the
+// spelling of their names. An overall score of 0 is raised to the minimum of
+// 1. An empty roster skips the name checks.
+func validateConsensus(result *debate.ConsensusResult, roster []string) error {
+	var errs []error
+	switch {
+	case result.Score < 0 || result.Score > 10:
+		errs = append(errs, fmt.Errorf("agreement_score %d is outside 1-10", result.Score))
+	case result.Score == 0:
+		result.Score = 1
+	}
+	rosterName := func(name string) (string, error) {
+		if len(roster) == 0 {
+			return name, nil
 		}
-
-		msgs := []openrouter.Message{system, user}
-		if attempt > 0 {
-			msgs = append(msgs, openrouter.Message{
-				Role:    "user",
-				Content: "Your previous response was not valid JSON. Return ONLY a JSON object, no markdown, no explanation.",
-			})
+		if i := slices.IndexFunc(roster, func(r string) bool { return strings.EqualFold(r, strings.TrimSpace(name)) }); i >= 0 {
+			return roster[i], nil
 		}
-
-		resp, err := j.llm.ChatCompletion(ctx, j.model, msgs)
+		return "", fmt.Errorf("%q is not one of %s", name, strings.Join(roster, ", "))
+	}
+	for i, name := range result.Dissenters {
+		known, err := rosterName(name)
 		if err != nil {
-			return nil, fmt.Errorf("consensus: %w", err)
+			errs = append(errs, fmt.Errorf("dissenter %w", err))
+			continue
 		}
-
-		raw := resp.Choices[0].Message.Content
-		result, ok := parseConsensusJSON(raw)
-		if ok {
-			return result, nil
+		result.Dissenters[i] = known
+	}
+	if len(result.AgentScores) > 0 {
+		scores := make(map[string]int, len(result.AgentScores))
+		for name, score := range result.AgentScores {
+			known, err := rosterName(name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("agent_scores: %w", err))
+				continue
+			}
+			if score < 1 || score > 10 {
+				errs = append(errs, fmt.Errorf("agent_scores: %s's score %d is outside 1-10", known, score))
+				continue
+			}
+			scores[known] = score
 		}
+		result.AgentScores = scores
 	}
-
-	return &debate.ConsensusResult{}, nil
+	return errors.Join(errs...)
 }
 
-// parseConsensusJSON tries to extract and parse a ConsensusResult from LLM output.
-func parseConsensusJSON(raw string) (*debate.ConsensusResult, bool) {
-	// Try direct parse first
-	var result debate.ConsensusResult
-	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &result); err == nil {
-		return &result, true
-	}
-
-	// Try extracting from markdown code block
-	if matches := codeBlockRe.FindStringSubmatch(raw); len(matches) > 1 {
-		if err := json.Unmarshal([]byte(strings.TrimSpace(matches[1])), &result); err == nil {
-			return &result, true
+// debaterNames returns the names of the agents who spoke in transcript, in
+// order of first appearance. Moderator notes are skipped.
+func debaterNames(transcript *debate.Transcript) []string {
+	var names []string
+	for _, turn := range transcript.Turns {
+		if turn.Agent.Role != "moderator" && !slices.Contains(names, turn.Agent.Name) {
+			names = append(names, turn.Agent.Name)
 		}
 	}
+	return names
+}
 
-	// Try finding JSON object in text (first { to last })
-	start := strings.Index(raw, "{")
-	end := strings.LastIndex(raw, "}")
-	if start >= 0 && end > start {
-		if err := json.Unmarshal([]byte(raw[start:end+1]), &result); err == nil {
-			return &result, true
-		}
+// removalNote tells the judge which agents were removed from the debate, so
+// their earlier turns do not count; "" if none were.
+func removalNote(removals []debate.AgentRemoval) string {
+	if len(removals) == 0 {
+		return ""
 	}
+	var sb strings.Builder
+	sb.WriteString("These agents were removed from the debate. Ignore their turns: do not count them towards consensus, list them as dissenters or score them.\n")
+	for _, r := range removals {
+		fmt.Fprintf(&sb, "- %s, from round %d: %s\n", r.Agent, r.Round, r.Reason)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
 
-	return nil, false
+// truncate shortens s to at most n bytes, marking the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
 }
@@ -3,9 +3,12 @@ package consensus
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
 )
 
@@ -14,7 +17,7 @@ type mockLLM struct {
 	err      error
 }
 
-func (m *mockLLM) ChatCompletion(_ context.Context, _ string, _ []openrouter.Message) (*openrouter.ChatResponse, error) {
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, _ []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
 	return m.response, m.err
 }
 
@@ -75,6 +78,7 @@ func TestJudgeNoConsensus(t *testing.T) {
 func TestJudgeMalformedJSON(t *testing.T) {
 	llm := &mockLLM{response: chatResponse("this is not json at all")}
 	judge := NewJudge(llm, "test-model")
+	judge.SetFallback(nil)
 
 	result, err := judge.Evaluate(context.Background(), sampleTranscript())
 	if err != nil {
@@ -105,6 +109,19 @@ func TestJudgeExtractsJSONFromMarkdownCodeBlock(t *testing.T) {
 	}
 }
 
+func TestJudgeIgnoresReasoning(t *testing.T) {
+	response := "<think>Draft: {\"consensus_detected\": false, \"agreement_score\": 2}. No, they agree.</think>\n{\"consensus_detected\": true, \"consensus_position\": \"we agree\", \"agreement_score\": 8, \"dissenting_agents\": []}"
+	judge := NewJudge(&mockLLM{response: chatResponse(response)}, "test-model")
+
+	result, err := judge.Evaluate(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Detected || result.Score != 8 {
+		t.Errorf("expected the verdict after the reasoning, got %+v", result)
+	}
+}
+
 func TestJudgeExtractsJSONFromCodeBlockNoLang(t *testing.T) {
 	response := "```\n{\"consensus_detected\": false, \"consensus_position\": \"\", \"agreement_score\": 4, \"dissenting_agents\": [\"Bob\"]}\n```"
 	llm := &mockLLM{response: chatResponse(response)}
@@ -122,8 +139,22 @@ func TestJudgeExtractsJSONFromCodeBlockNoLang(t *testing.T) {
 	}
 }
 
+func TestJudgeRepairsNearJSON(t *testing.T) {
+	response := "```json\n{'consensus_detected': True, 'consensus_position': 'phase it in', 'agreement_score': 8, 'dissenting_agents': ['Bob',],"
+	judge := NewJudge(&mockLLM{response: chatResponse(response)}, "test-model")
+	judge.SetStrict(true)
+
+	result, err := judge.Evaluate(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Detected || result.Score != 8 || result.Position != "phase it in" {
+		t.Errorf("expected the repaired verdict, got %+v", result)
+	}
+}
+
 func TestJudgeExtractsJSONFromPreambleText(t *testing.T) {
-	response := "Based on my analysis of the debate, here is the result:\n{\"consensus_detected\": true, \"consensus_position\": \"regulation needed\", \"agreement_score\": 7, \"dissenting_agents\": [\"Charlie\"]}\nEnd of evaluation."
+	response := "Based on my analysis of the debate, here is the result:\n{\"consensus_detected\": true, \"consensus_position\": \"regulation needed\", \"agreement_score\": 7, \"dissenting_agents\": [\"bob\"]}\nEnd of evaluation."
 	llm := &mockLLM{response: chatResponse(response)}
 	judge := NewJudge(llm, "test-model")
 
@@ -140,6 +171,52 @@ func TestJudgeExtractsJSONFromPreambleText(t *testing.T) {
 	if result.Position != "regulation needed" {
 		t.Errorf("expected position 'regulation needed', got %q", result.Position)
 	}
+	if len(result.Dissenters) != 1 || result.Dissenters[0] != "Bob" {
+		t.Errorf("expected the dissenter spelled as in the roster, got %v", result.Dissenters)
+	}
+}
+
+func TestJudgeRejectsInvalidVerdicts(t *testing.T) {
+	callCount := 0
+	llm := &retryMockLLM{
+		responses: []*openrouter.ChatResponse{
+			chatResponse(`{"consensus_detected": true, "consensus_position": "x", "agreement_score": 11, "dissenting_agents": []}`),
+			chatResponse(`{"consensus_detected": false, "consensus_position": "", "agreement_score": 4, "dissenting_agents": ["Zed"]}`),
+			chatResponse(`{"consensus_detected": false, "consensus_position": "", "agreement_score": 0, "dissenting_agents": ["Alice"]}`),
+		},
+		callCount: &callCount,
+	}
+	result, err := NewJudge(llm, "test-model").Evaluate(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if callCount != 3 || result.Fallback {
+		t.Fatalf("expected the third verdict to be accepted, got %d calls and %+v", callCount, result)
+	}
+	if result.Score != 1 {
+		t.Errorf("expected a score of 0 to be raised to 1, got %d", result.Score)
+	}
+	if len(result.Diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %+v", result.Diagnostics)
+	}
+	if d := result.Diagnostics[0]; d.Attempt != 1 || !strings.Contains(d.Error, "outside 1-10") || !strings.Contains(d.Response, `"agreement_score": 11`) {
+		t.Errorf("unexpected first diagnostic %+v", d)
+	}
+	if d := result.Diagnostics[1]; d.Attempt != 2 || !strings.Contains(d.Error, `"Zed"`) {
+		t.Errorf("unexpected second diagnostic %+v", d)
+	}
+
+	for name, raw := range map[string]string{
+		"negative score":  `{"consensus_detected": false, "agreement_score": -2}`,
+		"missing score":   `{"consensus_detected": false, "consensus_position": ""}`,
+		"missing verdict": `{"agreement_score": 5}`,
+	} {
+		judge := NewJudge(&mockLLM{response: chatResponse(raw)}, "test-model")
+		judge.SetStrict(true)
+		if _, err := judge.Evaluate(context.Background(), sampleTranscript()); !errors.Is(err, debate.ErrConsensusParse) {
+			t.Errorf("%s: expected ErrConsensusParse, got %v", name, err)
+		}
+	}
 }
 
 func TestJudgeRetriesOnMalformedJSON(t *testing.T) {
@@ -169,7 +246,7 @@ func TestJudgeRetriesOnMalformedJSON(t *testing.T) {
 	}
 }
 
-func TestJudgeRetriesExhaustedReturnsDefault(t *testing.T) {
+func TestJudgeRetriesExhaustedUsesFallback(t *testing.T) {
 	callCount := 0
 	llm := &retryMockLLM{
 		responses: []*openrouter.ChatResponse{
@@ -185,23 +262,33 @@ func TestJudgeRetriesExhaustedReturnsDefault(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error after retries exhausted, got: %v", err)
 	}
-	if result.Detected {
-		t.Error("expected Detected false when all retries fail")
+	if !result.Fallback {
+		t.Error("expected the fallback judge's verdict when all retries fail")
 	}
-	if result.Score != 0 {
-		t.Errorf("expected score 0, got %d", result.Score)
+	if !result.Detected || result.Score != 10 {
+		t.Errorf("expected the agreeing transcript to score 10, got %+v", result)
 	}
 	if callCount != 3 {
 		t.Errorf("expected 3 LLM calls, got %d", callCount)
 	}
 }
 
+func TestJudgeStrictReturnsParseError(t *testing.T) {
+	judge := NewJudge(&mockLLM{response: chatResponse("not json")}, "test-model")
+	judge.SetStrict(true)
+
+	_, err := judge.Evaluate(context.Background(), sampleTranscript())
+	if !errors.Is(err, debate.ErrConsensusParse) {
+		t.Errorf("expected ErrConsensusParse, got %v", err)
+	}
+}
+
 type retryMockLLM struct {
 	responses []*openrouter.ChatResponse
 	callCount *int
 }
 
-func (m *retryMockLLM) ChatCompletion(_ context.Context, _ string, _ []openrouter.Message) (*openrouter.ChatResponse, error) {
+func (m *retryMockLLM) ChatCompletion(_ context.Context, _ string, _ []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
 	idx := *m.callCount
 	*m.callCount++
 	if idx < len(m.responses) {
@@ -226,3 +313,230 @@ func TestJudgeLLMError(t *testing.T) {
 		t.Errorf("expected error prefix %q, got: %v", expected, err)
 	}
 }
+
+// promptLLM records the last user message it was sent.
+type promptLLM struct {
+	prompt string
+}
+
+func (m *promptLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	m.prompt = msgs[1].Content
+	return chatResponse(`{"consensus_detected": false, "consensus_position": "", "agreement_score": 3, "dissenting_agents": []}`), nil
+}
+
+func TestJudgeMarksNonSubstantiveTurns(t *testing.T) {
+	llm := &promptLLM{}
+	transcript := sampleTranscript()
+	transcript.Turns = append(transcript.Turns, debate.Turn{Round: 1, Agent: debate.Agent{ID: 3, Name: "Carol"}, Content: "  "})
+	if _, err := NewJudge(llm, "test-model").Evaluate(context.Background(), transcript); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(llm.prompt, "Alice: I agree\n") || !strings.Contains(llm.prompt, "Carol: "+noResponse) {
+		t.Errorf("expected Carol's empty turn to be marked, got %q", llm.prompt)
+	}
+
+	transcript.Turns = append(transcript.Turns, debate.Turn{Round: 1, Agent: debate.Agent{ID: 1, Name: "Alice"}, Passed: true})
+	if _, err := NewJudge(llm, "test-model").Evaluate(context.Background(), transcript); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(llm.prompt, "Alice: "+debate.PassNote) {
+		t.Errorf("expected Alice's pass to be marked, got %q", llm.prompt)
+	}
+}
+
+func TestJudgeReadsTopicClass(t *testing.T) {
+	llm := &promptLLM{}
+	transcript := sampleTranscript()
+	if _, err := NewJudge(llm, "test-model").Evaluate(context.Background(), transcript); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(llm.prompt, "question of") {
+		t.Errorf("expected no topic guidance without a class, got %q", llm.prompt)
+	}
+	transcript.TopicClass = &debate.TopicClass{Kind: debate.QuestionOfValue}
+	if _, err := NewJudge(llm, "test-model").Evaluate(context.Background(), transcript); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(llm.prompt, transcript.TopicClass.JudgeGuidance()+"\n\n") {
+		t.Errorf("expected the judge told how to read a question of value, got %q", llm.prompt)
+	}
+}
+
+func TestJudgeIgnoresRemovedAgents(t *testing.T) {
+	callCount := 0
+	llm := &retryMockLLM{
+		responses: []*openrouter.ChatResponse{
+			chatResponse(`{"consensus_detected": true, "consensus_position": "x", "agreement_score": 8, "dissenting_agents": ["Bob"]}`),
+			chatResponse(`{"consensus_detected": true, "consensus_position": "x", "agreement_score": 8, "dissenting_agents": []}`),
+		},
+		callCount: &callCount,
+	}
+	transcript := sampleTranscript()
+	transcript.Removals = []debate.AgentRemoval{{Round: 2, Agent: "Bob", Reason: "3 failed turns in a row"}}
+	result, err := NewJudge(llm, "test-model").Evaluate(context.Background(), transcript)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if callCount != 2 || len(result.Dissenters) != 0 {
+		t.Errorf("expected the removed dissenter to be rejected, got %d calls and %+v", callCount, result)
+	}
+
+	prompt := &promptLLM{}
+	if _, err := NewJudge(prompt, "test-model").Evaluate(context.Background(), transcript); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt.prompt, "- Bob, from round 2: 3 failed turns in a row") {
+		t.Errorf("expected the judge to be told about Bob, got %q", prompt.prompt)
+	}
+}
+
+func TestJudgeParsesAgentScores(t *testing.T) {
+	callCount := 0
+	llm := &retryMockLLM{
+		responses: []*openrouter.ChatResponse{
+			chatResponse(`{"consensus_detected": true, "consensus_position": "x", "agreement_score": 8, "dissenting_agents": [], "agent_scores": {"Alice": 9, "Bob": 12}}`),
+			chatResponse(`{"consensus_detected": true, "consensus_position": "x", "agreement_score": 8, "dissenting_agents": ["bob"], "agent_scores": {"alice": 9, "Bob": 3}}`),
+		},
+		callCount: &callCount,
+	}
+	result, err := NewJudge(llm, "test-model").Evaluate(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if callCount != 2 || len(result.Diagnostics) != 1 || !strings.Contains(result.Diagnostics[0].Error, "Bob's score 12") {
+		t.Errorf("expected the out-of-range score to be rejected once, got %d calls and %+v", callCount, result.Diagnostics)
+	}
+	if result.AgentScores["Alice"] != 9 || result.AgentScores["Bob"] != 3 || len(result.AgentScores) != 2 {
+		t.Errorf("expected scores keyed by roster names, got %v", result.AgentScores)
+	}
+}
+
+// summarizingLLM answers summarizer calls with a fixed line per round and
+// records what the judge is shown.
+type summarizingLLM struct {
+	summaries  int
+	summarized []openrouter.Message
+	judged     string
+}
+
+func (m *summarizingLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if strings.HasPrefix(msgs[0].Content, "You are a debate summarizer") {
+		m.summaries++
+		m.summarized = msgs
+		return chatResponse("Alice: holds a summarized position"), nil
+	}
+	m.judged = msgs[1].Content
+	return chatResponse(`{"consensus_detected": false, "consensus_position": "", "agreement_score": 3, "dissenting_agents": []}`), nil
+}
+
+func TestJudgeSummarizesLongTranscripts(t *testing.T) {
+	transcript := &debate.Transcript{Topic: "t"}
+	for round := 1; round <= 3; round++ {
+		for _, name := range []string{"Alice", "Bob"} {
+			transcript.Turns = append(transcript.Turns, debate.Turn{Round: round, Agent: debate.Agent{Name: name}, Content: strings.Repeat(fmt.Sprintf("argument %d ", round), 20)})
+		}
+	}
+	transcript.Turns[len(transcript.Turns)-1].Content = "Latest point from Bob"
+	llm := &summarizingLLM{}
+	judge := NewJudge(llm, "test-model")
+	judge.SetMaxTranscript(150)
+
+	for range 2 {
+		if _, err := judge.Evaluate(context.Background(), transcript); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if llm.summaries != 2 {
+		t.Errorf("expected each earlier round to be summarized once, got %d summaries", llm.summaries)
+	}
+	for _, want := range []string{"Round 1 (summary):\nAlice: holds a summarized position", "Round 2 (summary):", "Round 3 (latest, in full):\n", "Bob: Latest point from Bob"} {
+		if !strings.Contains(llm.judged, want) {
+			t.Errorf("expected the judge to see %q, got:\n%s", want, llm.judged)
+		}
+	}
+	if strings.Contains(llm.judged, "Round 1 (summary):\nAlice: argument") {
+		t.Error("expected earlier rounds not to be sent verbatim")
+	}
+	if !strings.Contains(llm.summarized[1].Content, "<untrusted source=\"round 2\">\nAlice: argument 2") || !strings.Contains(llm.summarized[0].Content, guard.Instruction) {
+		t.Errorf("expected the summarized round fenced as untrusted, got %+v", llm.summarized)
+	}
+
+	judge.SetMaxTranscript(0)
+	if _, err := judge.Evaluate(context.Background(), transcript); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(llm.judged, "(summary)") {
+		t.Error("expected the full transcript with chunking disabled")
+	}
+}
+
+func TestJudgeWindowEvaluatesRecentRounds(t *testing.T) {
+	transcript := &debate.Transcript{Rounds: 4}
+	for round := 1; round <= 4; round++ {
+		transcript.Turns = append(transcript.Turns, debate.Turn{Round: round, Agent: debate.Agent{Name: "Alice"}, Content: fmt.Sprintf("view in round %d", round)})
+	}
+	llm := &promptLLM{}
+	judge := NewJudge(llm, "test-model")
+	judge.SetWindow(2)
+	if _, err := judge.Evaluate(context.Background(), transcript); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(llm.prompt, "round 2") || !strings.Contains(llm.prompt, "round 3") || !strings.Contains(llm.prompt, "round 4") {
+		t.Errorf("expected only rounds 3 and 4, got %q", llm.prompt)
+	}
+	if !strings.Contains(llm.prompt, "Only the last 2 of 4 rounds") {
+		t.Errorf("expected the judge to be told about the window, got %q", llm.prompt)
+	}
+	if len(transcript.Turns) != 4 {
+		t.Error("expected the transcript itself to be left intact")
+	}
+}
+
+// largeTranscript returns a debate of rounds rounds of agents turns of about
+// a hundred words each.
+func largeTranscript(rounds, agents int) *debate.Transcript {
+	content := strings.Repeat("The evidence on remote work is mixed, and the strongest studies point both ways. ", 7)
+	t := &debate.Transcript{Topic: "Is remote work better?", Rounds: rounds}
+	for r := 1; r <= rounds; r++ {
+		for a := 1; a <= agents; a++ {
+			t.Turns = append(t.Turns, debate.Turn{ID: len(t.Turns) + 1, Round: r, Agent: debate.Agent{ID: a, Name: fmt.Sprintf("Agent-%d", a), Role: "debater"}, Content: content})
+		}
+	}
+	return t
+}
+
+// BenchmarkJudgeEvaluate judges a 15-round debate of 10 debaters, from
+// rendering the transcript to parsing the verdict, with the verdict as a
+// model would send it: in a code fence, with a trailing comma to repair.
+func BenchmarkJudgeEvaluate(b *testing.B) {
+	transcript := largeTranscript(15, 10)
+	var scores []string
+	for a := 1; a <= 10; a++ {
+		scores = append(scores, fmt.Sprintf(`"Agent-%d": 7`, a))
+	}
+	verdict := "Here is my verdict:\n```json\n" + `{"consensus_detected": true, "consensus_position": "remote work is better for focused work", "agreement_score": 7, "dissenting_agents": [], "agent_scores": {` + strings.Join(scores, ", ") + "},}\n```"
+	llm := &mockLLM{response: chatResponse(verdict)}
+	if result, err := NewJudge(llm, "test-model").Evaluate(context.Background(), transcript); err != nil || result.Score != 7 {
+		b.Fatalf("verdict not parsed: %+v, %v", result, err)
+	}
+	for _, bc := range []struct {
+		name          string
+		maxTranscript int
+	}{
+		{"full", 0},
+		{"summarized", defaultMaxTranscript},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				// A new judge each time, so summaries are not served from
+				// the last iteration's cache.
+				judge := NewJudge(llm, "test-model")
+				judge.SetMaxTranscript(bc.maxTranscript)
+				if _, err := judge.Evaluate(context.Background(), transcript); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
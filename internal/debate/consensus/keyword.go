@@ -0,0 +1,117 @@
+package consensus
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// agreeWords and disagreeWords are the stems the keyword judge counts.
+var (
+	agreeWords    = []string{"agree", "concur", "consensus", "support", "endorse", "aligned", "convinced", "right", "correct", "share"}
+	disagreeWords = []string{"disagree", "oppose", "reject", "object", "doubt", "flawed", "wrong", "unconvinced", "skeptical", "however", "but"}
+	negations     = map[string]bool{"not": true, "no": true, "never": true, "don't": true, "dont": true, "cannot": true, "can't": true, "isn't": true, "doesn't": true}
+)
+
+// KeywordJudge implements debate.ConsensusJudge without an LLM. Each agent's
+// latest turn votes for or against the emerging position by counting
+// agreement and disagreement words, a negated agreement word counting
+// against; turns with as many of each abstain. The agreement score is the
+// share of agreeing votes on a 0-10 scale, and the position is the first
+// sentence of the most recent agreeing turn.
+type KeywordJudge struct{}
+
+// NewKeywordJudge creates a KeywordJudge.
+func NewKeywordJudge() *KeywordJudge {
+	return &KeywordJudge{}
+}
+
+// Evaluate implements debate.ConsensusJudge.
+func (j *KeywordJudge) Evaluate(_ context.Context, transcript *debate.Transcript) (*debate.ConsensusResult, error) {
+	result := &debate.ConsensusResult{Dissenters: []string{}}
+	agree, voters := 0, 0
+	for _, turn := range latestTurns(transcript) {
+		switch vote := keywordVote(turn.Content); {
+		case vote > 0:
+			agree++
+			voters++
+			result.Position = firstSentence(turn.Content)
+		case vote < 0:
+			voters++
+			result.Dissenters = append(result.Dissenters, turn.Agent.Name)
+		}
+	}
+	if voters > 0 {
+		result.Score = int(math.Round(10 * float64(agree) / float64(voters)))
+	}
+	result.Detected = result.Score >= debate.ConsensusThreshold
+	if !result.Detected {
+		result.Position = ""
+	}
+	return result, nil
+}
+
+// latestTurns returns each debater's latest turn, ordered by when it was
+// spoken. Moderator notes are skipped, and so are passed turns, which stand
+// by the agent's previous one.
+func latestTurns(transcript *debate.Transcript) []debate.Turn {
+	latest := make(map[string]int)
+	for i, turn := range transcript.Turns {
+		if turn.Agent.Role != "moderator" && !turn.Passed {
+			latest[turn.Agent.Name] = i
+		}
+	}
+	turns := make([]debate.Turn, 0, len(latest))
+	for i, turn := range transcript.Turns {
+		if idx, ok := latest[turn.Agent.Name]; ok && idx == i {
+			turns = append(turns, turn)
+		}
+	}
+	return turns
+}
+
+// keywordVote returns a positive number when content leans towards
+// agreement, a negative one when it leans against, and 0 otherwise.
+func keywordVote(content string) int {
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	vote := 0
+	for i, w := range words {
+		negated := i > 0 && negations[words[i-1]]
+		switch {
+		case hasStem(w, agreeWords) && !hasStem(w, disagreeWords):
+			if negated {
+				vote--
+			} else {
+				vote++
+			}
+		case hasStem(w, disagreeWords):
+			vote--
+		}
+	}
+	return vote
+}
+
+// hasStem reports whether word starts with one of stems, so "agreed" and
+// "supporting" count as "agree" and "support".
+func hasStem(word string, stems []string) bool {
+	for _, s := range stems {
+		if word == s || (len(s) > 3 && strings.HasPrefix(word, s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstSentence returns the first sentence of content, trimmed.
+func firstSentence(content string) string {
+	content = strings.TrimSpace(content)
+	if i := strings.IndexAny(content, ".!?\n"); i >= 0 {
+		return strings.TrimSpace(content[:i+1])
+	}
+	return content
+}
@@ -0,0 +1,49 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+func TestKeywordJudge(t *testing.T) {
+	transcript := &debate.Transcript{Turns: []debate.Turn{
+		{Agent: debate.Agent{Name: "Alice"}, Content: "I disagree with everything."},
+		{Agent: debate.Agent{Name: "Bob"}, Content: "Remote work raises output. I agree with Alice's data."},
+		{Agent: debate.Agent{Name: "Carol"}, Content: "I support this and agree."},
+		{Agent: debate.Agent{Name: "Moderator", Role: "moderator"}, Content: "I reject nothing."},
+		{Agent: debate.Agent{Name: "Alice"}, Content: "Convinced now: we agree. Remote work wins."},
+	}}
+	result, err := NewKeywordJudge().Evaluate(context.Background(), transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Detected || result.Score != 10 || len(result.Dissenters) != 0 {
+		t.Errorf("expected unanimous consensus from the latest turns, got %+v", result)
+	}
+	if result.Position != "Convinced now: we agree." {
+		t.Errorf("expected the latest agreeing turn's first sentence, got %q", result.Position)
+	}
+
+	transcript.Turns = append(transcript.Turns,
+		debate.Turn{Agent: debate.Agent{Name: "Bob"}, Content: "I do not agree; the data is flawed."},
+		debate.Turn{Agent: debate.Agent{Name: "Dave"}, Content: "Interesting points all round."},
+	)
+	result, _ = NewKeywordJudge().Evaluate(context.Background(), transcript)
+	if !result.Detected || result.Score != 7 || len(result.Dissenters) != 1 || result.Dissenters[0] != "Bob" {
+		t.Errorf("expected 2 of 3 votes with Bob dissenting and Dave abstaining, got %+v", result)
+	}
+
+	// A pass stands by the agent's previous turn.
+	transcript.Turns = append(transcript.Turns, debate.Turn{Agent: debate.Agent{Name: "Carol"}, Passed: true})
+	if again, _ := NewKeywordJudge().Evaluate(context.Background(), transcript); again.Score != 7 || again.Position != result.Position {
+		t.Errorf("expected Carol's agreeing vote to stand after the pass, got %+v", again)
+	}
+
+	transcript.Turns = append(transcript.Turns, debate.Turn{Agent: debate.Agent{Name: "Carol"}, Content: "But I doubt it now."})
+	result, _ = NewKeywordJudge().Evaluate(context.Background(), transcript)
+	if result.Detected || result.Score != 3 || result.Position != "" {
+		t.Errorf("a score below the threshold should carry no position, got %+v", result)
+	}
+}
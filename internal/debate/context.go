@@ -0,0 +1,144 @@
+package debate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/tokens"
+)
+
+// replyReserve is the room, in tokens, left for the reply when no max tokens
+// is set. It never takes more than half the context window.
+const replyReserve = 1024
+
+// condensedLength is the number of characters of a turn kept when it is
+// condensed to fit a context window.
+const condensedLength = 200
+
+// SetContextWindows sets the context window, in tokens, of each model by ID,
+// as the models API reports them. Before every turn the engine estimates the
+// size of the prompt; if it and the reply, up to the max tokens, would not
+// fit the agent model's window, the oldest turns are condensed to their
+// opening and then, if that is not enough, omitted, rather than letting the
+// API reject or silently cut the request. The turn records how many turns
+// were condensed in Turn.Condensed. Models without a known window are not
+// checked.
+func (e *Engine) SetContextWindows(windows map[string]int) {
+	e.contextWindows = windows
+}
+
+// fitContext returns msgs fitted to the context window of model and the
+// number of turns condensed or omitted to fit it. msgs is returned unchanged
+// if it fits or the window is unknown; it is never written to, since its
+// turns are shared with other prompts, so only a prompt that must be trimmed
+// is copied. Turns are condensed oldest first,
+// then omitted oldest first; if the prompt is still too long without any,
+// its longest message is truncated.
+func (e *Engine) fitContext(model string, msgs []openrouter.Message) ([]openrouter.Message, int) {
+	window := e.contextWindows[model]
+	if window <= 0 {
+		return msgs, 0
+	}
+	reserve := replyReserve
+	if e.maxTokens > 0 {
+		reserve = e.maxTokens
+	}
+	budget := window - min(reserve, window/2)
+	est := tokens.For(model)
+	if est.Messages(msgs) <= budget {
+		return msgs, 0
+	}
+
+	msgs = slices.Clone(msgs)
+	recorded, shown := e.view()
+	var turns []int // indexes of the messages showing a turn, oldest first
+	for i, m := range msgs {
+		if turn, ok := shownTurn(recorded.Turns, shown, m); ok {
+			turns = append(turns, i)
+			msgs[i] = condensedTurnMessage(turn)
+			if est.Messages(msgs) <= budget {
+				return msgs, len(turns)
+			}
+		}
+	}
+	for k := 1; k <= len(turns); k++ {
+		if fitted := omitTurns(msgs, turns[:k]); est.Messages(fitted) <= budget || k == len(turns) {
+			msgs = fitted
+			break
+		}
+	}
+	if over := est.Messages(msgs) - budget; over > 0 {
+		longest := 0
+		for i, m := range msgs {
+			if len(m.Content) > len(msgs[longest].Content) {
+				longest = i
+			}
+		}
+		msgs[longest].Content = truncateRunes(msgs[longest].Content, utf8.RuneCountInString(msgs[longest].Content)-est.Chars(over+1))
+	}
+	return msgs, len(turns)
+}
+
+// shownTurn returns the turn of recorded m shows, if m is the message in
+// shown showing it.
+func shownTurn(recorded []Turn, shown []openrouter.Message, m openrouter.Message) (Turn, bool) {
+	var id int
+	if m.Role != "user" || !strings.HasPrefix(m.Content, "[#") {
+		return Turn{}, false
+	}
+	if _, err := fmt.Sscanf(m.Content, "[#%d]", &id); err != nil || id < 1 || id > len(recorded) {
+		return Turn{}, false
+	}
+	return recorded[id-1], m.Content == shown[id-1].Content
+}
+
+// condensedTurnMessage shows turn cut to its opening, like turnMessage.
+func condensedTurnMessage(turn Turn) openrouter.Message {
+	return openrouter.Message{
+		Role:    "user",
+		Content: fmt.Sprintf("[#%d] %s (condensed): %s", turn.ID, turn.Agent.Name, guard.Wrap(fmt.Sprintf("turn #%d", turn.ID), truncateRunes(turn.Text(), condensedLength))),
+	}
+}
+
+// omitTurns returns msgs without the messages at indexes, a note in place of
+// the first saying how many were omitted.
+func omitTurns(msgs []openrouter.Message, indexes []int) []openrouter.Message {
+	fitted := make([]openrouter.Message, 0, len(msgs))
+	for i, m := range msgs {
+		switch {
+		case i == indexes[0]:
+			fitted = append(fitted, openrouter.Message{
+				Role:    "user",
+				Content: fmt.Sprintf("[%d earlier turn(s) omitted to fit the context window]", len(indexes)),
+			})
+		case !slices.Contains(indexes, i):
+			fitted = append(fitted, m)
+		}
+	}
+	return fitted
+}
+
+// truncateRunes cuts s to at most n runes, ending it with truncationMark if
+// anything was cut. The cut backs off to the last space so words stay whole.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	n = max(n-utf8.RuneCountInString(truncationMark), 0)
+	cut := s
+	for i := range s {
+		if n == 0 {
+			cut = s[:i]
+			break
+		}
+		n--
+	}
+	if i := strings.LastIndexAny(cut, " \n\t"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " \n\t") + truncationMark
+}
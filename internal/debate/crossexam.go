@@ -0,0 +1,83 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// Exchange is a question one debater put to another during cross-examination,
+// and its answer.
+type Exchange struct {
+	Round    int
+	Asker    string
+	Answerer string
+	Question int // ID of the question turn
+	Answer   int // ID of the answer turn
+}
+
+// SetCrossExamination makes round of Phase 1 a cross-examination: instead
+// of speaking in turn, each debater puts one direct question to the next
+// debater, who answers it before asking its own. Questions and answers are
+// turns of the round, each answer replying to its question, and every pair
+// is recorded in the transcript's CrossExamination. Nothing happens if
+// Phase 1 ends before round; a round below 1 disables cross-examination.
+func (e *Engine) SetCrossExamination(round int) {
+	e.crossRound = round
+}
+
+// crossExamining reports whether round is the cross-examination round.
+func (e *Engine) crossExamining(round int) bool {
+	return e.crossRound > 0 && round == e.crossRound && e.transcript.Phase == FreeDebate && len(e.agents) >= 2
+}
+
+// crossExamine runs round as a cross-examination.
+func (e *Engine) crossExamine(ctx context.Context, round int) error {
+	agents := e.agents
+	for i, asker := range agents {
+		target := agents[(i+1)%len(agents)]
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("debate: %w", err)
+		}
+		ask := fmt.Sprintf("Cross-examination: put one direct question to %s that tests the weakest assumption behind their position. Give only the question.", target.Name)
+		question, err := e.takeTurn(ctx, round, asker, withPrompt(e.turnMessages(asker), ask), lastTurnBy(e.transcript.Turns, target.Name))
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(question.Content) == "" {
+			continue
+		}
+		answer := fmt.Sprintf("Cross-examination: %s has asked you a direct question in #%d. Answer it directly and honestly, conceding what you cannot defend.", asker.Name, question.ID)
+		reply, err := e.takeTurn(ctx, round, target, withPrompt(e.turnMessages(target), answer), question.ID)
+		if err != nil {
+			return err
+		}
+		e.transcript.CrossExamination = append(e.transcript.CrossExamination, Exchange{
+			Round:    round,
+			Asker:    asker.Name,
+			Answerer: target.Name,
+			Question: question.ID,
+			Answer:   reply.ID,
+		})
+	}
+	return nil
+}
+
+// withPrompt replaces the closing prompt of msgs with prompt.
+func withPrompt(msgs []openrouter.Message, prompt string) []openrouter.Message {
+	msgs[len(msgs)-1].Content = prompt
+	return msgs
+}
+
+// lastTurnBy returns the ID of agent's latest turn in turns, or 0 if it has
+// none.
+func lastTurnBy(turns []Turn, agent string) int {
+	for i := len(turns) - 1; i >= 0; i-- {
+		if turns[i].Agent.Name == agent {
+			return turns[i].ID
+		}
+	}
+	return 0
+}
@@ -0,0 +1,216 @@
+// Package disagreement measures how strongly each pair of agents disagrees
+// in every round of a debate.
+package disagreement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/llmjson"
+)
+
+const maxAnalyzeRetries = 3
+
+// Sources of a round's scores.
+const (
+	// Judge scores come from the model reading the round.
+	Judge = "judge"
+	// Agreement scores are the gaps between the agents' agreement with the
+	// consensus, used when the model gives no usable answer.
+	Agreement = "agreement"
+)
+
+// Analyzer scores pairwise disagreement between agents using an LLM.
+type Analyzer struct {
+	llm   debate.LLMClient
+	model string
+}
+
+// NewAnalyzer creates a new Analyzer.
+func NewAnalyzer(llm debate.LLMClient, model string) *Analyzer {
+	return &Analyzer{llm: llm, model: model}
+}
+
+// Analyze returns a disagreement matrix for every round of transcript in
+// which at least two agents spoke, with one call per round. A round the
+// model gives no valid JSON for is scored from the judge's agreement scores
+// for that round instead, and left out if there are none.
+func (a *Analyzer) Analyze(ctx context.Context, transcript *debate.Transcript) ([]debate.RoundDisagreement, error) {
+	var rounds []debate.RoundDisagreement
+	for round := 1; round <= transcript.Rounds; round++ {
+		agents, text := roundTurns(transcript.Turns, round)
+		if len(agents) < 2 {
+			continue
+		}
+		rd := debate.RoundDisagreement{Round: round, Agents: agents, Source: Judge}
+		scores, err := a.score(ctx, transcript.Topic, round, agents, text)
+		if err != nil {
+			return nil, err
+		}
+		if scores == nil {
+			scores = agreementGaps(transcript.Agreement, round, agents)
+			rd.Source = Agreement
+		}
+		if scores == nil {
+			continue
+		}
+		rd.Scores = scores
+		rounds = append(rounds, rd)
+	}
+	return rounds, nil
+}
+
+// roundTurns returns the agents that spoke in round, in speaking order, and
+// the round's turns as text. Moderator and empty turns are skipped.
+func roundTurns(turns []debate.Turn, round int) ([]string, string) {
+	var agents []string
+	var sb strings.Builder
+	for _, turn := range turns {
+		if turn.Round != round || turn.Agent.Role == "moderator" || strings.TrimSpace(turn.Content) == "" {
+			continue
+		}
+		if !slices.Contains(agents, turn.Agent.Name) {
+			agents = append(agents, turn.Agent.Name)
+		}
+		fmt.Fprintf(&sb, "%s (%s): %s\n\n", turn.Agent.Name, turn.Agent.Role, turn.Content)
+	}
+	return agents, strings.TrimSpace(sb.String())
+}
+
+// score asks the model to rate every pair of agents in a round. It returns
+// nil if no answer parses after retries.
+func (a *Analyzer) score(ctx context.Context, topic string, round int, agents []string, text string) ([][]int, error) {
+	var scores [][]int
+	_, err := llmjson.StructuredCall(ctx, a.llm, a.model, llmjson.Prompt[reply]{
+		System:   "You are a debate analyst. Rate how strongly each pair of participants disagreed in one round of a debate, from 0 (same position) to 10 (directly opposed). Judge their positions, not their wording. " + guard.Instruction,
+		Schema:   `{"pairs": [{"a": "...", "b": "...", "score": 0}]}`,
+		Notes:    "Rate every pair once, using the participant names exactly as given.",
+		User:     fmt.Sprintf("Debate topic: %s\nParticipants: %s\n\nRound %d:\n\n%s", topic, strings.Join(agents, ", "), round, guard.Wrap(fmt.Sprintf("round %d", round), text)),
+		Required: []string{"pairs"},
+		Validate: func(r *reply) error {
+			if scores = r.matrix(agents); scores == nil {
+				return errors.New("no pair of the given participants is rated")
+			}
+			return nil
+		},
+		Attempts: maxAnalyzeRetries,
+	})
+	if errors.As(err, new(*llmjson.InvalidError)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("disagreement: %w", err)
+	}
+	return scores, nil
+}
+
+// reply is the model's answer.
+type reply struct {
+	Pairs []struct {
+		A     string `json:"a"`
+		B     string `json:"b"`
+		Score int    `json:"score"`
+	} `json:"pairs"`
+}
+
+// matrix returns r's pairwise scores as a matrix over agents. Pairs naming
+// unknown agents are ignored, scores are clamped to 0-10, and pairs left
+// unrated are -1. It returns nil if no pair is rated.
+func (r *reply) matrix(agents []string) [][]int {
+	index := func(name string) int {
+		return slices.IndexFunc(agents, func(a string) bool { return strings.EqualFold(a, strings.TrimSpace(name)) })
+	}
+	scores := newMatrix(len(agents))
+	rated := false
+	for _, p := range r.Pairs {
+		i, j := index(p.A), index(p.B)
+		if i < 0 || j < 0 || i == j {
+			continue
+		}
+		scores[i][j] = min(max(p.Score, 0), 10)
+		scores[j][i] = scores[i][j]
+		rated = true
+	}
+	if !rated {
+		return nil
+	}
+	return scores
+}
+
+// agreementGaps scores each pair of agents by how far apart their agreement
+// with the consensus was after round, or returns nil if the judge scored
+// fewer than two of them. Pairs with an unscored agent are -1.
+func agreementGaps(agreement []debate.RoundAgreement, round int, agents []string) [][]int {
+	i := slices.IndexFunc(agreement, func(ra debate.RoundAgreement) bool { return ra.Round == round })
+	if i < 0 {
+		return nil
+	}
+	got := agreement[i].Scores
+	scored := 0
+	for _, a := range agents {
+		if _, ok := got[a]; ok {
+			scored++
+		}
+	}
+	if scored < 2 {
+		return nil
+	}
+	scores := newMatrix(len(agents))
+	for i, a := range agents {
+		for j, b := range agents {
+			sa, okA := got[a]
+			sb, okB := got[b]
+			if i != j && okA && okB {
+				scores[i][j] = min(max(sa-sb, sb-sa), 10)
+			}
+		}
+	}
+	return scores
+}
+
+// newMatrix returns an n×n matrix of unrated pairs with a zero diagonal.
+func newMatrix(n int) [][]int {
+	m := make([][]int, n)
+	for i := range m {
+		m[i] = make([]int, n)
+		for j := range m[i] {
+			if i != j {
+				m[i][j] = -1
+			}
+		}
+	}
+	return m
+}
+
+// Dissent returns each agent's mean disagreement with the others over every
+// rated pair in rounds, in order of first appearance.
+func Dissent(rounds []debate.RoundDisagreement) ([]string, map[string]float64) {
+	var agents []string
+	sum := make(map[string]int)
+	count := make(map[string]int)
+	for _, rd := range rounds {
+		for i, a := range rd.Agents {
+			if !slices.Contains(agents, a) {
+				agents = append(agents, a)
+			}
+			for j, s := range rd.Scores[i] {
+				if i != j && s >= 0 {
+					sum[a] += s
+					count[a]++
+				}
+			}
+		}
+	}
+	mean := make(map[string]float64)
+	for _, a := range agents {
+		if count[a] > 0 {
+			mean[a] = float64(sum[a]) / float64(count[a])
+		}
+	}
+	return agents, mean
+}
@@ -0,0 +1,114 @@
+package disagreement
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+type mockLLM struct {
+	responses []string
+	err       error
+	calls     int
+	systems   []string
+	prompts   []string
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.systems = append(m.systems, msgs[0].Content)
+	m.prompts = append(m.prompts, msgs[1].Content)
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+func sampleTranscript() *debate.Transcript {
+	debater := func(name string) debate.Agent { return debate.Agent{Name: name, Role: "debater"} }
+	return &debate.Transcript{
+		Topic:  "test topic",
+		Rounds: 3,
+		Turns: []debate.Turn{
+			{Round: 1, Agent: debater("Alice"), Content: "Cache everything."},
+			{Round: 1, Agent: debater("Bob"), Content: "Cache nothing."},
+			{Round: 1, Agent: debater("Carol"), Content: "Cache some."},
+			{Round: 2, Agent: debate.Agent{Name: "Moderator", Role: "moderator"}, Content: "New data."},
+			{Round: 2, Agent: debater("Alice"), Content: "Still cache."},
+			{Round: 2, Agent: debater("Bob"), Content: ""},
+			{Round: 3, Agent: debater("Alice"), Content: "Cache."},
+			{Round: 3, Agent: debater("Bob"), Content: "Fine, cache."},
+		},
+		Agreement: []debate.RoundAgreement{{Round: 3, Scores: map[string]int{"Alice": 9, "Bob": 6}}},
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	llm := &mockLLM{responses: []string{
+		"```json\n" + `{"pairs": [{"a": "Alice", "b": "Bob", "score": 9}, {"a": "bob", "b": "Carol", "score": 14}, {"a": "Alice", "b": "Dave", "score": 3}]}` + "\n```",
+		"nope", "nope", "nope",
+	}}
+	got, err := NewAnalyzer(llm, "m").Analyze(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected rounds 1 and 3, got %+v", got)
+	}
+	r1 := got[0]
+	if r1.Round != 1 || r1.Source != Judge || strings.Join(r1.Agents, ",") != "Alice,Bob,Carol" {
+		t.Fatalf("unexpected round 1 %+v", r1)
+	}
+	if r1.Scores[0][1] != 9 || r1.Scores[1][0] != 9 || r1.Scores[1][2] != 10 || r1.Scores[0][2] != -1 || r1.Scores[1][1] != 0 {
+		t.Errorf("unexpected round 1 scores %v", r1.Scores)
+	}
+	r3 := got[1]
+	if r3.Round != 3 || r3.Source != Agreement || r3.Scores[0][1] != 3 {
+		t.Errorf("expected round 3 scored from agreement gaps, got %+v", r3)
+	}
+	if llm.calls != 1+maxAnalyzeRetries {
+		t.Errorf("expected round 2 skipped without a call, got %d calls", llm.calls)
+	}
+	if strings.Contains(llm.prompts[0], "Still cache") || !strings.Contains(llm.prompts[0], "Participants: Alice, Bob, Carol") {
+		t.Errorf("unexpected round 1 prompt %q", llm.prompts[0])
+	}
+}
+
+func TestAnalyzeError(t *testing.T) {
+	llm := &mockLLM{err: errors.New("boom")}
+	if _, err := NewAnalyzer(llm, "m").Analyze(context.Background(), sampleTranscript()); err == nil {
+		t.Error("expected the client error")
+	}
+}
+
+func TestDissent(t *testing.T) {
+	rounds := []debate.RoundDisagreement{
+		{Round: 1, Agents: []string{"Alice", "Bob", "Tenth Man"}, Scores: [][]int{{0, 2, 8}, {2, 0, -1}, {8, -1, 0}}},
+		{Round: 2, Agents: []string{"Alice", "Tenth Man"}, Scores: [][]int{{0, 6}, {6, 0}}},
+	}
+	agents, mean := Dissent(rounds)
+	if strings.Join(agents, ",") != "Alice,Bob,Tenth Man" {
+		t.Errorf("unexpected agents %v", agents)
+	}
+	if mean["Alice"] != 16.0/3 || mean["Bob"] != 2 || mean["Tenth Man"] != 7 {
+		t.Errorf("unexpected means %v", mean)
+	}
+}
+
+func TestAnalyzeFencesTurns(t *testing.T) {
+	llm := &mockLLM{responses: []string{`{"pairs": [{"a": "Alice", "b": "Bob", "score": 5}]}`}}
+	if _, err := NewAnalyzer(llm, "m").Analyze(context.Background(), sampleTranscript()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(llm.prompts[0], "<untrusted source=\"round 1\">\nAlice (debater): Cache everything.") || !strings.Contains(llm.systems[0], guard.Instruction) {
+		t.Errorf("expected the round's turns fenced as untrusted, got system %q and prompt %q", llm.systems[0], llm.prompts[0])
+	}
+}
@@ -2,10 +2,22 @@ package debate
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
 )
 
-const tenthManRounds = 3
+const defaultTenthManRounds = 3
+
+// ConsensusThreshold is the minimum agreement score (0-10) at which a
+// detected consensus triggers the Tenth Man.
+const ConsensusThreshold = 7
 
 // Engine orchestrates a multi-agent debate.
 type Engine struct {
@@ -18,9 +30,58 @@ type Engine struct {
 	minRounds         int
 	maxRounds         int
 	tenthManModel     string
+	tenthManRounds    int
+	instructions      string
+	images            []string // image URLs attached to the topic
+	stagnationRounds  int
+	minNovelty        float64
+	retriever         Retriever
+	evidenceBudget    int
+	checkpointer      Checkpointer
+	retryBudget       int
+	fallbackModels    []string
+	maxTokens         int          // completion cap for agents' turns; 0 leaves it to the client
+	maxWords          int          // word limit for a turn; 0 disables the length guard
+	samples           int          // candidates drawn per turn; below 2 draws one
+	samplePick        string       // how the strongest candidate is chosen: PickLLM or PickHeuristic
+	refine            bool         // draft, critique and revise every turn
+	passing           bool         // debaters may pass free debate turns
+	openings          bool         // round 1 is a round of opening statements
+	closings          bool         // Phase 2 ends with a round of closing statements
+	crossRound        int          // Phase 1 round run as a cross-examination; 0 disables it
+	retries           atomic.Int64 // retried LLM calls, counted against retryBudget
 	consensusPosition string
+	pendingMu         sync.Mutex
+	pending           []string       // moderator notes queued by InjectEvent
+	pendingSwaps      []ModelSwap    // model swaps queued by SwapModel
+	pendingRemovals   []AgentRemoval // removals queued by RemoveAgent
+	pendingJoins      []Agent        // agents queued by AddAgent
+	maxFailures       int            // failed turns in a row that remove a debater; 0 fails the debate
+	failures          map[string]int // consecutive failed turns by agent name; guarded by recordMu
+	recordMu          sync.Mutex     // guards the turns and reasoning traces turns add to the transcript
+	emitMu            sync.Mutex     // delivers one event at a time, in order
+	contextWindows    map[string]int // context window in tokens by model ID; models not in it are not checked
+	events            chan<- Event
+	states            map[State]StateHandler // replaced or added state handlers
+	middleware        []Middleware           // wraps every state's handler, first outermost
+	stopped           bool                   // Stop was called; guarded by pendingMu
+	cancel            func(error)            // cancels the run in progress with a cause; guarded by pendingMu
+	shown             []openrouter.Message   // the message showing each turn of shownFor, shared by every prompt; guarded by recordMu
+	shownFor          *Transcript            // the transcript shown renders; guarded by recordMu
+	prefix            []openrouter.Message   // the system prompt, images and shown turns of the last turn's prompt, which the next extends; guarded by recordMu
+	prefixInUse       bool                   // a turn is still using prefix, so the next prompt copies it; guarded by recordMu
+	stopWhen          Condition              // ends Phase 1 without the Tenth Man; nil disables it
+	tenthManWhen      Condition              // replaces the built-in Tenth Man activation when set
+	adaptive          *AdaptiveRounds        // ends Phase 1 by the agreement score's velocity; nil disables it
 	OnTurn            func(Turn)
 	OnPhase           func(Phase)
+	OnEvidence        func(Evidence)
+
+	OnRoundStart        func(RoundSummary)
+	OnRoundEnd          func(RoundSummary)
+	OnConsensus         func(*ConsensusResult) // after every consensus evaluation
+	OnTenthManActivated func(agent Agent, position string)
+	OnError             func(agent Agent, err error, willRetry bool) // failed LLM calls, including ones about to be retried
 }
 
 // NewEngine creates a new debate engine.
@@ -35,8 +96,9 @@ func NewEngine(topic string, agents []Agent, llm LLMClient, judge ConsensusJudge
 			Topic: topic,
 			Phase: FreeDebate,
 		},
-		minRounds: minRounds,
-		maxRounds: maxRounds,
+		minRounds:      minRounds,
+		maxRounds:      maxRounds,
+		tenthManRounds: defaultTenthManRounds,
 	}
 }
 
@@ -45,88 +107,457 @@ func (e *Engine) SetTenthManModel(model string) {
 	e.tenthManModel = model
 }
 
-// Run executes the full debate: Phase 1 (free debate) and optionally Phase 2 (tenth man).
+// SetTenthManRounds sets how many rounds Phase 2 lasts. Values below 1 are ignored.
+func (e *Engine) SetTenthManRounds(n int) {
+	if n > 0 {
+		e.tenthManRounds = n
+	}
+}
+
+// SetInstructions sets scenario-specific guidance added to every debater's system prompt.
+func (e *Engine) SetInstructions(instructions string) {
+	e.instructions = instructions
+}
+
+// SetImages attaches images to the topic, as https:// or data: URLs. Every
+// agent is shown them with the topic, so its model must accept images.
+func (e *Engine) SetImages(images []string) {
+	e.images = images
+}
+
+// SetStagnation ends Phase 1 early, once the minimum rounds are done, after
+// rounds consecutive rounds whose share of new words falls below minNovelty.
+// A rounds value below 1 disables the check.
+func (e *Engine) SetStagnation(rounds int, minNovelty float64) {
+	e.stagnationRounds = rounds
+	e.minNovelty = minNovelty
+}
+
+// SetRetriever lets debaters request evidence with "EVIDENCE NEEDED: <query>"
+// lines. Requests are answered by r after each round and shared with every
+// agent from the next round on, up to budget queries per debate.
+func (e *Engine) SetRetriever(r Retriever, budget int) {
+	e.retriever = r
+	e.evidenceBudget = budget
+}
+
+// SetCheckpointer makes the engine save the transcript with c at the end of
+// every round. A failed checkpoint stops the debate.
+func (e *Engine) SetCheckpointer(c Checkpointer) {
+	e.checkpointer = c
+}
+
+// SetRetryBudget caps the failed LLM calls retried over the whole debate at
+// n. Once it is spent the call in progress is abandoned and the debate ends
+// early with the rounds completed so far, flagged Partial. A value below 1
+// leaves retries unlimited.
+func (e *Engine) SetRetryBudget(n int) {
+	e.retryBudget = n
+}
+
+// SetFallbackModels sets the models tried in order, for one call at a time,
+// when an agent's model is unavailable or its circuit is open. The turn
+// records the model that answered.
+func (e *Engine) SetFallbackModels(models []string) {
+	e.fallbackModels = models
+}
+
+// SetMaxTokens caps the completion length of every agent turn at n tokens,
+// overriding the LLM client's own cap. A value below 1 leaves it to the
+// client.
+func (e *Engine) SetMaxTokens(n int) {
+	e.maxTokens = n
+}
+
+// SetMaxWords limits turns to n words. An agent whose reply is longer is
+// asked once to restate it concisely; a restatement that is still too long
+// is truncated. A value below 1 disables the limit.
+func (e *Engine) SetMaxWords(n int) {
+	e.maxWords = n
+}
+
+// Run executes the full debate: Phase 1 (free debate) and optionally Phase 2
+// (tenth man), as a state machine starting in StateOpening.
 func (e *Engine) Run(ctx context.Context) (*Result, error) {
-	if e.OnPhase != nil {
-		e.OnPhase(FreeDebate)
+	if err := ValidateAgents(e.agents); err != nil {
+		return nil, fmt.Errorf("debate: %w", err)
 	}
+	return e.run(ctx, &Machine{engine: e}, StateOpening)
+}
 
-	// Phase 1: Free Debate
-	var consensus *ConsensusResult
-	for round := 1; round <= e.maxRounds; round++ {
-		if err := e.runRound(ctx, round); err != nil {
-			return nil, err
-		}
-		if round >= e.minRounds {
-			var err error
-			consensus, err = e.judge.Evaluate(ctx, e.transcript)
-			if err != nil {
-				return nil, fmt.Errorf("debate: consensus evaluation: %w", err)
-			}
-			if consensus.Detected && consensus.Score >= 7 {
-				break
-			}
-		}
+// Resume finishes a debate interrupted after prior's last completed round,
+// such as a checkpoint saved before a crash, as Run would have. The engine
+// must be set up like the original one, with the debaters who took part.
+func (e *Engine) Resume(ctx context.Context, prior *Transcript) (*Result, error) {
+	if err := ValidateAgents(e.agents); err != nil {
+		return nil, fmt.Errorf("debate: %w", err)
 	}
+	e.transcript = prior
+	e.topic = prior.Topic
+	if prior.Phase == TenthManPhase {
+		return e.run(ctx, &Machine{engine: e}, StateTenthMan)
+	}
+	e.emit(PhaseChanged{Phase: FreeDebate})
+	return e.run(ctx, &Machine{engine: e}, StateFreeDebate)
+}
 
-	// Phase 2: Tenth Man
-	if consensus != nil && consensus.Detected && consensus.Score >= 7 {
-		e.transcript.Phase = TenthManPhase
-		if e.OnPhase != nil {
-			e.OnPhase(TenthManPhase)
-		}
+// rejoinTenthMan adds the Tenth Man challenging position to the debaters.
+func (e *Engine) rejoinTenthMan(position string) Agent {
+	model := e.tenthManModel
+	if model == "" {
+		model = e.agents[0].Model
+	}
+	tmAgent := e.tenthMan.BuildAgent(position, len(e.agents)+1, model)
+	e.agents = append(e.agents, tmAgent)
+	e.consensusPosition = position
+	return tmAgent
+}
 
-		model := e.tenthManModel
-		if model == "" {
-			model = e.agents[0].Model
-		}
-		tmAgent := e.tenthMan.BuildAgent(consensus.Position, len(e.agents)+1, model)
-		e.agents = append(e.agents, tmAgent)
-		e.consensusPosition = consensus.Position
-
-		startRound := e.transcript.Rounds + 1
-		for round := startRound; round < startRound+tenthManRounds; round++ {
-			if err := e.runRound(ctx, round); err != nil {
-				return nil, err
-			}
-		}
-		var err error
-		consensus, err = e.judge.Evaluate(ctx, e.transcript)
-		if err != nil {
-			return nil, fmt.Errorf("debate: final consensus evaluation: %w", err)
+// tenthManStart returns the first round the Tenth Man spoke in, or the round
+// after the last one if it has not spoken yet.
+func tenthManStart(t *Transcript) int {
+	for _, turn := range t.Turns {
+		if turn.Agent.Role == "tenth-man" {
+			return turn.Round
 		}
 	}
+	return t.Rounds + 1
+}
 
+// result completes the debate with the minority reports for consensus and,
+// after a Tenth Man phase, how the challenged position changed. Failing to
+// describe the change is not fatal unless the retry budget ran out.
+func (e *Engine) result(ctx context.Context, consensus *ConsensusResult, stagnated bool) (*Result, error) {
+	var err error
+	e.transcript.PositionChange, err = e.positionChange(ctx, consensus)
+	partial := errors.Is(err, ErrRetryBudgetExceeded)
+	reports, err := e.minorityReports(ctx, consensus)
+	if errors.Is(err, ErrRetryBudgetExceeded) {
+		partial = true
+	} else if err != nil {
+		return nil, err
+	}
+	e.transcript.Outcome = Classify(e.transcript, consensus)
 	return &Result{
-		Transcript: e.transcript,
-		Consensus:  consensus,
+		Transcript:      e.transcript,
+		Consensus:       consensus,
+		Stagnated:       stagnated,
+		Partial:         partial,
+		Outcome:         e.transcript.Outcome,
+		MinorityReports: reports,
 	}, nil
 }
 
-func (e *Engine) runRound(ctx context.Context, round int) error {
-	for _, agent := range e.agents {
-		if err := ctx.Err(); err != nil {
-			return fmt.Errorf("debate: %w", err)
+// partial ends a debate whose retry budget ran out with the rounds completed
+// so far, judged once if there are any.
+func (e *Engine) partial(ctx context.Context) (*Result, error) {
+	e.transcript.Outcome = VerdictNoConsensus
+	result := &Result{Transcript: e.transcript, Partial: true, Outcome: VerdictNoConsensus}
+	if e.transcript.Rounds == 0 {
+		return result, nil
+	}
+	consensus, err := e.judge.Evaluate(ctx, e.transcript)
+	if err != nil {
+		return nil, fmt.Errorf("debate: consensus evaluation: %w", err)
+	}
+	e.consensusEvaluated(consensus)
+	result.Consensus = consensus
+	e.transcript.Outcome = Classify(e.transcript, consensus)
+	result.Outcome = e.transcript.Outcome
+	return result, nil
+}
+
+// minorityReports asks every agent the judge lists as dissenting to summarize
+// its unresolved objections. Dissenters that match no agent are skipped. On
+// error, the reports written so far are returned with it.
+func (e *Engine) minorityReports(ctx context.Context, consensus *ConsensusResult) ([]MinorityReport, error) {
+	if consensus == nil {
+		return nil, nil
+	}
+	var reports []MinorityReport
+	for _, name := range consensus.Dissenters {
+		idx := slices.IndexFunc(e.agents, func(a Agent) bool { return strings.EqualFold(a.Name, name) })
+		if idx < 0 {
+			continue
 		}
-		msgs := buildMessages(agent, e.topic, e.transcript, e.tenthMan, e.consensusPosition)
-		resp, err := e.llm.ChatCompletion(ctx, agent.Model, msgs)
+		agent := e.agents[idx]
+		_, shown := e.view()
+		msgs := withImages(minorityReportMessages(agent, e.topic, consensus.Position, shown), e.images)
+		resp, _, err := e.complete(ctx, agent, msgs)
 		if err != nil {
-			return fmt.Errorf("debate: agent %s: %w", agent.Name, err)
+			return reports, fmt.Errorf("debate: minority report for %s: %w", agent.Name, err)
 		}
 		content := ""
 		if len(resp.Choices) > 0 {
 			content = resp.Choices[0].Message.Content
 		}
-		turn := Turn{
-			Round:   round,
-			Agent:   agent,
-			Content: content,
+		reports = append(reports, MinorityReport{Agent: agent, Objections: content})
+	}
+	return reports, nil
+}
+
+// agentOptions returns the per-request options configured on agent.
+func agentOptions(agent Agent) []openrouter.Option {
+	var opts []openrouter.Option
+	if agent.Temperature != nil {
+		opts = append(opts, openrouter.WithTemperature(*agent.Temperature))
+	}
+	if agent.ReasoningEffort != "" {
+		opts = append(opts, openrouter.WithReasoning(agent.ReasoningEffort))
+	}
+	return opts
+}
+
+// Continue runs rounds more rounds on top of prior, a finished transcript,
+// then re-evaluates consensus. The engine's agents should be the debaters who
+// took part; if prior reached the Tenth Man phase, the Tenth Man rejoins to
+// keep challenging the recorded consensus position. Each note is added to the
+// transcript as a moderator turn before the first new round.
+func (e *Engine) Continue(ctx context.Context, prior *Transcript, rounds int, notes []string) (*Result, error) {
+	if err := ValidateAgents(e.agents); err != nil {
+		return nil, fmt.Errorf("debate: %w", err)
+	}
+	if rounds < 1 {
+		return nil, fmt.Errorf("debate: rounds must be >= 1, got %d", rounds)
+	}
+	e.transcript = prior
+	e.topic = prior.Topic
+	if prior.Phase == TenthManPhase {
+		e.rejoinTenthMan(prior.ConsensusPosition)
+	}
+
+	for _, note := range notes {
+		e.InjectEvent(note)
+	}
+	m := &Machine{engine: e}
+	for range rounds {
+		if err := m.RunRound(ctx); err != nil {
+			if errors.Is(err, ErrRetryBudgetExceeded) {
+				return e.partial(ctx)
+			}
+			return nil, err
 		}
-		e.transcript.Turns = append(e.transcript.Turns, turn)
-		if e.OnTurn != nil {
-			e.OnTurn(turn)
+	}
+	return e.run(ctx, m, StateVote)
+}
+
+func (e *Engine) runRound(ctx context.Context, round int) error {
+	e.emit(RoundStarted{RoundSummary{Round: round, Phase: e.transcript.Phase}})
+	firstTurn := len(e.transcript.Turns)
+	e.addPendingNotes(round)
+	e.applySwaps(round)
+	e.applyRemovals(round)
+	e.applyJoins(ctx, round)
+	speak := e.speakInOrder
+	switch {
+	case e.statement(round) != "":
+		speak = e.makeStatements
+	case e.crossExamining(round):
+		speak = e.crossExamine
+	}
+	if err := speak(ctx, round); err != nil {
+		if errors.Is(err, ErrRetryBudgetExceeded) || wasStopped(ctx) {
+			// Drop the unfinished round so the transcript ends cleanly.
+			e.truncate(firstTurn)
 		}
+		return err
 	}
 	e.transcript.Rounds = round
+	summary := RoundSummary{Round: round, Phase: e.transcript.Phase, Turns: e.transcript.Turns[firstTurn:]}
+	if rc, ok := roundConfidence(e.transcript.Turns, round); ok {
+		e.transcript.Confidence = append(e.transcript.Confidence, rc)
+		summary.Confidence = &rc
+	}
+	firstEvidence := len(e.transcript.Evidence)
+	if err := e.gatherEvidence(ctx, round); err != nil {
+		return fmt.Errorf("debate: %w", err)
+	}
+	if e.checkpointer != nil {
+		if err := e.checkpointer.Checkpoint(ctx, e.transcript); err != nil {
+			return fmt.Errorf("debate: checkpoint after round %d: %w", round, err)
+		}
+	}
+	summary.Evidence = e.transcript.Evidence[firstEvidence:]
+	e.emit(RoundEnded{summary})
+	return nil
+}
+
+// speakInOrder has every agent take a turn in round, in order.
+func (e *Engine) speakInOrder(ctx context.Context, round int) error {
+	for _, agent := range e.agents {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("debate: %w", err)
+		}
+		if _, err := e.takeTurn(ctx, round, agent, e.turnMessages(agent), 0); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// turnMessages returns the messages asking agent for its next turn.
+func (e *Engine) turnMessages(agent Agent) []openrouter.Message {
+	transcript, shown := e.view()
+	system := turnSystemPrompt(agent, e.topic, e.guidance(), transcript, e.tenthMan, e.consensusPosition, e.retriever != nil)
+	if e.mayPass(agent, e.transcript.Rounds+1) {
+		system += " " + passInstruction
+	}
+	msgs := turnRequest(e.sharedPrompt(system, shown), transcript)
+	return withBriefing(msgs, briefing(transcript, agent))
+}
+
+// takeTurn asks agent for a turn in round with msgs and adds it to the
+// transcript. If replyTo is not 0 the turn replies to that turn, whatever
+// the agent says. A failed turn the engine tolerates is recorded without
+// content; otherwise the error is returned.
+func (e *Engine) takeTurn(ctx context.Context, round int, agent Agent, msgs []openrouter.Message, replyTo int) (Turn, error) {
+	defer e.release(msgs)
+	start := time.Now()
+	msgs, condensed := e.fitContext(agent.Model, msgs)
+	resp, model, samples, err := e.sample(ctx, agent, msgs)
+	latency := time.Since(start)
+	if err != nil {
+		turn, err := e.turnFailed(ctx, round, agent, err)
+		if err != nil {
+			return Turn{}, fmt.Errorf("debate: agent %s: %w", agent.Name, err)
+		}
+		return turn, nil
+	}
+	e.recordMu.Lock()
+	delete(e.failures, agent.Name)
+	e.recordMu.Unlock()
+	critique := ""
+	if e.refine {
+		resp, critique = e.refineDraft(ctx, agent, model, msgs, resp)
+		latency = time.Since(start)
+	}
+	content, trace := "", ""
+	if len(resp.Choices) > 0 {
+		content, trace = splitReasoning(resp.Choices[0].Message)
+	}
+	content, injection := screen(content)
+	draft := content
+	recorded, _ := e.view()
+	inReplyTo, content := parseReply(content, recorded.Turns)
+	if replyTo != 0 {
+		inReplyTo = replyTo
+	}
+	confidence, content := parseConfidence(content)
+	passed := e.mayPass(agent, round) && isPass(content)
+	if passed {
+		content, inReplyTo = "", 0
+	}
+	statement := e.statement(round)
+	if statement == OpeningStatement {
+		inReplyTo = 0
+	}
+	tokens := 0
+	if resp.Usage != nil {
+		tokens = resp.Usage.TotalTokens
+	}
+	agent.Model = model
+	turn := Turn{
+		Round:      round,
+		Agent:      agent,
+		Content:    content,
+		InReplyTo:  inReplyTo,
+		Confidence: confidence,
+		Tokens:     tokens,
+	}
+	if samples > 1 {
+		turn.Samples = samples
+	}
+	turn.Critique = critique
+	turn.Passed = passed
+	turn.Statement = statement
+	turn.Condensed = condensed
+	if e.maxWords > 0 && countWords(content) > e.maxWords {
+		e.shorten(ctx, agent, msgs, draft, &turn)
+		latency = time.Since(start)
+	}
+	turn.LatencyMS = int(latency.Milliseconds())
+	turn = e.record(turn, trace)
+	e.warnInjection(round, fmt.Sprintf("turn #%d", turn.ID), agent.Name, injection)
+	return turn, nil
+}
+
+// complete makes an LLM call for agent and returns the model that answered.
+// If agent's model is unavailable, the fallback models are tried in order.
+func (e *Engine) complete(ctx context.Context, agent Agent, msgs []openrouter.Message) (*openrouter.ChatResponse, string, error) {
+	model := agent.Model
+	resp, err := e.call(ctx, agent, model, msgs)
+	for _, fallback := range e.fallbackModels {
+		if err == nil || !modelUnavailable(err) {
+			break
+		}
+		if fallback == model || fallback == agent.Model {
+			continue
+		}
+		e.emit(AgentError{Agent: agent, Err: fmt.Errorf("%w; falling back to %s", err, fallback), WillRetry: true})
+		model = fallback
+		resp, err = e.call(ctx, agent, model, msgs)
+	}
+	if err != nil {
+		e.reportError(agent, err)
+		return nil, "", err
+	}
+	return resp, model, nil
+}
+
+// modelUnavailable reports whether err means the model could not be used,
+// so another model should be tried.
+func modelUnavailable(err error) bool {
+	return errors.Is(err, openrouter.ErrModelUnavailable) || errors.Is(err, openrouter.ErrCircuitOpen)
+}
+
+// call makes one LLM call on model for agent, reporting failed attempts that
+// will be retried as AgentError events. Once the retry budget is spent, the
+// call is abandoned instead of retried again.
+func (e *Engine) call(ctx context.Context, agent Agent, model string, msgs []openrouter.Message) (*openrouter.ChatResponse, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var lastErr error
+	opts := append(agentOptions(agent), openrouter.WithMaxTokens(e.maxTokens), openrouter.WithRetryHook(func(err error) {
+		if e.retryBudget > 0 && e.retries.Add(1) > int64(e.retryBudget) {
+			lastErr = err
+			cancel(ErrRetryBudgetExceeded)
+			return
+		}
+		e.emit(AgentError{Agent: agent, Err: err, WillRetry: true})
+	}))
+	// Turn prompts share their backing array with the next turn's, so the
+	// client gets a copy it may keep or modify.
+	resp, err := e.llm.ChatCompletion(ctx, model, slices.Clone(msgs), opts...)
+	if err != nil && lastErr != nil && errors.Is(context.Cause(ctx), ErrRetryBudgetExceeded) {
+		err = fmt.Errorf("%w after %d retries: %w", ErrRetryBudgetExceeded, e.retryBudget, lastErr)
+	}
+	return resp, err
+}
+
+// reportError emits an AgentError for a call for agent that failed for good.
+func (e *Engine) reportError(agent Agent, err error) {
+	e.emit(AgentError{Agent: agent, Err: err})
+}
+
+// consensusEvaluated weighs a judge's verdict by participation, records its
+// score, per-agent scores and diagnostics in the transcript and emits it.
+func (e *Engine) consensusEvaluated(consensus *ConsensusResult) {
+	weighParticipation(consensus, e.transcript)
+	if !consensus.Fallback {
+		e.transcript.Evaluations++
+	}
+	e.transcript.ConsensusScores = append(e.transcript.ConsensusScores, RoundScore{
+		Round:    e.transcript.Rounds,
+		Score:    consensus.Score,
+		Detected: consensus.Detected,
+		Fallback: consensus.Fallback,
+	})
+	if len(consensus.AgentScores) > 0 {
+		e.transcript.Agreement = append(e.transcript.Agreement, RoundAgreement{Round: e.transcript.Rounds, Scores: consensus.AgentScores})
+	}
+	for _, d := range consensus.Diagnostics {
+		d.Round = e.transcript.Rounds
+		e.transcript.JudgeDiagnostics = append(e.transcript.JudgeDiagnostics, d)
+	}
+	e.emit(ConsensusEvaluated{Result: consensus})
+}
@@ -0,0 +1,20 @@
+package debate
+
+import "errors"
+
+// Error kinds callers can branch on with errors.Is. LLM failures wrap the
+// client's errors, such as openrouter.ErrRateLimited, unchanged.
+var (
+	// ErrConsensusParse means the consensus judge never returned a verdict
+	// that could be parsed.
+	ErrConsensusParse = errors.New("consensus verdict could not be parsed")
+	// ErrBudgetExceeded means the debate used up its token budget.
+	ErrBudgetExceeded = errors.New("token budget exceeded")
+	// ErrRetryBudgetExceeded means the debate retried as many failed LLM
+	// calls as its retry budget allows. The engine ends the debate early
+	// rather than failing it.
+	ErrRetryBudgetExceeded = errors.New("retry budget exceeded")
+	// ErrStopped means the debate was ended by Engine.Stop. The engine ends
+	// the debate early rather than failing it.
+	ErrStopped = errors.New("debate stopped")
+)
@@ -0,0 +1,148 @@
+package debate
+
+// Event is something that happened while the engine ran. Events are emitted
+// in order, both to the engine's OnX callbacks and to the channel set with
+// SetEvents.
+type Event interface {
+	event()
+}
+
+// TurnCompleted is emitted after every turn, including moderator notes.
+type TurnCompleted struct {
+	Turn Turn
+}
+
+// PhaseChanged is emitted when a debate phase starts.
+type PhaseChanged struct {
+	Phase Phase
+}
+
+// RoundStarted is emitted before the first turn of a round. Only Round and
+// Phase are set.
+type RoundStarted struct {
+	RoundSummary
+}
+
+// RoundEnded is emitted once a round's turns and evidence are complete.
+type RoundEnded struct {
+	RoundSummary
+}
+
+// EvidenceGathered is emitted for every answered evidence request.
+type EvidenceGathered struct {
+	Evidence Evidence
+}
+
+// ConsensusEvaluated is emitted after every consensus evaluation.
+type ConsensusEvaluated struct {
+	Result *ConsensusResult
+}
+
+// TenthManActivated is emitted when the Tenth Man joins to challenge Position.
+type TenthManActivated struct {
+	Agent    Agent
+	Position string
+}
+
+// AgentError is emitted when an LLM call for Agent fails. WillRetry reports
+// whether the call is about to be retried; if not, the turn failed, and the
+// run fails with Err unless the engine tolerates failed turns (see
+// SetMaxFailures).
+type AgentError struct {
+	Agent     Agent
+	Err       error
+	WillRetry bool
+}
+
+// ModelSwapped is emitted when an agent's model is replaced between rounds.
+type ModelSwapped struct {
+	Swap ModelSwap
+}
+
+// AgentRemoved is emitted when a debater is taken out of the debate.
+type AgentRemoved struct {
+	Removal AgentRemoval
+}
+
+// AgentJoined is emitted when a debater is added to the debate after it
+// started.
+type AgentJoined struct {
+	Join AgentJoin
+}
+
+// InjectionSuspected is emitted after a turn or evidence that looks like a
+// prompt injection attempt.
+type InjectionSuspected struct {
+	Warning InjectionWarning
+}
+
+func (TurnCompleted) event()      {}
+func (PhaseChanged) event()       {}
+func (RoundStarted) event()       {}
+func (RoundEnded) event()         {}
+func (EvidenceGathered) event()   {}
+func (ConsensusEvaluated) event() {}
+func (TenthManActivated) event()  {}
+func (AgentError) event()         {}
+func (ModelSwapped) event()       {}
+func (AgentRemoved) event()       {}
+func (AgentJoined) event()        {}
+func (InjectionSuspected) event() {}
+
+// SetEvents makes the engine send every event to ch as well as to its
+// callbacks. The engine blocks until each event is received, so ch must be
+// drained while the engine runs.
+func (e *Engine) SetEvents(ch chan<- Event) {
+	e.events = ch
+}
+
+// emit delivers ev to the matching callback and to the events channel. It
+// is safe to call from concurrent turns: events are delivered one at a
+// time, so callbacks and the channel's reader never run concurrently for
+// one engine.
+func (e *Engine) emit(ev Event) {
+	e.emitMu.Lock()
+	defer e.emitMu.Unlock()
+	e.deliver(ev)
+}
+
+// deliver delivers ev. e.emitMu must be held.
+func (e *Engine) deliver(ev Event) {
+	switch ev := ev.(type) {
+	case TurnCompleted:
+		if e.OnTurn != nil {
+			e.OnTurn(ev.Turn)
+		}
+	case PhaseChanged:
+		if e.OnPhase != nil {
+			e.OnPhase(ev.Phase)
+		}
+	case RoundStarted:
+		if e.OnRoundStart != nil {
+			e.OnRoundStart(ev.RoundSummary)
+		}
+	case RoundEnded:
+		if e.OnRoundEnd != nil {
+			e.OnRoundEnd(ev.RoundSummary)
+		}
+	case EvidenceGathered:
+		if e.OnEvidence != nil {
+			e.OnEvidence(ev.Evidence)
+		}
+	case ConsensusEvaluated:
+		if e.OnConsensus != nil {
+			e.OnConsensus(ev.Result)
+		}
+	case TenthManActivated:
+		if e.OnTenthManActivated != nil {
+			e.OnTenthManActivated(ev.Agent, ev.Position)
+		}
+	case AgentError:
+		if e.OnError != nil {
+			e.OnError(ev.Agent, ev.Err, ev.WillRetry)
+		}
+	}
+	if e.events != nil {
+		e.events <- ev
+	}
+}
@@ -0,0 +1,100 @@
+package debate
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+)
+
+const evidenceInstruction = `If a factual question would settle a point in dispute, add a line "EVIDENCE NEEDED: <search query>"; the results will be shared with everyone next round.`
+
+// evidenceRe matches an "EVIDENCE NEEDED: <query>" line.
+var evidenceRe = regexp.MustCompile(`(?im)^[ \t*_]*evidence needed[ \t*_]*:[ \t*_]*(.+?)[ \t*_]*$`)
+
+// Retriever looks up evidence for queries raised by agents.
+type Retriever interface {
+	Retrieve(ctx context.Context, query string) (string, error)
+}
+
+// Evidence is one answered evidence request.
+type Evidence struct {
+	Round       int    // round in which the request was made
+	RequestedBy string // agent name
+	Query       string
+	Result      string `json:",omitempty"`
+	Error       string `json:",omitempty"` // retrieval failure, if any
+}
+
+// parseEvidenceRequests returns the queries of every "EVIDENCE NEEDED" line in content.
+func parseEvidenceRequests(content string) []string {
+	var queries []string
+	for _, m := range evidenceRe.FindAllStringSubmatch(content, -1) {
+		if q := strings.TrimSpace(m[1]); q != "" {
+			queries = append(queries, q)
+		}
+	}
+	return queries
+}
+
+// gatherEvidence answers the new evidence requests made in round, skipping
+// queries already asked and stopping once the budget is spent. Retrieval
+// errors are recorded on the evidence rather than ending the debate.
+func (e *Engine) gatherEvidence(ctx context.Context, round int) error {
+	if e.retriever == nil {
+		return nil
+	}
+	for _, turn := range e.transcript.Turns {
+		if turn.Round != round {
+			continue
+		}
+		for _, query := range parseEvidenceRequests(turn.Content) {
+			if len(e.transcript.Evidence) >= e.evidenceBudget {
+				return nil
+			}
+			if e.evidenceAsked(query) {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			ev := Evidence{Round: round, RequestedBy: turn.Agent.Name, Query: query}
+			result, err := e.retriever.Retrieve(ctx, query)
+			var injection []string
+			if err != nil {
+				ev.Error = err.Error()
+			} else {
+				ev.Result, injection = screen(result)
+			}
+			e.transcript.Evidence = append(e.transcript.Evidence, ev)
+			e.emit(EvidenceGathered{Evidence: ev})
+			e.warnInjection(round, "evidence for "+query, turn.Agent.Name, injection)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) evidenceAsked(query string) bool {
+	for _, ev := range e.transcript.Evidence {
+		if strings.EqualFold(ev.Query, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// evidenceMessage summarizes the evidence gathered so far for the agents.
+func evidenceMessage(evidence []Evidence) string {
+	var sb strings.Builder
+	sb.WriteString("Evidence gathered so far:")
+	for _, ev := range evidence {
+		sb.WriteString("\n\nQuery: " + ev.Query + " (requested by " + ev.RequestedBy + ")\n")
+		if ev.Error != "" {
+			sb.WriteString("Not available: " + ev.Error)
+		} else {
+			sb.WriteString(guard.Wrap("evidence for "+ev.Query, ev.Result))
+		}
+	}
+	return sb.String()
+}
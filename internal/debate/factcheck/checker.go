@@ -0,0 +1,175 @@
+// Package factcheck verifies the factual claims in a debate's final
+// consensus position, flagging those that cannot be verified.
+package factcheck
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/llmjson"
+)
+
+const maxCheckRetries = 3
+
+// maxClaims caps how many claims of a position are checked.
+const maxClaims = 8
+
+// Verdicts of a checked claim.
+const (
+	Verified     = "verified"
+	Disputed     = "disputed"
+	Unverifiable = "unverifiable"
+)
+
+// Checker fact-checks a consensus position using an LLM and, if set, a
+// Retriever.
+type Checker struct {
+	llm       debate.LLMClient
+	model     string
+	retriever debate.Retriever
+}
+
+// NewChecker creates a new Checker.
+func NewChecker(llm debate.LLMClient, model string) *Checker {
+	return &Checker{llm: llm, model: model}
+}
+
+// SetRetriever makes the checker look up evidence for every claim and judge
+// it against that evidence only, rather than the model's own knowledge.
+func (c *Checker) SetRetriever(r debate.Retriever) {
+	c.retriever = r
+}
+
+// Check returns a verdict on each factual claim in the consensus position
+// of the debate on topic, in the order the claims appear. A claim the model
+// gives no verdict for is unverifiable. An empty position, or one the model
+// never splits into claims as valid JSON, returns nil rather than an error.
+func (c *Checker) Check(ctx context.Context, topic, position string) ([]debate.FactCheck, error) {
+	if strings.TrimSpace(position) == "" {
+		return nil, nil
+	}
+	claims, err := c.claims(ctx, topic, position)
+	if err != nil || len(claims) == 0 {
+		return nil, err
+	}
+	checks := make([]debate.FactCheck, len(claims))
+	for i, cl := range claims {
+		checks[i] = debate.FactCheck{Claim: cl.Claim, Verdict: Unverifiable}
+		if c.retriever == nil {
+			continue
+		}
+		evidence, err := c.retriever.Retrieve(ctx, cmp.Or(cl.Query, cl.Claim))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("factcheck: %w", ctx.Err())
+			}
+			checks[i].Note = fmt.Sprintf("Retrieval failed: %v", err)
+			continue
+		}
+		checks[i].Evidence = strings.TrimSpace(evidence)
+	}
+	if err := c.verify(ctx, topic, checks); err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
+
+type claim struct {
+	Claim string `json:"claim"`
+	Query string `json:"query"`
+}
+
+// claims splits position into its factual claims, each with a search query
+// that would verify it.
+func (c *Checker) claims(ctx context.Context, topic, position string) ([]claim, error) {
+	out, err := ask(ctx, c, llmjson.Prompt[struct {
+		Claims []claim `json:"claims"`
+	}]{
+		System:   "You are a fact-checker. List the factual claims in the conclusion of a debate: statements about the world that are true or false, such as figures, dates, events, studies or how something works. Skip opinions, recommendations and predictions. " + guard.Instruction,
+		Schema:   `{"claims": [{"claim": "...", "query": "..."}]}`,
+		Notes:    fmt.Sprintf(`"claim" restates one claim so it stands on its own and "query" is a search query that would verify it. List at most %d claims, the most consequential first, or an empty list if there are none.`, maxClaims),
+		User:     fmt.Sprintf("Debate topic: %s\n\nConclusion:\n%s", topic, guard.Wrap("conclusion", position)),
+		Required: []string{"claims"},
+	})
+	if err != nil || out == nil {
+		return nil, err
+	}
+	var claims []claim
+	for _, cl := range out.Claims {
+		cl.Claim, cl.Query = strings.TrimSpace(cl.Claim), strings.TrimSpace(cl.Query)
+		if cl.Claim != "" && len(claims) < maxClaims {
+			claims = append(claims, cl)
+		}
+	}
+	return claims, nil
+}
+
+// verify sets the verdict and note of each of checks, whose Evidence is
+// filled in when the checker has a Retriever.
+func (c *Checker) verify(ctx context.Context, topic string, checks []debate.FactCheck) error {
+	basis := "your own knowledge. If you are not confident a claim is true or false, it is unverifiable"
+	if c.retriever != nil {
+		basis = "the evidence given under each claim only, not your own knowledge. A claim the evidence neither supports nor contradicts is unverifiable"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Debate topic: %s\n\n", topic)
+	for i, check := range checks {
+		fmt.Fprintf(&sb, "Claim %d: %s\n", i+1, guard.Wrap(fmt.Sprintf("claim %d", i+1), check.Claim))
+		if c.retriever != nil {
+			evidence := "(none found)"
+			if check.Evidence != "" {
+				evidence = guard.Wrap(fmt.Sprintf("evidence for claim %d", i+1), check.Evidence)
+			}
+			fmt.Fprintf(&sb, "Evidence: %s\n", evidence)
+		}
+		sb.WriteString("\n")
+	}
+
+	out, err := ask(ctx, c, llmjson.Prompt[struct {
+		Checks []struct {
+			Claim   int    `json:"claim"`
+			Verdict string `json:"verdict"`
+			Note    string `json:"note"`
+		} `json:"checks"`
+	}]{
+		System:   fmt.Sprintf("You are a fact-checker. Judge each numbered claim from a debate's conclusion using %s. %s", basis, guard.Instruction),
+		Schema:   `{"checks": [{"claim": 1, "verdict": "verified", "note": "..."}]}`,
+		Notes:    `"verdict" is "verified", "disputed" (the claim is false or contradicted) or "unverifiable", and "note" says in one sentence why.`,
+		User:     strings.TrimSpace(sb.String()),
+		Required: []string{"checks"},
+	})
+	if err != nil || out == nil {
+		// Without verdicts every claim stays unverifiable.
+		return err
+	}
+	for _, v := range out.Checks {
+		verdict := strings.ToLower(strings.TrimSpace(v.Verdict))
+		if v.Claim < 1 || v.Claim > len(checks) || (verdict != Verified && verdict != Disputed && verdict != Unverifiable) {
+			continue
+		}
+		checks[v.Claim-1].Verdict = verdict
+		if note := strings.TrimSpace(v.Note); note != "" {
+			checks[v.Claim-1].Note = note
+		}
+	}
+	return nil
+}
+
+// ask runs a structured-output step of the check. It returns nil if the
+// model never gives a usable answer, and fails if a call does.
+func ask[T any](ctx context.Context, c *Checker, p llmjson.Prompt[T]) (*T, error) {
+	p.Attempts = maxCheckRetries
+	out, err := llmjson.StructuredCall(ctx, c.llm, c.model, p)
+	if errors.As(err, new(*llmjson.InvalidError)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("factcheck: %w", err)
+	}
+	return out, nil
+}
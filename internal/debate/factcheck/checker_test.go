@@ -0,0 +1,131 @@
+package factcheck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+type mockLLM struct {
+	responses []string
+	err       error
+	calls     int
+	prompts   []string
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.prompts = append(m.prompts, msgs[0].Content+"\n"+msgs[1].Content)
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+type mockRetriever struct {
+	results map[string]string
+	queries []string
+}
+
+func (r *mockRetriever) Retrieve(_ context.Context, query string) (string, error) {
+	r.queries = append(r.queries, query)
+	if res, ok := r.results[query]; ok {
+		return res, nil
+	}
+	return "", errors.New("not found")
+}
+
+const claimsJSON = `{"claims": [{"claim": "Caching cut latency by 40% at Acme", "query": "acme caching latency"}, {"claim": " "}, {"claim": "Redis was released in 2009", "query": "redis release year"}]}`
+
+func TestCheckWithoutRetriever(t *testing.T) {
+	llm := &mockLLM{responses: []string{
+		claimsJSON,
+		"```json\n" + `{"checks": [{"claim": 2, "verdict": "Verified", "note": "Well known."}, {"claim": 7, "verdict": "disputed"}]}` + "\n```",
+	}}
+	got, err := NewChecker(llm, "m").Check(context.Background(), "topic", "Adopt caching: it cut latency by 40% at Acme.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected the blank claim dropped, got %+v", got)
+	}
+	if got[0].Verdict != Unverifiable || got[0].Claim != "Caching cut latency by 40% at Acme" {
+		t.Errorf("expected a claim without a verdict to be unverifiable, got %+v", got[0])
+	}
+	if got[1].Verdict != Verified || got[1].Note != "Well known." || got[1].Evidence != "" {
+		t.Errorf("unexpected second check %+v", got[1])
+	}
+	if !strings.Contains(llm.prompts[1], "your own knowledge") || strings.Contains(llm.prompts[1], "Evidence:") {
+		t.Errorf("expected a knowledge-based verification, got %q", llm.prompts[1])
+	}
+	for i, fenced := range []string{"<untrusted source=\"conclusion\">\nAdopt caching", "<untrusted source=\"claim 1\">\nCaching cut latency"} {
+		if !strings.Contains(llm.prompts[i], fenced) || !strings.Contains(llm.prompts[i], guard.Instruction) {
+			t.Errorf("expected call %d to fence %q as untrusted, got %q", i+1, fenced, llm.prompts[i])
+		}
+	}
+}
+
+func TestCheckWithRetriever(t *testing.T) {
+	llm := &mockLLM{responses: []string{
+		claimsJSON,
+		`{"checks": [{"claim": 1, "verdict": "disputed", "note": "The report says 4%."}, {"claim": 2, "verdict": "unverifiable"}]}`,
+	}}
+	retriever := &mockRetriever{results: map[string]string{"acme caching latency": "Acme reported a 4% latency drop."}}
+	c := NewChecker(llm, "m")
+	c.SetRetriever(retriever)
+	got, err := c.Check(context.Background(), "topic", "position")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(retriever.queries) != 2 || retriever.queries[1] != "redis release year" {
+		t.Errorf("expected one query per claim, got %v", retriever.queries)
+	}
+	if got[0].Verdict != Disputed || got[0].Evidence != "Acme reported a 4% latency drop." {
+		t.Errorf("unexpected first check %+v", got[0])
+	}
+	if got[1].Verdict != Unverifiable || !strings.Contains(got[1].Note, "Retrieval failed") {
+		t.Errorf("expected the retrieval failure kept as the note, got %+v", got[1])
+	}
+	if !strings.Contains(llm.prompts[1], "Evidence: <untrusted source=\"evidence for claim 1\">\nAcme reported") || !strings.Contains(llm.prompts[1], "Evidence: (none found)") {
+		t.Errorf("expected the evidence in the verification prompt, got %q", llm.prompts[1])
+	}
+}
+
+func TestCheckUnparseableVerdicts(t *testing.T) {
+	llm := &mockLLM{responses: []string{claimsJSON, "no json", "no json", "no json"}}
+	got, err := NewChecker(llm, "m").Check(context.Background(), "topic", "position")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Verdict != Unverifiable || got[1].Verdict != Unverifiable {
+		t.Errorf("expected every claim unverifiable, got %+v", got)
+	}
+	if llm.calls != 1+maxCheckRetries {
+		t.Errorf("expected %d calls, got %d", 1+maxCheckRetries, llm.calls)
+	}
+}
+
+func TestCheckNoClaims(t *testing.T) {
+	llm := &mockLLM{responses: []string{`{"claims": []}`}}
+	got, err := NewChecker(llm, "m").Check(context.Background(), "topic", "We should be careful.")
+	if err != nil || got != nil || llm.calls != 1 {
+		t.Errorf("expected nothing after one call, got %+v, %v after %d", got, err, llm.calls)
+	}
+	if got, err := NewChecker(llm, "m").Check(context.Background(), "topic", " "); err != nil || got != nil || llm.calls != 1 {
+		t.Errorf("expected no call for an empty position, got %+v, %v", got, err)
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	llm := &mockLLM{err: errors.New("boom")}
+	if _, err := NewChecker(llm, "m").Check(context.Background(), "topic", "position"); err == nil {
+		t.Error("expected the client error")
+	}
+}
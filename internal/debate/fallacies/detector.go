@@ -0,0 +1,99 @@
+// Package fallacies scans a debate transcript for logical fallacies and
+// cognitive biases, tagging the turns that show them.
+package fallacies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/llmjson"
+)
+
+const maxDetectRetries = 3
+
+// Categories of reasoning flags.
+const (
+	Fallacy = "fallacy"
+	Bias    = "bias"
+)
+
+// Detector finds flawed reasoning in a debate transcript using an LLM.
+type Detector struct {
+	llm   debate.LLMClient
+	model string
+}
+
+// NewDetector creates a new Detector.
+func NewDetector(llm debate.LLMClient, model string) *Detector {
+	return &Detector{llm: llm, model: model}
+}
+
+// Detect returns the fallacies and biases in transcript's turns, in turn
+// order. Flags naming a turn that does not exist are dropped, and each flag's
+// agent is taken from its turn. If the model never returns valid JSON, it
+// returns nil rather than an error.
+func (d *Detector) Detect(ctx context.Context, transcript *debate.Transcript) ([]debate.ReasoningFlag, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Debate topic: %s\n\n", transcript.Topic)
+	var turns strings.Builder
+	for _, turn := range transcript.Turns {
+		if turn.Agent.Role != "moderator" && turn.Content != "" {
+			fmt.Fprintf(&turns, "[#%d] %s (%s): %s\n", turn.ID, turn.Agent.Name, turn.Agent.Role, turn.Content)
+		}
+	}
+	sb.WriteString(guard.Wrap("transcript", turns.String()))
+
+	out, err := llmjson.StructuredCall(ctx, d.llm, d.model, llmjson.Prompt[reply]{
+		System: "You are a critical-thinking examiner. Read a debate and flag the turns that commit a logical fallacy or show a cognitive bias. " + guard.Instruction,
+		Schema: `{"flags": [{"turn": 3, "kind": "strawman", "category": "fallacy", "excerpt": "...", "explanation": "..."}]}`,
+		Notes: `"turn" is the number in brackets before the turn. "category" is "fallacy" (e.g. strawman, ad hominem, appeal to authority, false dilemma, slippery slope, hasty generalization, circular reasoning, red herring) or "bias" (e.g. sunk cost, confirmation bias, anchoring, bandwagon, overconfidence, status quo bias). "kind" names it in lower case, "excerpt" quotes the words that show it and "explanation" says in one sentence why it is flawed.
+Flag only clear cases; a strong argument you disagree with is not a fallacy. Use an empty list if there are none.`,
+		User:     sb.String(),
+		Required: []string{"flags"},
+		Attempts: maxDetectRetries,
+	})
+	if errors.As(err, new(*llmjson.InvalidError)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fallacies: %w", err)
+	}
+	return out.flags(transcript.Turns), nil
+}
+
+// reply is the model's answer.
+type reply struct {
+	Flags []struct {
+		Turn        int    `json:"turn"`
+		Kind        string `json:"kind"`
+		Category    string `json:"category"`
+		Excerpt     string `json:"excerpt"`
+		Explanation string `json:"explanation"`
+	} `json:"flags"`
+}
+
+// flags returns the reasoning flags of r. Flags on unknown or moderator
+// turns, without a kind or of an unknown category are dropped.
+func (r *reply) flags(turns []debate.Turn) []debate.ReasoningFlag {
+	flags := []debate.ReasoningFlag{}
+	for _, f := range r.Flags {
+		category := strings.ToLower(strings.TrimSpace(f.Category))
+		kind := strings.ToLower(strings.TrimSpace(f.Kind))
+		if f.Turn < 1 || f.Turn > len(turns) || turns[f.Turn-1].Agent.Role == "moderator" || kind == "" || (category != Fallacy && category != Bias) {
+			continue
+		}
+		flags = append(flags, debate.ReasoningFlag{
+			TurnID:      f.Turn,
+			Agent:       turns[f.Turn-1].Agent.Name,
+			Kind:        kind,
+			Category:    category,
+			Excerpt:     strings.TrimSpace(f.Excerpt),
+			Explanation: strings.TrimSpace(f.Explanation),
+		})
+	}
+	return flags
+}
@@ -0,0 +1,108 @@
+package fallacies
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+type mockLLM struct {
+	responses []string
+	err       error
+	calls     int
+	system    string
+	prompt    string
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.system, m.prompt = msgs[0].Content, msgs[1].Content
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+func sampleTranscript() *debate.Transcript {
+	return &debate.Transcript{
+		Topic: "test topic",
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Role: "debater"}, Content: "Every expert agrees, so we should cache."},
+			{ID: 2, Round: 1, Agent: debate.Agent{Name: "Moderator", Role: "moderator"}, Content: "Stay on topic."},
+			{ID: 3, Round: 2, Agent: debate.Agent{Name: "Tenth Man", Role: "tenth-man"}, Content: "We already built it, so we must keep it."},
+		},
+	}
+}
+
+func TestDetectFlags(t *testing.T) {
+	llm := &mockLLM{responses: []string{"```json\n" + `{"flags": [
+		{"turn": 1, "kind": "Appeal to Authority", "category": "Fallacy", "excerpt": "Every expert agrees", "explanation": "No evidence is given."},
+		{"turn": 3, "kind": "sunk cost", "category": "bias"},
+		{"turn": 2, "kind": "red herring", "category": "fallacy"},
+		{"turn": 9, "kind": "strawman", "category": "fallacy"},
+		{"turn": 1, "kind": "vibes", "category": "mood"}
+	]}` + "\n```"}}
+	got, err := NewDetector(llm, "m").Detect(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 flags, got %+v", got)
+	}
+	if got[0].TurnID != 1 || got[0].Agent != "Alice" || got[0].Kind != "appeal to authority" || got[0].Category != Fallacy || got[0].Excerpt != "Every expert agrees" {
+		t.Errorf("unexpected first flag %+v", got[0])
+	}
+	if got[1].Agent != "Tenth Man" || got[1].Category != Bias {
+		t.Errorf("unexpected second flag %+v", got[1])
+	}
+	if strings.Contains(llm.prompt, "Stay on topic") || !strings.Contains(llm.prompt, "[#3] Tenth Man (tenth-man): We already built it") {
+		t.Errorf("expected numbered turns without the moderator, got %q", llm.prompt)
+	}
+}
+
+func TestDetectNoFlags(t *testing.T) {
+	llm := &mockLLM{responses: []string{`{"flags": []}`}}
+	got, err := NewDetector(llm, "m").Detect(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("expected an empty, non-nil list, got %#v", got)
+	}
+}
+
+func TestDetectRetriesThenGivesUp(t *testing.T) {
+	llm := &mockLLM{responses: []string{"no json here"}}
+	got, err := NewDetector(llm, "m").Detect(context.Background(), sampleTranscript())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != nil || llm.calls != maxDetectRetries {
+		t.Errorf("expected nothing after %d calls, got %+v after %d", maxDetectRetries, got, llm.calls)
+	}
+}
+
+func TestDetectError(t *testing.T) {
+	llm := &mockLLM{err: errors.New("boom")}
+	if _, err := NewDetector(llm, "m").Detect(context.Background(), sampleTranscript()); err == nil {
+		t.Error("expected the client error")
+	}
+}
+
+func TestDetectFencesTurns(t *testing.T) {
+	llm := &mockLLM{responses: []string{`{"flags": []}`}}
+	if _, err := NewDetector(llm, "m").Detect(context.Background(), sampleTranscript()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(llm.prompt, "<untrusted source=\"transcript\">\n[#1] Alice (debater): Every expert agrees") || !strings.Contains(llm.system, guard.Instruction) {
+		t.Errorf("expected the turns fenced as untrusted, got system %q and prompt %q", llm.system, llm.prompt)
+	}
+}
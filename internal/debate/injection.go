@@ -0,0 +1,32 @@
+package debate
+
+import "github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+
+// InjectionWarning flags text the debate took in that looks like an attempt
+// to inject instructions into the agents' prompts, such as a turn telling
+// the others to ignore their instructions. The text is kept, cleaned of
+// invisible characters, and shown to agents only as untrusted data, so the
+// warning is for whoever reads the debate.
+type InjectionWarning struct {
+	Round  int
+	Source string   // the text flagged: "turn #3" or "evidence for <query>"
+	Agent  string   `json:",omitempty"` // who wrote the turn or requested the evidence
+	Kinds  []string // what it looks like, as guard.Detect reports
+}
+
+// warnInjection records and emits a warning about the text from source if
+// kinds, what guard.Detect found in it, is not empty.
+func (e *Engine) warnInjection(round int, source, agent string, kinds []string) {
+	if len(kinds) == 0 {
+		return
+	}
+	w := InjectionWarning{Round: round, Source: source, Agent: agent, Kinds: kinds}
+	e.transcript.InjectionWarnings = append(e.transcript.InjectionWarnings, w)
+	e.emit(InjectionSuspected{Warning: w})
+}
+
+// screen returns text cleaned by guard.Clean and what guard.Detect found in
+// it beforehand.
+func screen(text string) (string, []string) {
+	return guard.Clean(text), guard.Detect(text)
+}
@@ -14,7 +14,7 @@ type simulatedLLM struct {
 	callCount int
 }
 
-func (m *simulatedLLM) ChatCompletion(_ context.Context, model string, msgs []openrouter.Message) (*openrouter.ChatResponse, error) {
+func (m *simulatedLLM) ChatCompletion(_ context.Context, model string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
 	m.callCount++
 	systemPrompt := ""
 	if len(msgs) > 0 {
@@ -0,0 +1,141 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// AgentJoin records a debater added to a debate after it started.
+type AgentJoin struct {
+	Round int // first round the agent speaks in
+	Agent Agent
+	// Briefing is the catch-up summary of the debate so far the agent is
+	// shown before its first turn; empty if it joined before any turn or
+	// the summary failed.
+	Briefing string `json:",omitempty"`
+}
+
+// AddAgent queues agent to join the debate as a debater at the start of the
+// next round, for instance a specialist summoned when a new question comes
+// up. Before its first turn the agent's model writes it a briefing on the
+// debate so far; the join is recorded in the transcript's Joins. An empty
+// Role means "debater". A join whose agent is invalid or whose name is
+// already taken by an agent of the debate, past or present, is dropped.
+// AddAgent is safe to call while Run is in progress.
+func (e *Engine) AddAgent(agent Agent) {
+	e.pendingMu.Lock()
+	defer e.pendingMu.Unlock()
+	e.pendingJoins = append(e.pendingJoins, agent)
+}
+
+// applyJoins adds every queued agent to the debate from round on.
+func (e *Engine) applyJoins(ctx context.Context, round int) {
+	e.pendingMu.Lock()
+	agents := e.pendingJoins
+	e.pendingJoins = nil
+	e.pendingMu.Unlock()
+	for _, agent := range agents {
+		if agent.Role == "" {
+			agent.Role = "debater"
+		}
+		if ValidateAgents([]Agent{agent}) != nil || e.nameTaken(agent.Name) {
+			continue
+		}
+		agent.ID = e.nextAgentID()
+		join := AgentJoin{Round: round, Agent: agent}
+		if len(e.transcript.Turns) > 0 {
+			briefing, err := e.brief(ctx, agent)
+			if err != nil {
+				e.reportError(agent, fmt.Errorf("catch-up briefing: %w", err))
+			}
+			join.Briefing = briefing
+		}
+		// Speak after the other debaters but before the Tenth Man, in a new
+		// slice so a round iterating over the old one is unaffected.
+		i := slices.IndexFunc(e.agents, func(a Agent) bool { return a.Role == "tenth-man" })
+		if i < 0 {
+			i = len(e.agents)
+		}
+		e.agents = slices.Insert(slices.Clone(e.agents), i, agent)
+		e.transcript.Joins = append(e.transcript.Joins, join)
+		e.emit(AgentJoined{Join: join})
+	}
+}
+
+// nameTaken reports whether name, compared case-insensitively, belongs to
+// an agent in the debate or to one that has spoken or been removed.
+func (e *Engine) nameTaken(name string) bool {
+	same := func(other string) bool { return strings.EqualFold(name, other) }
+	return slices.ContainsFunc(e.agents, func(a Agent) bool { return same(a.Name) }) ||
+		slices.ContainsFunc(e.transcript.Turns, func(t Turn) bool { return same(t.Agent.Name) }) ||
+		slices.ContainsFunc(e.transcript.Removals, func(r AgentRemoval) bool { return same(r.Agent) })
+}
+
+// nextAgentID returns an ID no agent of the debate has used.
+func (e *Engine) nextAgentID() int {
+	id := 0
+	for _, a := range e.agents {
+		id = max(id, a.ID)
+	}
+	for _, t := range e.transcript.Turns {
+		id = max(id, t.Agent.ID)
+	}
+	return id + 1
+}
+
+// brief asks agent's model for a catch-up summary of the debate so far.
+func (e *Engine) brief(ctx context.Context, agent Agent) (string, error) {
+	transcript, shown := e.view()
+	msgs := briefingMessages(e.topic, transcript, shown)
+	resp, _, err := e.complete(ctx, agent, msgs)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", nil
+	}
+	briefing, _ := splitReasoning(resp.Choices[0].Message)
+	return strings.TrimSpace(briefing), nil
+}
+
+// briefing returns the catch-up summary agent is shown before its first
+// turn in transcript, or "" once it has spoken.
+func briefing(transcript *Transcript, agent Agent) string {
+	if slices.ContainsFunc(transcript.Turns, func(t Turn) bool { return t.Agent.Name == agent.Name }) {
+		return ""
+	}
+	for _, j := range transcript.Joins {
+		if j.Agent.Name == agent.Name {
+			return j.Briefing
+		}
+	}
+	return ""
+}
+
+// withBriefing shows briefing just before the closing prompt of msgs. msgs
+// is returned unchanged if briefing is empty.
+func withBriefing(msgs []openrouter.Message, briefing string) []openrouter.Message {
+	if briefing == "" {
+		return msgs
+	}
+	brief := openrouter.Message{
+		Role:    "user",
+		Content: "You are joining this debate late. Catch-up summary of the debate so far:\n\n" + briefing,
+	}
+	return slices.Insert(msgs, len(msgs)-1, brief)
+}
+
+// WithJoined returns agents followed by the agents that joined t and have
+// not spoken yet, so a resumed or continued debate keeps them.
+func WithJoined(agents []Agent, t *Transcript) []Agent {
+	for _, j := range t.Joins {
+		if !slices.ContainsFunc(agents, func(a Agent) bool { return a.Name == j.Agent.Name }) {
+			agents = append(agents, j.Agent)
+		}
+	}
+	return agents
+}
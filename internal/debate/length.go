@@ -0,0 +1,89 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// Ways a turn over the word limit was shortened; see Turn.Shortened.
+const (
+	ShortenedRestated  = "restated"
+	ShortenedTruncated = "truncated"
+)
+
+// truncationMark ends a turn cut at the word limit.
+const truncationMark = " […]"
+
+// countWords returns the number of whitespace-separated words in s.
+func countWords(s string) int {
+	return len(strings.Fields(s))
+}
+
+// shorten brings turn, whose content is over the word limit, back under it.
+// The agent is asked once to restate its reply concisely; if the
+// restatement fails or is still too long, the content is truncated. draft is
+// the agent's reply without reasoning. Tokens of the restatement are added
+// to the turn's.
+func (e *Engine) shorten(ctx context.Context, agent Agent, msgs []openrouter.Message, draft string, turn *Turn) {
+	restate := append(msgs[:len(msgs):len(msgs)],
+		openrouter.Message{Role: "assistant", Content: draft},
+		openrouter.Message{Role: "user", Content: fmt.Sprintf(
+			"Your reply is too long. Restate it in at most %d words, keeping your strongest points. Keep any REPLY TO and CONFIDENCE lines.",
+			e.maxWords)},
+	)
+	if resp, err := e.call(ctx, agent, turn.Agent.Model, restate); err == nil && len(resp.Choices) > 0 {
+		if resp.Usage != nil {
+			turn.Tokens += resp.Usage.TotalTokens
+		}
+		content, _ := splitReasoning(resp.Choices[0].Message)
+		inReplyTo, content := parseReply(content, e.transcript.Turns)
+		confidence, content := parseConfidence(content)
+		if strings.TrimSpace(content) != "" && countWords(content) <= e.maxWords {
+			turn.Content = content
+			if inReplyTo != 0 {
+				turn.InReplyTo = inReplyTo
+			}
+			if confidence != nil {
+				turn.Confidence = confidence
+			}
+			turn.Shortened = ShortenedRestated
+			return
+		}
+	}
+	turn.Content = truncateWords(turn.Content, e.maxWords)
+	turn.Shortened = ShortenedTruncated
+}
+
+// truncateWords cuts s after n words. If a sentence ends in the second half
+// of what is kept, the cut backs off to it; otherwise truncationMark is
+// appended.
+func truncateWords(s string, n int) string {
+	end, count := 0, 0
+	inWord := false
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if inWord && count == n {
+				end = i
+				break
+			}
+			inWord = false
+			continue
+		}
+		if !inWord {
+			inWord = true
+			count++
+		}
+	}
+	if count < n || end == 0 {
+		return s
+	}
+	kept := s[:end]
+	if i := strings.LastIndexAny(kept, ".!?"); i >= len(kept)/2 {
+		return kept[:i+1]
+	}
+	return strings.TrimRightFunc(kept, unicode.IsSpace) + truncationMark
+}
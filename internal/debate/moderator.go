@@ -0,0 +1,41 @@
+package debate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// moderator is the speaker of notes added to the transcript from outside the
+// debate, such as new information introduced between rounds.
+var moderator = Agent{Name: "Moderator", Role: "moderator"}
+
+// InjectEvent queues a moderator note with new information. It is added to
+// the shared transcript at the start of the next round, so every agent sees it
+// from then on. InjectEvent is safe to call while Run is in progress.
+func (e *Engine) InjectEvent(content string) {
+	e.pendingMu.Lock()
+	defer e.pendingMu.Unlock()
+	e.pending = append(e.pending, content)
+}
+
+// addPendingNotes adds every queued note to round.
+func (e *Engine) addPendingNotes(round int) {
+	e.pendingMu.Lock()
+	notes := e.pending
+	e.pending = nil
+	e.pendingMu.Unlock()
+	for _, note := range notes {
+		e.addNote(round, note)
+	}
+}
+
+// addNote appends a moderator turn carrying content to the transcript as
+// part of round.
+func (e *Engine) addNote(round int, content string) {
+	content, injection := screen(strings.TrimSpace(content))
+	if content == "" {
+		return
+	}
+	turn := e.record(Turn{Round: round, Agent: moderator, Content: content}, "")
+	e.warnInjection(round, fmt.Sprintf("turn #%d", turn.ID), turn.Agent.Name, injection)
+}
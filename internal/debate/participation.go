@@ -0,0 +1,64 @@
+package debate
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// nonSubstantivePrefixes open replies that carry no argument, such as error
+// text a provider returned as content or a refusal to take part.
+var nonSubstantivePrefixes = []string{
+	"error:", "[error", "null", "undefined", "(no response", "[no response",
+	"i'm sorry, but i can't", "i am sorry, but i cannot", "i cannot assist", "i can't assist",
+}
+
+// Substantive reports whether a turn's content is a real contribution to the
+// debate: it has words, and it is not error text or a refusal.
+func Substantive(content string) bool {
+	content = strings.ToLower(strings.TrimSpace(content))
+	if !strings.ContainsFunc(content, unicode.IsLetter) {
+		return false
+	}
+	for _, p := range nonSubstantivePrefixes {
+		if strings.HasPrefix(content, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Participation returns the share of substantive turns among the debaters'
+// and the Tenth Man's turns in the latest round of transcript, or 1 if it
+// has none. Passed turns stand by an earlier one, so they count.
+func Participation(transcript *Transcript) float64 {
+	spoke, substantive := 0, 0
+	for _, turn := range transcript.Turns {
+		if turn.Round != transcript.Rounds || turn.Agent.Role == moderator.Role {
+			continue
+		}
+		spoke++
+		if turn.Passed || Substantive(turn.Content) {
+			substantive++
+		}
+	}
+	if spoke == 0 {
+		return 1
+	}
+	return float64(substantive) / float64(spoke)
+}
+
+// weighParticipation scales consensus's agreement score by the transcript's
+// participation, so agents who gave no substantive answer count against
+// agreement rather than for it. A consensus that drops below
+// ConsensusThreshold is no longer detected.
+func weighParticipation(consensus *ConsensusResult, transcript *Transcript) {
+	consensus.Participation = Participation(transcript)
+	if consensus.Participation == 1 {
+		return
+	}
+	consensus.Score = int(math.Round(float64(consensus.Score) * consensus.Participation))
+	if consensus.Score < ConsensusThreshold {
+		consensus.Detected = false
+	}
+}
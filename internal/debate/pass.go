@@ -0,0 +1,41 @@
+package debate
+
+import "regexp"
+
+const passInstruction = `If you have nothing new to add and still hold the position you argued last, reply with only the word PASS instead of repeating yourself.`
+
+// PassNote stands in for a passed turn wherever turns are shown as text.
+const PassNote = "[passes: nothing new to add, stands by the previous turn]"
+
+// passRe matches a reply that is only the word PASS, tolerating markdown
+// emphasis, quotes and a final full stop.
+var passRe = regexp.MustCompile("(?i)^[\\s*_\"'`]*pass[\\s*_\"'`.!]*$")
+
+// SetPassing lets debaters pass a free debate turn when they have nothing
+// new to add. A passed turn is recorded without content, flagged Passed,
+// and counts as standing by the agent's previous turn: participation counts
+// it as substantive, and judges read it as continued agreement. Passing is
+// never offered in the first round, in a cross-examination or in Phase 2,
+// where debaters must answer the Tenth Man.
+func (e *Engine) SetPassing(on bool) {
+	e.passing = on
+}
+
+// mayPass reports whether agent may pass its turn in round.
+func (e *Engine) mayPass(agent Agent, round int) bool {
+	return e.passing && round > 1 && e.transcript.Phase == FreeDebate && agent.Role != "tenth-man" && !e.crossExamining(round)
+}
+
+// isPass reports whether content, a reply without its "Re:" and confidence
+// lines, passes the turn.
+func isPass(content string) bool {
+	return passRe.MatchString(content)
+}
+
+// Text returns the turn's content, or PassNote if the agent passed.
+func (t Turn) Text() string {
+	if t.Passed {
+		return PassNote
+	}
+	return t.Content
+}
@@ -0,0 +1,71 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// PositionChange compares the consensus the Tenth Man challenged with the
+// one judged after the Tenth Man rounds, answering whether the challenge
+// changed anything.
+type PositionChange struct {
+	Before  string // the Phase 1 consensus position
+	After   string // the final consensus position; empty if consensus broke
+	Changed bool   // whether the position materially changed
+	Summary string `json:",omitempty"` // how it changed, as described by a model
+}
+
+// changedRe matches the "CHANGED: yes|no" line the diff prompt asks for.
+var changedRe = regexp.MustCompile(`(?im)^[ \t*_]*changed[ \t*_]*:[ \t*_]*(yes|no)\b.*$`)
+
+// positionChange compares the challenged position with the final consensus.
+// Identical positions need no model call. Otherwise the first debater's model
+// describes the change; if that call fails, the change is still returned,
+// without a summary, alongside the error.
+func (e *Engine) positionChange(ctx context.Context, consensus *ConsensusResult) (*PositionChange, error) {
+	before := e.transcript.ConsensusPosition
+	if e.transcript.Phase != TenthManPhase || before == "" || consensus == nil || len(e.agents) == 0 {
+		return nil, nil
+	}
+	change := &PositionChange{Before: before}
+	if consensus.Detected {
+		change.After = consensus.Position
+	}
+	if strings.EqualFold(strings.TrimSpace(change.Before), strings.TrimSpace(change.After)) {
+		change.Summary = "The consensus position is unchanged."
+		return change, nil
+	}
+	change.Changed = true
+
+	after := change.After
+	if after == "" {
+		after = "(no consensus: the group no longer agrees)"
+	}
+	msgs := []openrouter.Message{
+		{
+			Role: "system",
+			Content: `You compare two consensus positions from a debate: the one the group held before a devil's advocate challenged it, and the one after.
+Answer with a first line "CHANGED: yes" or "CHANGED: no", where "no" means the wording differs but the substance is the same.
+Then, in at most three sentences, say what changed: caveats added, scope narrowed or widened, confidence lowered, conclusion reversed, or consensus lost.`,
+		},
+		{Role: "user", Content: fmt.Sprintf("Topic: %s\n\nBefore: %s\n\nAfter: %s", e.topic, before, after)},
+	}
+	resp, _, err := e.complete(ctx, e.agents[0], msgs)
+	if err != nil {
+		return change, err
+	}
+	if len(resp.Choices) == 0 {
+		return change, nil
+	}
+	content := resp.Choices[0].Message.Content
+	if m := changedRe.FindStringSubmatchIndex(content); m != nil {
+		change.Changed = strings.EqualFold(content[m[2]:m[3]], "yes") || change.After == ""
+		content = content[:m[0]] + content[m[1]:]
+	}
+	change.Summary = strings.TrimSpace(content)
+	return change, nil
+}
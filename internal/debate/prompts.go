@@ -2,7 +2,9 @@ package debate
 
 import (
 	"fmt"
+	"slices"
 
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
 )
 
@@ -14,28 +16,122 @@ func phase2SystemPrompt(agent Agent, topic string) string {
 	return fmt.Sprintf("You are %s, a debate participant. The topic is: %s. The Tenth Man has been activated and is arguing against the group consensus. You MUST directly engage with the Tenth Man's arguments — address them specifically, refute or acknowledge them. Be concise but thorough.", agent.Name, topic)
 }
 
-func buildMessages(agent Agent, topic string, transcript *Transcript, tenthMan TenthManActivator, consensusPosition string) []openrouter.Message {
+// withPersona adds the agent's perspective, analytical frame and any scenario
+// instructions to a debater prompt.
+func withPersona(prompt string, agent Agent, instructions string) string {
+	if agent.Persona != "" {
+		prompt += fmt.Sprintf(" Argue from the perspective of %s.", agent.Persona)
+	}
+	if agent.Frame != "" {
+		prompt += " " + agent.Frame
+	}
+	if instructions != "" {
+		prompt += " " + instructions
+	}
+	return prompt
+}
+
+// promptExtras is room left in a prompt for the messages around its turns:
+// the system prompt, the images, the evidence, a briefing and the closing
+// prompt. withImages and withBriefing insert them without reallocating.
+const promptExtras = 5
+
+// newPrompt returns a prompt of system followed by shown, the messages
+// showing the turns, with room for promptExtras messages in all. shown is
+// copied, never written to, so it can be shared by every prompt.
+func newPrompt(system string, shown []openrouter.Message) []openrouter.Message {
+	msgs := make([]openrouter.Message, 0, len(shown)+promptExtras)
+	msgs = append(msgs, openrouter.Message{Role: "system", Content: system})
+	return append(msgs, shown...)
+}
+
+// turnSystemPrompt returns the system prompt asking agent for a turn in
+// transcript.
+func turnSystemPrompt(agent Agent, topic, instructions string, transcript *Transcript, tenthMan TenthManActivator, consensusPosition string, evidence bool) string {
 	var systemPrompt string
 	if agent.Role == "tenth-man" && tenthMan != nil {
 		systemPrompt = tenthMan.SystemPrompt(consensusPosition)
 	} else if transcript.Phase == TenthManPhase && agent.Role != "tenth-man" {
-		systemPrompt = phase2SystemPrompt(agent, topic)
+		systemPrompt = withPersona(phase2SystemPrompt(agent, topic), agent, instructions)
+		if di, ok := tenthMan.(DebaterInstructor); ok {
+			systemPrompt += " " + di.DebaterInstructions()
+		}
 	} else {
-		systemPrompt = agentSystemPrompt(agent, topic)
+		systemPrompt = withPersona(agentSystemPrompt(agent, topic), agent, instructions)
 	}
 
-	msgs := []openrouter.Message{
-		{Role: "system", Content: systemPrompt},
+	if len(transcript.Turns) > 0 || len(transcript.Evidence) > 0 {
+		systemPrompt += " " + guard.Instruction
 	}
-	for _, turn := range transcript.Turns {
+	if agent.Role != "tenth-man" {
+		if len(transcript.Turns) > 0 {
+			systemPrompt += " " + replyInstruction
+		}
+		systemPrompt += " " + confidenceInstruction
+		if evidence {
+			systemPrompt += " " + evidenceInstruction
+		}
+	}
+	return systemPrompt
+}
+
+// turnRequest appends to msgs, a prompt showing the turns of transcript, its
+// evidence and the request for a turn.
+func turnRequest(msgs []openrouter.Message, transcript *Transcript) []openrouter.Message {
+	if len(transcript.Evidence) > 0 {
 		msgs = append(msgs, openrouter.Message{
 			Role:    "user",
-			Content: fmt.Sprintf("%s: %s", turn.Agent.Name, turn.Content),
+			Content: evidenceMessage(transcript.Evidence),
 		})
 	}
-	msgs = append(msgs, openrouter.Message{
+	return append(msgs, openrouter.Message{
 		Role:    "user",
 		Content: "It's your turn to speak. Provide your perspective on the topic.",
 	})
+}
+
+// turnMessage shows turn to an agent, its text wrapped as untrusted so that
+// instructions in it are not taken for the agent's own.
+func turnMessage(turn Turn) openrouter.Message {
+	return openrouter.Message{
+		Role:    "user",
+		Content: fmt.Sprintf("[#%d] %s: %s", turn.ID, turn.Agent.Name, guard.Wrap(fmt.Sprintf("turn #%d", turn.ID), turn.Text())),
+	}
+}
+
+// withImages shows images right after the system prompt of msgs, as
+// attachments to the topic. msgs is returned unchanged if there are none.
+func withImages(msgs []openrouter.Message, images []string) []openrouter.Message {
+	if len(images) == 0 {
+		return msgs
+	}
+	attached := openrouter.Message{
+		Role:    "user",
+		Content: "The topic comes with the attached image(s). Base your arguments on what they show, and say so when you read something into them.",
+		Images:  images,
+	}
+	return slices.Insert(msgs, 1, attached)
+}
+
+func minorityReportMessages(agent Agent, topic, position string, shown []openrouter.Message) []openrouter.Message {
+	system := fmt.Sprintf("You are %s, a debate participant. The topic is: %s. The debate has ended and you still dissent from the group position: %q. Write a short minority report: list the objections that remain unresolved, explain why the group's arguments did not answer them, and state what evidence would change your mind. Be concise. %s", agent.Name, topic, position, guard.Instruction)
+	return append(newPrompt(system, shown), openrouter.Message{
+		Role:    "user",
+		Content: "Write your minority report.",
+	})
+}
+
+// briefingMessages asks for a catch-up summary of transcript, which shown
+// shows turn by turn, for a debater joining late.
+func briefingMessages(topic string, transcript *Transcript, shown []openrouter.Message) []openrouter.Message {
+	system := fmt.Sprintf("You brief a participant who is joining a debate late. The topic is: %s. Summarize the debate so far in at most 200 words: the positions taken and who holds them, what the participants agree on, the open disagreements and any evidence cited. Be neutral and do not add arguments of your own. %s", topic, guard.Instruction)
+	msgs := newPrompt(system, shown)
+	if len(transcript.Evidence) > 0 {
+		msgs = append(msgs, openrouter.Message{Role: "user", Content: evidenceMessage(transcript.Evidence)})
+	}
+	msgs = append(msgs, openrouter.Message{
+		Role:    "user",
+		Content: "Write the catch-up summary.",
+	})
 	return msgs
 }
@@ -0,0 +1,173 @@
+// Package qa answers follow-up questions about a finished debate from its
+// transcript, without re-running it.
+package qa
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/tokens"
+)
+
+// DefaultContextTokens bounds how many tokens of transcript are sent with
+// a question. Longer transcripts are cut down to the turns most relevant to it.
+const DefaultContextTokens = 6000
+
+// speakerBoost is added to a turn's relevance when the question names its
+// speaker or role, e.g. "What did the Tenth Man say ...".
+const speakerBoost = 3
+
+// Answerer answers questions about a debate transcript using an LLM.
+type Answerer struct {
+	llm           debate.LLMClient
+	model         string
+	contextTokens int
+}
+
+// NewAnswerer creates a new Answerer.
+func NewAnswerer(llm debate.LLMClient, model string) *Answerer {
+	return &Answerer{llm: llm, model: model, contextTokens: DefaultContextTokens}
+}
+
+// SetContextTokens sets the transcript budget per question, in tokens of the
+// answerer's model.
+func (a *Answerer) SetContextTokens(n int) {
+	if n > 0 {
+		a.contextTokens = n
+	}
+}
+
+// Answer answers question from transcript. The model is told to cite turns
+// as #N and to say so when the transcript does not cover the question.
+func (a *Answerer) Answer(ctx context.Context, transcript *debate.Transcript, question string) (string, error) {
+	turns, omitted := selectTurns(transcript.Turns, question, tokens.For(a.model), a.contextTokens)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Debate topic: %s\n\n", transcript.Topic)
+	if omitted > 0 {
+		fmt.Fprintf(&sb, "Transcript excerpt (%d less relevant turns omitted):\n\n", omitted)
+	} else {
+		sb.WriteString("Transcript:\n\n")
+	}
+	var shown strings.Builder
+	for _, turn := range turns {
+		shown.WriteString(formatTurn(turn))
+	}
+	sb.WriteString(guard.Wrap("transcript", strings.TrimSpace(shown.String())))
+	fmt.Fprintf(&sb, "\n\nQuestion: %s", question)
+
+	msgs := []openrouter.Message{
+		{
+			Role: "system",
+			Content: "You answer questions about a completed multi-agent debate. Use only the transcript provided. " +
+				"Cite the turns you rely on as #N. If the transcript does not answer the question, say so plainly instead of guessing. " + guard.Instruction,
+		},
+		{Role: "user", Content: sb.String()},
+	}
+	resp, err := a.llm.ChatCompletion(ctx, a.model, msgs)
+	if err != nil {
+		return "", fmt.Errorf("qa: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("qa: empty response from model")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+func formatTurn(turn debate.Turn) string {
+	return fmt.Sprintf("[#%d] Round %d, %s (%s): %s\n\n", turn.ID, turn.Round, turn.Agent.Name, turn.Agent.Role, strings.TrimSpace(turn.Content))
+}
+
+// selectTurns returns the turns to send for question within budget tokens
+// of est, in transcript order, and how many were left out. When the
+// whole transcript does not fit, turns are ranked by keyword overlap with the
+// question, with a boost for turns by a speaker the question names.
+func selectTurns(turns []debate.Turn, question string, est tokens.Family, budget int) ([]debate.Turn, int) {
+	total := 0
+	for _, t := range turns {
+		total += est.Count(formatTurn(t))
+	}
+	if total <= budget {
+		return turns, 0
+	}
+
+	lowerQ := strings.ToLower(question)
+	terms := make(map[string]bool)
+	for _, w := range keywords(question) {
+		terms[w] = true
+	}
+	scores := make([]int, len(turns))
+	for i, t := range turns {
+		seen := make(map[string]bool)
+		for _, w := range keywords(t.Content) {
+			if terms[w] && !seen[w] {
+				seen[w] = true
+				scores[i]++
+			}
+		}
+		if mentions(lowerQ, t.Agent.Name) || mentions(lowerQ, strings.ReplaceAll(t.Agent.Role, "-", " ")) {
+			scores[i] += speakerBoost
+		}
+	}
+
+	order := make([]int, len(turns))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(x, y int) int { return scores[y] - scores[x] })
+
+	var picked []int
+	used := 0
+	for _, i := range order {
+		size := est.Count(formatTurn(turns[i]))
+		if used+size > budget {
+			continue
+		}
+		used += size
+		picked = append(picked, i)
+	}
+	slices.Sort(picked)
+
+	selected := make([]debate.Turn, len(picked))
+	for j, i := range picked {
+		selected[j] = turns[i]
+	}
+	return selected, len(turns) - len(selected)
+}
+
+// mentions reports whether lowerQ contains name as a whole phrase.
+func mentions(lowerQ, name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return name != "" && slices.Contains(phrases(lowerQ, len(strings.Fields(name))), name)
+}
+
+// phrases returns every run of n consecutive words in s.
+func phrases(s string, n int) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	var out []string
+	for i := 0; i+n <= len(fields); i++ {
+		out = append(out, strings.Join(fields[i:i+n], " "))
+	}
+	return out
+}
+
+// keywords lowercases s and splits it into words of at least four characters,
+// which skips most function words.
+func keywords(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) >= 4 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
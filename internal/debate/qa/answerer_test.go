@@ -0,0 +1,92 @@
+package qa
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+type mockLLM struct {
+	reply  string
+	system string
+	prompt string
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	m.system, m.prompt = msgs[0].Content, msgs[1].Content
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: m.reply}}},
+	}, nil
+}
+
+func sampleTranscript() *debate.Transcript {
+	alice := debate.Agent{Name: "Alice", Role: "debater"}
+	tm := debate.Agent{Name: "The Tenth Man", Role: "tenth-man"}
+	return &debate.Transcript{
+		Topic: "Should we migrate to Kubernetes?",
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: alice, Content: "Kubernetes gives us portability. " + strings.Repeat("Filler about teams. ", 20)},
+			{ID: 2, Round: 2, Agent: alice, Content: "Autoscaling reduces toil. " + strings.Repeat("Filler about process. ", 20)},
+			{ID: 3, Round: 3, Agent: tm, Content: "The migration cost is underestimated by half."},
+			{ID: 4, Round: 3, Agent: alice, Content: "Licensing cost is flat. " + strings.Repeat("Filler about hiring. ", 20)},
+		},
+	}
+}
+
+func TestAnswerSendsWholeTranscriptWhenItFits(t *testing.T) {
+	llm := &mockLLM{reply: " The Tenth Man said cost is underestimated (#3). "}
+	answer, err := NewAnswerer(llm, "m").Answer(context.Background(), sampleTranscript(), "What did the Tenth Man say about cost?")
+	if err != nil {
+		t.Fatalf("Answer() error = %v", err)
+	}
+	if answer != "The Tenth Man said cost is underestimated (#3)." {
+		t.Errorf("answer = %q", answer)
+	}
+	for _, want := range []string{"Should we migrate to Kubernetes?", "[#1] Round 1, Alice (debater)", "[#3] Round 3, The Tenth Man (tenth-man)", "Question: What did the Tenth Man say about cost?"} {
+		if !strings.Contains(llm.prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
+
+func TestAnswerTruncatesToRelevantTurns(t *testing.T) {
+	llm := &mockLLM{reply: "ok"}
+	a := NewAnswerer(llm, "m")
+	a.SetContextTokens(230)
+	if _, err := a.Answer(context.Background(), sampleTranscript(), "What did the Tenth Man say about cost?"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(llm.prompt, "[#3]") || !strings.Contains(llm.prompt, "[#4]") {
+		t.Errorf("relevant turns should be kept:\n%s", llm.prompt)
+	}
+	if strings.Contains(llm.prompt, "[#1]") || !strings.Contains(llm.prompt, "omitted") {
+		t.Errorf("irrelevant turns should be dropped and noted:\n%s", llm.prompt)
+	}
+	if strings.Index(llm.prompt, "[#3]") > strings.Index(llm.prompt, "[#4]") {
+		t.Error("selected turns should stay in transcript order")
+	}
+}
+
+func TestMentions(t *testing.T) {
+	q := "what did the tenth man say?"
+	if !mentions(q, "The Tenth Man") || !mentions(q, "tenth man") {
+		t.Error("expected the Tenth Man to be mentioned")
+	}
+	if mentions(q, "Ten") || mentions(q, "") {
+		t.Error("partial words should not count as mentions")
+	}
+}
+
+func TestAnswerFencesTurns(t *testing.T) {
+	llm := &mockLLM{reply: "ok"}
+	if _, err := NewAnswerer(llm, "m").Answer(context.Background(), sampleTranscript(), "What about cost?"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(llm.prompt, "<untrusted source=\"transcript\">\n[#1] Round 1, Alice (debater)") || !strings.Contains(llm.system, guard.Instruction) {
+		t.Errorf("expected the turns fenced as untrusted, got system %q and prompt %q", llm.system, llm.prompt)
+	}
+}
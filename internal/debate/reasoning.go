@@ -0,0 +1,48 @@
+package debate
+
+import (
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// Tags some reasoning models wrap their thinking in, inline in the answer.
+const (
+	thinkOpen  = "<think>"
+	thinkClose = "</think>"
+)
+
+// StripReasoning returns content without the <think>...</think> blocks some
+// reasoning models put before their answer, and the text of those blocks.
+// An unclosed block is left in place, since the answer may be inside it.
+func StripReasoning(content string) (answer, reasoning string) {
+	var traces []string
+	for {
+		start := strings.Index(content, thinkOpen)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(content[start:], thinkClose)
+		if end < 0 {
+			break
+		}
+		end += start
+		traces = append(traces, strings.TrimSpace(content[start+len(thinkOpen):end]))
+		content = content[:start] + content[end+len(thinkClose):]
+	}
+	return strings.TrimSpace(content), strings.Join(traces, "\n\n")
+}
+
+// splitReasoning separates msg's answer from its reasoning, whether the
+// provider returned it in msg.Reasoning or inline in think tags.
+func splitReasoning(msg openrouter.Message) (answer, reasoning string) {
+	answer, inline := StripReasoning(msg.Content)
+	reasoning = strings.TrimSpace(msg.Reasoning)
+	if inline != "" {
+		if reasoning != "" {
+			reasoning += "\n\n"
+		}
+		reasoning += inline
+	}
+	return answer, reasoning
+}
@@ -0,0 +1,98 @@
+package debate
+
+import (
+	"slices"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// record numbers turn, appends it to the transcript with its reasoning
+// trace, if any, and emits it. It is safe to call from concurrent turns:
+// turns are numbered in the order they are recorded and emitted in that
+// order too.
+func (e *Engine) record(turn Turn, trace string) Turn {
+	e.emitMu.Lock()
+	defer e.emitMu.Unlock()
+	e.recordMu.Lock()
+	turn.ID = len(e.transcript.Turns) + 1
+	if trace != "" {
+		e.transcript.Reasoning = append(e.transcript.Reasoning, ReasoningTrace{TurnID: turn.ID, Agent: turn.Agent.Name, Text: trace})
+	}
+	e.transcript.Turns = append(e.transcript.Turns, turn)
+	e.recordMu.Unlock()
+	e.deliver(TurnCompleted{Turn: turn})
+	return turn
+}
+
+// truncate drops the turns of the transcript from the nth on, such as those
+// of an unfinished round.
+func (e *Engine) truncate(n int) {
+	e.recordMu.Lock()
+	defer e.recordMu.Unlock()
+	e.transcript.Turns = e.transcript.Turns[:n]
+	// Clipped, so the next turn's message does not overwrite one that
+	// prompts built before still share.
+	e.shown = slices.Clip(e.shown[:min(n, len(e.shown))])
+	e.prefix = nil
+}
+
+// view returns a shallow copy of the transcript that turns may read while
+// others are being recorded, and the messages showing its turns. Its turns
+// and reasoning traces are those recorded so far; later appends never write
+// into them.
+//
+// The messages are rendered once per turn and shared by every prompt, so
+// building a prompt never renders the whole transcript again; they must not
+// be written to.
+func (e *Engine) view() (*Transcript, []openrouter.Message) {
+	e.recordMu.Lock()
+	defer e.recordMu.Unlock()
+	if e.shownFor != e.transcript || len(e.shown) > len(e.transcript.Turns) {
+		e.shown, e.shownFor, e.prefix = nil, e.transcript, nil
+	}
+	for _, turn := range e.transcript.Turns[len(e.shown):] {
+		e.shown = append(e.shown, turnMessage(turn))
+	}
+	t := *e.transcript
+	t.Turns = slices.Clip(t.Turns)
+	t.Reasoning = slices.Clip(t.Reasoning)
+	return &t, slices.Clip(e.shown)
+}
+
+// sharedPrompt returns a prompt of system, the images, if any, and shown,
+// the messages showing the turns, with room for promptExtras more messages.
+//
+// Turn prompts share one backing array: each writes its system prompt over
+// the last one's and appends only the turns recorded since, so a turn does
+// not copy the whole transcript. The prompt is the caller's to append to
+// until it is released; its other messages must not be written to. If the
+// last prompt is still in use, the new one is a copy instead.
+func (e *Engine) sharedPrompt(system string, shown []openrouter.Message) []openrouter.Message {
+	e.recordMu.Lock()
+	defer e.recordMu.Unlock()
+	msgs := e.prefix
+	if e.prefixInUse || msgs == nil {
+		msgs = withImages(make([]openrouter.Message, 1, len(shown)+promptExtras), e.images)
+	}
+	head := 1
+	if len(e.images) > 0 {
+		head = 2
+	}
+	msgs[0] = openrouter.Message{Role: "system", Content: system}
+	kept := min(len(msgs)-head, len(shown))
+	msgs = slices.Grow(append(msgs[:head+kept], shown[kept:]...), promptExtras)
+	if !e.prefixInUse {
+		e.prefix, e.prefixInUse = msgs, true
+	}
+	return msgs
+}
+
+// release frees msgs, a prompt from sharedPrompt, for the next prompt to
+// reuse once its turn is done with it.
+func (e *Engine) release(msgs []openrouter.Message) {
+	e.recordMu.Lock()
+	defer e.recordMu.Unlock()
+	if len(msgs) > 0 && len(e.prefix) > 0 && &msgs[0] == &e.prefix[0] {
+		e.prefixInUse = false
+	}
+}
@@ -0,0 +1,76 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// noCritique is the critic's answer for a draft with nothing worth fixing.
+const noCritique = "NONE"
+
+// SetRefine makes every turn a draft that a critic prompt reviews and the
+// agent revises once before it is published. It adds up to two calls per
+// turn, both on the model that wrote the draft.
+func (e *Engine) SetRefine(on bool) {
+	e.refine = on
+}
+
+// refineDraft has the draft in resp, answered by model for agent to msgs,
+// critiqued and revised. It returns the response to publish, whose usage
+// covers every call made, and the critique it was revised against. A failed
+// or empty critique, a critique finding nothing to fix, or a failed revision
+// leaves the draft as it is.
+func (e *Engine) refineDraft(ctx context.Context, agent Agent, model string, msgs []openrouter.Message, resp *openrouter.ChatResponse) (*openrouter.ChatResponse, string) {
+	if len(resp.Choices) == 0 {
+		return resp, ""
+	}
+	draft, _ := splitReasoning(resp.Choices[0].Message)
+	if strings.TrimSpace(draft) == "" {
+		return resp, ""
+	}
+	usage := &openrouter.Usage{}
+	addUsage(usage, resp.Usage)
+	published := *resp
+	published.Usage = usage
+
+	critic, err := e.call(ctx, agent, model, critiqueMessages(agent, e.topic, msgs, draft))
+	if err != nil || len(critic.Choices) == 0 {
+		return &published, ""
+	}
+	addUsage(usage, critic.Usage)
+	critique, _ := splitReasoning(critic.Choices[0].Message)
+	critique = strings.TrimSpace(critique)
+	if critique == "" || strings.EqualFold(strings.Trim(critique, ".* "), noCritique) {
+		return &published, ""
+	}
+
+	revise := append(msgs[:len(msgs):len(msgs)],
+		openrouter.Message{Role: "assistant", Content: draft},
+		openrouter.Message{Role: "user", Content: "A reviewer found these weaknesses in your reply:\n\n" + guard.Wrap("review", critique) +
+			"\n\nRevise your reply once to address them. Give only the revised reply, keeping any REPLY TO and CONFIDENCE lines."},
+	)
+	revised, err := e.call(ctx, agent, model, revise)
+	if err != nil || len(revised.Choices) == 0 {
+		return &published, critique
+	}
+	addUsage(usage, revised.Usage)
+	if content, _ := splitReasoning(revised.Choices[0].Message); strings.TrimSpace(content) == "" {
+		return &published, critique
+	}
+	published.Choices = revised.Choices
+	return &published, critique
+}
+
+// critiqueMessages asks for the weaknesses of agent's draft reply to the
+// debate in msgs, whose system prompt is replaced by the critic's.
+func critiqueMessages(agent Agent, topic string, msgs []openrouter.Message, draft string) []openrouter.Message {
+	system := fmt.Sprintf("You are a demanding debate coach reviewing a draft reply by %s in a debate on: %s. List at most three concrete weaknesses of the draft, one per line: unsupported claims, arguments by others it ignores, vagueness, repetition of earlier turns, or padding. Do not rewrite the reply. If nothing is worth fixing, answer only %s. %s", agent.Name, topic, noCritique, guard.Instruction)
+	critique := slices.Clone(msgs[1 : len(msgs)-1])
+	critique = slices.Insert(critique, 0, openrouter.Message{Role: "system", Content: system})
+	return append(critique, openrouter.Message{Role: "user", Content: "Draft reply by " + agent.Name + ":\n\n" + guard.Wrap("draft reply", draft)})
+}
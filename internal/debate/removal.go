@@ -0,0 +1,107 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// minActiveDebaters is how many debaters must remain for an agent to be
+// removed.
+const minActiveDebaters = 2
+
+// AgentRemoval records a debater taken out of a debate.
+type AgentRemoval struct {
+	Round  int    // first round without the agent
+	Agent  string // name of the agent
+	Reason string
+}
+
+// RemoveAgent queues the removal of the named debater, for reason. It takes
+// effect at the start of the next round and is recorded in the transcript's
+// Removals, which the judge is shown. Names match case-insensitively; a
+// removal naming no active debater, or one that would leave fewer than two,
+// is dropped. RemoveAgent is safe to call while Run is in progress.
+func (e *Engine) RemoveAgent(agent, reason string) {
+	e.pendingMu.Lock()
+	defer e.pendingMu.Unlock()
+	e.pendingRemovals = append(e.pendingRemovals, AgentRemoval{Agent: agent, Reason: reason})
+}
+
+// SetMaxFailures makes the engine remove a debater whose turns fail n times
+// in a row, instead of failing the debate, as long as two debaters remain.
+// A failed turn is recorded without content, so the judge sees the agent did
+// not answer. A value below 1, the default, fails the debate on the first
+// failed turn.
+func (e *Engine) SetMaxFailures(n int) {
+	e.maxFailures = n
+}
+
+// applyRemovals applies every queued removal from round on.
+func (e *Engine) applyRemovals(round int) {
+	e.pendingMu.Lock()
+	removals := e.pendingRemovals
+	e.pendingRemovals = nil
+	e.pendingMu.Unlock()
+	for _, r := range removals {
+		if i := slices.IndexFunc(e.agents, func(a Agent) bool { return strings.EqualFold(a.Name, r.Agent) }); i >= 0 {
+			e.removeAgent(round, e.agents[i].Name, r.Reason)
+		}
+	}
+}
+
+// removeAgent takes the named debater out of the debate from round on. It
+// reports false, changing nothing, if the agent is not a debater or too few
+// debaters would remain.
+func (e *Engine) removeAgent(round int, name, reason string) bool {
+	debaters := 0
+	found := false
+	for _, a := range e.agents {
+		if a.Role != "debater" {
+			continue
+		}
+		debaters++
+		found = found || a.Name == name
+	}
+	if !found || debaters <= minActiveDebaters {
+		return false
+	}
+	// A new slice, so a round iterating over the old one is unaffected.
+	e.agents = slices.DeleteFunc(slices.Clone(e.agents), func(a Agent) bool { return a.Name == name })
+	removal := AgentRemoval{Round: round, Agent: name, Reason: reason}
+	e.transcript.Removals = append(e.transcript.Removals, removal)
+	e.emit(AgentRemoved{Removal: removal})
+	return true
+}
+
+// turnFailed handles a failed turn by agent in round. It returns err if the
+// debate must fail; otherwise the turn is recorded without content and
+// returned, and the agent is removed once it has failed maxFailures turns in
+// a row.
+func (e *Engine) turnFailed(ctx context.Context, round int, agent Agent, err error) (Turn, error) {
+	if e.maxFailures < 1 || agent.Role != "debater" || errors.Is(err, ErrRetryBudgetExceeded) || ctx.Err() != nil {
+		return Turn{}, err
+	}
+	e.recordMu.Lock()
+	if e.failures == nil {
+		e.failures = make(map[string]int)
+	}
+	e.failures[agent.Name]++
+	n := e.failures[agent.Name]
+	e.recordMu.Unlock()
+	if n >= e.maxFailures {
+		if !e.removeAgent(round+1, agent.Name, fmt.Sprintf("%d failed turns in a row: %v", n, err)) {
+			return Turn{}, err
+		}
+	}
+	return e.record(Turn{Round: round, Agent: agent}, ""), nil
+}
+
+// WithoutRemoved returns agents without those removed in t.
+func WithoutRemoved(agents []Agent, t *Transcript) []Agent {
+	return slices.DeleteFunc(agents, func(a Agent) bool {
+		return slices.ContainsFunc(t.Removals, func(r AgentRemoval) bool { return r.Agent == a.Name })
+	})
+}
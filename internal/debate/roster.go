@@ -0,0 +1,44 @@
+package debate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// ValidateAgents checks every agent's configuration and reports all problems
+// at once, one error per misconfigured setting.
+func ValidateAgents(agents []Agent) error {
+	if len(agents) == 0 {
+		return errors.New("no agents configured")
+	}
+	var errs []error
+	seen := make(map[string]int)
+	for i, a := range agents {
+		label := fmt.Sprintf("agent %d", i+1)
+		if a.Name != "" {
+			label = fmt.Sprintf("agent %d (%s)", i+1, a.Name)
+		}
+		if a.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: name is required", label))
+		} else if prev, ok := seen[a.Name]; ok {
+			errs = append(errs, fmt.Errorf("%s: name already used by agent %d", label, prev))
+		} else {
+			seen[a.Name] = i + 1
+		}
+		if a.Model == "" {
+			errs = append(errs, fmt.Errorf("%s: model is required", label))
+		}
+		if a.Role != "debater" {
+			errs = append(errs, fmt.Errorf("%s: role must be \"debater\", got %q", label, a.Role))
+		}
+		if a.Temperature != nil && (*a.Temperature < 0 || *a.Temperature > 2) {
+			errs = append(errs, fmt.Errorf("%s: temperature %g out of range [0, 2]", label, *a.Temperature))
+		}
+		if err := openrouter.ValidateEffort(a.ReasoningEffort); err != nil {
+			errs = append(errs, fmt.Errorf("%s: reasoning effort %q must be low, medium or high", label, a.ReasoningEffort))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,29 @@
+package debate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateAgents(t *testing.T) {
+	if err := ValidateAgents(makeAgents(3)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hot := 2.5
+	agents := makeAgents(5)
+	agents[1].Model = ""
+	agents[2].Name = agents[0].Name
+	agents[3].Temperature = &hot
+	agents[4].ReasoningEffort = "extreme"
+
+	err := ValidateAgents(agents)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"agent 2", "model is required", "agent 3", "already used", "agent 4", "temperature", "agent 5", "reasoning effort"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// Ways of picking the strongest of several sampled turns.
+const (
+	// PickLLM asks the agent's model which candidate is strongest, falling
+	// back to PickHeuristic if its answer is unusable.
+	PickLLM = "llm"
+	// PickHeuristic prefers substantive candidates with the most distinct
+	// words that fit the word limit, without a model call.
+	PickHeuristic = "heuristic"
+)
+
+// pickRe matches the candidate number in a ranking answer.
+var pickRe = regexp.MustCompile(`\d+`)
+
+// SetSamples makes the engine draw n completions for every turn and keep
+// the strongest, chosen as pick says (PickLLM or PickHeuristic). It costs n
+// calls per turn, plus one for PickLLM, and helps most with weak models. A
+// value below 2 draws a single completion.
+func (e *Engine) SetSamples(n int, pick string) {
+	e.samples = n
+	e.samplePick = pick
+}
+
+// sample completes a turn for agent, drawing e.samples candidates and
+// returning the strongest, the model that wrote it and the number of
+// candidates drawn. Failed candidates are skipped; the turn fails only if
+// all do. The returned response's usage covers every call made.
+func (e *Engine) sample(ctx context.Context, agent Agent, msgs []openrouter.Message) (*openrouter.ChatResponse, string, int, error) {
+	if e.samples < 2 {
+		resp, model, err := e.complete(ctx, agent, msgs)
+		return resp, model, 1, err
+	}
+	var (
+		candidates []*openrouter.ChatResponse
+		models     []string
+		lastErr    error
+	)
+	usage := &openrouter.Usage{}
+	for range e.samples {
+		resp, model, err := e.complete(ctx, agent, msgs)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, ErrRetryBudgetExceeded) {
+				return nil, "", 0, err
+			}
+			lastErr = err
+			continue
+		}
+		if len(resp.Choices) == 0 {
+			continue
+		}
+		addUsage(usage, resp.Usage)
+		candidates = append(candidates, resp)
+		models = append(models, model)
+	}
+	if len(candidates) == 0 {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no completion in %d samples", e.samples)
+		}
+		return nil, "", 0, lastErr
+	}
+	texts := make([]string, len(candidates))
+	for i, c := range candidates {
+		texts[i], _ = splitReasoning(c.Choices[0].Message)
+	}
+	best := -1
+	if e.samplePick == PickLLM && len(candidates) > 1 {
+		best = e.rankSamples(ctx, agent, texts, usage)
+	}
+	if best < 0 {
+		best = bestByHeuristic(texts, e.maxWords)
+	}
+	chosen := *candidates[best]
+	chosen.Usage = usage
+	return &chosen, models[best], len(candidates), nil
+}
+
+// rankSamples asks agent's model for the strongest of texts and returns its
+// index, or -1 if the call fails or the answer names no candidate.
+func (e *Engine) rankSamples(ctx context.Context, agent Agent, texts []string, usage *openrouter.Usage) int {
+	var sb strings.Builder
+	for i, text := range texts {
+		fmt.Fprintf(&sb, "Reply %d:\n%s\n\n", i+1, guard.Wrap(fmt.Sprintf("reply %d", i+1), text))
+	}
+	msgs := []openrouter.Message{
+		{
+			Role:    "system",
+			Content: fmt.Sprintf("You pick the strongest of %d candidate replies %s could give in a debate on: %s. Prefer specific, well-reasoned arguments that engage with the other participants over vague, repetitive or padded ones. Answer with only the number of the strongest reply. %s", len(texts), agent.Name, e.topic, guard.Instruction),
+		},
+		{Role: "user", Content: strings.TrimSpace(sb.String())},
+	}
+	resp, _, err := e.complete(ctx, agent, msgs)
+	if err != nil || len(resp.Choices) == 0 {
+		return -1
+	}
+	addUsage(usage, resp.Usage)
+	answer, _ := splitReasoning(resp.Choices[0].Message)
+	n, err := strconv.Atoi(pickRe.FindString(answer))
+	if err != nil || n < 1 || n > len(texts) {
+		return -1
+	}
+	return n - 1
+}
+
+// bestByHeuristic returns the index of the strongest of texts: substantive
+// ones beat the rest, ones within maxWords (when set) beat longer ones, and
+// then the one with the most distinct words wins. Ties go to the earliest.
+func bestByHeuristic(texts []string, maxWords int) int {
+	score := func(text string) (int, int, int) {
+		substantive, fits := 0, 1
+		if Substantive(text) {
+			substantive = 1
+		}
+		if maxWords > 0 && countWords(text) > maxWords {
+			fits = 0
+		}
+		distinct := make(map[string]bool)
+		for _, w := range strings.Fields(strings.ToLower(text)) {
+			distinct[strings.Trim(w, ".,;:!?\"'()")] = true
+		}
+		return substantive, fits, len(distinct)
+	}
+	best := 0
+	bs, bf, bd := score(texts[0])
+	for i := 1; i < len(texts); i++ {
+		s, f, d := score(texts[i])
+		if s > bs || s == bs && (f > bf || f == bf && d > bd) {
+			best, bs, bf, bd = i, s, f, d
+		}
+	}
+	return best
+}
+
+// addUsage adds u to total.
+func addUsage(total, u *openrouter.Usage) {
+	if u == nil {
+		return
+	}
+	total.PromptTokens += u.PromptTokens
+	total.CompletionTokens += u.CompletionTokens
+	total.TotalTokens += u.TotalTokens
+}
@@ -0,0 +1,54 @@
+package debate
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultMinNovelty is the share of new words below which a round is
+// considered to add no new information.
+const DefaultMinNovelty = 0.15
+
+// minWordLength filters out short, mostly function words when comparing rounds.
+const minWordLength = 4
+
+// roundNovelty returns the fraction of distinct words in the given round's
+// turns that appear in no earlier turn.
+func roundNovelty(turns []Turn, round int) float64 {
+	seen := make(map[string]bool)
+	current := make(map[string]bool)
+	for _, turn := range turns {
+		target := seen
+		if turn.Round == round {
+			target = current
+		} else if turn.Round > round {
+			continue
+		}
+		for _, w := range words(turn.Content) {
+			target[w] = true
+		}
+	}
+	if len(current) == 0 {
+		return 0
+	}
+	fresh := 0
+	for w := range current {
+		if !seen[w] {
+			fresh++
+		}
+	}
+	return float64(fresh) / float64(len(current))
+}
+
+func words(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) >= minWordLength {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// Statement kinds, recorded in Turn.Statement.
+const (
+	OpeningStatement = "opening"
+	ClosingStatement = "closing"
+)
+
+const closingPrompt = "Closing statement: the debate is over. State your final position on the topic in a few sentences: what you now hold, whether and how the Tenth Man's challenge changed it, and the strongest reason for it. Do not introduce new arguments."
+
+// SetStatements adds opening and closing statements to the debate. With
+// opening on, round 1 is a round of opening statements: each debater states
+// an initial position on the topic without seeing anyone else's, so no
+// statement refers to another. With closing on, Phase 2 ends with one more
+// round in which every debater but the Tenth Man states a final position
+// with the whole debate in view. Statements are turns of their round,
+// flagged with their kind in Turn.Statement; the opening round takes the
+// place of round 1's cross-examination, if one was set.
+func (e *Engine) SetStatements(opening, closing bool) {
+	e.openings = opening
+	e.closings = closing
+}
+
+// statement returns the kind of statement round consists of, or "" if it
+// is a regular round.
+func (e *Engine) statement(round int) string {
+	switch {
+	case e.openings && round == 1 && e.transcript.Phase == FreeDebate:
+		return OpeningStatement
+	case e.closings && e.transcript.Phase == TenthManPhase && round == tenthManStart(e.transcript)+e.tenthManRounds:
+		return ClosingStatement
+	}
+	return ""
+}
+
+// makeStatements has every debater make the statement round calls for.
+func (e *Engine) makeStatements(ctx context.Context, round int) error {
+	kind := e.statement(round)
+	for _, agent := range e.agents {
+		if agent.Role == "tenth-man" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("debate: %w", err)
+		}
+		if _, err := e.takeTurn(ctx, round, agent, e.statementMessages(agent, kind), 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statementMessages returns the messages asking agent for a statement of
+// kind. An opening statement is asked for with the topic alone.
+func (e *Engine) statementMessages(agent Agent, kind string) []openrouter.Message {
+	if kind == ClosingStatement {
+		return withPrompt(e.turnMessages(agent), closingPrompt)
+	}
+	system := fmt.Sprintf("You are %s, a debate participant. The topic is: %s. This is your opening statement: state your initial position on the topic and the main reasons for it, in your own words. Do not refer to other participants.", agent.Name, e.topic)
+	msgs := []openrouter.Message{
+		{Role: "system", Content: withPersona(system, agent, e.guidance()) + " " + confidenceInstruction},
+		{Role: "user", Content: "Give your opening statement."},
+	}
+	return withImages(msgs, e.images)
+}
@@ -0,0 +1,296 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// State is a step of the engine's state machine. A debate starts in
+// StateOpening and moves from state to state until one returns StateDone.
+// States are finer than Phase, which only records whether the Tenth Man has
+// joined.
+type State string
+
+// The built-in states, in the order a debate that reaches consensus visits
+// them. FreeDebate and ConsensusCheck alternate until consensus is reached,
+// the debate stagnates or the maximum rounds are done.
+const (
+	StateOpening        State = "opening"         // announces the free debate
+	StateFreeDebate     State = "free-debate"     // one Phase 1 round
+	StateConsensusCheck State = "consensus-check" // the judge evaluates Phase 1 so far
+	StateTenthMan       State = "tenth-man"       // the Tenth Man joins and Phase 2 runs
+	StateVote           State = "vote"            // the judge's final verdict
+	StateSynthesis      State = "synthesis"       // position change, minority reports and outcome
+	StateDone           State = "done"            // the run returns the machine's Result
+)
+
+// StateHandler implements a state: it does the state's work on m and returns
+// the state to move to. An error ends the run; ErrRetryBudgetExceeded ends it
+// with a partial result instead.
+type StateHandler interface {
+	Run(ctx context.Context, m *Machine) (State, error)
+}
+
+// StateFunc adapts a function to a StateHandler.
+type StateFunc func(ctx context.Context, m *Machine) (State, error)
+
+// Run calls f(ctx, m).
+func (f StateFunc) Run(ctx context.Context, m *Machine) (State, error) {
+	return f(ctx, m)
+}
+
+// defaultStates are the built-in state handlers.
+var defaultStates = map[State]StateHandler{
+	StateOpening:        StateFunc(runOpening),
+	StateFreeDebate:     StateFunc(runFreeDebate),
+	StateConsensusCheck: StateFunc(runConsensusCheck),
+	StateTenthMan:       StateFunc(runTenthMan),
+	StateVote:           StateFunc(runVote),
+	StateSynthesis:      StateFunc(runSynthesis),
+}
+
+// SetStateHandler replaces the handler of state, or adds a new state that
+// replaced handlers can move to. A nil h restores the built-in handler.
+func (e *Engine) SetStateHandler(state State, h StateHandler) {
+	if e.states == nil {
+		e.states = make(map[State]StateHandler)
+	}
+	if h == nil {
+		delete(e.states, state)
+		return
+	}
+	e.states[state] = h
+}
+
+// StateHandler returns the handler of state, so a replacement can wrap the
+// built-in one. It returns nil for unknown states.
+func (e *Engine) StateHandler(state State) StateHandler {
+	if h, ok := e.states[state]; ok {
+		return h
+	}
+	return defaultStates[state]
+}
+
+// Middleware wraps the handler of every state the engine runs, for work
+// around states such as checkpoints or notifications, without replacing
+// them. It is called with the state about to run and that state's handler,
+// and returns the handler to run instead; it should call next.Run unless it
+// means to skip the state.
+type Middleware func(state State, next StateHandler) StateHandler
+
+// Use adds middleware around every state. The first added is outermost: its
+// work before a state runs first, and its work after runs last.
+func (e *Engine) Use(mw ...Middleware) {
+	e.middleware = append(e.middleware, mw...)
+}
+
+// Around returns middleware that calls before just before each state runs
+// and after once it has run, with the state it moved to. Either may be nil.
+// An error from either ends the run, as one from the state would.
+func Around(before func(ctx context.Context, state State, m *Machine) error, after func(ctx context.Context, state, next State, m *Machine) error) Middleware {
+	return func(state State, h StateHandler) StateHandler {
+		return StateFunc(func(ctx context.Context, m *Machine) (State, error) {
+			if before != nil {
+				if err := before(ctx, state, m); err != nil {
+					return "", err
+				}
+			}
+			next, err := h.Run(ctx, m)
+			if err != nil || after == nil {
+				return next, err
+			}
+			if err := after(ctx, state, next, m); err != nil {
+				return "", err
+			}
+			return next, nil
+		})
+	}
+}
+
+// Machine is a debate in progress, as its state handlers see it.
+type Machine struct {
+	Consensus *ConsensusResult // the latest verdict; nil before the first evaluation
+	Stagnated bool             // Phase 1 stopped adding new information
+	Settled   string           // why adaptive rounds ended Phase 1, if they did
+	Result    *Result          // the run's result, set by StateSynthesis
+
+	engine      *Engine
+	staleRounds int // consecutive Phase 1 rounds below the novelty threshold
+}
+
+// Transcript returns the debate so far.
+func (m *Machine) Transcript() *Transcript {
+	return m.engine.transcript
+}
+
+// RunRound runs the next round with the current debaters.
+func (m *Machine) RunRound(ctx context.Context) error {
+	return m.engine.runRound(ctx, m.engine.transcript.Rounds+1)
+}
+
+// InjectEvent queues a moderator note for the start of the next round, as
+// Engine.InjectEvent does.
+func (m *Machine) InjectEvent(content string) {
+	m.engine.InjectEvent(content)
+}
+
+// Evaluate asks the judge for a verdict on the transcript and records it as
+// m.Consensus.
+func (m *Machine) Evaluate(ctx context.Context) (*ConsensusResult, error) {
+	consensus, err := m.engine.judge.Evaluate(ctx, m.engine.transcript)
+	if err != nil {
+		return nil, err
+	}
+	m.engine.consensusEvaluated(consensus)
+	m.Consensus = consensus
+	return consensus, nil
+}
+
+// reached reports whether the latest verdict activates the Tenth Man.
+func (m *Machine) reached() bool {
+	return m.Consensus != nil && m.Consensus.Detected && m.Consensus.Score >= ConsensusThreshold
+}
+
+// run moves the machine from state until StateDone and returns its result.
+func (e *Engine) run(ctx context.Context, m *Machine, state State) (*Result, error) {
+	ctx, release := e.stoppable(ctx)
+	defer release()
+	for state != StateDone {
+		h := e.StateHandler(state)
+		if h == nil {
+			return nil, fmt.Errorf("debate: unknown state %q", state)
+		}
+		for _, mw := range slices.Backward(e.middleware) {
+			h = mw(state, h)
+		}
+		next, err := h.Run(ctx, m)
+		if err != nil && wasStopped(ctx) {
+			return e.stoppedResult(m.Consensus), nil
+		}
+		if errors.Is(err, ErrRetryBudgetExceeded) {
+			return e.partial(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		state = next
+	}
+	if m.Result == nil {
+		return nil, errors.New("debate: the run ended without a result")
+	}
+	return m.Result, nil
+}
+
+func runOpening(ctx context.Context, m *Machine) (State, error) {
+	m.engine.emit(PhaseChanged{Phase: FreeDebate})
+	return StateFreeDebate, nil
+}
+
+// runFreeDebate runs the next Phase 1 round and has it judged once the
+// minimum rounds are done. With no rounds left, a debate resumed after its
+// last round but before that round was judged is judged now.
+func runFreeDebate(ctx context.Context, m *Machine) (State, error) {
+	e := m.engine
+	round := e.transcript.Rounds + 1
+	if round > e.maxRounds {
+		if m.Consensus == nil && e.transcript.Rounds >= e.minRounds {
+			return StateConsensusCheck, nil
+		}
+		return StateSynthesis, nil
+	}
+	if err := m.RunRound(ctx); err != nil {
+		return "", err
+	}
+	if e.stagnationRounds > 0 && round > 1 {
+		if roundNovelty(e.transcript.Turns, round) < e.minNovelty {
+			m.staleRounds++
+		} else {
+			m.staleRounds = 0
+		}
+	}
+	if round >= e.minRounds {
+		return StateConsensusCheck, nil
+	}
+	return StateFreeDebate, nil
+}
+
+// runConsensusCheck judges Phase 1 and ends it once the stop condition
+// holds, consensus is reached, the debate has stagnated, the maximum rounds
+// are done or adaptive rounds find it settled.
+func runConsensusCheck(ctx context.Context, m *Machine) (State, error) {
+	e := m.engine
+	if _, err := m.Evaluate(ctx); err != nil {
+		return "", fmt.Errorf("debate: consensus evaluation: %w", err)
+	}
+	stop, err := m.stop()
+	if err != nil {
+		return "", err
+	}
+	activate, err := m.activate()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case stop:
+		return StateSynthesis, nil
+	case activate:
+		return StateTenthMan, nil
+	case e.stagnationRounds > 0 && m.staleRounds >= e.stagnationRounds:
+		m.Stagnated = true
+		return StateSynthesis, nil
+	case e.transcript.Rounds >= e.maxRounds:
+		return StateSynthesis, nil
+	case e.adaptive != nil:
+		if m.Settled = e.adaptive.settled(e.transcript.Rounds, e.minRounds, e.maxRounds, e.transcript.ConsensusScores); m.Settled != "" {
+			return StateSynthesis, nil
+		}
+	}
+	return StateFreeDebate, nil
+}
+
+// runTenthMan has the Tenth Man join to challenge the consensus position and
+// runs the Phase 2 rounds, closing statements included. A debate resumed in
+// Phase 2 runs the rounds it has left.
+func runTenthMan(ctx context.Context, m *Machine) (State, error) {
+	e := m.engine
+	if e.transcript.Phase == TenthManPhase {
+		e.emit(PhaseChanged{Phase: TenthManPhase})
+		e.rejoinTenthMan(e.transcript.ConsensusPosition)
+	} else {
+		position := m.Consensus.Position
+		e.transcript.Phase = TenthManPhase
+		e.emit(PhaseChanged{Phase: TenthManPhase})
+		agent := e.rejoinTenthMan(position)
+		e.transcript.ConsensusPosition = position
+		e.emit(TenthManActivated{Agent: agent, Position: position})
+	}
+	last := tenthManStart(e.transcript) + e.tenthManRounds - 1
+	if e.closings {
+		last++
+	}
+	for e.transcript.Rounds < last {
+		if err := m.RunRound(ctx); err != nil {
+			return "", err
+		}
+	}
+	return StateVote, nil
+}
+
+func runVote(ctx context.Context, m *Machine) (State, error) {
+	if _, err := m.Evaluate(ctx); err != nil {
+		return "", fmt.Errorf("debate: final consensus evaluation: %w", err)
+	}
+	return StateSynthesis, nil
+}
+
+func runSynthesis(ctx context.Context, m *Machine) (State, error) {
+	result, err := m.engine.result(ctx, m.Consensus, m.Stagnated)
+	if err != nil {
+		return "", err
+	}
+	result.Settled = m.Settled
+	m.Result = result
+	return StateDone, nil
+}
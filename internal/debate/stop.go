@@ -0,0 +1,53 @@
+package debate
+
+import (
+	"context"
+	"errors"
+)
+
+// Stop ends the debate early: the LLM call in progress is abandoned, the
+// unfinished round is dropped, and Run returns the rounds completed so far,
+// flagged Partial and Stopped, without asking any model for more. Stop is
+// safe to call while Run is in progress; called before, it makes Run stop
+// before the first round.
+func (e *Engine) Stop() {
+	e.pendingMu.Lock()
+	defer e.pendingMu.Unlock()
+	e.stopped = true
+	if e.cancel != nil {
+		e.cancel(ErrStopped)
+	}
+}
+
+// stoppable returns ctx, cancelled with ErrStopped when Stop is called, and a
+// func releasing it once the run is over.
+func (e *Engine) stoppable(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	e.pendingMu.Lock()
+	e.cancel = cancel
+	if e.stopped {
+		cancel(ErrStopped)
+	}
+	e.pendingMu.Unlock()
+	return ctx, func() {
+		e.pendingMu.Lock()
+		e.cancel = nil
+		e.pendingMu.Unlock()
+		cancel(nil)
+	}
+}
+
+// wasStopped reports whether ctx, from stoppable, was cancelled by Stop.
+func wasStopped(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrStopped)
+}
+
+// stoppedResult ends a stopped debate with the rounds completed so far and
+// consensus, the latest verdict on them, if any.
+func (e *Engine) stoppedResult(consensus *ConsensusResult) *Result {
+	e.transcript.Outcome = VerdictNoConsensus
+	if consensus != nil {
+		e.transcript.Outcome = Classify(e.transcript, consensus)
+	}
+	return &Result{Transcript: e.transcript, Consensus: consensus, Partial: true, Stopped: true, Outcome: e.transcript.Outcome}
+}
@@ -0,0 +1,182 @@
+// Package summary writes the executive summary that opens a debate report:
+// a few bullets on the consensus, the strongest counter-arguments, the final
+// verdict and what to do next.
+package summary
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/tokens"
+)
+
+const (
+	maxSummaryRetries = 3
+	minBullets        = 3
+	maxBullets        = 5
+)
+
+// DefaultContextTokens bounds how many tokens of transcript are sent. Longer
+// transcripts lose their earliest free-debate turns first; the Tenth Man
+// rounds are always kept.
+const DefaultContextTokens = 6000
+
+// bulletRe matches a markdown bullet or numbered list item.
+var bulletRe = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s+(.+)$`)
+
+// Summarizer writes executive summaries of debates using an LLM.
+type Summarizer struct {
+	llm           debate.LLMClient
+	model         string
+	contextTokens int
+}
+
+// NewSummarizer creates a new Summarizer.
+func NewSummarizer(llm debate.LLMClient, model string) *Summarizer {
+	return &Summarizer{llm: llm, model: model, contextTokens: DefaultContextTokens}
+}
+
+// SetContextTokens sets the transcript budget per summary, in tokens of the
+// summarizer's model.
+func (s *Summarizer) SetContextTokens(n int) {
+	if n > 0 {
+		s.contextTokens = n
+	}
+}
+
+// Summarize returns 3 to 5 bullets summarizing transcript and its final
+// consensus. If the model never answers with that many bullets, it returns
+// none rather than an error.
+func (s *Summarizer) Summarize(ctx context.Context, transcript *debate.Transcript, consensus *debate.ConsensusResult) ([]string, error) {
+	system := openrouter.Message{
+		Role: "system",
+		Content: `You are an executive summary writer. Summarize a multi-agent debate for a busy decision maker who will not read the transcript.
+Write 3 to 5 bullets, one per line, each starting with "- " and at most two sentences long, covering in order:
+the consensus the group reached (or that it reached none), the strongest counter-arguments raised against it, the final verdict, and the recommended actions.
+Do NOT include a heading, preamble or any other text.
+` + guard.Instruction,
+	}
+	user := openrouter.Message{Role: "user", Content: s.prompt(transcript, consensus)}
+
+	for attempt := range maxSummaryRetries {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("summary: %w", err)
+		}
+
+		msgs := []openrouter.Message{system, user}
+		if attempt > 0 {
+			msgs = append(msgs, openrouter.Message{
+				Role:    "user",
+				Content: fmt.Sprintf("Your previous response did not have %d to %d bullets. Return ONLY the bullets, one per line, each starting with \"- \".", minBullets, maxBullets),
+			})
+		}
+
+		resp, err := s.llm.ChatCompletion(ctx, s.model, msgs)
+		if err != nil {
+			return nil, fmt.Errorf("summary: %w", err)
+		}
+		if len(resp.Choices) == 0 {
+			continue
+		}
+		if bullets := parseBullets(resp.Choices[0].Message.Content); len(bullets) >= minBullets && len(bullets) <= maxBullets {
+			return bullets, nil
+		}
+	}
+	return nil, nil
+}
+
+// prompt describes the debate's outcome followed by as much of the
+// transcript as fits.
+func (s *Summarizer) prompt(transcript *debate.Transcript, consensus *debate.ConsensusResult) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Debate topic: %s\n\n", transcript.Topic)
+	if transcript.ConsensusPosition != "" {
+		fmt.Fprintf(&sb, "Consensus challenged by the Tenth Man: %s\n", transcript.ConsensusPosition)
+	}
+	if consensus != nil {
+		position := consensus.Position
+		if !consensus.Detected || position == "" {
+			position = "(none)"
+		}
+		fmt.Fprintf(&sb, "Final consensus: %s (agreement %d/10)\n", position, consensus.Score)
+		if len(consensus.Dissenters) > 0 {
+			fmt.Fprintf(&sb, "Still dissenting: %s\n", strings.Join(consensus.Dissenters, ", "))
+		}
+	}
+	fmt.Fprintf(&sb, "Verdict: %s\n", verdictText(debate.Classify(transcript, consensus)))
+
+	turns, omitted := selectTurns(transcript.Turns, tokens.For(s.model), s.contextTokens)
+	if omitted > 0 {
+		fmt.Fprintf(&sb, "\nTranscript (the %d earliest turns omitted):\n\n", omitted)
+	} else {
+		sb.WriteString("\nTranscript:\n\n")
+	}
+	var shown strings.Builder
+	for _, turn := range turns {
+		shown.WriteString(formatTurn(turn))
+	}
+	sb.WriteString(guard.Wrap("transcript", strings.TrimSpace(shown.String())))
+	return sb.String()
+}
+
+func verdictText(v debate.Verdict) string {
+	switch v {
+	case debate.VerdictUpheld:
+		return "the consensus survived the Tenth Man unchanged"
+	case debate.VerdictRevised:
+		return "the consensus survived the Tenth Man with a revised position"
+	case debate.VerdictOverturned:
+		return "the consensus was overturned by the Tenth Man"
+	}
+	return "no consensus was reached"
+}
+
+func formatTurn(turn debate.Turn) string {
+	return fmt.Sprintf("Round %d, %s (%s): %s\n\n", turn.Round, turn.Agent.Name, turn.Agent.Role, strings.TrimSpace(turn.Content))
+}
+
+// selectTurns returns the latest turns that fit within budget tokens of est,
+// in transcript order, and how many earlier ones were left out. Turns from
+// the first Tenth Man turn on are always kept.
+func selectTurns(turns []debate.Turn, est tokens.Family, budget int) ([]debate.Turn, int) {
+	keep := len(turns)
+	for i, t := range turns {
+		if t.Agent.Role == "tenth-man" {
+			keep = i
+			break
+		}
+	}
+	used := 0
+	for _, t := range turns[keep:] {
+		used += est.Count(formatTurn(t))
+	}
+	start := keep
+	for start > 0 {
+		size := est.Count(formatTurn(turns[start-1]))
+		if used+size > budget {
+			break
+		}
+		used += size
+		start--
+	}
+	return turns[start:], start
+}
+
+// parseBullets returns the text of every bullet in raw. Lines that are not
+// bullets are ignored.
+func parseBullets(raw string) []string {
+	var bullets []string
+	for _, line := range strings.Split(raw, "\n") {
+		if m := bulletRe.FindStringSubmatch(line); m != nil {
+			if text := strings.TrimSpace(m[1]); text != "" {
+				bullets = append(bullets, text)
+			}
+		}
+	}
+	return bullets
+}
@@ -0,0 +1,109 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/tokens"
+)
+
+type mockLLM struct {
+	responses []string
+	err       error
+	calls     int
+	system    string
+	prompt    string
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.system, m.prompt = msgs[0].Content, msgs[1].Content
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+func sampleTranscript() *debate.Transcript {
+	return &debate.Transcript{
+		Topic:             "test topic",
+		Phase:             debate.TenthManPhase,
+		ConsensusPosition: "Adopt caching.",
+		Turns: []debate.Turn{
+			{Round: 1, Agent: debate.Agent{Name: "Alice", Role: "debater"}, Content: "Caching cuts latency."},
+			{Round: 2, Agent: debate.Agent{Name: "Tenth Man", Role: "tenth-man"}, Content: "Caching adds staleness."},
+		},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	llm := &mockLLM{responses: []string{"Summary:\n- The group agreed to adopt caching.\n* The Tenth Man warned about staleness.\n1. The consensus held.\n- Add cache invalidation tests."}}
+	consensus := &debate.ConsensusResult{Detected: true, Position: "Adopt caching.", Score: 8, Dissenters: []string{"Bob"}}
+	got, err := NewSummarizer(llm, "m").Summarize(context.Background(), sampleTranscript(), consensus)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 4 || got[1] != "The Tenth Man warned about staleness." || got[2] != "The consensus held." {
+		t.Errorf("unexpected bullets %q", got)
+	}
+	for _, want := range []string{"Consensus challenged by the Tenth Man: Adopt caching.", "Final consensus: Adopt caching. (agreement 8/10)", "Still dissenting: Bob", "Verdict: the consensus survived the Tenth Man unchanged", "Tenth Man (tenth-man): Caching adds staleness."} {
+		if !strings.Contains(llm.prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, llm.prompt)
+		}
+	}
+}
+
+func TestSummarizeRetriesThenGivesUp(t *testing.T) {
+	llm := &mockLLM{responses: []string{"- Only one bullet."}}
+	got, err := NewSummarizer(llm, "m").Summarize(context.Background(), sampleTranscript(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != nil || llm.calls != maxSummaryRetries {
+		t.Errorf("expected no bullets after %d calls, got %q after %d", maxSummaryRetries, got, llm.calls)
+	}
+	if !strings.Contains(llm.prompt, "Verdict: the consensus was overturned by the Tenth Man") {
+		t.Errorf("a missing final consensus should read as overturned, got:\n%s", llm.prompt)
+	}
+}
+
+func TestSummarizeError(t *testing.T) {
+	llm := &mockLLM{err: errors.New("boom")}
+	if _, err := NewSummarizer(llm, "m").Summarize(context.Background(), sampleTranscript(), nil); err == nil {
+		t.Error("expected the client error")
+	}
+}
+
+func TestSelectTurnsKeepsTenthManRounds(t *testing.T) {
+	turns := []debate.Turn{
+		{Round: 1, Agent: debate.Agent{Name: "A", Role: "debater"}, Content: strings.Repeat("a", 100)},
+		{Round: 2, Agent: debate.Agent{Name: "B", Role: "debater"}, Content: strings.Repeat("b", 100)},
+		{Round: 3, Agent: debate.Agent{Name: "T", Role: "tenth-man"}, Content: strings.Repeat("t", 100)},
+	}
+	got, omitted := selectTurns(turns, tokens.Default, 85)
+	if omitted != 1 || len(got) != 2 || got[0].Agent.Name != "B" {
+		t.Errorf("expected the earliest turn dropped, got %d omitted and %+v", omitted, got)
+	}
+	got, omitted = selectTurns(turns, tokens.Default, 3)
+	if omitted != 2 || len(got) != 1 || got[0].Agent.Name != "T" {
+		t.Errorf("expected only the Tenth Man turn kept, got %d omitted and %+v", omitted, got)
+	}
+}
+
+func TestSummarizeFencesTurns(t *testing.T) {
+	llm := &mockLLM{responses: []string{"- a\n- b\n- c"}}
+	if _, err := NewSummarizer(llm, "m").Summarize(context.Background(), sampleTranscript(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(llm.prompt, "<untrusted source=\"transcript\">\nRound 1, Alice (debater): Caching cuts latency.") || !strings.Contains(llm.system, guard.Instruction) {
+		t.Errorf("expected the turns fenced as untrusted, got system %q and prompt %q", llm.system, llm.prompt)
+	}
+}
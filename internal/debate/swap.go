@@ -0,0 +1,56 @@
+package debate
+
+import "strings"
+
+// ModelSwap is a change of an agent's model during a debate.
+type ModelSwap struct {
+	Round int    // first round on the new model
+	Agent string // name of the agent
+	From  string // model before the swap
+	To    string // model from Round on
+}
+
+// SwapModel queues a change of the named agent's model to model. It takes
+// effect at the start of the next round and is recorded in the transcript's
+// ModelSwaps. Names match case-insensitively; a swap naming no agent in the
+// debate is dropped. SwapModel is safe to call while Run is in progress.
+func (e *Engine) SwapModel(agent, model string) {
+	e.pendingMu.Lock()
+	defer e.pendingMu.Unlock()
+	e.pendingSwaps = append(e.pendingSwaps, ModelSwap{Agent: agent, To: model})
+}
+
+// applySwaps applies every queued model swap from round on.
+func (e *Engine) applySwaps(round int) {
+	e.pendingMu.Lock()
+	swaps := e.pendingSwaps
+	e.pendingSwaps = nil
+	e.pendingMu.Unlock()
+	for _, swap := range swaps {
+		for i, agent := range e.agents {
+			if !strings.EqualFold(agent.Name, swap.Agent) || agent.Model == swap.To {
+				continue
+			}
+			swap.Round = round
+			swap.Agent = agent.Name
+			swap.From = agent.Model
+			e.agents[i].Model = swap.To
+			e.transcript.ModelSwaps = append(e.transcript.ModelSwaps, swap)
+			e.emit(ModelSwapped{Swap: swap})
+			break
+		}
+	}
+}
+
+// ApplyModelSwaps returns agents with the swaps recorded in t applied, so a
+// resumed or continued debate keeps the models agents ended on.
+func ApplyModelSwaps(agents []Agent, t *Transcript) []Agent {
+	for _, swap := range t.ModelSwaps {
+		for i := range agents {
+			if agents[i].Name == swap.Agent {
+				agents[i].Model = swap.To
+			}
+		}
+	}
+	return agents
+}
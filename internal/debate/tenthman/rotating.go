@@ -0,0 +1,57 @@
+package tenthman
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// lenses are the lines of attack the rotating devil's advocate cycles
+// through, one per turn.
+var lenses = []string{
+	"the evidence: which claims rest on weak, missing or cherry-picked data",
+	"incentives: who benefits from the consensus and how that biases it",
+	"second-order effects: what happens after the consensus is acted on",
+	"precedent: when similar consensus positions failed in the past",
+	"the worst case: the most damaging realistic scenario if the group is wrong",
+}
+
+// RotatingActivator implements debate.TenthManActivator with a devil's
+// advocate who attacks the consensus from a different angle every turn, so
+// the challenge covers more ground than one sustained argument.
+type RotatingActivator struct {
+	mu   sync.Mutex
+	next int
+}
+
+// NewRotatingActivator creates a RotatingActivator.
+func NewRotatingActivator() *RotatingActivator {
+	return &RotatingActivator{}
+}
+
+// BuildAgent returns the devil's advocate agent.
+func (a *RotatingActivator) BuildAgent(consensusPosition string, agentID int, model string) debate.Agent {
+	return debate.Agent{
+		ID:    agentID,
+		Name:  "The Devil's Advocate",
+		Model: model,
+		Role:  "tenth-man",
+	}
+}
+
+// SystemPrompt returns the prompt for the devil's advocate's next turn,
+// moving on to the next line of attack each time it is called.
+func (a *RotatingActivator) SystemPrompt(consensusPosition string) string {
+	a.mu.Lock()
+	lens := lenses[a.next%len(lenses)]
+	a.next++
+	a.mu.Unlock()
+	return fmt.Sprintf(
+		"You are The Devil's Advocate. The group has reached consensus on the following position: %s. "+
+			"You are OBLIGATED to argue against it. This turn, attack it through %s. "+
+			"Do not repeat objections you already made; build the strongest case from this angle. "+
+			"Be thorough but concise.",
+		consensusPosition, lens,
+	)
+}
@@ -0,0 +1,44 @@
+package tenthman
+
+import (
+	"fmt"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// SocraticActivator implements debate.TenthManActivator with a Tenth Man
+// who attacks the consensus through pointed questions instead of
+// assertions, and holds the debaters to answering every one of them.
+type SocraticActivator struct{}
+
+// NewSocraticActivator creates a SocraticActivator.
+func NewSocraticActivator() *SocraticActivator {
+	return &SocraticActivator{}
+}
+
+// BuildAgent returns an Agent configured as The Tenth Man.
+func (a *SocraticActivator) BuildAgent(consensusPosition string, agentID int, model string) debate.Agent {
+	return debate.Agent{
+		ID:    agentID,
+		Name:  "The Tenth Man",
+		Model: model,
+		Role:  "tenth-man",
+	}
+}
+
+// SystemPrompt returns the questioning system prompt for the Tenth Man.
+func (a *SocraticActivator) SystemPrompt(consensusPosition string) string {
+	return fmt.Sprintf(
+		"You are The Tenth Man. The group has reached consensus on the following position: %s. "+
+			"You are OBLIGATED to challenge it, but only by asking questions: do not argue the contrary yourself. "+
+			"Ask two to four pointed questions, numbered Q1, Q2 and so on, each exposing a hidden assumption, missing evidence or a consequence the group has not faced. "+
+			"Press on answers that dodged your earlier questions instead of moving on. "+
+			"Be thorough but concise.",
+		consensusPosition,
+	)
+}
+
+// DebaterInstructions requires the debaters to answer every question.
+func (a *SocraticActivator) DebaterInstructions() string {
+	return "The Tenth Man challenges the consensus with numbered questions. Answer each of its latest questions explicitly and in order, starting each answer with its number (Q1, Q2, ...). If you cannot answer one, say so and what that means for the consensus."
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/tenthman"
 )
 
@@ -45,3 +46,37 @@ func TestSystemPromptContainsContraryMandate(t *testing.T) {
 		t.Error("expected prompt to contain 'contrary'")
 	}
 }
+
+func TestSocraticActivatorAsksQuestionsDebatersMustAnswer(t *testing.T) {
+	a := tenthman.NewSocraticActivator()
+	if agent := a.BuildAgent("some consensus", 4, "m"); agent.Role != "tenth-man" || agent.Name != "The Tenth Man" {
+		t.Errorf("expected The Tenth Man, got %+v", agent)
+	}
+	prompt := a.SystemPrompt("some consensus")
+	if !strings.Contains(prompt, "some consensus") || !strings.Contains(prompt, "only by asking questions") {
+		t.Errorf("expected the position and the questioning mandate in %q", prompt)
+	}
+	var _ debate.DebaterInstructor = a
+	if !strings.Contains(a.DebaterInstructions(), "Answer each of its latest questions") {
+		t.Errorf("unexpected debater instructions %q", a.DebaterInstructions())
+	}
+}
+
+func TestRotatingActivatorChangesAngleEachTurn(t *testing.T) {
+	a := tenthman.NewRotatingActivator()
+	agent := a.BuildAgent("some consensus", 4, "m")
+	if agent.Role != "tenth-man" || agent.ID != 4 {
+		t.Errorf("expected a tenth-man agent with ID 4, got %+v", agent)
+	}
+
+	first := a.SystemPrompt("some consensus")
+	second := a.SystemPrompt("some consensus")
+	if first == second {
+		t.Error("expected consecutive prompts to attack from different angles")
+	}
+	for _, prompt := range []string{first, second} {
+		if !strings.Contains(prompt, "some consensus") || !strings.Contains(prompt, "OBLIGATED") {
+			t.Errorf("expected the position and the mandate in %q", prompt)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package debate
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const replyInstruction = `Pick one specific argument from an earlier turn and address it directly. Start your response with a line of the form "Re: #N", where N is the number shown in brackets before that turn.`
+
+// replyMarkerRe matches a leading "Re: #N" or "Re: Name" line, tolerating
+// markdown emphasis around it.
+var replyMarkerRe = regexp.MustCompile(`(?i)^[\s*_]*re:\s*(?:#(\d+)|([^\n*_]+?))[\s*_]*(?:\n|$)`)
+
+// parseReply resolves a leading reply marker in content to the ID of an
+// earlier turn and returns the content without it. A marker naming an agent
+// resolves to that agent's most recent turn. Content is returned unchanged
+// when there is no marker or it does not resolve.
+func parseReply(content string, turns []Turn) (int, string) {
+	m := replyMarkerRe.FindStringSubmatch(content)
+	if m == nil {
+		return 0, content
+	}
+	id := 0
+	if m[1] != "" {
+		n, err := strconv.Atoi(m[1])
+		if err == nil && n >= 1 && n <= len(turns) {
+			id = n
+		}
+	} else {
+		name := strings.TrimSpace(m[2])
+		for i := len(turns) - 1; i >= 0; i-- {
+			if strings.EqualFold(turns[i].Agent.Name, name) {
+				id = turns[i].ID
+				break
+			}
+		}
+	}
+	if id == 0 {
+		return 0, content
+	}
+	return id, strings.TrimSpace(content[len(m[0]):])
+}
@@ -0,0 +1,53 @@
+package debate
+
+// Kinds of topic.
+const (
+	QuestionOfFact  = "fact"  // settled by evidence: what is, was or will be the case
+	QuestionOfValue = "value" // settled by judgement: what is better, right or worth doing
+)
+
+// TopicClass is what kind of question a debate's topic asks.
+type TopicClass struct {
+	Kind   string // QuestionOfFact or QuestionOfValue
+	Reason string `json:",omitempty"` // why the classifier chose Kind; empty if Kind was given
+}
+
+var debaterGuidance = map[string]string{
+	QuestionOfFact:  "This is a question of fact: ground your arguments in evidence, data and verifiable sources, say what evidence would settle it, and claim no more certainty than the evidence allows.",
+	QuestionOfValue: "This is a question of value: no evidence settles it on its own. Make the values and frameworks behind your position explicit, weigh the tradeoffs between them, and say which you would give up and why.",
+}
+
+var judgeGuidance = map[string]string{
+	QuestionOfFact:  "The topic is a question of fact. Weigh agreement grounded in evidence above agreement by assertion, and do not count agents as agreeing if they accept the same conclusion on contradictory evidence.",
+	QuestionOfValue: "The topic is a question of value. Agents agree when they accept the same tradeoff, not merely the same facts: agents who share the facts but rank the values differently still disagree.",
+}
+
+// SetTopicClass records c in the transcript and adapts the debaters'
+// prompts to it; judges that read TopicClass adapt too. A nil c argues the
+// topic without regard to its kind. Resume and Continue use the class the
+// prior transcript records instead.
+func (e *Engine) SetTopicClass(c *TopicClass) {
+	e.transcript.TopicClass = c
+}
+
+// JudgeGuidance returns how a judge should read a debate on a topic of c's
+// kind, or "" if c is nil or of an unknown kind.
+func (c *TopicClass) JudgeGuidance() string {
+	if c == nil {
+		return ""
+	}
+	return judgeGuidance[c.Kind]
+}
+
+// guidance returns the scenario instructions debaters argue under, with the
+// guidance for the topic's kind.
+func (e *Engine) guidance() string {
+	c := e.transcript.TopicClass
+	if c == nil || debaterGuidance[c.Kind] == "" {
+		return e.instructions
+	}
+	if e.instructions == "" {
+		return debaterGuidance[c.Kind]
+	}
+	return debaterGuidance[c.Kind] + " " + e.instructions
+}
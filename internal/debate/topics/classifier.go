@@ -0,0 +1,63 @@
+// Package topics classifies a debate topic as a question of fact, settled by
+// evidence, or a question of value, settled by judgement, before the debate
+// starts, so it can be argued and judged accordingly.
+package topics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/llmjson"
+)
+
+const maxClassifyRetries = 3
+
+type answer struct {
+	Kind   string `json:"kind"`
+	Reason string `json:"reason"`
+}
+
+// validate rejects an answer of an unknown kind, ignoring case.
+func validate(a *answer) error {
+	a.Kind = strings.ToLower(strings.TrimSpace(a.Kind))
+	if a.Kind != debate.QuestionOfFact && a.Kind != debate.QuestionOfValue {
+		return fmt.Errorf("kind is %q, want %q or %q", a.Kind, debate.QuestionOfFact, debate.QuestionOfValue)
+	}
+	return nil
+}
+
+// Classifier classifies debate topics using an LLM.
+type Classifier struct {
+	llm   debate.LLMClient
+	model string
+}
+
+// NewClassifier creates a new Classifier.
+func NewClassifier(llm debate.LLMClient, model string) *Classifier {
+	return &Classifier{llm: llm, model: model}
+}
+
+// Classify returns the kind of question topic asks. A topic that mixes both
+// is classed by the question its conclusion has to answer. If the model
+// never gives a valid answer, Classify returns nil rather than an error.
+func (c *Classifier) Classify(ctx context.Context, topic string) (*debate.TopicClass, error) {
+	out, err := llmjson.StructuredCall(ctx, c.llm, c.model, llmjson.Prompt[answer]{
+		System:   `You classify debate topics before the debate starts. A question of fact asks what is, was or will be the case, and evidence could settle it, e.g. "Did remote work lower productivity at large firms?". A question of value asks what is better, right or worth doing, and settling it takes weighing values and tradeoffs, e.g. "Should we mandate remote work?". If a topic mixes both, class it by the question its conclusion has to answer.`,
+		Schema:   `{"kind": "fact" | "value", "reason": "..."}`,
+		Notes:    `"reason" says in one sentence why.`,
+		User:     "Topic: " + topic,
+		Required: []string{"kind"},
+		Validate: validate,
+		Attempts: maxClassifyRetries,
+	})
+	if errors.As(err, new(*llmjson.InvalidError)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("topics: %w", err)
+	}
+	return &debate.TopicClass{Kind: out.Kind, Reason: strings.TrimSpace(out.Reason)}, nil
+}
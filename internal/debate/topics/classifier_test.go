@@ -0,0 +1,63 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+type mockLLM struct {
+	responses []string
+	err       error
+	calls     int
+	last      []openrouter.Message
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.last = msgs
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+func TestClassify(t *testing.T) {
+	llm := &mockLLM{responses: []string{
+		`{"kind": "policy", "reason": "It asks what to do."}`,
+		"```json\n" + `{"kind": " Fact ", "reason": " Studies could settle it. "}` + "\n```",
+	}}
+	got, err := NewClassifier(llm, "m").Classify(context.Background(), "Did remote work lower productivity?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || got.Kind != debate.QuestionOfFact || got.Reason != "Studies could settle it." {
+		t.Errorf("Classify() = %+v, want a question of fact", got)
+	}
+	if llm.calls != 2 || !strings.Contains(llm.last[len(llm.last)-1].Content, `kind is "policy"`) {
+		t.Errorf("expected an unknown kind to be asked again with the reason, got %d calls", llm.calls)
+	}
+}
+
+func TestClassifyGivesUp(t *testing.T) {
+	llm := &mockLLM{responses: []string{"It depends."}}
+	got, err := NewClassifier(llm, "m").Classify(context.Background(), "topic")
+	if err != nil || got != nil {
+		t.Errorf("Classify() = %+v, %v, want nil without an error", got, err)
+	}
+	if llm.calls != maxClassifyRetries {
+		t.Errorf("expected %d attempts, got %d", maxClassifyRetries, llm.calls)
+	}
+
+	llm = &mockLLM{err: errors.New("boom")}
+	if _, err := NewClassifier(llm, "m").Classify(context.Background(), "topic"); err == nil || !strings.HasPrefix(err.Error(), "topics: ") {
+		t.Errorf("expected a failed call to fail, got %v", err)
+	}
+}
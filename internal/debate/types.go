@@ -16,30 +16,110 @@ const (
 
 // Agent represents a debate participant.
 type Agent struct {
-	ID    int
-	Name  string
-	Model string // OpenRouter model ID
-	Role  string // "debater" or "tenth-man"
+	ID          int
+	Name        string
+	Model       string   // OpenRouter model ID
+	Role        string   // "debater" or "tenth-man"
+	Persona     string   `json:",omitempty"` // optional perspective the agent argues from
+	Frame       string   `json:",omitempty"` // optional analytical instructions specific to the agent
+	Temperature *float64 `json:",omitempty"` // sampling temperature; nil uses the model default
+	// ReasoningEffort is low, medium or high for reasoning models; "" uses
+	// the model default.
+	ReasoningEffort string `json:",omitempty"`
 }
 
 // Turn represents a single agent's contribution in a round.
 type Turn struct {
-	Round   int
-	Agent   Agent
-	Content string
+	ID         int // 1-based position in the transcript
+	Round      int
+	Agent      Agent
+	Content    string
+	InReplyTo  int  `json:",omitempty"` // ID of the turn this one answers; 0 if none
+	Confidence *int `json:",omitempty"` // self-reported confidence in the agent's position, 0-100
+	Tokens     int  `json:",omitempty"` // tokens the provider reported for the completion
+	LatencyMS  int  `json:",omitempty"` // milliseconds the completion took, retries included
+	// Shortened is "restated" or "truncated" when the reply was over the
+	// engine's word limit.
+	Shortened string `json:",omitempty"`
+	// Samples is how many candidate replies the turn was chosen from when
+	// best-of-N sampling is on.
+	Samples int `json:",omitempty"`
+	// Critique is the critic's review the turn was revised against when
+	// refinement is on; empty if the draft was published as it was.
+	Critique string `json:",omitempty"`
+	// Passed is set when the agent passed, having nothing new to add; the
+	// turn has no content and stands by the agent's previous one.
+	Passed bool `json:",omitempty"`
+	// Statement is OpeningStatement or ClosingStatement for the turns of
+	// the statement rounds, and empty otherwise.
+	Statement string `json:",omitempty"`
+	// Condensed is how many earlier turns were condensed or omitted from
+	// the turn's prompt to fit its model's context window.
+	Condensed int `json:",omitempty"`
 }
 
 // Transcript holds the full state of a debate.
 type Transcript struct {
-	Topic  string
-	Turns  []Turn
-	Phase  Phase
-	Rounds int
+	Topic      string
+	Project    string   `json:",omitempty"` // project tag the run was filed under, if any
+	Images     []string `json:",omitempty"` // files or URLs attached to the topic, as given
+	Turns      []Turn
+	Phase      Phase
+	Rounds     int
+	Confidence []RoundConfidence `json:",omitempty"` // group confidence per round, for rounds where any was reported
+	Evidence   []Evidence        `json:",omitempty"` // answered evidence requests, in order
+	Agreement  []RoundAgreement  `json:",omitempty"` // per-agent agreement after each evaluation whose judge scored agents
+	// ConsensusScores is the judge's agreement score after every evaluation.
+	ConsensusScores []RoundScore `json:",omitempty"`
+	// Disagreement holds the pairwise disagreement between agents in each
+	// round, when it was analysed.
+	Disagreement []RoundDisagreement `json:",omitempty"`
+	// JudgeDiagnostics records every consensus judge response that was
+	// rejected as unparseable or invalid, in order.
+	JudgeDiagnostics []JudgeDiagnostic `json:",omitempty"`
+	// Reasoning holds the reasoning traces agents produced before their
+	// turns. It is kept apart from the turns so that neither the agents nor
+	// the judge ever see it.
+	Reasoning []ReasoningTrace `json:",omitempty"`
+	// ModelSwaps records every agent model replaced mid-debate, in order.
+	ModelSwaps []ModelSwap `json:",omitempty"`
+	// Removals records every debater taken out mid-debate, in order.
+	Removals []AgentRemoval `json:",omitempty"`
+	// Joins records every debater added mid-debate, in order.
+	Joins []AgentJoin `json:",omitempty"`
+	// CrossExamination holds the question and answer pairs of the
+	// cross-examination round, in order.
+	CrossExamination []Exchange `json:",omitempty"`
+	// ReasoningFlags holds the fallacies and biases found in the turns once
+	// the debate ended, in turn order.
+	ReasoningFlags []ReasoningFlag `json:",omitempty"`
+	// FactChecks holds the verdicts on the factual claims of the final
+	// consensus position, when it was fact-checked.
+	FactChecks []FactCheck `json:",omitempty"`
+	// TopicClass is whether the topic is a question of fact or of value,
+	// when it was classified; it adapts how the debate is argued and judged.
+	TopicClass *TopicClass `json:",omitempty"`
+	// InjectionWarnings flags the turns and evidence that looked like
+	// prompt injection attempts, in order.
+	InjectionWarnings []InjectionWarning `json:",omitempty"`
+
+	ConsensusPosition string `json:",omitempty"` // the position the Tenth Man was asked to challenge
+	// PositionChange compares ConsensusPosition with the final consensus;
+	// nil if the Tenth Man never spoke.
+	PositionChange *PositionChange `json:",omitempty"`
+
+	Outcome Verdict `json:",omitempty"` // how the debate ended; empty until it does
+	Tokens  int     `json:",omitempty"` // LLM tokens spent on the debate, when metered
+
+	Summary []string `json:",omitempty"` // executive summary bullets, written once the debate ends
+
+	JudgeModel  string `json:",omitempty"` // model of the consensus judge, when the runner knows it
+	Evaluations int    `json:",omitempty"` // valid verdicts the judge gave; fallback verdicts are not counted
 }
 
 // LLMClient interface so we can mock the OpenRouter client.
 type LLMClient interface {
-	ChatCompletion(ctx context.Context, model string, messages []openrouter.Message) (*openrouter.ChatResponse, error)
+	ChatCompletion(ctx context.Context, model string, messages []openrouter.Message, opts ...openrouter.Option) (*openrouter.ChatResponse, error)
 }
 
 // ConsensusResult is used by the consensus judge.
@@ -48,6 +128,98 @@ type ConsensusResult struct {
 	Position   string   `json:"consensus_position"`
 	Score      int      `json:"agreement_score"`
 	Dissenters []string `json:"dissenting_agents"`
+	// AgentScores is each agent's agreement with Position, 1-10, by name.
+	// Low scores mark strong dissent. Judges may leave it empty.
+	AgentScores map[string]int `json:"agent_scores,omitempty"`
+	Fallback    bool           `json:"fallback,omitempty"` // decided by the rule-based fallback because the LLM judge gave no usable verdict
+	// Participation is the share of the latest round's turns that were
+	// substantive. The engine sets it and scales Score by it.
+	Participation float64 `json:"participation,omitempty"`
+	// Diagnostics describes the judge responses rejected on the way to this
+	// result. The engine moves them into Transcript.JudgeDiagnostics.
+	Diagnostics []JudgeDiagnostic `json:"-"`
+}
+
+// RoundAgreement is the per-agent agreement a judge reported after a round.
+type RoundAgreement struct {
+	Round  int
+	Scores map[string]int // agreement with the consensus position, 1-10, by agent name
+}
+
+// RoundScore is the consensus a judge reported after a round.
+type RoundScore struct {
+	Round    int
+	Score    int // agreement score, 0-10, after weighing participation
+	Detected bool
+	Fallback bool `json:",omitempty"` // judged by the rule-based fallback
+}
+
+// RoundDisagreement is how strongly each pair of agents disagreed in a
+// round.
+type RoundDisagreement struct {
+	Round  int
+	Agents []string // in speaking order
+	// Scores[i][j] is the disagreement between Agents[i] and Agents[j],
+	// from 0 (same position) to 10 (directly opposed), or -1 if unrated.
+	Scores [][]int
+	Source string // "judge", or "agreement" if derived from the judge's agreement scores
+}
+
+// ReasoningTrace is the private reasoning behind one turn.
+type ReasoningTrace struct {
+	TurnID int
+	Agent  string
+	Text   string
+}
+
+// JudgeDiagnostic describes one consensus judge response that was rejected.
+type JudgeDiagnostic struct {
+	Round    int    // rounds completed when the judge was asked
+	Attempt  int    // 1-based attempt within that evaluation
+	Error    string // why the response was rejected
+	Response string // the response, truncated
+}
+
+// Claim is a discrete position argued in the debate, with the agents on each
+// side of it.
+type Claim struct {
+	Statement  string   `json:"statement"`
+	Supporters []string `json:"supporting_agents"`
+	Opponents  []string `json:"opposing_agents"`
+	Rebuttals  []string `json:"tenth_man_rebuttals"`
+}
+
+// ActionItems are the operational takeaways of a debate's final rounds.
+type ActionItems struct {
+	Actions       []Action `json:"actions"`
+	OpenQuestions []string `json:"open_questions"`
+	Research      []string `json:"follow_up_research"`
+}
+
+// Action is a concrete step the debate recommends.
+type Action struct {
+	Description string   `json:"description"`
+	Rationale   string   `json:"rationale,omitempty"`
+	RaisedBy    []string `json:"raised_by,omitempty"`
+}
+
+// ReasoningFlag is a logical fallacy or cognitive bias found in a turn.
+type ReasoningFlag struct {
+	TurnID      int
+	Agent       string
+	Kind        string // e.g. "strawman", "appeal to authority" or "sunk cost"
+	Category    string // "fallacy" or "bias"
+	Excerpt     string `json:",omitempty"` // the words that show it
+	Explanation string `json:",omitempty"`
+}
+
+// FactCheck is the verdict on one factual claim of the final consensus
+// position.
+type FactCheck struct {
+	Claim    string
+	Verdict  string // "verified", "disputed" or "unverifiable"
+	Note     string `json:",omitempty"` // why, or why the evidence could not be retrieved
+	Evidence string `json:",omitempty"` // what retrieval found for the claim, if a retriever was used
 }
 
 // ConsensusJudge interface so we can mock consensus detection.
@@ -61,8 +233,44 @@ type TenthManActivator interface {
 	SystemPrompt(consensusPosition string) string
 }
 
+// DebaterInstructor is implemented by Tenth Man activators whose challenge
+// asks something specific of the debaters, such as answering its questions.
+type DebaterInstructor interface {
+	// DebaterInstructions is added to every debater's Phase 2 system prompt.
+	DebaterInstructions() string
+}
+
+// Checkpointer persists the transcript as the debate progresses.
+type Checkpointer interface {
+	Checkpoint(ctx context.Context, transcript *Transcript) error
+}
+
 // Result holds the complete output of a debate run.
 type Result struct {
 	Transcript *Transcript
 	Consensus  *ConsensusResult
+	Stagnated  bool    // Phase 1 ended early because rounds stopped adding new information
+	Settled    string  // why adaptive rounds ended Phase 1 before the maximum rounds; "" if they did not
+	Partial    bool    // the retry budget ran out or the debate was stopped, so planned rounds or minority reports are missing
+	Stopped    bool    // Engine.Stop ended the debate early
+	Outcome    Verdict // how the consensus fared against the Tenth Man; see Classify
+	// MinorityReports holds one summary of unresolved objections per agent
+	// still dissenting after the final evaluation.
+	MinorityReports []MinorityReport
+}
+
+// MinorityReport is a dissenting agent's summary of its unresolved objections.
+type MinorityReport struct {
+	Agent      Agent
+	Objections string
+}
+
+// RoundSummary describes a round for the OnRoundStart and OnRoundEnd
+// callbacks. Turns, Confidence and Evidence are only set when the round ends.
+type RoundSummary struct {
+	Round      int
+	Phase      Phase
+	Turns      []Turn           // every turn of the round, including moderator notes
+	Confidence *RoundConfidence // nil if no agent reported confidence
+	Evidence   []Evidence       // evidence gathered at the end of the round
 }
@@ -0,0 +1,46 @@
+package debate
+
+import "strings"
+
+// Verdict is how a debate ended relative to the Tenth Man challenge.
+type Verdict string
+
+const (
+	VerdictNoConsensus Verdict = "no_consensus" // free debate never reached consensus
+	VerdictUpheld      Verdict = "upheld"       // consensus survived the Tenth Man unchanged
+	VerdictRevised     Verdict = "revised"      // consensus survived the Tenth Man with a changed position
+	VerdictOverturned  Verdict = "overturned"   // consensus broke under the Tenth Man
+)
+
+// Verdict returns the result's Outcome, classifying it with Classify if the
+// engine did not set one.
+func (r *Result) Verdict() Verdict {
+	if r.Outcome != "" {
+		return r.Outcome
+	}
+	return Classify(r.Transcript, r.Consensus)
+}
+
+// Classify compares the evaluations before and after the Tenth Man rounds.
+// A consensus survives when the final judge still detects it at or above
+// ConsensusThreshold; it is revised rather than upheld when the position
+// changed, as recorded in transcript.PositionChange or, without one, when
+// the final position differs from the challenged one.
+func Classify(transcript *Transcript, final *ConsensusResult) Verdict {
+	if transcript == nil || transcript.Phase != TenthManPhase {
+		return VerdictNoConsensus
+	}
+	if final == nil || !final.Detected || final.Score < ConsensusThreshold {
+		return VerdictOverturned
+	}
+	if change := transcript.PositionChange; change != nil {
+		if change.Changed {
+			return VerdictRevised
+		}
+		return VerdictUpheld
+	}
+	if before := strings.TrimSpace(transcript.ConsensusPosition); before != "" && !strings.EqualFold(before, strings.TrimSpace(final.Position)) {
+		return VerdictRevised
+	}
+	return VerdictUpheld
+}
@@ -0,0 +1,231 @@
+// Package eval runs a fixed suite of benchmark debates and checks structural
+// invariants of their results, such as consensus being detected where it is
+// expected, the Tenth Man taking part and the report being complete. Run it
+// against a recorded cassette or cheap models to validate prompt and
+// template changes before a release.
+package eval
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/health"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed suite.yaml
+var builtinSuite []byte
+
+// reportSections are the report.md headings every run must have.
+var reportSections = []string{"# Debate Report: ", "## Consensus", "## Transcript"}
+
+// Suite is the on-disk benchmark definition. Defaults fill in any zero
+// fields of the individual cases.
+type Suite struct {
+	Defaults runner.Job `yaml:"defaults"`
+	Cases    []Case     `yaml:"cases"`
+}
+
+// Case is one benchmark debate. Its job's Name identifies it and names its
+// run directory.
+type Case struct {
+	Job    runner.Job `yaml:",inline"`
+	Expect Expect     `yaml:"expect"`
+}
+
+// Expect lists the invariants a case's result must satisfy besides the ones
+// every run must: finishing, saving a readable transcript and writing a
+// report with its standard sections.
+type Expect struct {
+	Consensus *bool    `yaml:"consensus"` // whether the free debate reaches consensus; unset skips the check
+	TenthMan  bool     `yaml:"tenth_man"` // the Tenth Man takes at least one turn
+	Sections  []string `yaml:"sections"`  // extra report.md headings that must be present
+}
+
+// Builtin returns the bundled benchmark suite.
+func Builtin() (*Suite, error) {
+	return parse("builtin suite", builtinSuite)
+}
+
+// Load reads a suite from a YAML file.
+func Load(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("eval: %w", err)
+	}
+	return parse(path, data)
+}
+
+func parse(source string, data []byte) (*Suite, error) {
+	var s Suite
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("eval: parsing %s: %w", source, err)
+	}
+	if len(s.Cases) == 0 {
+		return nil, fmt.Errorf("eval: %s defines no cases", source)
+	}
+	seen := make(map[string]bool)
+	for i, c := range s.Cases {
+		if strings.TrimSpace(c.Job.Topic) == "" {
+			return nil, fmt.Errorf("eval: case %d: topic is required", i+1)
+		}
+		if c.Job.Name == "" {
+			return nil, fmt.Errorf("eval: case %d: name is required", i+1)
+		}
+		if seen[c.Job.Name] {
+			return nil, fmt.Errorf("eval: duplicate case %q", c.Job.Name)
+		}
+		seen[c.Job.Name] = true
+		s.Cases[i].Job = c.Job.WithDefaults(s.Defaults)
+	}
+	return &s, nil
+}
+
+// Filter returns the suite restricted to the named cases, in suite order.
+// No names returns s unchanged.
+func (s *Suite) Filter(names []string) (*Suite, error) {
+	if len(names) == 0 {
+		return s, nil
+	}
+	out := &Suite{Defaults: s.Defaults}
+	for _, name := range names {
+		if !slices.ContainsFunc(s.Cases, func(c Case) bool { return c.Job.Name == name }) {
+			return nil, fmt.Errorf("eval: unknown case %q", name)
+		}
+	}
+	for _, c := range s.Cases {
+		if slices.Contains(names, c.Job.Name) {
+			out.Cases = append(out.Cases, c)
+		}
+	}
+	return out, nil
+}
+
+// RunFunc executes a case's job.
+type RunFunc func(ctx context.Context, job runner.Job) (*runner.Outcome, error)
+
+// Result is how one case fared.
+type Result struct {
+	Case   string
+	Dir    string // run directory; empty if the run failed before creating it
+	Checks []health.Result
+}
+
+// Passed reports whether every check of the case passed.
+func (r Result) Passed() bool {
+	return health.Healthy(r.Checks)
+}
+
+// Run executes the cases of s one after another, so a recorded cassette
+// replays in the order it was recorded, with defaults filling the settings
+// the suite leaves unset. Artifacts are neither encrypted nor uploaded, so
+// they can be checked.
+func Run(ctx context.Context, s *Suite, defaults runner.Job, run RunFunc) []Result {
+	results := make([]Result, len(s.Cases))
+	for i, c := range s.Cases {
+		job := c.Job.WithDefaults(defaults)
+		job.EncryptTo, job.Upload = nil, ""
+		outcome, err := run(ctx, job)
+		results[i] = Result{Case: job.Name}
+		if outcome != nil {
+			results[i].Dir = outcome.Dir
+		}
+		if err == nil && (outcome == nil || outcome.Result == nil) {
+			err = fmt.Errorf("no result")
+		}
+		if err != nil {
+			results[i].Checks = []health.Result{{Name: "debate completes", Error: err.Error()}}
+			continue
+		}
+		results[i].Checks = health.Run(ctx, Checks(outcome, c.Expect))
+	}
+	return results
+}
+
+// Checks returns the invariants outcome must satisfy: the ones every run
+// must, plus the ones expect asks for.
+func Checks(outcome *runner.Outcome, expect Expect) []health.Check {
+	result := outcome.Result
+	checks := []health.Check{
+		{Name: "debate completes", Run: func(context.Context) error {
+			if result.Partial {
+				return fmt.Errorf("the retry budget ran out after round %d", result.Transcript.Rounds)
+			}
+			return nil
+		}},
+		{Name: "transcript saved", Run: func(context.Context) error {
+			t, err := runs.LoadTranscript(outcome.Dir)
+			if err != nil {
+				return err
+			}
+			if len(t.Turns) == 0 {
+				return fmt.Errorf("no turns recorded")
+			}
+			return nil
+		}},
+		{Name: "report complete", Run: func(context.Context) error {
+			return checkReport(outcome.Dir, slices.Concat(reportSections, expect.Sections))
+		}},
+	}
+	if expect.Consensus != nil {
+		want := *expect.Consensus
+		name := "consensus reached"
+		if !want {
+			name = "no consensus"
+		}
+		checks = append(checks, health.Check{Name: name, Run: func(context.Context) error {
+			if got := result.Verdict() != debate.VerdictNoConsensus; got != want {
+				return fmt.Errorf("free debate ended with verdict %q after %d rounds", result.Verdict(), result.Transcript.Rounds)
+			}
+			return nil
+		}})
+	}
+	if expect.TenthMan {
+		checks = append(checks, health.Check{Name: "tenth man activated", Run: func(context.Context) error {
+			for _, turn := range result.Transcript.Turns {
+				if turn.Agent.Role == "tenth-man" {
+					return nil
+				}
+			}
+			return fmt.Errorf("no Tenth Man turn in %d turns", len(result.Transcript.Turns))
+		}})
+	}
+	return checks
+}
+
+// checkReport checks that dir's report.md has a line starting with each of
+// headings.
+func checkReport(dir string, headings []string) error {
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, h := range headings {
+		if !strings.HasPrefix(string(data), h) && !strings.Contains(string(data), "\n"+h) {
+			missing = append(missing, strings.TrimSpace(h))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Passed reports whether every case passed.
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if !r.Passed() {
+			return false
+		}
+	}
+	return true
+}
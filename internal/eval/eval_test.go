@@ -0,0 +1,106 @@
+package eval
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/mockserver"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+)
+
+func TestBuiltinSuitePassesAgainstMockServer(t *testing.T) {
+	suite, err := Builtin()
+	if err != nil {
+		t.Fatalf("Builtin() error = %v", err)
+	}
+	srv := httptest.NewServer(mockserver.New())
+	defer srv.Close()
+	client := openrouter.NewClientWithBaseURL("mock", srv.URL)
+	registry := models.NewRegistry(models.DefaultFreeModels())
+	out := t.TempDir()
+
+	results := Run(context.Background(), suite, runner.Job{}, func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		return runner.Run(ctx, client, registry, out, job, runner.Hooks{})
+	})
+	if len(results) != len(suite.Cases) {
+		t.Fatalf("got %d results for %d cases", len(results), len(suite.Cases))
+	}
+	for _, r := range results {
+		for _, c := range r.Checks {
+			if !c.OK {
+				t.Errorf("%s: %s: %s", r.Case, c.Name, c.Error)
+			}
+		}
+	}
+	if !Passed(results) {
+		t.Error("Passed() = false")
+	}
+}
+
+func TestChecksReportFailures(t *testing.T) {
+	suite, _ := Builtin()
+	suite, err := suite.Filter([]string{"four-day-week"})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	srv := httptest.NewServer(mockserver.New())
+	defer srv.Close()
+	client := openrouter.NewClientWithBaseURL("mock", srv.URL)
+	registry := models.NewRegistry(models.DefaultFreeModels())
+
+	yes := true
+	suite.Cases[0].Expect = Expect{Consensus: &yes, TenthMan: true, Sections: []string{"## Fact Check"}}
+	results := Run(context.Background(), suite, runner.Job{}, func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		outcome, err := runner.Run(ctx, client, registry, t.TempDir(), job, runner.Hooks{})
+		if err == nil {
+			os.Remove(filepath.Join(outcome.Dir, "transcript.json"))
+		}
+		return outcome, err
+	})
+	failed := make(map[string]string)
+	for _, c := range results[0].Checks {
+		if !c.OK {
+			failed[c.Name] = c.Error
+		}
+	}
+	for _, name := range []string{"consensus reached", "tenth man activated", "transcript saved", "report complete"} {
+		if _, ok := failed[name]; !ok {
+			t.Errorf("check %q passed, want failure (failures: %v)", name, failed)
+		}
+	}
+	if !strings.Contains(failed["report complete"], "## Fact Check") {
+		t.Errorf("report complete error = %q", failed["report complete"])
+	}
+	if results[0].Passed() {
+		t.Error("Passed() = true")
+	}
+}
+
+func TestLoadSuite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "suite.yaml")
+	os.WriteFile(path, []byte("defaults:\n  agents: 4\ncases:\n  - name: a\n    topic: A?\n    expect:\n      consensus: false\n"), 0o644)
+	suite, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	c := suite.Cases[0]
+	if c.Job.Agents != 4 || c.Expect.Consensus == nil || *c.Expect.Consensus {
+		t.Errorf("case = %+v", c)
+	}
+	if _, err := suite.Filter([]string{"b"}); err == nil {
+		t.Error("expected error filtering on an unknown case")
+	}
+
+	for _, bad := range []string{"cases: []\n", "cases:\n  - topic: A?\n", "cases:\n  - name: a\n", "cases:\n  - {name: a, topic: A?}\n  - {name: a, topic: B?}\n"} {
+		os.WriteFile(path, []byte(bad), 0o644)
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+// Package expr evaluates the small condition expressions users write to
+// steer a debate, such as
+//
+//	consensus.score >= 8 && round >= 4 && dissenters == 0
+//
+// Expressions combine numbers, true and false, and variables with
+// arithmetic (+ - * / %), comparisons (== != < <= > >=), the logical
+// operators && || ! and parentheses. They are type-checked when compiled,
+// so a typo or a comparison of a number with a boolean is reported before
+// the debate starts rather than in the middle of it.
+package expr
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of a value.
+type Kind int
+
+const (
+	Number Kind = iota
+	Bool
+)
+
+func (k Kind) String() string {
+	if k == Bool {
+		return "boolean"
+	}
+	return "number"
+}
+
+// value is a Number or a Bool.
+type value struct {
+	num float64
+	b   bool
+}
+
+// node is a compiled subexpression; eval is only called on environments
+// whose variables have been checked against the kinds it was compiled for.
+type node struct {
+	kind Kind
+	eval func(env map[string]value) value
+}
+
+// Expr is a compiled condition.
+type Expr struct {
+	src  string
+	vars map[string]Kind // the variables the expression reads
+	root node
+}
+
+// Compile parses src as a condition over the variables of env. Only the
+// names in env may be used, and each takes the kind of its value there: an
+// int or float64 is a number, a bool a boolean. The expression must be a
+// boolean.
+func Compile(src string, env map[string]any) (*Expr, error) {
+	kinds := make(map[string]Kind, len(env))
+	for name, v := range env {
+		k, ok := kindOf(v)
+		if !ok {
+			return nil, fmt.Errorf("expr: variable %q has unsupported type %T", name, v)
+		}
+		kinds[name] = k
+	}
+	p := &parser{src: src, kinds: kinds, vars: make(map[string]Kind)}
+	if err := p.lex(); err != nil {
+		return nil, fmt.Errorf("expr: %q: %w", src, err)
+	}
+	root, err := p.or()
+	if err == nil && p.peek().kind != tokEOF {
+		err = p.errorf("unexpected %q", p.peek().text)
+	}
+	if err == nil && root.kind != Bool {
+		err = fmt.Errorf("is a number, not a condition")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expr: %q: %w", src, err)
+	}
+	return &Expr{src: src, vars: p.vars, root: root}, nil
+}
+
+// String returns the source of e.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval evaluates e with the variables of env, which must hold every variable
+// e reads with the kind it was compiled for.
+func (e *Expr) Eval(env map[string]any) (bool, error) {
+	vals := make(map[string]value, len(e.vars))
+	for name, want := range e.vars {
+		v, ok := env[name]
+		if !ok {
+			return false, fmt.Errorf("expr: %q: variable %q is not set", e.src, name)
+		}
+		k, ok := kindOf(v)
+		if !ok || k != want {
+			return false, fmt.Errorf("expr: %q: variable %q is a %T, want a %s", e.src, name, v, want)
+		}
+		switch v := v.(type) {
+		case bool:
+			vals[name] = value{b: v}
+		case int:
+			vals[name] = value{num: float64(v)}
+		case float64:
+			vals[name] = value{num: v}
+		}
+	}
+	return e.root.eval(vals).b, nil
+}
+
+func kindOf(v any) (Kind, bool) {
+	switch v.(type) {
+	case bool:
+		return Bool, true
+	case int, float64:
+		return Number, true
+	}
+	return 0, false
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int // byte offset in the source
+}
+
+type parser struct {
+	src   string
+	toks  []token
+	next  int
+	kinds map[string]Kind // variables that may be used
+	vars  map[string]Kind // variables used
+}
+
+// operators lists the operator tokens, longest first so "<=" is not read
+// as "<".
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")"}
+
+func (p *parser) lex() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			p.toks = append(p.toks, token{tokNumber, s[i:j], i})
+			i = j
+		case isIdentStart(c):
+			j := i
+			for j < len(s) && (isIdentStart(s[j]) || s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			p.toks = append(p.toks, token{tokIdent, s[i:j], i})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return fmt.Errorf("at %d: unexpected %q", i+1, s[i:i+1])
+			}
+			p.toks = append(p.toks, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	p.toks = append(p.toks, token{tokEOF, "end of expression", len(s)})
+	return nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.next]
+}
+
+// accept consumes the next token if it is one of the operators ops.
+func (p *parser) accept(ops ...string) (string, bool) {
+	if t := p.peek(); t.kind == tokOp && slices.Contains(ops, t.text) {
+		p.next++
+		return t.text, true
+	}
+	return "", false
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at %d: "+format, append([]any{p.peek().pos + 1}, args...)...)
+}
+
+// want checks that n, the operand of op, has kind k.
+func want(n node, k Kind, op string) error {
+	if n.kind != k {
+		return fmt.Errorf("%s needs a %s, got a %s", op, k, n.kind)
+	}
+	return nil
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return node{}, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.and()
+		if err != nil {
+			return node{}, err
+		}
+		if err := want(left, Bool, "||"); err != nil {
+			return node{}, err
+		}
+		if err := want(right, Bool, "||"); err != nil {
+			return node{}, err
+		}
+		l, r := left.eval, right.eval
+		left = node{Bool, func(env map[string]value) value { return value{b: l(env).b || r(env).b} }}
+	}
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return node{}, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.comparison()
+		if err != nil {
+			return node{}, err
+		}
+		if err := want(left, Bool, "&&"); err != nil {
+			return node{}, err
+		}
+		if err := want(right, Bool, "&&"); err != nil {
+			return node{}, err
+		}
+		l, r := left.eval, right.eval
+		left = node{Bool, func(env map[string]value) value { return value{b: l(env).b && r(env).b} }}
+	}
+}
+
+func (p *parser) comparison() (node, error) {
+	left, err := p.sum()
+	if err != nil {
+		return node{}, err
+	}
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.sum()
+	if err != nil {
+		return node{}, err
+	}
+	if left.kind != right.kind {
+		return node{}, fmt.Errorf("%s compares a %s with a %s", op, left.kind, right.kind)
+	}
+	l, r := left.eval, right.eval
+	if op == "==" || op == "!=" {
+		equal := op == "=="
+		return node{Bool, func(env map[string]value) value { return value{b: (l(env) == r(env)) == equal} }}, nil
+	}
+	if err := want(left, Number, op); err != nil {
+		return node{}, err
+	}
+	var cmp func(a, b float64) bool
+	switch op {
+	case "<":
+		cmp = func(a, b float64) bool { return a < b }
+	case "<=":
+		cmp = func(a, b float64) bool { return a <= b }
+	case ">":
+		cmp = func(a, b float64) bool { return a > b }
+	default:
+		cmp = func(a, b float64) bool { return a >= b }
+	}
+	return node{Bool, func(env map[string]value) value { return value{b: cmp(l(env).num, r(env).num)} }}, nil
+}
+
+func (p *parser) sum() (node, error) {
+	return p.arithmetic(p.product, "+", "-")
+}
+
+func (p *parser) product() (node, error) {
+	return p.arithmetic(p.unary, "*", "/", "%")
+}
+
+// arithmetic parses operands joined by the left-associative operators ops.
+func (p *parser) arithmetic(operand func() (node, error), ops ...string) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return node{}, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return node{}, err
+		}
+		if err := want(left, Number, op); err != nil {
+			return node{}, err
+		}
+		if err := want(right, Number, op); err != nil {
+			return node{}, err
+		}
+		f := arithmeticOps[op]
+		l, r := left.eval, right.eval
+		left = node{Number, func(env map[string]value) value { return value{num: f(l(env).num, r(env).num)} }}
+	}
+}
+
+// arithmeticOps implement the arithmetic operators. Division by zero gives
+// an infinity or NaN, which compare false with everything but themselves.
+var arithmeticOps = map[string]func(a, b float64) float64{
+	"+": func(a, b float64) float64 { return a + b },
+	"-": func(a, b float64) float64 { return a - b },
+	"*": func(a, b float64) float64 { return a * b },
+	"/": func(a, b float64) float64 { return a / b },
+	"%": math.Mod,
+}
+
+func (p *parser) unary() (node, error) {
+	op, ok := p.accept("!", "-")
+	if !ok {
+		return p.primary()
+	}
+	operand, err := p.unary()
+	if err != nil {
+		return node{}, err
+	}
+	f := operand.eval
+	if op == "!" {
+		if err := want(operand, Bool, "!"); err != nil {
+			return node{}, err
+		}
+		return node{Bool, func(env map[string]value) value { return value{b: !f(env).b} }}, nil
+	}
+	if err := want(operand, Number, "-"); err != nil {
+		return node{}, err
+	}
+	return node{Number, func(env map[string]value) value { return value{num: -f(env).num} }}, nil
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return node{}, p.errorf("invalid number %q", t.text)
+		}
+		p.next++
+		return node{Number, func(map[string]value) value { return value{num: n} }}, nil
+	case tokIdent:
+		p.next++
+		switch t.text {
+		case "true", "false":
+			b := t.text == "true"
+			return node{Bool, func(map[string]value) value { return value{b: b} }}, nil
+		}
+		k, ok := p.kinds[t.text]
+		if !ok {
+			p.next--
+			return node{}, p.errorf("unknown variable %q (have %s)", t.text, strings.Join(slices.Sorted(maps.Keys(p.kinds)), ", "))
+		}
+		p.vars[t.text] = k
+		name := t.text
+		return node{k, func(env map[string]value) value { return env[name] }}, nil
+	}
+	if _, ok := p.accept("("); ok {
+		n, err := p.or()
+		if err != nil {
+			return node{}, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return node{}, p.errorf("expected \")\", got %q", p.peek().text)
+		}
+		return n, nil
+	}
+	return node{}, p.errorf("unexpected %q", t.text)
+}
@@ -0,0 +1,88 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+var env = map[string]any{
+	"round":              4,
+	"dissenters":         0,
+	"consensus.score":    8,
+	"consensus.detected": true,
+	"participation":      0.75,
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"consensus.score >= 8 && round >= 4 && dissenters == 0", true},
+		{"consensus.score > 8 || !consensus.detected", false},
+		{"consensus.detected == true", true},
+		{"round - 1 < 3 * (dissenters + 1)", false},
+		{"round % 2 == 0 && -round < 0", true},
+		{"participation >= 0.5 && participation < 1", true},
+		{"round / dissenters > 100", true}, // division by zero gives +Inf
+		{"true || false && false", true},   // && binds tighter
+		{"!(round >= 4)", false},
+		{"round != 4", false},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.src, env)
+		if err != nil {
+			t.Errorf("Compile(%q) error = %v", tt.src, err)
+			continue
+		}
+		got, err := e.Eval(env)
+		if err != nil || got != tt.want {
+			t.Errorf("Eval(%q) = %v, %v, want %v", tt.src, got, err, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := map[string]string{
+		"consensus.scroe >= 8":       `at 1: unknown variable "consensus.scroe"`,
+		"round >= 4 &&":              `at 14: unexpected "end of expression"`,
+		"round = 4":                  `at 7: unexpected "="`,
+		"round >= 4)":                `at 11: unexpected ")"`,
+		"(round >= 4":                `expected ")"`,
+		"round + 1":                  "is a number, not a condition",
+		"round && true":              "&& needs a boolean, got a number",
+		"consensus.detected > 1":     "> compares a boolean with a number",
+		"consensus.detected < false": "< needs a number, got a boolean",
+		"!round":                     "! needs a boolean, got a number",
+		"1.2.3 > 0":                  `invalid number "1.2.3"`,
+		"":                           `unexpected "end of expression"`,
+	}
+	for src, want := range tests {
+		_, err := Compile(src, env)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Compile(%q) error = %v, want %q", src, err, want)
+		}
+	}
+	if _, err := Compile("x", map[string]any{"x": "yes"}); err == nil {
+		t.Error("expected an error for a string variable")
+	}
+}
+
+func TestEvalChecksVariables(t *testing.T) {
+	e, err := Compile("round >= 4", env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Eval(map[string]any{}); err == nil || !strings.Contains(err.Error(), `"round" is not set`) {
+		t.Errorf("Eval() error = %v, want a missing variable", err)
+	}
+	if _, err := e.Eval(map[string]any{"round": true}); err == nil {
+		t.Error("expected an error for a variable of the wrong kind")
+	}
+	if ok, err := e.Eval(map[string]any{"round": 4.0}); err != nil || !ok {
+		t.Errorf("Eval() = %v, %v; ints and floats are both numbers", ok, err)
+	}
+	if e.String() != "round >= 4" {
+		t.Errorf("String() = %q", e.String())
+	}
+}
@@ -0,0 +1,83 @@
+// Package health runs dependency checks for the server's readiness probe and
+// the doctor command.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Check is a named dependency check. Run returns nil when the dependency is
+// usable.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Name     string        `json:"name"`
+	OK       bool          `json:"ok"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Run runs every check concurrently and returns their results in the order
+// given.
+func Run(ctx context.Context, checks []Check) []Result {
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := c.Run(ctx)
+			results[i] = Result{Name: c.Name, OK: err == nil, Duration: time.Since(start)}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Healthy reports whether every result is OK.
+func Healthy(results []Result) bool {
+	for _, r := range results {
+		if !r.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// Cached wraps check so it runs at most once per ttl; calls in between get
+// the last outcome. Use it for probes of remote services that should not be
+// hit on every readiness request.
+func Cached(ttl time.Duration, check func(ctx context.Context) error) func(ctx context.Context) error {
+	c := &cached{ttl: ttl, check: check, now: time.Now}
+	return c.run
+}
+
+type cached struct {
+	ttl   time.Duration
+	check func(ctx context.Context) error
+	now   func() time.Time
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+func (c *cached) run(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now := c.now(); c.checked.IsZero() || now.Sub(c.checked) >= c.ttl {
+		c.err = c.check(ctx)
+		c.checked = now
+	}
+	return c.err
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	results := Run(context.Background(), []Check{
+		{Name: "ok", Run: func(context.Context) error { return nil }},
+		{Name: "down", Run: func(context.Context) error { return errors.New("connection refused") }},
+	})
+	if len(results) != 2 || results[0].Name != "ok" || !results[0].OK {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if results[1].OK || results[1].Error != "connection refused" {
+		t.Errorf("expected the failed check to report its error, got %+v", results[1])
+	}
+	if Healthy(results) || !Healthy(results[:1]) {
+		t.Error("Healthy should only hold when every check passed")
+	}
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	c := &cached{ttl: time.Minute, check: func(context.Context) error {
+		calls++
+		return errors.New("down")
+	}}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	for range 3 {
+		if err := c.run(context.Background()); err == nil {
+			t.Fatal("expected the cached error")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected one probe within the ttl, got %d", calls)
+	}
+	now = now.Add(time.Minute)
+	c.run(context.Background())
+	if calls != 2 {
+		t.Errorf("expected a new probe after the ttl, got %d calls", calls)
+	}
+}
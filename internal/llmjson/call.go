@@ -0,0 +1,242 @@
+package llmjson
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// DefaultAttempts is how many calls StructuredCall makes when a prompt does
+// not say.
+const DefaultAttempts = 3
+
+// Prompt describes a structured-output step: what the model is asked and
+// which answers are usable.
+type Prompt[T any] struct {
+	System string // the task; the JSON format and the reply rules are appended
+	User   string // the input to work on
+	// Schema shows the expected JSON with placeholder values, such as
+	// {"claims": [{"statement": "..."}]}. Empty derives one from T's json
+	// field names.
+	Schema string
+	Notes  string // what the fields mean, placed after the schema
+
+	// Required are the top-level fields an answer must have; a reply
+	// without them is not decoded at all, so zero values can be told from
+	// missing ones.
+	Required []string
+	// Validate rejects a decoded answer the step cannot use, and may
+	// normalize it. The error is shown to the model when it is asked again.
+	// Nil accepts every answer that decodes.
+	Validate func(*T) error
+
+	Attempts int                 // calls before giving up; 0 is DefaultAttempts
+	Options  []openrouter.Option // passed to every call
+
+	// OnReject, if set, is called with every response that was not usable,
+	// reasoning traces removed, and why.
+	OnReject func(attempt int, response string, err error)
+}
+
+// InvalidError is returned by StructuredCall when no attempt gave a usable
+// answer. Err is the reason the last one was rejected.
+type InvalidError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("no valid answer after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *InvalidError) Unwrap() error { return e.Err }
+
+// StructuredCall asks model for a JSON answer to p and decodes it into a T.
+// The reply is read leniently, as Candidates does, and a reply that does not
+// decode or that p.Validate rejects is followed by a repair prompt naming
+// the problem, up to p.Attempts calls. A failed call or a cancelled ctx
+// ends it at once with that error; running out of attempts returns an
+// *InvalidError.
+func StructuredCall[T any](ctx context.Context, llm debate.LLMClient, model string, p Prompt[T]) (*T, error) {
+	schema := p.Schema
+	if schema == "" {
+		schema = Schema(reflect.TypeFor[T]())
+	}
+	system := p.System + " Return ONLY valid JSON in this exact format:\n" + schema + "\n"
+	if p.Notes != "" {
+		system += p.Notes + "\n"
+	}
+	system += "Do NOT include any other text, explanation, or markdown formatting. Return ONLY the JSON object."
+	msgs := []openrouter.Message{{Role: "system", Content: system}, {Role: "user", Content: p.User}}
+
+	attempts := p.Attempts
+	if attempts <= 0 {
+		attempts = DefaultAttempts
+	}
+	lastErr := errors.New("no response")
+	for attempt := range attempts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		call := msgs
+		if attempt > 0 {
+			call = append(msgs[:len(msgs):len(msgs)], openrouter.Message{
+				Role:    "user",
+				Content: fmt.Sprintf("Your previous response was invalid: %v. Return ONLY a JSON object, no markdown, no explanation.", lastErr),
+			})
+		}
+		resp, err := llm.ChatCompletion(ctx, model, call, p.Options...)
+		if err != nil {
+			return nil, err
+		}
+		var raw string
+		if len(resp.Choices) > 0 {
+			raw, _ = debate.StripReasoning(resp.Choices[0].Message.Content)
+		}
+		var out *T
+		if out, lastErr = decode(raw, p.Required, p.Validate); lastErr == nil {
+			return out, nil
+		}
+		if p.OnReject != nil {
+			p.OnReject(attempt+1, raw, lastErr)
+		}
+	}
+	return nil, &InvalidError{Attempts: attempts, Err: lastErr}
+}
+
+// decode returns the first candidate of raw that has the required fields,
+// decodes into a T and passes validate. Otherwise it returns why the most
+// likely candidate was unusable: its decoding or validation error, or that
+// no candidate has the required fields.
+func decode[T any](raw string, required []string, validate func(*T) error) (*T, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, errors.New("empty response")
+	}
+	var firstErr error
+	for _, c := range Candidates(raw) {
+		if len(required) > 0 {
+			var fields map[string]json.RawMessage
+			if json.Unmarshal([]byte(c), &fields) != nil || !hasAll(fields, required) {
+				continue
+			}
+		}
+		out := new(T)
+		err := json.Unmarshal([]byte(c), out)
+		if err != nil && len(required) == 0 {
+			// Without required fields there is no telling the answer from
+			// any other JSON in the reply, so its errors say nothing.
+			continue
+		}
+		if err == nil && validate != nil {
+			err = validate(out)
+		}
+		if err == nil {
+			return out, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if len(required) > 0 {
+		return nil, fmt.Errorf("not a JSON object with %s", quoteList(required))
+	}
+	return nil, errors.New("not valid JSON")
+}
+
+func hasAll(fields map[string]json.RawMessage, keys []string) bool {
+	for _, k := range keys {
+		if _, ok := fields[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteList renders ["a", "b", "c"] as `"a", "b" and "c"`.
+func quoteList(list []string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
+
+// Schema renders an example JSON value of type t with placeholder values,
+// using its json field names, for use as a format hint in prompts: strings
+// are "...", numbers 0, booleans false, and lists and maps hold one element.
+func Schema(t reflect.Type) string {
+	var sb strings.Builder
+	writeSchema(&sb, t, 0)
+	return sb.String()
+}
+
+// maxSchemaDepth stops recursive types from recursing forever.
+const maxSchemaDepth = 6
+
+func writeSchema(sb *strings.Builder, t reflect.Type, depth int) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if depth > maxSchemaDepth {
+		sb.WriteString("null")
+		return
+	}
+	// Types that marshal themselves as text, such as enums, are strings.
+	if text := reflect.TypeFor[encoding.TextMarshaler](); t.Implements(text) || reflect.PointerTo(t).Implements(text) {
+		sb.WriteString(`"..."`)
+		return
+	}
+	switch t.Kind() {
+	case reflect.String:
+		sb.WriteString(`"..."`)
+	case reflect.Bool:
+		sb.WriteString("false")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		sb.WriteString("0")
+	case reflect.Slice, reflect.Array:
+		sb.WriteString("[")
+		writeSchema(sb, t.Elem(), depth+1)
+		sb.WriteString("]")
+	case reflect.Map:
+		sb.WriteString(`{"...": `)
+		writeSchema(sb, t.Elem(), depth+1)
+		sb.WriteString("}")
+	case reflect.Struct:
+		sb.WriteString("{")
+		first := true
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if !first {
+				sb.WriteString(", ")
+			}
+			first = false
+			fmt.Fprintf(sb, "%q: ", name)
+			writeSchema(sb, f.Type, depth+1)
+		}
+		sb.WriteString("}")
+	default:
+		sb.WriteString("null")
+	}
+}
@@ -0,0 +1,155 @@
+package llmjson
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+type mockLLM struct {
+	responses []string
+	err       error
+	calls     int
+	msgs      [][]openrouter.Message
+}
+
+func (m *mockLLM) ChatCompletion(_ context.Context, _ string, msgs []openrouter.Message, _ ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.msgs = append(m.msgs, msgs)
+	resp := m.responses[min(m.calls, len(m.responses)-1)]
+	m.calls++
+	return &openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: resp}}},
+	}, nil
+}
+
+type answer struct {
+	Items []string `json:"items"`
+	Count int      `json:"count"`
+}
+
+func TestStructuredCallDecodes(t *testing.T) {
+	llm := &mockLLM{responses: []string{"<think>hmm</think>Sure:\n```json\n{\"items\": [\"a\", \"b\",], \"count\": 2}\n```"}}
+	got, err := StructuredCall(context.Background(), llm, "m", Prompt[answer]{
+		System: "You count things.",
+		Schema: `{"items": ["..."], "count": 0}`,
+		Notes:  `"count" is the number of items.`,
+		User:   "a b",
+	})
+	if err != nil || got.Count != 2 || len(got.Items) != 2 {
+		t.Fatalf("StructuredCall() = %+v, %v", got, err)
+	}
+	system := llm.msgs[0][0].Content
+	for _, want := range []string{"You count things. Return ONLY valid JSON", `{"items": ["..."], "count": 0}`, `"count" is the number of items.`, "Return ONLY the JSON object."} {
+		if !strings.Contains(system, want) {
+			t.Errorf("system prompt misses %q:\n%s", want, system)
+		}
+	}
+	if llm.msgs[0][1].Content != "a b" {
+		t.Errorf("user message = %q", llm.msgs[0][1].Content)
+	}
+}
+
+func TestStructuredCallRepairsInvalidAnswers(t *testing.T) {
+	llm := &mockLLM{responses: []string{
+		`I think there are two.`,
+		`{"items": ["a"], "count": 2}`,
+		`{"items": ["a", "b"], "count": 2}`,
+	}}
+	var rejected []string
+	got, err := StructuredCall(context.Background(), llm, "m", Prompt[answer]{
+		System:   "You count things.",
+		Required: []string{"items", "count"},
+		Validate: func(a *answer) error {
+			if a.Count != len(a.Items) {
+				return errors.New("count does not match items")
+			}
+			return nil
+		},
+		OnReject: func(attempt int, response string, err error) {
+			rejected = append(rejected, err.Error())
+		},
+	})
+	if err != nil || got.Count != 2 {
+		t.Fatalf("StructuredCall() = %+v, %v", got, err)
+	}
+	if llm.calls != 3 {
+		t.Errorf("calls = %d, want 3", llm.calls)
+	}
+	wantRejected := []string{`not a JSON object with "items" and "count"`, "count does not match items"}
+	if !reflect.DeepEqual(rejected, wantRejected) {
+		t.Errorf("rejections = %q, want %q", rejected, wantRejected)
+	}
+	if retry := llm.msgs[2]; len(retry) != 3 || !strings.Contains(retry[2].Content, "invalid: count does not match items") {
+		t.Errorf("repair prompt should name the problem, got %+v", retry)
+	}
+	if len(llm.msgs[0]) != 2 {
+		t.Errorf("first call should carry no repair prompt, got %+v", llm.msgs[0])
+	}
+}
+
+func TestStructuredCallGivesUp(t *testing.T) {
+	llm := &mockLLM{responses: []string{`{"items": "many"}`}}
+	_, err := StructuredCall(context.Background(), llm, "m", Prompt[answer]{Required: []string{"items"}, Attempts: 2})
+	var invalid *InvalidError
+	if !errors.As(err, &invalid) || invalid.Attempts != 2 {
+		t.Fatalf("StructuredCall() error = %v, want an InvalidError after 2 attempts", err)
+	}
+	if llm.calls != 2 || !strings.Contains(err.Error(), "cannot unmarshal") {
+		t.Errorf("got %v after %d calls", err, llm.calls)
+	}
+
+	llm = &mockLLM{responses: []string{""}}
+	if _, err = StructuredCall(context.Background(), llm, "m", Prompt[answer]{}); !errors.As(err, &invalid) || llm.calls != DefaultAttempts || !strings.Contains(err.Error(), "empty response") {
+		t.Errorf("StructuredCall() = %v after %d calls, want empty response after %d", err, llm.calls, DefaultAttempts)
+	}
+}
+
+func TestStructuredCallStopsOnErrors(t *testing.T) {
+	boom := errors.New("boom")
+	if _, err := StructuredCall(context.Background(), &mockLLM{err: boom}, "m", Prompt[answer]{}); !errors.Is(err, boom) {
+		t.Errorf("StructuredCall() error = %v, want the LLM error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	llm := &mockLLM{responses: []string{"{}"}}
+	if _, err := StructuredCall(ctx, llm, "m", Prompt[answer]{}); !errors.Is(err, context.Canceled) || llm.calls != 0 {
+		t.Errorf("StructuredCall() = %v after %d calls, want context.Canceled before any call", err, llm.calls)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	type level string
+	type inner struct {
+		Name string `json:"name,omitempty"`
+		Tags map[string]float64
+	}
+	type sample struct {
+		ID      int     `json:"id"`
+		OK      bool    `json:"ok"`
+		Level   *level  `json:"level"`
+		Inner   []inner `json:"inner"`
+		Skipped string  `json:"-"`
+		private int
+		Seen    []*string `json:"seen"`
+	}
+	want := `{"id": 0, "ok": false, "level": "...", "inner": [{"name": "...", "Tags": {"...": 0}}], "seen": ["..."]}`
+	if got := Schema(reflect.TypeFor[sample]()); got != want {
+		t.Errorf("Schema() =\n%s\nwant\n%s", got, want)
+	}
+
+	llm := &mockLLM{responses: []string{`{"items": [], "count": 0}`}}
+	if _, err := StructuredCall(context.Background(), llm, "m", Prompt[answer]{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(llm.msgs[0][0].Content, `{"items": ["..."], "count": 0}`) {
+		t.Errorf("system prompt should derive the schema from the type, got %q", llm.msgs[0][0].Content)
+	}
+}
@@ -0,0 +1,313 @@
+// Package llmjson extracts JSON objects from LLM output. Models asked for
+// JSON often wrap it in prose or code fences, or return something close to
+// JSON: trailing commas, single or smart quotes, Python literals, unquoted
+// keys, raw newlines in strings, or an object cut off by the token limit.
+// Candidates finds what looks like the object and repairs it, and
+// StructuredCall wraps it in the ask, decode, validate and retry loop, so
+// every structured-output step parses replies the same way.
+package llmjson
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// fenceRe matches a fenced code block; its first group is the content.
+var fenceRe = regexp.MustCompile("(?s)```[a-zA-Z]*[ \\t]*\\n?(.*?)\\n?```")
+
+// Candidates returns the texts in raw that may hold a JSON object, most
+// likely first: raw itself, the content of each code fence, each top-level
+// object found in the text, and the span from the first "{" to the last
+// "}" or to the end. Those that are not valid JSON follow again, repaired
+// with Repair. Callers decode each in turn and keep the first that has the
+// fields they need.
+func Candidates(raw string) []string {
+	var found []string
+	seen := make(map[string]bool)
+	add := func(s string) {
+		if s = strings.TrimSpace(s); s != "" && !seen[s] {
+			seen[s] = true
+			found = append(found, s)
+		}
+	}
+
+	add(raw)
+	for _, m := range fenceRe.FindAllStringSubmatch(raw, -1) {
+		add(m[1])
+	}
+	// Fence markers on lines of their own, including nested or unclosed
+	// fences, are dropped wholesale.
+	add(stripFenceLines(raw))
+	for _, obj := range objects(raw) {
+		add(obj)
+	}
+	if start := strings.IndexByte(raw, '{'); start >= 0 {
+		if end := strings.LastIndex(raw, "}"); end > start {
+			add(raw[start : end+1])
+		}
+		add(raw[start:])
+	}
+
+	out := make([]string, 0, 2*len(found))
+	var broken []string
+	for _, c := range found {
+		if json.Valid([]byte(c)) {
+			out = append(out, c)
+		} else {
+			broken = append(broken, c)
+		}
+	}
+	for _, c := range broken {
+		if fixed := Repair(c); fixed != c && !seen[fixed] && json.Valid([]byte(fixed)) {
+			seen[fixed] = true
+			out = append(out, fixed)
+		}
+	}
+	return out
+}
+
+// Decode unmarshals the first candidate of raw that decodes into out, and
+// reports whether one did. Use a loop over Candidates instead when a decoded
+// value can still be unusable, such as missing a required field.
+func Decode(raw string, out any) bool {
+	for _, c := range Candidates(raw) {
+		if json.Unmarshal([]byte(c), out) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// stripFenceLines removes every line of s that starts with a code fence.
+func stripFenceLines(s string) string {
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "```") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// objects returns each top-level {...} span of s, skipping braces inside
+// double-quoted strings. An object still open at the end of s runs to the
+// end.
+func objects(s string) []string {
+	var out []string
+	depth, start := 0, -1
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = depth > 0
+		case c == '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+			if depth == 0 {
+				out = append(out, s[start:i+1])
+			}
+		}
+	}
+	if depth > 0 {
+		out = append(out, s[start:])
+	}
+	return out
+}
+
+// Object member positions tracked by Repair for each open object.
+const (
+	wantKey = iota
+	wantColon
+	wantValue
+	afterValue
+)
+
+// frame is an open object or array.
+type frame struct {
+	open  byte // '{' or '['
+	state int  // for objects, where the next member token goes
+}
+
+// Repair rewrites near-JSON into JSON: single- and smart-quoted strings
+// become double-quoted, raw control characters in strings are escaped,
+// trailing commas are dropped, True, False and None become true, false and
+// null, bare object keys are quoted, and a truncated document is closed,
+// with a dangling key or colon completed with null. Valid JSON is returned
+// unchanged, and text it cannot make sense of is passed through, so the
+// result still has to be checked with json.Valid.
+func Repair(s string) string {
+	if json.Valid([]byte(s)) {
+		return s
+	}
+	var out []byte
+	var stack []frame
+	var quote rune // closing delimiter of the string being read, 0 outside strings
+	escaped := false
+
+	// value records that a value or key token ended.
+	value := func() {
+		if n := len(stack); n > 0 && stack[n-1].open == '{' {
+			switch stack[n-1].state {
+			case wantKey:
+				stack[n-1].state = wantColon
+			case wantValue:
+				stack[n-1].state = afterValue
+			}
+		}
+	}
+	trimComma := func() {
+		out = []byte(strings.TrimRightFunc(string(out), unicode.IsSpace))
+		if n := len(out); n > 0 && out[n-1] == ',' {
+			out = out[:n-1]
+		}
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+				if r == '\'' {
+					// \' is not a JSON escape; the quote needs none.
+					out = append(out[:len(out)-1], '\'')
+					continue
+				}
+				out = append(out, string(r)...)
+			case r == '\\':
+				escaped = true
+				out = append(out, '\\')
+			case r == quote:
+				quote = 0
+				out = append(out, '"')
+				value()
+			case r == '"':
+				out = append(out, '\\', '"')
+			case r == '\n':
+				out = append(out, '\\', 'n')
+			case r == '\r':
+				out = append(out, '\\', 'r')
+			case r == '\t':
+				out = append(out, '\\', 't')
+			default:
+				out = append(out, string(r)...)
+			}
+			continue
+		}
+
+		switch {
+		case r == '"' || r == '\'':
+			quote = r
+			out = append(out, '"')
+		case r == '“':
+			quote = '”'
+			out = append(out, '"')
+		case r == '‘':
+			quote = '’'
+			out = append(out, '"')
+		case r == '{' || r == '[':
+			stack = append(stack, frame{open: byte(r)})
+			out = append(out, byte(r))
+		case r == '}' || r == ']':
+			trimComma()
+			if n := len(stack); n > 0 {
+				// A mismatched bracket closes what is actually open.
+				r = rune(closer(stack[n-1].open))
+				stack = stack[:n-1]
+			}
+			out = append(out, byte(r))
+			value()
+		case r == ':':
+			if n := len(stack); n > 0 && stack[n-1].open == '{' {
+				stack[n-1].state = wantValue
+			}
+			out = append(out, ':')
+		case r == ',':
+			if n := len(stack); n > 0 && stack[n-1].open == '{' {
+				stack[n-1].state = wantKey
+			}
+			out = append(out, ',')
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			word := string(runes[i:j])
+			i = j - 1
+			switch n := len(stack); {
+			case n > 0 && stack[n-1].open == '{' && stack[n-1].state == wantKey:
+				out = append(out, '"')
+				out = append(out, word...)
+				out = append(out, '"')
+			case word == "True":
+				out = append(out, "true"...)
+			case word == "False":
+				out = append(out, "false"...)
+			case word == "None":
+				out = append(out, "null"...)
+			default:
+				out = append(out, word...)
+			}
+			value()
+		default:
+			out = append(out, string(r)...)
+		}
+	}
+
+	// Close whatever the text left open.
+	if quote != 0 {
+		if escaped {
+			out = out[:len(out)-1]
+		}
+		out = append(out, '"')
+		value()
+	}
+	for n := len(stack); n > 0; n-- {
+		top := stack[n-1]
+		trimComma()
+		if top.open == '{' {
+			switch top.state {
+			case wantColon:
+				out = append(out, ":null"...)
+			case wantValue:
+				out = append(out, "null"...)
+			}
+		}
+		out = append(out, closer(top.open))
+		stack = stack[:n-1]
+		value()
+	}
+	return string(out)
+}
+
+// closer returns the bracket that closes open.
+func closer(open byte) byte {
+	if open == '{' {
+		return '}'
+	}
+	return ']'
+}
+
+// isWordRune reports whether r can be part of a bare token: a number, a
+// literal or an unquoted key.
+func isWordRune(r rune) bool {
+	return r == '_' || r == '-' || r == '+' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,139 @@
+package llmjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// verdict mirrors the consensus judge's reply format.
+type verdict struct {
+	Detected   *bool    `json:"consensus_detected"`
+	Position   *string  `json:"consensus_position"`
+	Score      *int     `json:"agreement_score"`
+	Dissenters []string `json:"dissenting_agents"`
+}
+
+// TestJudgeCorpus parses every malformed judge output in testdata/judge.
+// Each file starts with a line "expect: detected=<bool> score=<int>"
+// followed by the raw reply.
+func TestJudgeCorpus(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "judge", "*.txt"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no corpus files: %v", err)
+	}
+	parsed := 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		header, raw, _ := strings.Cut(string(data), "\n")
+		var wantDetected bool
+		var wantScore int
+		if _, err := fmt.Sscanf(header, "expect: detected=%t score=%d", &wantDetected, &wantScore); err != nil {
+			t.Fatalf("%s: bad header %q: %v", path, header, err)
+		}
+
+		var got *verdict
+		for _, c := range Candidates(raw) {
+			var v verdict
+			if json.Unmarshal([]byte(c), &v) == nil && v.Detected != nil && v.Score != nil {
+				got = &v
+				break
+			}
+		}
+		name := filepath.Base(path)
+		switch {
+		case got == nil:
+			t.Errorf("%s: no verdict found in %q", name, raw)
+		case *got.Detected != wantDetected || *got.Score != wantScore:
+			t.Errorf("%s: got detected=%t score=%d, want detected=%t score=%d", name, *got.Detected, *got.Score, wantDetected, wantScore)
+		default:
+			parsed++
+		}
+	}
+	t.Logf("parsed %d of %d judge outputs", parsed, len(files))
+}
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"valid", `{"a": [1, 2]}`, `{"a": [1, 2]}`},
+		{"trailing commas", `{"a": [1, 2,], "b": 3,}`, `{"a": [1, 2], "b": 3}`},
+		{"single quotes", `{'a': 'it is'}`, `{"a": "it is"}`},
+		{"escaped single quote", `{'a': 'don\'t'}`, `{"a": "don't"}`},
+		{"double quote in single", `{'a': 'say "hi"'}`, `{"a": "say \"hi\""}`},
+		{"apostrophe in double", `{"a": "don't",}`, `{"a": "don't"}`},
+		{"smart quotes", `{“a”: “b”}`, `{"a": "b"}`},
+		{"python literals", `{"a": True, "b": False, "c": None}`, `{"a": true, "b": false, "c": null}`},
+		{"bare keys", `{a: 1, b_c: "x"}`, `{"a": 1, "b_c": "x"}`},
+		{"raw newline", "{\"a\": \"x\ny\"}", `{"a": "x\ny"}`},
+		{"truncated string", `{"a": "x`, `{"a": "x"}`},
+		{"truncated after key", `{"a": 1, "b"`, `{"a": 1, "b":null}`},
+		{"truncated after colon", `{"a": 1, "b": `, `{"a": 1, "b":null}`},
+		{"truncated after comma", `{"a": [1, 2, `, `{"a": [1, 2]}`},
+		{"truncated escape", `{"a": "x\`, `{"a": "x"}`},
+		{"mismatched bracket", `{"a": [1}`, `{"a": [1]}`},
+		{"negative number", `{"a": -1.5e3,}`, `{"a": -1.5e3}`},
+	}
+	for _, tt := range tests {
+		got := Repair(tt.in)
+		if got != tt.want {
+			t.Errorf("%s: Repair(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+		if !json.Valid([]byte(got)) {
+			t.Errorf("%s: Repair(%q) = %q is not valid JSON", tt.name, tt.in, got)
+		}
+	}
+}
+
+func TestCandidatesPreferValidJSON(t *testing.T) {
+	raw := "Draft: {\"a\": 1,}\n```json\n{\"a\": 2}\n```"
+	c := Candidates(raw)
+	if len(c) == 0 || c[0] != `{"a": 2}` {
+		t.Fatalf("Candidates() = %q, want the valid fenced object first", c)
+	}
+	var out struct{ A int }
+	if !Decode(raw, &out) || out.A != 2 {
+		t.Errorf("Decode() = %+v", out)
+	}
+	if Decode("no json here", &out) {
+		t.Error("Decode() succeeded on prose")
+	}
+}
+
+func TestCandidatesFindEveryObject(t *testing.T) {
+	raw := `first {"a": 1} then {"b": "}"} and {"c": 3`
+	got := strings.Join(Candidates(raw), "\n")
+	for _, want := range []string{`{"a": 1}`, `{"b": "}"}`, `{"c": 3}`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Candidates(%q) misses %s; got\n%s", raw, want, got)
+		}
+	}
+}
+
+func FuzzRepair(f *testing.F) {
+	files, _ := filepath.Glob(filepath.Join("testdata", "judge", "*.txt"))
+	for _, path := range files {
+		data, _ := os.ReadFile(path)
+		_, raw, _ := strings.Cut(string(data), "\n")
+		f.Add(raw)
+	}
+	f.Add(`{'a': [1, {b: None,`)
+	f.Add("```json\n{\"a\": \"\\")
+	f.Fuzz(func(t *testing.T, raw string) {
+		if json.Valid([]byte(raw)) && Repair(raw) != raw {
+			t.Errorf("Repair changed valid JSON %q", raw)
+		}
+		for _, c := range Candidates(raw) {
+			if !json.Valid([]byte(c)) {
+				t.Errorf("Candidates(%q) returned invalid JSON %q", raw, c)
+			}
+		}
+	})
+}
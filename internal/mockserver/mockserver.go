@@ -0,0 +1,172 @@
+// Package mockserver serves a fake OpenRouter API, so full debates can run
+// offline in development and CI. It lists the built-in free models and
+// answers chat completions with synthesized replies, or with the turns of a
+// saved transcript replayed in order.
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// defaultConsensusAfter is how many debate turns the synthesized judge waits
+// for before it detects a consensus.
+const defaultConsensusAfter = 15
+
+// speakerRe extracts the speaking agent from a debater's or the Tenth Man's
+// system prompt.
+var speakerRe = regexp.MustCompile(`^You are (.+?)[,.] `)
+
+// Server is an http.Handler that imitates the OpenRouter API. Paths are
+// accepted with or without the /api/v1 prefix.
+type Server struct {
+	mu             sync.Mutex
+	replay         map[string][]string // remaining turns to replay, by agent name
+	position       string
+	consensusAfter int
+	spoken         map[string]int // synthesized turns per agent
+	calls          int
+}
+
+// New returns a Server that synthesizes every reply.
+func New() *Server {
+	return &Server{
+		replay:         make(map[string][]string),
+		position:       "The group agrees on a cautious, staged approach.",
+		consensusAfter: defaultConsensusAfter,
+		spoken:         make(map[string]int),
+	}
+}
+
+// Replay makes each agent of t speak its recorded turns again, in order.
+// Agents that run out of turns, or that t does not know, get synthesized
+// replies. The judge reports t's consensus position, if it has one.
+func (s *Server) Replay(t *debate.Transcript) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, turn := range t.Turns {
+		content := turn.Content
+		if turn.InReplyTo > 0 {
+			content = fmt.Sprintf("Re: #%d\n%s", turn.InReplyTo, content)
+		}
+		if turn.Confidence != nil {
+			content += fmt.Sprintf("\nCONFIDENCE: %d", *turn.Confidence)
+		}
+		s.replay[turn.Agent.Name] = append(s.replay[turn.Agent.Name], content)
+	}
+	if t.ConsensusPosition != "" {
+		s.position = t.ConsensusPosition
+	}
+}
+
+// SetConsensusAfter makes the judge detect a consensus once the transcript
+// it is shown has at least turns turns. Values below 1 are ignored.
+func (s *Server) SetConsensusAfter(turns int) {
+	if turns > 0 {
+		s.consensusAfter = turns
+	}
+}
+
+// Calls returns how many chat completions the server has answered.
+func (s *Server) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	switch {
+	case path == "/models" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, openrouter.ModelsResponse{Data: models.DefaultFreeModels()})
+	case path == "/chat/completions" && r.Method == http.MethodPost:
+		s.handleChat(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req openrouter.ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	content := s.reply(req.Messages)
+	prompt := 0
+	for _, m := range req.Messages {
+		prompt += len(strings.Fields(m.Content))
+	}
+	completion := len(strings.Fields(content))
+	writeJSON(w, http.StatusOK, openrouter.ChatResponse{
+		Choices: []openrouter.Choice{{Message: openrouter.Message{Role: "assistant", Content: content}}},
+		Usage:   &openrouter.Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion},
+	})
+}
+
+// reply answers a conversation according to who its system prompt asks the
+// model to be.
+func (s *Server) reply(msgs []openrouter.Message) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	system := msgs[0].Content
+	switch {
+	case strings.HasPrefix(system, "You are a consensus judge"):
+		return s.verdict(msgs[len(msgs)-1].Content)
+	case strings.HasPrefix(system, "You are a debate summarizer"):
+		return "Every agent restated its position on the topic."
+	case strings.HasPrefix(system, "You compare two consensus positions"):
+		return "CHANGED: no\nThe challenge was answered and the position stands as stated."
+	case strings.HasPrefix(system, "You are an executive summary writer"):
+		return fmt.Sprintf("- The group converged on: %s\n- The Tenth Man argued the risks were understated.\n- Review the objections before acting.", s.position)
+	case strings.HasPrefix(system, "You are an operations analyst"):
+		return `{"actions": [{"description": "Pilot the approach with one team", "rationale": "limits the blast radius", "raised_by": []}], "open_questions": ["What does success look like?"], "follow_up_research": []}`
+	case strings.HasPrefix(system, "You are a claims analyst"):
+		return fmt.Sprintf(`{"claims": [{"statement": %q, "supporting_agents": [], "opposing_agents": [], "tenth_man_rebuttals": []}]}`, s.position)
+	case strings.Contains(system, "The debate has ended and you still dissent"):
+		return "The objections raised were never answered with evidence."
+	}
+	m := speakerRe.FindStringSubmatch(system)
+	if m == nil {
+		return "This is a synthesized answer from the mock OpenRouter server."
+	}
+	name := m[1]
+	if turns := s.replay[name]; len(turns) > 0 {
+		s.replay[name] = turns[1:]
+		return turns[0]
+	}
+	s.spoken[name]++
+	return fmt.Sprintf("%s makes point %d on the topic.\nCONFIDENCE: %d", name, s.spoken[name], min(50+5*s.spoken[name], 95))
+}
+
+// verdict judges a transcript given as one "Name: content" line per turn.
+func (s *Server) verdict(transcript string) string {
+	turns := strings.Count(strings.TrimSpace(transcript), "\n") + 1
+	result := debate.ConsensusResult{Score: 4, Dissenters: []string{}}
+	if turns >= s.consensusAfter {
+		result = debate.ConsensusResult{Detected: true, Position: s.position, Score: 8, Dissenters: []string{}}
+	}
+	data, _ := json.Marshal(result)
+	return string(data)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with an OpenRouter error payload.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]openrouter.APIError{"error": {Code: status, Message: message}})
+}
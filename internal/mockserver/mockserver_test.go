@@ -0,0 +1,95 @@
+package mockserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+)
+
+func TestFullDebateOffline(t *testing.T) {
+	mock := New()
+	mock.SetConsensusAfter(6)
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	client := openrouter.NewClientWithBaseURL("test-key", server.URL+"/api/v1")
+	available, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := runner.Job{Topic: "Offline topic", Agents: 3, MinRounds: 2, MaxRounds: 5}
+	outcome, err := runner.Run(context.Background(), client, models.NewRegistry(available), t.TempDir(), job, runner.Hooks{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := outcome.Result.Verdict(); got != debate.VerdictUpheld {
+		t.Errorf("expected the synthesized consensus to be upheld, got %s", got)
+	}
+	if rounds := outcome.Result.Transcript.Rounds; rounds != 5 {
+		t.Errorf("expected 2 free rounds and 3 Tenth Man rounds, got %d", rounds)
+	}
+	last := outcome.Result.Transcript.Turns[len(outcome.Result.Transcript.Turns)-1]
+	if last.Agent.Role != "tenth-man" || last.Confidence == nil {
+		t.Errorf("expected a synthesized Tenth Man turn with confidence, got %+v", last)
+	}
+	if len(outcome.Claims) != 1 || outcome.Tokens == 0 {
+		t.Errorf("expected a claim and token usage, got %v and %d tokens", outcome.Claims, outcome.Tokens)
+	}
+	if len(outcome.Result.Transcript.Summary) != 3 {
+		t.Errorf("expected a 3-bullet executive summary, got %q", outcome.Result.Transcript.Summary)
+	}
+	if outcome.Actions == nil || len(outcome.Actions.Actions) != 1 {
+		t.Errorf("expected one action item, got %+v", outcome.Actions)
+	}
+	if mock.Calls() == 0 {
+		t.Error("expected the mock to count its calls")
+	}
+}
+
+func TestReplayTranscript(t *testing.T) {
+	confidence := 80
+	mock := New()
+	mock.Replay(&debate.Transcript{
+		Turns: []debate.Turn{
+			{ID: 1, Agent: debate.Agent{Name: "Alice"}, Content: "First recorded point", Confidence: &confidence},
+			{ID: 2, Agent: debate.Agent{Name: "Alice"}, Content: "Second recorded point", InReplyTo: 1},
+		},
+		ConsensusPosition: "Recorded position",
+	})
+	server := httptest.NewServer(mock)
+	defer server.Close()
+	client := openrouter.NewClientWithBaseURL("test-key", server.URL)
+
+	ask := func(system string) string {
+		resp, err := client.ChatCompletion(context.Background(), "m", []openrouter.Message{{Role: "system", Content: system}, {Role: "user", Content: "Alice: x"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Choices[0].Message.Content
+	}
+	alice := "You are Alice, a debate participant. The topic is: t."
+	for _, want := range []string{"First recorded point\nCONFIDENCE: 80", "Re: #1\nSecond recorded point", "Alice makes point 1 on the topic.\nCONFIDENCE: 55"} {
+		if got := ask(alice); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+	mock.SetConsensusAfter(1)
+	if got := ask("You are a consensus judge."); got != `{"consensus_detected":true,"consensus_position":"Recorded position","agreement_score":8,"dissenting_agents":[]}` {
+		t.Errorf("expected the recorded position, got %s", got)
+	}
+
+	resp, err := http.Get(server.URL + "/nope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown paths, got %d", resp.StatusCode)
+	}
+}
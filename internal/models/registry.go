@@ -29,6 +29,29 @@ func (r *Registry) FreeModels() []openrouter.Model {
 	return r.free
 }
 
+// Vision returns a registry holding only the free models that accept images.
+func (r *Registry) Vision() *Registry {
+	var vision []openrouter.Model
+	for _, m := range r.free {
+		if m.AcceptsImages() {
+			vision = append(vision, m)
+		}
+	}
+	return &Registry{free: vision}
+}
+
+// ContextWindows returns the context window, in tokens, of every free model
+// whose window is known, by model ID.
+func (r *Registry) ContextWindows() map[string]int {
+	windows := make(map[string]int)
+	for _, m := range r.free {
+		if m.ContextLength > 0 {
+			windows[m.ID] = m.ContextLength
+		}
+	}
+	return windows
+}
+
 // SelectModels returns n models from the free list, cycling if n > available.
 func (r *Registry) SelectModels(n int) []openrouter.Model {
 	if len(r.free) == 0 {
@@ -49,5 +72,6 @@ func DefaultFreeModels() []openrouter.Model {
 		{ID: "nvidia/nemotron-nano-9b-v2:free", Name: "Nemotron Nano 9B V2", Pricing: &openrouter.Pricing{Prompt: "0", Completion: "0"}},
 		{ID: "qwen/qwen3-coder:free", Name: "Qwen3 Coder 480B A35B", Pricing: &openrouter.Pricing{Prompt: "0", Completion: "0"}},
 		{ID: "openai/gpt-oss-120b:free", Name: "GPT OSS 120B", Pricing: &openrouter.Pricing{Prompt: "0", Completion: "0"}},
+		{ID: "google/gemma-3-27b-it:free", Name: "Gemma 3 27B", Pricing: &openrouter.Pricing{Prompt: "0", Completion: "0"}, Architecture: &openrouter.Architecture{InputModalities: []string{"text", "image"}}},
 	}
 }
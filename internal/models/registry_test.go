@@ -1,6 +1,7 @@
 package models
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
@@ -83,3 +84,85 @@ func TestDefaultFreeModelsNonEmpty(t *testing.T) {
 		t.Fatal("expected non-empty default free models list")
 	}
 }
+
+func TestVisionKeepsImageModels(t *testing.T) {
+	image := &openrouter.Architecture{InputModalities: []string{"text", "image"}}
+	models := []openrouter.Model{
+		{ID: "text", Pricing: &openrouter.Pricing{Prompt: "0", Completion: "0"}, Architecture: &openrouter.Architecture{InputModalities: []string{"text"}}},
+		{ID: "vision", Pricing: &openrouter.Pricing{Prompt: "0", Completion: "0"}, Architecture: image},
+		{ID: "unknown", Pricing: &openrouter.Pricing{Prompt: "0", Completion: "0"}},
+		{ID: "paid-vision", Pricing: &openrouter.Pricing{Prompt: "1", Completion: "1"}, Architecture: image},
+	}
+
+	vision := NewRegistry(models).Vision().FreeModels()
+	if len(vision) != 1 || vision[0].ID != "vision" {
+		t.Errorf("expected only the free vision model, got %v", vision)
+	}
+	if len(NewRegistry(DefaultFreeModels()).Vision().FreeModels()) == 0 {
+		t.Error("expected a vision model among the defaults")
+	}
+}
+
+func TestContextWindows(t *testing.T) {
+	free := &openrouter.Pricing{Prompt: "0", Completion: "0"}
+	models := []openrouter.Model{
+		{ID: "long", Pricing: free, ContextLength: 131072},
+		{ID: "unknown", Pricing: free},
+		{ID: "paid", Pricing: &openrouter.Pricing{Prompt: "1", Completion: "1"}, ContextLength: 8192},
+	}
+
+	windows := NewRegistry(models).ContextWindows()
+	if len(windows) != 1 || windows["long"] != 131072 {
+		t.Errorf("expected only the free model with a known window, got %v", windows)
+	}
+}
+
+func TestAssignRoutesModelsByRole(t *testing.T) {
+	free := &openrouter.Pricing{Prompt: "0", Completion: "0"}
+	r := NewRegistry([]openrouter.Model{
+		{ID: "thinker", Pricing: free, ContextLength: 32000, SupportedParameters: []string{"reasoning"}},
+		{ID: "quick-a", Pricing: free, ContextLength: 8000},
+		{ID: "json", Pricing: free, ContextLength: 64000, SupportedParameters: []string{"response_format"}},
+		{ID: "long-thinker", Pricing: free, ContextLength: 128000, SupportedParameters: []string{"include_reasoning"}},
+		{ID: "quick-b", Pricing: free, ContextLength: 16000},
+	})
+
+	a, ok := r.Assign(4)
+	if !ok {
+		t.Fatal("expected an assignment")
+	}
+	var debaters []string
+	for _, m := range a.Debaters {
+		debaters = append(debaters, m.ID)
+	}
+	if want := []string{"quick-a", "json", "quick-b", "thinker"}; !slices.Equal(debaters, want) {
+		t.Errorf("debaters = %v, want the fast models first: %v", debaters, want)
+	}
+	if a.TenthMan.ID != "long-thinker" {
+		t.Errorf("tenth man = %s, want the reasoning model with the longest context", a.TenthMan.ID)
+	}
+	if a.Judge.ID != "json" {
+		t.Errorf("judge = %s, want the JSON-mode model", a.Judge.ID)
+	}
+}
+
+func TestAssignWithoutMetadataKeepsRegistryOrder(t *testing.T) {
+	r := NewRegistry(DefaultFreeModels()[:5])
+	a, ok := r.Assign(3)
+	if !ok {
+		t.Fatal("expected an assignment")
+	}
+	all := r.FreeModels()
+	if a.Debaters[0].ID != all[0].ID || a.Debaters[2].ID != all[2].ID {
+		t.Errorf("debaters %v should follow registry order", a.Debaters)
+	}
+	if a.TenthMan.ID != all[3].ID {
+		t.Errorf("tenth man = %s, want the first model no debater uses (%s)", a.TenthMan.ID, all[3].ID)
+	}
+	if a.Judge.ID != all[0].ID {
+		t.Errorf("judge = %s, want %s", a.Judge.ID, all[0].ID)
+	}
+	if _, ok := NewRegistry(nil).Assign(3); ok {
+		t.Error("expected no assignment from an empty registry")
+	}
+}
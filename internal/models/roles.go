@@ -0,0 +1,117 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// Role is a part a model plays in a debate.
+type Role string
+
+// Roles models are assigned to.
+const (
+	RoleDebater  Role = "debater"
+	RoleTenthMan Role = "tenth-man"
+	RoleJudge    Role = "judge"
+)
+
+// Requirements are the capabilities a role wants from its model. Models
+// meeting more of them are preferred; none is strictly required, so a
+// registry without capability metadata still yields models.
+type Requirements struct {
+	JSONMode    bool // structured output, so verdicts parse
+	Reasoning   bool // thinks before answering
+	LongContext bool // longer context windows rank higher
+	Fast        bool // answers without a reasoning pass
+}
+
+// RoleRequirements are the requirements of each role. The judge reads the
+// whole transcript and must answer in JSON; the Tenth Man argues against a
+// settled position, which rewards reasoning over a long transcript; debaters
+// speak every round, so they should be quick.
+var RoleRequirements = map[Role]Requirements{
+	RoleDebater:  {Fast: true},
+	RoleTenthMan: {Reasoning: true, LongContext: true},
+	RoleJudge:    {JSONMode: true, LongContext: true},
+}
+
+// Assignment is the models chosen for each role of a debate.
+type Assignment struct {
+	Debaters []openrouter.Model
+	TenthMan openrouter.Model
+	Judge    openrouter.Model
+}
+
+// Assign picks models for debaters debaters, the Tenth Man and the judge,
+// ranking the free models by how well they meet each role's requirements.
+// Debaters cycle through the best-ranked models so the debate stays
+// diverse; the Tenth Man prefers a model no debater uses. Models that rank
+// equally keep their registry order. It reports false if the registry is
+// empty.
+func (r *Registry) Assign(debaters int) (Assignment, bool) {
+	if len(r.free) == 0 {
+		return Assignment{}, false
+	}
+	var a Assignment
+	ranked := rank(r.free, RoleRequirements[RoleDebater])
+	for i := range debaters {
+		a.Debaters = append(a.Debaters, ranked[i%len(ranked)])
+	}
+	a.Judge = rank(r.free, RoleRequirements[RoleJudge])[0]
+
+	used := make(map[string]bool)
+	for _, m := range a.Debaters {
+		used[m.ID] = true
+	}
+	tenthMen := rank(r.free, RoleRequirements[RoleTenthMan])
+	a.TenthMan = tenthMen[0]
+	best := score(a.TenthMan, RoleRequirements[RoleTenthMan])
+	for _, m := range tenthMen {
+		if score(m, RoleRequirements[RoleTenthMan]) != best {
+			break
+		}
+		if !used[m.ID] {
+			a.TenthMan = m
+			break
+		}
+	}
+	return a, true
+}
+
+// rank returns models ordered by how well they meet req, best first.
+func rank(models []openrouter.Model, req Requirements) []openrouter.Model {
+	ranked := slices.Clone(models)
+	slices.SortStableFunc(ranked, func(a, b openrouter.Model) int {
+		return score(b, req).compare(score(a, req))
+	})
+	return ranked
+}
+
+// fit is how well a model meets a role's requirements.
+type fit struct {
+	met     int // requirements met
+	context int // context length, when the role wants a long one
+}
+
+func (f fit) compare(g fit) int {
+	return cmp.Or(cmp.Compare(f.met, g.met), cmp.Compare(f.context, g.context))
+}
+
+func score(m openrouter.Model, req Requirements) fit {
+	var f fit
+	if req.JSONMode && m.SupportsJSON() {
+		f.met++
+	}
+	if req.Reasoning && m.SupportsReasoning() {
+		f.met++
+	}
+	if req.Fast && !m.SupportsReasoning() {
+		f.met++
+	}
+	if req.LongContext {
+		f.context = m.ContextLength
+	}
+	return f
+}
@@ -0,0 +1,171 @@
+// Package narration renders a debate transcript as a podcast-style audio
+// file, with a narrator and one voice per agent.
+package narration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// DefaultGap is the silence between two segments.
+const DefaultGap = 600 * time.Millisecond
+
+var (
+	markupRe  = regexp.MustCompile("(?m)^\\s*(?:#+|[-*+]|>)\\s+|[*_`~]+")
+	turnRefRe = regexp.MustCompile(`#(\d+)`)
+)
+
+// segment is one stretch of speech in a single voice.
+type segment struct {
+	Speaker string
+	Voice   string
+	Text    string
+}
+
+// Narrator reads transcripts aloud.
+type Narrator struct {
+	synth  Synthesizer
+	voices []string
+	gap    time.Duration
+}
+
+// NewNarrator creates a Narrator. The first voice narrates; agents take the
+// others in order of first turn, reusing them when there are more agents
+// than voices. With a single voice, everyone shares it.
+func NewNarrator(synth Synthesizer, voices []string) *Narrator {
+	return &Narrator{synth: synth, voices: voices, gap: DefaultGap}
+}
+
+// SetGap sets the silence between two segments.
+func (n *Narrator) SetGap(gap time.Duration) {
+	if gap >= 0 {
+		n.gap = gap
+	}
+}
+
+// Narrate synthesizes transcript and writes it to w as one WAV file.
+func (n *Narrator) Narrate(ctx context.Context, w io.Writer, transcript *debate.Transcript) error {
+	if len(n.voices) == 0 {
+		return fmt.Errorf("narration: no voices")
+	}
+	script := n.script(transcript)
+	segments := make([][]byte, 0, len(script))
+	for _, s := range script {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("narration: %w", err)
+		}
+		audio, err := n.synth.Synthesize(ctx, s.Text, s.Voice)
+		if err != nil {
+			return err
+		}
+		segments = append(segments, audio)
+	}
+	if err := joinWAV(w, segments, n.gap); err != nil {
+		return fmt.Errorf("narration: %w", err)
+	}
+	return nil
+}
+
+// script lays out what is said, by whom and in which voice: the narrator
+// introduces the topic and speakers, announces each round and the Tenth
+// Man's challenge, and closes with the outcome and executive summary.
+func (n *Narrator) script(t *debate.Transcript) []segment {
+	narrator := n.voices[0]
+	agentVoices := n.voices
+	if len(n.voices) > 1 {
+		agentVoices = n.voices[1:]
+	}
+
+	voices := make(map[string]string)
+	var speakers []string
+	for _, turn := range t.Turns {
+		name := turn.Agent.Name
+		if turn.Agent.Role == "moderator" || voices[name] != "" {
+			continue
+		}
+		voices[name] = agentVoices[len(speakers)%len(agentVoices)]
+		speakers = append(speakers, name)
+	}
+
+	var script []segment
+	say := func(text string) {
+		script = append(script, segment{Speaker: "Narrator", Voice: narrator, Text: text})
+	}
+	say(fmt.Sprintf("%s. A debate between %s.", strings.TrimRight(t.Topic, ".?! "), listNames(speakers)))
+
+	round := 0
+	challenged := false
+	for _, turn := range t.Turns {
+		text := speakable(turn.Content)
+		if text == "" {
+			continue
+		}
+		var intro []string
+		if turn.Round != round {
+			round = turn.Round
+			intro = append(intro, fmt.Sprintf("Round %d.", round))
+		}
+		if turn.Agent.Role == "tenth-man" && !challenged {
+			challenged = true
+			if t.ConsensusPosition != "" {
+				intro = append(intro, "The debaters agreed: "+speakable(t.ConsensusPosition))
+			}
+			intro = append(intro, "Now the Tenth Man must argue against it.")
+		}
+		if turn.Agent.Role == "moderator" {
+			say(strings.Join(append(intro, "A note from the moderator: "+text), " "))
+			continue
+		}
+		say(strings.Join(append(intro, turn.Agent.Name+"."), " "))
+		script = append(script, segment{Speaker: turn.Agent.Name, Voice: voices[turn.Agent.Name], Text: text})
+	}
+
+	closing := []string{outcomeSentence(t.Outcome)}
+	if len(t.Summary) > 0 {
+		closing = append(closing, "In summary.")
+		for _, b := range t.Summary {
+			closing = append(closing, speakable(b))
+		}
+	}
+	if closing[0] != "" || len(closing) > 1 {
+		say(strings.TrimSpace(strings.Join(closing, " ")))
+	}
+	return script
+}
+
+// speakable strips markdown markup and spells out turn references so text
+// reads naturally aloud.
+func speakable(text string) string {
+	text = markupRe.ReplaceAllString(text, "")
+	text = turnRefRe.ReplaceAllString(text, "turn $1")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// listNames joins names as "A, B and C".
+func listNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// outcomeSentence says how the debate ended; "" if it is not recorded.
+func outcomeSentence(v debate.Verdict) string {
+	switch v {
+	case debate.VerdictUpheld:
+		return "The consensus survived the Tenth Man unchanged."
+	case debate.VerdictRevised:
+		return "The consensus survived the Tenth Man, with a changed position."
+	case debate.VerdictOverturned:
+		return "The Tenth Man overturned the consensus."
+	case debate.VerdictNoConsensus:
+		return "The debaters never reached a consensus."
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package narration
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// testWAV builds a 16-bit mono WAV file holding samples.
+func testWAV(rate int, samples ...int16) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(36+2*len(samples)))
+	b.WriteString("WAVEfmt ")
+	for _, v := range []any{uint32(16), uint16(1), uint16(1), uint32(rate), uint32(2 * rate), uint16(2), uint16(16)} {
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(2*len(samples)))
+	binary.Write(&b, binary.LittleEndian, samples)
+	return b.Bytes()
+}
+
+// fakeSynth records what it was asked to say and answers with one sample.
+type fakeSynth struct {
+	said []string
+	err  error
+}
+
+func (f *fakeSynth) Synthesize(_ context.Context, text, voice string) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.said = append(f.said, voice+": "+text)
+	return testWAV(1000, int16(len(f.said))), nil
+}
+
+func sampleTranscript() *debate.Transcript {
+	return &debate.Transcript{
+		Topic:             "Should we adopt caching?",
+		ConsensusPosition: "Adopt **caching**.",
+		Outcome:           debate.VerdictRevised,
+		Summary:           []string{"Caching was adopted with invalidation tests."},
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Role: "debater"}, Content: "## Position\n- Caching cuts `latency`."},
+			{ID: 2, Round: 1, Agent: debate.Agent{Name: "Bob", Role: "debater"}, Content: "I agree with #1."},
+			{ID: 3, Round: 1, Agent: debate.Agent{Name: "Moderator", Role: "moderator"}, Content: "Traffic doubled."},
+			{ID: 4, Round: 2, Agent: debate.Agent{Name: "Tenth Man", Role: "tenth-man"}, Content: "Caching adds staleness."},
+			{ID: 5, Round: 2, Agent: debate.Agent{Name: "Alice", Role: "debater"}, Content: "   "},
+		},
+	}
+}
+
+func TestScript(t *testing.T) {
+	n := NewNarrator(&fakeSynth{}, []string{"narrator", "v1", "v2"})
+	var got []string
+	for _, s := range n.script(sampleTranscript()) {
+		got = append(got, s.Speaker+"/"+s.Voice+": "+s.Text)
+	}
+	want := []string{
+		"Narrator/narrator: Should we adopt caching. A debate between Alice, Bob and Tenth Man.",
+		"Narrator/narrator: Round 1. Alice.",
+		"Alice/v1: Position Caching cuts latency.",
+		"Narrator/narrator: Bob.",
+		"Bob/v2: I agree with turn 1.",
+		"Narrator/narrator: A note from the moderator: Traffic doubled.",
+		"Narrator/narrator: Round 2. The debaters agreed: Adopt caching. Now the Tenth Man must argue against it. Tenth Man.",
+		"Tenth Man/v1: Caching adds staleness.",
+		"Narrator/narrator: The consensus survived the Tenth Man, with a changed position. In summary. Caching was adopted with invalidation tests.",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected script:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestNarrate(t *testing.T) {
+	synth := &fakeSynth{}
+	n := NewNarrator(synth, []string{"only"})
+	n.SetGap(2 * time.Millisecond)
+	var out bytes.Buffer
+	if err := n.Narrate(context.Background(), &out, sampleTranscript()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(synth.said) != 9 || !strings.HasPrefix(synth.said[2], "only: ") {
+		t.Errorf("expected 9 segments all in the one voice, got %q", synth.said)
+	}
+	w, err := parseWAV(out.Bytes())
+	if err != nil {
+		t.Fatalf("output is not a WAV file: %v", err)
+	}
+	// 9 one-sample segments with 2 silent samples between each.
+	if len(w.data) != 2*(9+8*2) {
+		t.Errorf("expected %d bytes of samples, got %d", 2*(9+8*2), len(w.data))
+	}
+	if got := int16(binary.LittleEndian.Uint16(w.data[6:8])); got != 2 {
+		t.Errorf("expected the second segment after the gap, got sample %d", got)
+	}
+
+	if err := NewNarrator(&fakeSynth{err: errors.New("boom")}, []string{"v"}).Narrate(context.Background(), &out, sampleTranscript()); err == nil {
+		t.Error("expected the synthesizer error")
+	}
+	if err := NewNarrator(synth, nil).Narrate(context.Background(), &out, sampleTranscript()); err == nil {
+		t.Error("expected an error without voices")
+	}
+}
+
+func TestJoinWAVRejectsMixedFormats(t *testing.T) {
+	err := joinWAV(&bytes.Buffer{}, [][]byte{testWAV(1000, 1), testWAV(2000, 1)}, 0)
+	if err == nil || !strings.Contains(err.Error(), "segment 2") {
+		t.Errorf("expected a format mismatch in segment 2, got %v", err)
+	}
+	if err := joinWAV(&bytes.Buffer{}, [][]byte{[]byte("ID3 not a wav")}, 0); err == nil {
+		t.Error("expected a non-WAV segment to be rejected")
+	}
+}
+
+func TestParseWAVStreamingSize(t *testing.T) {
+	b := testWAV(1000, 1, 2, 3)
+	binary.LittleEndian.PutUint32(b[40:44], 0xFFFFFFFF)
+	w, err := parseWAV(b)
+	if err != nil || len(w.data) != 6 {
+		t.Errorf("expected the data chunk to run to the end, got %v, %v", w, err)
+	}
+}
+
+func TestNewSynthesizer(t *testing.T) {
+	for _, spec := range []string{"ftp://tts", "https://", "exec:"} {
+		if _, err := NewSynthesizer(spec); err == nil {
+			t.Errorf("NewSynthesizer(%q) should fail", spec)
+		}
+	}
+	s, err := NewSynthesizer("exec:espeak-ng -v {voice} --stdout")
+	if err != nil || len(s.(*execSynthesizer).args) != 4 {
+		t.Errorf("unexpected exec synthesizer %+v, %v", s, err)
+	}
+}
+
+func TestHTTPSynthesizer(t *testing.T) {
+	t.Setenv("TTS_MODEL", "")
+	t.Setenv("TTS_API_KEY", "secret")
+	var got map[string]string
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/speech" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write(testWAV(1000, 7))
+	}))
+	defer srv.Close()
+
+	s, err := NewSynthesizer(srv.URL + "/v1/")
+	if err != nil {
+		t.Fatalf("NewSynthesizer: %v", err)
+	}
+	audio, err := s.Synthesize(context.Background(), "Hello.", "alloy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(audio, testWAV(1000, 7)) || auth != "Bearer secret" {
+		t.Errorf("unexpected audio or auth %q", auth)
+	}
+	if got["model"] != DefaultModel || got["input"] != "Hello." || got["voice"] != "alloy" || got["response_format"] != "wav" {
+		t.Errorf("unexpected request %v", got)
+	}
+
+	bad, _ := NewSynthesizer(srv.URL)
+	if _, err := bad.Synthesize(context.Background(), "Hello.", "alloy"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected the status error, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package narration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Synthesizer turns text into speech.
+type Synthesizer interface {
+	// Synthesize speaks text in voice and returns the audio as a WAV file.
+	Synthesize(ctx context.Context, text, voice string) ([]byte, error)
+}
+
+// DefaultModel is the model requested from HTTP backends when TTS_MODEL is
+// not set.
+const DefaultModel = "tts-1"
+
+// NewSynthesizer returns the text-to-speech backend for spec:
+//
+//   - http://... or https://... is an OpenAI-compatible speech API (OpenAI,
+//     Kokoro-FastAPI, LocalAI and others), given as its base URL, e.g.
+//     https://api.openai.com/v1. Audio is requested from <url>/audio/speech
+//     as WAV, with the model from TTS_MODEL (default tts-1) and the bearer
+//     token from TTS_API_KEY, if set.
+//   - exec:<command> runs a local program once per segment, with the text
+//     on stdin and WAV on stdout. {voice} in its arguments is replaced with
+//     the voice, e.g. "exec:espeak-ng -v {voice} --stdout".
+func NewSynthesizer(spec string) (Synthesizer, error) {
+	if command, ok := strings.CutPrefix(spec, "exec:"); ok {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("narration: %q has no command", spec)
+		}
+		return &execSynthesizer{args: args}, nil
+	}
+
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("narration: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, fmt.Errorf("narration: %q has no host", spec)
+		}
+		model := os.Getenv("TTS_MODEL")
+		if model == "" {
+			model = DefaultModel
+		}
+		return &httpSynthesizer{
+			httpClient: &http.Client{Timeout: 2 * time.Minute},
+			endpoint:   strings.TrimRight(spec, "/") + "/audio/speech",
+			model:      model,
+			apiKey:     os.Getenv("TTS_API_KEY"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("narration: unsupported TTS backend %q (want http://, https:// or exec:)", spec)
+	}
+}
+
+// httpSynthesizer calls an OpenAI-compatible /audio/speech endpoint.
+type httpSynthesizer struct {
+	httpClient *http.Client
+	endpoint   string
+	model      string
+	apiKey     string
+}
+
+// Synthesize implements Synthesizer.
+func (s *httpSynthesizer) Synthesize(ctx context.Context, text, voice string) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{
+		"model":           s.model,
+		"input":           text,
+		"voice":           voice,
+		"response_format": "wav",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("narration: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("narration: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("narration: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("narration: TTS returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("narration: %w", err)
+	}
+	return audio, nil
+}
+
+// execSynthesizer runs a local TTS program.
+type execSynthesizer struct {
+	args []string
+}
+
+// Synthesize implements Synthesizer.
+func (s *execSynthesizer) Synthesize(ctx context.Context, text, voice string) ([]byte, error) {
+	args := make([]string, len(s.args))
+	for i, a := range s.args {
+		args[i] = strings.ReplaceAll(a, "{voice}", voice)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	audio, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("narration: %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return audio, nil
+}
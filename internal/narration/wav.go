@@ -0,0 +1,127 @@
+package narration
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// wav is a parsed WAV file.
+type wav struct {
+	format []byte // body of the "fmt " chunk
+	data   []byte // sample data
+}
+
+// blockAlign returns the bytes per sample frame.
+func (w *wav) blockAlign() int {
+	return int(binary.LittleEndian.Uint16(w.format[12:14]))
+}
+
+// byteRate returns the bytes per second of audio.
+func (w *wav) byteRate() int {
+	return int(binary.LittleEndian.Uint32(w.format[8:12]))
+}
+
+// bitsPerSample returns the sample size.
+func (w *wav) bitsPerSample() int {
+	return int(binary.LittleEndian.Uint16(w.format[14:16]))
+}
+
+// parseWAV reads the format and samples of a RIFF WAV file. Streaming
+// encoders leave the data size unset, so a data chunk claiming more than the
+// file holds runs to its end.
+func parseWAV(b []byte) (*wav, error) {
+	if len(b) < 12 || string(b[0:4]) != "RIFF" || string(b[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("not a WAV file")
+	}
+	w := &wav{}
+	for pos := 12; pos+8 <= len(b); {
+		id := string(b[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(b[pos+4 : pos+8]))
+		start := pos + 8
+		end := start + size
+		if end > len(b) {
+			end = len(b)
+		}
+		switch id {
+		case "fmt ":
+			if end-start < 16 {
+				return nil, fmt.Errorf("WAV format chunk is too short")
+			}
+			w.format = b[start:end]
+		case "data":
+			w.data = b[start:end]
+		}
+		if w.format != nil && w.data != nil {
+			return w, nil
+		}
+		pos = end + size%2 // chunks are padded to an even size
+	}
+	if w.format == nil {
+		return nil, fmt.Errorf("WAV file has no format chunk")
+	}
+	return nil, fmt.Errorf("WAV file has no data chunk")
+}
+
+// joinWAV writes segments to out as one WAV file with gap of silence between
+// them. Every segment must have the same format.
+func joinWAV(out io.Writer, segments [][]byte, gap time.Duration) error {
+	if len(segments) == 0 {
+		return fmt.Errorf("no audio to join")
+	}
+	parsed := make([]*wav, len(segments))
+	for i, seg := range segments {
+		w, err := parseWAV(seg)
+		if err != nil {
+			return fmt.Errorf("segment %d: %w", i+1, err)
+		}
+		if i > 0 && !bytes.Equal(w.format, parsed[0].format) {
+			return fmt.Errorf("segment %d: audio format differs from the first segment; use voices with the same sample rate", i+1)
+		}
+		parsed[i] = w
+	}
+
+	first := parsed[0]
+	silence := make([]byte, 0)
+	if align := first.blockAlign(); align > 0 {
+		frames := int(gap.Seconds() * float64(first.byteRate()) / float64(align))
+		silence = make([]byte, frames*align)
+		if first.bitsPerSample() == 8 {
+			// 8-bit PCM is unsigned: silence is the midpoint.
+			for i := range silence {
+				silence[i] = 0x80
+			}
+		}
+	}
+
+	var data bytes.Buffer
+	for i, w := range parsed {
+		if i > 0 {
+			data.Write(silence)
+		}
+		data.Write(w.data)
+	}
+	if data.Len()%2 == 1 {
+		data.WriteByte(0)
+	}
+
+	var header bytes.Buffer
+	header.WriteString("RIFF")
+	binary.Write(&header, binary.LittleEndian, uint32(4+8+len(first.format)+len(first.format)%2+8+data.Len()))
+	header.WriteString("WAVEfmt ")
+	binary.Write(&header, binary.LittleEndian, uint32(len(first.format)))
+	header.Write(first.format)
+	if len(first.format)%2 == 1 {
+		header.WriteByte(0)
+	}
+	header.WriteString("data")
+	binary.Write(&header, binary.LittleEndian, uint32(data.Len()))
+
+	if _, err := out.Write(header.Bytes()); err != nil {
+		return err
+	}
+	_, err := out.Write(data.Bytes())
+	return err
+}
@@ -0,0 +1,23 @@
+package notify
+
+// Config selects and configures the notifiers for a headless deployment.
+type Config struct {
+	Webhook string       `yaml:"webhook"`
+	Email   *EmailConfig `yaml:"email"`
+}
+
+// Notifiers builds the notifiers enabled in c.
+func (c Config) Notifiers() ([]Notifier, error) {
+	var notifiers []Notifier
+	if c.Webhook != "" {
+		notifiers = append(notifiers, NewWebhook(c.Webhook))
+	}
+	if c.Email != nil {
+		email, err := NewEmail(*c.Email)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, email)
+	}
+	return notifiers, nil
+}
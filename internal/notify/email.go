@@ -0,0 +1,176 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailConfig configures SMTP delivery of run reports. Password may reference
+// environment variables (e.g. "${SMTP_PASSWORD}") so secrets stay out of the file.
+type EmailConfig struct {
+	Host             string   `yaml:"host"`
+	Port             int      `yaml:"port"`
+	Username         string   `yaml:"username"`
+	Password         string   `yaml:"password"`
+	From             string   `yaml:"from"`
+	To               []string `yaml:"to"`
+	AttachTranscript bool     `yaml:"attach_transcript"`
+}
+
+// Email sends run reports over SMTP, with report.md as the body.
+type Email struct {
+	cfg  EmailConfig
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmail validates cfg and creates an Email notifier.
+func NewEmail(cfg EmailConfig) (*Email, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("notify: email host is required")
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("notify: email from address is required")
+	}
+	if len(cfg.To) == 0 {
+		return nil, fmt.Errorf("notify: at least one email recipient is required")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	cfg.Password = os.ExpandEnv(cfg.Password)
+	return &Email{cfg: cfg, send: smtp.SendMail}, nil
+}
+
+// Notify implements Notifier. smtp.SendMail does not take a context, so ctx
+// is only checked before sending.
+func (e *Email) Notify(ctx context.Context, report Report) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	msg, err := e.buildMessage(report)
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+	}
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	if err := e.send(addr, auth, e.cfg.From, e.cfg.To, msg); err != nil {
+		return fmt.Errorf("notify: sending email: %w", err)
+	}
+	return nil
+}
+
+func (e *Email) buildMessage(report Report) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	textHeader := textproto.MIMEHeader{}
+	textHeader.Set("Content-Type", "text/plain; charset=utf-8")
+	part, err := mw.CreatePart(textHeader)
+	if err != nil {
+		return nil, err
+	}
+	part.Write([]byte(reportBody(report)))
+
+	if e.cfg.AttachTranscript && report.Dir != "" {
+		// Runs saved with compression only have the compressed transcript.
+		for _, a := range []struct{ name, contentType string }{
+			{"transcript.json", "application/json"},
+			{"transcript.json.gz", "application/gzip"},
+			{"transcript.json.zst", "application/zstd"},
+		} {
+			data, err := os.ReadFile(filepath.Join(report.Dir, a.name))
+			if err != nil {
+				continue
+			}
+			attachHeader := textproto.MIMEHeader{}
+			attachHeader.Set("Content-Type", a.contentType)
+			attachHeader.Set("Content-Transfer-Encoding", "base64")
+			attachHeader.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.name))
+			part, err := mw.CreatePart(attachHeader)
+			if err != nil {
+				return nil, err
+			}
+			part.Write([]byte(wrapBase64(data)))
+			break
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject(report)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", report.FinishedAt.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// subject returns the unencoded Subject header value. Names and topics come
+// from job files and API callers, so line breaks are flattened to keep them
+// from starting new headers.
+func subject(report Report) string {
+	name := report.Name
+	if name == "" {
+		name = report.Topic
+	}
+	name = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(name)
+	switch {
+	case report.Error != "":
+		return fmt.Sprintf("[tenthman] %s: failed", name)
+	case report.Consensus != nil && report.Consensus.Detected:
+		return fmt.Sprintf("[tenthman] %s: consensus %d/10", name, report.Consensus.Score)
+	default:
+		return fmt.Sprintf("[tenthman] %s: no consensus", name)
+	}
+}
+
+// reportBody returns the run's report.md, falling back to a short summary
+// when the report is unavailable (e.g. the run failed).
+func reportBody(report Report) string {
+	if report.Dir != "" {
+		if data, err := os.ReadFile(filepath.Join(report.Dir, "report.md")); err == nil {
+			return string(data)
+		}
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Topic: %s\n", report.Topic)
+	if report.Error != "" {
+		fmt.Fprintf(&sb, "Error: %s\n", report.Error)
+	}
+	if report.Dir != "" {
+		fmt.Fprintf(&sb, "Output: %s\n", report.Dir)
+	}
+	return sb.String()
+}
+
+// wrapBase64 encodes data with RFC 2045 line lengths.
+func wrapBase64(data []byte) string {
+	enc := base64.StdEncoding.EncodeToString(data)
+	var sb strings.Builder
+	for len(enc) > 76 {
+		sb.WriteString(enc[:76])
+		sb.WriteString("\r\n")
+		enc = enc[76:]
+	}
+	sb.WriteString(enc)
+	return sb.String()
+}
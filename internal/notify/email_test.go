@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+type sentMail struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	msg  string
+}
+
+func captureEmail(t *testing.T, cfg EmailConfig) (*Email, *sentMail) {
+	t.Helper()
+	e, err := NewEmail(cfg)
+	if err != nil {
+		t.Fatalf("NewEmail() error = %v", err)
+	}
+	sent := &sentMail{}
+	e.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		*sent = sentMail{addr: addr, auth: a, from: from, to: to, msg: string(msg)}
+		return nil
+	}
+	return e, sent
+}
+
+func runDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "report.md"), []byte("# Debate Report: Framework X\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "transcript.json"), []byte(`{"Topic":"Framework X"}`), 0o644)
+	return dir
+}
+
+func TestEmailSendsReport(t *testing.T) {
+	t.Setenv("TEST_SMTP_PASSWORD", "s3cret")
+	e, sent := captureEmail(t, EmailConfig{
+		Host:     "smtp.example.com",
+		Username: "bot@example.com",
+		Password: "${TEST_SMTP_PASSWORD}",
+		From:     "bot@example.com",
+		To:       []string{"team@example.com", "lead@example.com"},
+	})
+	if e.cfg.Password != "s3cret" {
+		t.Errorf("password env not expanded: %q", e.cfg.Password)
+	}
+
+	report := Report{
+		Name:       "framework-x",
+		Topic:      "Framework X",
+		Dir:        runDir(t),
+		Consensus:  &debate.ConsensusResult{Detected: true, Score: 8},
+		FinishedAt: time.Now(),
+	}
+	if err := e.Notify(context.Background(), report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sent.addr != "smtp.example.com:587" {
+		t.Errorf("addr = %q, want default port 587", sent.addr)
+	}
+	if sent.auth == nil {
+		t.Error("expected PLAIN auth when username is set")
+	}
+	if len(sent.to) != 2 {
+		t.Errorf("expected 2 recipients, got %v", sent.to)
+	}
+	for _, want := range []string{"Subject: [tenthman] framework-x: consensus 8/10", "To: team@example.com, lead@example.com", "# Debate Report: Framework X"} {
+		if !strings.Contains(sent.msg, want) {
+			t.Errorf("message missing %q", want)
+		}
+	}
+	if strings.Contains(sent.msg, "transcript.json") {
+		t.Error("transcript should not be attached unless configured")
+	}
+}
+
+func TestEmailAttachesTranscript(t *testing.T) {
+	e, sent := captureEmail(t, EmailConfig{
+		Host:             "smtp.example.com",
+		Port:             25,
+		From:             "bot@example.com",
+		To:               []string{"team@example.com"},
+		AttachTranscript: true,
+	})
+	if err := e.Notify(context.Background(), Report{Name: "framework-x", Dir: runDir(t)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.auth != nil {
+		t.Error("expected no auth without username")
+	}
+	if !strings.Contains(sent.msg, `filename="transcript.json"`) {
+		t.Error("expected transcript attachment")
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte(`{"Topic":"Framework X"}`))
+	if !strings.Contains(sent.msg, encoded) {
+		t.Error("attachment content not base64 encoded in message")
+	}
+}
+
+func TestEmailFailedRunSummary(t *testing.T) {
+	e, sent := captureEmail(t, EmailConfig{Host: "h", From: "f@example.com", To: []string{"t@example.com"}})
+	if err := e.Notify(context.Background(), Report{Name: "nightly", Topic: "Topic", Error: "rate limited"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(sent.msg, "Subject: [tenthman] nightly: failed") || !strings.Contains(sent.msg, "Error: rate limited") {
+		t.Errorf("unexpected failure message:\n%s", sent.msg)
+	}
+}
+
+func TestEmailSubjectCannotInjectHeaders(t *testing.T) {
+	e, sent := captureEmail(t, EmailConfig{Host: "h", From: "f@example.com", To: []string{"t@example.com"}})
+	topic := "Framework X\r\nBcc: attacker@example.com\r\n\r\nforged body"
+	if err := e.Notify(context.Background(), Report{Topic: topic, Error: "boom"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	headers, _, _ := strings.Cut(sent.msg, "\r\n\r\n")
+	for _, line := range strings.Split(headers, "\r\n") {
+		if strings.HasPrefix(line, "Bcc:") {
+			t.Fatalf("topic injected a header:\n%s", headers)
+		}
+	}
+	if !strings.Contains(headers, "Subject: [tenthman] Framework X Bcc: attacker@example.com  forged body: failed") {
+		t.Errorf("unexpected subject:\n%s", headers)
+	}
+}
+
+func TestEmailSubjectEncodesNonASCII(t *testing.T) {
+	e, sent := captureEmail(t, EmailConfig{Host: "h", From: "f@example.com", To: []string{"t@example.com"}})
+	if err := e.Notify(context.Background(), Report{Topic: "Migración", Error: "boom"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(sent.msg, "Subject: =?utf-8?q?") {
+		t.Errorf("subject not Q-encoded:\n%s", sent.msg)
+	}
+}
+
+func TestEmailSendError(t *testing.T) {
+	e, _ := captureEmail(t, EmailConfig{Host: "h", From: "f@example.com", To: []string{"t@example.com"}})
+	e.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+	if err := e.Notify(context.Background(), Report{}); err == nil {
+		t.Fatal("expected send error")
+	}
+}
+
+func TestNewEmailValidation(t *testing.T) {
+	for name, cfg := range map[string]EmailConfig{
+		"missing host": {From: "f", To: []string{"t"}},
+		"missing from": {Host: "h", To: []string{"t"}},
+		"missing to":   {Host: "h", From: "f"},
+	} {
+		if _, err := NewEmail(cfg); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestConfigNotifiers(t *testing.T) {
+	notifiers, err := Config{
+		Webhook: "https://hooks.example.com",
+		Email:   &EmailConfig{Host: "h", From: "f", To: []string{"t"}},
+	}.Notifiers()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifiers) != 2 {
+		t.Errorf("expected 2 notifiers, got %d", len(notifiers))
+	}
+	if _, err := (Config{Email: &EmailConfig{}}).Notifiers(); err == nil {
+		t.Error("expected error for invalid email config")
+	}
+}
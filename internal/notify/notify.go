@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// Report summarizes a finished debate run for delivery.
+type Report struct {
+	Name       string                  `json:"name"`
+	Topic      string                  `json:"topic"`
+	Dir        string                  `json:"dir,omitempty"`
+	Rounds     int                     `json:"rounds"`
+	Consensus  *debate.ConsensusResult `json:"consensus,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+	FinishedAt time.Time               `json:"finished_at"`
+}
+
+// Notifier delivers a report once a run finishes.
+type Notifier interface {
+	Notify(ctx context.Context, report Report) error
+}
+
+// Webhook posts reports as JSON to a URL.
+type Webhook struct {
+	httpClient *http.Client
+	url        string
+}
+
+// NewWebhook creates a Webhook notifier posting to url.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{httpClient: &http.Client{Timeout: 30 * time.Second}, url: url}
+}
+
+// Notify implements Notifier.
+func (w *Webhook) Notify(ctx context.Context, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("notify: webhook returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
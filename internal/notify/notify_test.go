@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+func TestWebhookPostsReport(t *testing.T) {
+	var got Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	report := Report{
+		Name:      "weekly-framework",
+		Topic:     "Should we still use framework X?",
+		Rounds:    6,
+		Consensus: &debate.ConsensusResult{Detected: true, Score: 8},
+	}
+	if err := NewWebhook(server.URL).Notify(context.Background(), report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "weekly-framework" || got.Rounds != 6 || got.Consensus == nil || got.Consensus.Score != 8 {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+}
+
+func TestWebhookErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := NewWebhook(server.URL).Notify(context.Background(), Report{}); err == nil {
+		t.Fatal("expected error for 500 status")
+	}
+}
@@ -0,0 +1,89 @@
+package openrouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailures = 3
+	defaultBreakerCooldown = time.Minute
+)
+
+// breaker keeps one circuit per model. A model's circuit opens after
+// failures consecutive failed requests; while it is open, requests for the
+// model fail at once with ErrCircuitOpen. After cooldown a single trial
+// request is let through: success closes the circuit, failure reopens it.
+type breaker struct {
+	mu       sync.Mutex
+	failures int
+	cooldown time.Duration
+	now      func() time.Time
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	trial     bool // a request is testing whether the model recovered
+}
+
+func newBreaker(failures int, cooldown time.Duration) *breaker {
+	return &breaker{failures: failures, cooldown: cooldown, now: time.Now, circuits: make(map[string]*circuit)}
+}
+
+// allow reports whether a request for model may be made.
+func (b *breaker) allow(model string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[model]
+	if c == nil || c.failures < b.failures {
+		return true
+	}
+	if c.trial || b.now().Before(c.openUntil) {
+		return false
+	}
+	c.trial = true
+	return true
+}
+
+// done records the outcome of a request for model that allow let through.
+// Errors that say nothing about the model, such as a cancelled context or a
+// rejected request, leave its circuit as it was.
+func (b *breaker) done(ctx context.Context, model string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		delete(b.circuits, model)
+		return
+	}
+	c := b.circuits[model]
+	if c == nil {
+		c = &circuit{}
+		b.circuits[model] = c
+	}
+	c.trial = false
+	if !modelFailure(ctx, err) {
+		return
+	}
+	c.failures++
+	if c.failures >= b.failures {
+		c.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// modelFailure reports whether err means the model could not serve the
+// request, as opposed to the request itself being wrong or abandoned.
+func modelFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return isRetryable(statusErr.StatusCode) || statusErr.StatusCode == http.StatusNotFound
+	}
+	return true
+}
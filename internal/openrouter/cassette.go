@@ -0,0 +1,220 @@
+package openrouter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Interaction is one recorded API request and its response. Bodies that are
+// JSON are kept as JSON so cassettes stay readable; others are kept as text.
+type Interaction struct {
+	Method       string            `json:"method"`
+	Path         string            `json:"path"`
+	Request      json.RawMessage   `json:"request,omitempty"`
+	Status       int               `json:"status"`
+	Header       map[string]string `json:"header,omitempty"`
+	Response     json.RawMessage   `json:"response,omitempty"`
+	ResponseText string            `json:"response_text,omitempty"`
+}
+
+// Cassette is a recording of API interactions, in the order they happened.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// recordedHeaders are the response headers a cassette keeps. Request
+// headers, including the API key, are never recorded.
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+// SetTransport makes the client send its requests through rt, such as a
+// Recorder or a Replayer.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient = &http.Client{Transport: rt}
+}
+
+// Recorder is an http.RoundTripper that sends requests through next and
+// saves every interaction to a cassette file as it completes.
+type Recorder struct {
+	path     string
+	next     http.RoundTripper
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder writing to path. A nil next uses
+// http.DefaultTransport.
+func NewRecorder(path string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{path: path, next: next, cassette: Cassette{Interactions: []Interaction{}}}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	in := Interaction{Method: req.Method, Path: req.URL.Path, Status: resp.StatusCode}
+	if json.Valid(reqBody) {
+		in.Request = json.RawMessage(reqBody)
+	}
+	if json.Valid(respBody) {
+		in.Response = json.RawMessage(respBody)
+	} else {
+		in.ResponseText = string(respBody)
+	}
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			if in.Header == nil {
+				in.Header = make(map[string]string)
+			}
+			in.Header[h] = v
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// save writes the cassette so far, replacing the file atomically. r.mu must
+// be held.
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("openrouter: cassette: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".cassette-*")
+	if err != nil {
+		return fmt.Errorf("openrouter: cassette: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("openrouter: cassette: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("openrouter: cassette: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("openrouter: cassette: %w", err)
+	}
+	return nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from a cassette
+// without touching the network. A request gets the response recorded for
+// the same method, path and body; identical requests get their recorded
+// responses in order.
+type Replayer struct {
+	mu      sync.Mutex
+	pending map[string][]Interaction
+}
+
+// LoadCassette reads the cassette at path into a Replayer.
+func LoadCassette(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("openrouter: cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("openrouter: cassette %s: %w", path, err)
+	}
+	return NewReplayer(c), nil
+}
+
+// NewReplayer returns a Replayer for c.
+func NewReplayer(c Cassette) *Replayer {
+	r := &Replayer{pending: make(map[string][]Interaction)}
+	for _, in := range c.Interactions {
+		key := interactionKey(in.Method, in.Path, in.Request)
+		r.pending[key] = append(r.pending[key], in)
+	}
+	return r
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	key := interactionKey(req.Method, req.URL.Path, body)
+
+	r.mu.Lock()
+	queue := r.pending[key]
+	if len(queue) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("openrouter: cassette has no response left for %s %s", req.Method, req.URL.Path)
+	}
+	in := queue[0]
+	r.pending[key] = queue[1:]
+	r.mu.Unlock()
+
+	respBody := []byte(in.ResponseText)
+	if len(in.Response) > 0 {
+		respBody = in.Response
+	}
+	header := make(http.Header)
+	for k, v := range in.Header {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// interactionKey identifies a request regardless of how its JSON body is
+// formatted.
+func interactionKey(method, path string, body []byte) string {
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil {
+		body = compact.Bytes()
+	}
+	return method + " " + path + "\n" + string(body)
+}
+
+// readBody reads *body and replaces it with a fresh reader of the same bytes.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, fmt.Errorf("openrouter: cassette: %w", err)
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
@@ -0,0 +1,86 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := count.Add(1)
+		if r.URL.Path == "/models" {
+			json.NewEncoder(w).Encode(ModelsResponse{Data: []Model{{ID: "m:free"}}})
+			return
+		}
+		if n == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, "overloaded")
+			return
+		}
+		json.NewEncoder(w).Encode(ChatResponse{Choices: []Choice{{Message: Message{Content: fmt.Sprintf("answer %d", n)}}}})
+	}))
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	recording := NewClientWithBaseURL("secret-key", server.URL)
+	recording.backoffFunc = noDelay
+	recording.SetTransport(NewRecorder(path, nil))
+	msgs := []Message{{Role: "user", Content: "hello"}}
+	var recorded []string
+	if _, err := recording.ListModels(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		resp, err := recording.ChatCompletion(context.Background(), "m:free", msgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorded = append(recorded, resp.Choices[0].Message.Content)
+	}
+	server.Close()
+
+	replayer, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaying := NewClientWithBaseURL("other-key", server.URL)
+	replaying.backoffFunc = noDelay
+	replaying.SetTransport(replayer)
+	models, err := replaying.ListModels(context.Background())
+	if err != nil || len(models) != 1 || models[0].ID != "m:free" {
+		t.Fatalf("expected the recorded models, got %v, %v", models, err)
+	}
+	for i, want := range recorded {
+		resp, err := replaying.ChatCompletion(context.Background(), "m:free", msgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Choices[0].Message.Content; got != want {
+			t.Errorf("call %d: expected %q, got %q", i, want, got)
+		}
+	}
+	if _, err := replaying.ChatCompletion(context.Background(), "m:free", msgs); err == nil {
+		t.Error("expected an error once the cassette has no responses left")
+	}
+	if _, err := replaying.ChatCompletion(context.Background(), "m:free", []Message{{Role: "user", Content: "unrecorded"}}); err == nil || errors.Is(err, ErrModelUnavailable) {
+		t.Errorf("expected a missing-interaction error, got %v", err)
+	}
+}
+
+func TestCassetteReplaysErrors(t *testing.T) {
+	replaying := NewClientWithBaseURL("key", "http://cassette.invalid")
+	replaying.backoffFunc = noDelay
+	replaying.SetTransport(NewReplayer(Cassette{Interactions: []Interaction{
+		{Method: http.MethodPost, Path: "/chat/completions", Request: json.RawMessage(`{"model":"m","messages":[]}`), Status: http.StatusBadRequest, Response: json.RawMessage(`{"error":{"code":400,"message":"m is not a valid model ID"}}`)},
+	}}))
+	if _, err := replaying.ChatCompletion(context.Background(), "m", []Message{}); !errors.Is(err, ErrInvalidModel) {
+		t.Errorf("expected the recorded error, got %v", err)
+	}
+}
@@ -7,22 +7,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"time"
 )
 
-const maxRetries = 3
-
 // Client is an OpenRouter API client.
 type Client struct {
 	httpClient  *http.Client
 	apiKey      string
 	baseURL     string
 	backoffFunc func(attempt int) time.Duration
-}
-
-func defaultBackoff(attempt int) time.Duration {
-	return time.Duration(1<<uint(attempt)) * time.Second
+	sleep       func(ctx context.Context, d time.Duration) error
+	maxWait     time.Duration // total wait between the attempts of one request
+	maxTokens   int
+	limiter     *rateLimiter
+	breaker     *breaker
+	pacer       *pacer
 }
 
 // NewClient creates a new Client with the default OpenRouter base URL.
@@ -32,6 +31,9 @@ func NewClient(apiKey string) *Client {
 		apiKey:      apiKey,
 		baseURL:     "https://openrouter.ai/api/v1",
 		backoffFunc: defaultBackoff,
+		sleep:       sleep,
+		maxWait:     defaultMaxRetryWait,
+		breaker:     newBreaker(defaultBreakerFailures, defaultBreakerCooldown),
 	}
 }
 
@@ -42,28 +44,113 @@ func NewClientWithBaseURL(apiKey, baseURL string) *Client {
 		apiKey:      apiKey,
 		baseURL:     baseURL,
 		backoffFunc: defaultBackoff,
+		sleep:       sleep,
+		maxWait:     defaultMaxRetryWait,
+		breaker:     newBreaker(defaultBreakerFailures, defaultBreakerCooldown),
 	}
 }
 
+// SetMaxTokens caps the completion length of every chat request. Zero leaves it to the model default.
+func (c *Client) SetMaxTokens(n int) {
+	c.maxTokens = n
+}
+
+// SetMaxRetryWait caps the total time one request waits between its
+// attempts; a retry that would go past it is not made. Values below 1 are
+// ignored.
+func (c *Client) SetMaxRetryWait(d time.Duration) {
+	if d > 0 {
+		c.maxWait = d
+	}
+}
+
+// SetCircuitBreaker makes requests for a model fail at once with
+// ErrCircuitOpen after failures consecutive requests for it failed, until
+// cooldown has passed. Clients start with 3 failures and a one minute
+// cooldown; failures below 1 turns the breaker off.
+func (c *Client) SetCircuitBreaker(failures int, cooldown time.Duration) {
+	if failures < 1 {
+		c.breaker = nil
+		return
+	}
+	c.breaker = newBreaker(failures, cooldown)
+}
+
+// SetAdaptivePacing spaces requests for each model by a delay that grows on
+// every 429 response, up to maxDelay, and shrinks on every success. Zero or
+// negative disables pacing, the default.
+func (c *Client) SetAdaptivePacing(maxDelay time.Duration) {
+	if maxDelay <= 0 {
+		c.pacer = nil
+		return
+	}
+	c.pacer = newPacer(maxDelay)
+}
+
+// Pace returns the current delay between requests for model under adaptive
+// pacing.
+func (c *Client) Pace(model string) time.Duration {
+	if c.pacer == nil {
+		return 0
+	}
+	return c.pacer.spacing(model)
+}
+
+// SetRateLimit limits the client to perMinute requests, shared across all
+// goroutines using it. Zero or negative disables limiting.
+func (c *Client) SetRateLimit(perMinute int) {
+	if perMinute <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = newRateLimiter(perMinute)
+}
+
 // ChatCompletion sends a chat completion request with retry for transient failures.
-func (c *Client) ChatCompletion(ctx context.Context, model string, messages []Message) (*ChatResponse, error) {
+func (c *Client) ChatCompletion(ctx context.Context, model string, messages []Message, opts ...Option) (*ChatResponse, error) {
 	reqBody := ChatRequest{
-		Model:    model,
-		Messages: messages,
+		Model:     model,
+		Messages:  messages,
+		MaxTokens: c.maxTokens,
+	}
+	for _, opt := range opts {
+		opt(&reqBody)
 	}
 	body, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("openrouter: %w", err)
 	}
+	if c.breaker != nil {
+		if !c.breaker.allow(model) {
+			return nil, fmt.Errorf("openrouter: %s: %w", model, ErrCircuitOpen)
+		}
+		chatResp, err := c.chatCompletion(ctx, model, reqBody, body)
+		c.breaker.done(ctx, model, err)
+		return chatResp, err
+	}
+	return c.chatCompletion(ctx, model, reqBody, body)
+}
 
-	resp, err := c.doWithRetry(ctx, func(ctx context.Context) (*http.Response, error) {
+// chatCompletion sends the encoded request body for model, with retries and
+// adaptive pacing.
+func (c *Client) chatCompletion(ctx context.Context, model string, reqBody ChatRequest, body []byte) (*ChatResponse, error) {
+	resp, err := c.doWithRetry(ctx, reqBody.onRetry, func(ctx context.Context) (*http.Response, error) {
+		if c.pacer != nil {
+			if err := c.sleep(ctx, c.pacer.reserve(model)); err != nil {
+				return nil, err
+			}
+		}
 		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
 		if err != nil {
 			return nil, err
 		}
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 		req.Header.Set("Content-Type", "application/json")
-		return c.httpClient.Do(req)
+		resp, err := c.httpClient.Do(req)
+		if err == nil && c.pacer != nil {
+			c.pacer.observe(model, resp.StatusCode == http.StatusTooManyRequests)
+		}
+		return resp, err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("openrouter: %w", err)
@@ -74,6 +161,15 @@ func (c *Client) ChatCompletion(ctx context.Context, model string, messages []Me
 	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
 		return nil, fmt.Errorf("openrouter: %w", err)
 	}
+	// A provider failing after OpenRouter accepted the request is reported
+	// in an otherwise successful response.
+	if e := chatResp.Error; e != nil {
+		status := e.Code
+		if status == 0 {
+			status = http.StatusBadGateway
+		}
+		return nil, fmt.Errorf("openrouter: %w", &StatusError{StatusCode: status, Code: e.Code, Message: e.Message, Metadata: e.Metadata})
+	}
 	return &chatResp, nil
 }
 
@@ -81,15 +177,18 @@ func isRetryable(statusCode int) bool {
 	return statusCode == http.StatusTooManyRequests || statusCode >= 500
 }
 
-func (c *Client) doWithRetry(ctx context.Context, do func(context.Context) (*http.Response, error)) (*http.Response, error) {
-	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			delay := c.backoffFunc(attempt - 1)
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(delay):
+// doWithRetry calls do until it succeeds, fails permanently or runs out of
+// retries. Before each retry it waits for the server's Retry-After, if any,
+// or else a jittered exponential backoff. It gives up early, returning the
+// last failure, when that wait would exceed the request's total wait or
+// outlast ctx's deadline. onRetry, if set, is told about each failure that
+// will be retried.
+func (c *Client) doWithRetry(ctx context.Context, onRetry func(error), do func(context.Context) (*http.Response, error)) (*http.Response, error) {
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx); err != nil {
+				return nil, err
 			}
 		}
 
@@ -105,30 +204,25 @@ func (c *Client) doWithRetry(ctx context.Context, do func(context.Context) (*htt
 		respBody, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
 
-		if !isRetryable(resp.StatusCode) {
-			return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+		statusErr := newStatusError(resp.StatusCode, respBody)
+		if !isRetryable(resp.StatusCode) || attempt == maxRetries {
+			return nil, statusErr
 		}
-
-		// Respect Retry-After header on 429 (additional wait on top of backoff)
-		if resp.StatusCode == http.StatusTooManyRequests {
-			if ra := resp.Header.Get("Retry-After"); ra != "" {
-				if secs, parseErr := strconv.Atoi(ra); parseErr == nil {
-					raDelay := time.Duration(secs) * time.Second
-					// Skip if backoffFunc signals zero delays (test mode)
-					if raDelay > 0 && c.backoffFunc(0) > 0 {
-						select {
-						case <-ctx.Done():
-							return nil, ctx.Err()
-						case <-time.After(raDelay):
-						}
-					}
-				}
-			}
+		delay, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			delay = c.backoffFunc(attempt)
 		}
-
-		lastErr = fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+		if waited+delay > c.maxWait || !fitsDeadline(ctx, delay) {
+			return nil, statusErr
+		}
+		if onRetry != nil {
+			onRetry(statusErr)
+		}
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		waited += delay
 	}
-	return nil, lastErr
 }
 
 // ListModels retrieves available models from OpenRouter.
@@ -148,7 +242,7 @@ func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("openrouter: unexpected status %d: %s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("openrouter: %w", newStatusError(resp.StatusCode, respBody))
 	}
 
 	var modelsResp ModelsResponse
@@ -157,3 +251,24 @@ func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
 	}
 	return modelsResp.Data, nil
 }
+
+// Ping checks that OpenRouter is reachable by requesting the model list,
+// without decoding it.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/models", nil)
+	if err != nil {
+		return fmt.Errorf("openrouter: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("openrouter: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("openrouter: %w", &StatusError{StatusCode: resp.StatusCode})
+	}
+	return nil
+}
@@ -3,10 +3,12 @@ package openrouter
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -111,6 +113,26 @@ func TestListModels(t *testing.T) {
 	}
 }
 
+func TestPing(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			t.Errorf("expected /models, got %s", r.URL.Path)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	status = http.StatusServiceUnavailable
+	if err := client.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("expected a status error, got %v", err)
+	}
+}
+
 func TestChatCompletionErrorStatus(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -128,6 +150,76 @@ func TestChatCompletionErrorStatus(t *testing.T) {
 	}
 }
 
+func TestChatCompletionErrorKinds(t *testing.T) {
+	tests := []struct {
+		status int
+		kind   error
+	}{
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusNotFound, ErrModelUnavailable},
+		{http.StatusServiceUnavailable, ErrModelUnavailable},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		client := NewClientWithBaseURL("test-key", server.URL)
+		client.backoffFunc = noDelay
+		_, err := client.ChatCompletion(context.Background(), "test-model", []Message{{Role: "user", Content: "hello"}})
+		server.Close()
+
+		var statusErr *StatusError
+		if !errors.Is(err, tt.kind) || !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.kind, err)
+		}
+	}
+	if errors.Is(&StatusError{StatusCode: http.StatusBadRequest}, ErrRateLimited) || errors.Is(&StatusError{StatusCode: http.StatusBadRequest}, ErrModelUnavailable) {
+		t.Error("400 should not match any kind")
+	}
+}
+
+func TestChatCompletionParsesErrorPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		kind    error
+		message string
+	}{
+		{"invalid model", http.StatusBadRequest, `{"error":{"code":400,"message":"foo/bar is not a valid model ID"}}`, ErrInvalidModel, "foo/bar is not a valid model ID"},
+		{"moderation", http.StatusForbidden, `{"error":{"code":403,"message":"Input flagged","metadata":{"reasons":["violence"],"flagged_input":"..."}}}`, ErrModerated, "Input flagged (violence)"},
+		{"provider", http.StatusBadGateway, `{"error":{"code":502,"message":"Upstream error","metadata":{"provider_name":"Acme"}}}`, ErrModelUnavailable, "Upstream error from Acme"},
+		{"in successful response", http.StatusOK, `{"error":{"code":503,"message":"Provider overloaded"}}`, ErrModelUnavailable, "Provider overloaded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			client := NewClientWithBaseURL("test-key", server.URL)
+			client.backoffFunc = noDelay
+
+			_, err := client.ChatCompletion(context.Background(), "test-model", []Message{{Role: "user", Content: "hello"}})
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("expected %v, got %v", tt.kind, err)
+			}
+			if !strings.HasSuffix(err.Error(), ": "+tt.message) {
+				t.Errorf("expected message %q, got %q", tt.message, err.Error())
+			}
+		})
+	}
+
+	plain := newStatusError(http.StatusBadRequest, []byte("bad request"))
+	if plain.Message != "" || plain.Error() != "unexpected status 400: bad request" {
+		t.Errorf("expected the raw body for a non-JSON error, got %q", plain.Error())
+	}
+	if errors.Is(plain, ErrInvalidModel) || errors.Is(newStatusError(http.StatusForbidden, nil), ErrModerated) {
+		t.Error("errors without a payload should not match payload kinds")
+	}
+}
+
 func TestListModelsErrorStatus(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -178,7 +270,9 @@ func TestChatCompletionRetries429(t *testing.T) {
 	defer server.Close()
 
 	client := NewClientWithBaseURL("test-key", server.URL)
-	client.backoffFunc = noDelay
+	client.backoffFunc = func(int) time.Duration { return time.Minute }
+	var delays []time.Duration
+	client.sleep = recordSleep(&delays)
 
 	resp, err := client.ChatCompletion(context.Background(), "test-model", []Message{
 		{Role: "user", Content: "hello"},
@@ -192,6 +286,229 @@ func TestChatCompletionRetries429(t *testing.T) {
 	if got := count.Load(); got != 3 {
 		t.Errorf("expected 3 total requests, got %d", got)
 	}
+	if fmt.Sprint(delays) != "[1s 1s]" {
+		t.Errorf("expected Retry-After to replace the backoff, waited %v", delays)
+	}
+}
+
+// recordSleep returns a sleep function that appends each delay to delays
+// instead of waiting.
+func recordSleep(delays *[]time.Duration) func(context.Context, time.Duration) error {
+	return func(_ context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+}
+
+func TestRetryStopsAtMaxWait(t *testing.T) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	client.backoffFunc = func(int) time.Duration { return time.Minute }
+	var delays []time.Duration
+	client.sleep = recordSleep(&delays)
+	client.SetMaxRetryWait(90 * time.Second)
+
+	_, err := client.ChatCompletion(context.Background(), "test-model", []Message{{Role: "user", Content: "hello"}})
+	if !errors.Is(err, ErrModelUnavailable) {
+		t.Fatalf("expected the last failure, got %v", err)
+	}
+	if got := count.Load(); got != 2 || len(delays) != 1 {
+		t.Errorf("expected 2 attempts and 1 wait within 90s, got %d attempts and waits %v", got, delays)
+	}
+}
+
+func TestRetryRespectsContextDeadline(t *testing.T) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := client.ChatCompletion(ctx, "test-model", []Message{{Role: "user", Content: "hello"}})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected the rate limit error rather than waiting for the deadline, got %v", err)
+	}
+	if got := count.Load(); got != 1 || time.Since(start) > time.Second {
+		t.Errorf("expected a single attempt without waiting, got %d in %v", got, time.Since(start))
+	}
+}
+
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	client.backoffFunc = noDelay
+	client.SetCircuitBreaker(2, time.Minute)
+	msgs := []Message{{Role: "user", Content: "hello"}}
+	for range 2 {
+		if _, err := client.ChatCompletion(context.Background(), "dead-model", msgs); !errors.Is(err, ErrModelUnavailable) {
+			t.Fatalf("expected the model to fail, got %v", err)
+		}
+	}
+	before := count.Load()
+	if _, err := client.ChatCompletion(context.Background(), "dead-model", msgs); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected an open circuit, got %v", err)
+	}
+	if count.Load() != before {
+		t.Error("an open circuit should not send requests")
+	}
+	if _, err := client.ChatCompletion(context.Background(), "other-model", msgs); errors.Is(err, ErrCircuitOpen) {
+		t.Error("circuits should be per model")
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := newBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+	unavailable := &StatusError{StatusCode: http.StatusServiceUnavailable}
+
+	b.done(ctx, "m", &StatusError{StatusCode: http.StatusBadRequest})
+	b.done(ctx, "m", unavailable)
+	if !b.allow("m") {
+		t.Fatal("a rejected request and one failure should not open the circuit")
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	b.done(cancelled, "m", context.Canceled)
+	if !b.allow("m") {
+		t.Fatal("an abandoned request should not count as a failure")
+	}
+	b.done(ctx, "m", unavailable)
+	if b.allow("m") {
+		t.Fatal("expected the circuit to open after 2 failures")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.allow("m") {
+		t.Fatal("expected a trial request after the cooldown")
+	}
+	if b.allow("m") {
+		t.Error("only one trial request should be let through")
+	}
+	b.done(ctx, "m", unavailable)
+	if b.allow("m") {
+		t.Fatal("a failed trial should reopen the circuit")
+	}
+
+	now = now.Add(time.Minute)
+	b.allow("m")
+	b.done(ctx, "m", nil)
+	if !b.allow("m") || !b.allow("m") {
+		t.Error("a successful trial should close the circuit")
+	}
+}
+
+func TestAdaptivePacing(t *testing.T) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(successResponse())
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	client.backoffFunc = noDelay
+	var delays []time.Duration
+	client.sleep = recordSleep(&delays)
+	client.SetAdaptivePacing(time.Minute)
+
+	msgs := []Message{{Role: "user", Content: "hello"}}
+	if _, err := client.ChatCompletion(context.Background(), "busy-model", msgs); err != nil {
+		t.Fatal(err)
+	}
+	if got := client.Pace("busy-model"); got != 900*time.Millisecond {
+		t.Errorf("expected a 1s pace shrunk by a tenth after the retry succeeded, got %v", got)
+	}
+	if len(delays) < 2 || delays[len(delays)-1] <= 0 {
+		t.Errorf("expected the retry to wait for its paced slot, waited %v", delays)
+	}
+	if got := client.Pace("other-model"); got != 0 {
+		t.Errorf("pacing should be per model, got %v", got)
+	}
+}
+
+func TestPacer(t *testing.T) {
+	now := time.Now()
+	p := newPacer(4 * time.Second)
+	p.now = func() time.Time { return now }
+
+	if d := p.reserve("m"); d != 0 {
+		t.Fatalf("an unpaced model should not wait, got %v", d)
+	}
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		p.observe("m", true)
+		if got := p.spacing("m"); got != want {
+			t.Fatalf("expected spacing %v after a 429, got %v", want, got)
+		}
+	}
+	if d := p.reserve("m"); d != 4*time.Second {
+		t.Errorf("expected to wait out the spacing after a 429, got %v", d)
+	}
+	if d := p.reserve("m"); d != 8*time.Second {
+		t.Errorf("expected back-to-back requests to queue one spacing apart, got %v", d)
+	}
+	for range 40 {
+		p.observe("m", false)
+	}
+	if got := p.spacing("m"); got != 0 {
+		t.Errorf("expected successes to wind the pacing down, got %v", got)
+	}
+}
+
+func TestDefaultBackoffFullJitter(t *testing.T) {
+	for attempt, ceiling := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second, 30 * time.Second} {
+		for range 50 {
+			if d := defaultBackoff(attempt); d < 0 || d > ceiling {
+				t.Fatalf("attempt %d: backoff %v outside [0, %v]", attempt, d, ceiling)
+			}
+		}
+	}
+	if defaultBackoff(100) > maxBackoff {
+		t.Error("backoff should stay capped for large attempts")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"7", 7 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(20 * time.Second).Format(http.TimeFormat), 20 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		if got, ok := retryAfter(tt.value, now); got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
 }
 
 func TestChatCompletionRetries500(t *testing.T) {
@@ -270,3 +587,139 @@ func TestChatCompletionNoRetryOn400(t *testing.T) {
 		t.Errorf("expected 1 request (no retry), got %d", got)
 	}
 }
+
+func TestChatCompletionSendsMaxTokens(t *testing.T) {
+	var got ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(successResponse())
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	client.SetMaxTokens(500)
+
+	if _, err := client.ChatCompletion(context.Background(), "test-model", []Message{{Role: "user", Content: "hello"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.MaxTokens != 500 {
+		t.Errorf("expected max_tokens 500, got %d", got.MaxTokens)
+	}
+}
+
+func TestRateLimitSpacesConcurrentRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(successResponse())
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	client.SetRateLimit(600) // one request every 100ms
+
+	start := time.Now()
+	errs := make(chan error, 3)
+	for range 3 {
+		go func() {
+			_, err := client.ChatCompletion(context.Background(), "test-model", []Message{{Role: "user", Content: "hello"}})
+			errs <- err
+		}()
+	}
+	for range 3 {
+		if err := <-errs; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Three requests sharing one limiter need at least two intervals.
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected requests to be spaced by the shared limiter, finished in %v", elapsed)
+	}
+}
+
+func TestRateLimitRespectsContextCancellation(t *testing.T) {
+	l := newRateLimiter(1) // one request per minute
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("first wait should not block: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); err == nil {
+		t.Error("expected context error while waiting for the next slot")
+	}
+}
+
+func TestChatCompletionSendsTemperature(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(successResponse())
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	msgs := []Message{{Role: "user", Content: "hello"}}
+
+	if _, err := client.ChatCompletion(context.Background(), "test-model", msgs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := got["temperature"]; ok {
+		t.Errorf("temperature should be omitted by default, got %v", got["temperature"])
+	}
+
+	if _, err := client.ChatCompletion(context.Background(), "test-model", msgs, WithTemperature(0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["temperature"] != 0.0 {
+		t.Errorf("expected temperature 0, got %v", got["temperature"])
+	}
+}
+
+func TestChatCompletionSendsReasoning(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"answer","reasoning":"thinking"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	msgs := []Message{{Role: "user", Content: "hello"}}
+
+	resp, err := client.ChatCompletion(context.Background(), "test-model", msgs, WithReasoning(EffortHigh))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reasoning, _ := got["reasoning"].(map[string]any)
+	if reasoning["effort"] != "high" || got["include_reasoning"] != true {
+		t.Errorf("expected high effort with reasoning included, got reasoning=%v include_reasoning=%v", got["reasoning"], got["include_reasoning"])
+	}
+	if msg := resp.Choices[0].Message; msg.Content != "answer" || msg.Reasoning != "thinking" {
+		t.Errorf("expected answer and reasoning apart, got %+v", msg)
+	}
+	if err := ValidateEffort("extreme"); err == nil {
+		t.Error("expected an error for an unknown effort")
+	}
+}
+
+func TestChatCompletionRetryHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, "overloaded")
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("test-key", server.URL)
+	client.backoffFunc = noDelay
+
+	var retried []error
+	_, err := client.ChatCompletion(context.Background(), "test-model", []Message{{Role: "user", Content: "hello"}},
+		WithRetryHook(func(err error) { retried = append(retried, err) }))
+	if err == nil {
+		t.Fatal("expected error after max retries, got nil")
+	}
+	if len(retried) != maxRetries {
+		t.Fatalf("expected the hook once per retry (%d), got %d", maxRetries, len(retried))
+	}
+	if !strings.Contains(retried[0].Error(), "503") {
+		t.Errorf("hook error should carry the status, got %v", retried[0])
+	}
+}
@@ -0,0 +1,75 @@
+package openrouter
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// contentPart is one part of a multi-part message content.
+type contentPart struct {
+	Type     string    `json:"type"` // "text" or "image_url"
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+// message is Message without its JSON methods, with content left raw.
+type message struct {
+	Role      string          `json:"role"`
+	Content   json.RawMessage `json:"content"`
+	Reasoning string          `json:"reasoning,omitempty"`
+}
+
+// MarshalJSON encodes m with plain string content, or with multi-part
+// content when it has images.
+func (m Message) MarshalJSON() ([]byte, error) {
+	var content any = m.Content
+	if len(m.Images) > 0 {
+		parts := []contentPart{{Type: "text", Text: m.Content}}
+		for _, url := range m.Images {
+			parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: url}})
+		}
+		content = parts
+	}
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(message{Role: m.Role, Content: raw, Reasoning: m.Reasoning})
+}
+
+// UnmarshalJSON decodes a message whose content is a string or a list of
+// parts. Text parts are joined into Content and image parts collected in
+// Images.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var raw message
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = Message{Role: raw.Role, Reasoning: raw.Reasoning}
+	if len(raw.Content) == 0 || string(raw.Content) == "null" {
+		return nil
+	}
+	if raw.Content[0] != '[' {
+		return json.Unmarshal(raw.Content, &m.Content)
+	}
+	var parts []contentPart
+	if err := json.Unmarshal(raw.Content, &parts); err != nil {
+		return fmt.Errorf("openrouter: message content: %w", err)
+	}
+	for _, p := range parts {
+		switch {
+		case p.Type == "text":
+			if m.Content != "" {
+				m.Content += "\n"
+			}
+			m.Content += p.Text
+		case p.Type == "image_url" && p.ImageURL != nil:
+			m.Images = append(m.Images, p.ImageURL.URL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package openrouter
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestMessageJSON(t *testing.T) {
+	data, err := json.Marshal(Message{Role: "user", Content: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"role":"user","content":"hi"}` {
+		t.Errorf("plain message encoded as %s", data)
+	}
+
+	msg := Message{Role: "user", Content: "look", Images: []string{"https://example.com/chart.png"}}
+	if data, err = json.Marshal(msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"role":"user","content":[{"type":"text","text":"look"},{"type":"image_url","image_url":{"url":"https://example.com/chart.png"}}]}`
+	if string(data) != want {
+		t.Errorf("message with images encoded as %s, want %s", data, want)
+	}
+
+	var got Message
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Role != msg.Role || got.Content != msg.Content || !slices.Equal(got.Images, msg.Images) {
+		t.Errorf("round trip gave %+v, want %+v", got, msg)
+	}
+	if err := json.Unmarshal([]byte(`{"role":"assistant","content":null}`), &got); err != nil || got.Content != "" {
+		t.Errorf("null content gave %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,111 @@
+package openrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error kinds callers can branch on with errors.Is.
+var (
+	// ErrRateLimited means OpenRouter answered 429 on every attempt.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrModelUnavailable means the model does not exist or no provider
+	// could serve it.
+	ErrModelUnavailable = errors.New("model unavailable")
+	// ErrInvalidModel means the request named a model ID OpenRouter does
+	// not know.
+	ErrInvalidModel = errors.New("invalid model")
+	// ErrModerated means a moderation check flagged the input.
+	ErrModerated = errors.New("input flagged by moderation")
+	// ErrCircuitOpen means the model failed repeatedly and is not being
+	// called until its cooldown ends.
+	ErrCircuitOpen = errors.New("circuit open")
+)
+
+// StatusError is returned for an unsuccessful response. When OpenRouter
+// sent its JSON error payload, Code, Message and Metadata hold its fields;
+// Body always holds the raw response. It matches the error kinds above when
+// the status or payload means so.
+type StatusError struct {
+	StatusCode int
+	Code       int
+	Message    string
+	Metadata   map[string]any
+	Body       string
+}
+
+// APIError is the error payload OpenRouter sends with failed requests.
+type APIError struct {
+	Code     int            `json:"code"`
+	Message  string         `json:"message"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// newStatusError builds the error for a response with the given status and
+// body, parsing OpenRouter's error payload if there is one.
+func newStatusError(status int, body []byte) *StatusError {
+	e := &StatusError{StatusCode: status, Body: string(body)}
+	var payload struct {
+		Error *APIError `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Error != nil {
+		e.Code = payload.Error.Code
+		e.Message = payload.Error.Message
+		e.Metadata = payload.Error.Metadata
+	}
+	return e
+}
+
+func (e *StatusError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Body
+	}
+	if reasons := e.Reasons(); len(reasons) > 0 {
+		msg += " (" + strings.Join(reasons, ", ") + ")"
+	}
+	if p := e.Provider(); p != "" {
+		msg += " from " + p
+	}
+	if msg == "" {
+		return fmt.Sprintf("unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, msg)
+}
+
+// Provider returns the upstream provider that failed, if OpenRouter said.
+func (e *StatusError) Provider() string {
+	p, _ := e.Metadata["provider_name"].(string)
+	return p
+}
+
+// Reasons returns why moderation flagged the input, if it did.
+func (e *StatusError) Reasons() []string {
+	list, _ := e.Metadata["reasons"].([]any)
+	var reasons []string
+	for _, r := range list {
+		if s, ok := r.(string); ok {
+			reasons = append(reasons, s)
+		}
+	}
+	return reasons
+}
+
+// Is reports whether the status or payload matches target's kind.
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrModelUnavailable:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusServiceUnavailable
+	case ErrInvalidModel:
+		return e.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(e.Message), "not a valid model")
+	case ErrModerated:
+		_, flagged := e.Metadata["flagged_input"]
+		return e.StatusCode == http.StatusForbidden && (flagged || len(e.Reasons()) > 0)
+	}
+	return false
+}
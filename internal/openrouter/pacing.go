@@ -0,0 +1,85 @@
+package openrouter
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// minPace is the spacing a model gets after its first 429.
+	minPace = time.Second
+	// paceDecay is the share of a model's spacing removed after every
+	// successful request.
+	paceDecay = 10
+)
+
+// pacer spaces requests per model, adapting to the rate limits it observes:
+// every 429 doubles the model's spacing, up to max, and every success
+// shrinks it by a tenth. Long debates settle at a rate the model sustains
+// instead of bursting into limits and backing off.
+type pacer struct {
+	mu     sync.Mutex
+	max    time.Duration
+	now    func() time.Time
+	models map[string]*pace
+}
+
+type pace struct {
+	spacing time.Duration
+	next    time.Time // earliest start of the next request
+}
+
+func newPacer(maxSpacing time.Duration) *pacer {
+	return &pacer{max: maxSpacing, now: time.Now, models: make(map[string]*pace)}
+}
+
+// reserve books the next request slot for model and returns how long to
+// wait for it.
+func (p *pacer) reserve(model string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m := p.models[model]
+	if m == nil || m.spacing == 0 {
+		return 0
+	}
+	now := p.now()
+	start := m.next
+	if start.Before(now) {
+		start = now
+	}
+	m.next = start.Add(m.spacing)
+	return start.Sub(now)
+}
+
+// observe adapts model's spacing to the outcome of a request.
+func (p *pacer) observe(model string, rateLimited bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m := p.models[model]
+	if m == nil {
+		if !rateLimited {
+			return
+		}
+		m = &pace{}
+		p.models[model] = m
+	}
+	if rateLimited {
+		m.spacing = min(max(2*m.spacing, minPace), p.max)
+		m.next = p.now().Add(m.spacing)
+		return
+	}
+	m.spacing -= m.spacing / paceDecay
+	if m.spacing < minPace/10 {
+		delete(p.models, model)
+	}
+}
+
+// spacing returns the current spacing between requests for model.
+func (p *pacer) spacing(model string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if m := p.models[model]; m != nil {
+		return m.spacing
+	}
+	return 0
+}
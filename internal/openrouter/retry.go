@@ -0,0 +1,68 @@
+package openrouter
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	maxRetries = 3
+	// baseBackoff and maxBackoff bound the backoff before each retry, which
+	// doubles with every attempt.
+	baseBackoff = time.Second
+	maxBackoff  = 30 * time.Second
+	// defaultMaxRetryWait caps the time one request spends waiting between
+	// its attempts.
+	defaultMaxRetryWait = 2 * time.Minute
+)
+
+// defaultBackoff returns a full-jitter delay before retry attempt+1: a random
+// duration up to baseBackoff doubled attempt times, capped at maxBackoff.
+// Spreading retries out keeps concurrent callers from retrying in lockstep.
+func defaultBackoff(attempt int) time.Duration {
+	ceiling := maxBackoff
+	if attempt < 5 {
+		ceiling = min(baseBackoff<<attempt, maxBackoff)
+	}
+	return rand.N(ceiling + 1)
+}
+
+// retryAfter parses a Retry-After header value, given in seconds or as an
+// HTTP date, into the delay it asks for.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for d, or returns ctx's error if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// fitsDeadline reports whether ctx leaves time to wait for d and still make
+// another attempt.
+func fitsDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Now().Add(d).Before(deadline)
+}
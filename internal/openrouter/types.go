@@ -1,20 +1,111 @@
 package openrouter
 
-// Message represents a chat message.
+import (
+	"fmt"
+	"slices"
+)
+
+// Message represents a chat message. A message with Images is sent in the
+// multi-part content format, with Content as its text part.
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string
+	Content string
+	Images  []string // image URLs, either https:// or data: URLs
+	// Reasoning is the reasoning trace a model returned alongside Content,
+	// when reasoning was requested.
+	Reasoning string
 }
 
 // ChatRequest represents a request to the chat completions endpoint.
 type ChatRequest struct {
-	Model    string    `json:"model"`
-	Messages []Message `json:"messages"`
+	Model       string     `json:"model"`
+	Messages    []Message  `json:"messages"`
+	MaxTokens   int        `json:"max_tokens,omitempty"`
+	Temperature *float64   `json:"temperature,omitempty"`
+	Reasoning   *Reasoning `json:"reasoning,omitempty"`
+	// IncludeReasoning asks models to return their reasoning trace in
+	// Message.Reasoning instead of dropping it.
+	IncludeReasoning bool `json:"include_reasoning,omitempty"`
+
+	onRetry func(error) // see WithRetryHook
+}
+
+// Reasoning configures the thinking of reasoning models.
+type Reasoning struct {
+	Effort string `json:"effort,omitempty"` // one of the Effort constants
+}
+
+// Reasoning efforts.
+const (
+	EffortLow    = "low"
+	EffortMedium = "medium"
+	EffortHigh   = "high"
+)
+
+// ValidateEffort reports whether effort is a reasoning effort OpenRouter
+// accepts. "" leaves the model's default.
+func ValidateEffort(effort string) error {
+	switch effort {
+	case "", EffortLow, EffortMedium, EffortHigh:
+		return nil
+	default:
+		return fmt.Errorf("openrouter: unknown reasoning effort %q (want low, medium or high)", effort)
+	}
+}
+
+// Option customizes a single chat completion request.
+type Option func(*ChatRequest)
+
+// WithTemperature sets the sampling temperature for the request.
+func WithTemperature(t float64) Option {
+	return func(r *ChatRequest) { r.Temperature = &t }
+}
+
+// WithMaxTokens caps the completion length of the request, overriding the
+// client's SetMaxTokens. Zero leaves the client's cap in place.
+func WithMaxTokens(n int) Option {
+	return func(r *ChatRequest) {
+		if n > 0 {
+			r.MaxTokens = n
+		}
+	}
+}
+
+// WithReasoning sets the reasoning effort for the request and asks for the
+// reasoning trace to be returned in Message.Reasoning.
+func WithReasoning(effort string) Option {
+	return func(r *ChatRequest) {
+		r.Reasoning = &Reasoning{Effort: effort}
+		r.IncludeReasoning = true
+	}
+}
+
+// WithRetryHook calls fn with the error of each failed attempt that is about
+// to be retried.
+func WithRetryHook(fn func(err error)) Option {
+	return func(r *ChatRequest) { r.onRetry = fn }
+}
+
+// Retrying calls the hook set with WithRetryHook, if any. It lets LLM
+// clients other than Client report the failed attempts they retry.
+func (r *ChatRequest) Retrying(err error) {
+	if r.onRetry != nil {
+		r.onRetry(err)
+	}
 }
 
 // ChatResponse represents a response from the chat completions endpoint.
 type ChatResponse struct {
-	Choices []Choice `json:"choices"`
+	Choices []Choice  `json:"choices"`
+	Usage   *Usage    `json:"usage,omitempty"`
+	Error   *APIError `json:"error,omitempty"`
+}
+
+// Usage reports the tokens a request consumed.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 // Choice represents a single completion choice.
@@ -24,9 +115,39 @@ type Choice struct {
 
 // Model represents an OpenRouter model.
 type Model struct {
-	ID      string   `json:"id"`
-	Name    string   `json:"name"`
-	Pricing *Pricing `json:"pricing"`
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Pricing      *Pricing      `json:"pricing"`
+	Architecture *Architecture `json:"architecture,omitempty"`
+	// ContextLength is the model's context window in tokens; 0 if unknown.
+	ContextLength int `json:"context_length,omitempty"`
+	// SupportedParameters are the request parameters the model accepts,
+	// e.g. "response_format" or "reasoning".
+	SupportedParameters []string `json:"supported_parameters,omitempty"`
+}
+
+// Architecture describes what a model accepts and produces.
+type Architecture struct {
+	InputModalities []string `json:"input_modalities"` // e.g. "text", "image"
+}
+
+// SupportsJSON reports whether the model can be asked for structured JSON
+// output.
+func (m Model) SupportsJSON() bool {
+	return slices.Contains(m.SupportedParameters, "response_format") || slices.Contains(m.SupportedParameters, "structured_outputs")
+}
+
+// SupportsReasoning reports whether the model reasons before answering.
+func (m Model) SupportsReasoning() bool {
+	return slices.Contains(m.SupportedParameters, "reasoning") || slices.Contains(m.SupportedParameters, "include_reasoning")
+}
+
+// AcceptsImages reports whether the model takes images as input.
+func (m Model) AcceptsImages() bool {
+	if m.Architecture == nil {
+		return false
+	}
+	return slices.Contains(m.Architecture.InputModalities, "image")
 }
 
 // Pricing represents model pricing information.
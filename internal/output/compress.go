@@ -0,0 +1,167 @@
+package output
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression formats for finished run artifacts.
+const (
+	CompressNone = ""
+	CompressGzip = "gzip"
+	CompressZstd = "zstd"
+)
+
+// compressedFiles are the artifacts replaced by compressed copies. report.md
+// stays readable as-is.
+var compressedFiles = []string{transcriptFile, logFile}
+
+// compressions are the compression formats with the suffix of the copies
+// they write.
+var compressions = []struct{ format, suffix string }{
+	{CompressGzip, ".gz"},
+	{CompressZstd, ".zst"},
+}
+
+// ValidateCompression reports whether format is a supported compression format.
+func ValidateCompression(format string) error {
+	switch format {
+	case CompressNone, CompressGzip, CompressZstd:
+		return nil
+	default:
+		return fmt.Errorf("output: unknown compression %q (want gzip or zstd)", format)
+	}
+}
+
+// CompressArtifacts replaces transcript.json and debate.log in dir with
+// compressed copies (transcript.json.gz and debate.log.gz with gzip,
+// transcript.json.zst and debate.log.zst with zstd). Missing files are
+// skipped.
+func CompressArtifacts(dir, format string) error {
+	if err := ValidateCompression(format); err != nil {
+		return err
+	}
+	if format == CompressNone {
+		return nil
+	}
+	for _, name := range compressedFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := compressFile(path, format); err != nil {
+			return fmt.Errorf("output: compressing %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// compressFile writes a copy of path compressed with format, path.gz or
+// path.zst, and removes path once the copy is complete.
+func compressFile(path, format string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(path + suffix(format))
+	if err != nil {
+		return err
+	}
+	var zw io.WriteCloser
+	if format == CompressZstd {
+		if zw, err = zstd.NewWriter(out); err != nil {
+			out.Close()
+			return err
+		}
+	} else {
+		gw := gzip.NewWriter(out)
+		gw.Name = filepath.Base(path)
+		zw = gw
+	}
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(path)
+}
+
+// DecompressArtifacts restores transcript.json and debate.log in dir from
+// their compressed copies so a finished run can be extended. It returns the
+// format the run was compressed with, CompressNone if it was not.
+func DecompressArtifacts(dir string) (string, error) {
+	format := CompressNone
+	for _, name := range compressedFiles {
+		path := filepath.Join(dir, name)
+		for _, c := range compressions {
+			if _, err := os.Stat(path + c.suffix); os.IsNotExist(err) {
+				continue
+			}
+			if err := decompressFile(path+c.suffix, c.format); err != nil {
+				return "", fmt.Errorf("output: decompressing %s: %w", name, err)
+			}
+			format = c.format
+		}
+	}
+	return format, nil
+}
+
+// decompressFile writes path, compressed with format, without its suffix
+// and removes path once the copy is complete.
+func decompressFile(path, format string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	var zr io.Reader
+	if format == CompressZstd {
+		d, err := zstd.NewReader(in)
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+		zr = d
+	} else if zr, err = gzip.NewReader(in); err != nil {
+		return err
+	}
+
+	out, err := os.Create(strings.TrimSuffix(path, suffix(format)))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, zr); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(path)
+}
+
+// suffix returns the suffix of the files compressed with format.
+func suffix(format string) string {
+	for _, c := range compressions {
+		if c.format == format {
+			return c.suffix
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Suffixes of encrypted artifacts.
+const (
+	ageSuffix = ".age"
+	gpgSuffix = ".gpg"
+)
+
+// encryptCommand returns the command that encrypts path into out for
+// recipients, run through age for age and SSH recipients and through gpg
+// otherwise. It is what a Writer runs unless tests stand in for the tools.
+func encryptCommand(ctx context.Context, tool string, recipients []string, path, out string) *exec.Cmd {
+	var args []string
+	if tool == "gpg" {
+		args = []string{"--batch", "--yes", "--trust-model", "always", "--encrypt"}
+	}
+	for _, r := range recipients {
+		args = append(args, "--recipient", r)
+	}
+	args = append(args, "--output", out, path)
+	return exec.CommandContext(ctx, tool, args...)
+}
+
+// encryptionTool returns the program that encrypts to recipients: "age"
+// when they are age (age1...) or SSH (ssh-...) public keys and "gpg" when
+// they are GnuPG key IDs, fingerprints or email addresses. The two kinds
+// cannot be mixed.
+func encryptionTool(recipients []string) (string, error) {
+	tool := ""
+	for _, r := range recipients {
+		if strings.TrimSpace(r) == "" {
+			return "", fmt.Errorf("output: empty encryption recipient")
+		}
+		kind := "gpg"
+		if strings.HasPrefix(r, "age1") || strings.HasPrefix(r, "ssh-") {
+			kind = "age"
+		}
+		if tool != "" && kind != tool {
+			return "", fmt.Errorf("output: cannot mix age and GPG encryption recipients")
+		}
+		tool = kind
+	}
+	return tool, nil
+}
+
+// ValidateRecipients reports whether recipients can be encrypted to
+// together. No recipients means no encryption.
+func ValidateRecipients(recipients []string) error {
+	_, err := encryptionTool(recipients)
+	return err
+}
+
+// EncryptArtifacts replaces every file in the run directory with a copy
+// encrypted to recipients, name.age when encrypting with age and name.gpg
+// with GnuPG, so the run can be stored or uploaded without exposing its
+// content. No recipients leaves the directory untouched.
+func (w *Writer) EncryptArtifacts(ctx context.Context, recipients []string) error {
+	tool, err := encryptionTool(recipients)
+	if err != nil || tool == "" {
+		return err
+	}
+	suffix := gpgSuffix
+	if tool == "age" {
+		suffix = ageSuffix
+	}
+	dir := w.dir
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || isEncrypted(path) {
+			return err
+		}
+		cmd := w.encryptCommand(ctx, tool, recipients, path, path+suffix)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			os.Remove(path + suffix)
+			rel, _ := filepath.Rel(dir, path)
+			return fmt.Errorf("output: encrypting %s with %s: %w: %s", rel, tool, err, strings.TrimSpace(stderr.String()))
+		}
+		return os.Remove(path)
+	})
+}
+
+// Encrypted reports whether dir holds artifacts saved with EncryptArtifacts.
+func Encrypted(dir string) bool {
+	for _, name := range []string{transcriptFile, transcriptFile + ".gz", transcriptFile + ".zst"} {
+		for _, suffix := range []string{ageSuffix, gpgSuffix} {
+			if _, err := os.Stat(filepath.Join(dir, name+suffix)); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isEncrypted(path string) bool {
+	return strings.HasSuffix(path, ageSuffix) || strings.HasSuffix(path, gpgSuffix)
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+const (
+	heatmapCell  = 44  // side of a heatmap cell, in pixels
+	heatmapLabel = 140 // width and height reserved for agent names
+)
+
+// WriteDisagreement writes an HTML page to disagreement.html with one SVG
+// heatmap per analysed round, each cell shaded by how strongly two agents
+// disagreed. Nothing is written if disagreement was not analysed.
+func (w *Writer) WriteDisagreement(transcript *debate.Transcript) error {
+	if len(transcript.Disagreement) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Disagreement: %s</title>\n", html.EscapeString(transcript.Topic))
+	sb.WriteString("<style>body{font-family:sans-serif;margin:2em}svg text{font-size:12px}</style>\n</head>\n<body>\n")
+	fmt.Fprintf(&sb, "<h1>Disagreement: %s</h1>\n<p>How strongly each pair of agents disagreed, from 0 (same position, white) to 10 (directly opposed, red). Grey pairs were not rated.</p>\n", html.EscapeString(transcript.Topic))
+	for _, rd := range transcript.Disagreement {
+		fmt.Fprintf(&sb, "<h2>Round %d</h2>\n", rd.Round)
+		if rd.Source != "" && rd.Source != "judge" {
+			fmt.Fprintf(&sb, "<p><em>Scored from %s.</em></p>\n", html.EscapeString(rd.Source))
+		}
+		writeHeatmapSVG(&sb, rd)
+	}
+	sb.WriteString("</body>\n</html>\n")
+	return w.writeFile(disagreementFile, []byte(sb.String()))
+}
+
+// writeHeatmapSVG renders rd's scores as a grid, rows and columns in the
+// agents' speaking order.
+func writeHeatmapSVG(sb *strings.Builder, rd debate.RoundDisagreement) {
+	size := heatmapLabel + heatmapCell*len(rd.Agents)
+	fmt.Fprintf(sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\">\n", size, size)
+	for i, name := range rd.Agents {
+		name = html.EscapeString(name)
+		pos := heatmapLabel + heatmapCell*i + heatmapCell/2
+		fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\" text-anchor=\"end\" dominant-baseline=\"middle\">%s</text>\n", heatmapLabel-6, pos, name)
+		fmt.Fprintf(sb, "<text transform=\"translate(%d,%d) rotate(-45)\">%s</text>\n", pos, heatmapLabel-6, name)
+	}
+	for i, row := range rd.Scores {
+		for j, score := range row {
+			x, y := heatmapLabel+heatmapCell*j, heatmapLabel+heatmapCell*i
+			fmt.Fprintf(sb, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" stroke=\"#fff\"><title>%s / %s: %s</title></rect>\n",
+				x, y, heatmapCell, heatmapCell, heatColor(score),
+				html.EscapeString(rd.Agents[i]), html.EscapeString(rd.Agents[j]), scoreText(score))
+			if i != j && score >= 0 {
+				fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\" text-anchor=\"middle\" dominant-baseline=\"middle\">%d</text>\n", x+heatmapCell/2, y+heatmapCell/2, score)
+			}
+		}
+	}
+	sb.WriteString("</svg>\n")
+}
+
+// heatColor shades a 0-10 disagreement score from white to red, or grey if
+// it is unrated.
+func heatColor(score int) string {
+	if score < 0 {
+		return "#ddd"
+	}
+	c := 255 - min(score, 10)*20
+	return fmt.Sprintf("rgb(255,%d,%d)", c, c)
+}
+
+// scoreText describes a disagreement score for a tooltip.
+func scoreText(score int) string {
+	if score < 0 {
+		return "not rated"
+	}
+	return fmt.Sprintf("%d/10", score)
+}
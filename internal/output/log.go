@@ -0,0 +1,196 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// Formats of debate.log.
+const (
+	LogText = "text" // one timestamped line of prose per entry
+	LogJSON = "json" // one JSON object per entry, for tail -f debate.log | jq
+)
+
+// LogEntry is one event in debate.log.
+type LogEntry struct {
+	Time    time.Time `json:"timestamp"`
+	Type    string    `json:"type"` // "log" for free text; otherwise the event, e.g. "turn"
+	Round   int       `json:"round,omitempty"`
+	Agent   string    `json:"agent,omitempty"`
+	Payload any       `json:"payload,omitempty"` // the event's data; {"message": ...} for free text
+	// Message is the entry in the text format; entries without one only
+	// appear in the JSON format.
+	Message string `json:"-"`
+}
+
+// ValidateLogFormat reports whether format is a supported debate.log format.
+func ValidateLogFormat(format string) error {
+	switch format {
+	case "", LogText, LogJSON:
+		return nil
+	default:
+		return fmt.Errorf("output: unknown log format %q (want text or json)", format)
+	}
+}
+
+// SetLogFormat sets the format of entries logged from now on. "" is LogText.
+func (w *Writer) SetLogFormat(format string) {
+	w.logFormat = format
+}
+
+// DetectLogFormat returns the format of the debate.log in dir: LogJSON if its
+// first line is a JSON object, otherwise LogText.
+func DetectLogFormat(dir string) string {
+	f, err := os.Open(filepath.Join(dir, logFile))
+	if err != nil {
+		return LogText
+	}
+	defer f.Close()
+	line, _ := bufio.NewReader(f).ReadBytes('\n')
+	var obj map[string]any
+	if json.Unmarshal(line, &obj) == nil {
+		return LogJSON
+	}
+	return LogText
+}
+
+// formatLogEntry renders entry as a line of debate.log in the writer's
+// format. It reports false if the entry has nothing to write.
+func (w *Writer) formatLogEntry(entry LogEntry) (string, bool) {
+	if w.logFormat != LogJSON {
+		if entry.Message == "" {
+			return "", false
+		}
+		return fmt.Sprintf("%s %s", entry.Time.Format(time.RFC3339), entry.Message), true
+	}
+	if entry.Payload == nil && entry.Message != "" {
+		entry.Payload = map[string]string{"message": entry.Message}
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data, _ = json.Marshal(LogEntry{Time: entry.Time, Type: entry.Type, Round: entry.Round, Agent: entry.Agent, Payload: map[string]string{"error": err.Error()}})
+	}
+	return string(data), true
+}
+
+// eventLog turns engine events into log entries. It remembers the current
+// round for events that do not carry one.
+type eventLog struct {
+	round int
+}
+
+// entry returns the log entry for ev; false if ev is not logged.
+func (l *eventLog) entry(ev debate.Event) (LogEntry, bool) {
+	entry := LogEntry{Time: time.Now(), Round: l.round}
+	switch ev := ev.(type) {
+	case debate.RoundStarted:
+		l.round = ev.Round
+		return entry, false
+	case debate.TurnCompleted:
+		turn := ev.Turn
+		entry.Type = "turn"
+		entry.Round = turn.Round
+		entry.Agent = turn.Agent.Name
+		entry.Payload = turnPayload{
+			ID: turn.ID, Model: turn.Agent.Model, Role: turn.Agent.Role, Content: turn.Content,
+			InReplyTo: turn.InReplyTo, Confidence: turn.Confidence, Tokens: turn.Tokens, LatencyMS: turn.LatencyMS,
+			Shortened: turn.Shortened, Samples: turn.Samples, Critique: turn.Critique, Passed: turn.Passed,
+			Statement: turn.Statement, Condensed: turn.Condensed,
+		}
+		entry.Message = fmt.Sprintf("[Round %d] %s (%s): %s", turn.Round, turn.Agent.Name, turn.Agent.Model, turn.Text())
+	case debate.EvidenceGathered:
+		e := ev.Evidence
+		entry.Type = "evidence"
+		entry.Round = e.Round
+		entry.Agent = e.RequestedBy
+		entry.Payload = map[string]string{"query": e.Query, "result": e.Result, "error": e.Error}
+		entry.Message = fmt.Sprintf("Evidence requested by %s: %s", e.RequestedBy, e.Query)
+	case debate.PhaseChanged:
+		entry.Type = "phase"
+		entry.Payload = map[string]string{"phase": phaseName(ev.Phase)}
+		entry.Message = fmt.Sprintf("Phase transition: %d", ev.Phase)
+	case debate.TenthManActivated:
+		entry.Type = "tenth_man"
+		entry.Agent = ev.Agent.Name
+		entry.Payload = map[string]string{"model": ev.Agent.Model, "position": ev.Position}
+	case debate.ConsensusEvaluated:
+		entry.Type = "consensus"
+		entry.Payload = ev.Result
+		if ev.Result.Fallback {
+			entry.Message = fmt.Sprintf("Consensus judge gave no parseable verdict; rule-based fallback scored %d/10", ev.Result.Score)
+		}
+	case debate.ModelSwapped:
+		s := ev.Swap
+		entry.Type = "model_swap"
+		entry.Round = s.Round
+		entry.Agent = s.Agent
+		entry.Payload = map[string]string{"from": s.From, "to": s.To}
+		entry.Message = fmt.Sprintf("Swapped %s's model from %s to %s from round %d", s.Agent, s.From, s.To, s.Round)
+	case debate.AgentRemoved:
+		r := ev.Removal
+		entry.Type = "agent_removed"
+		entry.Round = r.Round
+		entry.Agent = r.Agent
+		entry.Payload = map[string]string{"reason": r.Reason}
+		entry.Message = fmt.Sprintf("Removed %s from round %d: %s", r.Agent, r.Round, r.Reason)
+	case debate.AgentJoined:
+		j := ev.Join
+		entry.Type = "agent_joined"
+		entry.Round = j.Round
+		entry.Agent = j.Agent.Name
+		entry.Payload = map[string]string{"model": j.Agent.Model, "briefing": j.Briefing}
+		entry.Message = fmt.Sprintf("Added %s (%s) from round %d", j.Agent.Name, j.Agent.Model, j.Round)
+	case debate.InjectionSuspected:
+		w := ev.Warning
+		entry.Type = "injection"
+		entry.Round = w.Round
+		entry.Agent = w.Agent
+		entry.Payload = map[string]any{"source": w.Source, "kinds": w.Kinds}
+		entry.Message = fmt.Sprintf("Warning: %s looks like a prompt injection attempt (%s); agents see it only as untrusted text", w.Source, strings.Join(w.Kinds, ", "))
+	case debate.AgentError:
+		entry.Type = "agent_error"
+		entry.Agent = ev.Agent.Name
+		entry.Payload = map[string]any{"model": ev.Agent.Model, "error": ev.Err.Error(), "will_retry": ev.WillRetry}
+		if ev.WillRetry {
+			entry.Message = fmt.Sprintf("Retrying %s (%s): %v", ev.Agent.Name, ev.Agent.Model, ev.Err)
+		} else {
+			entry.Message = fmt.Sprintf("Failed %s (%s): %v", ev.Agent.Name, ev.Agent.Model, ev.Err)
+		}
+	default:
+		return entry, false
+	}
+	return entry, true
+}
+
+// turnPayload is a logged turn.
+type turnPayload struct {
+	ID         int    `json:"id"`
+	Model      string `json:"model"`
+	Role       string `json:"role"`
+	Content    string `json:"content"`
+	InReplyTo  int    `json:"in_reply_to,omitempty"`
+	Confidence *int   `json:"confidence,omitempty"`
+	Tokens     int    `json:"tokens,omitempty"`
+	LatencyMS  int    `json:"latency_ms,omitempty"`
+	Shortened  string `json:"shortened,omitempty"`
+	Samples    int    `json:"samples,omitempty"`
+	Critique   string `json:"critique,omitempty"`
+	Passed     bool   `json:"passed,omitempty"`
+	Statement  string `json:"statement,omitempty"`
+	Condensed  int    `json:"condensed,omitempty"`
+}
+
+// phaseName names a phase as the JSON log and the server do.
+func phaseName(p debate.Phase) string {
+	if p == debate.TenthManPhase {
+		return "tenth_man"
+	}
+	return "free_debate"
+}
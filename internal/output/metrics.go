@@ -0,0 +1,175 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+const (
+	chartHeight = 200 // height of a chart's plot area, in pixels
+	chartBar    = 48  // width of a bar or point step, in pixels
+	chartMargin = 48  // room for axis labels around the plot area
+)
+
+// series is one chart's labelled values.
+type series struct {
+	labels []string
+	values []float64
+}
+
+// metricSeries returns the turn tokens spent per round, the mean turn
+// latency per agent in speaking order and the consensus score after every
+// evaluation. A series is empty if the transcript has no data for it.
+func metricSeries(transcript *debate.Transcript) (tokens, latency, scores series) {
+	perRound := make(map[int]int)
+	latencySum := make(map[string]int)
+	latencyCount := make(map[string]int)
+	var rounds []int
+	var agents []string
+	for _, turn := range transcript.Turns {
+		if turn.Tokens > 0 {
+			if _, ok := perRound[turn.Round]; !ok {
+				rounds = append(rounds, turn.Round)
+			}
+			perRound[turn.Round] += turn.Tokens
+		}
+		if turn.LatencyMS > 0 {
+			if !slices.Contains(agents, turn.Agent.Name) {
+				agents = append(agents, turn.Agent.Name)
+			}
+			latencySum[turn.Agent.Name] += turn.LatencyMS
+			latencyCount[turn.Agent.Name]++
+		}
+	}
+	for _, r := range rounds {
+		tokens.labels = append(tokens.labels, fmt.Sprintf("R%d", r))
+		tokens.values = append(tokens.values, float64(perRound[r]))
+	}
+	for _, a := range agents {
+		latency.labels = append(latency.labels, a)
+		latency.values = append(latency.values, float64(latencySum[a])/float64(latencyCount[a]))
+	}
+	for _, s := range transcript.ConsensusScores {
+		scores.labels = append(scores.labels, fmt.Sprintf("R%d", s.Round))
+		scores.values = append(scores.values, float64(s.Score))
+	}
+	return tokens, latency, scores
+}
+
+// WriteMetrics writes an HTML page to metrics.html charting the run's turn
+// tokens per round, mean turn latency per agent and consensus score over
+// time as inline SVG. Charts without data are left out, and nothing is
+// written if there are none, as for runs saved before these were recorded.
+func (w *Writer) WriteMetrics(transcript *debate.Transcript) error {
+	tokens, latency, scores := metricSeries(transcript)
+	if len(tokens.values) == 0 && len(latency.values) == 0 && len(scores.values) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Metrics: %s</title>\n", html.EscapeString(transcript.Topic))
+	sb.WriteString("<style>body{font-family:sans-serif;margin:2em}svg text{font-size:12px}</style>\n</head>\n<body>\n")
+	fmt.Fprintf(&sb, "<h1>Metrics: %s</h1>\n", html.EscapeString(transcript.Topic))
+	if transcript.Tokens > 0 {
+		fmt.Fprintf(&sb, "<p>%d tokens spent in total, judge and post-debate analysis included.</p>\n", transcript.Tokens)
+	}
+	if len(tokens.values) > 0 {
+		sb.WriteString("<h2>Turn tokens per round</h2>\n")
+		writeBarChart(&sb, tokens, "%.0f")
+	}
+	if len(latency.values) > 0 {
+		sb.WriteString("<h2>Mean turn latency per agent (ms)</h2>\n")
+		writeBarChart(&sb, latency, "%.0f")
+	}
+	if len(scores.values) > 0 {
+		sb.WriteString("<h2>Consensus score over time</h2>\n")
+		writeLineChart(&sb, scores, 10)
+	}
+	sb.WriteString("</body>\n</html>\n")
+	return w.writeFile(metricsFile, []byte(sb.String()))
+}
+
+// writeRunMetrics sums up the run's tokens and latency and points to the
+// charts in metrics.html. Nothing is written if WriteMetrics writes none.
+func writeRunMetrics(sb *strings.Builder, transcript *debate.Transcript) {
+	tokens, latency, scores := metricSeries(transcript)
+	if len(tokens.values) == 0 && len(latency.values) == 0 && len(scores.values) == 0 {
+		return
+	}
+	sb.WriteString("\n## Run Metrics\n\n")
+	if transcript.Tokens > 0 {
+		fmt.Fprintf(sb, "- **Tokens:** %d in total\n", transcript.Tokens)
+	}
+	if len(tokens.values) > 0 {
+		var sum float64
+		for _, v := range tokens.values {
+			sum += v
+		}
+		fmt.Fprintf(sb, "- **Turn tokens:** %.0f over %d round(s)\n", sum, len(tokens.values))
+	}
+	if len(latency.values) > 0 {
+		i := slices.Index(latency.values, slices.Max(latency.values))
+		fmt.Fprintf(sb, "- **Slowest agent:** %s, %.0f ms per turn on average\n", latency.labels[i], latency.values[i])
+	}
+	fmt.Fprintf(sb, "\nCharts of tokens per round, latency per agent and the consensus score over time are in `%s`.\n", metricsFile)
+}
+
+// writeBarChart renders s as vertical bars scaled to its largest value,
+// each labelled below and with its value, formatted by format, above.
+func writeBarChart(sb *strings.Builder, s series, format string) {
+	top := slices.Max(s.values)
+	if top <= 0 {
+		top = 1
+	}
+	width := 2*chartMargin + chartBar*len(s.values)
+	fmt.Fprintf(sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\">\n", width, chartHeight+2*chartMargin)
+	writeAxes(sb, width)
+	for i, v := range s.values {
+		h := int(v / top * chartHeight)
+		x := chartMargin + chartBar*i + chartBar/8
+		y := chartMargin + chartHeight - h
+		value := fmt.Sprintf(format, v)
+		fmt.Fprintf(sb, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#4a7bd0\"><title>%s: %s</title></rect>\n",
+			x, y, chartBar*3/4, h, html.EscapeString(s.labels[i]), value)
+		fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%s</text>\n", x+chartBar*3/8, y-4, value)
+		writeXLabel(sb, i, s.labels[i])
+	}
+	sb.WriteString("</svg>\n")
+}
+
+// writeLineChart renders s as a line over a 0 to top scale, one point per
+// value.
+func writeLineChart(sb *strings.Builder, s series, top float64) {
+	width := 2*chartMargin + chartBar*len(s.values)
+	fmt.Fprintf(sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\">\n", width, chartHeight+2*chartMargin)
+	writeAxes(sb, width)
+	fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\" text-anchor=\"end\">%.0f</text>\n", chartMargin-4, chartMargin+4, top)
+	points := make([]string, len(s.values))
+	for i, v := range s.values {
+		x := chartMargin + chartBar*i + chartBar/2
+		y := chartMargin + chartHeight - int(min(v, top)/top*chartHeight)
+		points[i] = fmt.Sprintf("%d,%d", x, y)
+		fmt.Fprintf(sb, "<circle cx=\"%d\" cy=\"%d\" r=\"4\" fill=\"#d04a4a\"><title>%s: %.0f</title></circle>\n", x, y, html.EscapeString(s.labels[i]), v)
+		writeXLabel(sb, i, s.labels[i])
+	}
+	fmt.Fprintf(sb, "<polyline points=\"%s\" fill=\"none\" stroke=\"#d04a4a\" stroke-width=\"2\"/>\n", strings.Join(points, " "))
+	sb.WriteString("</svg>\n")
+}
+
+// writeAxes draws the axes of a chart width pixels wide, with 0 at the
+// origin.
+func writeAxes(sb *strings.Builder, width int) {
+	bottom := chartMargin + chartHeight
+	fmt.Fprintf(sb, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#888\"/>\n", chartMargin, chartMargin, chartMargin, bottom)
+	fmt.Fprintf(sb, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#888\"/>\n", chartMargin, bottom, width-chartMargin, bottom)
+	fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\" text-anchor=\"end\">0</text>\n", chartMargin-4, bottom)
+}
+
+// writeXLabel labels the i-th bar or point of a chart.
+func writeXLabel(sb *strings.Builder, i int, label string) {
+	fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%s</text>\n",
+		chartMargin+chartBar*i+chartBar/2, chartMargin+chartHeight+16, html.EscapeString(label))
+}
@@ -1,15 +1,25 @@
 package output
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/redact"
 )
 
 func TestGenerateSlug(t *testing.T) {
@@ -28,11 +38,108 @@ func TestGenerateSlugMaxLength(t *testing.T) {
 	}
 }
 
+func TestGenerateSlugPortable(t *testing.T) {
+	for topic, want := range map[string]string{
+		"Café con leche, ¿sí o no?": "cafe-con-leche-si-o-no",
+		"Cafe\u0301 decomposed":     "cafe-decomposed",
+		"Straße über Æsir":          "strasse-uber-aesir",
+		"Должны ли мы мигрировать":  "должны-ли-мы-мигрировать",
+		"マイクロサービスは必要か":              "マイクロサービスは必要か",
+		"?!…":                       "untitled",
+		"CON":                       "_con",
+		"lpt1":                      "_lpt1",
+		"console":                   "console",
+	} {
+		if got := GenerateSlug(topic); got != want {
+			t.Errorf("GenerateSlug(%q) = %q, want %q", topic, got, want)
+		}
+	}
+	long := GenerateSlug(strings.Repeat("é日", 40))
+	if len(long) > 50 || !utf8.ValidString(long) {
+		t.Errorf("GenerateSlug() = %q, want valid UTF-8 of at most 50 bytes", long)
+	}
+}
+
+func TestCreateOutputDirSanitizesName(t *testing.T) {
+	base := t.TempDir()
+	dir, err := CreateOutputDir(base, `aux.q3: "plan"?. `, LayoutFlat, "")
+	if err != nil {
+		t.Fatalf("CreateOutputDir() error = %v", err)
+	}
+	if got := filepath.Base(dir); !regexp.MustCompile(`^_aux\.q3- -plan-\d{8}-\d{6}$`).MatchString(got) {
+		t.Errorf("unexpected run directory %q", got)
+	}
+}
+
+func TestCreateOutputDirFitsMaxPath(t *testing.T) {
+	base := filepath.Join(t.TempDir(), strings.Repeat("d", 120))
+	dir, err := CreateOutputDir(base, strings.Repeat("slug-", 20), LayoutFlat, "")
+	if err != nil {
+		t.Fatalf("CreateOutputDir() error = %v", err)
+	}
+	abs, _ := filepath.Abs(dir)
+	if len(abs)+artifactNameReserve > maxPathLength {
+		t.Errorf("run directory %q leaves no room for artifacts within MAX_PATH", abs)
+	}
+	if !strings.HasPrefix(filepath.Base(dir), "slug-") {
+		t.Errorf("expected the slug shortened, not dropped, got %q", filepath.Base(dir))
+	}
+}
+
+func TestCreateOutputDirNested(t *testing.T) {
+	base := t.TempDir()
+	dir, err := CreateOutputDir(base, "topic", LayoutNested, "")
+	if err != nil {
+		t.Fatalf("CreateOutputDir() error = %v", err)
+	}
+	rel, _ := filepath.Rel(base, dir)
+	if !regexp.MustCompile(`^\d{4}-\d{2}[/\\]topic-\d{8}-\d{6}$`).MatchString(rel) {
+		t.Errorf("expected <YYYY-MM>/<slug>-<timestamp>, got %q", rel)
+	}
+	if _, err := CreateOutputDir(base, "topic", "deep", ""); err == nil {
+		t.Error("expected an unknown layout to be rejected")
+	}
+}
+
+func TestCreateOutputDirTemplate(t *testing.T) {
+	base := t.TempDir()
+	for _, want := range []string{"billing/topic-001", "billing/topic-002"} {
+		dir, err := CreateOutputDir(base, "topic", "{project}/{slug}-{seq}", "billing")
+		if err != nil {
+			t.Fatalf("CreateOutputDir() error = %v", err)
+		}
+		if rel, _ := filepath.Rel(base, dir); filepath.ToSlash(rel) != want {
+			t.Errorf("got %q, want %q", rel, want)
+		}
+	}
+
+	dir, err := CreateOutputDir(base, "topic", "{date}/{slug}", "")
+	if err != nil {
+		t.Fatalf("CreateOutputDir() error = %v", err)
+	}
+	again, err := CreateOutputDir(base, "topic", "{date}/{slug}", "")
+	if err != nil {
+		t.Fatalf("CreateOutputDir() error = %v", err)
+	}
+	if again != dir+"-2" {
+		t.Errorf("expected an existing run directory never to be reused, got %q after %q", again, dir)
+	}
+	if dir, _ := CreateOutputDir(base, "topic", "{project}/{slug}", ""); filepath.Base(filepath.Dir(dir)) != "default" {
+		t.Errorf("expected runs without a project filed under default, got %q", dir)
+	}
+
+	for _, bad := range []string{"{slug}-{uuid}", "{seq}/{slug}", "{slug}-{seq}-{seq}", "by-date"} {
+		if err := ValidateLayout(bad); err == nil {
+			t.Errorf("ValidateLayout(%q) expected error", bad)
+		}
+	}
+}
+
 func TestCreateOutputDir(t *testing.T) {
 	base := t.TempDir()
 	slug := "test-topic"
 
-	dir, err := CreateOutputDir(base, slug)
+	dir, err := CreateOutputDir(base, slug, LayoutFlat, "")
 	if err != nil {
 		t.Fatalf("CreateOutputDir() error = %v", err)
 	}
@@ -115,7 +222,7 @@ func TestWriteMarkdown(t *testing.T) {
 		Dissenters: []string{"Charlie"},
 	}
 
-	err := w.WriteMarkdown(transcript, consensus)
+	err := w.WriteMarkdown(transcript, consensus, nil)
 	if err != nil {
 		t.Fatalf("WriteMarkdown() error = %v", err)
 	}
@@ -135,6 +242,35 @@ func TestWriteMarkdown(t *testing.T) {
 	}
 }
 
+func TestWriteMarkdownAgreementHeatmap(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	transcript := &debate.Transcript{
+		Topic: "Heatmap",
+		Turns: []debate.Turn{
+			{Round: 1, Agent: debate.Agent{Name: "Bob"}, Content: "x"},
+			{Round: 1, Agent: debate.Agent{Name: "Alice"}, Content: "y"},
+		},
+		Rounds: 2,
+		Agreement: []debate.RoundAgreement{
+			{Round: 1, Scores: map[string]int{"Alice": 2, "Bob": 9}},
+			{Round: 2, Scores: map[string]int{"Alice": 5}},
+		},
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	want := "| Agent | R1 | R2 |\n|-------|----|----|\n| Bob | █ 9 | · |\n| Alice | ░ 2 | ▒ 5 |\n"
+	if !strings.Contains(string(data), "## Agreement Heatmap") || !strings.Contains(string(data), want) {
+		t.Errorf("expected the heatmap in speaking order, got:\n%s", data)
+	}
+}
+
 func TestWriteLog(t *testing.T) {
 	dir := t.TempDir()
 	w := NewWriter(dir)
@@ -178,6 +314,193 @@ func TestLogWritesImmediatelyToFile(t *testing.T) {
 	}
 }
 
+func TestLogIsSafeForConcurrentUse(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+	w.SetLogFormat(LogJSON)
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			w.Log(fmt.Sprintf("entry %d", i))
+		}()
+		go func() {
+			defer wg.Done()
+			w.Handle(debate.TurnCompleted{Turn: debate.Turn{ID: i + 1, Round: 1, Agent: debate.Agent{Name: "Alice"}, Content: "a point"}})
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(filepath.Join(dir, "debate.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2*n {
+		t.Fatalf("expected %d lines, got %d", 2*n, len(lines))
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("interleaved line: %s", line)
+		}
+	}
+	if err := w.WriteLog(); err != nil {
+		t.Fatal(err)
+	}
+	rewritten, _ := os.ReadFile(filepath.Join(dir, "debate.log"))
+	if len(rewritten) != len(data) {
+		t.Errorf("WriteLog() wrote %d bytes, want the %d appended", len(rewritten), len(data))
+	}
+}
+
+func TestLogJSONFormat(t *testing.T) {
+	dir := t.TempDir()
+	if got := DetectLogFormat(dir); got != LogText {
+		t.Errorf("a run without a log should default to text, got %q", got)
+	}
+	w := NewWriter(dir)
+	w.SetLogFormat(LogJSON)
+	w.Log("started")
+	w.LogEntry(LogEntry{Type: "turn", Round: 2, Agent: "Alice", Payload: map[string]int{"id": 4}})
+
+	data, err := os.ReadFile(filepath.Join(dir, "debate.log"))
+	if err != nil {
+		t.Fatalf("reading debate.log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", lines)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("line 1 is not JSON: %v", err)
+	}
+	if entry["type"] != "log" || entry["payload"].(map[string]any)["message"] != "started" || entry["timestamp"] == nil {
+		t.Errorf("unexpected free-text entry %q", lines[0])
+	}
+	if !strings.Contains(lines[1], `"type":"turn","round":2,"agent":"Alice","payload":{"id":4}`) {
+		t.Errorf("unexpected event entry %q", lines[1])
+	}
+	if got := DetectLogFormat(dir); got != LogJSON {
+		t.Errorf("DetectLogFormat = %q, want json", got)
+	}
+}
+
+func TestLogTextSkipsEventsWithoutMessage(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+	w.LogEntry(LogEntry{Type: "consensus", Payload: map[string]int{"score": 3}})
+	w.LogEntry(LogEntry{Type: "turn", Message: "[Round 1] Alice (m): hi"})
+	if err := w.WriteLog(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "debate.log"))
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 1 || !strings.HasSuffix(lines[0], " [Round 1] Alice (m): hi") {
+		t.Errorf("expected only the entry with a message, got %q", data)
+	}
+	if err := ValidateLogFormat("xml"); err == nil {
+		t.Error("expected an unknown log format to be rejected")
+	}
+}
+
+func TestMux(t *testing.T) {
+	var got []string
+	record := func(name string) Sink {
+		return SinkFunc(func(debate.Event) { got = append(got, name) })
+	}
+	mux := NewMux(record("a"))
+	mux.Add(record("b"))
+	mux.Add(&webhookSink{err: errors.New("down")})
+	mux.Handle(debate.PhaseChanged{})
+	if strings.Join(got, ",") != "a,b" {
+		t.Errorf("expected every sink in order, got %v", got)
+	}
+	if err := mux.Close(); err == nil || !strings.Contains(err.Error(), "down") {
+		t.Errorf("expected the failing sink's error, got %v", err)
+	}
+}
+
+func TestNewSinkErrors(t *testing.T) {
+	for _, spec := range []string{"", "kafka:topic", "file:", "webhook:ftp://host"} {
+		if _, err := NewSink(spec); err == nil {
+			t.Errorf("NewSink(%q) should fail", spec)
+		}
+	}
+}
+
+func sinkEvents() []debate.Event {
+	return []debate.Event{
+		debate.RoundStarted{RoundSummary: debate.RoundSummary{Round: 1}},
+		debate.TurnCompleted{Turn: debate.Turn{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: "hi"}},
+		debate.RoundEnded{RoundSummary: debate.RoundSummary{Round: 1}},
+		debate.ConsensusEvaluated{Result: &debate.ConsensusResult{Score: 4}},
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, err := NewSink("file:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ev := range sinkEvents() {
+		sink.Handle(ev)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"type":"turn","round":1,"agent":"Alice"`) || !strings.Contains(lines[1], `"type":"consensus","round":1,`) {
+		t.Errorf("unexpected events:\n%s", data)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var posted []string
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		posted = append(posted, string(body))
+		if fail {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	sink, err := NewSink("webhook:" + srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := sinkEvents()
+	sink.Handle(events[0])
+	sink.Handle(events[1])
+	fail = true
+	sink.Handle(events[3])
+	sink.Handle(events[1])
+	if len(posted) != 2 || !strings.Contains(posted[0], `"content":"hi"`) {
+		t.Errorf("expected posting to stop after the failure, got %q", posted)
+	}
+	if err := sink.Close(); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("expected the failure on Close, got %v", err)
+	}
+}
+
+func TestWriterIsSink(t *testing.T) {
+	dir := t.TempDir()
+	var sink Sink = NewWriter(dir)
+	for _, ev := range sinkEvents() {
+		sink.Handle(ev)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "debate.log"))
+	if !strings.HasSuffix(strings.TrimSpace(string(data)), "[Round 1] Alice (m): hi") || strings.Contains(string(data), "consensus") {
+		t.Errorf("expected only the turn in the text log, got %q", data)
+	}
+}
+
 func captureStdout(fn func()) string {
 	old := os.Stdout
 	r, w, _ := os.Pipe()
@@ -259,3 +582,653 @@ func TestPrintTurnShowsFullContent(t *testing.T) {
 		t.Error("PrintTurn should print full content")
 	}
 }
+
+func TestWriteMarkdownReplyThreads(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	alice := debate.Agent{ID: 1, Name: "Alice", Model: "model-a", Role: "debater"}
+	bob := debate.Agent{ID: 2, Name: "Bob", Model: "model-b", Role: "debater"}
+	transcript := &debate.Transcript{
+		Topic: "Threads",
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: alice, Content: "Opening claim"},
+			{ID: 2, Round: 1, Agent: bob, Content: "Rebuttal", InReplyTo: 1},
+			{ID: 3, Round: 2, Agent: alice, Content: "Counter-rebuttal", InReplyTo: 2},
+		},
+		Rounds: 2,
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	content := string(data)
+
+	for _, check := range []string{
+		"**[#2] Bob** (model-b), replying to #1 Alice: Rebuttal",
+		"## Reply Threads",
+		"- #1 **Alice** (round 1): Opening claim\n  - #2 **Bob** (round 1): Rebuttal\n    - #3 **Alice** (round 2): Counter-rebuttal\n",
+	} {
+		if !strings.Contains(content, check) {
+			t.Errorf("report.md does not contain %q:\n%s", check, content)
+		}
+	}
+}
+
+func TestWriteMarkdownMinorityReports(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	transcript := &debate.Transcript{Topic: "Minority", Rounds: 1}
+	minority := []debate.MinorityReport{
+		{Agent: debate.Agent{Name: "Bob", Model: "model-b"}, Objections: "Costs were never quantified."},
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{Dissenters: []string{"Bob"}}, minority); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	content := string(data)
+	section := strings.Index(content, "## Minority Reports\n\n### Bob (model-b)\n\nCosts were never quantified.")
+	if section < 0 {
+		t.Fatalf("report.md missing minority report:\n%s", content)
+	}
+	if section > strings.Index(content, "## Transcript") {
+		t.Error("minority reports should come before the transcript")
+	}
+}
+
+func TestWriteActions(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	items := &debate.ActionItems{
+		Actions:       []debate.Action{{Description: "Run a pilot", Rationale: "cheap to undo", RaisedBy: []string{"Alice", "Bob"}}},
+		OpenQuestions: []string{"Who owns it?"},
+		Research:      []string{},
+	}
+	if err := w.WriteActions("Pilot", items); err != nil {
+		t.Fatalf("WriteActions() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "actions.json"))
+	if err != nil {
+		t.Fatalf("reading actions.json: %v", err)
+	}
+	var got struct {
+		Topic string `json:"topic"`
+		debate.ActionItems
+	}
+	if err := json.Unmarshal(data, &got); err != nil || got.Topic != "Pilot" || len(got.Actions) != 1 || got.OpenQuestions[0] != "Who owns it?" {
+		t.Errorf("unexpected actions.json %s (%v)", data, err)
+	}
+	md, err := os.ReadFile(filepath.Join(dir, "actions.md"))
+	if err != nil {
+		t.Fatalf("reading actions.md: %v", err)
+	}
+	want := "# Action Items: Pilot\n\n## Recommended Actions\n\n- [ ] Run a pilot — cheap to undo *(Alice, Bob)*\n\n## Open Questions\n\n- [ ] Who owns it?\n"
+	if string(md) != want {
+		t.Errorf("unexpected actions.md:\n%s", md)
+	}
+}
+
+func TestWriteMarkdownReportTemplate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "brief.tmpl")
+	tmpl := `# {{upper .Topic}}
+Verdict: {{.Verdict}} ({{.Outcome}})
+Position: {{.Consensus.Position}} at {{.Consensus.Score}}/10
+Usage: {{.Usage.Rounds}} rounds, {{.Usage.Turns}} turns, {{.Usage.Tokens}} tokens, models {{join .Usage.Models ", "}}
+{{range .Minority}}Dissent from {{.Agent.Name}}: {{trim .Objections}}
+{{end}}`
+	if err := os.WriteFile(path, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := LoadReportTemplate(path)
+	if err != nil {
+		t.Fatalf("LoadReportTemplate() error = %v", err)
+	}
+
+	w := NewWriter(dir)
+	w.SetReportTemplate(report)
+	transcript := &debate.Transcript{
+		Topic: "Cache", Rounds: 2, Phase: debate.TenthManPhase, ConsensusPosition: "Add a cache.", Tokens: 1500,
+		Turns: []debate.Turn{
+			{Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m1"}},
+			{Round: 2, Agent: debate.Agent{Name: "Bob", Model: "m2"}},
+			{Round: 2, Agent: debate.Agent{Name: "Alice", Model: "m1"}},
+		},
+	}
+	consensus := &debate.ConsensusResult{Detected: true, Position: "Add a cache.", Score: 8}
+	minority := []debate.MinorityReport{{Agent: debate.Agent{Name: "Bob"}, Objections: " Staleness. "}}
+	if err := w.WriteMarkdown(transcript, consensus, minority); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	want := `# CACHE
+Verdict: upheld (consensus upheld: it survived the Tenth Man unchanged)
+Position: Add a cache. at 8/10
+Usage: 2 rounds, 3 turns, 1500 tokens, models m1, m2
+Dissent from Bob: Staleness.
+`
+	if string(data) != want {
+		t.Errorf("unexpected report.md:\n%s", data)
+	}
+
+	if err := os.WriteFile(path, []byte("{{.Nope}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bad, err := LoadReportTemplate(path)
+	if err != nil {
+		t.Fatalf("LoadReportTemplate() error = %v", err)
+	}
+	w.SetReportTemplate(bad)
+	if err := w.WriteMarkdown(transcript, consensus, nil); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	if err := os.WriteFile(path, []byte("{{if}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReportTemplate(path); err == nil {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestWriteMarkdownExecutiveSummary(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	transcript := &debate.Transcript{Topic: "Summary", Rounds: 1, Summary: []string{"The group agreed.", "Nobody objected.", "Ship it."}}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	want := "# Debate Report: Summary\n\n## Executive Summary\n\n- The group agreed.\n- Nobody objected.\n- Ship it.\n\n## Consensus"
+	if !strings.HasPrefix(string(data), want) {
+		t.Errorf("report.md should open with the executive summary:\n%s", data)
+	}
+}
+
+func TestWriteMarkdownPositionChange(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	transcript := &debate.Transcript{Topic: "Change", Rounds: 2, Phase: debate.TenthManPhase, PositionChange: &debate.PositionChange{
+		Before: "Ship it.", Changed: true, Summary: "Consensus was lost.",
+	}}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	if !strings.Contains(string(data), "- **Outcome:** consensus overturned by the Tenth Man\n") {
+		t.Errorf("report.md missing the outcome:\n%s", data)
+	}
+	want := "## Did the Tenth Man Change Anything?\n\n**Yes.** Consensus was lost.\n\n- **Before:** Ship it.\n- **After:** *(no consensus)*\n"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("report.md missing position change:\n%s", data)
+	}
+}
+
+func TestWriteMarkdownStatements(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	sixty, eighty := 60, 80
+	transcript := &debate.Transcript{
+		Topic: "Statements",
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: "Ship it.", Confidence: &sixty, Statement: debate.OpeningStatement},
+			{ID: 2, Round: 1, Agent: debate.Agent{Name: "Bob", Model: "m"}, Content: "Wait.", Statement: debate.OpeningStatement},
+			{ID: 3, Round: 2, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: "Still ship it."},
+			{ID: 4, Round: 3, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: "Ship it, with a rollback plan.", Confidence: &eighty, Statement: debate.ClosingStatement},
+			{ID: 5, Round: 3, Agent: debate.Agent{Name: "Bob", Model: "m"}, Content: "Ship it.", Confidence: &eighty, Statement: debate.ClosingStatement},
+		},
+		Rounds: 3,
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	want := "## Opening Statements\n\n**Alice [60%]** (m): Ship it.\n\n**Bob** (m): Wait.\n\n## Closing Statements\n\n**Alice [60% → 80%]** (m): Ship it, with a rollback plan.\n\n**Bob [80%]** (m): Ship it.\n"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("report.md missing the statements:\n%s", data)
+	}
+}
+
+func TestWriteMarkdownCrossExamination(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	transcript := &debate.Transcript{
+		Topic: "Questions",
+		Turns: []debate.Turn{
+			{ID: 1, Round: 2, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: "Why assume demand holds?"},
+			{ID: 2, Round: 2, Agent: debate.Agent{Name: "Bob", Model: "m"}, Content: "Because of contracts.\nBut they expire.", InReplyTo: 1},
+		},
+		Rounds:           2,
+		CrossExamination: []debate.Exchange{{Round: 2, Asker: "Alice", Answerer: "Bob", Question: 1, Answer: 2}},
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	want := "## Cross-Examination\n\n**Alice asks Bob (round 2):** Why assume demand holds?\n\n> Because of contracts.\n> But they expire.\n"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("report.md missing the cross-examination:\n%s", data)
+	}
+}
+
+func TestWriteDisagreement(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	transcript := &debate.Transcript{
+		Topic:  "Split <views>",
+		Rounds: 1,
+		Disagreement: []debate.RoundDisagreement{
+			{Round: 1, Agents: []string{"Alice", "Bob", "Tenth Man"}, Scores: [][]int{{0, 2, 8}, {2, 0, -1}, {8, -1, 0}}, Source: "judge"},
+		},
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if err := w.WriteDisagreement(transcript); err != nil {
+		t.Fatalf("WriteDisagreement() error = %v", err)
+	}
+	report, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	want := "| Agent | Mean disagreement |\n|---|---|\n| Tenth Man | 8.0 |\n| Alice | 5.0 |\n| Bob | 2.0 |\n\n**Natural dissenter:** Tenth Man\n"
+	if !strings.Contains(string(report), want) {
+		t.Errorf("report.md missing the disagreement ranking:\n%s", report)
+	}
+	page, err := os.ReadFile(filepath.Join(dir, "disagreement.html"))
+	if err != nil {
+		t.Fatalf("reading disagreement.html: %v", err)
+	}
+	for _, want := range []string{"Split &lt;views&gt;", "<h2>Round 1</h2>", "<svg", "rgb(255,95,95)", "#ddd", "Alice / Tenth Man: 8/10"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("disagreement.html missing %q", want)
+		}
+	}
+}
+
+func TestWriteDisagreementSkipsWithoutData(t *testing.T) {
+	dir := t.TempDir()
+	if err := NewWriter(dir).WriteDisagreement(&debate.Transcript{Topic: "t"}); err != nil {
+		t.Fatalf("WriteDisagreement() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "disagreement.html")); !os.IsNotExist(err) {
+		t.Errorf("expected no disagreement.html, got %v", err)
+	}
+}
+
+func TestWriterRedacts(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+	w.SetLogFormat(LogJSON)
+	redactor, err := redact.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.SetRedactor(redactor)
+
+	transcript := &debate.Transcript{
+		Topic:  "Should we rotate the key for ops@example.com?",
+		Rounds: 1,
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: `It says "password: hunter2hunter2" on db.prod.internal`},
+		},
+	}
+	w.Log("Contacted ops@example.com")
+	if err := w.WriteJSON(transcript); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	for _, name := range []string{"transcript.json", "report.md", "debate.log"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		for _, secret := range []string{"ops@example.com", "hunter2", "db.prod.internal"} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s leaks %q:\n%s", name, secret, data)
+			}
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "transcript.json"))
+	var got debate.Transcript
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("transcript.json is no longer valid JSON: %v", err)
+	}
+	if got.Turns[0].Content != `It says "password: [REDACTED:secret]" on [REDACTED:hostname]` {
+		t.Errorf("unexpected redacted turn %q", got.Turns[0].Content)
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	transcript := &debate.Transcript{
+		Topic:  "Speed & cost",
+		Rounds: 2,
+		Tokens: 900,
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: "a", Tokens: 100, LatencyMS: 400},
+			{ID: 2, Round: 1, Agent: debate.Agent{Name: "Bob", Model: "m"}, Content: "b", Tokens: 200, LatencyMS: 1000},
+			{ID: 3, Round: 2, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: "c", Tokens: 300, LatencyMS: 600},
+		},
+		ConsensusScores: []debate.RoundScore{{Round: 1, Score: 4}, {Round: 2, Score: 8, Detected: true}},
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if err := w.WriteMetrics(transcript); err != nil {
+		t.Fatalf("WriteMetrics() error = %v", err)
+	}
+	report, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	want := "## Run Metrics\n\n- **Tokens:** 900 in total\n- **Turn tokens:** 600 over 2 round(s)\n- **Slowest agent:** Bob, 1000 ms per turn on average\n"
+	if !strings.Contains(string(report), want) {
+		t.Errorf("report.md missing the run metrics:\n%s", report)
+	}
+	page, err := os.ReadFile(filepath.Join(dir, "metrics.html"))
+	if err != nil {
+		t.Fatalf("reading metrics.html: %v", err)
+	}
+	for _, want := range []string{"Speed &amp; cost", "Turn tokens per round", "<title>R1: 300</title>", "<title>Alice: 500</title>", "<polyline", "<title>R2: 8</title>"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("metrics.html missing %q", want)
+		}
+	}
+}
+
+func TestWriteMetricsSkipsWithoutData(t *testing.T) {
+	dir := t.TempDir()
+	transcript := &debate.Transcript{Topic: "t", Turns: []debate.Turn{{ID: 1, Round: 1, Content: "old run"}}}
+	if err := NewWriter(dir).WriteMetrics(transcript); err != nil {
+		t.Fatalf("WriteMetrics() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "metrics.html")); !os.IsNotExist(err) {
+		t.Errorf("expected no metrics.html, got %v", err)
+	}
+}
+
+func TestWriteMarkdownFactCheck(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	transcript := &debate.Transcript{
+		Topic:  "Facts",
+		Rounds: 1,
+		FactChecks: []debate.FactCheck{
+			{Claim: "Redis was released in 2009", Verdict: "verified"},
+			{Claim: "Caching cut latency by 40%", Verdict: "unverifiable", Note: "No source found."},
+		},
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{Position: "Cache."}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	want := "## Fact Check\n\nOf 2 factual claims in the consensus, 1 verified, 0 disputed and 1 unverifiable.\n\n- **Verified:** Redis was released in 2009\n- **Unverifiable:** Caching cut latency by 40% — No source found.\n"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("report.md missing the fact check:\n%s", data)
+	}
+}
+
+func TestWriteMarkdownReasoningQuality(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	transcript := &debate.Transcript{
+		Topic: "Quality",
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m", Role: "debater"}, Content: "Experts agree."},
+			{ID: 2, Round: 1, Agent: debate.Agent{Name: "Bob", Model: "m", Role: "debater"}, Content: "We already paid for it."},
+		},
+		Rounds: 1,
+		ReasoningFlags: []debate.ReasoningFlag{
+			{TurnID: 1, Agent: "Alice", Kind: "appeal to authority", Category: "fallacy", Excerpt: "Experts agree.", Explanation: "No evidence."},
+			{TurnID: 2, Agent: "Bob", Kind: "sunk cost", Category: "bias"},
+		},
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	for _, want := range []string{
+		"**[#1] Alice [⚠ appeal to authority]** (m): Experts agree.",
+		"## Reasoning Quality\n\n| Agent | Turns | Fallacies | Biases |\n|---|---|---|---|\n| Alice | 1 | 1 | 0 |\n| Bob | 1 | 0 | 1 |\n",
+		"- #1 **Alice** — appeal to authority (fallacy): \"Experts agree.\" No evidence.\n- #2 **Bob** — sunk cost (bias)\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report.md missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestWriteMarkdownConfidenceChart(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	conf := 70
+	transcript := &debate.Transcript{
+		Topic:      "Confidence",
+		Turns:      []debate.Turn{{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: "Yes", Confidence: &conf}},
+		Rounds:     1,
+		Confidence: []debate.RoundConfidence{{Round: 1, Mean: 70, Spread: 5, Reported: 2}},
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatalf("reading report.md: %v", err)
+	}
+	content := string(data)
+	for _, check := range []string{
+		"## Group Confidence",
+		"Round  1 ██████████████░░░░░░  70 ±5 (2 reported)",
+		"**[#1] Alice [70%]** (m): Yes",
+	} {
+		if !strings.Contains(content, check) {
+			t.Errorf("report.md does not contain %q:\n%s", check, content)
+		}
+	}
+}
+
+func TestWriteClaims(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	if err := w.WriteClaims("Topic", nil); err != nil {
+		t.Fatalf("WriteClaims() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "claims.json"))
+	if err != nil {
+		t.Fatalf("reading claims.json: %v", err)
+	}
+	var got struct {
+		Topic  string         `json:"topic"`
+		Claims []debate.Claim `json:"claims"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.Topic != "Topic" || got.Claims == nil {
+		t.Errorf("expected topic and an empty claims list, got %s", data)
+	}
+}
+
+func TestCompressArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+	transcript := &debate.Transcript{Topic: "Compressed", Rounds: 1}
+	if err := w.WriteJSON(transcript); err != nil {
+		t.Fatal(err)
+	}
+	w.Log("entry")
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CompressArtifacts(dir, CompressGzip); err != nil {
+		t.Fatalf("CompressArtifacts() error = %v", err)
+	}
+	for _, name := range []string{"transcript.json", "debate.log"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should be replaced by its compressed copy", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "report.md")); err != nil {
+		t.Errorf("report.md should be left as-is: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "transcript.json.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got debate.Transcript
+	if err := json.NewDecoder(zr).Decode(&got); err != nil || got.Topic != "Compressed" {
+		t.Errorf("decompressed transcript = %+v, %v", got, err)
+	}
+}
+
+func TestValidateCompression(t *testing.T) {
+	for _, ok := range []string{"", "gzip", "zstd"} {
+		if err := ValidateCompression(ok); err != nil {
+			t.Errorf("ValidateCompression(%q) = %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"brotli", "Gzip"} {
+		if err := ValidateCompression(bad); err == nil {
+			t.Errorf("ValidateCompression(%q) expected error", bad)
+		}
+	}
+}
+
+func TestEncryptArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+	transcript := &debate.Transcript{Topic: "Encrypted", Rounds: 1}
+	if err := w.WriteJSON(transcript); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteMarkdown(transcript, &debate.ConsensusResult{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stand in for age with a copy, recording the arguments it was given.
+	var tools []string
+	w.encryptCommand = func(ctx context.Context, tool string, recipients []string, path, out string) *exec.Cmd {
+		tools = append(tools, tool+" "+strings.Join(recipients, ","))
+		return exec.CommandContext(ctx, "cp", path, out)
+	}
+	if err := w.EncryptArtifacts(context.Background(), []string{"age1alice", "ssh-ed25519 AAAAbob"}); err != nil {
+		t.Fatalf("EncryptArtifacts() error = %v", err)
+	}
+	for _, name := range []string{"transcript.json", "report.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should be replaced by its encrypted copy", name)
+		}
+		if _, err := os.Stat(filepath.Join(dir, name+".age")); err != nil {
+			t.Errorf("expected %s.age: %v", name, err)
+		}
+	}
+	if len(tools) != 2 || tools[0] != "age age1alice,ssh-ed25519 AAAAbob" {
+		t.Errorf("unexpected encryption calls %q", tools)
+	}
+	if !Encrypted(dir) {
+		t.Error("expected the run to be reported encrypted")
+	}
+
+	// Encrypting again leaves the encrypted copies alone.
+	tools = nil
+	if err := w.EncryptArtifacts(context.Background(), []string{"ops@example.com"}); err != nil || len(tools) != 0 {
+		t.Errorf("expected nothing left to encrypt, got %q, %v", tools, err)
+	}
+}
+
+func TestValidateRecipients(t *testing.T) {
+	for _, ok := range [][]string{nil, {"age1abc", "ssh-rsa AAAA"}, {"ops@example.com", "0xDEADBEEF"}} {
+		if err := ValidateRecipients(ok); err != nil {
+			t.Errorf("ValidateRecipients(%q) = %v", ok, err)
+		}
+	}
+	for _, bad := range [][]string{{"age1abc", "ops@example.com"}, {" "}} {
+		if err := ValidateRecipients(bad); err == nil {
+			t.Errorf("ValidateRecipients(%q) expected error", bad)
+		}
+	}
+}
+
+func TestDecompressArtifacts(t *testing.T) {
+	for _, c := range compressions {
+		t.Run(c.format, func(t *testing.T) {
+			dir := t.TempDir()
+			w := NewWriter(dir)
+			if err := w.WriteJSON(&debate.Transcript{Topic: "Round trip"}); err != nil {
+				t.Fatal(err)
+			}
+			w.Log("entry")
+			if err := CompressArtifacts(dir, c.format); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "transcript.json"+c.suffix)); err != nil {
+				t.Fatalf("expected transcript.json%s: %v", c.suffix, err)
+			}
+
+			format, err := DecompressArtifacts(dir)
+			if err != nil || format != c.format {
+				t.Fatalf("DecompressArtifacts() = %q, %v; want %s", format, err, c.format)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "transcript.json"))
+			if err != nil || !strings.Contains(string(data), "Round trip") {
+				t.Errorf("transcript.json not restored: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "debate.log"+c.suffix)); !os.IsNotExist(err) {
+				t.Errorf("debate.log%s should be removed after decompressing", c.suffix)
+			}
+
+			if format, err := DecompressArtifacts(dir); err != nil || format != CompressNone {
+				t.Errorf("uncompressed run: DecompressArtifacts() = %q, %v", format, err)
+			}
+		})
+	}
+}
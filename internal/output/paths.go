@@ -0,0 +1,270 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Named output directory layouts. Any other layout is a template such as
+// "{date}/{slug}" or "{project}/{slug}-{seq}"; see CreateOutputDir.
+const (
+	LayoutFlat   = "flat"   // <base>/<slug>-<timestamp>, the default
+	LayoutNested = "nested" // <base>/<YYYY-MM>/<slug>-<timestamp>, grouped by month
+)
+
+// layoutPresets are the templates the named layouts stand for.
+var layoutPresets = map[string]string{
+	"":           "{slug}-{timestamp}",
+	LayoutFlat:   "{slug}-{timestamp}",
+	LayoutNested: "{month}/{slug}-{timestamp}",
+}
+
+// layoutFieldRe matches a field of a layout template.
+var layoutFieldRe = regexp.MustCompile(`\{[a-z]+\}`)
+
+// layoutFields are the fields a layout template may use.
+var layoutFields = map[string]bool{
+	"{slug}": true, "{project}": true, "{date}": true, "{month}": true,
+	"{year}": true, "{timestamp}": true, "{seq}": true,
+}
+
+const (
+	maxSlugLength = 50
+	// maxPathLength is the longest path Windows accepts without long path
+	// support (MAX_PATH, 260 including the terminating NUL).
+	maxPathLength = 259
+	// artifactNameReserve is room left in a run directory's path for the
+	// longest artifact name, such as "transcript.json.gz.gpg", and a
+	// separator.
+	artifactNameReserve = 32
+	// untitledSlug names runs whose topic leaves nothing to slug.
+	untitledSlug = "untitled"
+	// defaultProject fills {project} for runs without a project tag.
+	defaultProject = "default"
+)
+
+// latinFold spells accented Latin letters in ASCII, so "Café" slugs as
+// "cafe" rather than "caf".
+var latinFold = map[rune]string{}
+
+func init() {
+	for ascii, letters := range map[string]string{
+		"a": "àáâãäåāăą", "c": "çćĉċč", "d": "ďđð", "e": "èéêëēĕėęě",
+		"g": "ĝğġģ", "h": "ĥħ", "i": "ìíîïĩīĭįı", "j": "ĵ", "k": "ķ",
+		"l": "ĺļľŀł", "n": "ñńņňŉ", "o": "òóôõöøōŏő", "r": "ŕŗř",
+		"s": "śŝşšș", "t": "ţťŧț", "u": "ùúûüũūŭůűų", "w": "ŵ", "y": "ýÿŷ",
+		"z": "źżž", "ss": "ß", "ae": "æ", "oe": "œ", "th": "þ",
+	} {
+		for _, r := range letters {
+			latinFold[r] = ascii
+		}
+	}
+}
+
+// windowsReserved are the device names Windows refuses as file or directory
+// names, with or without an extension.
+var windowsReserved = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true, "com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true, "lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// ValidateLayout reports whether layout is LayoutFlat, LayoutNested or a
+// valid template. "" is LayoutFlat.
+func ValidateLayout(layout string) error {
+	_, err := layoutTemplate(layout)
+	return err
+}
+
+// layoutTemplate returns the template layout stands for.
+func layoutTemplate(layout string) (string, error) {
+	if tmpl, ok := layoutPresets[layout]; ok {
+		return tmpl, nil
+	}
+	if !strings.Contains(layout, "{") {
+		return "", fmt.Errorf("output: unknown layout %q (want %s, %s or a template such as {date}/{slug})", layout, LayoutFlat, LayoutNested)
+	}
+	for _, field := range layoutFieldRe.FindAllString(layout, -1) {
+		if !layoutFields[field] {
+			return "", fmt.Errorf("output: layout %q: unknown field %s", layout, field)
+		}
+	}
+	segments := strings.Split(strings.Trim(layout, "/"), "/")
+	for _, seg := range segments[:len(segments)-1] {
+		if strings.Contains(seg, "{seq}") {
+			return "", fmt.Errorf("output: layout %q: {seq} can only be used in the last folder", layout)
+		}
+	}
+	if strings.Count(segments[len(segments)-1], "{seq}") > 1 {
+		return "", fmt.Errorf("output: layout %q: {seq} can only be used once", layout)
+	}
+	return layout, nil
+}
+
+// GenerateSlug converts a topic into a lowercase, hyphen-separated folder
+// name that is valid on Windows as well as POSIX systems. Accented Latin
+// letters are spelled in ASCII, other letters and digits are kept, and a
+// topic with none of them slugs as "untitled".
+func GenerateSlug(topic string) string {
+	var sb strings.Builder
+	lastHyphen := true
+	for _, r := range strings.ToLower(topic) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining accents of decomposed letters are dropped.
+		case latinFold[r] != "":
+			sb.WriteString(latinFold[r])
+			lastHyphen = false
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') ||
+			(r >= utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))):
+			sb.WriteRune(r)
+			lastHyphen = false
+		case !lastHyphen:
+			sb.WriteByte('-')
+			lastHyphen = true
+		}
+	}
+	return safeName(truncate(strings.Trim(sb.String(), "-"), maxSlugLength))
+}
+
+// safeName makes name, a slug or a user-chosen folder name, valid on
+// Windows: characters Windows forbids become hyphens, trailing dots, spaces
+// and hyphens are dropped and reserved device names such as "con" are
+// prefixed with an underscore.
+func safeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	name = strings.TrimRight(strings.TrimSpace(name), ". -")
+	if name == "" {
+		return untitledSlug
+	}
+	stem, _, _ := strings.Cut(name, ".")
+	if windowsReserved[strings.ToLower(stem)] {
+		name = "_" + name
+	}
+	return name
+}
+
+// truncate shortens s to at most n bytes without splitting a character or
+// leaving a trailing hyphen.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return strings.TrimRight(s[:n], "-")
+}
+
+// CreateOutputDir creates a run directory under base laid out by layout, a
+// named layout or a template of folders separated by "/" using the fields
+// {slug}, {project} (project, or "default" when empty), {date}
+// (YYYY-MM-DD), {month} (YYYY-MM), {year}, {timestamp} (YYYYMMDD-HHMMSS)
+// and, in the last folder, {seq}, the next free three-digit number there.
+// Every folder is made valid on Windows, slug is shortened so the
+// artifacts' paths stay within MAX_PATH, and a directory that already exists
+// is never reused: the next {seq} or a -2, -3, ... suffix is taken instead.
+func CreateOutputDir(base, slug, layout, project string) (string, error) {
+	tmpl, err := layoutTemplate(layout)
+	if err != nil {
+		return "", err
+	}
+	if project == "" {
+		project = defaultProject
+	}
+	now := time.Now()
+	fields := map[string]string{
+		"{project}":   project,
+		"{date}":      now.Format("2006-01-02"),
+		"{month}":     now.Format("2006-01"),
+		"{year}":      now.Format("2006"),
+		"{timestamp}": now.Format("20060102-150405"),
+		"{seq}":       "{seq}", // resolved once the parent folder exists
+	}
+	render := func(slug string) []string {
+		fields["{slug}"] = slug
+		var segments []string
+		for _, seg := range strings.Split(tmpl, "/") {
+			if seg = layoutFieldRe.ReplaceAllStringFunc(seg, func(f string) string { return fields[f] }); seg != "" {
+				segments = append(segments, seg)
+			}
+		}
+		return segments
+	}
+
+	slug = safeName(slug)
+	if n := strings.Count(tmpl, "{slug}"); n > 0 {
+		if abs, err := filepath.Abs(base); err == nil {
+			fixed := len(abs)
+			for _, seg := range render("") {
+				fixed += len(string(filepath.Separator)) + len(seg)
+			}
+			room := (maxPathLength - artifactNameReserve - fixed) / n
+			if short := truncate(slug, max(room, 1)); short != "" {
+				slug = safeName(short)
+			}
+		}
+	}
+	segments := render(slug)
+	for i := range segments {
+		segments[i] = safeName(segments[i])
+	}
+
+	parent := filepath.Join(append([]string{base}, segments[:len(segments)-1]...)...)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return "", fmt.Errorf("output: %w", err)
+	}
+	last := segments[len(segments)-1]
+	prefix, suffix, hasSeq := strings.Cut(last, "{seq}")
+	seq := 0
+	if hasSeq {
+		seq = lastSeq(parent, prefix, suffix)
+	}
+	for attempt := 1; ; attempt++ {
+		name := last
+		if hasSeq {
+			seq++
+			name = fmt.Sprintf("%s%03d%s", prefix, seq, suffix)
+		} else if attempt > 1 {
+			name = fmt.Sprintf("%s-%d", last, attempt)
+		}
+		dir := filepath.Join(parent, name)
+		err := os.Mkdir(dir, 0o755)
+		if err == nil {
+			return dir, nil
+		}
+		if !os.IsExist(err) {
+			return "", fmt.Errorf("output: %w", err)
+		}
+	}
+}
+
+// lastSeq returns the highest number between prefix and suffix among the
+// entries of dir, 0 if there is none.
+func lastSeq(dir, prefix, suffix string) int {
+	entries, _ := os.ReadDir(dir)
+	highest := 0
+	for _, e := range entries {
+		rest, ok := strings.CutPrefix(e.Name(), prefix)
+		middle, ok2 := strings.CutSuffix(rest, suffix)
+		if !ok || !ok2 {
+			continue
+		}
+		if n, err := strconv.Atoi(middle); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest
+}
@@ -0,0 +1,190 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// Sink receives the events of a running debate.
+type Sink interface {
+	// Handle is called with every event, in order, from a single goroutine.
+	Handle(ev debate.Event)
+	// Close is called once the debate is done. It reports whether the sink
+	// failed to deliver events.
+	Close() error
+}
+
+// SinkFunc adapts a function to a Sink that never fails.
+type SinkFunc func(debate.Event)
+
+// Handle implements Sink.
+func (f SinkFunc) Handle(ev debate.Event) { f(ev) }
+
+// Close implements Sink.
+func (f SinkFunc) Close() error { return nil }
+
+// Mux fans events out to several sinks, in the order they were added.
+type Mux struct {
+	sinks []Sink
+}
+
+// NewMux creates a Mux delivering to sinks.
+func NewMux(sinks ...Sink) *Mux {
+	return &Mux{sinks: sinks}
+}
+
+// Add appends s to the sinks.
+func (m *Mux) Add(s Sink) {
+	m.sinks = append(m.sinks, s)
+}
+
+// Handle implements Sink.
+func (m *Mux) Handle(ev debate.Event) {
+	for _, s := range m.sinks {
+		s.Handle(ev)
+	}
+}
+
+// Close implements Sink. It closes every sink and joins their errors.
+func (m *Mux) Close() error {
+	var errs []error
+	for _, s := range m.sinks {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewSink returns the sink for spec:
+//
+//   - terminal prints turns and phase banners, as the CLI does.
+//   - stdout writes every event to stdout as a JSON line.
+//   - file:<path> appends every event to path as a JSON line.
+//   - webhook:<url> posts every event as JSON to url.
+//
+// Events are encoded as in the JSON format of debate.log, including those
+// the text format leaves out.
+func NewSink(spec string) (Sink, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "terminal":
+		return SinkFunc(PrintEvent), nil
+	case "stdout":
+		return &jsonLinesSink{out: os.Stdout}, nil
+	case "file":
+		if arg == "" {
+			return nil, fmt.Errorf("output: sink %q has no path", spec)
+		}
+		return &jsonLinesSink{path: arg}, nil
+	case "webhook":
+		if !strings.HasPrefix(arg, "http://") && !strings.HasPrefix(arg, "https://") {
+			return nil, fmt.Errorf("output: sink %q needs an http:// or https:// URL", spec)
+		}
+		return &webhookSink{httpClient: &http.Client{Timeout: 10 * time.Second}, url: arg}, nil
+	default:
+		return nil, fmt.Errorf("output: unknown sink %q (want terminal, stdout, file:<path> or webhook:<url>)", spec)
+	}
+}
+
+// Handle implements Sink by logging ev to debate.log.
+func (w *Writer) Handle(ev debate.Event) {
+	if entry, ok := w.events.entry(ev); ok {
+		w.LogEntry(entry)
+	}
+}
+
+// Close implements Sink. debate.log is written as events arrive, so there
+// is nothing left to do.
+func (w *Writer) Close() error {
+	return nil
+}
+
+// jsonLinesSink writes events as JSON lines to out, or appends them to the
+// file at path, which is opened on the first event.
+type jsonLinesSink struct {
+	out    io.Writer
+	path   string
+	file   *os.File
+	events eventLog
+	err    error
+}
+
+func (s *jsonLinesSink) Handle(ev debate.Event) {
+	entry, ok := s.events.entry(ev)
+	if !ok || s.err != nil {
+		return
+	}
+	if s.out == nil {
+		if s.file, s.err = os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); s.err != nil {
+			s.err = fmt.Errorf("output: sink: %w", s.err)
+			return
+		}
+		s.out = s.file
+	}
+	data, err := json.Marshal(entry)
+	if err == nil {
+		_, err = fmt.Fprintf(s.out, "%s\n", data)
+	}
+	if err != nil {
+		s.err = fmt.Errorf("output: sink: %w", err)
+	}
+}
+
+func (s *jsonLinesSink) Close() error {
+	if s.file != nil {
+		if err := s.file.Close(); err != nil && s.err == nil {
+			s.err = fmt.Errorf("output: sink: %w", err)
+		}
+	}
+	return s.err
+}
+
+// webhookSink posts events as JSON. After the first failure it stops
+// posting, so an unreachable endpoint does not slow the debate down.
+type webhookSink struct {
+	httpClient *http.Client
+	url        string
+	events     eventLog
+	err        error
+}
+
+func (s *webhookSink) Handle(ev debate.Event) {
+	entry, ok := s.events.entry(ev)
+	if !ok || s.err != nil {
+		return
+	}
+	if err := s.post(entry); err != nil {
+		s.err = fmt.Errorf("output: webhook sink: %w", err)
+	}
+}
+
+func (s *webhookSink) post(entry LogEntry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	return s.err
+}
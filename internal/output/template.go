@@ -0,0 +1,82 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// ReportData is what a report template is executed with.
+type ReportData struct {
+	Topic      string
+	Transcript *debate.Transcript
+	Consensus  *debate.ConsensusResult // never nil
+	Verdict    debate.Verdict          // upheld, revised, overturned or no_consensus
+	Outcome    string                  // Verdict as a sentence
+	Minority   []debate.MinorityReport
+	Usage      Usage
+}
+
+// Usage is what a debate consumed.
+type Usage struct {
+	Rounds int
+	Turns  int
+	Tokens int      // 0 when the run was not metered
+	Models []string // every model that spoke, in order of first turn
+}
+
+// reportFuncs are the functions report templates may call besides the
+// text/template built-ins.
+var reportFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"trim":    strings.TrimSpace,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"percent": func(f float64) string { return fmt.Sprintf("%.0f%%", 100*f) },
+	"add":     func(a, b int) int { return a + b },
+}
+
+// LoadReportTemplate parses the Go text/template at path for
+// Writer.SetReportTemplate.
+func LoadReportTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("output: report template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(reportFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("output: report template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// SetReportTemplate makes WriteMarkdown render report.md with tmpl instead
+// of the built-in layout. A nil tmpl restores the built-in layout.
+func (w *Writer) SetReportTemplate(tmpl *template.Template) {
+	w.reportTemplate = tmpl
+}
+
+func newReportData(transcript *debate.Transcript, consensus *debate.ConsensusResult, minority []debate.MinorityReport) ReportData {
+	verdict := debate.Classify(transcript, consensus)
+	usage := Usage{Rounds: transcript.Rounds, Turns: len(transcript.Turns), Tokens: transcript.Tokens}
+	seen := make(map[string]bool)
+	for _, turn := range transcript.Turns {
+		if m := turn.Agent.Model; m != "" && !seen[m] {
+			seen[m] = true
+			usage.Models = append(usage.Models, m)
+		}
+	}
+	return ReportData{
+		Topic:      transcript.Topic,
+		Transcript: transcript,
+		Consensus:  consensus,
+		Verdict:    verdict,
+		Outcome:    OutcomeText(verdict),
+		Minority:   minority,
+		Usage:      usage,
+	}
+}
@@ -2,8 +2,13 @@ package output
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
 
 	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/health"
 )
 
 const (
@@ -16,6 +21,17 @@ const (
 	ansiCyan    = "\033[36m"
 )
 
+// terminalMu keeps the lines printed for concurrent debates, or for one
+// debate and its caller, from interleaving.
+var terminalMu sync.Mutex
+
+// printTo writes a formatted line, or several, to out in one piece.
+func printTo(out io.Writer, format string, args ...any) {
+	terminalMu.Lock()
+	defer terminalMu.Unlock()
+	fmt.Fprintf(out, format, args...)
+}
+
 // Colorize wraps s with an ANSI color code and reset.
 func Colorize(color, s string) string { return color + s + ansiReset }
 
@@ -24,10 +40,25 @@ func Bold(s string) string { return ansiBold + s + ansiReset }
 
 // PrintTurn prints a formatted turn to stdout.
 func PrintTurn(turn debate.Turn) {
-	fmt.Printf("%s %s: %s\n",
-		Colorize(ansiYellow, fmt.Sprintf("[Round %d]", turn.Round)),
+	header := fmt.Sprintf("[Round %d]", turn.Round)
+	if turn.ID > 0 {
+		header = fmt.Sprintf("[Round %d #%d]", turn.Round, turn.ID)
+	}
+	annotations := ""
+	if turn.Statement != "" {
+		annotations = Colorize(ansiCyan, fmt.Sprintf(" [%s statement]", turn.Statement))
+	}
+	if turn.InReplyTo > 0 {
+		annotations += Colorize(ansiCyan, fmt.Sprintf(" ↩ #%d", turn.InReplyTo))
+	}
+	if turn.Confidence != nil {
+		annotations += Colorize(ansiCyan, fmt.Sprintf(" [%d%%]", *turn.Confidence))
+	}
+	printTo(os.Stdout, "%s %s%s: %s\n",
+		Colorize(ansiYellow, header),
 		Bold(turn.Agent.Name),
-		turn.Content,
+		annotations,
+		turn.Text(),
 	)
 }
 
@@ -39,7 +70,31 @@ func PrintPhase(phase debate.Phase) {
 		name = "Tenth Man"
 		color = ansiRed
 	}
-	fmt.Printf("\n%s\n\n", Colorize(ansiBold+color, "=== Phase: "+name+" ==="))
+	printTo(os.Stdout, "\n%s\n\n", Colorize(ansiBold+color, "=== Phase: "+name+" ==="))
+}
+
+// PrintEvent prints turns and phase banners to stdout and retried LLM calls
+// as warnings to stderr. Other events are ignored. It is safe to call from
+// several goroutines.
+func PrintEvent(ev debate.Event) {
+	switch ev := ev.(type) {
+	case debate.TurnCompleted:
+		PrintTurn(ev.Turn)
+	case debate.PhaseChanged:
+		PrintPhase(ev.Phase)
+	case debate.ModelSwapped:
+		printTo(os.Stdout, "%s\n", Colorize(AnsiMagenta, fmt.Sprintf("%s now speaks on %s (was %s).", ev.Swap.Agent, ev.Swap.To, ev.Swap.From)))
+	case debate.AgentRemoved:
+		printTo(os.Stdout, "%s\n", Colorize(AnsiMagenta, fmt.Sprintf("%s has been removed from the debate: %s", ev.Removal.Agent, ev.Removal.Reason)))
+	case debate.AgentJoined:
+		printTo(os.Stdout, "%s\n", Colorize(AnsiMagenta, fmt.Sprintf("%s (%s) joins the debate from round %d.", ev.Join.Agent.Name, ev.Join.Agent.Model, ev.Join.Round)))
+	case debate.InjectionSuspected:
+		printTo(os.Stderr, "Warning: %s looks like a prompt injection attempt (%s)\n", ev.Warning.Source, strings.Join(ev.Warning.Kinds, ", "))
+	case debate.AgentError:
+		if ev.WillRetry {
+			printTo(os.Stderr, "Warning: retrying %s (%s): %v\n", ev.Agent.Name, ev.Agent.Model, ev.Err)
+		}
+	}
 }
 
 // PrintConsensus prints the consensus summary.
@@ -53,7 +108,70 @@ func PrintConsensus(result *debate.ConsensusResult) {
 	fmt.Printf("Consensus Detected: %s\n", Colorize(ansiBold+detectedColor, detected))
 	fmt.Printf("Position: %s\n", result.Position)
 	fmt.Printf("Agreement Score: %s\n", Colorize(ansiYellow, fmt.Sprintf("%d/10", result.Score)))
+	if result.Participation > 0 && result.Participation < 1 {
+		fmt.Printf("Participation: %.0f%% of the last round's turns were substantive\n", 100*result.Participation)
+	}
+	if result.Fallback {
+		fmt.Println("Judged by: rule-based fallback (the LLM judge gave no parseable verdict)")
+	}
 	if len(result.Dissenters) > 0 {
 		fmt.Printf("Dissenters: %v\n", result.Dissenters)
 	}
 }
+
+// OutcomeText describes a debate's outcome in a sentence.
+func OutcomeText(v debate.Verdict) string {
+	switch v {
+	case debate.VerdictUpheld:
+		return "consensus upheld: it survived the Tenth Man unchanged"
+	case debate.VerdictRevised:
+		return "consensus revised: it survived the Tenth Man with a changed position"
+	case debate.VerdictOverturned:
+		return "consensus overturned by the Tenth Man"
+	}
+	return "no consensus reached"
+}
+
+// PrintOutcome prints how the consensus fared against the Tenth Man.
+func PrintOutcome(v debate.Verdict) {
+	color := ansiRed
+	switch v {
+	case debate.VerdictUpheld:
+		color = ansiGreen
+	case debate.VerdictRevised:
+		color = ansiYellow
+	}
+	fmt.Printf("Outcome: %s\n", Colorize(ansiBold+color, OutcomeText(v)))
+}
+
+// PrintPositionChange prints whether the Tenth Man changed the consensus
+// position. Nothing is printed for a nil change.
+func PrintPositionChange(change *debate.PositionChange) {
+	if change == nil {
+		return
+	}
+	answer := Colorize(ansiGreen, "No")
+	if change.Changed {
+		answer = Colorize(ansiYellow, "Yes")
+	}
+	fmt.Printf("Tenth Man changed the position: %s\n", answer)
+	if change.Summary != "" {
+		fmt.Printf("  %s\n", change.Summary)
+	}
+}
+
+// PrintMinorityReports prints each dissenting agent's unresolved objections.
+func PrintMinorityReports(reports []debate.MinorityReport) {
+	for _, report := range reports {
+		fmt.Printf("\n%s\n%s\n", Colorize(ansiBold+ansiRed, "Minority report: "+report.Agent.Name), report.Objections)
+	}
+}
+
+// PrintCheck prints a health check result as a checklist line.
+func PrintCheck(r health.Result) {
+	if r.OK {
+		fmt.Printf("%s %s\n", Colorize(ansiGreen, "✓"), r.Name)
+		return
+	}
+	fmt.Printf("%s %s: %s\n", Colorize(ansiRed, "✗"), r.Name, r.Error)
+}
@@ -0,0 +1,606 @@
+package output
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/disagreement"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/factcheck"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/fallacies"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/redact"
+)
+
+const (
+	transcriptFile = "transcript.json"
+	reportFile     = "report.md"
+	logFile        = "debate.log"
+	claimsFile     = "claims.json"
+	actionsFile    = "actions.json"
+	actionsMDFile  = "actions.md"
+	// disagreementFile holds the pairwise disagreement heatmaps.
+	disagreementFile = "disagreement.html"
+	// metricsFile holds the token, latency and consensus score charts.
+	metricsFile = "metrics.html"
+
+	threadExcerptLength = 80
+	confidenceBarWidth  = 20
+)
+
+// Writer persists debate artifacts into a single run directory. Its log
+// may be written from several goroutines at once, such as the runner and
+// the goroutine handling engine events.
+type Writer struct {
+	dir            string
+	mu             sync.Mutex // guards entries and appends to debate.log
+	entries        []string
+	logFormat      string             // LogText or LogJSON
+	events         eventLog           // encodes events handled as a Sink
+	reportTemplate *template.Template // replaces the built-in report.md layout when set
+	redactor       *redact.Redactor   // masks secrets in everything written when set
+	// encryptCommand builds the command EncryptArtifacts runs for each file.
+	encryptCommand func(ctx context.Context, tool string, recipients []string, path, out string) *exec.Cmd
+}
+
+// NewWriter creates a Writer that writes into dir.
+func NewWriter(dir string) *Writer {
+	return &Writer{dir: dir, encryptCommand: encryptCommand}
+}
+
+// SetRedactor makes the Writer mask what r matches in every artifact and log
+// entry it writes.
+func (w *Writer) SetRedactor(r *redact.Redactor) {
+	w.redactor = r
+}
+
+// Dir returns the run directory the Writer writes into.
+func (w *Writer) Dir() string {
+	return w.dir
+}
+
+// Log records a timestamped free-text entry and appends it to debate.log
+// immediately, so partial logs survive an interrupted run.
+func (w *Writer) Log(msg string) {
+	w.LogEntry(LogEntry{Type: "log", Message: msg})
+}
+
+// LogEntry records a timestamped entry and appends it to debate.log
+// immediately. In the text format, an entry without a Message is skipped.
+func (w *Writer) LogEntry(entry LogEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	line, ok := w.formatLogEntry(entry)
+	if !ok {
+		return
+	}
+	line = w.redactText(logFile, line)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.entries = append(w.entries, line)
+
+	f, err := os.OpenFile(filepath.Join(w.dir, logFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// WriteLog rewrites debate.log with every entry recorded so far.
+func (w *Writer) WriteLog() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var sb strings.Builder
+	for _, entry := range w.entries {
+		sb.WriteString(entry)
+		sb.WriteByte('\n')
+	}
+	return w.writeFile(logFile, []byte(sb.String()))
+}
+
+// WriteJSON writes the transcript as indented JSON to transcript.json.
+func (w *Writer) WriteJSON(transcript *debate.Transcript) error {
+	data, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		return fmt.Errorf("output: %w", err)
+	}
+	return w.writeFile(transcriptFile, data)
+}
+
+// WriteClaims writes the claims extracted from a debate to claims.json.
+func (w *Writer) WriteClaims(topic string, claims []debate.Claim) error {
+	if claims == nil {
+		claims = []debate.Claim{}
+	}
+	data, err := json.MarshalIndent(struct {
+		Topic  string         `json:"topic"`
+		Claims []debate.Claim `json:"claims"`
+	}{topic, claims}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("output: %w", err)
+	}
+	return w.writeFile(claimsFile, data)
+}
+
+// WriteActions writes the action items extracted from a debate to
+// actions.json and, as checklists, to actions.md.
+func (w *Writer) WriteActions(topic string, items *debate.ActionItems) error {
+	if items == nil {
+		items = &debate.ActionItems{}
+	}
+	data, err := json.MarshalIndent(struct {
+		Topic string `json:"topic"`
+		*debate.ActionItems
+	}{topic, items}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("output: %w", err)
+	}
+	if err := w.writeFile(actionsFile, data); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Action Items: %s\n", topic)
+	sb.WriteString("\n## Recommended Actions\n\n")
+	if len(items.Actions) == 0 {
+		sb.WriteString("None recommended.\n")
+	}
+	for _, a := range items.Actions {
+		fmt.Fprintf(&sb, "- [ ] %s", a.Description)
+		if a.Rationale != "" {
+			fmt.Fprintf(&sb, " — %s", a.Rationale)
+		}
+		if len(a.RaisedBy) > 0 {
+			fmt.Fprintf(&sb, " *(%s)*", strings.Join(a.RaisedBy, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	writeChecklist(&sb, "Open Questions", items.OpenQuestions)
+	writeChecklist(&sb, "Follow-up Research", items.Research)
+	return w.writeFile(actionsMDFile, []byte(sb.String()))
+}
+
+// writeChecklist renders list as a checklist section, or nothing if empty.
+func writeChecklist(sb *strings.Builder, title string, list []string) {
+	if len(list) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n## %s\n\n", title)
+	for _, item := range list {
+		fmt.Fprintf(sb, "- [ ] %s\n", item)
+	}
+}
+
+// WriteMarkdown writes a human-readable report to report.md. The executive
+// summary, if any, opens it, and minority reports follow the consensus
+// summary. A report template set with SetReportTemplate replaces this layout.
+func (w *Writer) WriteMarkdown(transcript *debate.Transcript, consensus *debate.ConsensusResult, minority []debate.MinorityReport) error {
+	if w.reportTemplate != nil {
+		var buf bytes.Buffer
+		if err := w.reportTemplate.Execute(&buf, newReportData(transcript, consensus, minority)); err != nil {
+			return fmt.Errorf("output: report template: %w", err)
+		}
+		return w.writeFile(reportFile, buf.Bytes())
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Debate Report: %s\n\n", transcript.Topic)
+	if transcript.Project != "" {
+		fmt.Fprintf(&sb, "**Project:** %s\n\n", transcript.Project)
+	}
+	if c := transcript.TopicClass; c != nil {
+		fmt.Fprintf(&sb, "**Topic type:** question of %s", c.Kind)
+		if c.Reason != "" {
+			fmt.Fprintf(&sb, " — %s", c.Reason)
+		}
+		sb.WriteString("\n\n")
+	}
+
+	if len(transcript.Summary) > 0 {
+		sb.WriteString("## Executive Summary\n\n")
+		for _, bullet := range transcript.Summary {
+			fmt.Fprintf(&sb, "- %s\n", bullet)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Consensus\n\n")
+	detected := "No"
+	if consensus.Detected {
+		detected = "Yes"
+	}
+	fmt.Fprintf(&sb, "- **Consensus Detected:** %s\n", detected)
+	fmt.Fprintf(&sb, "- **Position:** %s\n", consensus.Position)
+	fmt.Fprintf(&sb, "- **Agreement Score:** %d/10\n", consensus.Score)
+	fmt.Fprintf(&sb, "- **Outcome:** %s\n", OutcomeText(debate.Classify(transcript, consensus)))
+	if consensus.Participation > 0 && consensus.Participation < 1 {
+		fmt.Fprintf(&sb, "- **Participation:** %.0f%% of the last round's turns were substantive; the score is scaled accordingly\n", 100*consensus.Participation)
+	}
+	if consensus.Fallback {
+		sb.WriteString("- **Judged by:** rule-based fallback (the LLM judge gave no parseable verdict)\n")
+	}
+	if len(consensus.Dissenters) > 0 {
+		fmt.Fprintf(&sb, "- **Dissenters:** %s\n", strings.Join(consensus.Dissenters, ", "))
+	}
+
+	writeFactCheck(&sb, transcript.FactChecks)
+	writePositionChange(&sb, transcript.PositionChange)
+	writeStatements(&sb, transcript.Turns)
+
+	if len(minority) > 0 {
+		sb.WriteString("\n## Minority Reports\n")
+		for _, report := range minority {
+			fmt.Fprintf(&sb, "\n### %s (%s)\n\n%s\n", report.Agent.Name, report.Agent.Model, strings.TrimSpace(report.Objections))
+		}
+	}
+
+	writeConfidenceChart(&sb, transcript.Confidence)
+	writeAgreementHeatmap(&sb, transcript)
+	writeDissent(&sb, transcript.Disagreement)
+	writeRunMetrics(&sb, transcript)
+
+	if len(transcript.Evidence) > 0 {
+		sb.WriteString("\n## Evidence\n")
+		for _, ev := range transcript.Evidence {
+			fmt.Fprintf(&sb, "\n### %s\n\n*Requested by %s in round %d*\n\n", ev.Query, ev.RequestedBy, ev.Round)
+			if ev.Error != "" {
+				fmt.Fprintf(&sb, "Retrieval failed: %s\n", ev.Error)
+			} else {
+				fmt.Fprintf(&sb, "%s\n", strings.TrimSpace(ev.Result))
+			}
+		}
+	}
+
+	writeCrossExamination(&sb, transcript)
+
+	sb.WriteString("\n## Transcript\n")
+	round := 0
+	for _, turn := range transcript.Turns {
+		if turn.Round != round {
+			round = turn.Round
+			fmt.Fprintf(&sb, "\n### Round %d\n\n", round)
+		}
+		label := turn.Agent.Name
+		if turn.ID > 0 {
+			label = fmt.Sprintf("[#%d] %s", turn.ID, turn.Agent.Name)
+		}
+		if turn.Confidence != nil {
+			label += fmt.Sprintf(" [%d%%]", *turn.Confidence)
+		}
+		if kinds := flagKinds(transcript.ReasoningFlags, turn.ID); len(kinds) > 0 {
+			label += fmt.Sprintf(" [⚠ %s]", strings.Join(kinds, ", "))
+		}
+		if parent, ok := findTurn(transcript.Turns, turn.InReplyTo); ok {
+			fmt.Fprintf(&sb, "**%s** (%s), replying to #%d %s: %s\n\n", label, turn.Agent.Model, parent.ID, parent.Agent.Name, turn.Content)
+		} else {
+			fmt.Fprintf(&sb, "**%s** (%s): %s\n\n", label, turn.Agent.Model, turn.Text())
+		}
+	}
+
+	writeThreads(&sb, transcript.Turns)
+	writeReasoningQuality(&sb, transcript)
+
+	return w.writeFile(reportFile, []byte(sb.String()))
+}
+
+// writeCrossExamination lists the question and answer pairs of the
+// cross-examination round. Nothing is written if there was none.
+func writeCrossExamination(sb *strings.Builder, transcript *debate.Transcript) {
+	if len(transcript.CrossExamination) == 0 {
+		return
+	}
+	sb.WriteString("\n## Cross-Examination\n")
+	for _, ex := range transcript.CrossExamination {
+		question, _ := findTurn(transcript.Turns, ex.Question)
+		answer, _ := findTurn(transcript.Turns, ex.Answer)
+		fmt.Fprintf(sb, "\n**%s asks %s (round %d):** %s\n\n> %s\n", ex.Asker, ex.Answerer, ex.Round, strings.TrimSpace(question.Content), strings.ReplaceAll(strings.TrimSpace(answer.Content), "\n", "\n> "))
+	}
+}
+
+// writeStatements lists the opening and closing statements, each debater's
+// closing confidence next to the opening one for comparison. Nothing is
+// written for a kind of statement no one made.
+func writeStatements(sb *strings.Builder, turns []debate.Turn) {
+	opening := make(map[string]*int)
+	for _, kind := range []string{debate.OpeningStatement, debate.ClosingStatement} {
+		header := false
+		for _, turn := range turns {
+			if turn.Statement != kind {
+				continue
+			}
+			if !header {
+				fmt.Fprintf(sb, "\n## %s Statements\n", strings.ToUpper(kind[:1])+kind[1:])
+				header = true
+			}
+			label := turn.Agent.Name
+			switch before := opening[turn.Agent.Name]; {
+			case kind == debate.OpeningStatement:
+				opening[turn.Agent.Name] = turn.Confidence
+				if turn.Confidence != nil {
+					label += fmt.Sprintf(" [%d%%]", *turn.Confidence)
+				}
+			case turn.Confidence != nil && before != nil:
+				label += fmt.Sprintf(" [%d%% → %d%%]", *before, *turn.Confidence)
+			case turn.Confidence != nil:
+				label += fmt.Sprintf(" [%d%%]", *turn.Confidence)
+			}
+			fmt.Fprintf(sb, "\n**%s** (%s): %s\n", label, turn.Agent.Model, strings.TrimSpace(turn.Content))
+		}
+	}
+}
+
+// verdictLabels are the report's names for fact-check verdicts.
+var verdictLabels = map[string]string{
+	factcheck.Verified:     "Verified",
+	factcheck.Disputed:     "Disputed",
+	factcheck.Unverifiable: "Unverifiable",
+}
+
+// writeFactCheck lists the verdict on each factual claim of the consensus
+// position, after a count of each verdict. Nothing is written if the
+// position was not fact-checked.
+func writeFactCheck(sb *strings.Builder, checks []debate.FactCheck) {
+	if len(checks) == 0 {
+		return
+	}
+	counts := make(map[string]int)
+	for _, c := range checks {
+		counts[c.Verdict]++
+	}
+	sb.WriteString("\n## Fact Check\n\n")
+	fmt.Fprintf(sb, "Of %d factual claims in the consensus, %d verified, %d disputed and %d unverifiable.\n\n",
+		len(checks), counts[factcheck.Verified], counts[factcheck.Disputed], counts[factcheck.Unverifiable])
+	for _, c := range checks {
+		label := cmp.Or(verdictLabels[c.Verdict], c.Verdict)
+		fmt.Fprintf(sb, "- **%s:** %s", label, c.Claim)
+		if c.Note != "" {
+			fmt.Fprintf(sb, " — %s", c.Note)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// writePositionChange answers whether the Tenth Man changed the consensus,
+// with the positions before and after. Nothing is written if the Tenth Man
+// never spoke.
+func writePositionChange(sb *strings.Builder, change *debate.PositionChange) {
+	if change == nil {
+		return
+	}
+	sb.WriteString("\n## Did the Tenth Man Change Anything?\n\n")
+	answer := "No"
+	if change.Changed {
+		answer = "Yes"
+	}
+	fmt.Fprintf(sb, "**%s.**", answer)
+	if change.Summary != "" {
+		fmt.Fprintf(sb, " %s", change.Summary)
+	}
+	after := change.After
+	if after == "" {
+		after = "*(no consensus)*"
+	}
+	fmt.Fprintf(sb, "\n\n- **Before:** %s\n- **After:** %s\n", change.Before, after)
+}
+
+// writeConfidenceChart renders the group's confidence per round as a text
+// bar chart. Nothing is written if no confidence was reported.
+func writeConfidenceChart(sb *strings.Builder, rounds []debate.RoundConfidence) {
+	if len(rounds) == 0 {
+		return
+	}
+	sb.WriteString("\n## Group Confidence\n\n```\n")
+	for _, rc := range rounds {
+		filled := int(math.Round(rc.Mean / 100 * confidenceBarWidth))
+		fmt.Fprintf(sb, "Round %2d %s%s %3.0f ±%.0f (%d reported)\n",
+			rc.Round, strings.Repeat("█", filled), strings.Repeat("░", confidenceBarWidth-filled), rc.Mean, rc.Spread, rc.Reported)
+	}
+	sb.WriteString("```\n")
+}
+
+// writeDissent ranks the agents by their mean disagreement with the others
+// across the analysed rounds, naming the natural dissenter. Nothing is
+// written if disagreement was not analysed.
+func writeDissent(sb *strings.Builder, rounds []debate.RoundDisagreement) {
+	agents, mean := disagreement.Dissent(rounds)
+	if len(agents) == 0 {
+		return
+	}
+	slices.SortStableFunc(agents, func(a, b string) int { return cmp.Compare(mean[b], mean[a]) })
+	fmt.Fprintf(sb, "\n## Disagreement\n\nMean disagreement with the other agents, 0 (same position) to 10 (directly opposed), over %d round(s). Per-round heatmaps are in `%s`.\n\n| Agent | Mean disagreement |\n|---|---|\n", len(rounds), disagreementFile)
+	for _, a := range agents {
+		fmt.Fprintf(sb, "| %s | %.1f |\n", a, mean[a])
+	}
+	fmt.Fprintf(sb, "\n**Natural dissenter:** %s\n", agents[0])
+}
+
+// agreementShades shades a 1-10 agreement score, from strong dissent to full
+// agreement.
+var agreementShades = []string{"░", "░", "░", "▒", "▒", "▒", "▓", "▓", "█", "█"}
+
+// writeAgreementHeatmap renders each agent's agreement with the consensus
+// after every scored evaluation, one row per agent in speaking order and one
+// column per round. Nothing is written if the judge never scored agents.
+func writeAgreementHeatmap(sb *strings.Builder, transcript *debate.Transcript) {
+	rounds := transcript.Agreement
+	if len(rounds) == 0 {
+		return
+	}
+	var agents []string
+	for _, turn := range transcript.Turns {
+		if slices.Contains(agents, turn.Agent.Name) {
+			continue
+		}
+		for _, ra := range rounds {
+			if _, ok := ra.Scores[turn.Agent.Name]; ok {
+				agents = append(agents, turn.Agent.Name)
+				break
+			}
+		}
+	}
+
+	sb.WriteString("\n## Agreement Heatmap\n\nEach agent's agreement with the consensus, 1 (strong dissent) to 10 (full agreement).\n\n| Agent |")
+	for _, ra := range rounds {
+		fmt.Fprintf(sb, " R%d |", ra.Round)
+	}
+	sb.WriteString("\n|-------|")
+	sb.WriteString(strings.Repeat("----|", len(rounds)))
+	sb.WriteString("\n")
+	for _, name := range agents {
+		fmt.Fprintf(sb, "| %s |", name)
+		for _, ra := range rounds {
+			if score, ok := ra.Scores[name]; ok {
+				fmt.Fprintf(sb, " %s %d |", agreementShades[min(max(score, 1), 10)-1], score)
+			} else {
+				sb.WriteString(" · |")
+			}
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// writeThreads renders the reply structure as nested lists, one per turn
+// that starts a thread. Nothing is written if no turn replies to another.
+func writeThreads(sb *strings.Builder, turns []debate.Turn) {
+	replies := make(map[int][]debate.Turn)
+	for _, turn := range turns {
+		if _, ok := findTurn(turns, turn.InReplyTo); ok {
+			replies[turn.InReplyTo] = append(replies[turn.InReplyTo], turn)
+		}
+	}
+	if len(replies) == 0 {
+		return
+	}
+
+	sb.WriteString("\n## Reply Threads\n\n")
+	var walk func(turn debate.Turn, depth int)
+	walk = func(turn debate.Turn, depth int) {
+		fmt.Fprintf(sb, "%s- #%d **%s** (round %d): %s\n", strings.Repeat("  ", depth), turn.ID, turn.Agent.Name, turn.Round, excerpt(turn.Content, threadExcerptLength))
+		for _, reply := range replies[turn.ID] {
+			walk(reply, depth+1)
+		}
+	}
+	for _, turn := range turns {
+		if _, isReply := findTurn(turns, turn.InReplyTo); !isReply && len(replies[turn.ID]) > 0 {
+			walk(turn, 0)
+		}
+	}
+}
+
+// writeReasoningQuality appends a table of each agent's fallacies and biases
+// and the flags behind it. Nothing is written if the turns were not analysed.
+func writeReasoningQuality(sb *strings.Builder, transcript *debate.Transcript) {
+	if transcript.ReasoningFlags == nil {
+		return
+	}
+	sb.WriteString("\n## Reasoning Quality\n\n")
+	if len(transcript.ReasoningFlags) == 0 {
+		sb.WriteString("No fallacies or biases were found.\n")
+		return
+	}
+	var agents []string
+	turns := make(map[string]int)
+	for _, turn := range transcript.Turns {
+		if turn.Agent.Role == "moderator" || turn.Content == "" {
+			continue
+		}
+		if turns[turn.Agent.Name] == 0 {
+			agents = append(agents, turn.Agent.Name)
+		}
+		turns[turn.Agent.Name]++
+	}
+	counts := make(map[string]map[string]int)
+	for _, f := range transcript.ReasoningFlags {
+		if counts[f.Agent] == nil {
+			counts[f.Agent] = make(map[string]int)
+		}
+		counts[f.Agent][f.Category]++
+	}
+	sb.WriteString("| Agent | Turns | Fallacies | Biases |\n|---|---|---|---|\n")
+	for _, agent := range agents {
+		fmt.Fprintf(sb, "| %s | %d | %d | %d |\n", agent, turns[agent], counts[agent][fallacies.Fallacy], counts[agent][fallacies.Bias])
+	}
+	sb.WriteString("\n")
+	for _, f := range transcript.ReasoningFlags {
+		fmt.Fprintf(sb, "- #%d **%s** — %s (%s)", f.TurnID, f.Agent, f.Kind, f.Category)
+		if f.Excerpt != "" {
+			fmt.Fprintf(sb, ": \"%s\"", f.Excerpt)
+		}
+		if f.Explanation != "" {
+			fmt.Fprintf(sb, " %s", f.Explanation)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// flagKinds returns the kinds of reasoning flags on the turn with the given ID.
+func flagKinds(flags []debate.ReasoningFlag, id int) []string {
+	var kinds []string
+	for _, f := range flags {
+		if f.TurnID == id {
+			kinds = append(kinds, f.Kind)
+		}
+	}
+	return kinds
+}
+
+// findTurn returns the turn with the given ID.
+func findTurn(turns []debate.Turn, id int) (debate.Turn, bool) {
+	if id < 1 || id > len(turns) || turns[id-1].ID != id {
+		return debate.Turn{}, false
+	}
+	return turns[id-1], true
+}
+
+// excerpt returns the first line of s, truncated to at most n runes.
+func excerpt(s string, n int) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(s); len(r) > n {
+		return strings.TrimSpace(string(r[:n])) + "…"
+	}
+	return s
+}
+
+// redactText masks secrets in text bound for the artifact called name,
+// keeping JSON documents valid.
+func (w *Writer) redactText(name, text string) string {
+	if w.redactor == nil {
+		return text
+	}
+	if name == logFile && w.logFormat == LogJSON {
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = string(w.redactor.RedactJSON([]byte(line)))
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	if strings.HasSuffix(name, ".json") {
+		return string(w.redactor.RedactJSON([]byte(text)))
+	}
+	return w.redactor.Redact(text)
+}
+
+func (w *Writer) writeFile(name string, data []byte) error {
+	if w.redactor != nil {
+		data = []byte(w.redactText(name, string(data)))
+	}
+	if err := os.WriteFile(filepath.Join(w.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package plugin runs external commands on debate events, so users can bolt
+// on their own processing, such as posting turns to internal tools or
+// scoring finished debates, without recompiling. A plugin is any program:
+// it is run once per event with the event as JSON on stdin, and each line
+// it prints is logged to debate.log.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// Event names.
+const (
+	TurnCompleted   = "turn_completed"
+	DebateCompleted = "debate_completed"
+)
+
+// DefaultTimeout bounds each run of a plugin.
+const DefaultTimeout = 30 * time.Second
+
+// maxStderr caps how much of a failed plugin's stderr its error quotes.
+const maxStderr = 512
+
+// Event is what a plugin reads on stdin.
+type Event struct {
+	Event string `json:"event"` // TurnCompleted or DebateCompleted
+	Run   string `json:"run"`   // run directory; complete by the time of DebateCompleted
+	Topic string `json:"topic"`
+
+	Turn *debate.Turn `json:"turn,omitempty"` // the turn, for TurnCompleted
+
+	// For DebateCompleted:
+	Verdict   debate.Verdict          `json:"verdict,omitempty"`
+	Rounds    int                     `json:"rounds,omitempty"`
+	Consensus *debate.ConsensusResult `json:"consensus,omitempty"`
+}
+
+// Plugin is an external command run with an Event on stdin.
+type Plugin struct {
+	args    []string
+	timeout time.Duration
+}
+
+// New returns the plugin that runs command, a program and its arguments
+// separated by spaces. The program must be found on PATH, or be a path.
+func New(command string) (*Plugin, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("plugin: %q has no command", command)
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return nil, fmt.Errorf("plugin: %w", err)
+	}
+	return &Plugin{args: args, timeout: DefaultTimeout}, nil
+}
+
+// Name returns the base name of the plugin's program.
+func (p *Plugin) Name() string {
+	return filepath.Base(p.args[0])
+}
+
+// Run runs the plugin on ev and returns what it printed. A plugin that exits
+// with a non-zero status, or runs longer than its timeout, fails.
+func (p *Plugin) Run(ctx context.Context, ev Event) (string, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return "", fmt.Errorf("plugin: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.args[0], p.args[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxStderr {
+			msg = msg[:maxStderr] + "..."
+		}
+		if msg != "" {
+			return "", fmt.Errorf("plugin: %s: %w: %s", p.Name(), err, msg)
+		}
+		return "", fmt.Errorf("plugin: %s: %w", p.Name(), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Set runs plugins on the events of one debate. It is an event sink: every
+// turn is passed to each plugin as it completes, and Completed passes the
+// finished debate. Plugin output and failures go to the log function; a
+// plugin that fails is not run again for the debate, so a broken one does
+// not slow every turn down.
+type Set struct {
+	plugins []*Plugin
+	failed  []bool
+	run     string
+	topic   string
+	log     func(string)
+}
+
+// NewSet returns a Set running commands, as New parses them, for the debate
+// on topic whose artifacts go to runDir.
+func NewSet(commands []string, runDir, topic string, log func(string)) (*Set, error) {
+	s := &Set{run: runDir, topic: topic, log: log}
+	for _, c := range commands {
+		p, err := New(c)
+		if err != nil {
+			return nil, err
+		}
+		s.plugins = append(s.plugins, p)
+	}
+	s.failed = make([]bool, len(s.plugins))
+	return s, nil
+}
+
+// Handle passes completed turns to every plugin.
+func (s *Set) Handle(ev debate.Event) {
+	if tc, ok := ev.(debate.TurnCompleted); ok {
+		s.runAll(context.Background(), Event{Event: TurnCompleted, Run: s.run, Topic: s.topic, Turn: &tc.Turn})
+	}
+}
+
+// Close does nothing: plugin failures are logged as they happen.
+func (s *Set) Close() error {
+	return nil
+}
+
+// Completed passes the finished debate to every plugin.
+func (s *Set) Completed(ctx context.Context, result *debate.Result) {
+	s.runAll(ctx, Event{
+		Event:     DebateCompleted,
+		Run:       s.run,
+		Topic:     s.topic,
+		Verdict:   result.Verdict(),
+		Rounds:    result.Transcript.Rounds,
+		Consensus: result.Consensus,
+	})
+}
+
+func (s *Set) runAll(ctx context.Context, ev Event) {
+	for i, p := range s.plugins {
+		if s.failed[i] {
+			continue
+		}
+		out, err := p.Run(ctx, ev)
+		if err != nil {
+			s.failed[i] = true
+			s.log(fmt.Sprintf("Plugin %s failed on %s, not running it again: %v", p.Name(), ev.Event, err))
+			continue
+		}
+		for line := range strings.Lines(out) {
+			if line = strings.TrimSpace(line); line != "" {
+				s.log(fmt.Sprintf("Plugin %s: %s", p.Name(), line))
+			}
+		}
+	}
+}
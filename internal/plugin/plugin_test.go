@@ -0,0 +1,105 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// script writes an executable shell script running body and returns its path.
+func script(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "plugin.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("   "); err == nil {
+		t.Error("expected an error for an empty command")
+	}
+	if _, err := New("tenthman-no-such-plugin --flag"); err == nil {
+		t.Error("expected an error for a program not on PATH")
+	}
+	p, err := New(script(t, "cat") + " --flag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "plugin.sh" || !slices.Equal(p.args[1:], []string{"--flag"}) {
+		t.Errorf("unexpected plugin %+v", p)
+	}
+}
+
+func TestSetRunsPluginsOnEvents(t *testing.T) {
+	dir := t.TempDir()
+	events := filepath.Join(dir, "events")
+	command := script(t, `cat >> "$1"; echo >> "$1"; echo "seen"`) + " " + events
+	var logs []string
+	s, err := NewSet([]string{command}, "/runs/x", "Cache?", func(msg string) { logs = append(logs, msg) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Handle(debate.TurnCompleted{Turn: debate.Turn{ID: 1, Agent: debate.Agent{Name: "Alice"}, Content: "Yes."}})
+	s.Handle(debate.RoundStarted{})
+	s.Completed(context.Background(), &debate.Result{
+		Transcript: &debate.Transcript{Rounds: 2},
+		Consensus:  &debate.ConsensusResult{Detected: true, Position: "Cache it", Score: 8},
+		Outcome:    debate.VerdictUpheld,
+	})
+
+	data, err := os.ReadFile(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one turn and one debate event, got %q", lines)
+	}
+	var turn, done Event
+	if err := json.Unmarshal([]byte(lines[0]), &turn); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &done); err != nil {
+		t.Fatal(err)
+	}
+	if turn.Event != TurnCompleted || turn.Run != "/runs/x" || turn.Topic != "Cache?" || turn.Turn == nil || turn.Turn.Content != "Yes." {
+		t.Errorf("unexpected turn event %+v", turn)
+	}
+	if done.Event != DebateCompleted || done.Verdict != debate.VerdictUpheld || done.Rounds != 2 || done.Consensus.Position != "Cache it" || done.Turn != nil {
+		t.Errorf("unexpected debate event %+v", done)
+	}
+	if !slices.Equal(logs, []string{"Plugin plugin.sh: seen", "Plugin plugin.sh: seen"}) {
+		t.Errorf("unexpected log %q", logs)
+	}
+}
+
+func TestSetStopsRunningAFailedPlugin(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	command := script(t, `echo x >> "$1"; echo "scoring service down" >&2; exit 3`) + " " + calls
+	var logs []string
+	s, err := NewSet([]string{command}, "", "t", func(msg string) { logs = append(logs, msg) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		s.Handle(debate.TurnCompleted{})
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close() = %v, want failures only logged", err)
+	}
+	if data, _ := os.ReadFile(calls); string(data) != "x\n" {
+		t.Errorf("expected one run of the failing plugin, got %q", data)
+	}
+	if len(logs) != 1 || !strings.Contains(logs[0], "exit status 3: scoring service down") {
+		t.Errorf("unexpected log %q", logs)
+	}
+}
@@ -0,0 +1,130 @@
+// Package redact masks personal data and secrets, such as email addresses,
+// API keys and internal hostnames, in text written to run artifacts.
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// rule masks every match of re as "[REDACTED:<name>]".
+type rule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// builtins are the patterns every Redactor applies, most specific first so
+// a key is not half-masked as something vaguer.
+var builtins = []rule{
+	{"private-key", regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{"aws-key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github-token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{"api-key", regexp.MustCompile(`\bsk-(?:[a-z]+-)*[A-Za-z0-9_-]{20,}\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\b`)},
+	{"bearer-token", regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{16,}=*`)},
+	{"secret", regexp.MustCompile(`(?i)\b(?:password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)\b(\s*[:=]\s*)["']?[^\s"'\\,;]{6,}["']?`)},
+	{"email", regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+	{"hostname", regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+(?:internal|corp|local|lan|intranet|private)\b`)},
+}
+
+// Redactor masks the built-in patterns and any configured ones.
+type Redactor struct {
+	rules []rule
+}
+
+// New returns a Redactor applying the built-in patterns followed by
+// patterns, each a regular expression optionally prefixed by a name and
+// "=", as in "hostname=\b[a-z0-9.-]+\.example\.net\b". Unnamed patterns are
+// masked as "custom".
+func New(patterns []string) (*Redactor, error) {
+	r := &Redactor{rules: append([]rule(nil), builtins...)}
+	for _, p := range patterns {
+		name, expr := "custom", p
+		if n, e, ok := strings.Cut(p, "="); ok && validName(n) {
+			name, expr = n, e
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("redact: pattern %q: %w", p, err)
+		}
+		r.rules = append(r.rules, rule{name: name, re: re})
+	}
+	return r, nil
+}
+
+// validName reports whether s can name a pattern: lower-case letters,
+// digits and dashes.
+func validName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Redact returns s with every match masked. A nil Redactor returns s
+// unchanged.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, rule := range r.rules {
+		mask := "[REDACTED:" + rule.name + "]"
+		if rule.name == "secret" {
+			// Keep the key name and separator so the text still reads.
+			s = rule.re.ReplaceAllStringFunc(s, func(m string) string {
+				sub := rule.re.FindStringSubmatchIndex(m)
+				return m[:sub[3]] + mask
+			})
+			continue
+		}
+		s = rule.re.ReplaceAllLiteralString(s, mask)
+	}
+	return s
+}
+
+// RedactJSON returns the JSON document data with every string value
+// masked. Masking the encoded text keeps the document's layout; if that
+// leaves it invalid, as a custom pattern matching an escape sequence could,
+// the values are masked one by one and the document re-encoded, indented.
+func (r *Redactor) RedactJSON(data []byte) []byte {
+	if r == nil {
+		return data
+	}
+	if masked := []byte(r.Redact(string(data))); json.Valid(masked) {
+		return masked
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []byte(r.Redact(string(data)))
+	}
+	out, err := json.MarshalIndent(r.redactValue(doc), "", "  ")
+	if err != nil {
+		return []byte(r.Redact(string(data)))
+	}
+	return out
+}
+
+// redactValue masks every string in a decoded JSON value.
+func (r *Redactor) redactValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return r.Redact(v)
+	case []any:
+		for i := range v {
+			v[i] = r.redactValue(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = r.redactValue(v[k])
+		}
+	}
+	return v
+}
@@ -0,0 +1,132 @@
+package research
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxResults       = 3
+	maxPassageLength = 600
+)
+
+// sourceExts are the file types indexed by LocalRetriever.
+var sourceExts = map[string]bool{".md": true, ".markdown": true, ".txt": true}
+
+// passage is one paragraph of a source document.
+type passage struct {
+	source string
+	text   string
+	terms  map[string]bool
+}
+
+// LocalRetriever answers evidence requests from a directory of local
+// markdown and text documents by keyword overlap.
+type LocalRetriever struct {
+	passages []passage
+}
+
+// NewLocalRetriever indexes every markdown and text file under paths, which
+// may be files or directories.
+func NewLocalRetriever(paths ...string) (*LocalRetriever, error) {
+	r := &LocalRetriever{}
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !sourceExts[strings.ToLower(filepath.Ext(path))] {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			r.add(path, string(data))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("research: %w", err)
+		}
+	}
+	if len(r.passages) == 0 {
+		return nil, fmt.Errorf("research: no .md or .txt sources found in %s", strings.Join(paths, ", "))
+	}
+	return r, nil
+}
+
+func (r *LocalRetriever) add(source, text string) {
+	for para := range strings.SplitSeq(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		terms := make(map[string]bool)
+		for _, t := range tokenize(para) {
+			terms[t] = true
+		}
+		r.passages = append(r.passages, passage{source: source, text: para, terms: terms})
+	}
+}
+
+// Retrieve implements debate.Retriever. It returns the best-matching
+// passages with their source files, or a note that nothing matched.
+func (r *LocalRetriever) Retrieve(ctx context.Context, query string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("research: %w", err)
+	}
+	queryTerms := tokenize(query)
+	type hit struct {
+		p     passage
+		score int
+	}
+	var hits []hit
+	for _, p := range r.passages {
+		score := 0
+		for _, t := range queryTerms {
+			if p.terms[t] {
+				score++
+			}
+		}
+		if score > 0 {
+			hits = append(hits, hit{p, score})
+		}
+	}
+	if len(hits) == 0 {
+		return "No matching sources found.", nil
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+
+	var sb strings.Builder
+	for i, h := range hits[:min(len(hits), maxResults)] {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		text := h.p.text
+		if r := []rune(text); len(r) > maxPassageLength {
+			text = string(r[:maxPassageLength]) + "…"
+		}
+		fmt.Fprintf(&sb, "[%s] %s", filepath.Base(h.p.source), text)
+	}
+	return sb.String(), nil
+}
+
+// tokenize lowercases s and splits it into words of at least three characters.
+func tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) >= 3 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package research
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSource(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalRetriever(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, dir, "incidents.md", "# Incidents\n\nThe 2025 outage rate was 0.4% across all regions.\n\nUnrelated paragraph about hiring.")
+	writeSource(t, dir, "pricing.txt", "Vendor pricing rose 12% in 2025.")
+	writeSource(t, dir, "image.png", "outage outage outage")
+
+	r, err := NewLocalRetriever(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := r.Retrieve(context.Background(), "2025 outage rate")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "[incidents.md] The 2025 outage rate was 0.4%") {
+		t.Errorf("best match should come first, got %q", got)
+	}
+	if strings.Contains(got, "hiring") || strings.Contains(got, "image.png") {
+		t.Errorf("unexpected passages in %q", got)
+	}
+
+	got, _ = r.Retrieve(context.Background(), "quantum chromodynamics")
+	if got != "No matching sources found." {
+		t.Errorf("expected no-match note, got %q", got)
+	}
+}
+
+func TestNewLocalRetrieverRequiresSources(t *testing.T) {
+	if _, err := NewLocalRetriever(t.TempDir()); err == nil {
+		t.Error("expected error for a directory without sources")
+	}
+	if _, err := NewLocalRetriever(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for a missing path")
+	}
+}
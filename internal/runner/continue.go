@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/consensus"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/tenthman"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/redact"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+)
+
+// Extension describes additional rounds for a finished run.
+type Extension struct {
+	Dir    string   // run directory to extend in place
+	Rounds int      // rounds to add
+	Notes  []string // new information shown to every agent before the first new round
+	Upload string   // s3:// or gs:// destination for the updated run directory
+	// ReportTemplate is a Go template file replacing the built-in report.md
+	// layout, as in Job.
+	ReportTemplate string
+	Sinks          []string // extra destinations for engine events, as in Job
+	MaxTokens      int      // completion cap for each new turn, as in Job
+	MaxWords       int      // word limit for each new turn, as in Job
+	// Redact and RedactPatterns mask secrets in the notes and the rewritten
+	// artifacts, as in Job.
+	Redact         bool
+	RedactPatterns []string
+	EncryptTo      []string // age or GPG recipients the rewritten artifacts are encrypted to, as in Job
+}
+
+// Continue reloads the transcript in ext.Dir, runs ext.Rounds more rounds
+// with the same agents and models, re-evaluates consensus and rewrites the
+// run's artifacts. debate.log is appended to in the format it was written
+// in, and a run saved compressed is compressed again. An encrypted run must
+// be decrypted first.
+func Continue(ctx context.Context, llm debate.LLMClient, ext Extension, hooks Hooks) (*Outcome, error) {
+	metered := &meteredLLM{LLMClient: llm}
+	outcome, err := extend(ctx, metered, ext, hooks)
+	if outcome != nil {
+		outcome.Tokens = int(metered.tokens.Load())
+	}
+	return outcome, err
+}
+
+func extend(ctx context.Context, llm debate.LLMClient, ext Extension, hooks Hooks) (*Outcome, error) {
+	if ext.Rounds < 1 {
+		return nil, fmt.Errorf("runner: rounds must be >= 1, got %d", ext.Rounds)
+	}
+	if ext.MaxTokens < 0 || ext.MaxWords < 0 {
+		return nil, fmt.Errorf("runner: max tokens and max words must be >= 0")
+	}
+	if _, err := redact.New(ext.RedactPatterns); err != nil {
+		return nil, fmt.Errorf("runner: %w", err)
+	}
+	redactor := jobRedactor(Job{Redact: ext.Redact, RedactPatterns: ext.RedactPatterns})
+	if redactor != nil {
+		notes := make([]string, len(ext.Notes))
+		for i, note := range ext.Notes {
+			notes[i] = redactor.Redact(note)
+		}
+		ext.Notes = notes
+	}
+	if err := output.ValidateRecipients(ext.EncryptTo); err != nil {
+		return nil, fmt.Errorf("runner: %w", err)
+	}
+	if output.Encrypted(ext.Dir) {
+		return nil, fmt.Errorf("runner: %s is encrypted, decrypt its artifacts before continuing it", ext.Dir)
+	}
+	compress, err := output.DecompressArtifacts(ext.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("runner: %w", err)
+	}
+	prior, err := runs.LoadTranscript(ext.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("runner: %w", err)
+	}
+	agents, tenthManModel := priorAgents(prior)
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("runner: %s has no debaters to continue with", ext.Dir)
+	}
+	images, _, err := loadImages(prior.Images)
+	if err != nil {
+		return nil, err
+	}
+	if hooks.OnStart != nil {
+		hooks.OnStart(ext.Dir)
+	}
+
+	writer := output.NewWriter(ext.Dir)
+	writer.SetLogFormat(output.DetectLogFormat(ext.Dir))
+	writer.SetRedactor(redactor)
+	if ext.ReportTemplate != "" {
+		tmpl, err := output.LoadReportTemplate(ext.ReportTemplate)
+		if err != nil {
+			return &Outcome{Dir: ext.Dir}, fmt.Errorf("runner: %w", err)
+		}
+		writer.SetReportTemplate(tmpl)
+	}
+	writer.Log(fmt.Sprintf("Continuing after round %d for %d more round(s)", prior.Rounds, ext.Rounds))
+	for _, note := range ext.Notes {
+		writer.Log(fmt.Sprintf("Moderator note: %s", note))
+	}
+
+	judge := consensus.NewJudge(llm, agents[0].Model)
+	engine := debate.NewEngine(prior.Topic, agents, llm, judge, tenthman.NewActivator(), 1, ext.Rounds)
+	engine.SetTenthManModel(tenthManModel)
+	engine.SetImages(images)
+	engine.SetMaxTokens(ext.MaxTokens)
+	engine.SetMaxWords(ext.MaxWords)
+	sinks, err := newSinks(writer, hooks, ext.Sinks, prior)
+	if err != nil {
+		return &Outcome{Dir: ext.Dir}, err
+	}
+	stop := streamEvents(engine, sinks)
+
+	result, err := engine.Continue(ctx, prior, ext.Rounds, ext.Notes)
+	if err := stop(); err != nil {
+		writer.Log(fmt.Sprintf("Event sink failed: %v", err))
+	}
+	if err != nil {
+		return &Outcome{Dir: ext.Dir}, fmt.Errorf("runner: %w", err)
+	}
+	result.Transcript.JudgeModel = agents[0].Model
+	outcome, err := saveResult(ctx, llm, writer, agents[0].Model, nil, result)
+	if err != nil {
+		return outcome, err
+	}
+	return outcome, finish(ctx, writer, outcome, compress, ext.EncryptTo, ext.Upload)
+}
+
+// priorAgents recovers the debaters of a saved transcript still in the debate,
+// including late joiners, in speaking order and on the models they ended on,
+// and the Tenth Man's model if it took part.
+func priorAgents(t *debate.Transcript) ([]debate.Agent, string) {
+	var agents []debate.Agent
+	seen := make(map[string]bool)
+	tenthManModel := ""
+	for _, turn := range t.Turns {
+		switch {
+		case turn.Agent.Role == "tenth-man":
+			tenthManModel = turn.Agent.Model
+		case turn.Agent.Role == "debater" && !seen[strings.ToLower(turn.Agent.Name)]:
+			seen[strings.ToLower(turn.Agent.Name)] = true
+			agents = append(agents, turn.Agent)
+		}
+	}
+	return debate.WithoutRemoved(debate.ApplyModelSwaps(debate.WithJoined(agents, t), t), t), tenthManModel
+}
@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/store"
+)
+
+// eventBuffer is how many engine events may wait for the sinks.
+const eventBuffer = 64
+
+// streamEvents delivers engine's events to sink, in order, on a separate
+// goroutine. The returned stop function must be called once the engine is
+// done; it returns after every event has been handled and sink is closed.
+func streamEvents(engine *debate.Engine, sink output.Sink) (stop func() error) {
+	events := make(chan debate.Event, eventBuffer)
+	engine.SetEvents(events)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range events {
+			sink.Handle(ev)
+		}
+	}()
+	return func() error {
+		close(events)
+		<-done
+		return sink.Close()
+	}
+}
+
+// newSinks returns the sinks every run writes to, debate.log and hooks,
+// followed by those configured in specs. seed is the transcript the run
+// starts from.
+func newSinks(writer *output.Writer, hooks Hooks, specs []string, seed *debate.Transcript) (*output.Mux, error) {
+	mux := output.NewMux(writer, output.SinkFunc(hooks.handle))
+	for _, spec := range specs {
+		sink, err := newSink(spec, writer.Dir(), seed)
+		if err != nil {
+			return nil, err
+		}
+		mux.Add(sink)
+	}
+	return mux, nil
+}
+
+// newSink returns the sink for spec. store:<dir> saves the transcript to
+// <dir>/<run-dir-name>/transcript.json after every round, in the layout of
+// a file store; the other specs are described at output.NewSink.
+func newSink(spec, runDir string, seed *debate.Transcript) (output.Sink, error) {
+	if dir, ok := strings.CutPrefix(spec, "store:"); ok {
+		if dir == "" {
+			return nil, fmt.Errorf("runner: sink %q has no directory", spec)
+		}
+		return store.NewEventSink(store.NewFileStore(dir), filepath.Base(filepath.Clean(runDir)), seed), nil
+	}
+	sink, err := output.NewSink(spec)
+	if err != nil {
+		return nil, fmt.Errorf("runner: %w", err)
+	}
+	return sink, nil
+}
+
+// sendEvents returns a sink forwarding events to ch.
+func sendEvents(ch chan<- debate.Event) output.SinkFunc {
+	return func(ev debate.Event) { ch <- ev }
+}
+
+// handle passes ev to the matching hooks.
+func (h Hooks) handle(ev debate.Event) {
+	switch ev := ev.(type) {
+	case debate.TurnCompleted:
+		if h.OnTurn != nil {
+			h.OnTurn(ev.Turn)
+		}
+	case debate.PhaseChanged:
+		if h.OnPhase != nil {
+			h.OnPhase(ev.Phase)
+		}
+	case debate.EvidenceGathered:
+		if h.OnEvidence != nil {
+			h.OnEvidence(ev.Evidence)
+		}
+	}
+	if h.OnEvent != nil {
+		h.OnEvent(ev)
+	}
+}
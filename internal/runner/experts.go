@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// expert is a built-in domain archetype that gives a debater a distinct
+// analytical frame.
+type expert struct {
+	key     string   // canonical key, e.g. "security"
+	aliases []string // alternative keys accepted by ExpertPersonas
+	persona Persona
+}
+
+var experts = []expert{
+	{
+		key:     "economics",
+		aliases: []string{"economist", "econ"},
+		persona: Persona{
+			Name:        "Economist",
+			Description: "an economist",
+			Frame: "Analyze the question through incentives, opportunity cost, second-order effects and who bears the costs versus who captures the benefits. " +
+				"Ask what behavior the decision rewards, what it crowds out and how the picture changes at scale or over time. Quantify trade-offs where you can.",
+		},
+	},
+	{
+		key:     "security",
+		aliases: []string{"security-engineer", "sec"},
+		persona: Persona{
+			Name:        "Security Engineer",
+			Description: "a security engineer",
+			Frame: "Think like a motivated attacker. Identify the assets, trust boundaries and entry points involved, " +
+				"name concrete threats and how they would be exploited, and judge each by likelihood and blast radius. " +
+				"Prefer controls that eliminate whole classes of failure over ones that patch individual cases.",
+		},
+	},
+	{
+		key:     "ethics",
+		aliases: []string{"ethicist"},
+		persona: Persona{
+			Name:        "Ethicist",
+			Description: "an ethicist",
+			Frame: "Examine who is affected, including people absent from the discussion, and whether burdens and benefits are fairly distributed. " +
+				"Weigh consequences, duties and rights explicitly, surface consent and autonomy concerns, and say which values are being traded against each other.",
+		},
+	},
+	{
+		key:     "legal",
+		aliases: []string{"lawyer", "law"},
+		persona: Persona{
+			Name:        "Lawyer",
+			Description: "a lawyer",
+			Frame: "Identify the legal and regulatory exposure: liability, contractual obligations, compliance requirements, intellectual property and jurisdictional differences. " +
+				"Distinguish what is clearly prohibited from what is merely risky, and state which assumptions would need review by counsel.",
+		},
+	},
+	{
+		key:     "statistics",
+		aliases: []string{"statistician", "stats"},
+		persona: Persona{
+			Name:        "Statistician",
+			Description: "a statistician",
+			Frame: "Scrutinize the evidence behind every claim: sample sizes, base rates, selection bias, confounders and whether correlation is being read as causation. " +
+				"Ask what data would change the conclusion, state uncertainty explicitly and call out claims that rest on anecdotes.",
+		},
+	},
+}
+
+// ExpertPersonas resolves archetype keys or aliases (case-insensitive) to
+// their personas, in the order given.
+func ExpertPersonas(keys []string) ([]Persona, error) {
+	personas := make([]Persona, 0, len(keys))
+	for _, key := range keys {
+		e, ok := lookupExpert(key)
+		if !ok {
+			return nil, fmt.Errorf("runner: unknown expert %q (available: %s)", key, strings.Join(ExpertKeys(), ", "))
+		}
+		personas = append(personas, e.persona)
+	}
+	return personas, nil
+}
+
+func lookupExpert(key string) (expert, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, e := range experts {
+		if e.key == key || slices.Contains(e.aliases, key) {
+			return e, true
+		}
+	}
+	return expert{}, false
+}
+
+// ExpertKeys returns the keys of the built-in expert archetypes, in order.
+func ExpertKeys() []string {
+	keys := make([]string, len(experts))
+	for i, e := range experts {
+		keys[i] = e.key
+	}
+	return keys
+}
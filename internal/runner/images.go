@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+)
+
+// maxImageSize bounds an attached image file; providers reject larger ones.
+const maxImageSize = 20 << 20
+
+// loadImages resolves images attached to a topic into URLs a model can be
+// sent. http://, https:// and data: URLs are passed through; anything else
+// is read as an image file and inlined as a data: URL. It also returns the
+// references to record in the transcript, with file paths made absolute so
+// a continued run finds them again.
+func loadImages(refs []string) (urls, resolved []string, err error) {
+	for _, ref := range refs {
+		if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "data:") {
+			urls = append(urls, ref)
+			resolved = append(resolved, ref)
+			continue
+		}
+		path, err := filepath.Abs(ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("runner: image %s: %w", ref, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("runner: image: %w", err)
+		}
+		if len(data) > maxImageSize {
+			return nil, nil, fmt.Errorf("runner: image %s is %d bytes, over the %d byte limit", ref, len(data), maxImageSize)
+		}
+		mime := http.DetectContentType(data)
+		if !strings.HasPrefix(mime, "image/") {
+			return nil, nil, fmt.Errorf("runner: image %s is %s, not an image", ref, mime)
+		}
+		urls = append(urls, "data:"+mime+";base64,"+base64.StdEncoding.EncodeToString(data))
+		resolved = append(resolved, path)
+	}
+	return urls, resolved, nil
+}
+
+// visionRegistry narrows registry to the models that accept images.
+func visionRegistry(registry *models.Registry) (*models.Registry, error) {
+	vision := registry.Vision()
+	if len(vision.FreeModels()) == 0 {
+		return nil, fmt.Errorf("runner: images are attached but no free model accepts images")
+	}
+	return vision, nil
+}
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"gopkg.in/yaml.v3"
+)
+
+// AgentSpec is one entry of an agent roster.
+type AgentSpec struct {
+	Name        string   `yaml:"name" json:"name"`
+	Model       string   `yaml:"model" json:"model,omitempty"` // empty: assigned from the free model registry
+	Role        string   `yaml:"role" json:"role,omitempty"`   // "debater" (default) or "tenth-man"
+	Expertise   string   `yaml:"expertise" json:"expertise,omitempty"`
+	Temperature *float64 `yaml:"temperature" json:"temperature,omitempty"`
+	// ReasoningEffort overrides Job.ReasoningEffort for this agent.
+	ReasoningEffort string `yaml:"reasoning_effort" json:"reasoning_effort,omitempty"`
+}
+
+// LoadRoster reads a roster YAML file with a top-level "agents" list.
+func LoadRoster(path string) ([]AgentSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("runner: %w", err)
+	}
+	var f struct {
+		Agents []AgentSpec `yaml:"agents"`
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("runner: parsing roster %s: %w", path, err)
+	}
+	if len(f.Agents) == 0 {
+		return nil, fmt.Errorf("runner: roster %s defines no agents", path)
+	}
+	return f.Agents, nil
+}
+
+// rosterDebaters counts the roster entries that are debaters.
+func rosterDebaters(roster []AgentSpec) int {
+	n := 0
+	for _, spec := range roster {
+		if spec.Role != "tenth-man" {
+			n++
+		}
+	}
+	return n
+}
+
+// rosterAgents builds debaters from the roster, filling missing models from
+// selected in order. It also returns the Tenth Man's model, if one is
+// designated, and an error listing every misconfigured agent.
+func rosterAgents(roster []AgentSpec, selected []openrouter.Model) ([]debate.Agent, string, error) {
+	var agents []debate.Agent
+	tenthManModel := ""
+	tenthMen := 0
+	for _, spec := range roster {
+		if spec.Role == "tenth-man" {
+			tenthMen++
+			tenthManModel = spec.Model
+			continue
+		}
+		role := spec.Role
+		if role == "" {
+			role = "debater"
+		}
+		model := spec.Model
+		if model == "" && len(selected) > 0 {
+			model = selected[len(agents)%len(selected)].ID
+		}
+		persona := ""
+		if spec.Expertise != "" {
+			persona = "an expert in " + spec.Expertise
+		}
+		agents = append(agents, debate.Agent{
+			ID:              len(agents) + 1,
+			Name:            spec.Name,
+			Model:           model,
+			Role:            role,
+			Persona:         persona,
+			Temperature:     spec.Temperature,
+			ReasoningEffort: spec.ReasoningEffort,
+		})
+	}
+	if tenthMen > 1 {
+		return nil, "", fmt.Errorf("runner: roster: at most one tenth-man entry is allowed, got %d", tenthMen)
+	}
+	if err := debate.ValidateAgents(agents); err != nil {
+		return nil, "", fmt.Errorf("runner: roster:\n%w", err)
+	}
+	return agents, tenthManModel, nil
+}
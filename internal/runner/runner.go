@@ -0,0 +1,972 @@
+package runner
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/actions"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/claims"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/disagreement"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/factcheck"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/fallacies"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/summary"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/topics"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/expr"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/guard"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/plugin"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/redact"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/storage"
+)
+
+var agentNames = []string{"Alice", "Bob", "Carol", "Dave", "Eve", "Frank", "Grace", "Heidi", "Ivan"}
+
+// Persona is a named perspective assigned to a debater.
+type Persona struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description" json:"description"`
+	Frame       string `yaml:"frame" json:"frame,omitempty"` // extra analytical instructions for this debater
+}
+
+// Job describes a single debate run.
+type Job struct {
+	Topic            string      `yaml:"topic" json:"topic"`
+	Name             string      `yaml:"name" json:"name"`
+	Agents           int         `yaml:"agents" json:"agents"`
+	MinRounds        int         `yaml:"min_rounds" json:"min_rounds"`
+	MaxRounds        int         `yaml:"max_rounds" json:"max_rounds"`
+	TenthManRounds   int         `yaml:"tenth_man_rounds" json:"tenth_man_rounds,omitempty"`
+	StagnationRounds int         `yaml:"stagnation_rounds" json:"stagnation_rounds,omitempty"` // 0 disables the early exit
+	AdaptiveRounds   bool        `yaml:"adaptive_rounds" json:"adaptive_rounds,omitempty"`     // end the free debate by the agreement score's velocity, between MinRounds and MaxRounds
+	TargetRounds     int         `yaml:"target_rounds" json:"target_rounds,omitempty"`         // with AdaptiveRounds, rounds the free debate lasts while the score holds steady; 0 is halfway
+	VelocityWindow   int         `yaml:"velocity_window" json:"velocity_window,omitempty"`     // with AdaptiveRounds, evaluations the velocity is measured over; 0 is 2
+	MinVelocity      float64     `yaml:"min_velocity" json:"min_velocity,omitempty"`           // with AdaptiveRounds, agreement points per round that count as movement; 0 is 0.5
+	Instructions     string      `yaml:"instructions" json:"instructions,omitempty"`
+	Personas         []Persona   `yaml:"personas" json:"personas,omitempty"`                     // assigned to agents in order
+	Experts          []string    `yaml:"experts" json:"experts,omitempty"`                       // built-in archetypes; replace Personas when set
+	Compress         string      `yaml:"compress" json:"compress,omitempty"`                     // "gzip" or "zstd" compresses transcript.json and debate.log on completion
+	EvidenceBudget   int         `yaml:"evidence_budget" json:"evidence_budget,omitempty"`       // max evidence queries when Retriever is set
+	Roster           []AgentSpec `yaml:"roster" json:"roster,omitempty"`                         // replaces Agents and Personas when set
+	Upload           string      `yaml:"upload" json:"upload,omitempty"`                         // s3:// or gs:// destination for the finished run directory
+	Layout           string      `yaml:"layout" json:"layout,omitempty"`                         // "flat" (default), "nested" or a template such as "{project}/{slug}-{seq}"
+	Project          string      `yaml:"project" json:"project,omitempty"`                       // project tag recorded in the transcript and available to Layout as {project}
+	Priority         int         `yaml:"priority" json:"priority,omitempty"`                     // in serve mode, queued runs of higher priority start first; equal priorities start in order
+	EncryptTo        []string    `yaml:"encrypt_to" json:"encrypt_to,omitempty"`                 // age or GPG recipients the finished artifacts are encrypted to
+	TokenBudget      int         `yaml:"token_budget" json:"token_budget,omitempty"`             // fail the run once this many LLM tokens are used; 0 is unlimited
+	RetryBudget      int         `yaml:"retry_budget" json:"retry_budget,omitempty"`             // end the debate early after this many retried LLM calls; 0 is unlimited
+	MaxTokens        int         `yaml:"max_tokens" json:"max_tokens,omitempty"`                 // completion cap for each turn; 0 leaves the client's cap
+	MaxWords         int         `yaml:"max_words" json:"max_words,omitempty"`                   // longer turns are restated or truncated; 0 is unlimited
+	MaxAgentFailures int         `yaml:"max_agent_failures" json:"max_agent_failures,omitempty"` // remove a debater after this many failed turns in a row; 0 fails the run
+	Samples          int         `yaml:"samples" json:"samples,omitempty"`                       // candidate replies drawn per turn, keeping the strongest; 0 or 1 draws one
+	SamplePick       string      `yaml:"sample_pick" json:"sample_pick,omitempty"`               // how the strongest sample is chosen: "llm" (default) or "heuristic"
+	Refine           bool        `yaml:"refine" json:"refine,omitempty"`                         // critique every turn's draft and revise it once before publishing
+	AllowPass        bool        `yaml:"allow_pass" json:"allow_pass,omitempty"`                 // debaters may answer PASS in the free debate when they have nothing new to add
+	Opening          bool        `yaml:"opening_statements" json:"opening_statements,omitempty"` // round 1 is a round of independent opening statements
+	Closing          bool        `yaml:"closing_statements" json:"closing_statements,omitempty"` // Phase 2 ends with a round of closing statements
+	ClassifyTopic    bool        `yaml:"classify_topic" json:"classify_topic,omitempty"`         // classify the topic as a question of fact or of value and adapt prompts and judging
+	TopicKind        string      `yaml:"topic_kind" json:"topic_kind,omitempty"`                 // "fact" or "value" to adapt to without classifying; "" leaves it to ClassifyTopic
+	CrossExamination int         `yaml:"cross_examination" json:"cross_examination,omitempty"`   // Phase 1 round run as a cross-examination; 0 disables it
+	Redact           bool        `yaml:"redact" json:"redact,omitempty"`                         // mask emails, keys and internal hostnames in the topic, evidence and artifacts
+	RedactPatterns   []string    `yaml:"redact_patterns" json:"redact_patterns,omitempty"`       // extra regular expressions to mask, optionally "name=regex"; imply Redact
+	Disagreement     bool        `yaml:"disagreement" json:"disagreement,omitempty"`             // score pairwise agent disagreement per round, one judge call each
+	FactCheck        bool        `yaml:"fact_check" json:"fact_check,omitempty"`                 // verify the factual claims of the final consensus position
+	FactCheckModel   string      `yaml:"fact_check_model" json:"fact_check_model,omitempty"`     // model for the fact check; "" is the judge's model
+	ReasoningEffort  string      `yaml:"reasoning_effort" json:"reasoning_effort,omitempty"`     // low, medium or high for reasoning models; "" is the model default
+	Images           []string    `yaml:"images" json:"-"`                                        // image files or URLs attached to the topic; only vision models are drawn; set from YAML and flags only, so API clients cannot read server files
+	Judge            string      `yaml:"judge" json:"judge,omitempty"`                           // consensus judge from Strategies; "" is DefaultJudge
+	TenthMan         string      `yaml:"tenth_man" json:"tenth_man,omitempty"`                   // Tenth Man activator from Strategies; "" is DefaultTenthMan
+	JudgeWindow      int         `yaml:"judge_window" json:"judge_window,omitempty"`             // judge only the last N rounds; 0 judges every round
+	StopWhen         string      `yaml:"stop_when" json:"stop_when,omitempty"`                   // condition expression ending the free debate without the Tenth Man, e.g. "round >= 6 && consensus.score < 4"
+	TenthManWhen     string      `yaml:"tenth_man_when" json:"tenth_man_when,omitempty"`         // condition expression replacing the built-in Tenth Man activation
+	ReportTemplate   string      `yaml:"report_template" json:"-"`                               // Go template file replacing the built-in report.md layout; set from YAML and flags only, so API clients cannot read server files
+	LogFormat        string      `yaml:"log_format" json:"log_format,omitempty"`                 // debate.log format: "text" (default) or "json" lines
+	Sinks            []string    `yaml:"sinks" json:"-"`                                         // extra destinations for engine events, e.g. "webhook:https://..."; set from YAML and flags only, so API clients cannot write files or make requests
+	Plugins          []string    `yaml:"plugins" json:"-"`                                       // commands run on every turn and the finished debate, JSON on stdin; set from YAML and flags only, so API clients cannot run commands
+
+	// Strategies, if set, is where Judge and TenthMan are looked up, so
+	// callers can register their own; otherwise only the built-ins exist.
+	Strategies *Strategies `yaml:"-" json:"-"`
+	// Retriever answers agents' evidence requests. It is set by callers such
+	// as research mode and is not part of the serialized job.
+	Retriever debate.Retriever `yaml:"-" json:"-"`
+	// Events delivers new information to inject into the debate while it
+	// runs, as moderator notes at the start of the next round.
+	Events <-chan string `yaml:"-" json:"-"`
+	// Swaps delivers agent model changes to apply while the debate runs,
+	// from the next round on. Only Agent and To are read.
+	Swaps <-chan debate.ModelSwap `yaml:"-" json:"-"`
+	// Removals delivers debaters to take out of the debate while it runs,
+	// from the next round on. Only Agent and Reason are read.
+	Removals <-chan debate.AgentRemoval `yaml:"-" json:"-"`
+	// Joins delivers debaters to add to the debate while it runs, from the
+	// next round on. An agent without a Model is given the model the next
+	// debater would be assigned.
+	Joins <-chan debate.Agent `yaml:"-" json:"-"`
+	// Cancel, once closed, stops the debate cleanly: the round under way is
+	// dropped and the rounds already completed are saved as a partial run.
+	Cancel <-chan struct{} `yaml:"-" json:"-"`
+	// Progress, if set, receives every engine event while the debate runs.
+	// It must be drained until Run returns.
+	Progress chan<- debate.Event `yaml:"-" json:"-"`
+	// Checkpoint, if set, saves the transcript after every round.
+	Checkpoint debate.Checkpointer `yaml:"-" json:"-"`
+	// Middleware runs around every state of the debate engine, first
+	// outermost, for callers' own work between phases; see debate.Around.
+	Middleware []debate.Middleware `yaml:"-" json:"-"`
+	// Resume, if set, is the last checkpoint of an interrupted run of this
+	// job. The debate picks up after its last round, with the same debaters,
+	// in a new run directory.
+	Resume *debate.Transcript `yaml:"-" json:"-"`
+}
+
+// WithDefaults returns j with its zero-valued settings taken from defaults.
+// Topic and Name are never defaulted.
+func (j Job) WithDefaults(defaults Job) Job {
+	if len(j.Roster) == 0 {
+		j.Roster = defaults.Roster
+	}
+	if len(j.Roster) > 0 {
+		j.Agents = rosterDebaters(j.Roster)
+	}
+	if j.Agents == 0 {
+		j.Agents = defaults.Agents
+	}
+	if j.MinRounds == 0 {
+		j.MinRounds = defaults.MinRounds
+	}
+	if j.MaxRounds == 0 {
+		j.MaxRounds = defaults.MaxRounds
+	}
+	if j.TenthManRounds == 0 {
+		j.TenthManRounds = defaults.TenthManRounds
+	}
+	if j.StagnationRounds == 0 {
+		j.StagnationRounds = defaults.StagnationRounds
+	}
+	j.AdaptiveRounds = j.AdaptiveRounds || defaults.AdaptiveRounds
+	if j.TargetRounds == 0 {
+		j.TargetRounds = defaults.TargetRounds
+	}
+	if j.VelocityWindow == 0 {
+		j.VelocityWindow = defaults.VelocityWindow
+	}
+	if j.MinVelocity == 0 {
+		j.MinVelocity = defaults.MinVelocity
+	}
+	if j.EvidenceBudget == 0 {
+		j.EvidenceBudget = defaults.EvidenceBudget
+	}
+	if j.TokenBudget == 0 {
+		j.TokenBudget = defaults.TokenBudget
+	}
+	if j.RetryBudget == 0 {
+		j.RetryBudget = defaults.RetryBudget
+	}
+	if j.MaxTokens == 0 {
+		j.MaxTokens = defaults.MaxTokens
+	}
+	if j.MaxWords == 0 {
+		j.MaxWords = defaults.MaxWords
+	}
+	if j.MaxAgentFailures == 0 {
+		j.MaxAgentFailures = defaults.MaxAgentFailures
+	}
+	if j.Samples == 0 {
+		j.Samples = defaults.Samples
+	}
+	if j.SamplePick == "" {
+		j.SamplePick = defaults.SamplePick
+	}
+	j.Refine = j.Refine || defaults.Refine
+	j.AllowPass = j.AllowPass || defaults.AllowPass
+	j.Opening = j.Opening || defaults.Opening
+	j.Closing = j.Closing || defaults.Closing
+	j.ClassifyTopic = j.ClassifyTopic || defaults.ClassifyTopic
+	if j.TopicKind == "" {
+		j.TopicKind = defaults.TopicKind
+	}
+	if j.CrossExamination == 0 {
+		j.CrossExamination = defaults.CrossExamination
+	}
+	j.Redact = j.Redact || defaults.Redact
+	if len(j.RedactPatterns) == 0 {
+		j.RedactPatterns = defaults.RedactPatterns
+	}
+	j.Disagreement = j.Disagreement || defaults.Disagreement
+	j.FactCheck = j.FactCheck || defaults.FactCheck
+	if j.FactCheckModel == "" {
+		j.FactCheckModel = defaults.FactCheckModel
+	}
+	if j.ReasoningEffort == "" {
+		j.ReasoningEffort = defaults.ReasoningEffort
+	}
+	if j.Judge == "" {
+		j.Judge = defaults.Judge
+	}
+	if j.TenthMan == "" {
+		j.TenthMan = defaults.TenthMan
+	}
+	if j.Strategies == nil {
+		j.Strategies = defaults.Strategies
+	}
+	if j.Middleware == nil {
+		j.Middleware = defaults.Middleware
+	}
+	if j.JudgeWindow == 0 {
+		j.JudgeWindow = defaults.JudgeWindow
+	}
+	if j.StopWhen == "" {
+		j.StopWhen = defaults.StopWhen
+	}
+	if j.TenthManWhen == "" {
+		j.TenthManWhen = defaults.TenthManWhen
+	}
+	if j.Compress == "" {
+		j.Compress = defaults.Compress
+	}
+	if j.Upload == "" {
+		j.Upload = defaults.Upload
+	}
+	if j.Layout == "" {
+		j.Layout = defaults.Layout
+	}
+	if j.Project == "" {
+		j.Project = defaults.Project
+	}
+	if j.Priority == 0 {
+		j.Priority = defaults.Priority
+	}
+	if len(j.EncryptTo) == 0 {
+		j.EncryptTo = defaults.EncryptTo
+	}
+	if j.ReportTemplate == "" {
+		j.ReportTemplate = defaults.ReportTemplate
+	}
+	if j.LogFormat == "" {
+		j.LogFormat = defaults.LogFormat
+	}
+	if len(j.Sinks) == 0 {
+		j.Sinks = defaults.Sinks
+	}
+	if len(j.Plugins) == 0 {
+		j.Plugins = defaults.Plugins
+	}
+	if j.Instructions == "" {
+		j.Instructions = defaults.Instructions
+	}
+	if len(j.Personas) == 0 {
+		j.Personas = defaults.Personas
+	}
+	if len(j.Experts) == 0 {
+		j.Experts = defaults.Experts
+	}
+	return j
+}
+
+// Validate checks that the job can be run.
+func (j Job) Validate() error {
+	if j.Topic == "" {
+		return fmt.Errorf("runner: topic is required")
+	}
+	if len(j.Roster) > 0 {
+		if n := rosterDebaters(j.Roster); n < 3 {
+			return fmt.Errorf("runner: roster must define >= 3 debaters, got %d", n)
+		}
+	} else if j.Agents < 3 {
+		return fmt.Errorf("runner: agent count must be >= 3, got %d", j.Agents)
+	}
+	if len(j.Experts) > 0 {
+		if _, err := ExpertPersonas(j.Experts); err != nil {
+			return err
+		}
+		if len(j.Roster) == 0 && len(j.Experts) > j.Agents {
+			return fmt.Errorf("runner: %d experts requested but only %d agents", len(j.Experts), j.Agents)
+		}
+	}
+	if j.MinRounds < 1 {
+		return fmt.Errorf("runner: min rounds must be >= 1, got %d", j.MinRounds)
+	}
+	if j.MaxRounds < j.MinRounds {
+		return fmt.Errorf("runner: max rounds (%d) must be >= min rounds (%d)", j.MaxRounds, j.MinRounds)
+	}
+	if j.TokenBudget < 0 {
+		return fmt.Errorf("runner: token budget must be >= 0, got %d", j.TokenBudget)
+	}
+	if j.RetryBudget < 0 {
+		return fmt.Errorf("runner: retry budget must be >= 0, got %d", j.RetryBudget)
+	}
+	if j.MaxTokens < 0 {
+		return fmt.Errorf("runner: max tokens must be >= 0, got %d", j.MaxTokens)
+	}
+	if j.MaxWords < 0 {
+		return fmt.Errorf("runner: max words must be >= 0, got %d", j.MaxWords)
+	}
+	if j.MaxAgentFailures < 0 {
+		return fmt.Errorf("runner: max agent failures must be >= 0, got %d", j.MaxAgentFailures)
+	}
+	if j.TargetRounds != 0 && (j.TargetRounds < j.MinRounds || j.TargetRounds > j.MaxRounds) {
+		return fmt.Errorf("runner: target rounds (%d) must be between min rounds (%d) and max rounds (%d)", j.TargetRounds, j.MinRounds, j.MaxRounds)
+	}
+	if j.VelocityWindow < 0 {
+		return fmt.Errorf("runner: velocity window must be >= 0, got %d", j.VelocityWindow)
+	}
+	if j.MinVelocity < 0 {
+		return fmt.Errorf("runner: min velocity must be >= 0, got %g", j.MinVelocity)
+	}
+	if j.JudgeWindow < 0 {
+		return fmt.Errorf("runner: judge window must be >= 0, got %d", j.JudgeWindow)
+	}
+	if _, err := condition(j.StopWhen); err != nil {
+		return fmt.Errorf("runner: stop_when: %w", err)
+	}
+	if _, err := condition(j.TenthManWhen); err != nil {
+		return fmt.Errorf("runner: tenth_man_when: %w", err)
+	}
+	if _, err := j.strategies().judge(j.Judge); err != nil {
+		return err
+	}
+	if _, err := j.strategies().tenthMan(j.TenthMan); err != nil {
+		return err
+	}
+	if err := output.ValidateCompression(j.Compress); err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	if err := output.ValidateLayout(j.Layout); err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	if err := output.ValidateLogFormat(j.LogFormat); err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	if err := openrouter.ValidateEffort(j.ReasoningEffort); err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	if j.CrossExamination < 0 {
+		return fmt.Errorf("runner: cross examination must be >= 0, got %d", j.CrossExamination)
+	}
+	if j.Samples < 0 {
+		return fmt.Errorf("runner: samples must be >= 0, got %d", j.Samples)
+	}
+	if j.TopicKind != "" && j.TopicKind != debate.QuestionOfFact && j.TopicKind != debate.QuestionOfValue {
+		return fmt.Errorf("runner: topic kind must be %q or %q, got %q", debate.QuestionOfFact, debate.QuestionOfValue, j.TopicKind)
+	}
+	if j.SamplePick != "" && j.SamplePick != debate.PickLLM && j.SamplePick != debate.PickHeuristic {
+		return fmt.Errorf("runner: sample pick must be %q or %q, got %q", debate.PickLLM, debate.PickHeuristic, j.SamplePick)
+	}
+	if _, err := redact.New(j.RedactPatterns); err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	for _, spec := range j.Sinks {
+		if _, err := newSink(spec, "", nil); err != nil {
+			return err
+		}
+	}
+	for _, command := range j.Plugins {
+		if _, err := plugin.New(command); err != nil {
+			return fmt.Errorf("runner: %w", err)
+		}
+	}
+	if j.Upload != "" {
+		if _, err := storage.NewSink(j.Upload); err != nil {
+			return fmt.Errorf("runner: %w", err)
+		}
+	}
+	if err := output.ValidateRecipients(j.EncryptTo); err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	if j.ReportTemplate != "" {
+		if _, err := output.LoadReportTemplate(j.ReportTemplate); err != nil {
+			return fmt.Errorf("runner: %w", err)
+		}
+	}
+	return nil
+}
+
+// windowedJudge is a consensus judge that can limit its evaluation to the
+// latest rounds.
+type windowedJudge interface {
+	SetWindow(rounds int)
+}
+
+// strategies returns the strategies the job's judge and Tenth Man are chosen
+// from.
+func (j Job) strategies() *Strategies {
+	if j.Strategies != nil {
+		return j.Strategies
+	}
+	return builtinStrategies
+}
+
+// Hooks are optional callbacks invoked while a job runs.
+type Hooks struct {
+	OnStart    func(dir string)
+	OnTurn     func(debate.Turn)
+	OnPhase    func(debate.Phase)
+	OnEvidence func(debate.Evidence)
+	OnEvent    func(debate.Event) // every engine event, after the specific hook
+}
+
+// Outcome is the result of a completed job.
+type Outcome struct {
+	Dir       string
+	Result    *debate.Result
+	Consensus *debate.ConsensusResult // never nil
+	Claims    []debate.Claim
+	Actions   *debate.ActionItems // nil if extraction failed
+	Uploaded  string              // remote location of the run directory when Job.Upload is set
+	Tokens    int                 // LLM tokens consumed, as reported by the provider
+}
+
+// Run executes job against llm, writing its artifacts into a new run
+// directory under outputBase. Models are drawn from registry, each role
+// getting the ones that best meet its requirements; see Registry.Assign.
+func Run(ctx context.Context, llm debate.LLMClient, registry *models.Registry, outputBase string, job Job, hooks Hooks) (*Outcome, error) {
+	metered := &meteredLLM{LLMClient: llm, budget: job.TokenBudget}
+	outcome, err := run(ctx, metered, registry, outputBase, job, hooks)
+	if outcome != nil {
+		outcome.Tokens = int(metered.tokens.Load())
+	}
+	return outcome, err
+}
+
+func run(ctx context.Context, llm debate.LLMClient, registry *models.Registry, outputBase string, job Job, hooks Hooks) (*Outcome, error) {
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+	if len(job.Roster) > 0 {
+		job.Agents = rosterDebaters(job.Roster)
+	}
+	injections := cleanInputs(&job)
+	redactor := jobRedactor(job)
+	if redactor != nil {
+		// Context documents are masked before any model sees them; turns
+		// are masked as the writer saves them.
+		job.Topic = redactor.Redact(job.Topic)
+		if job.Retriever != nil {
+			job.Retriever = redactingRetriever{job.Retriever, redactor}
+		}
+	}
+	if len(job.Experts) > 0 {
+		job.Personas, _ = ExpertPersonas(job.Experts)
+	}
+	images, imageRefs, err := loadImages(job.Images)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) > 0 {
+		if registry, err = visionRegistry(registry); err != nil {
+			return nil, err
+		}
+	}
+
+	assignment, ok := registry.Assign(job.Agents)
+	if !ok {
+		return nil, fmt.Errorf("runner: no free models available")
+	}
+	selected := assignment.Debaters
+	tenthManModel := assignment.TenthMan.ID
+	judgeModel := assignment.Judge.ID
+
+	var agents []debate.Agent
+	if len(job.Roster) > 0 {
+		var model string
+		var err error
+		if agents, model, err = rosterAgents(job.Roster, selected); err != nil {
+			return nil, err
+		}
+		if model != "" {
+			tenthManModel = model
+		}
+	} else {
+		agents = personaAgents(job, selected)
+	}
+	for i := range agents {
+		if agents[i].ReasoningEffort == "" {
+			agents[i].ReasoningEffort = job.ReasoningEffort
+		}
+	}
+	if job.Resume != nil {
+		if prior, model := priorAgents(job.Resume); len(prior) > 0 {
+			agents = prior
+			if model != "" {
+				tenthManModel = model
+			}
+		}
+	}
+
+	newJudge, _ := job.strategies().judge(job.Judge)
+	newTenthMan, _ := job.strategies().tenthMan(job.TenthMan)
+	judge := newJudge(llm, judgeModel)
+	if w, ok := judge.(windowedJudge); ok && job.JudgeWindow > 0 {
+		w.SetWindow(job.JudgeWindow)
+	}
+	tm := newTenthMan()
+
+	slug := job.Name
+	if slug == "" {
+		slug = output.GenerateSlug(job.Topic)
+	}
+	outDir, err := output.CreateOutputDir(outputBase, slug, job.Layout, job.Project)
+	if err != nil {
+		return nil, fmt.Errorf("runner: creating output directory: %w", err)
+	}
+	if hooks.OnStart != nil {
+		hooks.OnStart(outDir)
+	}
+
+	writer := output.NewWriter(outDir)
+	writer.SetLogFormat(job.LogFormat)
+	writer.SetRedactor(redactor)
+	if job.ReportTemplate != "" {
+		tmpl, err := output.LoadReportTemplate(job.ReportTemplate)
+		if err != nil {
+			return &Outcome{Dir: outDir}, fmt.Errorf("runner: %w", err)
+		}
+		writer.SetReportTemplate(tmpl)
+	}
+	for _, w := range injections {
+		writer.Log(fmt.Sprintf("Warning: the %s looks like a prompt injection attempt (%s)", w.Source, strings.Join(w.Kinds, ", ")))
+	}
+
+	class := topicClass(ctx, llm, judgeModel, job, writer)
+	if job.Resume != nil {
+		job.Resume.TopicClass = class
+	}
+
+	engine := debate.NewEngine(job.Topic, agents, llm, judge, tm, job.MinRounds, job.MaxRounds)
+	engine.SetTopicClass(class)
+	engine.SetTenthManModel(tenthManModel)
+	engine.SetTenthManRounds(job.TenthManRounds)
+	engine.SetInstructions(job.Instructions)
+	engine.SetImages(images)
+	engine.SetStagnation(job.StagnationRounds, debate.DefaultMinNovelty)
+	if job.AdaptiveRounds {
+		engine.SetAdaptiveRounds(&debate.AdaptiveRounds{Target: job.TargetRounds, Window: job.VelocityWindow, MinVelocity: job.MinVelocity})
+	}
+	engine.SetRetryBudget(job.RetryBudget)
+	engine.SetMaxTokens(job.MaxTokens)
+	engine.SetMaxWords(job.MaxWords)
+	engine.SetContextWindows(registry.ContextWindows())
+	engine.SetMaxFailures(job.MaxAgentFailures)
+	engine.SetSamples(job.Samples, cmp.Or(job.SamplePick, debate.PickLLM))
+	engine.SetRefine(job.Refine)
+	engine.SetPassing(job.AllowPass)
+	engine.SetStatements(job.Opening, job.Closing)
+	engine.SetCrossExamination(job.CrossExamination)
+	stopWhen, _ := condition(job.StopWhen)
+	engine.SetStopCondition(stopWhen)
+	tenthManWhen, _ := condition(job.TenthManWhen)
+	engine.SetTenthManCondition(tenthManWhen)
+	engine.SetFallbackModels(modelIDs(registry.FreeModels()))
+	if job.Retriever != nil {
+		engine.SetRetriever(job.Retriever, job.EvidenceBudget)
+	}
+	if job.Checkpoint != nil {
+		engine.SetCheckpointer(job.Checkpoint)
+	}
+	engine.Use(job.Middleware...)
+	seed := job.Resume
+	if seed == nil {
+		seed = &debate.Transcript{Topic: job.Topic}
+	}
+	sinks, err := newSinks(writer, hooks, job.Sinks, seed)
+	if err != nil {
+		return &Outcome{Dir: outDir}, err
+	}
+	if job.Progress != nil {
+		sinks.Add(sendEvents(job.Progress))
+	}
+	plugins, err := plugin.NewSet(job.Plugins, outDir, job.Topic, writer.Log)
+	if err != nil {
+		return &Outcome{Dir: outDir}, fmt.Errorf("runner: %w", err)
+	}
+	sinks.Add(plugins)
+	stop := streamEvents(engine, sinks)
+
+	if job.Events != nil {
+		eventsCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go forwardEvents(eventsCtx, job.Events, engine)
+	}
+	if job.Swaps != nil {
+		swapsCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go forwardSwaps(swapsCtx, job.Swaps, engine)
+	}
+	if job.Removals != nil {
+		removalsCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go forwardRemovals(removalsCtx, job.Removals, engine)
+	}
+	if job.Joins != nil {
+		joinsCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go forwardJoins(joinsCtx, job.Joins, engine, registry, len(agents), job.ReasoningEffort)
+	}
+	if job.Cancel != nil {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go forwardCancel(cancelCtx, job.Cancel, engine)
+	}
+
+	var result *debate.Result
+	if job.Resume != nil {
+		writer.Log(fmt.Sprintf("Resuming after round %d", job.Resume.Rounds))
+		result, err = engine.Resume(ctx, job.Resume)
+	} else {
+		result, err = engine.Run(ctx)
+	}
+	if err := stop(); err != nil {
+		writer.Log(fmt.Sprintf("Event sink failed: %v", err))
+	}
+	if err != nil {
+		return &Outcome{Dir: outDir}, fmt.Errorf("runner: %w", err)
+	}
+
+	if result.Stagnated {
+		writer.Log(fmt.Sprintf("Phase 1 ended early after round %d: rounds stopped adding new information", result.Transcript.Rounds))
+	}
+	if result.Settled != "" {
+		writer.Log(fmt.Sprintf("Phase 1 ended after round %d: %s", result.Transcript.Rounds, result.Settled))
+	}
+	switch {
+	case result.Stopped:
+		writer.Log(fmt.Sprintf("Debate cancelled after round %d", result.Transcript.Rounds))
+	case result.Partial:
+		writer.Log(fmt.Sprintf("Debate ended early after round %d: the retry budget of %d was spent", result.Transcript.Rounds, job.RetryBudget))
+	}
+
+	result.Transcript.JudgeModel = judgeModel
+	result.Transcript.Images = imageRefs
+	result.Transcript.Project = job.Project
+	if job.Resume == nil {
+		// A resumed transcript already records the warnings about its inputs.
+		result.Transcript.InjectionWarnings = append(injections, result.Transcript.InjectionWarnings...)
+	}
+	if job.Disagreement {
+		// Like the other post-debate analyses, a failure is logged rather
+		// than failing the run.
+		rounds, err := disagreement.NewAnalyzer(llm, judgeModel).Analyze(ctx, result.Transcript)
+		if err != nil {
+			writer.Log(fmt.Sprintf("Disagreement analysis failed: %v", err))
+		} else {
+			result.Transcript.Disagreement = rounds
+		}
+	}
+	var checker *factcheck.Checker
+	if job.FactCheck && class != nil && class.Kind == debate.QuestionOfValue {
+		writer.Log("Fact check skipped: the topic is a question of value")
+	} else if job.FactCheck {
+		checker = factcheck.NewChecker(llm, cmp.Or(job.FactCheckModel, judgeModel))
+		if job.Retriever != nil {
+			checker.SetRetriever(job.Retriever)
+		}
+	}
+	outcome, err := saveResult(ctx, llm, writer, judgeModel, checker, result)
+	if err != nil {
+		return outcome, err
+	}
+	plugins.Completed(ctx, result)
+	if err := writer.WriteLog(); err != nil {
+		return &Outcome{Dir: outDir}, fmt.Errorf("runner: writing log: %w", err)
+	}
+	return outcome, finish(ctx, writer, outcome, job.Compress, job.EncryptTo, job.Upload)
+}
+
+// cleanInputs cleans job's topic and instructions, which hold any context
+// documents, of invisible and control characters, and returns a warning for
+// each that looked like a prompt injection attempt before.
+func cleanInputs(job *Job) []debate.InjectionWarning {
+	var warnings []debate.InjectionWarning
+	for _, in := range []struct {
+		source string
+		text   *string
+	}{{"topic", &job.Topic}, {"instructions", &job.Instructions}} {
+		if kinds := guard.Detect(*in.text); len(kinds) > 0 {
+			warnings = append(warnings, debate.InjectionWarning{Source: in.source, Kinds: kinds})
+		}
+		*in.text = guard.Clean(*in.text)
+	}
+	return warnings
+}
+
+// topicClass returns the class of job's topic: the kind job gives, the
+// class a resumed transcript records or, if job asks for it, the judge
+// model's classification. It returns nil if there is none; a failed
+// classification is logged rather than failing the run.
+func topicClass(ctx context.Context, llm debate.LLMClient, model string, job Job, writer *output.Writer) *debate.TopicClass {
+	var class *debate.TopicClass
+	switch {
+	case job.TopicKind != "":
+		class = &debate.TopicClass{Kind: job.TopicKind}
+	case job.Resume != nil && job.Resume.TopicClass != nil:
+		return job.Resume.TopicClass
+	case job.ClassifyTopic:
+		var err error
+		if class, err = topics.NewClassifier(llm, model).Classify(ctx, job.Topic); err != nil {
+			writer.Log(fmt.Sprintf("Topic classification failed: %v", err))
+			return nil
+		}
+	}
+	if class != nil {
+		msg := fmt.Sprintf("Topic classified as a question of %s", class.Kind)
+		if class.Reason != "" {
+			msg += ": " + class.Reason
+		}
+		writer.Log(msg)
+	}
+	return class
+}
+
+// condition compiles src, a condition over debate.Facts, for the engine. An
+// empty src is no condition.
+func condition(src string) (debate.Condition, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil
+	}
+	e, err := expr.Compile(src, debate.Facts{}.Env())
+	if err != nil {
+		return nil, err
+	}
+	return func(f debate.Facts) (bool, error) {
+		return e.Eval(f.Env())
+	}, nil
+}
+
+// jobRedactor returns the redactor job asks for, or nil if it asks for none.
+// The patterns must have passed Validate.
+func jobRedactor(job Job) *redact.Redactor {
+	if !job.Redact && len(job.RedactPatterns) == 0 {
+		return nil
+	}
+	redactor, _ := redact.New(job.RedactPatterns)
+	return redactor
+}
+
+// redactingRetriever masks secrets in the evidence a Retriever returns.
+type redactingRetriever struct {
+	debate.Retriever
+	redactor *redact.Redactor
+}
+
+func (r redactingRetriever) Retrieve(ctx context.Context, query string) (string, error) {
+	result, err := r.Retriever.Retrieve(ctx, query)
+	return r.redactor.Redact(result), err
+}
+
+// forwardEvents injects every event received into engine until ctx is done
+// or events is closed.
+func forwardEvents(ctx context.Context, events <-chan string, engine *debate.Engine) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			engine.InjectEvent(ev)
+		}
+	}
+}
+
+// forwardSwaps queues every model swap received on engine until ctx is done
+// or swaps is closed.
+func forwardSwaps(ctx context.Context, swaps <-chan debate.ModelSwap, engine *debate.Engine) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case swap, ok := <-swaps:
+			if !ok {
+				return
+			}
+			engine.SwapModel(swap.Agent, swap.To)
+		}
+	}
+}
+
+// forwardRemovals queues every agent removal received on engine until ctx
+// is done or removals is closed.
+func forwardRemovals(ctx context.Context, removals <-chan debate.AgentRemoval, engine *debate.Engine) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r, ok := <-removals:
+			if !ok {
+				return
+			}
+			engine.RemoveAgent(r.Agent, r.Reason)
+		}
+	}
+}
+
+// forwardJoins adds every agent received to engine until ctx is done or
+// joins is closed. Agents without a model are given the one registry would
+// assign the next debater after debaters, and reasoningEffort if they set
+// none.
+func forwardJoins(ctx context.Context, joins <-chan debate.Agent, engine *debate.Engine, registry *models.Registry, debaters int, reasoningEffort string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case agent, ok := <-joins:
+			if !ok {
+				return
+			}
+			if agent.Model == "" {
+				if assignment, ok := registry.Assign(debaters + 1); ok {
+					agent.Model = assignment.Debaters[debaters].ID
+				}
+				debaters++
+			}
+			if agent.ReasoningEffort == "" {
+				agent.ReasoningEffort = reasoningEffort
+			}
+			engine.AddAgent(agent)
+		}
+	}
+}
+
+// forwardCancel stops engine once cancel is closed, unless ctx is done
+// first.
+func forwardCancel(ctx context.Context, cancel <-chan struct{}, engine *debate.Engine) {
+	select {
+	case <-ctx.Done():
+	case <-cancel:
+		engine.Stop()
+	}
+}
+
+// saveResult writes the transcript, report and claims for result into the
+// writer's directory. model extracts the claims, and checker, if not nil,
+// fact-checks the consensus position.
+func saveResult(ctx context.Context, llm debate.LLMClient, writer *output.Writer, model string, checker *factcheck.Checker, result *debate.Result) (*Outcome, error) {
+	outDir := writer.Dir()
+	cons := result.Consensus
+	if cons == nil {
+		cons = &debate.ConsensusResult{}
+	}
+	// The executive summary is a by-product of a finished debate as well, so
+	// a failure leaves it out of the report rather than failing the run.
+	bullets, err := summary.NewSummarizer(llm, model).Summarize(ctx, result.Transcript, result.Consensus)
+	if err != nil {
+		writer.Log(fmt.Sprintf("Executive summary failed: %v", err))
+	} else {
+		result.Transcript.Summary = bullets
+	}
+	// So is the fact check. Verdicts from an earlier save are dropped: they
+	// judged a position that may since have changed.
+	result.Transcript.FactChecks = nil
+	if checker != nil {
+		checks, err := checker.Check(ctx, result.Transcript.Topic, cons.Position)
+		if err != nil {
+			writer.Log(fmt.Sprintf("Fact check failed: %v", err))
+		} else {
+			result.Transcript.FactChecks = checks
+		}
+	}
+	// So is the reasoning quality analysis.
+	flags, err := fallacies.NewDetector(llm, model).Detect(ctx, result.Transcript)
+	if err != nil {
+		writer.Log(fmt.Sprintf("Reasoning analysis failed: %v", err))
+	} else {
+		result.Transcript.ReasoningFlags = flags
+	}
+	if metered, ok := llm.(*meteredLLM); ok {
+		// A continued debate adds to the tokens its transcript already records.
+		result.Transcript.Tokens += int(metered.tokens.Load())
+	}
+	if err := writer.WriteJSON(result.Transcript); err != nil {
+		return &Outcome{Dir: outDir}, fmt.Errorf("runner: writing JSON: %w", err)
+	}
+	if err := writer.WriteMarkdown(result.Transcript, cons, result.MinorityReports); err != nil {
+		return &Outcome{Dir: outDir}, fmt.Errorf("runner: writing markdown: %w", err)
+	}
+	if err := writer.WriteDisagreement(result.Transcript); err != nil {
+		return &Outcome{Dir: outDir}, fmt.Errorf("runner: writing disagreement heatmap: %w", err)
+	}
+	if err := writer.WriteMetrics(result.Transcript); err != nil {
+		return &Outcome{Dir: outDir}, fmt.Errorf("runner: writing metrics: %w", err)
+	}
+	// Claims are a by-product of a finished debate, so a failed extraction is
+	// logged rather than failing the run.
+	extracted, err := claims.NewExtractor(llm, model).Extract(ctx, result.Transcript)
+	if err != nil {
+		writer.Log(fmt.Sprintf("Claims extraction failed: %v", err))
+	} else if err := writer.WriteClaims(result.Transcript.Topic, extracted); err != nil {
+		return &Outcome{Dir: outDir}, fmt.Errorf("runner: writing claims: %w", err)
+	}
+	// So are action items.
+	items, err := actions.NewExtractor(llm, model).Extract(ctx, result.Transcript)
+	if err != nil {
+		writer.Log(fmt.Sprintf("Action items extraction failed: %v", err))
+	} else if err := writer.WriteActions(result.Transcript.Topic, items); err != nil {
+		return &Outcome{Dir: outDir}, fmt.Errorf("runner: writing action items: %w", err)
+	}
+	return &Outcome{Dir: outDir, Result: result, Consensus: cons, Claims: extracted, Actions: items}, nil
+}
+
+// finish compresses and encrypts the saved artifacts and uploads the run
+// directory, as configured.
+func finish(ctx context.Context, writer *output.Writer, outcome *Outcome, compress string, encryptTo []string, upload string) error {
+	if err := output.CompressArtifacts(outcome.Dir, compress); err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	if err := writer.EncryptArtifacts(ctx, encryptTo); err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	if upload == "" {
+		return nil
+	}
+	sink, err := storage.NewSink(upload)
+	if err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	if outcome.Uploaded, err = sink.Upload(ctx, outcome.Dir); err != nil {
+		return fmt.Errorf("runner: %w", err)
+	}
+	return nil
+}
+
+// modelIDs returns the IDs of models, in order.
+func modelIDs(models []openrouter.Model) []string {
+	ids := make([]string, len(models))
+	for i, m := range models {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+// personaAgents builds job.Agents debaters on the selected models, applying
+// job.Personas in order.
+func personaAgents(job Job, selected []openrouter.Model) []debate.Agent {
+	agents := make([]debate.Agent, job.Agents)
+	for i := range job.Agents {
+		agentName := fmt.Sprintf("Agent-%d", i+1)
+		if i < len(agentNames) {
+			agentName = agentNames[i]
+		}
+		var persona Persona
+		if i < len(job.Personas) {
+			persona = job.Personas[i]
+			if persona.Name != "" {
+				agentName = persona.Name
+			}
+		}
+		agents[i] = debate.Agent{
+			ID:      i + 1,
+			Name:    agentName,
+			Model:   selected[i].ID,
+			Role:    "debater",
+			Persona: persona.Description,
+			Frame:   persona.Frame,
+		}
+	}
+	return agents
+}
@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/consensus"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/tenthman"
+)
+
+// Default strategy names, used when a job does not choose one.
+const (
+	DefaultJudge    = "llm"
+	DefaultTenthMan = "contrarian"
+)
+
+// JudgeFactory builds a consensus judge for one run. llm and model are the
+// client and model the built-in LLM judge uses.
+type JudgeFactory func(llm debate.LLMClient, model string) debate.ConsensusJudge
+
+// TenthManFactory builds a Tenth Man activator for one run.
+type TenthManFactory func() debate.TenthManActivator
+
+// Strategies is the set of consensus judges and Tenth Man activators a job
+// can choose from by name. Its zero value is not usable; use NewStrategies.
+type Strategies struct {
+	judges   map[string]JudgeFactory
+	tenthMen map[string]TenthManFactory
+}
+
+// NewStrategies returns the built-in strategies:
+//   - judges "llm", which asks a model, and "keyword-vote", which counts
+//     agreement words without an LLM
+//   - Tenth Man activators "contrarian", which argues the contrary position,
+//     "rotating", a devil's advocate who changes angle every turn, and
+//     "socratic", who challenges through questions the debaters must answer
+func NewStrategies() *Strategies {
+	return &Strategies{
+		judges: map[string]JudgeFactory{
+			"llm": func(llm debate.LLMClient, model string) debate.ConsensusJudge {
+				return consensus.NewJudge(llm, model)
+			},
+			"keyword-vote": func(debate.LLMClient, string) debate.ConsensusJudge {
+				return consensus.NewKeywordJudge()
+			},
+		},
+		tenthMen: map[string]TenthManFactory{
+			"contrarian": func() debate.TenthManActivator { return tenthman.NewActivator() },
+			"rotating":   func() debate.TenthManActivator { return tenthman.NewRotatingActivator() },
+			"socratic":   func() debate.TenthManActivator { return tenthman.NewSocraticActivator() },
+		},
+	}
+}
+
+// builtinStrategies serves jobs that do not set Strategies.
+var builtinStrategies = NewStrategies()
+
+// RegisterJudge makes a consensus judge available as name.
+func (s *Strategies) RegisterJudge(name string, f JudgeFactory) error {
+	if name == "" || f == nil {
+		return fmt.Errorf("runner: register judge: name and factory are required")
+	}
+	if _, ok := s.judges[name]; ok {
+		return fmt.Errorf("runner: judge %q is already registered", name)
+	}
+	s.judges[name] = f
+	return nil
+}
+
+// RegisterTenthMan makes a Tenth Man activator available as name.
+func (s *Strategies) RegisterTenthMan(name string, f TenthManFactory) error {
+	if name == "" || f == nil {
+		return fmt.Errorf("runner: register tenth man: name and factory are required")
+	}
+	if _, ok := s.tenthMen[name]; ok {
+		return fmt.Errorf("runner: tenth man %q is already registered", name)
+	}
+	s.tenthMen[name] = f
+	return nil
+}
+
+// Judges returns the names of the registered consensus judges, sorted.
+func (s *Strategies) Judges() []string {
+	return slices.Sorted(maps.Keys(s.judges))
+}
+
+// TenthMen returns the names of the registered Tenth Man activators, sorted.
+func (s *Strategies) TenthMen() []string {
+	return slices.Sorted(maps.Keys(s.tenthMen))
+}
+
+// judge returns the judge factory registered as name, or the default one
+// when name is empty.
+func (s *Strategies) judge(name string) (JudgeFactory, error) {
+	if name == "" {
+		name = DefaultJudge
+	}
+	f, ok := s.judges[name]
+	if !ok {
+		return nil, fmt.Errorf("runner: unknown judge %q (available: %s)", name, strings.Join(s.Judges(), ", "))
+	}
+	return f, nil
+}
+
+// tenthMan returns the activator factory registered as name, or the default
+// one when name is empty.
+func (s *Strategies) tenthMan(name string) (TenthManFactory, error) {
+	if name == "" {
+		name = DefaultTenthMan
+	}
+	f, ok := s.tenthMen[name]
+	if !ok {
+		return nil, fmt.Errorf("runner: unknown tenth man %q (available: %s)", name, strings.Join(s.TenthMen(), ", "))
+	}
+	return f, nil
+}
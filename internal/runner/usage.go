@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// meteredLLM counts the tokens reported for every completion and refuses new
+// calls once budget tokens are used, if budget is positive.
+type meteredLLM struct {
+	debate.LLMClient
+	budget int
+	tokens atomic.Int64
+}
+
+func (m *meteredLLM) ChatCompletion(ctx context.Context, model string, messages []openrouter.Message, opts ...openrouter.Option) (*openrouter.ChatResponse, error) {
+	if used := m.tokens.Load(); m.budget > 0 && used >= int64(m.budget) {
+		return nil, fmt.Errorf("runner: %w: %d of %d tokens used", debate.ErrBudgetExceeded, used, m.budget)
+	}
+	resp, err := m.LLMClient.ChatCompletion(ctx, model, messages, opts...)
+	if resp != nil && resp.Usage != nil {
+		m.tokens.Add(int64(resp.Usage.TotalTokens))
+	}
+	return resp, err
+}
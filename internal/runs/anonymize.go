@@ -0,0 +1,152 @@
+package runs
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// textExts are the artifact types whose text an anonymized archive rewrites.
+// Other files, such as narrated audio, cannot be rewritten and are left out.
+var textExts = []string{".json", ".jsonl", ".md", ".log", ".html", ".txt", ".csv"}
+
+// Pseudonyms replaces agent names and model IDs with stand-ins such as
+// "Agent A" and "Model 1", so debate results can be shared without
+// revealing the model lineup or internal naming. Stand-ins are assigned in
+// order of first appearance and stay the same for every run learned by the
+// same Pseudonyms. The built-in Tenth Man and Moderator keep their names.
+type Pseudonyms struct {
+	agents map[string]string
+	models map[string]string
+	re     *regexp.Regexp // matches every known name and model; nil until rebuilt
+}
+
+// NewPseudonyms returns a Pseudonyms that knows no names yet.
+func NewPseudonyms() *Pseudonyms {
+	return &Pseudonyms{agents: make(map[string]string), models: make(map[string]string)}
+}
+
+// Learn assigns stand-ins to the agents and models of t not seen before.
+func (p *Pseudonyms) Learn(t *debate.Transcript) {
+	learnAgent := func(a debate.Agent) {
+		p.Agent(a.Name, a.Role)
+		p.Model(a.Model)
+	}
+	for _, turn := range t.Turns {
+		learnAgent(turn.Agent)
+	}
+	for _, j := range t.Joins {
+		learnAgent(j.Agent)
+	}
+	for _, r := range t.Removals {
+		p.Agent(r.Agent, "debater")
+	}
+	for _, s := range t.ModelSwaps {
+		p.Model(s.From)
+		p.Model(s.To)
+	}
+	p.Model(t.JudgeModel)
+}
+
+// Agent returns the stand-in for the agent called name with role, assigning
+// one if it has none. The Tenth Man and the Moderator keep their names.
+func (p *Pseudonyms) Agent(name, role string) string {
+	if name == "" || role == "tenth-man" || role == "moderator" {
+		return name
+	}
+	if alias, ok := p.agents[name]; ok {
+		return alias
+	}
+	alias := "Agent " + letters(len(p.agents))
+	p.agents[name] = alias
+	p.re = nil
+	return alias
+}
+
+// Model returns the stand-in for model, assigning one if it has none.
+func (p *Pseudonyms) Model(model string) string {
+	if model == "" {
+		return ""
+	}
+	if alias, ok := p.models[model]; ok {
+		return alias
+	}
+	alias := fmt.Sprintf("Model %d", len(p.models)+1)
+	p.models[model] = alias
+	p.re = nil
+	return alias
+}
+
+// Replace returns s with every learned agent name and model ID replaced by
+// its stand-in. Only whole words are replaced, so "Eve" leaves "every"
+// alone.
+func (p *Pseudonyms) Replace(s string) string {
+	if len(p.agents)+len(p.models) == 0 {
+		return s
+	}
+	if p.re == nil {
+		var known []string
+		for name := range p.agents {
+			known = append(known, name)
+		}
+		for model := range p.models {
+			known = append(known, model)
+		}
+		// Longest first, so a model ID wins over a name it contains.
+		slices.SortFunc(known, func(a, b string) int { return len(b) - len(a) })
+		for i, k := range known {
+			known[i] = regexp.QuoteMeta(k)
+		}
+		p.re = regexp.MustCompile(strings.Join(known, "|"))
+	}
+	var sb strings.Builder
+	last := 0
+	for _, m := range p.re.FindAllStringIndex(s, -1) {
+		if !wordBoundary(s, m[0], m[1]) {
+			continue
+		}
+		match := s[m[0]:m[1]]
+		alias, ok := p.models[match]
+		if !ok {
+			alias = p.agents[match]
+		}
+		sb.WriteString(s[last:m[0]])
+		sb.WriteString(alias)
+		last = m[1]
+	}
+	sb.WriteString(s[last:])
+	return sb.String()
+}
+
+// wordBoundary reports whether s[start:end] is not part of a longer word.
+func wordBoundary(s string, start, end int) bool {
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' }
+	if before, _ := utf8.DecodeLastRuneInString(s[:start]); start > 0 && isWord(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(s[end:]); end < len(s) && isWord(after) {
+		return false
+	}
+	return true
+}
+
+// letters returns the n-th spreadsheet-style column name: A, ..., Z, AA, ...
+func letters(n int) string {
+	s := ""
+	for n++; n > 0; n = (n - 1) / 26 {
+		s = string(rune('A'+(n-1)%26)) + s
+	}
+	return s
+}
+
+// isText reports whether the artifact at path is text an anonymized archive
+// can rewrite, possibly compressed.
+func isText(path string) bool {
+	return slices.Contains(textExts, filepath.Ext(uncompressedName(path)))
+}
@@ -0,0 +1,120 @@
+package runs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Archive writes the run directory dir as a gzip-compressed tarball to dest.
+// Entries are stored under the directory's base name. If names is not nil,
+// it learns the run's agents and models and the archive is anonymized: text
+// artifacts are rewritten with names' stand-ins and other files are left
+// out.
+func Archive(dir, dest string, names *Pseudonyms) error {
+	if names != nil {
+		transcript, err := LoadTranscript(dir)
+		if err != nil {
+			return err
+		}
+		names.Learn(transcript)
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("runs: %w", err)
+	}
+	if err := writeArchive(dir, f, names); err != nil {
+		f.Close()
+		os.Remove(dest)
+		return fmt.Errorf("runs: archiving %s: %w", dir, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("runs: %w", err)
+	}
+	return nil
+}
+
+func writeArchive(dir string, w io.Writer, names *Pseudonyms) error {
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	root := filepath.Base(filepath.Clean(dir))
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(filepath.Join(root, rel))
+		if d.IsDir() {
+			hdr.Name += "/"
+			return tw.WriteHeader(hdr)
+		}
+		if names != nil {
+			if !isText(path) {
+				return nil
+			}
+			data, err := anonymizeFile(path, names)
+			if err != nil {
+				return err
+			}
+			hdr.Size = int64(len(data))
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			_, err = tw.Write(data)
+			return err
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// anonymizeFile returns the text file at path with names' stand-ins in
+// place of agent names and model IDs, compressed again if it was.
+func anonymizeFile(path string, names *Pseudonyms) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := decompress(path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return compress(path, []byte(names.Replace(string(plain))))
+}
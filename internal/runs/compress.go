@@ -0,0 +1,65 @@
+package runs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressedSuffixes are the suffixes of artifacts saved compressed with
+// gzip and zstd.
+var compressedSuffixes = []string{".gz", ".zst"}
+
+// uncompressedName returns path without its compression suffix, if any.
+func uncompressedName(path string) string {
+	for _, suffix := range compressedSuffixes {
+		if s, ok := strings.CutSuffix(path, suffix); ok {
+			return s
+		}
+	}
+	return path
+}
+
+// decompress returns a reader of the content r reads from path, decompressed
+// if path is compressed.
+func decompress(path string, r io.Reader) (io.ReadCloser, error) {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return gzip.NewReader(r)
+	case strings.HasSuffix(path, ".zst"):
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	}
+	return io.NopCloser(r), nil
+}
+
+// compress returns data compressed the way path is.
+func compress(path string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var zw io.WriteCloser
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		zw = gzip.NewWriter(&buf)
+	case strings.HasSuffix(path, ".zst"):
+		w, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		zw = w
+	default:
+		return data, nil
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
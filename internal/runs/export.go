@@ -0,0 +1,129 @@
+package runs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// Export formats.
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// exportColumns are the columns of an exported turn table. In Parquet, the
+// integer columns are typed as such and their empty cells are nulls.
+var exportColumns = []parquetColumn{
+	{name: "run"},
+	{name: "turn", integer: true},
+	{name: "round", integer: true},
+	{name: "phase"},
+	{name: "agent"},
+	{name: "model"},
+	{name: "role"},
+	{name: "in_reply_to", integer: true},
+	{name: "confidence", integer: true},
+	{name: "tokens", integer: true},
+	{name: "latency_ms", integer: true},
+	{name: "content_length", integer: true},
+}
+
+// ValidateExportFormat reports whether turns can be exported as format.
+func ValidateExportFormat(format string) error {
+	switch format {
+	case FormatCSV, FormatParquet:
+		return nil
+	default:
+		return fmt.Errorf("runs: unknown export format %q (want csv or parquet)", format)
+	}
+}
+
+// ExportTurns writes one row per turn of every run in dirs to w, in the
+// given format, CSV or Parquet. The run column is the run directory's name, so the turns of
+// several runs can be told apart. Tokens and latency are empty for turns
+// saved before they were recorded; content_length counts characters. If
+// names is not nil, it learns every run's agents and models and the agent
+// and model columns hold their stand-ins.
+func ExportTurns(w io.Writer, format string, names *Pseudonyms, dirs ...string) error {
+	if err := ValidateExportFormat(format); err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, dir := range dirs {
+		transcript, err := LoadTranscript(dir)
+		if err != nil {
+			return err
+		}
+		if names != nil {
+			names.Learn(transcript)
+		}
+		run := filepath.Base(filepath.Clean(dir))
+		tenthMan := -1
+		if transcript.Phase == debate.TenthManPhase {
+			tenthMan = consensusRound(transcript)
+		}
+		for _, turn := range transcript.Turns {
+			phase := "free"
+			if tenthMan >= 0 && turn.Round > tenthMan {
+				phase = "tenth-man"
+			}
+			agent, model := turn.Agent.Name, turn.Agent.Model
+			if names != nil {
+				agent, model = names.Agent(agent, turn.Agent.Role), names.Model(model)
+			}
+			rows = append(rows, []string{
+				run,
+				strconv.Itoa(turn.ID),
+				strconv.Itoa(turn.Round),
+				phase,
+				agent,
+				model,
+				turn.Agent.Role,
+				optional(turn.InReplyTo),
+				optionalPtr(turn.Confidence),
+				optional(turn.Tokens),
+				optional(turn.LatencyMS),
+				strconv.Itoa(utf8.RuneCountInString(turn.Content)),
+			})
+		}
+	}
+	if format == FormatParquet {
+		if err := writeParquet(w, exportColumns, rows); err != nil {
+			return fmt.Errorf("runs: export: %w", err)
+		}
+		return nil
+	}
+	cw := csv.NewWriter(w)
+	header := make([]string, len(exportColumns))
+	for i, col := range exportColumns {
+		header[i] = col.name
+	}
+	cw.Write(header)
+	cw.WriteAll(rows)
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("runs: export: %w", err)
+	}
+	return nil
+}
+
+// optional formats n, leaving 0 (not recorded) empty.
+func optional(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// optionalPtr formats *n, leaving nil empty.
+func optionalPtr(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
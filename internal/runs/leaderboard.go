@@ -0,0 +1,132 @@
+package runs
+
+import (
+	"sort"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// ModelScore is one model's record across saved runs.
+type ModelScore struct {
+	Model       string
+	Debates     int // debates it spoke or judged in
+	Turns       int
+	Substantive int // turns with a real answer rather than an empty reply, error text or refusal
+	Replies     int // turns by other agents answering this model's turns
+
+	Judged   int // debates it judged
+	Verdicts int // valid judge verdicts
+	Rejected int // judge responses rejected as unparseable or invalid
+
+	Challenges int // finished debates it played the Tenth Man in
+	Moved      int // of those, consensuses it got revised or overturned
+}
+
+// Quality is the share of the model's turns other agents chose to answer,
+// a proxy for how much its arguments moved the debate.
+func (m ModelScore) Quality() float64 { return ratio(m.Replies, m.Turns) }
+
+// Reliability is the share of the model's turns that were substantive.
+func (m ModelScore) Reliability() float64 { return ratio(m.Substantive, m.Turns) }
+
+// JudgeAccuracy is the share of the model's judge responses that were valid
+// verdicts.
+func (m ModelScore) JudgeAccuracy() float64 { return ratio(m.Verdicts, m.Verdicts+m.Rejected) }
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// Leaderboard scores every model that spoke or judged in a run under base,
+// best argument quality first, then most reliable, then by name. Encrypted
+// runs and runs Scan cannot read are left out.
+func Leaderboard(base string) ([]ModelScore, error) {
+	runs, _, err := Scan(base)
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[string]*ModelScore)
+	score := func(model string) *ModelScore {
+		s := scores[model]
+		if s == nil {
+			s = &ModelScore{Model: model}
+			scores[model] = s
+		}
+		return s
+	}
+	for _, run := range runs {
+		if run.Encrypted {
+			continue
+		}
+		transcript, err := LoadTranscript(run.Dir)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		byID := make(map[int]debate.Turn, len(transcript.Turns))
+		for _, turn := range transcript.Turns {
+			byID[turn.ID] = turn
+		}
+		for _, turn := range transcript.Turns {
+			model := turn.Agent.Model
+			if model == "" || turn.Agent.Role == "moderator" {
+				continue
+			}
+			s := score(model)
+			s.Turns++
+			if debate.Substantive(turn.Content) {
+				s.Substantive++
+			}
+			seen[model] = true
+			if parent, ok := byID[turn.InReplyTo]; ok && parent.Agent.Name != turn.Agent.Name && parent.Agent.Model != "" {
+				score(parent.Agent.Model).Replies++
+			}
+		}
+		if tm := tenthManModel(transcript); tm != "" && transcript.Phase == debate.TenthManPhase && transcript.Outcome != "" {
+			s := score(tm)
+			s.Challenges++
+			if transcript.Outcome == debate.VerdictRevised || transcript.Outcome == debate.VerdictOverturned {
+				s.Moved++
+			}
+		}
+		if judge := transcript.JudgeModel; judge != "" {
+			s := score(judge)
+			s.Judged++
+			s.Verdicts += transcript.Evaluations
+			s.Rejected += len(transcript.JudgeDiagnostics)
+			seen[judge] = true
+		}
+		for model := range seen {
+			scores[model].Debates++
+		}
+	}
+
+	board := make([]ModelScore, 0, len(scores))
+	for _, s := range scores {
+		board = append(board, *s)
+	}
+	sort.Slice(board, func(i, j int) bool {
+		a, b := board[i], board[j]
+		if a.Quality() != b.Quality() {
+			return a.Quality() > b.Quality()
+		}
+		if a.Reliability() != b.Reliability() {
+			return a.Reliability() > b.Reliability()
+		}
+		return a.Model < b.Model
+	})
+	return board, nil
+}
+
+// tenthManModel returns the model that played the Tenth Man, or "".
+func tenthManModel(t *debate.Transcript) string {
+	for _, turn := range t.Turns {
+		if turn.Agent.Role == "tenth-man" {
+			return turn.Agent.Model
+		}
+	}
+	return ""
+}
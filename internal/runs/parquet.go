@@ -0,0 +1,247 @@
+package runs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+)
+
+// parquetMagic opens and closes every Parquet file.
+const parquetMagic = "PAR1"
+
+// Parquet physical types, repetitions, encodings and converted types, as
+// numbered by the format's Thrift definitions.
+const (
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetUTF8 = 0
+)
+
+// Thrift compact protocol field types.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// parquetColumn is a column of a Parquet table: text, or an integer that is
+// null where its cell is empty.
+type parquetColumn struct {
+	name    string
+	integer bool
+}
+
+// writeParquet writes rows as a Parquet file with columns to w: one row
+// group, one uncompressed, plain-encoded data page per column, the simplest
+// layout the format allows and one every reader supports.
+func writeParquet(w io.Writer, columns []parquetColumn, rows [][]string) error {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	chunks := make([][]byte, len(columns))
+	var total int64
+	for i, col := range columns {
+		page := columnPage(col, i, rows)
+		offset := int64(file.Len())
+		var header thriftWriter
+		header.field(1, thriftI32).i32(0) // DATA_PAGE
+		header.field(2, thriftI32).i32(int32(len(page)))
+		header.field(3, thriftI32).i32(int32(len(page)))
+		header.field(5, thriftStruct)
+		header.field(1, thriftI32).i32(int32(len(rows)))
+		header.field(2, thriftI32).i32(parquetPlain)
+		header.field(3, thriftI32).i32(parquetRLE)
+		header.field(4, thriftI32).i32(parquetRLE)
+		header.end()
+		header.end()
+		file.Write(header.Bytes())
+		file.Write(page)
+		size := int64(file.Len()) - offset
+		total += size
+
+		var chunk thriftWriter
+		chunk.field(2, thriftI64).i64(offset)
+		chunk.field(3, thriftStruct)
+		chunk.field(1, thriftI32).i32(col.physicalType())
+		chunk.field(2, thriftList).list(thriftI32, 2).i32(parquetPlain).i32(parquetRLE)
+		chunk.field(3, thriftList).list(thriftBinary, 1).binary(col.name)
+		chunk.field(4, thriftI32).i32(0) // UNCOMPRESSED
+		chunk.field(5, thriftI64).i64(int64(len(rows)))
+		chunk.field(6, thriftI64).i64(size)
+		chunk.field(7, thriftI64).i64(size)
+		chunk.field(9, thriftI64).i64(offset)
+		chunk.end()
+		chunk.end()
+		chunks[i] = chunk.Bytes()
+	}
+
+	var meta thriftWriter
+	meta.field(1, thriftI32).i32(1)
+	meta.field(2, thriftList).list(thriftStruct, len(columns)+1)
+	meta.begin()
+	meta.field(4, thriftBinary).binary("schema")
+	meta.field(5, thriftI32).i32(int32(len(columns)))
+	meta.end()
+	for _, col := range columns {
+		meta.begin()
+		meta.field(1, thriftI32).i32(col.physicalType())
+		if col.integer {
+			meta.field(3, thriftI32).i32(parquetOptional)
+			meta.field(4, thriftBinary).binary(col.name)
+		} else {
+			meta.field(3, thriftI32).i32(parquetRequired)
+			meta.field(4, thriftBinary).binary(col.name)
+			meta.field(6, thriftI32).i32(parquetUTF8)
+		}
+		meta.end()
+	}
+	meta.field(3, thriftI64).i64(int64(len(rows)))
+	if len(rows) == 0 {
+		meta.field(4, thriftList).list(thriftStruct, 0)
+	} else {
+		meta.field(4, thriftList).list(thriftStruct, 1)
+		meta.begin()
+		meta.field(1, thriftList).list(thriftStruct, len(chunks))
+		for _, chunk := range chunks {
+			meta.Write(chunk)
+		}
+		meta.field(2, thriftI64).i64(total)
+		meta.field(3, thriftI64).i64(int64(len(rows)))
+		meta.end()
+	}
+	meta.field(6, thriftBinary).binary("tenthman")
+	meta.end()
+
+	file.Write(meta.Bytes())
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(meta.Len())))
+	file.WriteString(parquetMagic)
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// physicalType returns the Parquet type col is stored as.
+func (col parquetColumn) physicalType() int32 {
+	if col.integer {
+		return parquetInt64
+	}
+	return parquetByteArray
+}
+
+// columnPage returns the body of the data page holding column i of rows.
+// An integer column starts with the definition levels telling its nulls
+// apart, run-length encoded and prefixed with their length.
+func columnPage(col parquetColumn, i int, rows [][]string) []byte {
+	var values []byte
+	if !col.integer {
+		for _, row := range rows {
+			values = binary.LittleEndian.AppendUint32(values, uint32(len(row[i])))
+			values = append(values, row[i]...)
+		}
+		return values
+	}
+	var levels []byte
+	for start := 0; start < len(rows); {
+		defined := rows[start][i] != ""
+		end := start
+		for end < len(rows) && (rows[end][i] != "") == defined {
+			if defined {
+				n, _ := strconv.ParseInt(rows[end][i], 10, 64)
+				values = binary.LittleEndian.AppendUint64(values, uint64(n))
+			}
+			end++
+		}
+		levels = binary.AppendUvarint(levels, uint64(end-start)<<1)
+		if defined {
+			levels = append(levels, 1)
+		} else {
+			levels = append(levels, 0)
+		}
+		start = end
+	}
+	page := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+	page = append(page, levels...)
+	return append(page, values...)
+}
+
+// thriftWriter encodes structs with Thrift's compact protocol, as Parquet's
+// page headers and footer are. Fields of a struct are written in increasing
+// order of ID, each encoded as the delta from the previous one.
+type thriftWriter struct {
+	bytes.Buffer
+	last  int16   // ID of the last field written in the current struct
+	outer []int16 // last of each struct enclosing the current one
+}
+
+// field writes the header of field id of type typ. A struct field opens the
+// struct, to be closed with end.
+func (t *thriftWriter) field(id int16, typ byte) *thriftWriter {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	t.last = id
+	if typ == thriftStruct {
+		t.begin()
+	}
+	return t
+}
+
+// list writes the header of a list of n elements of type typ. Each struct
+// element is opened with begin and closed with end.
+func (t *thriftWriter) list(typ byte, n int) *thriftWriter {
+	if n < 15 {
+		t.WriteByte(byte(n)<<4 | typ)
+	} else {
+		t.WriteByte(0xf0 | typ)
+		t.Write(binary.AppendUvarint(nil, uint64(n)))
+	}
+	return t
+}
+
+// begin opens a nested struct.
+func (t *thriftWriter) begin() {
+	t.outer = append(t.outer, t.last)
+	t.last = 0
+}
+
+// end closes the current struct.
+func (t *thriftWriter) end() {
+	t.WriteByte(0)
+	if n := len(t.outer); n > 0 {
+		t.last = t.outer[n-1]
+		t.outer = t.outer[:n-1]
+	}
+}
+
+func (t *thriftWriter) i32(n int32) *thriftWriter {
+	t.varint(int64(n))
+	return t
+}
+
+func (t *thriftWriter) i64(n int64) *thriftWriter {
+	t.varint(n)
+	return t
+}
+
+func (t *thriftWriter) binary(s string) *thriftWriter {
+	t.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	t.WriteString(s)
+	return t
+}
+
+// varint writes n zigzag-encoded.
+func (t *thriftWriter) varint(n int64) {
+	t.Write(binary.AppendUvarint(nil, uint64(n<<1^n>>63)))
+}
@@ -0,0 +1,86 @@
+package runs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrunePolicy selects runs to delete. Zero fields are not applied.
+type PrunePolicy struct {
+	OlderThan time.Duration // delete runs created longer ago than this
+	MaxSize   int64         // keep the newest runs whose combined size fits in this many bytes
+}
+
+// SelectPrunable returns the runs that policy would delete. runs must be
+// ordered newest first, as returned by Scan.
+func SelectPrunable(runs []Run, policy PrunePolicy, now time.Time) []Run {
+	var prune []Run
+	var kept int64
+	for _, run := range runs {
+		switch {
+		case policy.OlderThan > 0 && now.Sub(run.Created) > policy.OlderThan:
+			prune = append(prune, run)
+		case policy.MaxSize > 0 && kept+run.Size > policy.MaxSize:
+			prune = append(prune, run)
+		default:
+			kept += run.Size
+		}
+	}
+	return prune
+}
+
+// monthDirRe matches the YYYY-MM folders of the nested output layout.
+var monthDirRe = regexp.MustCompile(`^\d{4}-\d{2}$`)
+
+// Remove deletes a run directory and everything in it, and the month folder
+// holding it in the nested output layout once that is empty.
+func Remove(run Run) error {
+	if err := os.RemoveAll(run.Dir); err != nil {
+		return fmt.Errorf("runs: %w", err)
+	}
+	if parent := filepath.Dir(run.Dir); monthDirRe.MatchString(filepath.Base(parent)) {
+		os.Remove(parent) // fails, harmlessly, while other runs remain
+	}
+	return nil
+}
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1},
+}
+
+// ParseSize parses sizes like "500MB", "2GB" or "1024" (bytes). Units are
+// binary and case-insensitive.
+func ParseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			mult = u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("runs: invalid size %q", s)
+	}
+	return int64(n * float64(mult)), nil
+}
+
+// FormatSize renders a byte count with a binary unit, e.g. "1.5 MB".
+func FormatSize(n int64) string {
+	for _, u := range sizeUnits[:len(sizeUnits)-1] {
+		if n >= u.bytes {
+			return fmt.Sprintf("%.1f %s", float64(n)/float64(u.bytes), u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,102 @@
+package runs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSelectPrunable(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	list := []Run{ // newest first
+		{Dir: "a", Created: now.Add(-1 * day), Size: 400},
+		{Dir: "b", Created: now.Add(-5 * day), Size: 400},
+		{Dir: "c", Created: now.Add(-10 * day), Size: 100},
+		{Dir: "d", Created: now.Add(-40 * day), Size: 100},
+	}
+	dirs := func(runs []Run) string {
+		s := ""
+		for _, r := range runs {
+			s += r.Dir
+		}
+		return s
+	}
+
+	if got := dirs(SelectPrunable(list, PrunePolicy{OlderThan: 30 * day}, now)); got != "d" {
+		t.Errorf("age policy pruned %q, want d", got)
+	}
+	if got := dirs(SelectPrunable(list, PrunePolicy{MaxSize: 500}, now)); got != "bd" {
+		t.Errorf("size policy pruned %q, want bd", got)
+	}
+	if got := dirs(SelectPrunable(list, PrunePolicy{OlderThan: 7 * day, MaxSize: 500}, now)); got != "bcd" {
+		t.Errorf("combined policy pruned %q, want bcd", got)
+	}
+	if got := SelectPrunable(list, PrunePolicy{}, now); len(got) != 0 {
+		t.Errorf("empty policy should prune nothing, got %d", len(got))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	base := t.TempDir()
+	dir := writeRun(t, base, "gone-20260101-000000", sampleTranscript("Gone", "x"))
+	if err := Remove(Run{Dir: dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected run directory to be deleted, stat err = %v", err)
+	}
+}
+
+func TestRemoveEmptiesMonthFolder(t *testing.T) {
+	base := t.TempDir()
+	month := filepath.Join(base, "2026-01")
+	first := writeRun(t, month, "first-20260101-000000", sampleTranscript("First", "x"))
+	second := writeRun(t, month, "second-20260102-000000", sampleTranscript("Second", "x"))
+	if err := Remove(Run{Dir: first}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(month); err != nil {
+		t.Fatalf("expected the month folder kept while a run remains: %v", err)
+	}
+	if err := Remove(Run{Dir: second}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(month); !os.IsNotExist(err) {
+		t.Errorf("expected the empty month folder deleted, stat err = %v", err)
+	}
+	if _, err := os.Stat(base); err != nil {
+		t.Errorf("expected the output directory kept: %v", err)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"1024":  1024,
+		"500MB": 500 << 20,
+		"2gb":   2 << 30,
+		"1.5KB": 1536,
+		"10 B":  10,
+	}
+	for in, want := range tests {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "lots", "-5MB"} {
+		if _, err := ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q) expected error", bad)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	if got := FormatSize(1536); got != "1.5 KB" {
+		t.Errorf("FormatSize(1536) = %q", got)
+	}
+	if got := FormatSize(12); got != "12 B" {
+		t.Errorf("FormatSize(12) = %q", got)
+	}
+}
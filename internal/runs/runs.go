@@ -0,0 +1,165 @@
+package runs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+)
+
+const transcriptFile = "transcript.json"
+
+// dirTimeRe matches the -YYYYMMDD-HHMMSS suffix output.CreateOutputDir adds.
+var dirTimeRe = regexp.MustCompile(`-(\d{8}-\d{6})$`)
+
+// Run describes a saved debate run directory.
+type Run struct {
+	Dir     string
+	Topic   string
+	Project string // project tag the run was filed under, if any
+	Rounds  int
+	Created time.Time
+	Size    int64 // total bytes of every file in the directory
+	// Encrypted is set for runs saved with --encrypt-to. Their transcript
+	// cannot be read, so Topic, Project and Rounds are unknown.
+	Encrypted bool
+}
+
+// Scan returns every run directory under base, newest first. A run directory
+// is any directory containing a transcript, plain, compressed or encrypted.
+// Run directories that cannot be read are skipped, with one warning each.
+func Scan(base string) ([]Run, []error, error) {
+	var runs []Run
+	var warnings []error
+	err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		var run Run
+		switch {
+		case transcriptPath(path) != "":
+			run, err = load(path)
+		case output.Encrypted(path):
+			run, err = loadEncrypted(path)
+		default:
+			return nil
+		}
+		if err != nil {
+			warnings = append(warnings, err)
+			return filepath.SkipDir
+		}
+		runs = append(runs, run)
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("runs: %w", err)
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Created.After(runs[j].Created) })
+	return runs, warnings, nil
+}
+
+func load(dir string) (Run, error) {
+	transcript, err := LoadTranscript(dir)
+	if err != nil {
+		return Run{}, err
+	}
+	size, modTime, err := dirStats(dir)
+	if err != nil {
+		return Run{}, fmt.Errorf("runs: %s: %w", dir, err)
+	}
+	return Run{
+		Dir:     dir,
+		Topic:   transcript.Topic,
+		Project: transcript.Project,
+		Rounds:  transcript.Rounds,
+		Created: created(dir, modTime),
+		Size:    size,
+	}, nil
+}
+
+func loadEncrypted(dir string) (Run, error) {
+	size, modTime, err := dirStats(dir)
+	if err != nil {
+		return Run{}, fmt.Errorf("runs: %s: %w", dir, err)
+	}
+	return Run{Dir: dir, Created: created(dir, modTime), Size: size, Encrypted: true}, nil
+}
+
+// LoadTranscript reads the transcript saved in a run directory, whether
+// plain or compressed with gzip or zstd.
+func LoadTranscript(dir string) (*debate.Transcript, error) {
+	path := transcriptPath(dir)
+	if path == "" {
+		return nil, fmt.Errorf("runs: %s: no transcript", dir)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("runs: %w", err)
+	}
+	defer f.Close()
+
+	r, err := decompress(path, f)
+	if err != nil {
+		return nil, fmt.Errorf("runs: %s: %w", path, err)
+	}
+	defer r.Close()
+	var t debate.Transcript
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, fmt.Errorf("runs: %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+// transcriptPath returns the transcript file in dir, or "" if there is none.
+func transcriptPath(dir string) string {
+	for _, name := range []string{transcriptFile, transcriptFile + ".gz", transcriptFile + ".zst"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// dirStats returns the total size of the files in dir and the oldest
+// modification time among them.
+func dirStats(dir string) (int64, time.Time, error) {
+	var size int64
+	var oldest time.Time
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		if oldest.IsZero() || info.ModTime().Before(oldest) {
+			oldest = info.ModTime()
+		}
+		return nil
+	})
+	return size, oldest, err
+}
+
+// created returns the timestamp encoded in the run directory's name, falling
+// back to fallback for directories that were renamed.
+func created(dir string, fallback time.Time) time.Time {
+	if m := dirTimeRe.FindStringSubmatch(filepath.Base(dir)); m != nil {
+		if t, err := time.ParseInLocation("20060102-150405", m[1], time.Local); err == nil {
+			return t
+		}
+	}
+	return fallback
+}
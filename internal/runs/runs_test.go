@@ -0,0 +1,449 @@
+package runs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/output"
+)
+
+// writeRun saves a transcript under base/name and returns the run directory.
+func writeRun(t *testing.T, base, name string, transcript debate.Transcript) string {
+	t.Helper()
+	dir := filepath.Join(base, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, transcriptFile), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func sampleTranscript(topic string, contents ...string) debate.Transcript {
+	tr := debate.Transcript{Topic: topic, Rounds: len(contents)}
+	for i, c := range contents {
+		tr.Turns = append(tr.Turns, debate.Turn{ID: i + 1, Round: i + 1, Agent: debate.Agent{Name: "Alice", Model: "m"}, Content: c})
+	}
+	return tr
+}
+
+func TestScan(t *testing.T) {
+	base := t.TempDir()
+	writeRun(t, base, "old-topic-20250101-090000", sampleTranscript("Old", "a"))
+	writeRun(t, base, "new-topic-20260301-120000", sampleTranscript("New", "a", "b"))
+	if err := os.MkdirAll(filepath.Join(base, "batch-20260301-120000"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, warnings, err := Scan(base)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("unexpected error: %v, warnings: %v", err, warnings)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+	if runs[0].Topic != "New" || runs[0].Rounds != 2 || runs[0].Created.Year() != 2026 || runs[0].Size == 0 {
+		t.Errorf("unexpected newest run %+v", runs[0])
+	}
+	if runs[1].Topic != "Old" {
+		t.Errorf("runs should be newest first, got %+v", runs[1])
+	}
+}
+
+func TestScanSkipsCorruptRuns(t *testing.T) {
+	base := t.TempDir()
+	writeRun(t, base, "good-20260101-000000", sampleTranscript("Good", "fine"))
+	bad := filepath.Join(base, "bad-20260201-000000")
+	if err := os.MkdirAll(bad, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bad, transcriptFile), []byte(`{"Topic": "trunc`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, warnings, err := Scan(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Topic != "Good" {
+		t.Errorf("expected only the good run, got %+v", runs)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), bad) {
+		t.Errorf("expected one warning naming %s, got %v", bad, warnings)
+	}
+	if matches, err := Search(base, "fine", 0); err != nil || len(matches) != 1 {
+		t.Errorf("Search() = %d matches, %v; want the good run", len(matches), err)
+	}
+}
+
+func TestScanEncrypted(t *testing.T) {
+	base := t.TempDir()
+	writeRun(t, base, "plain-20260101-000000", sampleTranscript("Plain", "secret plans"))
+	dir := filepath.Join(base, "sealed-20260301-120000")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "transcript.json.age"), []byte("age-encryption.org/v1\n..."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, _, err := Scan(base)
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("Scan() = %+v, %v", runs, err)
+	}
+	if !runs[0].Encrypted || runs[0].Dir != dir || runs[0].Topic != "" || runs[0].Size == 0 || runs[0].Created.Year() != 2026 || runs[0].Created.Month() != 3 {
+		t.Errorf("unexpected encrypted run %+v", runs[0])
+	}
+	if runs[1].Encrypted || runs[1].Topic != "Plain" {
+		t.Errorf("unexpected plain run %+v", runs[1])
+	}
+	if matches, err := Search(base, "secret", 0); err != nil || len(matches) != 1 {
+		t.Errorf("Search() = %d matches, %v; want the plain run only", len(matches), err)
+	}
+	if stats, err := Summarize(base); err != nil || stats.Debates != 1 {
+		t.Errorf("Summarize() = %+v, %v; want the plain run only", stats, err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	base := t.TempDir()
+	long := strings.Repeat("filler ", 30) + "the risk of Regulatory Capture is real" + strings.Repeat(" filler", 30)
+	writeRun(t, base, "a-20260301-120000", sampleTranscript("Banking", long, "unrelated"))
+	writeRun(t, base, "b-20250301-120000", sampleTranscript("Telecom", "regulatory capture again"))
+
+	matches, err := Search(base, "regulatory capture", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+	first := matches[0]
+	if first.Run.Topic != "Banking" || first.Turn.Round != 1 {
+		t.Errorf("unexpected first match %+v", first)
+	}
+	if !strings.HasPrefix(first.Snippet, "…") || !strings.HasSuffix(first.Snippet, "…") || !strings.Contains(first.Snippet, "Regulatory Capture") {
+		t.Errorf("unexpected snippet %q", first.Snippet)
+	}
+	if matches[1].Snippet != "regulatory capture again" {
+		t.Errorf("short content should not be truncated, got %q", matches[1].Snippet)
+	}
+
+	limited, _ := Search(base, "regulatory capture", 1)
+	if len(limited) != 1 {
+		t.Errorf("expected limit to cap matches, got %d", len(limited))
+	}
+}
+
+func TestLoadTranscriptCompressed(t *testing.T) {
+	for _, format := range []string{output.CompressGzip, output.CompressZstd} {
+		t.Run(format, func(t *testing.T) {
+			base := t.TempDir()
+			dir := writeRun(t, base, "zipped-20260101-000000", sampleTranscript("Zipped", "hello"))
+			if err := output.CompressArtifacts(dir, format); err != nil {
+				t.Fatal(err)
+			}
+			runs, _, err := Scan(base)
+			if err != nil || len(runs) != 1 || runs[0].Topic != "Zipped" {
+				t.Fatalf("Scan() = %+v, %v", runs, err)
+			}
+			matches, err := Search(base, "hello", 0)
+			if err != nil || len(matches) != 1 {
+				t.Errorf("Search() over compressed run = %d matches, %v", len(matches), err)
+			}
+		})
+	}
+}
+
+func TestArchive(t *testing.T) {
+	base := t.TempDir()
+	dir := writeRun(t, base, "shared-20260101-000000", sampleTranscript("Shared", "x"))
+	dest := filepath.Join(t.TempDir(), "shared.tar.gz")
+	if err := Archive(dir, dest, nil); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(zr)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+	}
+	if got := strings.Join(names, ","); got != "shared-20260101-000000/,shared-20260101-000000/transcript.json" {
+		t.Errorf("unexpected archive entries %s", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	base := t.TempDir()
+	writeRun(t, base, "none-20260101-090000", sampleTranscript("None", "a", "b"))
+	upheld := sampleTranscript("Upheld", "a", "b", "c")
+	upheld.Phase = debate.TenthManPhase
+	upheld.Outcome = debate.VerdictUpheld
+	upheld.Tokens = 1200
+	upheld.Turns[2].Agent = debate.Agent{Name: "Tenth Man", Model: "tm", Role: "tenth-man"}
+	writeRun(t, base, "upheld-20260102-090000", upheld)
+	revised := sampleTranscript("Revised", "a", "b", "c", "d", "e")
+	revised.Phase = debate.TenthManPhase
+	revised.Outcome = debate.VerdictRevised
+	revised.Tokens = 800
+	revised.Turns[4].Agent = debate.Agent{Name: "Tenth Man", Model: "tm", Role: "tenth-man"}
+	writeRun(t, base, "revised-20260103-090000", revised)
+
+	stats, err := Summarize(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Debates != 3 || stats.Consensus != 2 || stats.ConsensusRate() < 0.66 || stats.ConsensusRate() > 0.67 {
+		t.Errorf("unexpected consensus counts %+v", stats)
+	}
+	if stats.RoundsToConsensus != 3 {
+		t.Errorf("expected 2 and 4 rounds to consensus to average 3, got %v", stats.RoundsToConsensus)
+	}
+	if stats.Outcomes[debate.VerdictUpheld] != 1 || stats.Outcomes[debate.VerdictRevised] != 1 || len(stats.Outcomes) != 2 {
+		t.Errorf("unexpected outcomes %v", stats.Outcomes)
+	}
+	if stats.Tokens != 2000 || stats.Metered != 2 {
+		t.Errorf("expected 2000 tokens over 2 metered runs, got %d over %d", stats.Tokens, stats.Metered)
+	}
+	want := []ModelUsage{{Model: "m", Turns: 8, Debates: 3}, {Model: "tm", Turns: 2, Debates: 2}}
+	if len(stats.Models) != 2 || stats.Models[0] != want[0] || stats.Models[1] != want[1] {
+		t.Errorf("unexpected model usage %+v", stats.Models)
+	}
+
+	empty, err := Summarize(t.TempDir())
+	if err != nil || empty.ConsensusRate() != 0 || empty.RoundsToConsensus != 0 {
+		t.Errorf("unexpected stats for no runs %+v, %v", empty, err)
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	base := t.TempDir()
+	alice := debate.Agent{Name: "Alice", Model: "a"}
+	bob := debate.Agent{Name: "Bob", Model: "b"}
+	tm := debate.Agent{Name: "Tenth Man", Model: "b", Role: "tenth-man"}
+	writeRun(t, base, "one-20260101-090000", debate.Transcript{
+		Topic: "One", Rounds: 2, Phase: debate.TenthManPhase, Outcome: debate.VerdictRevised,
+		JudgeModel: "a", Evaluations: 2,
+		JudgeDiagnostics: []debate.JudgeDiagnostic{{Round: 1, Attempt: 1, Error: "bad"}},
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: alice, Content: "We should ship."},
+			{ID: 2, Round: 1, Agent: bob, Content: "I agree with #1.", InReplyTo: 1},
+			{ID: 3, Round: 2, Agent: tm, Content: "Shipping now is reckless.", InReplyTo: 1},
+			{ID: 4, Round: 2, Agent: alice, Content: "Ship to one region first.", InReplyTo: 3},
+			{ID: 5, Round: 2, Agent: bob, Content: ""},
+		},
+	})
+
+	board, err := Leaderboard(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(board) != 2 || board[0].Model != "a" {
+		t.Fatalf("expected model a to lead, got %+v", board)
+	}
+	a, b := board[0], board[1]
+	if a.Turns != 2 || a.Replies != 2 || a.Quality() != 1 || a.Reliability() != 1 {
+		t.Errorf("unexpected score for a %+v", a)
+	}
+	if a.Judged != 1 || a.Verdicts != 2 || a.Rejected != 1 || a.JudgeAccuracy() < 0.66 || a.JudgeAccuracy() > 0.67 {
+		t.Errorf("unexpected judge record for a %+v", a)
+	}
+	if b.Turns != 3 || b.Replies != 1 || b.Substantive != 2 || b.Challenges != 1 || b.Moved != 1 || b.Debates != 1 {
+		t.Errorf("unexpected score for b %+v", b)
+	}
+}
+
+func TestExportTurns(t *testing.T) {
+	base := t.TempDir()
+	confidence := 70
+	one := writeRun(t, base, "one-20260101-090000", debate.Transcript{
+		Topic: "One", Rounds: 2, Phase: debate.TenthManPhase,
+		Turns: []debate.Turn{
+			{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "a", Role: "debater"}, Content: "Ship, «now».", Confidence: &confidence, Tokens: 120, LatencyMS: 850},
+			{ID: 2, Round: 2, Agent: debate.Agent{Name: "Alice", Model: "a", Role: "debater"}, Content: "Still ship.", InReplyTo: 3},
+			{ID: 3, Round: 2, Agent: debate.Agent{Name: "Tenth Man", Model: "b", Role: "tenth-man"}, Content: "Wait."},
+		},
+	})
+	two := writeRun(t, base, "two-20260102-090000", sampleTranscript("Two", "x"))
+
+	var sb strings.Builder
+	if err := ExportTurns(&sb, FormatCSV, nil, one, two); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `run,turn,round,phase,agent,model,role,in_reply_to,confidence,tokens,latency_ms,content_length
+one-20260101-090000,1,1,free,Alice,a,debater,,70,120,850,12
+one-20260101-090000,2,2,tenth-man,Alice,a,debater,3,,,,11
+one-20260101-090000,3,2,tenth-man,Tenth Man,b,tenth-man,,,,,5
+two-20260102-090000,1,1,free,Alice,m,,,,,,1
+`
+	if sb.String() != want {
+		t.Errorf("unexpected export:\n%s\nwant:\n%s", sb.String(), want)
+	}
+
+	var pq strings.Builder
+	if err := ExportTurns(&pq, FormatParquet, nil, one, two); err != nil {
+		t.Fatalf("parquet export: %v", err)
+	}
+	data := pq.String()
+	if !strings.HasPrefix(data, "PAR1") || !strings.HasSuffix(data, "PAR1") {
+		t.Fatalf("expected a Parquet file, got %q", data)
+	}
+	footer := int(binary.LittleEndian.Uint32([]byte(data[len(data)-8:])))
+	if footer <= 0 || footer > len(data)-12 {
+		t.Fatalf("footer length %d out of range of a %d-byte file", footer, len(data))
+	}
+	for _, col := range exportColumns {
+		if !strings.Contains(data[len(data)-8-footer:], col.name) {
+			t.Errorf("expected column %s in the Parquet schema", col.name)
+		}
+	}
+	if !strings.Contains(data, "one-20260101-090000") || !strings.Contains(data, "Tenth Man") {
+		t.Error("expected the turns' text in the Parquet data")
+	}
+	if err := ExportTurns(io.Discard, "xlsx", nil, one); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+}
+
+func TestPseudonyms(t *testing.T) {
+	names := NewPseudonyms()
+	names.Learn(&debate.Transcript{
+		Turns: []debate.Turn{
+			{Agent: debate.Agent{Name: "Eve", Model: "acme/llama-70b:free", Role: "debater"}},
+			{Agent: debate.Agent{Name: "Bob", Model: "acme/llama-70b", Role: "debater"}},
+			{Agent: debate.Agent{Name: "Tenth Man", Model: "other/m", Role: "tenth-man"}},
+		},
+		JudgeModel: "judge/x",
+	})
+	got := names.Replace("Eve (acme/llama-70b:free) told Bob, on acme/llama-70b, that every Tenth Man is judged by judge/x; Bobby disagreed.")
+	want := "Agent A (Model 1) told Agent B, on Model 2, that every Tenth Man is judged by Model 4; Bobby disagreed."
+	if got != want {
+		t.Errorf("Replace() = %q, want %q", got, want)
+	}
+	if names.Agent("Eve", "debater") != "Agent A" || names.Model("other/m") != "Model 3" {
+		t.Error("expected stand-ins to stay the same")
+	}
+	if letters(0) != "A" || letters(25) != "Z" || letters(26) != "AA" {
+		t.Errorf("unexpected letters %q %q %q", letters(0), letters(25), letters(26))
+	}
+}
+
+func TestExportTurnsAnonymized(t *testing.T) {
+	base := t.TempDir()
+	one := writeRun(t, base, "one-20260101-090000", sampleTranscript("One", "x"))
+	two := writeRun(t, base, "two-20260102-090000", debate.Transcript{Topic: "Two", Rounds: 1, Turns: []debate.Turn{
+		{ID: 1, Round: 1, Agent: debate.Agent{Name: "Bob", Model: "n", Role: "debater"}, Content: "y"},
+		{ID: 2, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m", Role: "debater"}, Content: "z"},
+	}})
+
+	var sb strings.Builder
+	if err := ExportTurns(&sb, FormatCSV, NewPseudonyms(), one, two); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `run,turn,round,phase,agent,model,role,in_reply_to,confidence,tokens,latency_ms,content_length
+one-20260101-090000,1,1,free,Agent A,Model 1,,,,,,1
+two-20260102-090000,1,1,free,Agent B,Model 2,debater,,,,,1
+two-20260102-090000,2,1,free,Agent A,Model 1,debater,,,,,1
+`
+	if sb.String() != want {
+		t.Errorf("unexpected export:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestArchiveAnonymized(t *testing.T) {
+	base := t.TempDir()
+	dir := writeRun(t, base, "shared-20260101-000000", sampleTranscript("Shared", "Alice says hi"))
+	if err := os.WriteFile(filepath.Join(dir, "report.md"), []byte("**Alice** (m): Alice says hi"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "narration.wav"), []byte("RIFF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var log strings.Builder
+	zw := gzip.NewWriter(&log)
+	zw.Write([]byte("Alice spoke"))
+	zw.Close()
+	if err := os.WriteFile(filepath.Join(dir, "debate.log.gz"), []byte(log.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(t.TempDir(), "shared.tar.gz")
+	if err := Archive(dir, dest, NewPseudonyms()); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(zr)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(hdr.Name, ".gz") {
+			gz, err := gzip.NewReader(strings.NewReader(string(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data, err = io.ReadAll(gz); err != nil {
+				t.Fatal(err)
+			}
+		}
+		files[filepath.Base(hdr.Name)] = string(data)
+	}
+	if _, ok := files["narration.wav"]; ok {
+		t.Error("expected audio to be left out")
+	}
+	if got := files["report.md"]; got != "**Agent A** (Model 1): Agent A says hi" {
+		t.Errorf("unexpected report.md %q", got)
+	}
+	if got := files["debate.log.gz"]; got != "Agent A spoke" {
+		t.Errorf("unexpected debate.log.gz %q", got)
+	}
+	if got := files["transcript.json"]; strings.Contains(got, "Alice") || !strings.Contains(got, `"Name":"Agent A"`) {
+		t.Errorf("unexpected transcript.json %q", got)
+	}
+}
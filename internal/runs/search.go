@@ -0,0 +1,74 @@
+package runs
+
+import (
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// contextChars is how much text is kept on each side of a match.
+const contextChars = 80
+
+// Match is a transcript turn containing the search query.
+type Match struct {
+	Run     Run
+	Turn    debate.Turn
+	Snippet string // the matching text with surrounding context
+}
+
+// Search returns every turn under base whose content contains query,
+// case-insensitively, newest run first. At most limit matches are returned
+// when limit is positive. Encrypted runs and runs Scan cannot read are
+// skipped.
+func Search(base, query string, limit int) ([]Match, error) {
+	runs, _, err := Scan(base)
+	if err != nil {
+		return nil, err
+	}
+	needle := strings.ToLower(query)
+	var matches []Match
+	for _, run := range runs {
+		if run.Encrypted {
+			continue
+		}
+		transcript, err := LoadTranscript(run.Dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, turn := range transcript.Turns {
+			idx := strings.Index(strings.ToLower(turn.Content), needle)
+			if idx < 0 {
+				continue
+			}
+			matches = append(matches, Match{Run: run, Turn: turn, Snippet: snippet(turn.Content, idx, len(needle))})
+			if limit > 0 && len(matches) >= limit {
+				return matches, nil
+			}
+		}
+	}
+	return matches, nil
+}
+
+// snippet returns the text around s[idx:idx+n], marking truncation with
+// ellipses and flattening newlines.
+func snippet(s string, idx, n int) string {
+	start := max(0, idx-contextChars)
+	end := min(len(s), idx+n+contextChars)
+	// Avoid cutting through a multi-byte rune.
+	for start > 0 && !isRuneStart(s[start]) {
+		start--
+	}
+	for end < len(s) && !isRuneStart(s[end]) {
+		end++
+	}
+	out := strings.Join(strings.Fields(s[start:end]), " ")
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(s) {
+		out += "…"
+	}
+	return out
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
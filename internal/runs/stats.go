@@ -0,0 +1,110 @@
+package runs
+
+import (
+	"sort"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// Stats summarizes the saved runs under a directory.
+type Stats struct {
+	Debates   int
+	Consensus int                    // debates whose free debate reached consensus
+	Outcomes  map[debate.Verdict]int // finished debates by outcome; runs saved before outcomes were recorded are not counted
+	// RoundsToConsensus is the mean number of free-debate rounds the
+	// debates reaching consensus needed; 0 if none did.
+	RoundsToConsensus float64
+	Models            []ModelUsage // by turns spoken, most used first
+	Tokens            int          // tokens recorded across all runs
+	Metered           int          // runs that recorded their token usage
+}
+
+// ModelUsage is how much one model spoke across saved runs.
+type ModelUsage struct {
+	Model   string
+	Turns   int
+	Debates int
+}
+
+// ConsensusRate returns the share of debates that reached consensus.
+func (s *Stats) ConsensusRate() float64 {
+	if s.Debates == 0 {
+		return 0
+	}
+	return float64(s.Consensus) / float64(s.Debates)
+}
+
+// Summarize computes Stats over every run under base. Encrypted runs and
+// runs Scan cannot read are left out.
+func Summarize(base string) (*Stats, error) {
+	runs, _, err := Scan(base)
+	if err != nil {
+		return nil, err
+	}
+	stats := &Stats{Outcomes: make(map[debate.Verdict]int)}
+	usage := make(map[string]*ModelUsage)
+	rounds := 0
+	for _, run := range runs {
+		if run.Encrypted {
+			continue
+		}
+		transcript, err := LoadTranscript(run.Dir)
+		if err != nil {
+			return nil, err
+		}
+		stats.Debates++
+		if transcript.Phase == debate.TenthManPhase {
+			stats.Consensus++
+			rounds += consensusRound(transcript)
+		}
+		if transcript.Outcome != "" {
+			stats.Outcomes[transcript.Outcome]++
+		}
+		if transcript.Tokens > 0 {
+			stats.Tokens += transcript.Tokens
+			stats.Metered++
+		}
+		seen := make(map[string]bool)
+		for _, turn := range transcript.Turns {
+			model := turn.Agent.Model
+			if model == "" || turn.Agent.Role == "moderator" {
+				continue
+			}
+			u := usage[model]
+			if u == nil {
+				u = &ModelUsage{Model: model}
+				usage[model] = u
+			}
+			u.Turns++
+			if !seen[model] {
+				seen[model] = true
+				u.Debates++
+			}
+		}
+	}
+	if stats.Consensus > 0 {
+		stats.RoundsToConsensus = float64(rounds) / float64(stats.Consensus)
+	}
+	for _, u := range usage {
+		stats.Models = append(stats.Models, *u)
+	}
+	sort.Slice(stats.Models, func(i, j int) bool {
+		a, b := stats.Models[i], stats.Models[j]
+		if a.Turns != b.Turns {
+			return a.Turns > b.Turns
+		}
+		return a.Model < b.Model
+	})
+	return stats, nil
+}
+
+// consensusRound returns the round after which the free debate reached
+// consensus: the one before the Tenth Man first spoke.
+func consensusRound(t *debate.Transcript) int {
+	for _, turn := range t.Turns {
+		if turn.Agent.Role == "tenth-man" {
+			return turn.Round - 1
+		}
+	}
+	return t.Rounds
+}
@@ -0,0 +1,71 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, spec string) *Schedule {
+	t.Helper()
+	s, err := Parse(spec)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", spec, err)
+	}
+	return s
+}
+
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		spec  string
+		after string
+		want  string
+	}{
+		{"* * * * *", "2026-03-10 12:00", "2026-03-10 12:01"},
+		{"*/15 * * * *", "2026-03-10 12:01", "2026-03-10 12:15"},
+		{"0 9 * * 1", "2026-03-10 12:00", "2026-03-16 09:00"}, // next Monday
+		{"30 8 1 * *", "2026-03-10 12:00", "2026-04-01 08:30"},
+		{"0 0 * * 7", "2026-03-10 12:00", "2026-03-15 00:00"}, // 7 is Sunday
+		{"0 12 1-5 * *", "2026-03-05 12:00", "2026-04-01 12:00"},
+		{"@daily", "2026-12-31 23:59", "2027-01-01 00:00"},
+		{"@weekly", "2026-03-10 12:00", "2026-03-15 00:00"},
+		{"0 0 13 * 5", "2026-03-10 12:00", "2026-03-13 00:00"}, // Friday or the 13th
+	}
+	for _, tt := range tests {
+		got := mustParse(t, tt.spec).Next(at(tt.after))
+		if !got.Equal(at(tt.want)) {
+			t.Errorf("Next(%q after %s) = %s, want %s", tt.spec, tt.after, got.Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+}
+
+func TestNextNeverFires(t *testing.T) {
+	if got := mustParse(t, "0 0 30 2 *").Next(at("2026-01-01 00:00")); !got.IsZero() {
+		t.Errorf("expected zero time for February 30th, got %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) expected error", spec)
+		}
+	}
+}
@@ -0,0 +1,467 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/health"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/store"
+)
+
+// Handler returns the web dashboard at /, the probes and the HTTP API:
+//
+//	GET  /healthz           liveness: 200 while the process serves requests
+//	GET  /readyz            readiness: 200 if the store and the health checks pass, 503 otherwise
+//	POST /runs              start a debate (body: runner.Job JSON)
+//	GET  /runs              list runs
+//	GET  /runs/{id}         get a single run
+//	GET  /runs/{id}/stream  the run's turns as server-sent events, live until it finishes
+//	POST /runs/{id}/events  inject new information into a running debate (body: {"content": "..."})
+//	POST /runs/{id}/swap    replace an agent's model from the next round (body: {"agent": "...", "model": "..."})
+//	POST /runs/{id}/remove  take a debater out from the next round (body: {"agent": "...", "reason": "..."})
+//	POST /runs/{id}/join    add a debater from the next round (body: {"name": "...", "model": "...", "persona": "...", "frame": "...", "expert": "..."})
+//	GET  /runs/{id}/transcript  the run's transcript as of its last completed round
+//	POST /runs/{id}/resume  restart a run interrupted by a server restart from its last checkpoint
+//	POST /runs/{id}/cancel  stop a run, saving the rounds it completed
+//	POST /runs/{id}/priority  reorder a queued run (body: {"priority": N}; higher starts first)
+//	GET  /history           past debates in the store (query: topic, since, limit)
+//	GET  /history/stats     counts of the same debates by verdict, and how often consensus survived
+//	GET  /usage             the calling tenant's token usage this month
+//	GET  /debug/pprof/      Go runtime profiles, if enabled with SetProfiling
+//
+// When tenants are configured every API request needs a tenant's bearer
+// token, and runs started by one tenant are invisible to the others. The
+// dashboard and the probes are always open.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /runs/{id}/stream", s.handleStream)
+	mux.HandleFunc("POST /runs", s.handleCreateRun)
+	mux.HandleFunc("GET /runs", s.handleListRuns)
+	mux.HandleFunc("GET /runs/{id}", s.handleGetRun)
+	mux.HandleFunc("POST /runs/{id}/events", s.handleInjectEvent)
+	mux.HandleFunc("POST /runs/{id}/swap", s.handleSwapModel)
+	mux.HandleFunc("POST /runs/{id}/remove", s.handleRemoveAgent)
+	mux.HandleFunc("POST /runs/{id}/join", s.handleJoinAgent)
+	mux.HandleFunc("GET /runs/{id}/transcript", s.handleGetTranscript)
+	mux.HandleFunc("POST /runs/{id}/resume", s.handleResume)
+	mux.HandleFunc("POST /runs/{id}/cancel", s.handleCancel)
+	mux.HandleFunc("POST /runs/{id}/priority", s.handleSetPriority)
+	mux.HandleFunc("GET /history", s.handleHistory)
+	mux.HandleFunc("GET /history/stats", s.handleHistoryStats)
+	mux.HandleFunc("GET /usage", s.handleUsage)
+
+	root := http.NewServeMux()
+	root.Handle("GET /{$}", dashboard())
+	root.Handle("GET /ui/", http.StripPrefix("/ui/", dashboard()))
+	root.HandleFunc("GET /healthz", handleHealthz)
+	root.HandleFunc("GET /readyz", s.handleReadyz)
+	if s.profiling {
+		root.Handle("/debug/pprof/", s.authenticate(profiles()))
+	}
+	root.Handle("/", s.authenticate(mux))
+	return root
+}
+
+func handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readyTimeout bounds the readiness checks, so a hung dependency fails the
+// probe instead of stalling it.
+const readyTimeout = 5 * time.Second
+
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+	results := s.Ready(ctx)
+	status, code := "ok", http.StatusOK
+	if !health.Healthy(results) {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]any{"status": status, "checks": results})
+}
+
+func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
+	var job runner.Job
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	job = job.WithDefaults(s.defaults)
+	if err := job.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tenant := tenantFrom(r)
+	if tenant != nil && tenant.overQuota(s.now()) {
+		writeError(w, http.StatusTooManyRequests, "monthly token quota exhausted")
+		return
+	}
+	run, err := s.startFor("api", tenant, job)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	tenant := tenantFrom(r)
+	runs := []Run{}
+	for _, run := range s.Runs() {
+		if visible(run, tenant) {
+			runs = append(runs, run)
+		}
+	}
+	writeJSON(w, http.StatusOK, runs)
+}
+
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.visibleRun(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "run not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// visibleRun returns the run named in r's path if the caller may see it.
+func (s *Server) visibleRun(r *http.Request) (Run, bool) {
+	run, ok := s.Get(r.PathValue("id"))
+	if !ok || !visible(run, tenantFrom(r)) {
+		return Run{}, false
+	}
+	return run, true
+}
+
+// visible reports whether tenant may see run. Everything is visible on an
+// open API.
+func visible(run Run, tenant *tenantState) bool {
+	return tenant == nil || run.owner == tenant
+}
+
+// handleStream sends every turn of a run as a "turn" event, then a "done"
+// event with the final run record once it is no longer running.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.visibleRun(r); !ok {
+		writeError(w, http.StatusNotFound, ErrRunNotFound.Error())
+		return
+	}
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	s.watch(r.Context(), r.PathValue("id"), func(turns []debate.Turn, _ []debate.Event, run Run) error {
+		for _, turn := range turns {
+			writeEvent(w, "turn", turn)
+		}
+		if run.done() {
+			writeEvent(w, "done", run)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+}
+
+func writeEvent(w http.ResponseWriter, event string, v any) {
+	data, _ := json.Marshal(v)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+func (s *Server) handleInjectEvent(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(body.Content) == "" {
+		writeError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+	if _, ok := s.visibleRun(r); !ok {
+		writeError(w, http.StatusNotFound, ErrRunNotFound.Error())
+		return
+	}
+	switch err := s.Inject(r.PathValue("id"), body.Content); {
+	case errors.Is(err, ErrRunNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrRunNotRunning):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrEventsFull):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "queued"})
+	}
+}
+
+func (s *Server) handleSwapModel(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Agent string `json:"agent"`
+		Model string `json:"model"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(body.Agent) == "" || strings.TrimSpace(body.Model) == "" {
+		writeError(w, http.StatusBadRequest, "agent and model are required")
+		return
+	}
+	if _, ok := s.visibleRun(r); !ok {
+		writeError(w, http.StatusNotFound, ErrRunNotFound.Error())
+		return
+	}
+	writeControlResult(w, s.Swap(r.PathValue("id"), body.Agent, body.Model))
+}
+
+func (s *Server) handleRemoveAgent(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Agent  string `json:"agent"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(body.Agent) == "" {
+		writeError(w, http.StatusBadRequest, "agent is required")
+		return
+	}
+	if body.Reason == "" {
+		body.Reason = "removed by the operator"
+	}
+	if _, ok := s.visibleRun(r); !ok {
+		writeError(w, http.StatusNotFound, ErrRunNotFound.Error())
+		return
+	}
+	writeControlResult(w, s.Remove(r.PathValue("id"), body.Agent, body.Reason))
+}
+
+func (s *Server) handleJoinAgent(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name    string `json:"name"`
+		Model   string `json:"model"`
+		Persona string `json:"persona"`
+		Frame   string `json:"frame"`
+		Expert  string `json:"expert"` // built-in archetype; fills in the name, persona and frame left empty
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	agent := debate.Agent{Name: body.Name, Model: body.Model, Role: "debater", Persona: body.Persona, Frame: body.Frame}
+	if body.Expert != "" {
+		personas, err := runner.ExpertPersonas([]string{body.Expert})
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		agent.Name = cmp.Or(agent.Name, personas[0].Name)
+		agent.Persona = cmp.Or(agent.Persona, personas[0].Description)
+		agent.Frame = cmp.Or(agent.Frame, personas[0].Frame)
+	}
+	if strings.TrimSpace(agent.Name) == "" {
+		writeError(w, http.StatusBadRequest, "name or expert is required")
+		return
+	}
+	if _, ok := s.visibleRun(r); !ok {
+		writeError(w, http.StatusNotFound, ErrRunNotFound.Error())
+		return
+	}
+	writeControlResult(w, s.Join(r.PathValue("id"), agent))
+}
+
+// writeControlResult answers a request to change a running debate's agents.
+func writeControlResult(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrRunNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrUnknownAgent):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrAgentExists):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrRunNotRunning):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrEventsFull):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "queued"})
+	}
+}
+
+func (s *Server) handleGetTranscript(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.visibleRun(r); !ok {
+		writeError(w, http.StatusNotFound, ErrRunNotFound.Error())
+		return
+	}
+	t, err := s.Transcript(r.Context(), r.PathValue("id"))
+	switch {
+	case errors.Is(err, ErrRunNotFound), errors.Is(err, store.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNoStore):
+		writeError(w, http.StatusNotImplemented, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, t)
+	}
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.visibleRun(r); !ok {
+		writeError(w, http.StatusNotFound, ErrRunNotFound.Error())
+		return
+	}
+	run, err := s.Resume(r.PathValue("id"))
+	switch {
+	case errors.Is(err, ErrRunNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotResumable):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrQueueFull):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		writeJSON(w, http.StatusAccepted, run)
+	}
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.visibleRun(r); !ok {
+		writeError(w, http.StatusNotFound, ErrRunNotFound.Error())
+		return
+	}
+	run, err := s.Cancel(r.PathValue("id"))
+	switch {
+	case errors.Is(err, ErrRunNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrRunNotRunning):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSON(w, http.StatusAccepted, run)
+	}
+}
+
+func (s *Server) handleSetPriority(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Priority *int `json:"priority"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if body.Priority == nil {
+		writeError(w, http.StatusBadRequest, "priority is required")
+		return
+	}
+	if _, ok := s.visibleRun(r); !ok {
+		writeError(w, http.StatusNotFound, ErrRunNotFound.Error())
+		return
+	}
+	run, err := s.SetPriority(r.PathValue("id"), *body.Priority)
+	switch {
+	case errors.Is(err, ErrRunNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotQueued):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, run)
+	}
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if records, ok := s.history(w, r); ok {
+		writeJSON(w, http.StatusOK, records)
+	}
+}
+
+func (s *Server) handleHistoryStats(w http.ResponseWriter, r *http.Request) {
+	if records, ok := s.history(w, r); ok {
+		writeJSON(w, http.StatusOK, store.Tally(records))
+	}
+}
+
+// history queries the store with the request's filter and returns the
+// debates the caller may see. On failure it writes the error response and
+// returns false.
+func (s *Server) history(w http.ResponseWriter, r *http.Request) ([]store.DebateRecord, bool) {
+	querier, ok := s.store.(store.HistoryQuerier)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, "the transcript store does not support history queries")
+		return nil, false
+	}
+	q := r.URL.Query()
+	filter := store.HistoryFilter{Topic: q.Get("topic")}
+	if since := q.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "since must be an RFC 3339 time")
+			return nil, false
+		}
+		filter.Since = t
+	}
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return nil, false
+		}
+		filter.Limit = n
+	}
+	tenant := tenantFrom(r)
+	limit := filter.Limit
+	if tenant != nil {
+		// Other tenants' runs would count against the limit in the store.
+		filter.Limit = 0
+	}
+	records, err := querier.History(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	if tenant != nil {
+		// The store does not know about tenants, so only runs this server
+		// started for the caller are shown.
+		records = slices.DeleteFunc(records, func(rec store.DebateRecord) bool {
+			run, ok := s.Get(rec.ID)
+			return !ok || !visible(run, tenant)
+		})
+		if limit > 0 && len(records) > limit {
+			records = records[:limit]
+		}
+	}
+	return records, true
+}
+
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	tenant := tenantFrom(r)
+	if tenant == nil {
+		writeError(w, http.StatusNotImplemented, "no tenants are configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, tenant.usage(s.now()))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
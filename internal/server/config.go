@@ -0,0 +1,69 @@
+package server
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/notify"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/schedule"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the serve-mode configuration file.
+type Config struct {
+	Notify    notify.Config `yaml:"notify"`
+	Store     store.Config  `yaml:"store"`
+	Tenants   []Tenant      `yaml:"tenants"`
+	Schedules []Entry       `yaml:"schedules"`
+}
+
+// Entry is a recurring debate. The job's name identifies the schedule and
+// names its output folders.
+type Entry struct {
+	Cron string     `yaml:"cron"`
+	Job  runner.Job `yaml:",inline"`
+
+	schedule *schedule.Schedule
+}
+
+// LoadConfig reads and validates a serve-mode YAML config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("server: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("server: parsing %s: %w", path, err)
+	}
+
+	for i := range cfg.Tenants {
+		cfg.Tenants[i].Token = os.ExpandEnv(cfg.Tenants[i].Token)
+	}
+	if err := validateTenants(cfg.Tenants); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for i := range cfg.Schedules {
+		entry := &cfg.Schedules[i]
+		if entry.Job.Name == "" {
+			return nil, fmt.Errorf("server: schedule %d: name is required", i+1)
+		}
+		if seen[entry.Job.Name] {
+			return nil, fmt.Errorf("server: schedule %q defined twice", entry.Job.Name)
+		}
+		seen[entry.Job.Name] = true
+		if entry.Job.Topic == "" {
+			return nil, fmt.Errorf("server: schedule %q: topic is required", entry.Job.Name)
+		}
+		sched, err := schedule.Parse(entry.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("server: schedule %q: %w", entry.Job.Name, err)
+		}
+		entry.schedule = sched
+	}
+	return &cfg, nil
+}
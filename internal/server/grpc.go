@@ -0,0 +1,335 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	tenthmanv1 "github.com/lorenzotomasdiez/tenth-man-rule/gen/tenthman/v1"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCServer returns a gRPC server offering the DebateService of
+// proto/tenthman/v1/tenthman.proto over the same runs as the HTTP API. When
+// tenants are configured every call needs a tenant's token as
+// "authorization: Bearer <token>" metadata, with the same rate limits and
+// run isolation as HTTP requests.
+func (s *Server) GRPCServer() *grpc.Server {
+	g := grpc.NewServer(grpc.UnaryInterceptor(s.authenticateUnary), grpc.StreamInterceptor(s.authenticateStream))
+	tenthmanv1.RegisterDebateServiceServer(g, &debateService{s: s})
+	return g
+}
+
+// authenticateUnary authenticates a unary call like authenticate does an
+// HTTP request.
+func (s *Server) authenticateUnary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticateCall(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authenticateStream authenticates a streaming call like authenticate does
+// an HTTP request.
+func (s *Server) authenticateStream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticateCall(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticateCall returns ctx with the calling tenant, rejecting calls
+// without a known token and calls over their tenant's rate limit.
+func (s *Server) authenticateCall(ctx context.Context) (context.Context, error) {
+	if len(s.tenants) == 0 {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var tenant *tenantState
+	for _, v := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(v, "Bearer "); ok {
+			tenant = s.tenantFor(token)
+		}
+	}
+	if tenant == nil {
+		return nil, status.Error(codes.Unauthenticated, "a valid API token is required")
+	}
+	if wait := tenant.take(s.now()); wait > 0 {
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded; retry in %s", wait.Round(time.Second))
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant), nil
+}
+
+// authenticatedStream is a server stream whose context carries the calling
+// tenant.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (a *authenticatedStream) Context() context.Context { return a.ctx }
+
+// debateService implements the DebateService over a Server.
+type debateService struct {
+	tenthmanv1.UnimplementedDebateServiceServer
+	s *Server
+}
+
+func (d *debateService) StartRun(ctx context.Context, req *tenthmanv1.StartRunRequest) (*tenthmanv1.Run, error) {
+	job := jobFromProto(req.GetJob()).WithDefaults(d.s.defaults)
+	if err := job.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	tenant := tenantIn(ctx)
+	if tenant != nil && tenant.overQuota(d.s.now()) {
+		return nil, status.Error(codes.ResourceExhausted, "monthly token quota exhausted")
+	}
+	run, err := d.s.startFor("api", tenant, job)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return runToProto(run), nil
+}
+
+func (d *debateService) GetRun(ctx context.Context, req *tenthmanv1.GetRunRequest) (*tenthmanv1.Run, error) {
+	run, err := d.visibleRun(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return runToProto(run), nil
+}
+
+func (d *debateService) ListRuns(ctx context.Context, _ *tenthmanv1.ListRunsRequest) (*tenthmanv1.ListRunsResponse, error) {
+	tenant := tenantIn(ctx)
+	resp := &tenthmanv1.ListRunsResponse{}
+	for _, run := range d.s.Runs() {
+		if visible(run, tenant) {
+			resp.Runs = append(resp.Runs, runToProto(run))
+		}
+	}
+	return resp, nil
+}
+
+func (d *debateService) InjectEvent(ctx context.Context, req *tenthmanv1.InjectEventRequest) (*tenthmanv1.InjectEventResponse, error) {
+	if strings.TrimSpace(req.GetContent()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "content is required")
+	}
+	if _, err := d.visibleRun(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	if err := d.s.Inject(req.GetId(), req.GetContent()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &tenthmanv1.InjectEventResponse{}, nil
+}
+
+func (d *debateService) StreamTurns(req *tenthmanv1.StreamRequest, stream grpc.ServerStreamingServer[tenthmanv1.Turn]) error {
+	if _, err := d.visibleRun(stream.Context(), req.GetId()); err != nil {
+		return err
+	}
+	err := d.s.watch(stream.Context(), req.GetId(), func(turns []debate.Turn, _ []debate.Event, _ Run) error {
+		for _, turn := range turns {
+			if err := stream.Send(turnToProto(turn)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return grpcError(err)
+}
+
+func (d *debateService) StreamEvents(req *tenthmanv1.StreamRequest, stream grpc.ServerStreamingServer[tenthmanv1.Event]) error {
+	if _, err := d.visibleRun(stream.Context(), req.GetId()); err != nil {
+		return err
+	}
+	err := d.s.watch(stream.Context(), req.GetId(), func(_ []debate.Turn, events []debate.Event, run Run) error {
+		for _, ev := range events {
+			if pb := eventToProto(ev); pb != nil {
+				if err := stream.Send(pb); err != nil {
+					return err
+				}
+			}
+		}
+		if run.done() {
+			return stream.Send(&tenthmanv1.Event{Event: &tenthmanv1.Event_Done{Done: runToProto(run)}})
+		}
+		return nil
+	})
+	return grpcError(err)
+}
+
+// visibleRun returns the run with id if the caller of ctx may see it.
+func (d *debateService) visibleRun(ctx context.Context, id string) (Run, error) {
+	run, ok := d.s.Get(id)
+	if !ok || !visible(run, tenantIn(ctx)) {
+		return Run{}, status.Error(codes.NotFound, ErrRunNotFound.Error())
+	}
+	return run, nil
+}
+
+// grpcError returns err with the gRPC status code matching it, as the HTTP
+// API's status codes do.
+func grpcError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrRunNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrRunNotRunning):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrEventsFull), errors.Is(err, ErrQueueFull):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return err
+	}
+}
+
+func jobFromProto(pb *tenthmanv1.Job) runner.Job {
+	return runner.Job{
+		Topic:            pb.GetTopic(),
+		Name:             pb.GetName(),
+		Agents:           int(pb.GetAgents()),
+		MinRounds:        int(pb.GetMinRounds()),
+		MaxRounds:        int(pb.GetMaxRounds()),
+		TenthManRounds:   int(pb.GetTenthManRounds()),
+		StagnationRounds: int(pb.GetStagnationRounds()),
+		Instructions:     pb.GetInstructions(),
+		Experts:          pb.GetExperts(),
+		Compress:         pb.GetCompress(),
+		Upload:           pb.GetUpload(),
+	}
+}
+
+func jobToProto(job runner.Job) *tenthmanv1.Job {
+	return &tenthmanv1.Job{
+		Topic:            job.Topic,
+		Name:             job.Name,
+		Agents:           int32(job.Agents),
+		MinRounds:        int32(job.MinRounds),
+		MaxRounds:        int32(job.MaxRounds),
+		TenthManRounds:   int32(job.TenthManRounds),
+		StagnationRounds: int32(job.StagnationRounds),
+		Instructions:     job.Instructions,
+		Experts:          job.Experts,
+		Compress:         job.Compress,
+		Upload:           job.Upload,
+	}
+}
+
+// statuses maps run statuses to their protobuf values.
+var statuses = map[string]tenthmanv1.Status{
+	StatusQueued:      tenthmanv1.Status_STATUS_QUEUED,
+	StatusRunning:     tenthmanv1.Status_STATUS_RUNNING,
+	StatusCompleted:   tenthmanv1.Status_STATUS_COMPLETED,
+	StatusFailed:      tenthmanv1.Status_STATUS_FAILED,
+	StatusInterrupted: tenthmanv1.Status_STATUS_INTERRUPTED,
+	StatusCancelling:  tenthmanv1.Status_STATUS_CANCELLING,
+	StatusCancelled:   tenthmanv1.Status_STATUS_CANCELLED,
+}
+
+func runToProto(run Run) *tenthmanv1.Run {
+	pb := &tenthmanv1.Run{
+		Id:        run.ID,
+		Source:    run.Source,
+		Tenant:    run.Tenant,
+		Job:       jobToProto(run.Job),
+		Status:    statuses[run.Status],
+		StartedAt: timestamppb.New(run.StartedAt),
+		Dir:       run.Dir,
+		Rounds:    int32(run.Rounds),
+		Consensus: consensusToProto(run.Consensus),
+		Error:     run.Error,
+		Tokens:    int64(run.Tokens),
+	}
+	if run.FinishedAt != nil {
+		pb.FinishedAt = timestamppb.New(*run.FinishedAt)
+	}
+	switch run.Phase {
+	case "free_debate":
+		pb.Phase = tenthmanv1.Phase_PHASE_FREE_DEBATE
+	case "tenth_man":
+		pb.Phase = tenthmanv1.Phase_PHASE_TENTH_MAN
+	}
+	if len(run.Models) > 0 {
+		pb.Models = make(map[string]int32, len(run.Models))
+		for model, turns := range run.Models {
+			pb.Models[model] = int32(turns)
+		}
+	}
+	return pb
+}
+
+func phaseToProto(p debate.Phase) tenthmanv1.Phase {
+	if p == debate.TenthManPhase {
+		return tenthmanv1.Phase_PHASE_TENTH_MAN
+	}
+	return tenthmanv1.Phase_PHASE_FREE_DEBATE
+}
+
+func consensusToProto(c *debate.ConsensusResult) *tenthmanv1.Consensus {
+	if c == nil {
+		return nil
+	}
+	return &tenthmanv1.Consensus{
+		Detected:       c.Detected,
+		Position:       c.Position,
+		AgreementScore: int32(c.Score),
+		Dissenters:     c.Dissenters,
+	}
+}
+
+func agentToProto(a debate.Agent) *tenthmanv1.Agent {
+	return &tenthmanv1.Agent{Id: int32(a.ID), Name: a.Name, Model: a.Model, Role: a.Role}
+}
+
+func turnToProto(t debate.Turn) *tenthmanv1.Turn {
+	pb := &tenthmanv1.Turn{
+		Id:        int32(t.ID),
+		Round:     int32(t.Round),
+		Agent:     agentToProto(t.Agent),
+		Content:   t.Content,
+		InReplyTo: int32(t.InReplyTo),
+	}
+	if t.Confidence != nil {
+		c := int32(*t.Confidence)
+		pb.Confidence = &c
+	}
+	return pb
+}
+
+// eventToProto returns ev as a protobuf event, or nil for the kinds of event
+// the Event message does not cover.
+func eventToProto(ev debate.Event) *tenthmanv1.Event {
+	switch ev := ev.(type) {
+	case debate.TurnCompleted:
+		return &tenthmanv1.Event{Event: &tenthmanv1.Event_TurnCompleted{TurnCompleted: turnToProto(ev.Turn)}}
+	case debate.PhaseChanged:
+		return &tenthmanv1.Event{Event: &tenthmanv1.Event_PhaseChanged{PhaseChanged: phaseToProto(ev.Phase)}}
+	case debate.RoundStarted:
+		return &tenthmanv1.Event{Event: &tenthmanv1.Event_RoundStarted{RoundStarted: &tenthmanv1.RoundSummary{Round: int32(ev.Round), Phase: phaseToProto(ev.Phase)}}}
+	case debate.RoundEnded:
+		return &tenthmanv1.Event{Event: &tenthmanv1.Event_RoundEnded{RoundEnded: &tenthmanv1.RoundSummary{Round: int32(ev.Round), Phase: phaseToProto(ev.Phase)}}}
+	case debate.ConsensusEvaluated:
+		return &tenthmanv1.Event{Event: &tenthmanv1.Event_ConsensusEvaluated{ConsensusEvaluated: consensusToProto(ev.Result)}}
+	case debate.TenthManActivated:
+		return &tenthmanv1.Event{Event: &tenthmanv1.Event_TenthManActivated{TenthManActivated: &tenthmanv1.TenthManActivated{Agent: agentToProto(ev.Agent), Position: ev.Position}}}
+	case debate.AgentError:
+		msg := ""
+		if ev.Err != nil {
+			msg = ev.Err.Error()
+		}
+		return &tenthmanv1.Event{Event: &tenthmanv1.Event_AgentError{AgentError: &tenthmanv1.AgentError{Agent: agentToProto(ev.Agent), Error: msg, WillRetry: ev.WillRetry}}}
+	}
+	return nil
+}
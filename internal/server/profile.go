@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// SetProfiling serves the Go runtime profiles under /debug/pprof/, for go
+// tool pprof: CPU, heap, allocations, goroutines and execution traces. They
+// are off by default and need a tenant's token when tenants are configured.
+// The command line is not served, as it may hold an API key.
+func (s *Server) SetProfiling(on bool) {
+	s.profiling = on
+}
+
+// profiles returns the handler of /debug/pprof/.
+func profiles() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	return mux
+}
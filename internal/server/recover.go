@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/store"
+)
+
+// SetAutoResume makes Serve resume the runs a previous server left
+// unfinished as soon as it starts. Otherwise they are listed as interrupted
+// until resumed with Resume. Either way, this needs a store that implements
+// store.RunTracker.
+func (s *Server) SetAutoResume(auto bool) {
+	s.resume = auto
+}
+
+// Resume restarts interrupted run id from its last checkpoint, or from the
+// beginning if it never completed a round.
+func (s *Server) Resume(id string) (Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.find(id)
+	if r == nil {
+		return Run{}, ErrRunNotFound
+	}
+	if r.Status != StatusInterrupted {
+		return Run{}, ErrNotResumable
+	}
+	if err := s.launch(r); err != nil {
+		return Run{}, err
+	}
+	r.Error = ""
+	r.changed()
+	return r.snapshot(), nil
+}
+
+// recover lists the runs the store knows of, so new run IDs do not reuse
+// theirs, and restores the ones still pending as interrupted runs.
+func (s *Server) recover(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	ids, err := s.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("server: recovering runs: %w", err)
+	}
+	tracker, ok := s.store.(store.RunTracker)
+	var pending []store.PendingRun
+	if ok {
+		if pending, err = tracker.Pending(ctx); err != nil {
+			return fmt.Errorf("server: recovering runs: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.skipSeq(id)
+	}
+	for _, p := range pending {
+		s.skipSeq(p.ID)
+		r, err := s.restore(ctx, p)
+		if err != nil {
+			log.Printf("server: %s: cannot resume: %v", p.ID, err)
+			continue
+		}
+		s.runs = append(s.runs, r)
+		if s.resume {
+			if err := s.launch(r); err != nil {
+				log.Printf("server: %s: %v", r.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// restore rebuilds an interrupted run from its pending record and last
+// checkpoint.
+func (s *Server) restore(ctx context.Context, p store.PendingRun) (*Run, error) {
+	var job runner.Job
+	if err := json.Unmarshal(p.Job, &job); err != nil {
+		return nil, err
+	}
+	prior, err := s.store.Load(ctx, p.ID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
+	}
+	events := make(chan string, eventBuffer)
+	job.Events = events
+	swaps := make(chan debate.ModelSwap, eventBuffer)
+	job.Swaps = swaps
+	removals := make(chan debate.AgentRemoval, eventBuffer)
+	job.Removals = removals
+	joins := make(chan debate.Agent, eventBuffer)
+	job.Joins = joins
+	cancel := make(chan struct{})
+	job.Cancel = cancel
+	job.Checkpoint = store.Checkpointer(s.store, p.ID)
+	job.Resume = prior
+
+	r := &Run{
+		ID:        p.ID,
+		Source:    p.Source,
+		Tenant:    p.Tenant,
+		Job:       job,
+		Status:    StatusInterrupted,
+		StartedAt: s.now(),
+		Error:     "interrupted by a server restart",
+		events:    events,
+		swaps:     swaps,
+		removals:  removals,
+		joins:     joins,
+		cancel:    cancel,
+		owner:     s.tenant(p.Tenant),
+		updated:   make(chan struct{}),
+	}
+	if prior != nil {
+		r.Rounds = prior.Rounds
+		r.Phase = "free_debate"
+		if prior.Phase == debate.TenthManPhase {
+			r.Phase = "tenth_man"
+		}
+		r.turns = prior.Turns
+	}
+	return r, nil
+}
+
+// skipSeq makes new run IDs start after id. s.mu must be held.
+func (s *Server) skipSeq(id string) {
+	if n, err := strconv.Atoi(strings.TrimPrefix(id, "run-")); err == nil && n > s.seq {
+		s.seq = n
+	}
+}
+
+// tenant returns the configured tenant called name, or nil.
+func (s *Server) tenant(name string) *tenantState {
+	for _, t := range s.tenants {
+		if name != "" && t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// begin records r as in flight in stores that track runs. s.mu must be
+// held, so the run cannot end first.
+func (s *Server) begin(r *Run) {
+	tracker, ok := s.store.(store.RunTracker)
+	if !ok {
+		return
+	}
+	job, err := json.Marshal(r.Job)
+	if err == nil {
+		err = tracker.Begin(s.baseCtx, store.PendingRun{ID: r.ID, Source: r.Source, Tenant: r.Tenant, Job: job})
+	}
+	if err != nil {
+		log.Printf("server: %s: %v", r.ID, err)
+	}
+}
+
+// end records run id as finished in stores that track runs.
+func (s *Server) end(id string) {
+	if tracker, ok := s.store.(store.RunTracker); ok {
+		if err := tracker.End(s.baseCtx, id); err != nil {
+			log.Printf("server: %s: %v", id, err)
+		}
+	}
+}
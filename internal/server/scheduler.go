@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/store"
+)
+
+// runSchedule starts entry's job at each activation time until ctx is done.
+// Activations that arrive while the previous run is still going are skipped.
+func (s *Server) runSchedule(ctx context.Context, entry Entry) {
+	source := "schedule:" + entry.Job.Name
+	for {
+		now := s.now()
+		next := entry.schedule.Next(now)
+		if next.IsZero() {
+			log.Printf("server: schedule %q never fires again", entry.Job.Name)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.after(next.Sub(now)):
+		}
+		if s.active(source) {
+			log.Printf("server: schedule %q: previous run still in progress, skipping", entry.Job.Name)
+			continue
+		}
+		if _, err := s.start(source, entry.Job); err != nil {
+			log.Printf("server: schedule %q: %v", entry.Job.Name, err)
+		}
+	}
+}
+
+// active reports whether a run from source is still queued or running.
+func (s *Server) active(source string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.runs {
+		if r.Source == source && (r.Status == StatusQueued || r.Status == StatusRunning || r.Status == StatusCancelling) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneInterval is how often expired debates are deleted from the store.
+const pruneInterval = time.Hour
+
+// runRetention deletes debates idle longer than the retention period, once at
+// startup and then every pruneInterval, until ctx is cancelled.
+func (s *Server) runRetention(ctx context.Context, pruner store.Pruner) {
+	for {
+		n, err := pruner.Prune(ctx, s.now().Add(-s.retention))
+		switch {
+		case err != nil:
+			log.Printf("server: retention: %v", err)
+		case n > 0:
+			log.Printf("server: retention: deleted %d debate(s) older than %s", n, s.retention)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.after(pruneInterval):
+		}
+	}
+}
@@ -0,0 +1,729 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/health"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/notify"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/store"
+	"google.golang.org/grpc"
+)
+
+// Run statuses.
+const (
+	StatusQueued      = "queued"
+	StatusRunning     = "running"
+	StatusCompleted   = "completed"
+	StatusFailed      = "failed"
+	StatusInterrupted = "interrupted" // cut short by a restart; see Resume
+	StatusCancelling  = "cancelling"  // asked to stop; ends as cancelled once the round under way is dropped
+	StatusCancelled   = "cancelled"   // stopped by Cancel, keeping the rounds it completed
+)
+
+// Run is the stored record of a debate executed by the server.
+type Run struct {
+	ID         string                  `json:"id"`
+	Source     string                  `json:"source"` // "api" or "schedule:<name>"
+	Tenant     string                  `json:"tenant,omitempty"`
+	Job        runner.Job              `json:"job"`
+	Status     string                  `json:"status"`
+	StartedAt  time.Time               `json:"started_at"`
+	FinishedAt *time.Time              `json:"finished_at,omitempty"`
+	Dir        string                  `json:"dir,omitempty"`
+	Rounds     int                     `json:"rounds,omitempty"` // completed rounds, updated while running
+	Phase      string                  `json:"phase,omitempty"`  // last phase reached: "free_debate" or "tenth_man"
+	Consensus  *debate.ConsensusResult `json:"consensus,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+	Tokens     int                     `json:"tokens,omitempty"` // LLM tokens consumed
+	Models     map[string]int          `json:"models,omitempty"` // turns per model
+
+	events   chan string              // new information for the running debate
+	swaps    chan debate.ModelSwap    // model swaps for the running debate
+	removals chan debate.AgentRemoval // agent removals for the running debate
+	joins    chan debate.Agent        // agents joining the running debate
+	cancel   chan struct{}            // closed to stop the running debate
+	owner    *tenantState             // tenant that started the run, nil for schedules and an open API
+	turns    []debate.Turn            // turns so far, for streaming
+	progress []debate.Event           // engine events so far, for streaming
+	updated  chan struct{}            // closed and replaced whenever turns or status change
+}
+
+// snapshot returns a copy of r that is safe to use without s.mu.
+func (r *Run) snapshot() Run {
+	c := *r
+	c.Models = maps.Clone(r.Models)
+	return c
+}
+
+// done reports whether the run has finished, successfully or not.
+func (r *Run) done() bool {
+	return r.Status == StatusCompleted || r.Status == StatusFailed || r.Status == StatusCancelled
+}
+
+// changed wakes everyone waiting on r.updated. s.mu must be held.
+func (r *Run) changed() {
+	close(r.updated)
+	r.updated = make(chan struct{})
+}
+
+// eventBuffer is how many injected events may wait for a run to pick them up.
+const eventBuffer = 16
+
+// Errors returned by Inject, Transcript, Resume, Cancel, SetPriority and
+// when starting runs.
+var (
+	ErrRunNotFound   = errors.New("run not found")
+	ErrRunNotRunning = errors.New("run is not running")
+	ErrEventsFull    = errors.New("too many pending events")
+	ErrUnknownAgent  = errors.New("no agent of that name in the run")
+	ErrAgentExists   = errors.New("an agent of that name is already in the run")
+	ErrNoStore       = errors.New("no transcript store configured")
+	ErrQueueFull     = errors.New("run queue is full")
+	ErrNotResumable  = errors.New("run is not interrupted")
+	ErrNotQueued     = errors.New("run is not queued")
+)
+
+// RunFunc executes a single debate job.
+type RunFunc func(ctx context.Context, job runner.Job) (*runner.Outcome, error)
+
+// Server runs debates on request and on schedule, keeping a record of every run.
+type Server struct {
+	run       RunFunc
+	defaults  runner.Job
+	notifiers []notify.Notifier
+	store     store.TranscriptStore
+	retention time.Duration
+	tenants   []*tenantState
+	workers   int           // 0 runs everything at once
+	queueSize int           // runs that may wait for a worker
+	ready     chan struct{} // signals the workers that runs are queued
+	resume    bool          // resume interrupted runs on startup
+	profiling bool          // serve /debug/pprof/
+	grpcAddr  string        // serve the gRPC API too, on this address
+	checks    []health.Check
+	baseCtx   context.Context
+	after     func(time.Duration) <-chan time.Time
+	now       func() time.Time
+
+	mu     sync.Mutex
+	runs   []*Run
+	queued []*Run // runs waiting for a worker, in the order they were queued
+	busy   int    // workers executing a run
+	seq    int
+	wg     sync.WaitGroup
+}
+
+// New creates a Server that executes jobs with run and reports every finished
+// run to notifiers.
+func New(run RunFunc, notifiers ...notify.Notifier) *Server {
+	return &Server{
+		run:       run,
+		notifiers: notifiers,
+		baseCtx:   context.Background(),
+		after:     time.After,
+		now:       time.Now,
+	}
+}
+
+// SetDefaults sets the values used for job fields left zero in API requests.
+func (s *Server) SetDefaults(defaults runner.Job) {
+	s.defaults = defaults
+}
+
+// SetStore makes every run checkpoint its transcript to st under the run ID
+// after each round, so GET /runs/{id}/transcript can serve it.
+func (s *Server) SetStore(st store.TranscriptStore) {
+	s.store = st
+}
+
+// SetWorkers makes the server run at most n debates at a time, queueing up to
+// queueSize more and rejecting runs beyond that with ErrQueueFull. Queued
+// runs start by their job's priority, highest first, and in the order they
+// were queued among equals. With n = 0 (the default) every run starts
+// immediately.
+func (s *Server) SetWorkers(n, queueSize int) {
+	s.workers = n
+	s.queueSize = queueSize
+	s.ready = make(chan struct{}, 1)
+}
+
+// SetHealthChecks adds checks, such as LLM provider reachability, to the
+// readiness probe. The store is checked too when it implements store.Pinger.
+func (s *Server) SetHealthChecks(checks ...health.Check) {
+	s.checks = checks
+}
+
+// Ready runs the readiness checks.
+func (s *Server) Ready(ctx context.Context) []health.Result {
+	checks := s.checks
+	if pinger, ok := s.store.(store.Pinger); ok {
+		checks = append([]health.Check{{Name: "store", Run: pinger.Ping}}, checks...)
+	}
+	return health.Run(ctx, checks)
+}
+
+// SetRetention makes the server delete debates idle for longer than d from
+// its store, if the store supports pruning. Zero keeps everything.
+func (s *Server) SetRetention(d time.Duration) {
+	s.retention = d
+}
+
+// Transcript returns the latest checkpoint of run id.
+func (s *Server) Transcript(ctx context.Context, id string) (*debate.Transcript, error) {
+	if _, ok := s.Get(id); !ok {
+		return nil, ErrRunNotFound
+	}
+	if s.store == nil {
+		return nil, ErrNoStore
+	}
+	return s.store.Load(ctx, id)
+}
+
+// Serve starts the schedules and the HTTP API on addr, and the gRPC API if
+// SetGRPCAddr gave it an address, blocking until ctx is cancelled. In-flight
+// runs are waited for before returning.
+func (s *Server) Serve(ctx context.Context, addr string, schedules []Entry) error {
+	s.baseCtx = ctx
+	var grpcListener net.Listener
+	if s.grpcAddr != "" {
+		var err error
+		if grpcListener, err = net.Listen("tcp", s.grpcAddr); err != nil {
+			return fmt.Errorf("server: %w", err)
+		}
+	}
+	if err := s.recover(ctx); err != nil {
+		if grpcListener != nil {
+			grpcListener.Close()
+		}
+		return err
+	}
+	s.startWorkers(ctx)
+	if pruner, ok := s.store.(store.Pruner); ok && s.retention > 0 {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runRetention(ctx, pruner)
+		}()
+	}
+	for _, entry := range schedules {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runSchedule(ctx, entry)
+		}()
+	}
+
+	httpServer := &http.Server{Addr: addr, Handler: s.Handler()}
+	errCh := make(chan error, 2)
+	go func() { errCh <- httpServer.ListenAndServe() }()
+	var grpcServer *grpc.Server
+	if grpcListener != nil {
+		grpcServer = s.GRPCServer()
+		go func() { errCh <- grpcServer.Serve(grpcListener) }()
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err = httpServer.Shutdown(shutdownCtx)
+		if grpcServer != nil {
+			stopGRPC(shutdownCtx, grpcServer)
+		}
+	case err = <-errCh:
+		httpServer.Close()
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+	}
+	s.wg.Wait()
+	s.abandonQueued()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server: %w", err)
+	}
+	return nil
+}
+
+// SetGRPCAddr makes Serve offer the gRPC API of GRPCServer on addr as well
+// as the HTTP API. An empty addr, the default, serves HTTP only.
+func (s *Server) SetGRPCAddr(addr string) {
+	s.grpcAddr = addr
+}
+
+// stopGRPC stops g gracefully, letting streams end with their runs, or at
+// once when ctx is done first.
+func stopGRPC(ctx context.Context, g *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		g.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		g.Stop()
+	}
+}
+
+// Runs returns a snapshot of every run, oldest first.
+func (s *Server) Runs() []Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Run, len(s.runs))
+	for i, r := range s.runs {
+		out[i] = r.snapshot()
+	}
+	return out
+}
+
+// Get returns a snapshot of the run with id.
+func (s *Server) Get(id string) (Run, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r := s.find(id); r != nil {
+		return r.snapshot(), true
+	}
+	return Run{}, false
+}
+
+// find returns the run with id, or nil. s.mu must be held.
+func (s *Server) find(id string) *Run {
+	for _, r := range s.runs {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// Inject queues content as new information for the running debate id. It is
+// shown to every agent as a moderator note from the next round.
+func (s *Server) Inject(id, content string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.find(id)
+	if r == nil {
+		return ErrRunNotFound
+	}
+	if r.done() || r.Status == StatusCancelling {
+		return ErrRunNotRunning
+	}
+	select {
+	case r.events <- content:
+		return nil
+	default:
+		return ErrEventsFull
+	}
+}
+
+// Swap queues a change of agent's model to model in the running debate id,
+// from its next round on. Once agents have spoken, agent must name one of
+// them.
+func (s *Server) Swap(id, agent, model string) error {
+	return s.control(id, func(r *Run) error {
+		if len(r.turns) > 0 && !r.spoke(agent) {
+			return ErrUnknownAgent
+		}
+		return offer(r.swaps, debate.ModelSwap{Agent: agent, To: model})
+	})
+}
+
+// Remove queues the removal of agent from the running debate id, for
+// reason, from its next round on. Once agents have spoken, agent must name
+// one of them.
+func (s *Server) Remove(id, agent, reason string) error {
+	return s.control(id, func(r *Run) error {
+		if len(r.turns) > 0 && !r.spoke(agent) {
+			return ErrUnknownAgent
+		}
+		return offer(r.removals, debate.AgentRemoval{Agent: agent, Reason: reason})
+	})
+}
+
+// Join queues agent to join the running debate id as a debater from its
+// next round on. Its name must not be one of an agent that has spoken.
+func (s *Server) Join(id string, agent debate.Agent) error {
+	return s.control(id, func(r *Run) error {
+		if r.spoke(agent.Name) {
+			return ErrAgentExists
+		}
+		return offer(r.joins, agent)
+	})
+}
+
+// control calls send with run id if it is running.
+func (s *Server) control(id string, send func(*Run) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.find(id)
+	if r == nil {
+		return ErrRunNotFound
+	}
+	if r.done() || r.Status == StatusCancelling {
+		return ErrRunNotRunning
+	}
+	return send(r)
+}
+
+// Cancel stops run id. A queued or interrupted run is cancelled at once. A
+// running one is marked cancelling until its debate has stopped, dropping
+// the round under way, and its artifacts for the rounds it completed are
+// written; it is then cancelled.
+func (s *Server) Cancel(id string) (Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.find(id)
+	if r == nil {
+		return Run{}, ErrRunNotFound
+	}
+	switch r.Status {
+	case StatusQueued, StatusInterrupted:
+		if i := slices.Index(s.queued, r); i >= 0 {
+			s.queued = slices.Delete(s.queued, i, i+1)
+		}
+		finished := s.now()
+		r.FinishedAt = &finished
+		r.Status = StatusCancelled
+		r.Error = ""
+		s.end(r.ID)
+	case StatusRunning:
+		close(r.cancel)
+		r.Status = StatusCancelling
+	case StatusCancelling:
+	default:
+		return Run{}, ErrRunNotRunning
+	}
+	r.changed()
+	return r.snapshot(), nil
+}
+
+// SetPriority changes the priority of queued run id, moving it ahead of the
+// queued runs of lower priority or behind those of higher priority.
+func (s *Server) SetPriority(id string, priority int) (Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.find(id)
+	if r == nil {
+		return Run{}, ErrRunNotFound
+	}
+	if r.Status != StatusQueued {
+		return Run{}, ErrNotQueued
+	}
+	r.Job.Priority = priority
+	s.begin(r)
+	r.changed()
+	return r.snapshot(), nil
+}
+
+// spoke reports whether an agent named agent, compared case-insensitively,
+// has taken a turn in r.
+func (r *Run) spoke(agent string) bool {
+	return slices.ContainsFunc(r.turns, func(t debate.Turn) bool {
+		return t.Agent.Role != "moderator" && strings.EqualFold(t.Agent.Name, agent)
+	})
+}
+
+// offer sends v on ch, or returns ErrEventsFull if ch's buffer is full.
+func offer[T any](ch chan T, v T) error {
+	select {
+	case ch <- v:
+		return nil
+	default:
+		return ErrEventsFull
+	}
+}
+
+// start records a new run and executes it in the background, or queues it
+// when the workers are busy.
+func (s *Server) start(source string, job runner.Job) (Run, error) {
+	return s.startFor(source, nil, job)
+}
+
+// startFor starts a run on behalf of owner.
+func (s *Server) startFor(source string, owner *tenantState, job runner.Job) (Run, error) {
+	events := make(chan string, eventBuffer)
+	job.Events = events
+	swaps := make(chan debate.ModelSwap, eventBuffer)
+	job.Swaps = swaps
+	removals := make(chan debate.AgentRemoval, eventBuffer)
+	job.Removals = removals
+	joins := make(chan debate.Agent, eventBuffer)
+	job.Joins = joins
+	cancel := make(chan struct{})
+	job.Cancel = cancel
+
+	s.mu.Lock()
+	s.seq++
+	id := fmt.Sprintf("run-%d", s.seq)
+	if s.store != nil {
+		job.Checkpoint = store.Checkpointer(s.store, id)
+	}
+	r := &Run{
+		ID:        id,
+		Source:    source,
+		Job:       job,
+		StartedAt: s.now(),
+		Tenant:    tenantName(owner),
+		events:    events,
+		swaps:     swaps,
+		removals:  removals,
+		joins:     joins,
+		cancel:    cancel,
+		owner:     owner,
+		updated:   make(chan struct{}),
+	}
+	if err := s.launch(r); err != nil {
+		s.seq--
+		s.mu.Unlock()
+		return Run{}, err
+	}
+	s.begin(r)
+	s.runs = append(s.runs, r)
+	snapshot := r.snapshot()
+	s.mu.Unlock()
+	return snapshot, nil
+}
+
+// launch executes r in the background, or queues it when workers are
+// configured. s.mu must be held.
+func (s *Server) launch(r *Run) error {
+	if s.workers == 0 {
+		r.Status = StatusRunning
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.execute(r)
+		}()
+		return nil
+	}
+	// Idle workers take runs at once, so they do not count against the
+	// queue size.
+	if len(s.queued) >= s.queueSize+s.workers-s.busy {
+		return ErrQueueFull
+	}
+	r.Status = StatusQueued
+	s.queued = append(s.queued, r)
+	s.signal()
+	return nil
+}
+
+// signal wakes a worker waiting for queued runs, if one is waiting.
+func (s *Server) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// dequeue takes the queued run of highest priority, the first queued among
+// equals, or returns nil if none is queued. s.mu must be held.
+func (s *Server) dequeue() *Run {
+	if len(s.queued) == 0 {
+		return nil
+	}
+	next := 0
+	for i, r := range s.queued {
+		if r.Job.Priority > s.queued[next].Job.Priority {
+			next = i
+		}
+	}
+	r := s.queued[next]
+	s.queued = slices.Delete(s.queued, next, next+1)
+	r.Status = StatusRunning
+	r.changed()
+	s.busy++
+	if len(s.queued) > 0 {
+		s.signal()
+	}
+	return r
+}
+
+// startWorkers starts the worker pool, if configured. Workers stop taking
+// runs from the queue once ctx is done.
+func (s *Server) startWorkers(ctx context.Context) {
+	for range s.workers {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for ctx.Err() == nil {
+				s.mu.Lock()
+				r := s.dequeue()
+				s.mu.Unlock()
+				if r == nil {
+					select {
+					case <-ctx.Done():
+					case <-s.ready:
+					}
+					continue
+				}
+				s.execute(r)
+				s.mu.Lock()
+				s.busy--
+				s.mu.Unlock()
+			}
+		}()
+	}
+}
+
+// abandonQueued fails the runs still queued at shutdown.
+func (s *Server) abandonQueued() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.queued {
+		finished := s.now()
+		r.FinishedAt = &finished
+		r.Status = StatusFailed
+		r.Error = "server stopped before the run started"
+		r.changed()
+	}
+	s.queued = nil
+}
+
+// execute runs r, which launch or dequeue has marked running.
+func (s *Server) execute(r *Run) {
+	progress := make(chan debate.Event, eventBuffer)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range progress {
+			s.track(r, ev)
+		}
+	}()
+	job := r.Job
+	job.Progress = progress
+	outcome, err := s.run(s.baseCtx, job)
+	close(progress)
+	<-done
+
+	s.mu.Lock()
+	finished := s.now()
+	r.FinishedAt = &finished
+	if outcome != nil {
+		r.Tokens = outcome.Tokens
+		r.Dir = outcome.Dir
+		r.Consensus = outcome.Consensus
+		if outcome.Result != nil && outcome.Result.Transcript != nil {
+			r.Rounds = outcome.Result.Transcript.Rounds
+		}
+	}
+	cancelled := r.Status == StatusCancelling
+	switch {
+	case err != nil:
+		r.Status = StatusFailed
+		r.Error = err.Error()
+	case cancelled:
+		r.Status = StatusCancelled
+	default:
+		r.Status = StatusCompleted
+	}
+	r.changed()
+	snapshot := r.snapshot()
+	s.mu.Unlock()
+
+	if r.owner != nil {
+		r.owner.charge(finished, snapshot.Tokens)
+	}
+	// Runs cut short by shutdown stay pending, so the next server can
+	// resume them.
+	if err == nil || s.baseCtx.Err() == nil {
+		s.end(r.ID)
+	}
+	// A cancelled debate has no verdict to track.
+	if recorder, ok := s.store.(store.VerdictRecorder); ok && err == nil && !cancelled && outcome.Result != nil {
+		if err := recorder.RecordVerdict(s.baseCtx, r.ID, outcome.Result); err != nil {
+			log.Printf("server: %s: %v", r.ID, err)
+		}
+	}
+
+	s.notify(snapshot)
+}
+
+// track records the live progress of r from an engine event.
+func (s *Server) track(r *Run, ev debate.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch ev := ev.(type) {
+	case debate.PhaseChanged:
+		r.Phase = "free_debate"
+		if ev.Phase == debate.TenthManPhase {
+			r.Phase = "tenth_man"
+		}
+	case debate.RoundEnded:
+		r.Rounds = ev.Round
+	case debate.ConsensusEvaluated:
+		r.Consensus = ev.Result
+	case debate.TurnCompleted:
+		if r.Models == nil {
+			r.Models = make(map[string]int)
+		}
+		if ev.Turn.Agent.Model != "" {
+			r.Models[ev.Turn.Agent.Model]++
+		}
+		r.turns = append(r.turns, ev.Turn)
+	}
+	r.progress = append(r.progress, ev)
+	r.changed()
+}
+
+// watch calls send with the turns and events of run id recorded since its
+// last call and a snapshot of the run: once at first, then each time the run
+// changes, until it is done, ctx is cancelled or send fails.
+func (s *Server) watch(ctx context.Context, id string, send func(turns []debate.Turn, events []debate.Event, run Run) error) error {
+	sentTurns, sentEvents := 0, 0
+	for {
+		s.mu.Lock()
+		r := s.find(id)
+		if r == nil {
+			s.mu.Unlock()
+			return ErrRunNotFound
+		}
+		turns, events := r.turns[sentTurns:], r.progress[sentEvents:]
+		updated := r.updated
+		snapshot := r.snapshot()
+		s.mu.Unlock()
+
+		if err := send(turns, events, snapshot); err != nil {
+			return err
+		}
+		sentTurns += len(turns)
+		sentEvents += len(events)
+		if snapshot.done() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-updated:
+		}
+	}
+}
+
+func (s *Server) notify(r Run) {
+	report := notify.Report{
+		Name:       r.Job.Name,
+		Topic:      r.Job.Topic,
+		Dir:        r.Dir,
+		Rounds:     r.Rounds,
+		Consensus:  r.Consensus,
+		Error:      r.Error,
+		FinishedAt: *r.FinishedAt,
+	}
+	for _, n := range s.notifiers {
+		if err := n.Notify(s.baseCtx, report); err != nil {
+			log.Printf("server: %s: %v", r.ID, err)
+		}
+	}
+}
@@ -0,0 +1,1203 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tenthmanv1 "github.com/lorenzotomasdiez/tenth-man-rule/gen/tenthman/v1"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/health"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/notify"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type recordingNotifier struct {
+	mu      sync.Mutex
+	reports []notify.Report
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, report notify.Report) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.reports = append(n.reports, report)
+	return nil
+}
+
+func successfulRun(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+	return &runner.Outcome{
+		Dir:       "/out/" + job.Name,
+		Result:    &debate.Result{Transcript: &debate.Transcript{Rounds: 5}},
+		Consensus: &debate.ConsensusResult{Detected: true, Score: 8},
+	}, nil
+}
+
+func validJob() runner.Job {
+	return runner.Job{Topic: "Should we still use framework X?", Name: "framework-x", Agents: 3, MinRounds: 1, MaxRounds: 2}
+}
+
+func TestCreateRunStoresResultAndNotifies(t *testing.T) {
+	n := &recordingNotifier{}
+	s := New(successfulRun, n)
+
+	body, _ := json.Marshal(validJob())
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs", bytes.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created Run
+	json.NewDecoder(rec.Body).Decode(&created)
+	if created.ID == "" || created.Source != "api" {
+		t.Errorf("unexpected created run: %+v", created)
+	}
+
+	s.wg.Wait()
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/runs/"+created.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var got Run
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Status != StatusCompleted || got.Rounds != 5 || got.Consensus == nil || got.Dir != "/out/framework-x" {
+		t.Errorf("unexpected stored run: %+v", got)
+	}
+	if len(n.reports) != 1 || n.reports[0].Name != "framework-x" {
+		t.Errorf("expected one digest for framework-x, got %+v", n.reports)
+	}
+}
+
+func TestCreateRunRejectsInvalidJob(t *testing.T) {
+	s := New(successfulRun)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs", bytes.NewReader([]byte(`{"topic": ""}`))))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestCreateRunAppliesDefaults(t *testing.T) {
+	s := New(successfulRun)
+	s.SetDefaults(runner.Job{Agents: 4, MinRounds: 2, MaxRounds: 5})
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs", bytes.NewReader([]byte(`{"topic": "Remote work"}`))))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created Run
+	json.NewDecoder(rec.Body).Decode(&created)
+	if created.Job.Agents != 4 || created.Job.MinRounds != 2 || created.Job.MaxRounds != 5 {
+		t.Errorf("defaults not applied: %+v", created.Job)
+	}
+	s.wg.Wait()
+}
+
+func TestCreateRunIgnoresSinksFromJSON(t *testing.T) {
+	var mu sync.Mutex
+	var got runner.Job
+	s := New(func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		mu.Lock()
+		got = job
+		mu.Unlock()
+		return successfulRun(ctx, job)
+	})
+	s.SetDefaults(runner.Job{Sinks: []string{"webhook:https://hooks.example.com/ops"}})
+	body := `{"topic": "Remote work", "agents": 3, "min_rounds": 1, "max_rounds": 2, "sinks": ["file:/etc/cron.d/x", "webhook:http://169.254.169.254/"]}`
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	s.wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(got.Sinks, []string{"webhook:https://hooks.example.com/ops"}) {
+		t.Errorf("sinks = %v, want only the operator's", got.Sinks)
+	}
+}
+
+func TestCreateRunIgnoresServerPathsFromJSON(t *testing.T) {
+	var mu sync.Mutex
+	var got runner.Job
+	s := New(func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		mu.Lock()
+		got = job
+		mu.Unlock()
+		return successfulRun(ctx, job)
+	})
+	body := `{"topic": "Remote work", "agents": 3, "min_rounds": 1, "max_rounds": 2, "images": ["/etc/shadow"], "report_template": "/root/.ssh/id_rsa"}`
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	s.wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got.Images) != 0 || got.ReportTemplate != "" {
+		t.Errorf("server paths taken from JSON: images %v, report template %q", got.Images, got.ReportTemplate)
+	}
+}
+
+func TestGetRunNotFound(t *testing.T) {
+	s := New(successfulRun)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/runs/run-99", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestFailedRunRecordsError(t *testing.T) {
+	n := &recordingNotifier{}
+	s := New(func(context.Context, runner.Job) (*runner.Outcome, error) {
+		return nil, errors.New("all models rate limited")
+	}, n)
+	run, _ := s.start("api", validJob())
+	s.wg.Wait()
+
+	got, _ := s.Get(run.ID)
+	if got.Status != StatusFailed || got.Error != "all models rate limited" {
+		t.Errorf("unexpected run: %+v", got)
+	}
+	if len(n.reports) != 1 || n.reports[0].Error == "" {
+		t.Errorf("expected failure digest, got %+v", n.reports)
+	}
+}
+
+func TestInjectEventReachesRunningJob(t *testing.T) {
+	received := make(chan string, 1)
+	release := make(chan struct{})
+	s := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		received <- <-job.Events
+		<-release
+		return successfulRun(context.Background(), job)
+	})
+	run, _ := s.start("api", validJob())
+
+	post := func(id, body string) int {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs/"+id+"/events", bytes.NewReader([]byte(body))))
+		return rec.Code
+	}
+	if code := post(run.ID, `{"content": "Breaking: costs doubled."}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	if got := <-received; got != "Breaking: costs doubled." {
+		t.Errorf("job received %q", got)
+	}
+	if code := post(run.ID, `{"content": "  "}`); code != http.StatusBadRequest {
+		t.Errorf("empty content: expected 400, got %d", code)
+	}
+	if code := post("run-99", `{"content": "x"}`); code != http.StatusNotFound {
+		t.Errorf("unknown run: expected 404, got %d", code)
+	}
+
+	close(release)
+	s.wg.Wait()
+	if code := post(run.ID, `{"content": "too late"}`); code != http.StatusConflict {
+		t.Errorf("finished run: expected 409, got %d", code)
+	}
+}
+
+func TestSwapModelReachesRunningJob(t *testing.T) {
+	received := make(chan debate.ModelSwap, 1)
+	release := make(chan struct{})
+	s := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		job.Progress <- debate.TurnCompleted{Turn: debate.Turn{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "junk/model", Role: "debater"}}}
+		received <- <-job.Swaps
+		<-release
+		return successfulRun(context.Background(), job)
+	})
+	run, _ := s.start("api", validJob())
+	deadline := time.Now().Add(time.Second)
+	for got, _ := s.Get(run.ID); len(got.Models) == 0; got, _ = s.Get(run.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("turn not tracked")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	post := func(id, body string) int {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs/"+id+"/swap", bytes.NewReader([]byte(body))))
+		return rec.Code
+	}
+	if code := post(run.ID, `{"agent": "Mallory", "model": "good/model"}`); code != http.StatusBadRequest {
+		t.Errorf("unknown agent: expected 400, got %d", code)
+	}
+	if code := post(run.ID, `{"agent": "alice"}`); code != http.StatusBadRequest {
+		t.Errorf("missing model: expected 400, got %d", code)
+	}
+	if code := post(run.ID, `{"agent": "alice", "model": "good/model"}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	if got := <-received; got.Agent != "alice" || got.To != "good/model" {
+		t.Errorf("job received %+v", got)
+	}
+
+	close(release)
+	s.wg.Wait()
+	if code := post(run.ID, `{"agent": "Alice", "model": "good/model"}`); code != http.StatusConflict {
+		t.Errorf("finished run: expected 409, got %d", code)
+	}
+}
+
+func TestRemoveAgentReachesRunningJob(t *testing.T) {
+	received := make(chan debate.AgentRemoval, 2)
+	release := make(chan struct{})
+	s := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		job.Progress <- debate.TurnCompleted{Turn: debate.Turn{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "junk/model", Role: "debater"}}}
+		received <- <-job.Removals
+		received <- <-job.Removals
+		<-release
+		return successfulRun(context.Background(), job)
+	})
+	run, _ := s.start("api", validJob())
+	deadline := time.Now().Add(time.Second)
+	for got, _ := s.Get(run.ID); len(got.Models) == 0; got, _ = s.Get(run.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("turn not tracked")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	post := func(id, body string) int {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs/"+id+"/remove", bytes.NewReader([]byte(body))))
+		return rec.Code
+	}
+	if code := post(run.ID, `{"agent": "Mallory"}`); code != http.StatusBadRequest {
+		t.Errorf("unknown agent: expected 400, got %d", code)
+	}
+	if code := post(run.ID, `{}`); code != http.StatusBadRequest {
+		t.Errorf("missing agent: expected 400, got %d", code)
+	}
+	if code := post(run.ID, `{"agent": "alice", "reason": "off topic"}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	if code := post(run.ID, `{"agent": "Alice"}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	if got := <-received; got.Agent != "alice" || got.Reason != "off topic" {
+		t.Errorf("job received %+v", got)
+	}
+	if got := <-received; got.Reason != "removed by the operator" {
+		t.Errorf("expected the default reason, got %+v", got)
+	}
+
+	close(release)
+	s.wg.Wait()
+	if code := post(run.ID, `{"agent": "Alice"}`); code != http.StatusConflict {
+		t.Errorf("finished run: expected 409, got %d", code)
+	}
+}
+
+func TestJoinAgentReachesRunningJob(t *testing.T) {
+	received := make(chan debate.Agent, 1)
+	release := make(chan struct{})
+	s := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		job.Progress <- debate.TurnCompleted{Turn: debate.Turn{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m", Role: "debater"}}}
+		received <- <-job.Joins
+		<-release
+		return successfulRun(context.Background(), job)
+	})
+	run, _ := s.start("api", validJob())
+	deadline := time.Now().Add(time.Second)
+	for got, _ := s.Get(run.ID); len(got.Models) == 0; got, _ = s.Get(run.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("turn not tracked")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	post := func(body string) int {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs/"+run.ID+"/join", bytes.NewReader([]byte(body))))
+		return rec.Code
+	}
+	if code := post(`{"model": "m"}`); code != http.StatusBadRequest {
+		t.Errorf("missing name: expected 400, got %d", code)
+	}
+	if code := post(`{"expert": "astrologer"}`); code != http.StatusBadRequest {
+		t.Errorf("unknown expert: expected 400, got %d", code)
+	}
+	if code := post(`{"name": "alice"}`); code != http.StatusConflict {
+		t.Errorf("existing agent: expected 409, got %d", code)
+	}
+	if code := post(`{"expert": "legal", "model": "law/model"}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	if got := <-received; got.Name != "Lawyer" || got.Model != "law/model" || got.Persona != "a lawyer" || got.Frame == "" {
+		t.Errorf("job received %+v", got)
+	}
+
+	close(release)
+	s.wg.Wait()
+	if code := post(`{"name": "Carol"}`); code != http.StatusConflict {
+		t.Errorf("finished run: expected 409, got %d", code)
+	}
+}
+
+func TestRunTracksLiveProgress(t *testing.T) {
+	release := make(chan struct{})
+	s := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		job.Progress <- debate.PhaseChanged{Phase: debate.TenthManPhase}
+		job.Progress <- debate.RoundEnded{RoundSummary: debate.RoundSummary{Round: 3}}
+		<-release
+		return nil, errors.New("interrupted")
+	})
+	run, _ := s.start("api", validJob())
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		got, _ := s.Get(run.ID)
+		if got.Phase == "tenth_man" && got.Rounds == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("progress not tracked: phase %q, rounds %d", got.Phase, got.Rounds)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	s.wg.Wait()
+	if got, _ := s.Get(run.ID); got.Status != StatusFailed || got.Rounds != 3 {
+		t.Errorf("failed run should keep its last progress, got %+v", got)
+	}
+}
+
+func TestWorkersQueueRuns(t *testing.T) {
+	release := make(chan struct{})
+	s := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		<-release
+		return successfulRun(context.Background(), job)
+	})
+	s.SetWorkers(1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.startWorkers(ctx)
+
+	first, _ := s.start("api", validJob())
+	deadline := time.Now().Add(time.Second)
+	for got, _ := s.Get(first.ID); got.Status != StatusRunning; got, _ = s.Get(first.ID) {
+		if time.Now().After(deadline) {
+			t.Fatalf("first run not picked up, status %q", got.Status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	second, err := s.start("api", validJob())
+	if err != nil || second.Status != StatusQueued {
+		t.Fatalf("second run should be queued, got %q, %v", second.Status, err)
+	}
+
+	rec := httptest.NewRecorder()
+	body, _ := json.Marshal(validJob())
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs", bytes.NewReader(body)))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("full queue: expected 503, got %d", rec.Code)
+	}
+	if n := len(s.Runs()); n != 2 {
+		t.Errorf("rejected run should not be recorded, got %d runs", n)
+	}
+
+	close(release)
+	deadline = time.Now().Add(time.Second)
+	for got, _ := s.Get(second.ID); got.Status != StatusCompleted; got, _ = s.Get(second.ID) {
+		if time.Now().After(deadline) {
+			t.Fatalf("queued run not completed, status %q", got.Status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	s.wg.Wait()
+}
+
+func TestCancelRun(t *testing.T) {
+	st := store.NewMemoryStore()
+	started := make(chan struct{})
+	s := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		close(started)
+		<-job.Cancel
+		return &runner.Outcome{
+			Dir:    "/out/" + job.Name,
+			Result: &debate.Result{Transcript: &debate.Transcript{Rounds: 2}, Partial: true, Stopped: true},
+		}, nil
+	})
+	s.SetStore(st)
+	run, _ := s.start("api", validJob())
+	<-started
+
+	cancel := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs/"+id+"/cancel", nil))
+		return rec
+	}
+	rec := cancel(run.ID)
+	var got Run
+	json.NewDecoder(rec.Body).Decode(&got)
+	if rec.Code != http.StatusAccepted || got.Status != StatusCancelling {
+		t.Fatalf("expected 202 and a cancelling run, got %d %q", rec.Code, got.Status)
+	}
+	s.wg.Wait()
+	if got, _ = s.Get(run.ID); got.Status != StatusCancelled || got.Rounds != 2 || got.Dir == "" {
+		t.Errorf("expected the run cancelled with its partial artifacts, got %+v", got)
+	}
+	if pending, _ := st.Pending(context.Background()); len(pending) != 0 {
+		t.Errorf("cancelled run should not be pending, got %+v", pending)
+	}
+	if rec := cancel(run.ID); rec.Code != http.StatusConflict {
+		t.Errorf("cancelled run: expected 409, got %d", rec.Code)
+	}
+	if rec := cancel("run-99"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown run: expected 404, got %d", rec.Code)
+	}
+}
+
+func TestQueuedRunsStartByPriority(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+	s := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		mu.Lock()
+		order = append(order, job.Name)
+		mu.Unlock()
+		<-release
+		return successfulRun(context.Background(), job)
+	})
+	s.SetWorkers(1, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.startWorkers(ctx)
+	start := func(name string, priority int) Run {
+		job := validJob()
+		job.Name, job.Priority = name, priority
+		run, err := s.start("api", job)
+		if err != nil {
+			t.Fatalf("start %s: %v", name, err)
+		}
+		return run
+	}
+	post := func(id, action, body string) int {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs/"+id+"/"+action, strings.NewReader(body)))
+		return rec.Code
+	}
+
+	blocker := start("blocker", 0)
+	deadline := time.Now().Add(time.Second)
+	for got, _ := s.Get(blocker.ID); got.Status != StatusRunning; got, _ = s.Get(blocker.ID) {
+		if time.Now().After(deadline) {
+			t.Fatalf("first run not picked up, status %q", got.Status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	low := start("low", 0)
+	start("high", 5)
+	dropped := start("dropped", 9)
+	if code := post(low.ID, "priority", `{"priority": 7}`); code != http.StatusOK {
+		t.Errorf("reprioritize queued run: expected 200, got %d", code)
+	}
+	if code := post(blocker.ID, "priority", `{"priority": 7}`); code != http.StatusConflict {
+		t.Errorf("reprioritize running run: expected 409, got %d", code)
+	}
+	if code := post(low.ID, "priority", `{}`); code != http.StatusBadRequest {
+		t.Errorf("missing priority: expected 400, got %d", code)
+	}
+	if code := post(dropped.ID, "cancel", ""); code != http.StatusAccepted {
+		t.Fatalf("cancel queued run: expected 202, got %d", code)
+	}
+	if got, _ := s.Get(dropped.ID); got.Status != StatusCancelled || got.FinishedAt == nil {
+		t.Errorf("queued run should be cancelled at once, got %+v", got)
+	}
+
+	close(release)
+	deadline = time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(order)
+		mu.Unlock()
+		if n == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queued runs not started, ran %d", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	s.wg.Wait()
+	if got := strings.Join(order, ","); got != "blocker,low,high" {
+		t.Errorf("runs started in order %s, want blocker,low,high", got)
+	}
+}
+
+func TestRestartResumesInterruptedRun(t *testing.T) {
+	st := store.NewMemoryStore()
+	checkpointed := make(chan struct{})
+	first := New(func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		job.Checkpoint.Checkpoint(ctx, &debate.Transcript{Topic: job.Topic, Rounds: 1, Turns: []debate.Turn{{ID: 1, Round: 1}}})
+		close(checkpointed)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	first.SetStore(st)
+	ctx, shutdown := context.WithCancel(context.Background())
+	first.baseCtx = ctx
+	run, _ := first.start("api", validJob())
+	<-checkpointed
+	shutdown()
+	first.wg.Wait()
+
+	resumed := make(chan *debate.Transcript, 1)
+	second := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		resumed <- job.Resume
+		return successfulRun(context.Background(), job)
+	})
+	second.SetStore(st)
+	if err := second.recover(context.Background()); err != nil {
+		t.Fatalf("recover() error = %v", err)
+	}
+	got, ok := second.Get(run.ID)
+	if !ok || got.Status != StatusInterrupted || got.Rounds != 1 || got.Job.Topic != validJob().Topic {
+		t.Fatalf("expected %s to be restored as interrupted, got %+v", run.ID, got)
+	}
+	if next, _ := second.start("api", validJob()); next.ID == run.ID {
+		t.Errorf("new run reused the interrupted run's ID %s", next.ID)
+	}
+	if prior := <-resumed; prior != nil {
+		t.Errorf("new run got a checkpoint to resume: %+v", prior)
+	}
+
+	post := func() int {
+		rec := httptest.NewRecorder()
+		second.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/runs/"+run.ID+"/resume", nil))
+		return rec.Code
+	}
+	if code := post(); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	if prior := <-resumed; prior == nil || prior.Rounds != 1 {
+		t.Errorf("resumed run should start from its checkpoint, got %+v", prior)
+	}
+	second.wg.Wait()
+	if got, _ := second.Get(run.ID); got.Status != StatusCompleted {
+		t.Errorf("resumed run status %q, want completed", got.Status)
+	}
+	if code := post(); code != http.StatusConflict {
+		t.Errorf("completed run: expected 409, got %d", code)
+	}
+	if pending, _ := st.Pending(context.Background()); len(pending) != 0 {
+		t.Errorf("finished runs should not be pending, got %+v", pending)
+	}
+}
+
+func TestGetRunTranscript(t *testing.T) {
+	get := func(s *Server, id string) int {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/runs/"+id+"/transcript", nil))
+		return rec.Code
+	}
+
+	s := New(func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		if err := job.Checkpoint.Checkpoint(ctx, &debate.Transcript{Topic: job.Topic, Rounds: 1}); err != nil {
+			return nil, err
+		}
+		return successfulRun(ctx, job)
+	})
+	s.SetStore(store.NewMemoryStore())
+	run, _ := s.start("api", validJob())
+	s.wg.Wait()
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/runs/"+run.ID+"/transcript", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), validJob().Topic) {
+		t.Errorf("expected the checkpointed transcript, got %d %s", rec.Code, rec.Body.String())
+	}
+	if code := get(s, "run-99"); code != http.StatusNotFound {
+		t.Errorf("unknown run: expected 404, got %d", code)
+	}
+
+	noStore := New(successfulRun)
+	run, _ = noStore.start("api", validJob())
+	noStore.wg.Wait()
+	if code := get(noStore, run.ID); code != http.StatusNotImplemented {
+		t.Errorf("without a store: expected 501, got %d", code)
+	}
+}
+
+// historyStore is a memory store that also records verdicts and answers
+// history queries.
+type historyStore struct {
+	*store.MemoryStore
+	mu       sync.Mutex
+	verdicts map[string]debate.Verdict
+	filter   store.HistoryFilter
+	records  []store.DebateRecord // returned by History, newest first, if set
+}
+
+func (h *historyStore) RecordVerdict(_ context.Context, id string, result *debate.Result) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.verdicts[id] = result.Verdict()
+	return nil
+}
+
+func (h *historyStore) History(_ context.Context, f store.HistoryFilter) ([]store.DebateRecord, error) {
+	h.filter = f
+	if h.records == nil {
+		return []store.DebateRecord{{ID: "run-1", Topic: "Framework X", Verdict: debate.VerdictUpheld}}, nil
+	}
+	records := h.records
+	if f.Limit > 0 && len(records) > f.Limit {
+		records = records[:f.Limit]
+	}
+	return records, nil
+}
+
+func TestHistoryAndVerdicts(t *testing.T) {
+	hs := &historyStore{MemoryStore: store.NewMemoryStore(), verdicts: map[string]debate.Verdict{}}
+	s := New(successfulRun)
+	s.SetStore(hs)
+	run, _ := s.start("api", validJob())
+	s.wg.Wait()
+	if got := hs.verdicts[run.ID]; got != debate.VerdictNoConsensus {
+		t.Errorf("recorded verdict = %q", got)
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/history?topic=framework&since=2026-01-01T00:00:00Z&limit=5", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"verdict":"upheld"`) {
+		t.Errorf("unexpected history response %d %s", rec.Code, rec.Body.String())
+	}
+	if hs.filter.Topic != "framework" || hs.filter.Limit != 5 || hs.filter.Since.Year() != 2026 {
+		t.Errorf("unexpected filter %+v", hs.filter)
+	}
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/history/stats?topic=framework", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"verdicts":{"upheld":1}`) || !strings.Contains(rec.Body.String(), `"survival_rate":1`) {
+		t.Errorf("unexpected history stats response %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/history?limit=-1", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("negative limit: expected 400, got %d", rec.Code)
+	}
+
+	plain := New(successfulRun)
+	plain.SetStore(store.NewMemoryStore())
+	rec = httptest.NewRecorder()
+	plain.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/history", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("store without history: expected 501, got %d", rec.Code)
+	}
+}
+
+func TestHistoryLimitAppliesAfterTenantFilter(t *testing.T) {
+	hs := &historyStore{MemoryStore: store.NewMemoryStore(), verdicts: map[string]debate.Verdict{}}
+	s := New(successfulRun)
+	s.SetStore(hs)
+	s.SetTenants([]Tenant{{Name: "platform", Token: "tok-platform"}, {Name: "data", Token: "tok-data"}})
+	create := func(token string) string {
+		body, _ := json.Marshal(validJob())
+		req := httptest.NewRequest(http.MethodPost, "/runs", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		var run Run
+		json.Unmarshal(rec.Body.Bytes(), &run)
+		return run.ID
+	}
+	older, newer := create("tok-platform"), create("tok-data")
+	s.wg.Wait()
+	hs.records = []store.DebateRecord{{ID: newer, Verdict: debate.VerdictUpheld}, {ID: older, Verdict: debate.VerdictRevised}}
+
+	req := httptest.NewRequest(http.MethodGet, "/history?limit=1", nil)
+	req.Header.Set("Authorization", "Bearer tok-platform")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	var records []store.DebateRecord
+	if err := json.Unmarshal(rec.Body.Bytes(), &records); err != nil || len(records) != 1 || records[0].ID != older {
+		t.Errorf("expected the tenant's own run %s, got %d %s", older, rec.Code, rec.Body.String())
+	}
+}
+
+func TestTenantsAuthenticateAndIsolateRuns(t *testing.T) {
+	s := New(func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		outcome, err := successfulRun(ctx, job)
+		outcome.Tokens = 600
+		return outcome, err
+	})
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.SetTenants([]Tenant{
+		{Name: "platform", Token: "tok-platform", TokenQuota: 1000},
+		{Name: "data", Token: "tok-data", RPM: 2},
+	})
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+	jobBody, _ := json.Marshal(validJob())
+
+	if rec := do(http.MethodGet, "/runs", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("missing token: expected 401, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/runs", "wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unknown token: expected 401, got %d", rec.Code)
+	}
+
+	rec := do(http.MethodPost, "/runs", "tok-platform", string(jobBody))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("create run: expected 202, got %d %s", rec.Code, rec.Body.String())
+	}
+	var run Run
+	json.Unmarshal(rec.Body.Bytes(), &run)
+	s.wg.Wait()
+	if run.Tenant != "platform" {
+		t.Errorf("run tenant = %q", run.Tenant)
+	}
+
+	if rec := do(http.MethodGet, "/runs/"+run.ID, "tok-data", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("other tenant's run: expected 404, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/runs", "tok-data", ""); strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("other tenant should see no runs, got %s", rec.Body.String())
+	}
+	if rec := do(http.MethodGet, "/runs/"+run.ID, "tok-platform", ""); rec.Code != http.StatusOK {
+		t.Errorf("own run: expected 200, got %d", rec.Code)
+	}
+
+	// 600 of 1000 tokens used: one more run is allowed, then the quota is spent.
+	do(http.MethodPost, "/runs", "tok-platform", string(jobBody))
+	s.wg.Wait()
+	if rec := do(http.MethodPost, "/runs", "tok-platform", string(jobBody)); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("over quota: expected 429, got %d", rec.Code)
+	}
+	var usage Usage
+	json.Unmarshal(do(http.MethodGet, "/usage", "tok-platform", "").Body.Bytes(), &usage)
+	if usage.Tokens != 1200 || usage.Month != "2026-03" {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	now = now.AddDate(0, 1, 0)
+	if rec := do(http.MethodPost, "/runs", "tok-platform", string(jobBody)); rec.Code != http.StatusAccepted {
+		t.Errorf("quota should reset next month, got %d", rec.Code)
+	}
+	s.wg.Wait()
+
+	// The data tenant may make 2 requests per minute; its allowance is full
+	// again after the month skipped above.
+	do(http.MethodGet, "/runs", "tok-data", "")
+	do(http.MethodGet, "/runs", "tok-data", "")
+	if rec := do(http.MethodGet, "/runs", "tok-data", ""); rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "30" {
+		t.Errorf("rate limited: expected 429 with Retry-After 30, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	now = now.Add(30 * time.Second)
+	if rec := do(http.MethodGet, "/runs", "tok-data", ""); rec.Code != http.StatusOK {
+		t.Errorf("after waiting: expected 200, got %d", rec.Code)
+	}
+}
+
+func TestStreamRunTurns(t *testing.T) {
+	release := make(chan struct{})
+	s := New(func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		job.Progress <- debate.TurnCompleted{Turn: debate.Turn{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m1"}, Content: "first"}}
+		<-release
+		job.Progress <- debate.TurnCompleted{Turn: debate.Turn{ID: 2, Round: 1, Agent: debate.Agent{Name: "Bob", Model: "m1"}, Content: "second"}}
+		return successfulRun(ctx, job)
+	})
+	run, _ := s.start("api", validJob())
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/runs/" + run.ID + "/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	var events []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			events = append(events, name)
+			if len(events) == 1 {
+				close(release) // the first turn arrived while the run was live
+			}
+		}
+		if strings.HasPrefix(line, "data: ") && strings.Contains(line, `"status":"completed"`) {
+			break
+		}
+	}
+	if strings.Join(events, ",") != "turn,turn,done" {
+		t.Errorf("events = %v", events)
+	}
+	s.wg.Wait()
+	if got, _ := s.Get(run.ID); got.Models["m1"] != 2 {
+		t.Errorf("models = %v, want 2 turns for m1", got.Models)
+	}
+}
+
+func TestDashboardIsServed(t *testing.T) {
+	s := New(successfulRun)
+	s.SetTenants([]Tenant{{Name: "a", Token: "secret"}})
+	for path, want := range map[string]string{"/": "<title>Tenth Man Rule</title>", "/ui/app.js": "/runs/"} {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("GET %s: %d, missing %q", path, rec.Code, want)
+		}
+	}
+}
+
+func TestHealthAndReadiness(t *testing.T) {
+	s := New(successfulRun)
+	s.SetStore(store.NewFileStore(t.TempDir()))
+	llmErr := errors.New("openrouter: unexpected status 503")
+	s.SetHealthChecks(health.Check{Name: "openrouter", Run: func(context.Context) error { return llmErr }})
+	s.SetTenants([]Tenant{{Name: "team-a", Token: "secret-a"}})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+	if rec := get("/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("/healthz: expected 200 without a token, got %d", rec.Code)
+	}
+
+	rec := get("/readyz")
+	var body struct {
+		Status string          `json:"status"`
+		Checks []health.Result `json:"checks"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusServiceUnavailable || body.Status != "unavailable" {
+		t.Fatalf("/readyz: expected 503 unavailable, got %d %q", rec.Code, body.Status)
+	}
+	if len(body.Checks) != 2 || body.Checks[0].Name != "store" || !body.Checks[0].OK || body.Checks[1].Error != llmErr.Error() {
+		t.Errorf("unexpected checks %+v", body.Checks)
+	}
+
+	llmErr = nil
+	if rec := get("/readyz"); rec.Code != http.StatusOK {
+		t.Errorf("/readyz: expected 200 once every check passes, got %d", rec.Code)
+	}
+}
+
+func TestProfiling(t *testing.T) {
+	s := New(successfulRun)
+	s.SetTenants([]Tenant{{Name: "team-a", Token: "secret-a"}})
+	get := func(path, token string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := get("/debug/pprof/heap", "secret-a"); code != http.StatusNotFound {
+		t.Errorf("profiling off: expected 404, got %d", code)
+	}
+
+	s.SetProfiling(true)
+	for path, want := range map[string]int{
+		"/debug/pprof/":                  http.StatusOK,
+		"/debug/pprof/goroutine?debug=1": http.StatusOK,
+		"/debug/pprof/symbol":            http.StatusOK,
+		"/debug/pprof/cmdline":           http.StatusNotFound,
+		"/debug/pprof/no-such-profile":   http.StatusNotFound,
+	} {
+		if code := get(path, "secret-a"); code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, code)
+		}
+	}
+	if code := get("/debug/pprof/heap", ""); code != http.StatusUnauthorized {
+		t.Errorf("no token: expected 401, got %d", code)
+	}
+}
+
+func TestScheduleFiresAndStopsOnCancel(t *testing.T) {
+	cfg := writeConfig(t, `
+schedules:
+  - name: weekly
+    cron: "0 9 * * 1"
+    topic: "Should we still use framework X?"
+    agents: 3
+    min_rounds: 1
+    max_rounds: 2
+`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fired := make(chan struct{}, 3)
+	s := New(func(_ context.Context, job runner.Job) (*runner.Outcome, error) {
+		fired <- struct{}{}
+		return successfulRun(ctx, job)
+	})
+	ticks := make(chan time.Time)
+	s.after = func(time.Duration) <-chan time.Time { return ticks }
+
+	done := make(chan struct{})
+	go func() {
+		s.runSchedule(ctx, cfg.Schedules[0])
+		close(done)
+	}()
+	for range 2 {
+		ticks <- time.Now()
+		select {
+		case <-fired:
+		case <-time.After(time.Second):
+			t.Fatal("schedule did not fire")
+		}
+		s.wg.Wait()
+	}
+	cancel()
+	<-done
+
+	if runs := s.Runs(); len(runs) != 2 {
+		t.Fatalf("expected 2 scheduled runs, got %d", len(runs))
+	}
+	for _, r := range s.Runs() {
+		if r.Source != "schedule:weekly" {
+			t.Errorf("unexpected source %q", r.Source)
+		}
+	}
+}
+
+func writeConfig(t *testing.T, content string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "serve.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	return cfg
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg := writeConfig(t, `
+notify:
+  webhook: https://hooks.example.com/digest
+schedules:
+  - name: weekly
+    cron: "@weekly"
+    topic: "Framework X"
+`)
+	if cfg.Notify.Webhook != "https://hooks.example.com/digest" {
+		t.Errorf("webhook = %q", cfg.Notify.Webhook)
+	}
+	if len(cfg.Schedules) != 1 || cfg.Schedules[0].Job.Topic != "Framework X" || cfg.Schedules[0].schedule == nil {
+		t.Errorf("unexpected schedules: %+v", cfg.Schedules)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"missing name":   "schedules:\n  - cron: \"@daily\"\n    topic: t\n",
+		"missing topic":  "schedules:\n  - name: a\n    cron: \"@daily\"\n",
+		"bad cron":       "schedules:\n  - name: a\n    cron: \"61 * * * *\"\n    topic: t\n",
+		"duplicate name": "schedules:\n  - name: a\n    cron: \"@daily\"\n    topic: t\n  - name: a\n    cron: \"@daily\"\n    topic: t\n",
+		"empty token":    "tenants:\n  - name: a\n    token: ${TENTHMAN_UNSET_TOKEN}\n",
+		"shared token":   "tenants:\n  - name: a\n    token: x\n  - name: b\n    token: x\n",
+	} {
+		path := filepath.Join(t.TempDir(), "serve.yaml")
+		os.WriteFile(path, []byte(content), 0o644)
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// grpcClient returns a client of s's gRPC API over an in-memory connection.
+func grpcClient(t *testing.T, s *Server) tenthmanv1.DebateServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	g := s.GRPCServer()
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return tenthmanv1.NewDebateServiceClient(conn)
+}
+
+func TestGRPCRunsAndStreams(t *testing.T) {
+	release := make(chan struct{})
+	s := New(func(ctx context.Context, job runner.Job) (*runner.Outcome, error) {
+		job.Progress <- debate.PhaseChanged{Phase: debate.FreeDebate}
+		job.Progress <- debate.TurnCompleted{Turn: debate.Turn{ID: 1, Round: 1, Agent: debate.Agent{Name: "Alice", Model: "m1"}, Content: "first"}}
+		<-release
+		job.Progress <- debate.TurnCompleted{Turn: debate.Turn{ID: 2, Round: 1, Agent: debate.Agent{Name: "Bob", Model: "m1"}, Content: "second", InReplyTo: 1}}
+		job.Progress <- debate.RoundEnded{RoundSummary: debate.RoundSummary{Round: 1}}
+		job.Progress <- debate.EvidenceGathered{}
+		return successfulRun(ctx, job)
+	})
+	client := grpcClient(t, s)
+	ctx := context.Background()
+
+	if _, err := client.StartRun(ctx, &tenthmanv1.StartRunRequest{Job: &tenthmanv1.Job{}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid job: expected InvalidArgument, got %v", err)
+	}
+	job := validJob()
+	run, err := client.StartRun(ctx, &tenthmanv1.StartRunRequest{Job: &tenthmanv1.Job{Topic: job.Topic, Name: job.Name, Agents: 3, MinRounds: 1, MaxRounds: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.GetId() == "" || run.GetStatus() != tenthmanv1.Status_STATUS_RUNNING || run.GetJob().GetName() != job.Name {
+		t.Errorf("unexpected started run: %v", run)
+	}
+
+	stream, err := client.StreamEvents(ctx, &tenthmanv1.StreamRequest{Id: run.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	var done *tenthmanv1.Run
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch e := ev.GetEvent().(type) {
+		case *tenthmanv1.Event_PhaseChanged:
+			kinds = append(kinds, "phase")
+		case *tenthmanv1.Event_TurnCompleted:
+			kinds = append(kinds, "turn")
+			if e.TurnCompleted.GetId() == 1 {
+				close(release) // the first turn arrived while the run was live
+			}
+		case *tenthmanv1.Event_RoundEnded:
+			kinds = append(kinds, "round")
+		case *tenthmanv1.Event_Done:
+			kinds = append(kinds, "done")
+			done = e.Done
+		}
+	}
+	if strings.Join(kinds, ",") != "phase,turn,turn,round,done" {
+		t.Errorf("events = %v", kinds)
+	}
+	if done.GetStatus() != tenthmanv1.Status_STATUS_COMPLETED || done.GetRounds() != 5 || !done.GetConsensus().GetDetected() || done.GetModels()["m1"] != 2 || done.GetFinishedAt() == nil {
+		t.Errorf("unexpected final run: %v", done)
+	}
+	s.wg.Wait()
+
+	turns, err := client.StreamTurns(ctx, &tenthmanv1.StreamRequest{Id: run.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replies []int32
+	for {
+		turn, err := turns.Recv()
+		if err != nil {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			break
+		}
+		replies = append(replies, turn.GetInReplyTo())
+	}
+	if !slices.Equal(replies, []int32{0, 1}) {
+		t.Errorf("expected both turns replayed after the run finished, got replies %v", replies)
+	}
+
+	got, err := client.GetRun(ctx, &tenthmanv1.GetRunRequest{Id: run.GetId()})
+	if err != nil || got.GetDir() != "/out/framework-x" {
+		t.Errorf("GetRun = %v, %v", got, err)
+	}
+	if _, err := client.GetRun(ctx, &tenthmanv1.GetRunRequest{Id: "run-99"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown run: expected NotFound, got %v", err)
+	}
+	if list, err := client.ListRuns(ctx, &tenthmanv1.ListRunsRequest{}); err != nil || len(list.GetRuns()) != 1 {
+		t.Errorf("ListRuns = %v, %v", list, err)
+	}
+	if _, err := client.InjectEvent(ctx, &tenthmanv1.InjectEventRequest{Id: run.GetId(), Content: "too late"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("finished run: expected FailedPrecondition, got %v", err)
+	}
+	if _, err := client.InjectEvent(ctx, &tenthmanv1.InjectEventRequest{Id: run.GetId(), Content: " "}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty content: expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestGRPCTenants(t *testing.T) {
+	s := New(successfulRun)
+	s.SetTenants([]Tenant{{Name: "platform", Token: "tok-platform"}, {Name: "data", Token: "tok-data"}})
+	client := grpcClient(t, s)
+	as := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+	job := &tenthmanv1.Job{Topic: "Remote work", Agents: 3, MinRounds: 1, MaxRounds: 2}
+
+	if _, err := client.ListRuns(context.Background(), &tenthmanv1.ListRunsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("missing token: expected Unauthenticated, got %v", err)
+	}
+	if _, err := client.StartRun(as("wrong"), &tenthmanv1.StartRunRequest{Job: job}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("unknown token: expected Unauthenticated, got %v", err)
+	}
+	run, err := client.StartRun(as("tok-platform"), &tenthmanv1.StartRunRequest{Job: job})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.wg.Wait()
+	if run.GetTenant() != "platform" {
+		t.Errorf("run tenant = %q", run.GetTenant())
+	}
+	if _, err := client.GetRun(as("tok-data"), &tenthmanv1.GetRunRequest{Id: run.GetId()}); status.Code(err) != codes.NotFound {
+		t.Errorf("other tenant's run: expected NotFound, got %v", err)
+	}
+	stream, err := client.StreamTurns(as("tok-data"), &tenthmanv1.StreamRequest{Id: run.GetId()})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("streaming another tenant's run: expected NotFound, got %v", err)
+	}
+	if list, err := client.ListRuns(as("tok-data"), &tenthmanv1.ListRunsRequest{}); err != nil || len(list.GetRuns()) != 0 {
+		t.Errorf("other tenant should see no runs, got %v, %v", list, err)
+	}
+	if _, err := client.GetRun(as("tok-platform"), &tenthmanv1.GetRunRequest{Id: run.GetId()}); err != nil {
+		t.Errorf("own run: %v", err)
+	}
+}
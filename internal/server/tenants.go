@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tenant is a team allowed to use the API with its own token. When any
+// tenant is configured, every API request must carry one of their tokens as
+// "Authorization: Bearer <token>", and each tenant only sees its own runs.
+type Tenant struct {
+	Name       string `yaml:"name"`
+	Token      string `yaml:"token"`       // ${VAR} is expanded by LoadConfig
+	RPM        int    `yaml:"rpm"`         // API requests per minute; 0 is unlimited
+	TokenQuota int    `yaml:"token_quota"` // LLM tokens per calendar month (UTC); 0 is unlimited
+}
+
+// Usage is a tenant's LLM token consumption in the current month.
+type Usage struct {
+	Tenant     string `json:"tenant"`
+	Month      string `json:"month"` // YYYY-MM
+	Tokens     int    `json:"tokens"`
+	TokenQuota int    `json:"token_quota,omitempty"`
+}
+
+// tenantState tracks a tenant's request rate and token usage. Usage is kept
+// in memory and starts over when the server restarts.
+type tenantState struct {
+	Tenant
+
+	mu        sync.Mutex
+	allowance float64 // requests available now, refilled at RPM per minute
+	refilled  time.Time
+	month     string
+	used      int
+}
+
+// validateTenants checks that every tenant has a unique name and token.
+func validateTenants(tenants []Tenant) error {
+	names := make(map[string]bool)
+	tokens := make(map[string]bool)
+	for i, t := range tenants {
+		if t.Name == "" {
+			return fmt.Errorf("server: tenant %d: name is required", i+1)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("server: tenant %q defined twice", t.Name)
+		}
+		names[t.Name] = true
+		if t.Token == "" {
+			return fmt.Errorf("server: tenant %q: token is required", t.Name)
+		}
+		if tokens[t.Token] {
+			return fmt.Errorf("server: tenant %q: token is shared with another tenant", t.Name)
+		}
+		tokens[t.Token] = true
+		if t.RPM < 0 || t.TokenQuota < 0 {
+			return fmt.Errorf("server: tenant %q: rpm and token_quota must be >= 0", t.Name)
+		}
+	}
+	return nil
+}
+
+// SetTenants enables token authentication with per-tenant limits. Without
+// tenants the API is open.
+func (s *Server) SetTenants(tenants []Tenant) {
+	s.tenants = make([]*tenantState, len(tenants))
+	for i, t := range tenants {
+		s.tenants[i] = &tenantState{Tenant: t, allowance: float64(t.RPM), refilled: s.now()}
+	}
+}
+
+type tenantKey struct{}
+
+// tenantFrom returns the tenant that made r, or nil if the API is open.
+func tenantFrom(r *http.Request) *tenantState {
+	return tenantIn(r.Context())
+}
+
+// tenantIn returns the tenant authenticated for ctx, or nil if the API is
+// open.
+func tenantIn(ctx context.Context) *tenantState {
+	t, _ := ctx.Value(tenantKey{}).(*tenantState)
+	return t
+}
+
+// tenantFor returns the tenant whose token is token, or nil if there is
+// none.
+func (s *Server) tenantFor(token string) *tenantState {
+	var tenant *tenantState
+	for _, t := range s.tenants {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t.Token)) == 1 {
+			tenant = t
+		}
+	}
+	return tenant
+}
+
+// tenantName is the name runs are recorded under for t.
+func tenantName(t *tenantState) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+// authenticate rejects requests without a known token and requests over
+// their tenant's rate limit.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.tenants) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var tenant *tenantState
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			tenant = s.tenantFor(token)
+		}
+		if tenant == nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "a valid API token is required")
+			return
+		}
+		if wait := tenant.take(s.now()); wait > 0 {
+			w.Header().Set("Retry-After", fmt.Sprint(int(math.Ceil(wait.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantKey{}, tenant)))
+	})
+}
+
+// take spends one request from the tenant's allowance, returning how long to
+// wait if none is left.
+func (t *tenantState) take(now time.Time) time.Duration {
+	if t.RPM == 0 {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	perSecond := float64(t.RPM) / 60
+	t.allowance = min(float64(t.RPM), t.allowance+now.Sub(t.refilled).Seconds()*perSecond)
+	t.refilled = now
+	if t.allowance < 1 {
+		return time.Duration((1 - t.allowance) / perSecond * float64(time.Second))
+	}
+	t.allowance--
+	return 0
+}
+
+// usage returns the tenant's consumption for the month of now.
+func (t *tenantState) usage(now time.Time) Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover(now)
+	return Usage{Tenant: t.Name, Month: t.month, Tokens: t.used, TokenQuota: t.TokenQuota}
+}
+
+// overQuota reports whether the tenant has used up this month's tokens.
+func (t *tenantState) overQuota(now time.Time) bool {
+	u := t.usage(now)
+	return u.TokenQuota > 0 && u.Tokens >= u.TokenQuota
+}
+
+// charge adds tokens to the tenant's usage for the month of now.
+func (t *tenantState) charge(now time.Time, tokens int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover(now)
+	t.used += tokens
+}
+
+func (t *tenantState) rollover(now time.Time) {
+	if month := now.UTC().Format("2006-01"); month != t.month {
+		t.month = month
+		t.used = 0
+	}
+}
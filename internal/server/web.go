@@ -0,0 +1,20 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webFiles is the dashboard: static pages that use the JSON API.
+//
+//go:embed web
+var webFiles embed.FS
+
+func dashboard() http.Handler {
+	sub, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServerFS(sub)
+}
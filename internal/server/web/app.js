@@ -0,0 +1,165 @@
+"use strict";
+
+// Dashboard for tenthman serve: polls /runs and streams the selected run's
+// turns from /runs/{id}/stream. With tenants configured the API token is kept
+// in localStorage and sent as a bearer token.
+
+const state = { runs: [], selected: null, stream: null };
+
+function token() {
+  return localStorage.getItem("tenthman-token") || "";
+}
+
+async function api(path, options = {}) {
+  const headers = options.headers || {};
+  if (token()) headers["Authorization"] = "Bearer " + token();
+  const resp = await fetch(path, { ...options, headers });
+  if (resp.status === 401) {
+    document.getElementById("token-form").hidden = false;
+    throw new Error("unauthorized");
+  }
+  return resp;
+}
+
+async function refresh() {
+  try {
+    const resp = await api("/runs");
+    if (!resp.ok) return;
+    state.runs = await resp.json();
+  } catch (e) {
+    return;
+  }
+  renderActive();
+  renderPast();
+  renderTrend();
+  renderModels();
+}
+
+function el(tag, attrs = {}, ...children) {
+  const node = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs)) node.setAttribute(k, v);
+  for (const c of children) node.append(c);
+  return node;
+}
+
+function topic(run) {
+  return run.job.topic || run.job.name || run.id;
+}
+
+function renderActive() {
+  const list = document.getElementById("active");
+  const active = state.runs.filter(r => ["running", "queued", "interrupted", "cancelling"].includes(r.status));
+  list.replaceChildren(...active.map(run => {
+    const progress = run.status === "running" ? `round ${run.rounds || 0}` : run.status;
+    const li = el("li", {}, topic(run), " ", el("span", { class: "muted" }, progress));
+    if (run.phase) li.append(el("span", { class: "phase " + run.phase }, run.phase.replace("_", " ")));
+    li.onclick = () => watch(run);
+    return li;
+  }));
+  document.getElementById("no-active").hidden = active.length > 0;
+}
+
+function renderPast() {
+  const body = document.getElementById("past");
+  const past = state.runs.filter(r => ["completed", "failed", "cancelled"].includes(r.status)).reverse();
+  body.replaceChildren(...past.map(run => {
+    const score = run.consensus ? `${run.consensus.agreement_score}/10` : "";
+    const finished = run.finished_at ? new Date(run.finished_at).toLocaleString() : "";
+    const tr = el("tr", {}, el("td", {}, run.id), el("td", {}, topic(run)), el("td", {}, run.status),
+      el("td", {}, String(run.rounds || 0)), el("td", {}, score), el("td", {}, finished));
+    tr.onclick = () => watch(run);
+    return tr;
+  }));
+}
+
+function renderTrend() {
+  const svg = document.getElementById("trend");
+  const points = state.runs.filter(r => r.status === "completed" && r.consensus)
+    .map(r => r.consensus.agreement_score);
+  const w = 400, h = 160, pad = 20;
+  const x = i => pad + (points.length > 1 ? i * (w - 2 * pad) / (points.length - 1) : (w - 2 * pad) / 2);
+  const y = v => h - pad - v * (h - 2 * pad) / 10;
+  let markup = `<line x1="${pad}" y1="${y(7)}" x2="${w - pad}" y2="${y(7)}" stroke="#c9b3e0" stroke-dasharray="4"/>`;
+  markup += `<text x="${w - pad}" y="${y(7) - 4}" font-size="10" text-anchor="end" fill="#888">threshold 7</text>`;
+  if (points.length > 0) {
+    markup += `<polyline fill="none" stroke="#6b3fa0" stroke-width="2" points="${points.map((v, i) => `${x(i)},${y(v)}`).join(" ")}"/>`;
+    markup += points.map((v, i) => `<circle cx="${x(i)}" cy="${y(v)}" r="3" fill="#6b3fa0"/>`).join("");
+  }
+  svg.innerHTML = markup;
+}
+
+function renderModels() {
+  const totals = {};
+  for (const run of state.runs) {
+    for (const [model, turns] of Object.entries(run.models || {})) {
+      totals[model] = (totals[model] || 0) + turns;
+    }
+  }
+  const rows = Object.entries(totals).sort((a, b) => b[1] - a[1]);
+  const max = rows.length ? rows[0][1] : 1;
+  document.getElementById("models").replaceChildren(...rows.map(([model, turns]) => {
+    const bar = el("div");
+    bar.style.width = `${40 * turns / max}%`;
+    return el("div", { class: "bar" }, el("span", { title: model }, model), bar, el("span", {}, String(turns)));
+  }));
+}
+
+// watch shows a run's transcript, following it live while it runs.
+async function watch(run) {
+  if (state.stream) state.stream.abort();
+  state.stream = new AbortController();
+  document.getElementById("live").hidden = false;
+  document.getElementById("live-title").textContent = `${run.id}: ${topic(run)}`;
+  const list = document.getElementById("turns");
+  list.replaceChildren();
+
+  let resp;
+  try {
+    resp = await api(`/runs/${run.id}/stream`, { signal: state.stream.signal });
+  } catch (e) {
+    return;
+  }
+  const reader = resp.body.getReader();
+  const decoder = new TextDecoder();
+  let buffer = "";
+  for (;;) {
+    let chunk;
+    try {
+      chunk = await reader.read();
+    } catch (e) {
+      return;
+    }
+    if (chunk.done) return;
+    buffer += decoder.decode(chunk.value, { stream: true });
+    let end;
+    while ((end = buffer.indexOf("\n\n")) >= 0) {
+      handleEvent(buffer.slice(0, end), list);
+      buffer = buffer.slice(end + 2);
+    }
+  }
+}
+
+function handleEvent(raw, list) {
+  let event = "message", data = "";
+  for (const line of raw.split("\n")) {
+    if (line.startsWith("event: ")) event = line.slice(7);
+    if (line.startsWith("data: ")) data += line.slice(6);
+  }
+  if (event === "turn") {
+    const turn = JSON.parse(data);
+    list.append(el("li", { class: turn.Agent.Role },
+      el("span", { class: "agent" }, `[Round ${turn.Round}] ${turn.Agent.Name}`), ": ", turn.Content));
+  } else if (event === "done") {
+    refresh();
+  }
+}
+
+document.getElementById("token-form").onsubmit = e => {
+  e.preventDefault();
+  localStorage.setItem("tenthman-token", document.getElementById("token").value);
+  document.getElementById("token-form").hidden = true;
+  refresh();
+};
+
+refresh();
+setInterval(refresh, 5000);
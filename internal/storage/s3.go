@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// s3Config describes an S3-compatible bucket destination.
+type s3Config struct {
+	Scheme       string // scheme used when reporting locations, "s3" or "gs"
+	Endpoint     string // e.g. https://s3.us-east-1.amazonaws.com
+	Region       string
+	Bucket       string
+	Prefix       string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// s3Sink uploads with path-style PUT requests signed with AWS Signature V4.
+type s3Sink struct {
+	cfg        s3Config
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func newS3Sink(cfg s3Config) (*s3Sink, error) {
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("storage: %s://%s: missing access key or secret", cfg.Scheme, cfg.Bucket)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	return &s3Sink{cfg: cfg, httpClient: &http.Client{Timeout: 5 * time.Minute}, now: time.Now}, nil
+}
+
+// Upload implements Sink. Files are stored under prefix/<run-dir-name>/.
+func (s *s3Sink) Upload(ctx context.Context, dir string) (string, error) {
+	base := path.Join(s.cfg.Prefix, filepath.Base(filepath.Clean(dir)))
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		return s.put(ctx, path.Join(base, filepath.ToSlash(rel)), p)
+	})
+	if err != nil {
+		return "", fmt.Errorf("storage: %w", err)
+	}
+	return fmt.Sprintf("%s://%s/%s/", s.cfg.Scheme, s.cfg.Bucket, base), nil
+}
+
+func (s *s3Sink) put(ctx context.Context, key, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	uri := "/" + s.cfg.Bucket + "/" + key
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.cfg.Endpoint+escapePath(uri), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		req.Header.Set("Content-Type", ct)
+	}
+	s.sign(req, uri, data)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("uploading %s: status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds AWS Signature V4 headers to req for the unescaped path uri.
+func (s *s3Sink) sign(req *http.Request, uri string, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	names := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if s.cfg.SessionToken != "" {
+		headers["x-amz-security-token"] = s.cfg.SessionToken
+		names = append(names, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, n := range names {
+		canonicalHeaders.WriteString(n + ":" + headers[n] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(uri),
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+// escapePath percent-encodes every byte of p except unreserved characters
+// and '/', as Signature V4 requires for S3 object keys.
+func escapePath(p string) string {
+	var sb strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
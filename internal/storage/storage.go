@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Sink stores completed run directories somewhere durable.
+type Sink interface {
+	// Upload copies every file in dir and returns the location it was stored at.
+	Upload(ctx context.Context, dir string) (string, error)
+}
+
+// NewSink returns the sink for a destination URL:
+//
+//   - s3://bucket/prefix uploads to Amazon S3 or, with AWS_ENDPOINT_URL set,
+//     any S3-compatible store. Credentials come from AWS_ACCESS_KEY_ID,
+//     AWS_SECRET_ACCESS_KEY and optionally AWS_SESSION_TOKEN; the region
+//     from AWS_REGION (default us-east-1).
+//   - gs://bucket/prefix uploads to Google Cloud Storage through its
+//     S3-compatible XML API, using HMAC keys from GCS_HMAC_ACCESS_ID and
+//     GCS_HMAC_SECRET.
+func NewSink(dest string) (Sink, error) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, fmt.Errorf("storage: %w", err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("storage: %q has no bucket", dest)
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case "s3":
+		endpoint := os.Getenv("AWS_ENDPOINT_URL")
+		region := os.Getenv("AWS_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		if endpoint == "" {
+			endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+		}
+		return newS3Sink(s3Config{
+			Scheme:       "s3",
+			Endpoint:     endpoint,
+			Region:       region,
+			Bucket:       u.Host,
+			Prefix:       prefix,
+			AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		})
+	case "gs":
+		return newS3Sink(s3Config{
+			Scheme:    "gs",
+			Endpoint:  "https://storage.googleapis.com",
+			Region:    "auto",
+			Bucket:    u.Host,
+			Prefix:    prefix,
+			AccessKey: os.Getenv("GCS_HMAC_ACCESS_ID"),
+			SecretKey: os.Getenv("GCS_HMAC_SECRET"),
+		})
+	default:
+		return nil, fmt.Errorf("storage: unsupported destination %q (want s3:// or gs://)", dest)
+	}
+}
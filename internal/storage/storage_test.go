@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewSinkErrors(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	tests := []struct {
+		dest string
+		want string
+	}{
+		{"ftp://bucket/prefix", "unsupported destination"},
+		{"s3:///prefix", "no bucket"},
+		{"s3://bucket/prefix", "missing access key"},
+	}
+	for _, tt := range tests {
+		_, err := NewSink(tt.dest)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("NewSink(%q) error = %v, want %q", tt.dest, err, tt.want)
+		}
+	}
+}
+
+func TestNewSinkS3(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+
+	sink, err := NewSink("s3://runs/ci/nightly/")
+	if err != nil {
+		t.Fatalf("NewSink: %v", err)
+	}
+	cfg := sink.(*s3Sink).cfg
+	if cfg.Bucket != "runs" || cfg.Prefix != "ci/nightly" || cfg.Endpoint != "https://s3.eu-west-1.amazonaws.com" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestS3SinkUpload(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[string]string)
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got[r.URL.Path] = string(body)
+		auth = r.Header.Get("Authorization")
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "my-topic-20260101-120000")
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "report.md"), []byte("# Report"), 0o644)
+	os.WriteFile(filepath.Join(dir, "transcript.json"), []byte("{}"), 0o644)
+
+	sink, err := newS3Sink(s3Config{Scheme: "s3", Endpoint: srv.URL, Region: "us-east-1", Bucket: "runs", Prefix: "ci", AccessKey: "AKID", SecretKey: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.now = func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	loc, err := sink.Upload(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if loc != "s3://runs/ci/my-topic-20260101-120000/" {
+		t.Errorf("location = %q", loc)
+	}
+	if got["/runs/ci/my-topic-20260101-120000/report.md"] != "# Report" {
+		t.Errorf("report.md not uploaded: %v", got)
+	}
+	if _, ok := got["/runs/ci/my-topic-20260101-120000/transcript.json"]; !ok {
+		t.Errorf("transcript.json not uploaded: %v", got)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20260101/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestS3SinkUploadError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "report.md"), []byte("x"), 0o644)
+
+	sink, _ := newS3Sink(s3Config{Scheme: "gs", Endpoint: srv.URL, Region: "auto", Bucket: "b", AccessKey: "k", SecretKey: "s"})
+	_, err := sink.Upload(context.Background(), dir)
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Upload error = %v, want 403 AccessDenied", err)
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	if got := escapePath("/b/a dir/ü+x.md"); got != "/b/a%20dir/%C3%BC%2Bx.md" {
+		t.Errorf("escapePath = %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runs"
+)
+
+// FileStore keeps each transcript as <dir>/<id>/transcript.json, the same
+// layout as a run directory, so saved runs can be read as a store and vice
+// versa. Runs in flight are marked by a <dir>/<id>/pending.json file.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a FileStore rooted at dir. The directory is created on
+// the first Save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Save implements TranscriptStore. The file is replaced atomically, so a crash
+// mid-write leaves the previous version intact.
+func (s *FileStore) Save(_ context.Context, id string, t *debate.Transcript) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return s.write(id, "transcript.json", data)
+}
+
+// write replaces <dir>/<id>/<name> with data atomically.
+func (s *FileStore) write(id, name string, data []byte) error {
+	runDir := filepath.Join(s.dir, id)
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	tmp, err := os.CreateTemp(runDir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(runDir, name)); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+// Ping implements Pinger by checking that the directory can be written.
+func (s *FileStore) Ping(_ context.Context) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	f, err := os.CreateTemp(s.dir, ".ping-*")
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// Load implements TranscriptStore. Compressed transcripts are read too.
+func (s *FileStore) Load(_ context.Context, id string) (*debate.Transcript, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+	runDir := filepath.Join(s.dir, id)
+	if !hasTranscript(runDir) {
+		return nil, ErrNotFound
+	}
+	t, err := runs.LoadTranscript(runDir)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return t, nil
+}
+
+// List implements TranscriptStore. Directories without a transcript are
+// skipped.
+func (s *FileStore) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() && hasTranscript(filepath.Join(s.dir, entry.Name())) {
+			ids = append(ids, entry.Name())
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func hasTranscript(runDir string) bool {
+	for _, name := range []string{"transcript.json", "transcript.json.gz", "transcript.json.zst"} {
+		if _, err := os.Stat(filepath.Join(runDir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Begin implements RunTracker.
+func (s *FileStore) Begin(_ context.Context, p PendingRun) error {
+	if err := validateID(p.ID); err != nil {
+		return err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return s.write(p.ID, "pending.json", data)
+}
+
+// End implements RunTracker.
+func (s *FileStore) End(_ context.Context, id string) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.dir, id, "pending.json")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+// Pending implements RunTracker.
+func (s *FileStore) Pending(_ context.Context) ([]PendingRun, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	var runs []PendingRun
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name(), "pending.json"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+		var p PendingRun
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("store: %s: %w", entry.Name(), err)
+		}
+		runs = append(runs, p)
+	}
+	return runs, nil
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// MemoryStore keeps transcripts in process memory. Nothing survives a
+// restart; it suits tests and short-lived deployments.
+type MemoryStore struct {
+	mu          sync.Mutex
+	transcripts map[string][]byte
+	pending     map[string]PendingRun
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{transcripts: make(map[string][]byte), pending: make(map[string]PendingRun)}
+}
+
+// Save implements TranscriptStore. The transcript is copied, so later changes
+// to t are not visible to Load.
+func (s *MemoryStore) Save(_ context.Context, id string, t *debate.Transcript) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transcripts[id] = data
+	return nil
+}
+
+// Load implements TranscriptStore.
+func (s *MemoryStore) Load(_ context.Context, id string) (*debate.Transcript, error) {
+	s.mu.Lock()
+	data, ok := s.transcripts[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	var t debate.Transcript
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return &t, nil
+}
+
+// List implements TranscriptStore.
+func (s *MemoryStore) List(_ context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.transcripts))
+	for id := range s.transcripts {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// Begin implements RunTracker.
+func (s *MemoryStore) Begin(_ context.Context, p PendingRun) error {
+	if err := validateID(p.ID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[p.ID] = p
+	return nil
+}
+
+// End implements RunTracker.
+func (s *MemoryStore) End(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, id)
+	return nil
+}
+
+// Pending implements RunTracker.
+func (s *MemoryStore) Pending(_ context.Context) ([]PendingRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := make([]PendingRun, 0, len(s.pending))
+	for _, p := range s.pending {
+		runs = append(runs, p)
+	}
+	slices.SortFunc(runs, func(a, b PendingRun) int { return strings.Compare(a.ID, b.ID) })
+	return runs, nil
+}
@@ -0,0 +1,334 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// PostgresDriver is the database/sql driver name OpenPostgres uses. The
+// binary must register it, e.g. by building with -tags postgres.
+const PostgresDriver = "pgx"
+
+// migrations create and evolve the schema. Each entry runs once, in order,
+// and is recorded in schema_migrations by its 1-based index; append new
+// migrations, never edit applied ones.
+var migrations = []string{
+	`CREATE TABLE debates (
+		id         TEXT PRIMARY KEY,
+		topic      TEXT NOT NULL,
+		phase      INTEGER NOT NULL,
+		rounds     INTEGER NOT NULL,
+		transcript JSONB NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);
+	CREATE TABLE turns (
+		debate_id   TEXT NOT NULL REFERENCES debates(id) ON DELETE CASCADE,
+		turn_id     INTEGER NOT NULL,
+		round       INTEGER NOT NULL,
+		agent       TEXT NOT NULL,
+		role        TEXT NOT NULL,
+		model       TEXT NOT NULL,
+		content     TEXT NOT NULL,
+		in_reply_to INTEGER,
+		confidence  INTEGER,
+		PRIMARY KEY (debate_id, turn_id)
+	);
+	CREATE TABLE verdicts (
+		debate_id  TEXT PRIMARY KEY REFERENCES debates(id) ON DELETE CASCADE,
+		verdict    TEXT NOT NULL,
+		detected   BOOLEAN NOT NULL,
+		position   TEXT NOT NULL,
+		score      INTEGER NOT NULL,
+		dissenters JSONB NOT NULL,
+		decided_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX debates_updated_at ON debates (updated_at);
+	CREATE INDEX debates_topic ON debates (lower(topic))`,
+	`CREATE TABLE pending_runs (
+		id         TEXT PRIMARY KEY,
+		source     TEXT NOT NULL,
+		tenant     TEXT NOT NULL,
+		job        JSONB NOT NULL,
+		started_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+}
+
+// PostgresStore keeps debates, their turns and verdicts in Postgres, so
+// several server instances can share history and old debates can be pruned.
+type PostgresStore struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// OpenPostgres connects to dsn and applies any pending migrations.
+func OpenPostgres(ctx context.Context, dsn string) (*PostgresStore, error) {
+	if !slices.Contains(sql.Drivers(), PostgresDriver) {
+		return nil, fmt.Errorf("store: postgres driver %q is not compiled in (build with -tags postgres)", PostgresDriver)
+	}
+	db, err := sql.Open(PostgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	s := NewPostgresStore(db)
+	if err := s.Migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewPostgresStore wraps an open database. Call Migrate before first use.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db, now: time.Now}
+}
+
+// Close closes the database.
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+// Ping implements Pinger.
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+// Migrate applies the migrations not yet recorded in schema_migrations.
+func (s *PostgresStore) Migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("store: migrate: %w", err)
+	}
+	var current int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("store: migrate: %w", err)
+	}
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("store: migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// Save implements TranscriptStore. The full transcript is kept as JSON for
+// Load; turns not stored yet are also added to the turns table for querying.
+func (s *PostgresStore) Save(ctx context.Context, id string, t *debate.Transcript) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	err = s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO debates (id, topic, phase, rounds, transcript, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (id) DO UPDATE SET topic = $2, phase = $3, rounds = $4, transcript = $5, updated_at = $6`,
+			id, t.Topic, int(t.Phase), t.Rounds, data, s.now()); err != nil {
+			return err
+		}
+		var stored int
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(turn_id), 0) FROM turns WHERE debate_id = $1`, id).Scan(&stored); err != nil {
+			return err
+		}
+		for _, turn := range t.Turns {
+			if turn.ID <= stored {
+				continue
+			}
+			var inReplyTo, confidence sql.NullInt64
+			if turn.InReplyTo > 0 {
+				inReplyTo = sql.NullInt64{Int64: int64(turn.InReplyTo), Valid: true}
+			}
+			if turn.Confidence != nil {
+				confidence = sql.NullInt64{Int64: int64(*turn.Confidence), Valid: true}
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT INTO turns (debate_id, turn_id, round, agent, role, model, content, in_reply_to, confidence)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+				id, turn.ID, turn.Round, turn.Agent.Name, turn.Agent.Role, turn.Agent.Model, turn.Content, inReplyTo, confidence); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("store: saving %s: %w", id, err)
+	}
+	return nil
+}
+
+// Load implements TranscriptStore.
+func (s *PostgresStore) Load(ctx context.Context, id string) (*debate.Transcript, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT transcript FROM debates WHERE id = $1`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: loading %s: %w", id, err)
+	}
+	var t debate.Transcript
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("store: loading %s: %w", id, err)
+	}
+	return &t, nil
+}
+
+// List implements TranscriptStore.
+func (s *PostgresStore) List(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM debates ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return ids, nil
+}
+
+// RecordVerdict implements VerdictRecorder.
+func (s *PostgresStore) RecordVerdict(ctx context.Context, id string, result *debate.Result) error {
+	cons := result.Consensus
+	if cons == nil {
+		cons = &debate.ConsensusResult{}
+	}
+	dissenters, err := json.Marshal(cons.Dissenters)
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO verdicts (debate_id, verdict, detected, position, score, dissenters, decided_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (debate_id) DO UPDATE SET verdict = $2, detected = $3, position = $4, score = $5, dissenters = $6, decided_at = $7`,
+		id, string(result.Verdict()), cons.Detected, cons.Position, cons.Score, dissenters, s.now()); err != nil {
+		return fmt.Errorf("store: recording verdict for %s: %w", id, err)
+	}
+	return nil
+}
+
+// History implements HistoryQuerier.
+func (s *PostgresStore) History(ctx context.Context, f HistoryFilter) ([]DebateRecord, error) {
+	query := `SELECT d.id, d.topic, d.rounds, COALESCE(v.verdict, ''), d.updated_at
+		FROM debates d LEFT JOIN verdicts v ON v.debate_id = d.id
+		WHERE ($1 = '' OR lower(d.topic) LIKE '%' || lower($1) || '%') AND d.updated_at >= $2
+		ORDER BY d.updated_at DESC`
+	args := []any{f.Topic, f.Since}
+	if f.Limit > 0 {
+		query += ` LIMIT $3`
+		args = append(args, f.Limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: history: %w", err)
+	}
+	defer rows.Close()
+	var records []DebateRecord
+	for rows.Next() {
+		var r DebateRecord
+		var verdict string
+		if err := rows.Scan(&r.ID, &r.Topic, &r.Rounds, &verdict, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("store: history: %w", err)
+		}
+		r.Verdict = debate.Verdict(verdict)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: history: %w", err)
+	}
+	return records, nil
+}
+
+// Prune implements Pruner. Turns and verdicts are removed with their debate.
+func (s *PostgresStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM debates WHERE updated_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("store: prune: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("store: prune: %w", err)
+	}
+	return int(n), nil
+}
+
+// Begin implements RunTracker.
+func (s *PostgresStore) Begin(ctx context.Context, p PendingRun) error {
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO pending_runs (id, source, tenant, job, started_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET source = $2, tenant = $3, job = $4, started_at = $5`,
+		p.ID, p.Source, p.Tenant, []byte(p.Job), s.now()); err != nil {
+		return fmt.Errorf("store: beginning %s: %w", p.ID, err)
+	}
+	return nil
+}
+
+// End implements RunTracker.
+func (s *PostgresStore) End(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM pending_runs WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("store: ending %s: %w", id, err)
+	}
+	return nil
+}
+
+// Pending implements RunTracker.
+func (s *PostgresStore) Pending(ctx context.Context) ([]PendingRun, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, source, tenant, job FROM pending_runs ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("store: pending runs: %w", err)
+	}
+	defer rows.Close()
+	var runs []PendingRun
+	for rows.Next() {
+		var p PendingRun
+		var job []byte
+		if err := rows.Scan(&p.ID, &p.Source, &p.Tenant, &job); err != nil {
+			return nil, fmt.Errorf("store: pending runs: %w", err)
+		}
+		p.Job = job
+		runs = append(runs, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: pending runs: %w", err)
+	}
+	return runs, nil
+}
+
+func (s *PostgresStore) inTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
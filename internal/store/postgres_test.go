@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// scriptDB is a database/sql driver that records statements and answers
+// queries from a handler, enough to check the SQL PostgresStore issues.
+type scriptDB struct {
+	mu    sync.Mutex
+	execs []string
+	query func(q string, args []driver.Value) [][]driver.Value
+}
+
+var (
+	registerOnce sync.Once
+	scriptDBs    sync.Map
+)
+
+func openScriptDB(t *testing.T, query func(string, []driver.Value) [][]driver.Value) (*sql.DB, *scriptDB) {
+	registerOnce.Do(func() { sql.Register("scriptdb", scriptDriver{}) })
+	s := &scriptDB{query: query}
+	scriptDBs.Store(t.Name(), s)
+	db, err := sql.Open("scriptdb", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, s
+}
+
+func (s *scriptDB) statements() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.execs...)
+}
+
+type scriptDriver struct{}
+
+func (scriptDriver) Open(name string) (driver.Conn, error) {
+	s, _ := scriptDBs.Load(name)
+	return scriptConn{s.(*scriptDB)}, nil
+}
+
+type scriptConn struct{ db *scriptDB }
+
+func (c scriptConn) Prepare(query string) (driver.Stmt, error) { return scriptStmt{c.db, query}, nil }
+func (c scriptConn) Close() error                              { return nil }
+func (c scriptConn) Begin() (driver.Tx, error)                 { return scriptTx{}, nil }
+
+type scriptTx struct{}
+
+func (scriptTx) Commit() error   { return nil }
+func (scriptTx) Rollback() error { return nil }
+
+type scriptStmt struct {
+	db    *scriptDB
+	query string
+}
+
+func (s scriptStmt) Close() error  { return nil }
+func (s scriptStmt) NumInput() int { return -1 }
+
+func (s scriptStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	s.db.execs = append(s.db.execs, strings.Join(strings.Fields(s.query), " "))
+	s.db.mu.Unlock()
+	return driver.RowsAffected(1), nil
+}
+
+func (s scriptStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &scriptRows{rows: s.db.query(strings.Join(strings.Fields(s.query), " "), args)}, nil
+}
+
+type scriptRows struct{ rows [][]driver.Value }
+
+func (r *scriptRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return []string{"c"}
+	}
+	return make([]string, len(r.rows[0]))
+}
+func (r *scriptRows) Close() error { return nil }
+func (r *scriptRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestPostgresMigrateAppliesPendingOnly(t *testing.T) {
+	db, script := openScriptDB(t, func(q string, _ []driver.Value) [][]driver.Value {
+		if strings.Contains(q, "FROM schema_migrations") {
+			return [][]driver.Value{{int64(2)}}
+		}
+		return nil
+	})
+	if err := NewPostgresStore(db).Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	execs := script.statements()
+	if len(execs) != 3 || !strings.Contains(execs[1], "CREATE TABLE pending_runs") || !strings.Contains(execs[2], "INSERT INTO schema_migrations") {
+		t.Errorf("expected only migration 3 to run, got %q", execs)
+	}
+}
+
+func TestPostgresSaveInsertsNewTurns(t *testing.T) {
+	db, script := openScriptDB(t, func(q string, _ []driver.Value) [][]driver.Value {
+		if strings.Contains(q, "MAX(turn_id)") {
+			return [][]driver.Value{{int64(2)}}
+		}
+		return nil
+	})
+	conf := 70
+	tr := &debate.Transcript{Topic: "t", Rounds: 2, Turns: []debate.Turn{
+		{ID: 1, Round: 1}, {ID: 2, Round: 1}, {ID: 3, Round: 2, Confidence: &conf},
+	}}
+	if err := NewPostgresStore(db).Save(context.Background(), "run-1", tr); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	execs := script.statements()
+	if len(execs) != 2 || !strings.HasPrefix(execs[0], "INSERT INTO debates") || !strings.HasPrefix(execs[1], "INSERT INTO turns") {
+		t.Errorf("expected a debate upsert and one turn insert, got %q", execs)
+	}
+}
+
+func TestPostgresLoad(t *testing.T) {
+	data, _ := json.Marshal(debate.Transcript{Topic: "Stored topic", Rounds: 4})
+	db, _ := openScriptDB(t, func(_ string, args []driver.Value) [][]driver.Value {
+		if args[0] == "run-1" {
+			return [][]driver.Value{{data}}
+		}
+		return nil
+	})
+	s := NewPostgresStore(db)
+	got, err := s.Load(context.Background(), "run-1")
+	if err != nil || got.Topic != "Stored topic" || got.Rounds != 4 {
+		t.Errorf("Load() = %+v, %v", got, err)
+	}
+	if _, err := s.Load(context.Background(), "run-2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load() of missing debate error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOpenPostgresWithoutDriver(t *testing.T) {
+	_, err := (Config{Driver: "postgres", DSN: "postgres://localhost/tenthman"}).Open(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "-tags postgres") {
+		t.Errorf("expected a missing driver error, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// saveTimeout bounds each save made by an EventSink.
+const saveTimeout = 30 * time.Second
+
+// EventSink rebuilds a debate's transcript from its engine events and saves
+// it to a store after every round, for use as an output.Sink.
+type EventSink struct {
+	store      TranscriptStore
+	id         string
+	transcript debate.Transcript
+	err        error
+}
+
+// NewEventSink creates an EventSink saving under id. The transcript starts
+// as a copy of seed, e.g. a run being resumed or continued, or holds just
+// the topic.
+func NewEventSink(s TranscriptStore, id string, seed *debate.Transcript) *EventSink {
+	sink := &EventSink{store: s, id: id}
+	if seed != nil {
+		sink.transcript = *seed
+		sink.transcript.Turns = append([]debate.Turn(nil), seed.Turns...)
+		sink.transcript.Evidence = append([]debate.Evidence(nil), seed.Evidence...)
+	}
+	return sink
+}
+
+// Handle records ev and saves the transcript once a round ends. After the
+// first failed save it stops saving.
+func (s *EventSink) Handle(ev debate.Event) {
+	t := &s.transcript
+	switch ev := ev.(type) {
+	case debate.TurnCompleted:
+		t.Turns = append(t.Turns, ev.Turn)
+	case debate.PhaseChanged:
+		t.Phase = ev.Phase
+	case debate.TenthManActivated:
+		t.ConsensusPosition = ev.Position
+	case debate.EvidenceGathered:
+		t.Evidence = append(t.Evidence, ev.Evidence)
+	case debate.ModelSwapped:
+		t.ModelSwaps = append(t.ModelSwaps, ev.Swap)
+	case debate.AgentRemoved:
+		t.Removals = append(t.Removals, ev.Removal)
+	case debate.AgentJoined:
+		t.Joins = append(t.Joins, ev.Join)
+	case debate.InjectionSuspected:
+		t.InjectionWarnings = append(t.InjectionWarnings, ev.Warning)
+	case debate.RoundEnded:
+		t.Rounds = ev.Round
+		if s.err != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+		defer cancel()
+		if err := s.store.Save(ctx, s.id, t); err != nil {
+			s.err = fmt.Errorf("store: sink: %w", err)
+		}
+	}
+}
+
+// Close reports the first failed save.
+func (s *EventSink) Close() error {
+	return s.err
+}
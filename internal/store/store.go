@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+// ErrNotFound is returned by Load for an unknown transcript ID.
+var ErrNotFound = errors.New("store: transcript not found")
+
+// TranscriptStore persists debate transcripts by ID. Implementations must be
+// safe for concurrent use.
+type TranscriptStore interface {
+	// Save stores a snapshot of t under id, replacing any previous version.
+	Save(ctx context.Context, id string, t *debate.Transcript) error
+	// Load returns the transcript saved under id, or ErrNotFound.
+	Load(ctx context.Context, id string) (*debate.Transcript, error)
+	// List returns every stored ID in sorted order.
+	List(ctx context.Context) ([]string, error)
+}
+
+// VerdictRecorder is implemented by stores that keep the outcome of finished
+// debates.
+type VerdictRecorder interface {
+	RecordVerdict(ctx context.Context, id string, result *debate.Result) error
+}
+
+// HistoryFilter narrows a history query. Zero fields match everything.
+type HistoryFilter struct {
+	Topic string    // case-insensitive substring of the topic
+	Since time.Time // last updated at or after
+	Limit int
+}
+
+// DebateRecord summarizes a stored debate.
+type DebateRecord struct {
+	ID        string         `json:"id"`
+	Topic     string         `json:"topic"`
+	Rounds    int            `json:"rounds"`
+	Verdict   debate.Verdict `json:"verdict,omitempty"` // empty while the debate is unfinished
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// HistoryStats counts past debates by verdict.
+type HistoryStats struct {
+	Debates    int                    `json:"debates"`
+	Unfinished int                    `json:"unfinished"`
+	Verdicts   map[debate.Verdict]int `json:"verdicts"`
+	// SurvivalRate is the share of consensuses challenged by the Tenth Man
+	// that held, upheld or revised; 0 if none was challenged.
+	SurvivalRate float64 `json:"survival_rate"`
+}
+
+// Tally summarizes records by verdict.
+func Tally(records []DebateRecord) HistoryStats {
+	stats := HistoryStats{Debates: len(records), Verdicts: make(map[debate.Verdict]int)}
+	for _, r := range records {
+		if r.Verdict == "" {
+			stats.Unfinished++
+			continue
+		}
+		stats.Verdicts[r.Verdict]++
+	}
+	survived := stats.Verdicts[debate.VerdictUpheld] + stats.Verdicts[debate.VerdictRevised]
+	if challenged := survived + stats.Verdicts[debate.VerdictOverturned]; challenged > 0 {
+		stats.SurvivalRate = float64(survived) / float64(challenged)
+	}
+	return stats
+}
+
+// HistoryQuerier is implemented by stores that can search past debates,
+// most recently updated first.
+type HistoryQuerier interface {
+	History(ctx context.Context, f HistoryFilter) ([]DebateRecord, error)
+}
+
+// Pruner is implemented by stores that can delete debates last updated
+// before a cutoff. It returns how many were deleted.
+type Pruner interface {
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// Pinger is implemented by stores that depend on something that can become
+// unavailable, such as a database or a directory.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PendingRun is a run that was started but has not finished.
+type PendingRun struct {
+	ID     string          `json:"id"`
+	Source string          `json:"source"`
+	Tenant string          `json:"tenant,omitempty"`
+	Job    json.RawMessage `json:"job"` // the job as the server encoded it
+}
+
+// RunTracker is implemented by stores that remember which runs are in
+// flight, so a restarted server can find the ones it interrupted.
+type RunTracker interface {
+	// Begin records that run p.ID has started.
+	Begin(ctx context.Context, p PendingRun) error
+	// End records that run id has finished, successfully or not.
+	End(ctx context.Context, id string) error
+	// Pending returns the runs begun but not ended, ordered by ID.
+	Pending(ctx context.Context) ([]PendingRun, error)
+}
+
+// Config selects the transcript store for a deployment.
+type Config struct {
+	Driver    string        `yaml:"driver"`    // "file", "memory" or "postgres"
+	Path      string        `yaml:"path"`      // directory for the file driver
+	DSN       string        `yaml:"dsn"`       // connection string for the postgres driver; ${VAR} is expanded
+	Retention time.Duration `yaml:"retention"` // delete debates idle this long, for stores that support it; 0 keeps everything
+}
+
+// Open returns the store c describes, or nil if no driver is set. Postgres
+// migrations are applied before it returns.
+func (c Config) Open(ctx context.Context) (TranscriptStore, error) {
+	switch c.Driver {
+	case "":
+		return nil, nil
+	case "postgres":
+		if c.DSN == "" {
+			return nil, fmt.Errorf("store: postgres driver needs a dsn")
+		}
+		return OpenPostgres(ctx, os.ExpandEnv(c.DSN))
+	case "memory":
+		return NewMemoryStore(), nil
+	case "file":
+		if c.Path == "" {
+			return nil, fmt.Errorf("store: file driver needs a path")
+		}
+		return NewFileStore(c.Path), nil
+	default:
+		return nil, fmt.Errorf("store: unknown driver %q (want file, memory or postgres)", c.Driver)
+	}
+}
+
+// Checkpointer returns a debate.Checkpointer saving the engine's transcript
+// to s under id after every round.
+func Checkpointer(s TranscriptStore, id string) debate.Checkpointer {
+	return checkpointer{store: s, id: id}
+}
+
+type checkpointer struct {
+	store TranscriptStore
+	id    string
+}
+
+func (c checkpointer) Checkpoint(ctx context.Context, t *debate.Transcript) error {
+	return c.store.Save(ctx, c.id, t)
+}
+
+// validateID rejects IDs that are empty or could escape a file store's
+// directory.
+func validateID(id string) error {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("store: invalid transcript ID %q", id)
+	}
+	return nil
+}
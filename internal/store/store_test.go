@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+)
+
+func TestStores(t *testing.T) {
+	stores := map[string]TranscriptStore{
+		"memory": NewMemoryStore(),
+		"file":   NewFileStore(t.TempDir()),
+	}
+	ctx := context.Background()
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.Load(ctx, "run-1"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Load() of missing transcript error = %v, want ErrNotFound", err)
+			}
+			tr := &debate.Transcript{Topic: "Store me", Rounds: 1, Turns: []debate.Turn{{ID: 1, Round: 1, Content: "first"}}}
+			if err := s.Save(ctx, "run-2", tr); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			tr.Rounds = 2
+			tr.Turns = append(tr.Turns, debate.Turn{ID: 2, Round: 2, Content: "second"})
+			if err := s.Save(ctx, "run-1", tr); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			tr.Topic = "changed after saving"
+
+			got, err := s.Load(ctx, "run-1")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got.Topic != "Store me" || got.Rounds != 2 || len(got.Turns) != 2 {
+				t.Errorf("Load() = %+v", got)
+			}
+			ids, err := s.List(ctx)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(ids) != 2 || ids[0] != "run-1" || ids[1] != "run-2" {
+				t.Errorf("List() = %v", ids)
+			}
+			for _, id := range []string{"", "..", "a/b"} {
+				if err := s.Save(ctx, id, tr); err == nil {
+					t.Errorf("Save(%q) should be rejected", id)
+				}
+			}
+		})
+	}
+}
+
+func TestRunTrackers(t *testing.T) {
+	trackers := map[string]RunTracker{
+		"memory": NewMemoryStore(),
+		"file":   NewFileStore(t.TempDir()),
+	}
+	ctx := context.Background()
+	for name, tr := range trackers {
+		t.Run(name, func(t *testing.T) {
+			for _, id := range []string{"run-2", "run-1"} {
+				if err := tr.Begin(ctx, PendingRun{ID: id, Source: "api", Job: json.RawMessage(`{"topic":"t"}`)}); err != nil {
+					t.Fatalf("Begin() error = %v", err)
+				}
+			}
+			if err := tr.End(ctx, "run-2"); err != nil {
+				t.Fatalf("End() error = %v", err)
+			}
+			if err := tr.End(ctx, "run-3"); err != nil {
+				t.Errorf("End() of an unknown run error = %v", err)
+			}
+			pending, err := tr.Pending(ctx)
+			if err != nil {
+				t.Fatalf("Pending() error = %v", err)
+			}
+			if len(pending) != 1 || pending[0].ID != "run-1" || string(pending[0].Job) != `{"topic":"t"}` {
+				t.Errorf("Pending() = %+v", pending)
+			}
+			if _, err := tr.(TranscriptStore).Load(ctx, "run-1"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Load() of a run without checkpoints error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestFileStorePing(t *testing.T) {
+	dir := t.TempDir()
+	if err := NewFileStore(filepath.Join(dir, "transcripts")).Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, nil, 0o644)
+	if err := NewFileStore(file).Ping(context.Background()); err == nil {
+		t.Error("Ping() should fail when the path is not a directory")
+	}
+}
+
+func TestCheckpointer(t *testing.T) {
+	s := NewMemoryStore()
+	if err := Checkpointer(s, "run-7").Checkpoint(context.Background(), &debate.Transcript{Topic: "t"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Load(context.Background(), "run-7"); err != nil || got.Topic != "t" {
+		t.Errorf("Load() = %+v, %v", got, err)
+	}
+}
+
+func TestEventSink(t *testing.T) {
+	s := NewMemoryStore()
+	seed := &debate.Transcript{Topic: "t", Rounds: 1, Turns: []debate.Turn{{ID: 1, Round: 1, Content: "old"}}}
+	sink := NewEventSink(s, "run-8", seed)
+	sink.Handle(debate.RoundStarted{RoundSummary: debate.RoundSummary{Round: 2}})
+	sink.Handle(debate.TurnCompleted{Turn: debate.Turn{ID: 2, Round: 2, Content: "new"}})
+	if _, err := s.Load(context.Background(), "run-8"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("nothing should be saved before the round ends, got %v", err)
+	}
+	sink.Handle(debate.PhaseChanged{Phase: debate.TenthManPhase})
+	sink.Handle(debate.TenthManActivated{Position: "p"})
+	sink.Handle(debate.RoundEnded{RoundSummary: debate.RoundSummary{Round: 2}})
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Load(context.Background(), "run-8")
+	if err != nil || got.Rounds != 2 || len(got.Turns) != 2 || got.Phase != debate.TenthManPhase || got.ConsensusPosition != "p" {
+		t.Errorf("Load() = %+v, %v", got, err)
+	}
+	if len(seed.Turns) != 1 {
+		t.Error("the seed transcript should not be modified")
+	}
+
+	bad := NewEventSink(NewFileStore(t.TempDir()), "../escape", nil)
+	bad.Handle(debate.RoundEnded{RoundSummary: debate.RoundSummary{Round: 1}})
+	if err := bad.Close(); err == nil {
+		t.Error("expected the failed save to be reported")
+	}
+}
+
+func TestConfigOpen(t *testing.T) {
+	if s, err := (Config{}).Open(context.Background()); s != nil || err != nil {
+		t.Errorf("empty config should open no store, got %v, %v", s, err)
+	}
+	if _, err := (Config{Driver: "file"}).Open(context.Background()); err == nil {
+		t.Error("file driver without a path should fail")
+	}
+	if _, err := (Config{Driver: "sqlite"}).Open(context.Background()); err == nil {
+		t.Error("unknown driver should fail")
+	}
+	if s, err := (Config{Driver: "file", Path: t.TempDir()}).Open(context.Background()); err != nil || s == nil {
+		t.Errorf("file driver: %v, %v", s, err)
+	}
+}
+
+func TestTally(t *testing.T) {
+	stats := Tally([]DebateRecord{
+		{ID: "a", Verdict: debate.VerdictUpheld},
+		{ID: "b", Verdict: debate.VerdictRevised},
+		{ID: "c", Verdict: debate.VerdictOverturned},
+		{ID: "d", Verdict: debate.VerdictOverturned},
+		{ID: "e", Verdict: debate.VerdictNoConsensus},
+		{ID: "f"},
+	})
+	if stats.Debates != 6 || stats.Unfinished != 1 || stats.Verdicts[debate.VerdictOverturned] != 2 || stats.Verdicts[debate.VerdictNoConsensus] != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.SurvivalRate != 0.5 {
+		t.Errorf("expected 2 of 4 challenged consensuses to survive, got %v", stats.SurvivalRate)
+	}
+	if empty := Tally(nil); empty.SurvivalRate != 0 || empty.Verdicts == nil {
+		t.Errorf("unexpected stats for no debates %+v", empty)
+	}
+}
@@ -0,0 +1,148 @@
+package templates
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed builtin/*.yaml
+var builtin embed.FS
+
+//go:embed presets/*.yaml
+var presets embed.FS
+
+// Presets lists the debate sizes LoadPreset accepts, smallest first.
+var Presets = []string{"quick", "standard", "deep"}
+
+// Template preconfigures a debate for a common decision type.
+type Template struct {
+	Name        string     `yaml:"-"`
+	Description string     `yaml:"description"`
+	Job         runner.Job `yaml:",inline"`
+	Source      string     `yaml:"-"` // "builtin" or the file path
+}
+
+// Apply returns job with unset settings taken from the template.
+func (t *Template) Apply(job runner.Job) runner.Job {
+	defaults := t.Job
+	defaults.Topic, defaults.Name = "", ""
+	// The template's experts never replace explicit personas or a roster,
+	// nor take more seats than the agents explicitly asked for.
+	if len(job.Personas) > 0 || len(job.Roster) > 0 {
+		defaults.Experts = nil
+	} else if job.Agents > 0 && len(defaults.Experts) > job.Agents {
+		defaults.Experts = defaults.Experts[:job.Agents]
+	}
+	return job.WithDefaults(defaults)
+}
+
+// UserDirs returns the default user template directories.
+func UserDirs() []string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil
+	}
+	return []string{filepath.Join(dir, "tenthman", "templates")}
+}
+
+// Load resolves a template by file path or by name. Named templates are
+// looked up in dirs first, in order, then among the built-ins, so user
+// templates can override built-in ones.
+func Load(nameOrPath string, dirs []string) (*Template, error) {
+	if strings.HasSuffix(nameOrPath, ".yaml") || strings.HasSuffix(nameOrPath, ".yml") {
+		return loadFile(nameOrPath)
+	}
+	for _, dir := range dirs {
+		for _, ext := range []string{".yaml", ".yml"} {
+			path := filepath.Join(dir, nameOrPath+ext)
+			if _, err := os.Stat(path); err == nil {
+				return loadFile(path)
+			}
+		}
+	}
+	data, err := builtin.ReadFile("builtin/" + nameOrPath + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("templates: unknown template %q", nameOrPath)
+	}
+	return parse(nameOrPath, "builtin", data)
+}
+
+// LoadPreset returns the bundled preset called name, which sets the number
+// of agents, the round range and the length of turns together.
+func LoadPreset(name string) (*Template, error) {
+	data, err := presets.ReadFile("presets/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("templates: unknown preset %q (want %s)", name, strings.Join(Presets, ", "))
+	}
+	return parse(name, "preset", data)
+}
+
+// List returns every available template, with user templates shadowing
+// built-ins of the same name, sorted by name.
+func List(dirs []string) ([]*Template, error) {
+	byName := make(map[string]*Template)
+	entries, err := fs.ReadDir(builtin, "builtin")
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		data, err := builtin.ReadFile("builtin/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("templates: %w", err)
+		}
+		t, err := parse(name, "builtin", data)
+		if err != nil {
+			return nil, err
+		}
+		byName[name] = t
+	}
+	// Walk dirs in reverse so earlier directories win.
+	for i := len(dirs) - 1; i >= 0; i-- {
+		matches, _ := filepath.Glob(filepath.Join(dirs[i], "*.y*ml"))
+		for _, path := range matches {
+			t, err := loadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			byName[t.Name] = t
+		}
+	}
+
+	out := make([]*Template, 0, len(byName))
+	for _, t := range byName {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func loadFile(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return parse(name, path, data)
+}
+
+func parse(name, source string, data []byte) (*Template, error) {
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("templates: parsing %s: %w", source, err)
+	}
+	t.Name = name
+	t.Source = source
+	if t.Job.Agents != 0 && t.Job.Agents < len(t.Job.Personas) {
+		return nil, fmt.Errorf("templates: %s: %d personas but only %d agents", name, len(t.Job.Personas), t.Job.Agents)
+	}
+	return &t, nil
+}
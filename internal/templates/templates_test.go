@@ -0,0 +1,130 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+)
+
+func TestBuiltinsLoad(t *testing.T) {
+	for _, name := range []string{"incident-postmortem", "investment-thesis", "hiring-decision", "security-review"} {
+		tmpl, err := Load(name, nil)
+		if err != nil {
+			t.Errorf("Load(%q) error = %v", name, err)
+			continue
+		}
+		if tmpl.Description == "" || tmpl.Job.Instructions == "" || len(tmpl.Job.Personas) == 0 {
+			t.Errorf("%s: incomplete template %+v", name, tmpl)
+		}
+		job := tmpl.Apply(runner.Job{Topic: "t"}).WithDefaults(runner.Job{Agents: 9, MinRounds: 5, MaxRounds: 15})
+		if err := job.Validate(); err != nil {
+			t.Errorf("%s: applied job invalid: %v", name, err)
+		}
+	}
+}
+
+func TestLoadUnknown(t *testing.T) {
+	if _, err := Load("does-not-exist", nil); err == nil {
+		t.Fatal("expected error for unknown template")
+	}
+}
+
+func TestApplyKeepsExplicitSettings(t *testing.T) {
+	tmpl, _ := Load("security-review", nil)
+	job := tmpl.Apply(runner.Job{Topic: "New login flow", Agents: 3})
+	if job.Agents != 3 {
+		t.Errorf("explicit agents overridden: %d", job.Agents)
+	}
+	if job.Topic != "New login flow" {
+		t.Errorf("topic overridden: %q", job.Topic)
+	}
+	if job.TenthManRounds != 4 || job.MinRounds != 4 {
+		t.Errorf("template settings not applied: %+v", job)
+	}
+}
+
+func TestUserDirOverridesBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "security-review.yaml"), []byte("description: custom\nagents: 3\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "vendor-selection.yml"), []byte("description: pick a vendor\n"), 0o644)
+
+	tmpl, err := Load("security-review", []string{dir})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if tmpl.Description != "custom" || tmpl.Source == "builtin" {
+		t.Errorf("expected user template, got %+v", tmpl)
+	}
+
+	list, err := List([]string{dir})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 5 {
+		t.Fatalf("expected 4 built-ins + 1 user template, got %d", len(list))
+	}
+	for _, tmpl := range list {
+		if tmpl.Name == "security-review" && tmpl.Description != "custom" {
+			t.Error("List should return the user override")
+		}
+	}
+}
+
+func TestLoadByPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "board-review.yaml")
+	os.WriteFile(path, []byte("description: board\npersonas:\n  - name: CFO\n    description: the CFO\n"), 0o644)
+	tmpl, err := Load(path, nil)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if tmpl.Name != "board-review" || tmpl.Job.Personas[0].Name != "CFO" {
+		t.Errorf("unexpected template %+v", tmpl)
+	}
+}
+
+func TestParseRejectsMorePersonasThanAgents(t *testing.T) {
+	_, err := parse("x", "test", []byte("agents: 1\npersonas:\n  - name: A\n  - name: B\n"))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestPresetsLoad(t *testing.T) {
+	for _, name := range Presets {
+		preset, err := LoadPreset(name)
+		if err != nil {
+			t.Fatalf("LoadPreset(%q) error = %v", name, err)
+		}
+		job := preset.Apply(runner.Job{Topic: "t"}).WithDefaults(runner.Job{Agents: 9, MinRounds: 5, MaxRounds: 15})
+		if err := job.Validate(); err != nil {
+			t.Errorf("%s: applied job invalid: %v", name, err)
+		}
+	}
+	if _, err := LoadPreset("huge"); err == nil {
+		t.Error("expected error for unknown preset")
+	}
+}
+
+func TestPresetSettings(t *testing.T) {
+	quick, _ := LoadPreset("quick")
+	job := quick.Apply(runner.Job{MaxRounds: 6})
+	if job.Agents != 3 || job.MinRounds != 2 || job.MaxRounds != 6 || job.MaxWords == 0 {
+		t.Errorf("quick preset = %+v", job)
+	}
+
+	deep, _ := LoadPreset("deep")
+	job = deep.Apply(runner.Job{})
+	if job.Agents != 9 || job.MinRounds != 5 || job.MaxRounds != 15 || len(job.Experts) == 0 {
+		t.Errorf("deep preset = %+v", job)
+	}
+	job = deep.Apply(runner.Job{Agents: 3})
+	if len(job.Experts) != 3 {
+		t.Errorf("experts not trimmed to explicit agents: %v", job.Experts)
+	}
+	job = deep.Apply(runner.Job{Personas: []runner.Persona{{Name: "CFO"}}})
+	if len(job.Experts) != 0 {
+		t.Errorf("experts replaced explicit personas: %v", job.Experts)
+	}
+}
@@ -0,0 +1,95 @@
+// Package tokens estimates how many tokens text takes up for a model without
+// running the model's tokenizer. Tokenizers differ in how much text a token
+// holds, so estimates are made per model family. They err high: they keep
+// prompts within context windows and transcripts within budgets, where an
+// underestimate costs a rejected or silently cut request.
+package tokens
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+// Fixed costs of a chat prompt beyond the text of its messages.
+const (
+	MessageTokens = 4    // chat markup around each message
+	ImageTokens   = 1000 // an attached image, at a typical resolution
+)
+
+// Family is a group of models whose tokenizers hold about as much text per
+// token.
+type Family struct {
+	Name string
+	// CharsPerToken is the characters of English text a token holds, a
+	// little below the family's average.
+	CharsPerToken float64
+}
+
+// Default is the family of models not recognised, with few characters to a
+// token so that it overestimates for any of them.
+var Default = Family{Name: "default", CharsPerToken: 3}
+
+// families maps OpenRouter model ID prefixes to their family.
+var families = []struct {
+	prefix string
+	family Family
+}{
+	{"openai/", Family{Name: "openai", CharsPerToken: 3.8}},
+	{"google/", Family{Name: "google", CharsPerToken: 3.8}},
+	{"meta-llama/", Family{Name: "llama", CharsPerToken: 3.6}},
+	{"qwen/", Family{Name: "qwen", CharsPerToken: 3.5}},
+	{"deepseek/", Family{Name: "deepseek", CharsPerToken: 3.5}},
+	{"anthropic/", Family{Name: "anthropic", CharsPerToken: 3.3}},
+	{"mistralai/", Family{Name: "mistral", CharsPerToken: 3.2}},
+	{"nvidia/", Family{Name: "nvidia", CharsPerToken: 3.2}},
+}
+
+// For returns the family of model, an OpenRouter model ID, or Default.
+func For(model string) Family {
+	for _, f := range families {
+		if strings.HasPrefix(model, f.prefix) {
+			return f.family
+		}
+	}
+	return Default
+}
+
+// Count returns an estimate of the tokens s takes up for model.
+func Count(model, s string) int {
+	return For(model).Count(s)
+}
+
+// Count returns an estimate of the tokens s takes up. Characters of scripts
+// written without spaces, such as Chinese and Japanese, count a token each,
+// as tokenizers seldom merge them; other text counts CharsPerToken
+// characters a token.
+func (f Family) Count(s string) int {
+	chars, ideographs := 0, 0
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai) {
+			ideographs++
+		} else {
+			chars++
+		}
+	}
+	return int(math.Ceil(float64(chars)/f.CharsPerToken)) + ideographs
+}
+
+// Messages returns an estimate of the tokens msgs take up in a prompt, chat
+// markup and attached images included.
+func (f Family) Messages(msgs []openrouter.Message) int {
+	n := 0
+	for _, m := range msgs {
+		n += MessageTokens + f.Count(m.Content) + len(m.Images)*ImageTokens
+	}
+	return n
+}
+
+// Chars returns how many characters of English text n tokens hold at most,
+// for cutting text down to a number of tokens.
+func (f Family) Chars(n int) int {
+	return int(float64(n) * f.CharsPerToken)
+}
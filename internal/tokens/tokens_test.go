@@ -0,0 +1,56 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+)
+
+func TestFor(t *testing.T) {
+	tests := map[string]string{
+		"openai/gpt-oss-120b:free":        "openai",
+		"google/gemma-3-27b-it:free":      "google",
+		"qwen/qwen3-235b-a22b:free":       "qwen",
+		"nvidia/nemotron-nano-9b-v2:free": "nvidia",
+		"test-model":                      "default",
+	}
+	for model, want := range tests {
+		if got := For(model).Name; got != want {
+			t.Errorf("For(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 100)
+	if got := Count("openai/gpt-oss-120b:free", text); got < 1000 || got > 1300 {
+		t.Errorf("expected about 1,100-1,200 tokens for 4,500 characters of English, got %d", got)
+	}
+	if Count("test-model", text) <= Count("openai/gpt-oss-120b:free", text) {
+		t.Error("expected an unknown model to be estimated more generously than a known one")
+	}
+	if got := Default.Count("辩论的主题"); got != 5 {
+		t.Errorf("expected a token per ideograph, got %d", got)
+	}
+	if got := Default.Count(""); got != 0 {
+		t.Errorf("expected no tokens for no text, got %d", got)
+	}
+}
+
+func TestMessages(t *testing.T) {
+	msgs := []openrouter.Message{
+		{Role: "system", Content: "abcdef"},
+		{Role: "user", Content: "look", Images: []string{"https://example.com/a.png"}},
+	}
+	if got, want := Default.Messages(msgs), 2*MessageTokens+2+2+ImageTokens; got != want {
+		t.Errorf("Messages() = %d, want %d", got, want)
+	}
+}
+
+func TestChars(t *testing.T) {
+	f := For("openai/gpt-oss-120b:free")
+	if got := f.Count(strings.Repeat("a", f.Chars(100))); got != 100 {
+		t.Errorf("expected Chars(100) characters to count 100 tokens, got %d", got)
+	}
+}
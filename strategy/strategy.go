@@ -0,0 +1,161 @@
+// Package strategy is the public Go API for swapping how a debate detects
+// consensus and how its Tenth Man argues, without forking the project.
+//
+// A consensus judge implements ConsensusJudge: after every round it reads the
+// transcript and reports whether the debaters agree, on what, and how
+// strongly. A consensus scoring at least ConsensusThreshold triggers the
+// Tenth Man. A Tenth Man activator implements TenthManActivator: it builds the
+// dissenting agent and writes its system prompt for each of its turns.
+//
+// Register your implementations on a Set under a name, select them by name
+// in a Job, and run it:
+//
+//	set := strategy.NewSet()
+//	set.RegisterJudge("strict", func(llm strategy.LLMClient, model string) strategy.ConsensusJudge {
+//		return &strictJudge{}
+//	})
+//	job := strategy.Job{Topic: "...", Agents: 3, MinRounds: 2, MaxRounds: 5, Judge: "strict", Strategies: set}
+//	outcome, err := strategy.Run(ctx, strategy.NewOpenRouterClient(apiKey), "output", job)
+//
+// Work between the phases of a debate, such as injecting evidence,
+// snapshotting the transcript or notifying a webhook, goes in
+// Job.Middleware, which runs around every state of the engine:
+//
+//	job.Middleware = []strategy.Middleware{strategy.Around(nil, func(ctx context.Context, state, next strategy.State, m *strategy.Machine) error {
+//		if next == strategy.StateTenthMan {
+//			m.InjectEvent("Latest figures: ...")
+//		}
+//		return nil
+//	})}
+package strategy
+
+import (
+	"context"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/consensus"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/debate/tenthman"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/models"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/openrouter"
+	"github.com/lorenzotomasdiez/tenth-man-rule/internal/runner"
+)
+
+// ConsensusThreshold is the minimum agreement score (0-10) at which a
+// detected consensus triggers the Tenth Man.
+const ConsensusThreshold = debate.ConsensusThreshold
+
+// Names of the strategies a Job uses when it does not choose one.
+const (
+	DefaultJudge    = runner.DefaultJudge
+	DefaultTenthMan = runner.DefaultTenthMan
+)
+
+type (
+	// ConsensusJudge decides after each round whether the debaters agree.
+	ConsensusJudge = debate.ConsensusJudge
+	// TenthManActivator builds the Tenth Man and its system prompt.
+	TenthManActivator = debate.TenthManActivator
+	// DebaterInstructor may be implemented by a TenthManActivator to add
+	// instructions to the debaters' Phase 2 prompts.
+	DebaterInstructor = debate.DebaterInstructor
+	// JudgeFactory builds a ConsensusJudge for one run, given the client and
+	// model the built-in LLM judge would use.
+	JudgeFactory = runner.JudgeFactory
+	// TenthManFactory builds a TenthManActivator for one run.
+	TenthManFactory = runner.TenthManFactory
+	// Set holds the strategies a Job can choose from by name.
+	Set = runner.Strategies
+
+	Transcript      = debate.Transcript
+	Turn            = debate.Turn
+	Agent           = debate.Agent
+	Phase           = debate.Phase
+	ConsensusResult = debate.ConsensusResult
+
+	// LLMClient sends chat completions for the debaters and the judge.
+	LLMClient    = debate.LLMClient
+	Message      = openrouter.Message
+	Option       = openrouter.Option
+	ChatResponse = openrouter.ChatResponse
+	Choice       = openrouter.Choice
+
+	Job     = runner.Job
+	Outcome = runner.Outcome
+
+	// State is a step of the debate engine, such as StateTenthMan.
+	State = debate.State
+	// StateHandler implements a state.
+	StateHandler = debate.StateHandler
+	// StateFunc adapts a function to a StateHandler.
+	StateFunc = debate.StateFunc
+	// Machine is a debate in progress, as state handlers and middleware see it.
+	Machine = debate.Machine
+	// Middleware wraps every state of a debate; set it on Job.Middleware.
+	Middleware = debate.Middleware
+)
+
+// The states a debate moves through; see debate.State.
+const (
+	StateOpening        = debate.StateOpening
+	StateFreeDebate     = debate.StateFreeDebate
+	StateConsensusCheck = debate.StateConsensusCheck
+	StateTenthMan       = debate.StateTenthMan
+	StateVote           = debate.StateVote
+	StateSynthesis      = debate.StateSynthesis
+	StateDone           = debate.StateDone
+)
+
+// Around returns middleware that calls before just before each state runs
+// and after once it has run, with the state it moved to. Either may be nil,
+// and an error from either ends the run.
+func Around(before func(ctx context.Context, state State, m *Machine) error, after func(ctx context.Context, state, next State, m *Machine) error) Middleware {
+	return debate.Around(before, after)
+}
+
+// NewSet returns a Set holding the built-in strategies: judges "llm" and
+// "keyword-vote", and Tenth Man activators "contrarian", "rotating" and
+// "socratic".
+func NewSet() *Set {
+	return runner.NewStrategies()
+}
+
+// NewLLMJudge returns the built-in judge that asks model to evaluate the
+// transcript.
+func NewLLMJudge(llm LLMClient, model string) ConsensusJudge {
+	return consensus.NewJudge(llm, model)
+}
+
+// NewKeywordJudge returns a judge that needs no LLM: each debater's latest
+// turn votes by its agreement and disagreement words.
+func NewKeywordJudge() ConsensusJudge {
+	return consensus.NewKeywordJudge()
+}
+
+// NewContrarianActivator returns the built-in Tenth Man, who argues the
+// contrary of the consensus.
+func NewContrarianActivator() TenthManActivator {
+	return tenthman.NewActivator()
+}
+
+// NewRotatingActivator returns a devil's advocate who attacks the consensus
+// from a different angle every turn.
+func NewRotatingActivator() TenthManActivator {
+	return tenthman.NewRotatingActivator()
+}
+
+// NewSocraticActivator returns a Tenth Man who challenges the consensus
+// through numbered questions that the debaters must answer one by one.
+func NewSocraticActivator() TenthManActivator {
+	return tenthman.NewSocraticActivator()
+}
+
+// NewOpenRouterClient returns an LLMClient for the OpenRouter API.
+func NewOpenRouterClient(apiKey string) LLMClient {
+	return openrouter.NewClient(apiKey)
+}
+
+// Run executes job against llm on the built-in free models, writing its
+// artifacts into a new run directory under outputDir.
+func Run(ctx context.Context, llm LLMClient, outputDir string, job Job) (*Outcome, error) {
+	return runner.Run(ctx, llm, models.NewRegistry(models.DefaultFreeModels()), outputDir, job, runner.Hooks{})
+}
@@ -0,0 +1,72 @@
+package strategy_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lorenzotomasdiez/tenth-man-rule/strategy"
+)
+
+// agreeableLLM has every debater agree, so the keyword judge finds consensus.
+type agreeableLLM struct{}
+
+func (agreeableLLM) ChatCompletion(_ context.Context, _ string, msgs []strategy.Message, _ ...strategy.Option) (*strategy.ChatResponse, error) {
+	content := "I agree the plan is sound."
+	if strings.Contains(msgs[0].Content, "claims analyst") {
+		content = `{"claims": []}`
+	}
+	return &strategy.ChatResponse{Choices: []strategy.Choice{{Message: strategy.Message{Role: "assistant", Content: content}}}}, nil
+}
+
+// quietActivator is a custom Tenth Man defined outside the module.
+type quietActivator struct{}
+
+func (quietActivator) BuildAgent(_ string, id int, model string) strategy.Agent {
+	return strategy.Agent{ID: id, Name: "The Skeptic", Model: model, Role: "tenth-man"}
+}
+
+func (quietActivator) SystemPrompt(position string) string {
+	return "You are The Skeptic. Doubt this: " + position
+}
+
+func TestRunWithCustomStrategies(t *testing.T) {
+	set := strategy.NewSet()
+	if err := set.RegisterTenthMan("skeptic", func() strategy.TenthManActivator { return quietActivator{} }); err != nil {
+		t.Fatal(err)
+	}
+	job := strategy.Job{
+		Topic: "t", Agents: 3, MinRounds: 1, MaxRounds: 1, TenthManRounds: 1,
+		Judge: "keyword-vote", TenthMan: "skeptic", Strategies: set,
+	}
+	outcome, err := strategy.Run(context.Background(), agreeableLLM{}, t.TempDir(), job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !outcome.Consensus.Detected || outcome.Consensus.Position != "I agree the plan is sound." {
+		t.Errorf("expected the keyword judge to detect consensus, got %+v", outcome.Consensus)
+	}
+	last := outcome.Result.Transcript.Turns[len(outcome.Result.Transcript.Turns)-1]
+	if last.Agent.Name != "The Skeptic" {
+		t.Errorf("expected the custom Tenth Man to speak last, got %q", last.Agent.Name)
+	}
+}
+
+func TestRunWithMiddleware(t *testing.T) {
+	var snapshots []int
+	job := strategy.Job{
+		Topic: "t", Agents: 3, MinRounds: 1, MaxRounds: 1, TenthManRounds: 1, Judge: "keyword-vote",
+		Middleware: []strategy.Middleware{strategy.Around(nil, func(_ context.Context, state, next strategy.State, m *strategy.Machine) error {
+			if state == strategy.StateFreeDebate || state == strategy.StateTenthMan {
+				snapshots = append(snapshots, m.Transcript().Rounds)
+			}
+			return nil
+		})},
+	}
+	if _, err := strategy.Run(context.Background(), agreeableLLM{}, t.TempDir(), job); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0] != 1 || snapshots[1] != 2 {
+		t.Errorf("expected a snapshot after each phase's round, got %v", snapshots)
+	}
+}